@@ -1,7 +1,13 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	_ "github.com/prefeitura-rio/app-busca-search/docs"
 	"github.com/prefeitura-rio/app-busca-search/internal/api/routes"
@@ -30,11 +36,25 @@ func main() {
 	observability.InitTracer(cfg)
 	defer observability.ShutdownTracer()
 
-	r := routes.SetupRouter(cfg)
+	// Cancelado no SIGTERM/SIGINT, encerrando as rotinas em segundo plano
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	r := routes.SetupRouter(ctx, cfg)
+	server := &http.Server{
+		Addr:    ":" + cfg.ServerPort,
+		Handler: r,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
 
 	log.Printf("Servidor iniciado na porta %s", cfg.ServerPort)
-	err := r.Run(":" + cfg.ServerPort)
-	if err != nil {
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		log.Fatalf("Erro ao iniciar servidor: %v", err)
 	}
 }
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/typesense/typesense-go/v3/typesense"
+)
+
+var (
+	fix        = flag.Bool("fix", false, "Remove os documentos órfãos encontrados (versões órfãs são apenas reportadas)")
+	jsonOutput = flag.Bool("json", false, "Saída em formato JSON")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Uso: %s [opções]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Verifica a consistência entre prefrio_services_base, service_versions,\n")
+		fmt.Fprintf(os.Stderr, "tombamentos_overlay, hub_search, aliases e backups de migração.\n")
+		fmt.Fprintf(os.Stderr, "\nOpções:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cfg := config.LoadConfig()
+
+	// Cliente Typesense com timeout maior para exportação completa das collections
+	typesenseClient := typesense.NewClient(
+		typesense.WithServer(fmt.Sprintf("%s://%s:%s", cfg.TypesenseProtocol, cfg.TypesenseHost, cfg.TypesensePort)),
+		typesense.WithAPIKey(cfg.TypesenseAPIKey),
+		typesense.WithConnectionTimeout(10*time.Minute),
+	)
+
+	consistencyService := services.NewConsistencyService(typesenseClient)
+
+	if *fix {
+		migrationService := services.NewMigrationService(typesenseClient, nil)
+		locked, err := migrationService.IsMigrationLocked(context.Background())
+		if err == nil && locked {
+			fmt.Fprintln(os.Stderr, "❌ Existe uma migração em andamento, --fix não pode ser executado agora")
+			os.Exit(1)
+		}
+	}
+
+	report, err := consistencyService.Check(context.Background(), *fix)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Erro ao verificar consistência: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		printJSON(report)
+	} else {
+		printReport(report)
+	}
+
+	// Código de saída != 0 quando restam inconsistências (útil em pipelines)
+	if len(report.Issues) > report.TotalFixed {
+		os.Exit(2)
+	}
+}
+
+func printReport(report *models.ConsistencyReport) {
+	fmt.Println("🔎 Verificação de Consistência")
+	fmt.Println("------------------------------")
+	fmt.Printf("Executado em: %s (%dms)\n", time.Unix(report.CheckedAt, 0).Format("02/01/2006 15:04:05"), report.DurationMs)
+
+	if len(report.Issues) == 0 {
+		fmt.Println("\n✅ Nenhuma inconsistência encontrada.")
+		return
+	}
+
+	fmt.Println("\nResumo:")
+	types := make([]string, 0, len(report.Summary))
+	for issueType := range report.Summary {
+		types = append(types, string(issueType))
+	}
+	sort.Strings(types)
+	for _, issueType := range types {
+		fmt.Printf("   %s: %d\n", issueType, report.Summary[models.ConsistencyIssueType(issueType)])
+	}
+
+	fmt.Println("\nDetalhes:")
+	for _, issue := range report.Issues {
+		marker := "⚠️ "
+		if issue.Fixed {
+			marker = "🔧"
+		} else if issue.FixError != "" {
+			marker = "❌"
+		}
+		fmt.Printf("%s [%s] %s", marker, issue.Type, issue.Collection)
+		if issue.DocumentID != "" {
+			fmt.Printf("/%s", issue.DocumentID)
+		}
+		fmt.Printf(": %s\n", issue.Detail)
+		if issue.FixError != "" {
+			fmt.Printf("   Erro na correção: %s\n", issue.FixError)
+		}
+	}
+
+	if report.FixApplied {
+		fmt.Printf("\n🔧 %d inconsistências corrigidas\n", report.TotalFixed)
+	} else {
+		fmt.Println("\nExecute com --fix para remover os documentos órfãos.")
+	}
+}
+
+func printJSON(v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Fatalf("Erro ao serializar JSON: %v", err)
+	}
+	fmt.Println(string(data))
+}
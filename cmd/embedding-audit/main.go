@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
+)
+
+var (
+	sample      = flag.Int("sample", 100, "Documentos sorteados para a auditoria (0 = todos)")
+	threshold   = flag.Float64("threshold", 0.03, "Distância de cosseno a partir da qual o vetor é considerado desatualizado")
+	seed        = flag.Int64("seed", 0, "Semente do sorteio (0 = aleatória)")
+	concurrency = flag.Int("concurrency", 4, "Embeddings gerados em paralelo")
+	fix         = flag.Bool("fix", false, "Regrava os embeddings dos documentos reportados")
+	force       = flag.Bool("force", false, "Regrava os embeddings de todos os documentos sorteados, mesmo os em dia (implica --fix)")
+	filter      = flag.String("filter", "", "filter_by do Typesense que restringe os documentos (ex: 'status:=1 && tema_geral:=Saúde')")
+	jsonOutput  = flag.Bool("json", false, "Saída em formato JSON")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Uso: %s [opções]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Sorteia serviços, regenera o embedding do search_content atual e compara com o\n")
+		fmt.Fprintf(os.Stderr, "vetor armazenado, reportando documentos editados sem atualização do embedding.\n")
+		fmt.Fprintf(os.Stderr, "Com --sample 0 --force --filter, reindexa os vetores de todos os documentos do filtro\n")
+		fmt.Fprintf(os.Stderr, "(--fix regrava apenas os desatualizados).\n")
+		fmt.Fprintf(os.Stderr, "\nOpções:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cfg := config.LoadConfig()
+	typesenseClient := typesense.NewClient(cfg)
+
+	if *fix || *force {
+		migrationService := services.NewMigrationService(typesenseClient.GetClient(), nil)
+		locked, err := migrationService.IsMigrationLocked(context.Background())
+		if err == nil && locked {
+			fmt.Fprintln(os.Stderr, "❌ Existe uma migração em andamento, --fix/--force não podem ser executados agora")
+			os.Exit(1)
+		}
+	}
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	report, err := typesenseClient.AuditEmbeddings(context.Background(), models.EmbeddingAuditOptions{
+		SampleSize:  *sample,
+		Threshold:   *threshold,
+		Seed:        *seed,
+		Concurrency: *concurrency,
+		Fix:         *fix,
+		Force:       *force,
+		FilterBy:    *filter,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Erro na auditoria de embeddings: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		printJSON(report)
+	} else {
+		printReport(report)
+	}
+
+	// Código de saída != 0 quando restam vetores desatualizados (útil em pipelines)
+	if len(report.Drifted) > report.TotalFixed {
+		os.Exit(2)
+	}
+}
+
+func printReport(report *models.EmbeddingAuditReport) {
+	fmt.Println("🧭 Auditoria de Embeddings")
+	fmt.Println("--------------------------")
+	fmt.Printf("Executado em: %s (%dms)\n", time.Unix(report.CheckedAt, 0).Format("02/01/2006 15:04:05"), report.DurationMs)
+	fmt.Printf("Modelo: %s | limite de distância: %.3f (semente %d)\n", report.Model, report.Threshold, *seed)
+	if report.Filter != "" {
+		fmt.Printf("Filtro: %s\n", report.Filter)
+	}
+	fmt.Printf("Documentos: %d sorteados de %d, %d comparados\n", report.Sampled, report.TotalDocuments, report.Checked)
+	fmt.Printf("Distância média: %.4f | máxima: %.4f\n", report.MeanDistance, report.MaxDistance)
+
+	if len(report.Errors) > 0 {
+		fmt.Printf("\n❌ %d documentos não puderam ser verificados:\n", len(report.Errors))
+		for _, e := range report.Errors {
+			fmt.Printf("   %s\n", e)
+		}
+	}
+
+	// Com --force os vetores em dia também são regravados; só os que falharam são listados
+	drifted, forced := 0, 0
+	for _, drift := range report.Drifted {
+		if drift.Reason == models.EmbeddingForced {
+			if drift.Fixed {
+				forced++
+			}
+		} else {
+			drifted++
+		}
+	}
+
+	if drifted == 0 {
+		fmt.Println("\n✅ Nenhum embedding desatualizado na amostra.")
+		if len(report.Drifted) == 0 {
+			return
+		}
+	} else {
+		fmt.Printf("\n%d documentos com embedding que não reflete o conteúdo:\n", drifted)
+	}
+	for _, drift := range report.Drifted {
+		if drift.Reason == models.EmbeddingForced && drift.FixError == "" {
+			continue
+		}
+		marker := "⚠️ "
+		if drift.Fixed {
+			marker = "🔧"
+		} else if drift.FixError != "" {
+			marker = "❌"
+		}
+		fmt.Printf("%s [%s] %s - %s", marker, drift.Reason, drift.ID, drift.NomeServico)
+		if drift.Reason == models.EmbeddingDrifted {
+			fmt.Printf(" (distância %.4f)", drift.Distance)
+		}
+		if drift.StoredModel != "" && drift.Reason == models.EmbeddingModelMismatch {
+			fmt.Printf(" (modelo %s)", drift.StoredModel)
+		}
+		fmt.Println()
+		if drift.FixError != "" {
+			fmt.Printf("   Erro na correção: %s\n", drift.FixError)
+		}
+	}
+
+	if forced > 0 {
+		fmt.Printf("\n%d embeddings em dia regravados por --force\n", forced)
+	}
+	if report.FixApplied {
+		fmt.Printf("\n🔧 %d embeddings regravados\n", report.TotalFixed)
+	} else if drifted > 0 {
+		fmt.Println("\nExecute com --fix para regravar os embeddings reportados.")
+	}
+}
+
+func printJSON(v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Fatalf("Erro ao serializar JSON: %v", err)
+	}
+	fmt.Println(string(data))
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/typesense/typesense-go/v3/typesense"
+)
+
+var (
+	collection        = flag.String("collection", services.PrefRioServicesCollection, "Collection ou alias a exportar")
+	output            = flag.String("output", "", "Arquivo JSONL de destino (padrão: saída padrão)")
+	schemaOutput      = flag.String("schema", "", "Grava também o schema da collection neste arquivo JSON")
+	includeEmbeddings = flag.Bool("include-embeddings", false, "Inclui os campos vetoriais (embeddings)")
+	filterBy          = flag.String("filter", "", "Filtro do Typesense (ex.: status:=1)")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Uso: %s [opções]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Exporta todos os documentos de uma collection do Typesense em JSONL (um documento\n")
+		fmt.Fprintf(os.Stderr, "por linha) pela API de export, para backups fora das collections de backup das\n")
+		fmt.Fprintf(os.Stderr, "migrações. O arquivo pode ser reimportado com a API de import do Typesense.\n")
+		fmt.Fprintf(os.Stderr, "\nOpções:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cfg := config.LoadConfig()
+
+	// Cliente Typesense com timeout maior para exportação completa das collections
+	typesenseClient := typesense.NewClient(
+		typesense.WithServer(fmt.Sprintf("%s://%s:%s", cfg.TypesenseProtocol, cfg.TypesenseHost, cfg.TypesensePort)),
+		typesense.WithAPIKey(cfg.TypesenseAPIKey),
+		typesense.WithConnectionTimeout(30*time.Minute),
+	)
+	exporter := services.NewCollectionExporter(typesenseClient)
+	ctx := context.Background()
+	start := time.Now()
+
+	if *schemaOutput != "" {
+		schema, err := exporter.Schema(ctx, *collection)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Erro ao ler o schema: %v\n", err)
+			os.Exit(1)
+		}
+		data, _ := json.MarshalIndent(schema, "", "  ")
+		if err := os.WriteFile(*schemaOutput, append(data, '\n'), 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Erro ao gravar o schema: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	body, err := exporter.Export(ctx, *collection, services.CollectionExportOptions{
+		IncludeEmbeddings: *includeEmbeddings,
+		FilterBy:          *filterBy,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+	defer body.Close()
+
+	documents, err := writeExport(body, *output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Erro ao exportar: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "✅ %d documentos de %s exportados em %s\n", documents, *collection, time.Since(start).Round(time.Millisecond))
+}
+
+// writeExport copia o export para o arquivo (ou para a saída padrão), contando os documentos.
+// O arquivo é gravado com um nome temporário e renomeado ao final, para que um export
+// interrompido não seja confundido com um backup completo.
+func writeExport(body io.Reader, path string) (int, error) {
+	if path == "" {
+		return copyLines(os.Stdout, body)
+	}
+
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return 0, err
+	}
+	documents, err := copyLines(file, body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return 0, err
+	}
+	return documents, os.Rename(tmp, path)
+}
+
+// copyLines copia as linhas não vazias do export, garantindo a quebra de linha final
+func copyLines(dst io.Writer, body io.Reader) (int, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 50*1024*1024)
+	w := bufio.NewWriterSize(dst, 256*1024)
+
+	documents := 0
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		w.Write(line)
+		w.WriteByte('\n')
+		documents++
+	}
+	if err := scanner.Err(); err != nil {
+		return documents, err
+	}
+	return documents, w.Flush()
+}
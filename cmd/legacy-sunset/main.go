@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/typesense/typesense-go/v3/typesense"
+)
+
+var (
+	days       = flag.Int("days", 30, "Período dos cliques considerados, em dias")
+	limit      = flag.Int("limit", 0, "Itens da lista de tombamento (0 = todos)")
+	csvOutput  = flag.String("csv", "", "Grava a lista de tombamento priorizada neste arquivo CSV")
+	jsonOutput = flag.Bool("json", false, "Saída em formato JSON")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Uso: %s [opções]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Mede os documentos ainda não tombados das collections legadas (1746_v2_llm,\n")
+		fmt.Fprintf(os.Stderr, "carioca-digital_v2_llm) e o tráfego que recebem, e gera a lista de tombamento\n")
+		fmt.Fprintf(os.Stderr, "priorizada pelos mais acessados.\n")
+		fmt.Fprintf(os.Stderr, "\nOpções:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cfg := config.LoadConfig()
+
+	// Cliente Typesense com timeout maior para exportação completa das collections
+	typesenseClient := typesense.NewClient(
+		typesense.WithServer(fmt.Sprintf("%s://%s:%s", cfg.TypesenseProtocol, cfg.TypesenseHost, cfg.TypesensePort)),
+		typesense.WithAPIKey(cfg.TypesenseAPIKey),
+		typesense.WithConnectionTimeout(10*time.Minute),
+	)
+
+	var journeys *services.JourneyAnalytics
+	if cfg.JourneyAnalyticsEnabled {
+		journeys = services.NewJourneyAnalytics(typesenseClient)
+	}
+
+	report, err := services.NewLegacySunsetService(typesenseClient, journeys, cfg).Report(context.Background(), *days, *limit)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Erro ao gerar relatório: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *csvOutput != "" {
+		if err := writeWorklist(*csvOutput, report.Worklist); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Erro ao gravar a lista de tombamento: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *jsonOutput {
+		printJSON(report)
+	} else {
+		printReport(report)
+	}
+}
+
+func printReport(report *models.LegacySunsetReport) {
+	fmt.Println("🌅 Desativação das Collections Legadas")
+	fmt.Println("--------------------------------------")
+	fmt.Printf("Executado em: %s (%dms)\n", time.Unix(report.GeneratedAt, 0).Format("02/01/2006 15:04:05"), report.DurationMs)
+	fmt.Printf("Cliques nos últimos %d dias: %d\n", report.Days, report.TotalClicks)
+	if report.Frozen {
+		fmt.Println("🧊 Collections legadas congeladas (somente leitura)")
+	}
+
+	fmt.Println("\nCollections:")
+	remaining := 0
+	for _, status := range report.Collections {
+		fmt.Printf("   %s: %d de %d documentos restantes (%d tombados), %d cliques (%.1f%% do tráfego)\n",
+			status.Collection, status.Remaining, status.TotalDocuments, status.Tombados, status.Clicks, status.TrafficShare*100)
+		remaining += status.Remaining
+	}
+
+	if remaining == 0 {
+		fmt.Println("\n✅ Todos os documentos legados foram tombados.")
+		return
+	}
+
+	fmt.Println("\nLista de tombamento:")
+	for _, item := range report.Worklist {
+		fmt.Printf("%4d. [%s] %s", item.Priority, item.Collection, item.ID)
+		if item.Title != "" {
+			fmt.Printf(" - %s", item.Title)
+		}
+		fmt.Printf(" (%d cliques)\n", item.Clicks)
+	}
+	if len(report.Worklist) < remaining {
+		fmt.Printf("... e mais %d documentos (use --limit 0 para listar todos)\n", remaining-len(report.Worklist))
+	}
+}
+
+// writeWorklist grava a lista de tombamento em CSV, para distribuição entre os órgãos
+func writeWorklist(path string, worklist []models.LegacySunsetItem) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"prioridade", "origem", "id_servico_antigo", "titulo", "cliques", "fracao_trafego"})
+	for _, item := range worklist {
+		writer.Write([]string{
+			strconv.Itoa(item.Priority),
+			item.Collection,
+			item.ID,
+			item.Title,
+			strconv.Itoa(item.Clicks),
+			strconv.FormatFloat(item.TrafficShare, 'f', 4, 64),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func printJSON(v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Fatalf("Erro ao serializar JSON: %v", err)
+	}
+	fmt.Println(string(data))
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/mcp"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
+	"google.golang.org/genai"
+)
+
+var (
+	transport = flag.String("transport", "stdio", "Transporte MCP: stdio ou sse")
+	addr      = flag.String("addr", ":8090", "Endereço HTTP para o transporte sse")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Uso: %s [opções]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Servidor MCP (Model Context Protocol) do catálogo de serviços.\n")
+		fmt.Fprintf(os.Stderr, "Ferramentas: search, get_document, list_categories\n")
+		fmt.Fprintf(os.Stderr, "\nOpções:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	// No modo stdio o stdout é reservado ao protocolo: qualquer saída das
+	// dependências (prints de inicialização) é redirecionada para o stderr
+	protocolOut := os.Stdout
+	os.Stdout = os.Stderr
+	log.SetOutput(os.Stderr)
+
+	cfg := config.LoadConfig()
+	ctx := context.Background()
+
+	typesenseClient := typesense.NewClient(cfg)
+
+	geminiClient, err := genai.NewClient(ctx, &genai.ClientConfig{
+		APIKey: cfg.GeminiAPIKey,
+	})
+	if err != nil {
+		log.Printf("Aviso: Gemini client não inicializado, busca vetorial desabilitada: %v", err)
+		geminiClient = nil
+	}
+
+	cache := services.NewLRUCache(500)
+	cache.StartCleanupRoutine(5 * time.Minute)
+
+	typesenseURL := fmt.Sprintf("%s://%s:%s", cfg.TypesenseProtocol, cfg.TypesenseHost, cfg.TypesensePort)
+	searchService := services.NewSearchService(
+		typesenseClient.GetClient(),
+		geminiClient,
+		cfg.GeminiEmbeddingModel,
+		cache,
+		typesenseURL,
+		cfg.TypesenseAPIKey,
+	)
+
+	popularityService := services.NewPopularityService()
+	categoryService := services.NewCategoryService(typesenseClient.GetClient(), popularityService)
+
+	server := mcp.NewServer(searchService, categoryService, typesenseClient)
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch *transport {
+	case "stdio":
+		log.Println("[MCP] Servidor iniciado via stdio")
+		if err := server.ServeStdio(ctx, os.Stdin, protocolOut); err != nil {
+			log.Fatalf("Erro no servidor MCP: %v", err)
+		}
+	case "sse":
+		if cfg.MCPToken == "" && !cfg.MultiTenant() {
+			log.Fatal("O transporte sse exige MCP_TOKEN ou API keys de tenant (TENANT_CONFIGS)")
+		}
+		httpServer := &http.Server{
+			Addr:    *addr,
+			Handler: mcp.NewSSEHandler(server, mcp.APIKeyAuthenticator(cfg)),
+		}
+
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			httpServer.Shutdown(shutdownCtx)
+		}()
+
+		log.Printf("[MCP] Servidor SSE iniciado em %s (GET /sse, POST /message; exige API key ou MCP_TOKEN)", *addr)
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Erro ao iniciar servidor MCP: %v", err)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Transporte desconhecido: %s\n", *transport)
+		flag.Usage()
+		os.Exit(1)
+	}
+}
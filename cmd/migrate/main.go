@@ -62,6 +62,13 @@ func main() {
 
 	ctx := context.Background()
 
+	// Campos promovidos dos schemas de extra_fields entram na collection migrada
+	extraFieldSchemas := services.NewExtraFieldSchemas(typesenseClient)
+	if err := extraFieldSchemas.Reload(ctx); err != nil {
+		log.Fatalf("Erro ao carregar schemas de extra_fields: %v", err)
+	}
+	migrationService.SetExtraFieldSchemas(extraFieldSchemas)
+
 	switch command {
 	case "start":
 		cmdStart(ctx, migrationService)
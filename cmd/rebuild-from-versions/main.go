@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+var (
+	target         = flag.String("target", "", "Collection de destino (padrão: prefrio_services_rebuild_<timestamp>)")
+	dryRun         = flag.Bool("dry-run", false, "Apenas reconstrói em memória e compara com a collection atual, sem gravar")
+	skipEmbeddings = flag.Bool("skip-embeddings", false, "Não regenera os embeddings (busca semântica indisponível até a auditoria com --fix)")
+	concurrency    = flag.Int("concurrency", 4, "Embeddings gerados em paralelo")
+	rateLimit      = flag.Float64("rate-limit", 10, "Chamadas de embedding ao Gemini por segundo (0 = sem limite)")
+	embedBatch     = flag.Int("embed-batch", 50, "Serviços embedados por chamada ao Gemini (máximo 100; 1 = um por chamada)")
+	swap           = flag.Bool("swap", false, "Aponta o alias prefrio_services_base para a collection reconstruída")
+	force          = flag.Bool("force", false, "Permite o --swap mesmo com erros ou documentos sem versões (que serão perdidos)")
+	jsonOutput     = flag.Bool("json", false, "Saída em formato JSON")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Uso: %s [opções]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Reconstrói a collection de serviços a partir da última versão de cada serviço em\n")
+		fmt.Fprintf(os.Stderr, "service_versions (recuperação de desastre), regenerando os embeddings, e compara o\n")
+		fmt.Fprintf(os.Stderr, "resultado com a collection atual. Campos fora dos snapshots de versão (botões,\n")
+		fmt.Fprintf(os.Stderr, "extra_fields, anexos, bairros, tenant) não são recuperados.\n")
+		fmt.Fprintf(os.Stderr, "\nOpções:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *swap && *dryRun {
+		fmt.Fprintln(os.Stderr, "❌ --swap não pode ser usado com --dry-run")
+		os.Exit(1)
+	}
+	if *target == "" && !*dryRun {
+		*target = fmt.Sprintf("prefrio_services_rebuild_%d", time.Now().Unix())
+	}
+	if *target == services.PrefRioServicesCollection {
+		fmt.Fprintf(os.Stderr, "❌ O destino não pode ser %s: reconstrua em outra collection e use --swap\n", services.PrefRioServicesCollection)
+		os.Exit(1)
+	}
+
+	cfg := config.LoadConfig()
+	typesenseClient := typesense.NewClient(cfg)
+	ctx := context.Background()
+
+	if !*dryRun {
+		migrationService := services.NewMigrationService(typesenseClient.GetClient(), nil)
+		locked, err := migrationService.IsMigrationLocked(ctx)
+		if err == nil && locked {
+			fmt.Fprintln(os.Stderr, "❌ Existe uma migração em andamento, a reconstrução não pode ser executada agora")
+			os.Exit(1)
+		}
+	}
+
+	report, err := typesenseClient.RebuildFromVersions(ctx, models.VersionRebuildOptions{
+		Target:         *target,
+		DryRun:         *dryRun,
+		SkipEmbeddings: *skipEmbeddings,
+		Concurrency:    *concurrency,
+		RateLimit:      *rateLimit,
+		EmbedBatch:     *embedBatch,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Erro na reconstrução: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		printJSON(report)
+	} else {
+		printReport(report)
+	}
+
+	if *swap {
+		if (len(report.Errors) > 0 || report.Summary[models.RebuildMissingInVersions] > 0) && !*force {
+			fmt.Fprintln(os.Stderr, "❌ Alias não atualizado: a reconstrução teve erros ou documentos sem versões (use --force para atualizar mesmo assim)")
+			os.Exit(2)
+		}
+		_, err := typesenseClient.GetClient().Aliases().Upsert(ctx, services.PrefRioServicesCollection, &api.CollectionAliasSchema{
+			CollectionName: report.Target,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Erro ao atualizar alias: %v\n", err)
+			os.Exit(1)
+		}
+		if !*jsonOutput {
+			fmt.Printf("\n🔀 Alias %s agora aponta para %s\n", services.PrefRioServicesCollection, report.Target)
+		}
+	}
+
+	// Código de saída != 0 quando houve erros (útil em pipelines)
+	if len(report.Errors) > 0 {
+		os.Exit(2)
+	}
+}
+
+func printReport(report *models.VersionRebuildReport) {
+	fmt.Println("🧱 Reconstrução a partir das versões")
+	fmt.Println("------------------------------------")
+	fmt.Printf("Executado em: %s (%dms)\n", time.Unix(report.StartedAt, 0).Format("02/01/2006 15:04:05"), report.DurationMs)
+	fmt.Printf("Versões: %d de %d serviços (%d removidos ignorados)\n", report.TotalVersions, report.TotalServices, report.SkippedDeleted)
+	if report.DryRun {
+		fmt.Println("Modo: simulação (nada foi gravado)")
+	} else {
+		fmt.Printf("Destino: %s | %d documentos gravados, %d na collection | %d embeddings gerados em %d chamadas (%d novas tentativas)\n",
+			report.Target, report.Rebuilt, report.TargetDocuments, report.EmbeddingsGenerated, report.EmbeddingRequests, report.EmbeddingRetries)
+	}
+
+	if len(report.Errors) > 0 {
+		fmt.Printf("\n❌ %d erros:\n", len(report.Errors))
+		for _, e := range report.Errors {
+			fmt.Printf("   %s\n", e)
+		}
+	}
+
+	fmt.Printf("\nVerificação contra %s:\n", report.Source)
+	if len(report.Diffs) == 0 {
+		fmt.Println("✅ Nenhuma divergência entre as versões e a collection atual.")
+	} else {
+		types := make([]string, 0, len(report.Summary))
+		for diffType := range report.Summary {
+			types = append(types, string(diffType))
+		}
+		sort.Strings(types)
+		for _, diffType := range types {
+			fmt.Printf("   %s: %d\n", diffType, report.Summary[models.VersionRebuildDiffType(diffType)])
+		}
+
+		fmt.Println("\nDetalhes:")
+		for _, diff := range report.Diffs {
+			fmt.Printf("⚠️  [%s] %s - %s", diff.Type, diff.ServiceID, diff.NomeServico)
+			if len(diff.Fields) > 0 {
+				fmt.Printf(" (%v)", diff.Fields)
+			}
+			fmt.Println()
+		}
+	}
+
+	if !report.DryRun && !*swap {
+		fmt.Printf("\nRevise a collection %s e execute novamente com --target %s --swap para ativá-la.\n", report.Target, report.Target)
+	}
+}
+
+func printJSON(v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Fatalf("Erro ao serializar JSON: %v", err)
+	}
+	fmt.Println(string(data))
+}
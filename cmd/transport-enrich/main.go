@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
+)
+
+var (
+	force      = flag.Bool("force", false, "Reprocessa também os serviços já enriquecidos e não editados")
+	jsonOutput = flag.Bool("json", false, "Saída em formato JSON")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Uso: %s [opções]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Geocodifica os canais presenciais dos serviços e grava as estações de transporte\n")
+		fmt.Fprintf(os.Stderr, "público mais próximas (TRANSPORT_STOPS_FILE) exibidas nos detalhes do serviço.\n")
+		fmt.Fprintf(os.Stderr, "\nOpções:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cfg := config.LoadConfig()
+	enricher, err := services.NewTransportEnricherFromConfig(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+	if !enricher.Enabled() {
+		fmt.Fprintln(os.Stderr, "❌ TRANSPORT_STOPS_FILE não configurado ou sem estações")
+		os.Exit(1)
+	}
+
+	typesenseClient := typesense.NewClient(cfg)
+
+	migrationService := services.NewMigrationService(typesenseClient.GetClient(), nil)
+	locked, err := migrationService.IsMigrationLocked(context.Background())
+	if err == nil && locked {
+		fmt.Fprintln(os.Stderr, "❌ Existe uma migração em andamento, o enriquecimento não pode ser executado agora")
+		os.Exit(1)
+	}
+
+	report, err := typesenseClient.BackfillServiceTransport(context.Background(), enricher, *force)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Erro no enriquecimento de transporte: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		printJSON(report)
+	} else {
+		printReport(report)
+	}
+}
+
+func printReport(report *models.TransportEnrichReport) {
+	fmt.Println("🚉 Transporte próximo dos canais presenciais")
+	fmt.Println("--------------------------------------------")
+	fmt.Printf("Serviços com canais presenciais: %d (%dms)\n", report.Checked, report.DurationMs)
+	fmt.Printf("Serviços enriquecidos: %d | locais geocodificados: %d\n", report.Enriched, report.Locations)
+
+	if len(report.Errors) > 0 {
+		fmt.Printf("\n❌ %d serviços não puderam ser enriquecidos:\n", len(report.Errors))
+		for _, e := range report.Errors {
+			fmt.Printf("   %s\n", e)
+		}
+	}
+
+	if len(report.Unresolved) > 0 {
+		fmt.Printf("\n⚠️  %d canais presenciais sem endereço localizado:\n", len(report.Unresolved))
+		for _, canal := range report.Unresolved {
+			fmt.Printf("   %s\n", canal)
+		}
+		return
+	}
+
+	fmt.Println("\n✅ Todos os canais presenciais foram localizados.")
+}
+
+func printJSON(v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Fatalf("Erro ao serializar JSON: %v", err)
+	}
+	fmt.Println(string(data))
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// AbuseFilterHandler gerencia a lista de termos ofensivos e expõe os incidentes por cliente
+type AbuseFilterHandler struct {
+	filter *services.AbuseFilter
+}
+
+// NewAbuseFilterHandler cria um novo handler do filtro de abuso
+func NewAbuseFilterHandler(filter *services.AbuseFilter) *AbuseFilterHandler {
+	return &AbuseFilterHandler{filter: filter}
+}
+
+// GetWords godoc
+// @Summary Lista os termos do filtro de abuso
+// @Description Retorna a lista de palavrões e ofensas usada para classificar as buscas. Buscas que contêm algum termo são atendidas apenas pela busca textual e contam como incidente do cliente.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.AbuseWordList
+// @Failure 401 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/abuse-filter/words [get]
+func (h *AbuseFilterHandler) GetWords(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+	c.JSON(http.StatusOK, h.filter.WordList())
+}
+
+// UpdateWords godoc
+// @Summary Substitui os termos do filtro de abuso
+// @Description Substitui a lista de termos ofensivos. A comparação ignora acentos e caixa e considera palavras inteiras; a lista é propagada às demais réplicas em alguns minutos.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body models.AbuseWordListRequest true "Nova lista de termos"
+// @Success 200 {object} models.AbuseWordList
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/abuse-filter/words [put]
+func (h *AbuseFilterHandler) UpdateWords(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	var request models.AbuseWordListRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	list, err := h.filter.UpdateWords(writeContext(c), request.Words, middlewares.GetUserName(c))
+	if errors.Is(err, services.ErrEmptyAbuseWordList) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar termos: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// GetIncidents godoc
+// @Summary Incidentes do filtro de abuso
+// @Description Retorna o total de buscas ofensivas desde o início do processo, os incidentes por cliente (IP ou hash da API key) na janela de contagem e os clientes bloqueados.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.AbuseReport
+// @Failure 401 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/reports/abuse [get]
+func (h *AbuseFilterHandler) GetIncidents(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+	c.JSON(http.StatusOK, h.filter.Report())
+}
+
+// enabled responde 503 quando o filtro está desabilitado (ABUSE_FILTER_ENABLED=false)
+func (h *AbuseFilterHandler) enabled(c *gin.Context) bool {
+	if h.filter == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Filtro de abuso desabilitado"})
+		return false
+	}
+	return true
+}
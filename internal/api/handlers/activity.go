@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// ActivityHandler gerencia o relatório de atividade editorial
+type ActivityHandler struct {
+	activityService *services.ActivityService
+	staleDays       int
+}
+
+// NewActivityHandler cria um novo handler de atividade editorial
+func NewActivityHandler(activityService *services.ActivityService, staleDays int) *ActivityHandler {
+	return &ActivityHandler{
+		activityService: activityService,
+		staleDays:       staleDays,
+	}
+}
+
+// GetActivity godoc
+// @Summary Relatório de atividade editorial
+// @Description Agrega as edições por usuário e por órgão gestor em cada semana e sinaliza os rascunhos sem edições há mais de stale_days dias, a partir do histórico de versões
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param weeks query int false "Quantidade de semanas agregadas (incluindo a atual)" default(8)
+// @Param stale_days query int false "Dias sem edição para considerar um rascunho parado" default(30)
+// @Success 200 {object} models.ActivityReport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/reports/activity [get]
+func (h *ActivityHandler) GetActivity(c *gin.Context) {
+	weeks, err := strconv.Atoi(c.DefaultQuery("weeks", "8"))
+	if err != nil || weeks < 1 || weeks > 52 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "weeks deve ser um inteiro entre 1 e 52"})
+		return
+	}
+
+	staleDays := h.staleDays
+	if value := c.Query("stale_days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "stale_days deve ser um inteiro positivo"})
+			return
+		}
+		staleDays = parsed
+	}
+
+	report, err := h.activityService.GenerateReport(c.Request.Context(), weeks, staleDays)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar relatório de atividade: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
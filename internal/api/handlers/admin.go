@@ -1,61 +1,146 @@
 package handlers
 
 import (
-	"context"
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
 	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
 	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
 	"github.com/prefeitura-rio/app-busca-search/internal/utils"
 )
 
 type AdminHandler struct {
-	typesenseClient *typesense.Client
-	validator       *validator.Validate
+	typesenseClient         *typesense.Client
+	validator               *validator.Validate
+	classifier              *services.CategoryClassifier
+	extractor               *services.EntityExtractor
+	availabilityWarningDays int
+	extraFieldSchemas       *services.ExtraFieldSchemas
+	neighborhoods           *services.Neighborhoods
+	orgaos                  *services.Orgaos
+	transport               *services.TransportEnricher
+	legacyFrozen            bool
 }
 
-func NewAdminHandler(client *typesense.Client) *AdminHandler {
+func NewAdminHandler(client *typesense.Client, classifier *services.CategoryClassifier, extractor *services.EntityExtractor, availabilityWarningDays int) *AdminHandler {
 	return &AdminHandler{
-		typesenseClient: client,
-		validator:       validator.New(),
+		typesenseClient:         client,
+		validator:               validator.New(),
+		classifier:              classifier,
+		extractor:               extractor,
+		availabilityWarningDays: availabilityWarningDays,
 	}
 }
 
-// CreateService godoc
-// @Summary Cria um novo serviço
-// @Description Cria um novo serviço na collection prefrio_services_base. A resposta inclui campos plaintext gerados automaticamente (resumo_plaintext, resultado_solicitacao_plaintext, descricao_completa_plaintext, documentos_necessarios_plaintext, instrucoes_solicitante_plaintext) que removem toda formatação markdown.
-// @Tags admin
-// @Accept json
-// @Produce json
-// @Param service body models.PrefRioServiceRequest true "Dados do serviço"
-// @Success 201 {object} models.PrefRioService
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 500 {object} map[string]string
-// @Router /api/v1/admin/services [post]
-func (h *AdminHandler) CreateService(c *gin.Context) {
-	var request models.PrefRioServiceRequest
-	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
-		return
-	}
+// SetExtraFieldSchemas habilita a validação do extra_fields pelo schema do tema
+func (h *AdminHandler) SetExtraFieldSchemas(schemas *services.ExtraFieldSchemas) {
+	h.extraFieldSchemas = schemas
+}
 
-	// Valida os dados
+// SetNeighborhoods habilita a validação dos bairros dos serviços pela lista canônica
+func (h *AdminHandler) SetNeighborhoods(neighborhoods *services.Neighborhoods) {
+	h.neighborhoods = neighborhoods
+}
+
+// SetOrgaos habilita a validação do orgao_gestor dos serviços pelo diretório de órgãos
+func (h *AdminHandler) SetOrgaos(orgaos *services.Orgaos) {
+	h.orgaos = orgaos
+}
+
+// SetTransportEnricher habilita o enriquecimento dos canais presenciais com o transporte próximo
+func (h *AdminHandler) SetTransportEnricher(enricher *services.TransportEnricher) {
+	h.transport = enricher
+}
+
+// SetLegacyFrozen recusa a criação de tombamentos na publicação (LEGACY_COLLECTIONS_FROZEN)
+func (h *AdminHandler) SetLegacyFrozen(frozen bool) {
+	h.legacyFrozen = frozen
+}
+
+// validateServiceRequest aplica as validações da criação e da atualização de serviços e retorna
+// os bairros canônicos com as regiões administrativas correspondentes
+func (h *AdminHandler) validateServiceRequest(request *models.PrefRioServiceRequest) ([]string, []string, error) {
 	if err := h.validator.Struct(request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Validação falhou: " + err.Error()})
-		return
+		return nil, nil, err
+	}
+	if err := services.ValidateAvailabilityWindow(request.AvailableFrom, request.AvailableUntil); err != nil {
+		return nil, nil, err
+	}
+	if err := services.ValidateServiceInput(request); err != nil {
+		return nil, nil, err
+	}
+	if err := h.extraFieldSchemas.Validate(request.TemaGeral, request.ExtraFields); err != nil {
+		return nil, nil, err
+	}
+	if err := h.orgaos.Validate(request.OrgaoGestor); err != nil {
+		return nil, nil, err
+	}
+	return h.neighborhoods.Canonicalize(request.Bairros)
+}
+
+// newServiceFromRequest monta um serviço novo a partir da requisição
+func newServiceFromRequest(serviceID string, request *models.PrefRioServiceRequest, author string, bairros, regioes []string) *models.PrefRioService {
+	return &models.PrefRioService{
+		ID:                    serviceID,
+		NomeServico:           request.NomeServico,
+		OrgaoGestor:           request.OrgaoGestor,
+		Resumo:                request.Resumo,
+		TempoAtendimento:      request.TempoAtendimento,
+		CustoServico:          request.CustoServico,
+		ResultadoSolicitacao:  request.ResultadoSolicitacao,
+		DescricaoCompleta:     request.DescricaoCompleta,
+		Autor:                 author, // Preenchimento automático
+		DocumentosNecessarios: request.DocumentosNecessarios,
+		InstrucoesSolicitante: request.InstrucoesSolicitante,
+		CanaisDigitais:        request.CanaisDigitais,
+		CanaisPresenciais:     request.CanaisPresenciais,
+		ServicoNaoCobre:       request.ServicoNaoCobre,
+		LegislacaoRelacionada: request.LegislacaoRelacionada,
+		TemaGeral:             request.TemaGeral,
+		SubCategoria:          request.SubCategoria,
+		PublicoEspecifico:     request.PublicoEspecifico,
+		FixarDestaque:         request.FixarDestaque,
+		AwaitingApproval:      request.AwaitingApproval,
+		PublishedAt:           request.PublishedAt,
+		IsFree:                request.IsFree,
+		Agents:                request.Agents,
+		ExtraFields:           request.ExtraFields,
+		Status:                request.Status,
+		Buttons:               request.Buttons,
+		Slug:                  utils.GenerateSlug(request.NomeServico, serviceID),
+		SlugHistory:           []string{},
+		AvailableFrom:         request.AvailableFrom,
+		AvailableUntil:        request.AvailableUntil,
+		DestaqueUntil:         request.DestaqueUntil,
+		Bairros:               bairros,
+		RegioesAdmin:          regioes,
 	}
+}
 
-	serviceID := uuid.New().String()
-	slug := utils.GenerateSlug(request.NomeServico, serviceID)
+// updatedServiceFromRequest aplica a requisição ao serviço existente, preservando os dados
+// geridos fora da edição (autor, criação, descontinuação, anexos)
+func updatedServiceFromRequest(serviceID string, existingService *models.PrefRioService, request *models.PrefRioServiceRequest, bairros, regioes []string) *models.PrefRioService {
+	// Gerencia slug: se nome mudou, atualiza slug e adiciona antigo ao histórico
+	slug := existingService.Slug
+	slugHistory := existingService.SlugHistory
+	if request.NomeServico != existingService.NomeServico {
+		if slug != "" {
+			slugHistory = append(slugHistory, slug)
+		}
+		slug = utils.GenerateSlug(request.NomeServico, serviceID)
+	}
 
-	// Converte para modelo completo
-	service := &models.PrefRioService{
+	// Converte para modelo completo preservando dados existentes
+	return &models.PrefRioService{
 		ID:                    serviceID,
 		NomeServico:           request.NomeServico,
 		OrgaoGestor:           request.OrgaoGestor,
@@ -64,7 +149,7 @@ func (h *AdminHandler) CreateService(c *gin.Context) {
 		CustoServico:          request.CustoServico,
 		ResultadoSolicitacao:  request.ResultadoSolicitacao,
 		DescricaoCompleta:     request.DescricaoCompleta,
-		Autor:                 middlewares.GetUserName(c), // Preenchimento automático
+		Autor:                 existingService.Autor, // Preserva autor original
 		DocumentosNecessarios: request.DocumentosNecessarios,
 		InstrucoesSolicitante: request.InstrucoesSolicitante,
 		CanaisDigitais:        request.CanaisDigitais,
@@ -82,12 +167,59 @@ func (h *AdminHandler) CreateService(c *gin.Context) {
 		ExtraFields:           request.ExtraFields,
 		Status:                request.Status,
 		Buttons:               request.Buttons,
+		CreatedAt:             existingService.CreatedAt, // Preserva data de criação
 		Slug:                  slug,
-		SlugHistory:           []string{},
+		SlugHistory:           slugHistory,
+		Deprecated:            existingService.Deprecated, // Descontinuação é gerida pelos endpoints próprios
+		ReplacedBy:            existingService.ReplacedBy,
+		SunsetAt:              existingService.SunsetAt,
+		Attachments:           existingService.Attachments, // Anexos são geridos pelos endpoints próprios
+		AvailableFrom:         request.AvailableFrom,
+		AvailableUntil:        request.AvailableUntil,
+		DestaqueUntil:         request.DestaqueUntil,
+		SeasonalPaused:        existingService.SeasonalPaused && request.Status == 0, // Publicação manual encerra a pausa sazonal
+		Bairros:               bairros,
+		RegioesAdmin:          regioes,
 	}
+}
+
+// CreateService godoc
+// @Summary Cria um novo serviço
+// @Description Cria um novo serviço na collection prefrio_services_base. A resposta inclui campos plaintext gravados na indexação (resumo_plaintext, resultado_solicitacao_plaintext, descricao_completa_plaintext, documentos_necessarios_plaintext, instrucoes_solicitante_plaintext) que removem toda formatação markdown. Se tema_geral ficar vazio ou fora da taxonomia, uma sugestão de categoria é gerada em segundo plano. Com dry_run=true valida e retorna o serviço como seria gravado (models.ServiceWritePreview), sem persistir.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param service body models.PrefRioServiceRequest true "Dados do serviço"
+// @Param dry_run query bool false "Apenas simula a criação"
+// @Success 201 {object} models.PrefRioService
+// @Success 200 {object} models.ServiceWritePreview
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/services [post]
+func (h *AdminHandler) CreateService(c *gin.Context) {
+	var request models.PrefRioServiceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	// Valida os dados
+	bairros, regioes, err := h.validateServiceRequest(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validação falhou: " + err.Error()})
+		return
+	}
+
+	// Converte para modelo completo
+	service := newServiceFromRequest(uuid.New().String(), &request, middlewares.GetUserName(c), bairros, regioes)
 
 	// Cria o serviço com rastreamento de versão
-	ctx := context.Background()
+	ctx := writeContext(c)
+	if isDryRun(c) {
+		c.JSON(http.StatusOK, h.typesenseClient.PreviewPrefRioServiceWrite(ctx, models.PreviewOperationCreate, nil, service))
+		return
+	}
 	createdService, err := h.typesenseClient.CreatePrefRioServiceWithVersion(
 		ctx,
 		service,
@@ -99,17 +231,21 @@ func (h *AdminHandler) CreateService(c *gin.Context) {
 		return
 	}
 
+	h.classifyInBackground(c, createdService)
+	h.enrichInBackground(c, createdService)
+
 	c.JSON(http.StatusCreated, createdService)
 }
 
 // UpdateService godoc
 // @Summary Atualiza um serviço existente
-// @Description Atualiza um serviço existente. A resposta inclui campos plaintext gerados automaticamente (resumo_plaintext, resultado_solicitacao_plaintext, descricao_completa_plaintext, documentos_necessarios_plaintext, instrucoes_solicitante_plaintext) que removem toda formatação markdown.
+// @Description Atualiza um serviço existente. A resposta inclui campos plaintext gravados na indexação (resumo_plaintext, resultado_solicitacao_plaintext, descricao_completa_plaintext, documentos_necessarios_plaintext, instrucoes_solicitante_plaintext) que removem toda formatação markdown. Se tema_geral ficar vazio ou fora da taxonomia, uma sugestão de categoria é gerada em segundo plano. Com dry_run=true valida e retorna o que mudaria, inclusive os campos que gerariam novo embedding (models.ServiceWritePreview), sem persistir.
 // @Tags admin
 // @Accept json
 // @Produce json
 // @Param id path string true "ID do serviço"
 // @Param service body models.PrefRioServiceRequest true "Dados atualizados do serviço"
+// @Param dry_run query bool false "Apenas simula a atualização"
 // @Success 200 {object} models.PrefRioService
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
@@ -130,7 +266,8 @@ func (h *AdminHandler) UpdateService(c *gin.Context) {
 	}
 
 	// Valida os dados
-	if err := h.validator.Struct(request); err != nil {
+	bairros, regioes, err := h.validateServiceRequest(&request)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Validação falhou: " + err.Error()})
 		return
 	}
@@ -138,54 +275,19 @@ func (h *AdminHandler) UpdateService(c *gin.Context) {
 	// Nota: Validação de permissões será feita externamente à API
 
 	// Busca o serviço existente para preservar created_at
-	ctx := context.Background()
+	ctx := writeContext(c)
 	existingService, err := h.typesenseClient.GetPrefRioService(ctx, serviceID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Serviço não encontrado"})
 		return
 	}
 
-	// Gerencia slug: se nome mudou, atualiza slug e adiciona antigo ao histórico
-	slug := existingService.Slug
-	slugHistory := existingService.SlugHistory
-	if request.NomeServico != existingService.NomeServico {
-		if slug != "" {
-			slugHistory = append(slugHistory, slug)
-		}
-		slug = utils.GenerateSlug(request.NomeServico, serviceID)
-	}
-
 	// Converte para modelo completo preservando dados existentes
-	service := &models.PrefRioService{
-		ID:                    serviceID,
-		NomeServico:           request.NomeServico,
-		OrgaoGestor:           request.OrgaoGestor,
-		Resumo:                request.Resumo,
-		TempoAtendimento:      request.TempoAtendimento,
-		CustoServico:          request.CustoServico,
-		ResultadoSolicitacao:  request.ResultadoSolicitacao,
-		DescricaoCompleta:     request.DescricaoCompleta,
-		Autor:                 existingService.Autor, // Preserva autor original
-		DocumentosNecessarios: request.DocumentosNecessarios,
-		InstrucoesSolicitante: request.InstrucoesSolicitante,
-		CanaisDigitais:        request.CanaisDigitais,
-		CanaisPresenciais:     request.CanaisPresenciais,
-		ServicoNaoCobre:       request.ServicoNaoCobre,
-		LegislacaoRelacionada: request.LegislacaoRelacionada,
-		TemaGeral:             request.TemaGeral,
-		SubCategoria:          request.SubCategoria,
-		PublicoEspecifico:     request.PublicoEspecifico,
-		FixarDestaque:         request.FixarDestaque,
-		AwaitingApproval:      request.AwaitingApproval,
-		PublishedAt:           request.PublishedAt,
-		IsFree:                request.IsFree,
-		Agents:                request.Agents,
-		ExtraFields:           request.ExtraFields,
-		Status:                request.Status,
-		Buttons:               request.Buttons,
-		CreatedAt:             existingService.CreatedAt, // Preserva data de criação
-		Slug:                  slug,
-		SlugHistory:           slugHistory,
+	service := updatedServiceFromRequest(serviceID, existingService, &request, bairros, regioes)
+
+	if isDryRun(c) {
+		c.JSON(http.StatusOK, h.typesenseClient.PreviewPrefRioServiceWrite(ctx, models.PreviewOperationUpdate, existingService, service))
+		return
 	}
 
 	// Atualiza o serviço com rastreamento de versão
@@ -202,6 +304,9 @@ func (h *AdminHandler) UpdateService(c *gin.Context) {
 		return
 	}
 
+	h.classifyInBackground(c, updatedService)
+	h.enrichInBackground(c, updatedService)
+
 	c.JSON(http.StatusOK, updatedService)
 }
 
@@ -226,7 +331,7 @@ func (h *AdminHandler) DeleteService(c *gin.Context) {
 	}
 
 	// Deleta o serviço com rastreamento de versão
-	ctx := context.Background()
+	ctx := writeContext(c)
 	err := h.typesenseClient.DeletePrefRioServiceWithVersion(
 		ctx,
 		serviceID,
@@ -247,7 +352,7 @@ func (h *AdminHandler) DeleteService(c *gin.Context) {
 
 // GetService godoc
 // @Summary Busca um serviço por ID
-// @Description Busca um serviço específico por ID. A resposta inclui campos plaintext gerados automaticamente (resumo_plaintext, resultado_solicitacao_plaintext, descricao_completa_plaintext, documentos_necessarios_plaintext, instrucoes_solicitante_plaintext) que removem toda formatação markdown.
+// @Description Busca um serviço específico por ID. A resposta inclui campos plaintext gravados na indexação (resumo_plaintext, resultado_solicitacao_plaintext, descricao_completa_plaintext, documentos_necessarios_plaintext, instrucoes_solicitante_plaintext) que removem toda formatação markdown.
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -265,7 +370,7 @@ func (h *AdminHandler) GetService(c *gin.Context) {
 	}
 
 	// Busca o serviço
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	service, err := h.typesenseClient.GetPrefRioService(ctx, serviceID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Serviço não encontrado"})
@@ -277,12 +382,14 @@ func (h *AdminHandler) GetService(c *gin.Context) {
 
 // ListServices godoc
 // @Summary Lista serviços com paginação e filtros
-// @Description Lista serviços com paginação e filtros opcionais. Cada serviço na resposta inclui campos plaintext gerados automaticamente (resumo_plaintext, resultado_solicitacao_plaintext, descricao_completa_plaintext, documentos_necessarios_plaintext, instrucoes_solicitante_plaintext) que removem toda formatação markdown.
+// @Description Lista serviços com paginação e filtros opcionais. Cada serviço na resposta inclui campos plaintext gravados na indexação (resumo_plaintext, resultado_solicitacao_plaintext, descricao_completa_plaintext, documentos_necessarios_plaintext, instrucoes_solicitante_plaintext) que removem toda formatação markdown. Para percorrer a lista inteira, use o next_cursor (ou o link next do header Link): a continuação por cursor não pula nem repete serviços editados entre as requisições. Com q, busca em todos os status (rascunhos inclusive) com tolerância a erros de digitação, ordenada por relevância e isolada dos ajustes da busca pública (curadorias e sinônimos); a busca é paginada por page.
 // @Tags admin
 // @Accept json
 // @Produce json
 // @Param page query int false "Página" default(1)
 // @Param per_page query int false "Resultados por página" default(10)
+// @Param q query string false "Busca textual em nome, resumo, órgão gestor e conteúdo (todos os status, combinável com os filtros)"
+// @Param cursor query string false "Continuação da listagem (next_cursor da resposta anterior); substitui page. Não se aplica com q"
 // @Param status query int false "Status do serviço (0=Draft, 1=Published)"
 // @Param author query string false "Filtrar por autor"
 // @Param tema_geral query string false "Filtrar por tema geral"
@@ -298,16 +405,7 @@ func (h *AdminHandler) GetService(c *gin.Context) {
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/admin/services [get]
 func (h *AdminHandler) ListServices(c *gin.Context) {
-	// Parse de parâmetros de paginação
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		page = 1
-	}
-
-	perPage, err := strconv.Atoi(c.DefaultQuery("per_page", "10"))
-	if err != nil || perPage < 1 || perPage > 100 {
-		perPage = 10
-	}
+	page, perPage := parsePagination(c, 10, 100)
 
 	// Parse de filtros
 	filters := make(map[string]interface{})
@@ -359,31 +457,51 @@ func (h *AdminHandler) ListServices(c *gin.Context) {
 		}
 	}
 
-	// Lista os serviços
-	ctx := context.Background()
-	response, err := h.typesenseClient.ListPrefRioServices(ctx, page, perPage, filters)
+	// Busca (q) ou lista os serviços, pela página ou continuando do cursor
+	ctx := c.Request.Context()
+	var response *models.PrefRioServiceResponse
+	var err error
+	query := strings.TrimSpace(c.Query("q"))
+	if query != "" && c.Query("cursor") != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cursor não se aplica à busca (q); use page"})
+		return
+	}
+	if query != "" {
+		response, err = h.typesenseClient.SearchPrefRioServicesAdmin(ctx, query, page, perPage, filters)
+	} else if cursor := c.Query("cursor"); cursor != "" {
+		response, err = h.typesenseClient.ListPrefRioServicesAfter(ctx, cursor, perPage, filters)
+	} else {
+		response, err = h.typesenseClient.ListPrefRioServices(ctx, page, perPage, filters)
+	}
+	if errors.Is(err, typesense.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar serviços: " + err.Error()})
 		return
 	}
 
+	setPaginationLinks(c, response.Page, response.PageInfo)
 	c.JSON(http.StatusOK, response)
 }
 
 // PublishService godoc
 // @Summary Publica um serviço (altera status para 1 e marca como aprovado)
-// @Description Publica um serviço alterando seu status para 1 e awaiting_approval para false. Opcionalmente, pode criar um tombamento se fornecidos os parâmetros origem e id_servico_antigo
+// @Description Publica um serviço alterando seu status para 1 e awaiting_approval para false. Opcionalmente, pode criar um tombamento se fornecidos os parâmetros origem e id_servico_antigo (recusado com 409 se as collections legadas estiverem congeladas). Com dry_run=true valida e retorna o que mudaria (models.ServiceWritePreview), sem publicar nem criar o tombamento.
 // @Tags admin
 // @Accept json
 // @Produce json
 // @Param id path string true "ID do serviço"
 // @Param origem query string false "Origem do serviço antigo (1746_v2_llm ou carioca-digital_v2_llm) para criar tombamento"
 // @Param id_servico_antigo query string false "ID do serviço antigo para criar tombamento"
+// @Param dry_run query bool false "Apenas simula a publicação"
 // @Param observacoes query string false "Observações sobre o tombamento"
 // @Success 200 {object} models.PrefRioService
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/admin/services/{id}/publish [patch]
 func (h *AdminHandler) PublishService(c *gin.Context) {
@@ -394,7 +512,7 @@ func (h *AdminHandler) PublishService(c *gin.Context) {
 	}
 
 	// Busca o serviço existente
-	ctx := context.Background()
+	ctx := writeContext(c)
 	service, err := h.typesenseClient.GetPrefRioService(ctx, serviceID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Serviço não encontrado"})
@@ -411,6 +529,10 @@ func (h *AdminHandler) PublishService(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Origem deve ser '1746_v2_llm' ou 'carioca-digital_v2_llm'"})
 			return
 		}
+		if h.legacyFrozen {
+			c.JSON(http.StatusConflict, middlewares.LegacyFrozenResponse)
+			return
+		}
 
 		// Verifica se já existe tombamento
 		existingTombamento, _ := h.typesenseClient.GetTombamentoByOldServiceID(ctx, origem, idServicoAntigo)
@@ -431,17 +553,25 @@ func (h *AdminHandler) PublishService(c *gin.Context) {
 			Observacoes:     c.Query("observacoes"),
 		}
 
-		_, err = h.typesenseClient.CreateTombamento(ctx, tombamento)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar tombamento: " + err.Error()})
-			return
+		if !isDryRun(c) {
+			_, err = h.typesenseClient.CreateTombamento(ctx, tombamento)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar tombamento: " + err.Error()})
+				return
+			}
 		}
 	}
 
 	// Atualiza status para publicado e marca como aprovado
+	existing := *service
 	service.Status = 1
 	service.AwaitingApproval = false
 
+	if isDryRun(c) {
+		c.JSON(http.StatusOK, h.typesenseClient.PreviewPrefRioServiceWrite(ctx, models.PreviewOperationPublish, &existing, service))
+		return
+	}
+
 	// Atualiza o serviço com rastreamento de versão
 	updatedService, err := h.typesenseClient.UpdatePrefRioServiceWithVersion(
 		ctx,
@@ -480,7 +610,7 @@ func (h *AdminHandler) UnpublishService(c *gin.Context) {
 	}
 
 	// Busca o serviço existente
-	ctx := context.Background()
+	ctx := writeContext(c)
 	service, err := h.typesenseClient.GetPrefRioService(ctx, serviceID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Serviço não encontrado"})
@@ -507,3 +637,286 @@ func (h *AdminHandler) UnpublishService(c *gin.Context) {
 
 	c.JSON(http.StatusOK, updatedService)
 }
+
+// DeprecateService godoc
+// @Summary Marca um serviço como descontinuado
+// @Description Marca o serviço como descontinuado, opcionalmente indicando o serviço substituto (replaced_by) e a data de sunset (sunset_at, unix). Serviços descontinuados são rebaixados na busca pública e, após o sunset, despublicados automaticamente.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do serviço"
+// @Param request body models.DeprecateServiceRequest false "Substituto e data de sunset"
+// @Success 200 {object} models.PrefRioService
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/services/{id}/deprecate [patch]
+func (h *AdminHandler) DeprecateService(c *gin.Context) {
+	serviceID := c.Param("id")
+	if serviceID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do serviço é obrigatório"})
+		return
+	}
+
+	var request models.DeprecateServiceRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+			return
+		}
+	}
+
+	if request.ReplacedBy == serviceID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Um serviço não pode ser substituto de si mesmo"})
+		return
+	}
+	if request.SunsetAt != nil && *request.SunsetAt <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sunset_at deve ser um timestamp unix válido"})
+		return
+	}
+
+	ctx := writeContext(c)
+	service, err := h.typesenseClient.GetPrefRioService(ctx, serviceID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Serviço não encontrado"})
+		return
+	}
+
+	if request.ReplacedBy != "" {
+		if _, err := h.typesenseClient.GetPrefRioService(ctx, request.ReplacedBy); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Serviço substituto não encontrado: " + request.ReplacedBy})
+			return
+		}
+	}
+
+	service.Deprecated = true
+	service.ReplacedBy = request.ReplacedBy
+	service.SunsetAt = request.SunsetAt
+
+	updatedService, err := h.typesenseClient.UpdatePrefRioServiceWithVersion(
+		ctx,
+		serviceID,
+		service,
+		middlewares.GetUserName(c),
+		middlewares.GetUserCPF(c),
+		"Descontinuação do serviço",
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao descontinuar serviço: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedService)
+}
+
+// UndeprecateService godoc
+// @Summary Remove a descontinuação de um serviço
+// @Description Desfaz a descontinuação, limpando replaced_by e sunset_at
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do serviço"
+// @Success 200 {object} models.PrefRioService
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/services/{id}/undeprecate [patch]
+func (h *AdminHandler) UndeprecateService(c *gin.Context) {
+	serviceID := c.Param("id")
+	if serviceID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID do serviço é obrigatório"})
+		return
+	}
+
+	ctx := writeContext(c)
+	service, err := h.typesenseClient.GetPrefRioService(ctx, serviceID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Serviço não encontrado"})
+		return
+	}
+
+	service.Deprecated = false
+	service.ReplacedBy = ""
+	service.SunsetAt = nil
+
+	updatedService, err := h.typesenseClient.UpdatePrefRioServiceWithVersion(
+		ctx,
+		serviceID,
+		service,
+		middlewares.GetUserName(c),
+		middlewares.GetUserCPF(c),
+		"Remoção da descontinuação do serviço",
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover descontinuação do serviço: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedService)
+}
+
+// BulkUpdateStatus godoc
+// @Summary Altera o status de vários serviços
+// @Description Aplica a mesma transição (publish, unpublish ou archive) a uma lista de serviços, validando cada item e registrando uma versão por serviço alterado. Falhas em um item não interrompem os demais. Com dry_run=true apenas valida e retorna o que seria aplicado. Arquivar despublica o serviço, retira da fila de aprovação e o marca como descontinuado.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body models.BulkStatusRequest true "IDs, ação e dry-run"
+// @Success 200 {object} models.BulkStatusResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/admin/services/bulk-status [post]
+func (h *AdminHandler) BulkUpdateStatus(c *gin.Context) {
+	var request models.BulkStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	result := h.typesenseClient.BulkUpdateStatus(
+		writeContext(c),
+		&request,
+		middlewares.GetUserName(c),
+		middlewares.GetUserCPF(c),
+	)
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListClosingAvailabilityWindows godoc
+// @Summary Lista janelas de disponibilidade prestes a encerrar
+// @Description Lista os serviços sazonais publicados cuja janela de disponibilidade (available_until) encerra nos próximos dias. Ao fim da janela o serviço é despublicado automaticamente e republicado quando uma nova janela abrir.
+// @Tags admin
+// @Produce json
+// @Param days query int false "Prazo em dias (padrão AVAILABILITY_WARNING_DAYS)"
+// @Success 200 {object} models.AvailabilityWarningList
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/services/availability/closing [get]
+func (h *AdminHandler) ListClosingAvailabilityWindows(c *gin.Context) {
+	days := h.availabilityWarningDays
+	if raw := c.Query("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > 365 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days deve ser um número entre 1 e 365"})
+			return
+		}
+		days = parsed
+	}
+
+	warnings, err := h.typesenseClient.ListClosingAvailabilityWindows(c.Request.Context(), time.Duration(days)*24*time.Hour)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar janelas de disponibilidade: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.AvailabilityWarningList{WithinDays: days, Services: warnings})
+}
+
+// RefreshDeepLinks godoc
+// @Summary Regenera os deep links dos serviços
+// @Description Regenera os deep links por canal (portal, app, WhatsApp...) de todos os serviços com a configuração atual de canais e parâmetros UTM (DEEP_LINK_CHANNELS). Não gera novas versões.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.DeepLinkBackfillResult
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/services/deep-links/backfill [post]
+func (h *AdminHandler) RefreshDeepLinks(c *gin.Context) {
+	result, err := h.typesenseClient.RefreshDeepLinks(writeContext(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao regenerar deep links: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RefreshPlaintextFields godoc
+// @Summary Regenera os campos plaintext dos serviços
+// @Description Grava os campos plaintext (textos sem markdown) dos serviços indexados antes deles serem persistidos ou cujos textos mudaram fora da API. Não gera novas versões.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.PlaintextBackfillResult
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/services/plaintext/backfill [post]
+func (h *AdminHandler) RefreshPlaintextFields(c *gin.Context) {
+	result, err := h.typesenseClient.RefreshPlaintextFields(writeContext(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao regenerar campos plaintext: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetServiceForm godoc
+// @Summary Descrição do formulário de serviço
+// @Description Retorna os campos editáveis de um serviço (nome, rótulo, tipo, obrigatoriedade, tamanhos máximos e opções) gerados do modelo e das regras de validação do servidor. As opções de tema e subcategoria vêm da taxonomia e as de bairros da lista canônica.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.ServiceForm
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/admin/meta/service-form [get]
+func (h *AdminHandler) GetServiceForm(c *gin.Context) {
+	taxonomy := map[string][]string{}
+	if h.classifier != nil {
+		taxonomy = h.classifier.Taxonomy(c.Request.Context())
+	}
+
+	var bairros []string
+	if h.neighborhoods != nil {
+		for _, bairro := range h.neighborhoods.List().Bairros {
+			bairros = append(bairros, bairro.Nome)
+		}
+	}
+
+	c.JSON(http.StatusOK, services.BuildServiceForm(taxonomy, bairros))
+}
+
+// isDryRun indica se a escrita deve apenas ser simulada (?dry_run=true)
+func isDryRun(c *gin.Context) bool {
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+	return dryRun
+}
+
+// classifyInBackground gera (ou resolve) a sugestão de categoria do serviço salvo sem atrasar a resposta
+func (h *AdminHandler) classifyInBackground(c *gin.Context, service *models.PrefRioService) {
+	if !h.classifier.Enabled() || service == nil {
+		return
+	}
+
+	ctx := writeContext(c)
+	userName := middlewares.GetUserName(c)
+	saved := *service
+	go h.classifier.HandleServiceSaved(ctx, &saved, userName)
+}
+
+// enrichInBackground extrai as entidades (prazos, valores, documentos, órgãos) do serviço salvo
+// e associa o transporte próximo dos canais presenciais
+func (h *AdminHandler) enrichInBackground(c *gin.Context, service *models.PrefRioService) {
+	if service == nil {
+		return
+	}
+
+	ctx := writeContext(c)
+	saved := *service
+	if h.extractor != nil {
+		go func() {
+			if _, err := h.typesenseClient.EnrichServiceEntities(ctx, &saved, h.extractor); err != nil {
+				log.Printf("[Entidades] %v", err)
+			}
+		}()
+	}
+	if h.transport.Enabled() && len(saved.CanaisPresenciais) > 0 {
+		go func() {
+			if _, _, err := h.typesenseClient.EnrichServiceTransport(ctx, &saved, h.transport); err != nil {
+				log.Printf("[Transporte] %v", err)
+			}
+		}()
+	}
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// AliasHandler expõe os aliases do Typesense para inspeção e correção pelos operadores
+type AliasHandler struct {
+	aliases *services.Aliases
+}
+
+// NewAliasHandler cria um novo handler de aliases
+func NewAliasHandler(aliases *services.Aliases) *AliasHandler {
+	return &AliasHandler{aliases: aliases}
+}
+
+// ListAliases godoc
+// @Summary Lista os aliases do Typesense
+// @Description Retorna os aliases, a collection para a qual cada um aponta e onde a configuração os referencia (in_use_by)
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.AliasList
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/aliases [get]
+func (h *AliasHandler) ListAliases(c *gin.Context) {
+	list, err := h.aliases.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar aliases: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// SaveAlias godoc
+// @Summary Cria ou reaponta um alias
+// @Description Aponta o alias para a collection informada, que deve existir. Reapontar um alias em uso pela configuração responde 409 com um confirmation_token; reenvie a mesma requisição com o token para confirmar. O token deixa de valer se o alias mudar antes da confirmação.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body models.AliasRequest true "Alias e collection de destino"
+// @Success 200 {object} models.CollectionAlias
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/aliases [post]
+func (h *AliasHandler) SaveAlias(c *gin.Context) {
+	var request models.AliasRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	alias, err := h.aliases.Save(writeContext(c), &request, middlewares.GetUserName(c))
+	var confirmation *services.AliasConfirmationError
+	if errors.As(err, &confirmation) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":              err.Error(),
+			"from":               confirmation.From,
+			"to":                 confirmation.To,
+			"confirmation_token": confirmation.Token,
+		})
+		return
+	}
+	if errors.Is(err, services.ErrInvalidAlias) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao salvar alias: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, alias)
+}
+
+// DeleteAlias godoc
+// @Summary Remove um alias
+// @Description Remove o alias; a collection para a qual aponta não é alterada. Aliases em uso pela configuração não podem ser removidos.
+// @Tags admin
+// @Param name path string true "Nome do alias"
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/aliases/{name} [delete]
+func (h *AliasHandler) DeleteAlias(c *gin.Context) {
+	err := h.aliases.Delete(writeContext(c), c.Param("name"), middlewares.GetUserName(c))
+	if errors.Is(err, services.ErrAliasNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrAliasInUse) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover alias: " + err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// AnalyticsExportHandler exporta os eventos de busca anonimizados para a plataforma de dados
+type AnalyticsExportHandler struct {
+	exporter *services.AnalyticsExporter
+}
+
+// NewAnalyticsExportHandler cria um novo handler de exportação de analytics
+func NewAnalyticsExportHandler(exporter *services.AnalyticsExporter) *AnalyticsExportHandler {
+	return &AnalyticsExportHandler{exporter: exporter}
+}
+
+// ExportAnalytics godoc
+// @Summary Exporta os eventos de busca anonimizados
+// @Description Exporta as buscas e cliques do período (dias inclusivos, horário de Brasília) com hash da sessão e da query, tipo de busca, latência, quantidade de resultados e posição clicada, para análise na plataforma de dados. Com query=text inclui o texto da query, mascarado na gravação. Períodos acima do limite de eventos (ou com async=true) são gravados no bucket em segundo plano: a resposta é 202 com a exportação, acompanhada em /admin/analytics/exports/{id}.
+// @Tags admin
+// @Produce json
+// @Produce text/csv
+// @Param from query string true "Primeiro dia (AAAA-MM-DD)"
+// @Param to query string false "Último dia (AAAA-MM-DD)" default(hoje)
+// @Param format query string false "Formato: json (uma linha por evento) ou csv" default(json)
+// @Param query query string false "Query como hash ou text (hash e texto mascarado)" default(hash)
+// @Param async query bool false "Gravar no bucket em segundo plano mesmo abaixo do limite"
+// @Success 200 {array} models.AnalyticsExportRow
+// @Success 202 {object} models.AnalyticsExportJob
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/analytics/export [get]
+func (h *AnalyticsExportHandler) ExportAnalytics(c *gin.Context) {
+	if h.exporter == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Analytics de jornadas desabilitado"})
+		return
+	}
+
+	if c.Query("from") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro from é obrigatório"})
+		return
+	}
+	from, err := services.ParseUsageDay(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from: " + err.Error()})
+		return
+	}
+	to, err := services.ParseUsageDay(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to: " + err.Error()})
+		return
+	}
+
+	req := &models.AnalyticsExportRequest{
+		From:      from.Unix(),
+		To:        to.AddDate(0, 0, 1).Unix(),
+		Format:    c.Query("format"),
+		QueryMode: c.Query("query"),
+	}
+	if err := h.exporter.Validate(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	count, err := h.exporter.Count(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao exportar eventos: " + err.Error()})
+		return
+	}
+
+	if c.Query("async") == "true" || count > h.exporter.MaxEvents() {
+		if !h.exporter.AsyncAvailable() {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error":   services.ErrAnalyticsExportTooLarge.Error(),
+				"details": fmt.Sprintf("%d eventos no período (limite %d); reduza o período ou configure GCS_BUCKET para a exportação assíncrona", count, h.exporter.MaxEvents()),
+			})
+			return
+		}
+		job, err := h.exporter.StartAsync(c.Request.Context(), req, middlewares.GetUserName(c))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao iniciar exportação: " + err.Error()})
+			return
+		}
+		c.Header("Location", "/api/v1/admin/analytics/exports/"+job.ID)
+		c.JSON(http.StatusAccepted, job)
+		return
+	}
+
+	extension := "ndjson"
+	if req.Format == models.AnalyticsExportCSV {
+		extension = "csv"
+	}
+	c.Header("Content-Type", services.AnalyticsExportContentType(req.Format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="analytics-%s.%s"`, from.Format("20060102"), extension))
+	c.Status(http.StatusOK)
+
+	// O status já foi enviado: falhas no meio da transmissão só truncam o arquivo
+	if _, err := h.exporter.Write(c.Request.Context(), c.Writer, req, h.exporter.MaxEvents()); err != nil {
+		log.Printf("[AnalyticsExport] Exportação interrompida: %v", err)
+	}
+}
+
+// GetAnalyticsExport godoc
+// @Summary Consulta uma exportação assíncrona de analytics
+// @Description Retorna o estado da exportação e, quando concluída, a URL de download (válida por uma hora)
+// @Tags admin
+// @Produce json
+// @Param id path string true "ID da exportação"
+// @Success 200 {object} models.AnalyticsExportJob
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/analytics/exports/{id} [get]
+func (h *AnalyticsExportHandler) GetAnalyticsExport(c *gin.Context) {
+	if h.exporter == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Analytics de jornadas desabilitado"})
+		return
+	}
+
+	job, err := h.exporter.Job(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao consultar exportação: " + err.Error()})
+		return
+	}
+	if job == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exportação não encontrada"})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// apiKeyUsageMaxDays período máximo de um relatório de uso
+const apiKeyUsageMaxDays = 90
+
+// APIKeyHandler expõe as API keys dos tenants e o uso de cada uma
+type APIKeyHandler struct {
+	cfg    *config.Config
+	usage  *services.APIKeyUsage
+	quotas *services.APIKeyQuotas
+}
+
+// NewAPIKeyHandler cria um novo handler de API keys
+func NewAPIKeyHandler(cfg *config.Config, usage *services.APIKeyUsage, quotas *services.APIKeyQuotas) *APIKeyHandler {
+	return &APIKeyHandler{cfg: cfg, usage: usage, quotas: quotas}
+}
+
+// ListAPIKeys godoc
+// @Summary Lista as API keys dos tenants
+// @Description Retorna o ID público (usado no relatório de uso) e o tenant de cada API key configurada em TENANT_CONFIGS, sem o valor da key
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.APIKeyList
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/admin/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	list := models.APIKeyList{Keys: []models.APIKeyInfo{}}
+	for tenantID, tenant := range h.cfg.Tenants {
+		for _, key := range tenant.APIKeys {
+			suffix := key
+			if len(suffix) > 4 {
+				suffix = suffix[len(suffix)-4:]
+			}
+			list.Keys = append(list.Keys, models.APIKeyInfo{ID: config.APIKeyID(key), Tenant: tenantID, Suffix: suffix})
+		}
+	}
+	sort.Slice(list.Keys, func(a, b int) bool {
+		if list.Keys[a].Tenant != list.Keys[b].Tenant {
+			return list.Keys[a].Tenant < list.Keys[b].Tenant
+		}
+		return list.Keys[a].ID < list.Keys[b].ID
+	})
+	list.Total = len(list.Keys)
+
+	c.JSON(http.StatusOK, list)
+}
+
+// GetAPIKeyUsage godoc
+// @Summary Uso de uma API key
+// @Description Retorna as chamadas diárias, percentis de latência (p50, p95, p99) e taxas de erro por rota da API key no período, agregados dos eventos registrados pelo middleware de uso. Datas no horário de Brasília.
+// @Tags admin
+// @Produce json
+// @Param id path string true "ID público da API key (ver GET /api/v1/admin/api-keys)"
+// @Param from query string false "Data inicial (AAAA-MM-DD); padrão: 6 dias antes de to"
+// @Param to query string false "Data final, inclusiva (AAAA-MM-DD); padrão: hoje"
+// @Success 200 {object} models.APIKeyUsageReport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/api-keys/{id}/usage [get]
+func (h *APIKeyHandler) GetAPIKeyUsage(c *gin.Context) {
+	if h.usage == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Registro de uso das API keys desabilitado"})
+		return
+	}
+
+	keyID := c.Param("id")
+	tenantID, ok := h.keyTenant(keyID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key não encontrada"})
+		return
+	}
+
+	to, err := services.ParseUsageDay(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to: " + err.Error()})
+		return
+	}
+	from := to.AddDate(0, 0, -6)
+	if raw := c.Query("from"); raw != "" {
+		if from, err = services.ParseUsageDay(raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from: " + err.Error()})
+			return
+		}
+	}
+	if from.After(to) || to.Sub(from) >= apiKeyUsageMaxDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Período inválido: from deve ser anterior a to, com no máximo 90 dias"})
+		return
+	}
+
+	report, err := h.usage.Usage(c.Request.Context(), keyID, tenantID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar relatório de uso: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetAPIKeyQuota godoc
+// @Summary Quota de uma API key
+// @Description Retorna a quota diária vigente da API key (personalizada ou padrão), os aumentos temporários vigentes, o burst tolerado e o consumo do dia (horário de Brasília)
+// @Tags admin
+// @Produce json
+// @Param id path string true "ID público da API key"
+// @Success 200 {object} models.APIKeyQuotaStatus
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/api-keys/{id}/quota [get]
+func (h *APIKeyHandler) GetAPIKeyQuota(c *gin.Context) {
+	keyID, tenantID, ok := h.quotaKey(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.quotas.Status(c.Request.Context(), keyID, tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// SetAPIKeyQuota godoc
+// @Summary Define a quota de uma API key
+// @Description Substitui a quota diária padrão da API key (0 = sem quota) e o burst tolerado acima dela, em percentual da quota. Os aumentos temporários vigentes são mantidos.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "ID público da API key"
+// @Param request body models.APIKeyQuotaRequest true "Quota diária"
+// @Success 200 {object} models.APIKeyQuota
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/api-keys/{id}/quota [put]
+func (h *APIKeyHandler) SetAPIKeyQuota(c *gin.Context) {
+	keyID, _, ok := h.quotaKey(c)
+	if !ok {
+		return
+	}
+
+	var request models.APIKeyQuotaRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	quota, err := h.quotas.SetQuota(writeContext(c), keyID, request, middlewares.GetUserName(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, quota)
+}
+
+// GrantQuotaBoost godoc
+// @Summary Concede aumento temporário de quota
+// @Description Soma chamadas diárias à quota da API key até o vencimento (ex.: durante campanhas). Keys sem quota não são afetadas.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "ID público da API key"
+// @Param request body models.APIKeyQuotaBoostRequest true "Aumento de quota"
+// @Success 201 {object} models.APIKeyQuota
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/api-keys/{id}/quota/boosts [post]
+func (h *APIKeyHandler) GrantQuotaBoost(c *gin.Context) {
+	keyID, _, ok := h.quotaKey(c)
+	if !ok {
+		return
+	}
+
+	var request models.APIKeyQuotaBoostRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	quota, err := h.quotas.GrantBoost(writeContext(c), keyID, request, middlewares.GetUserName(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, quota)
+}
+
+// RevokeQuotaBoost godoc
+// @Summary Encerra aumento temporário de quota
+// @Description Remove o aumento de quota da API key antes do vencimento
+// @Tags admin
+// @Produce json
+// @Param id path string true "ID público da API key"
+// @Param boost_id path string true "ID do aumento"
+// @Success 200 {object} models.APIKeyQuota
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/api-keys/{id}/quota/boosts/{boost_id} [delete]
+func (h *APIKeyHandler) RevokeQuotaBoost(c *gin.Context) {
+	keyID, _, ok := h.quotaKey(c)
+	if !ok {
+		return
+	}
+
+	quota, err := h.quotas.RevokeBoost(writeContext(c), keyID, c.Param("boost_id"), middlewares.GetUserName(c))
+	if errors.Is(err, services.ErrQuotaBoostNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, quota)
+}
+
+// quotaKey valida as quotas habilitadas e a key do parâmetro id; responde e retorna false
+// caso contrário
+func (h *APIKeyHandler) quotaKey(c *gin.Context) (string, string, bool) {
+	if h.quotas == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Quotas de API key desabilitadas"})
+		return "", "", false
+	}
+
+	keyID := c.Param("id")
+	tenantID, ok := h.keyTenant(keyID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key não encontrada"})
+		return "", "", false
+	}
+	return keyID, tenantID, true
+}
+
+// keyTenant retorna o tenant da API key com o ID público informado
+func (h *APIKeyHandler) keyTenant(keyID string) (string, bool) {
+	for tenantID, tenant := range h.cfg.Tenants {
+		for _, key := range tenant.APIKeys {
+			if config.APIKeyID(key) == keyID {
+				return tenantID, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,356 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
+)
+
+// attachmentURLExpiry validade das URLs assinadas de download
+const attachmentURLExpiry = 15 * time.Minute
+
+// allowedAttachmentTypes extensões aceitas e o tipo MIME gravado no bucket
+var allowedAttachmentTypes = map[string]string{
+	".pdf":  "application/pdf",
+	".doc":  "application/msword",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xls":  "application/vnd.ms-excel",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".odt":  "application/vnd.oasis.opendocument.text",
+	".ods":  "application/vnd.oasis.opendocument.spreadsheet",
+	".csv":  "text/csv",
+	".txt":  "text/plain",
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+}
+
+type AttachmentHandler struct {
+	typesenseClient *typesense.Client
+	storage         *services.GCSStorage
+	extractor       *services.AttachmentTextExtractor
+	eventBus        *services.EventBus
+	maxSize         int64
+}
+
+// NewAttachmentHandler cria o handler de anexos. Com storage nil (GCS_BUCKET não
+// configurado) os endpoints respondem 503. O texto dos anexos é extraído em segundo
+// plano e indexado no serviço; a conclusão é publicada no eventBus.
+func NewAttachmentHandler(client *typesense.Client, storage *services.GCSStorage, extractor *services.AttachmentTextExtractor, eventBus *services.EventBus, maxSizeMB int) *AttachmentHandler {
+	return &AttachmentHandler{
+		typesenseClient: client,
+		storage:         storage,
+		extractor:       extractor,
+		eventBus:        eventBus,
+		maxSize:         int64(maxSizeMB) << 20,
+	}
+}
+
+// UploadAttachment godoc
+// @Summary Anexa um arquivo a um serviço
+// @Description Envia um arquivo (formulário, modelo) para o GCS e registra seus metadados no serviço. O nome do arquivo passa a ser pesquisável na busca; o conteúdo de PDFs, imagens e arquivos de texto é extraído em segundo plano (text_status) e também indexado.
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "ID do serviço"
+// @Param file formData file true "Arquivo (pdf, doc, docx, xls, xlsx, odt, ods, csv, txt, png, jpg)"
+// @Success 201 {object} models.Attachment
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/services/{id}/attachments [post]
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+
+	serviceID := c.Param("id")
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Arquivo é obrigatório (campo file)"})
+		return
+	}
+
+	if fileHeader.Size > h.maxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Arquivo excede o tamanho máximo de %d MB", h.maxSize>>20)})
+		return
+	}
+
+	filename := filepath.Base(fileHeader.Filename)
+	mimeType, ok := allowedAttachmentTypes[strings.ToLower(filepath.Ext(filename))]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Tipo de arquivo não permitido: " + filename})
+		return
+	}
+
+	ctx := writeContext(c)
+	service, err := h.typesenseClient.GetPrefRioService(ctx, serviceID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Serviço não encontrado"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao ler arquivo: " + err.Error()})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao ler arquivo: " + err.Error()})
+		return
+	}
+
+	attachment := models.Attachment{
+		ID:         uuid.New().String(),
+		Filename:   filename,
+		MimeType:   mimeType,
+		Size:       fileHeader.Size,
+		UploadedAt: time.Now().Unix(),
+		UploadedBy: middlewares.GetUserName(c),
+	}
+	attachment.ObjectName = fmt.Sprintf("services/%s/%s%s", serviceID, attachment.ID, strings.ToLower(filepath.Ext(filename)))
+	attachment.TextStatus = services.AttachmentTextUnsupported
+	if h.extractor != nil && h.extractor.Supports(mimeType) {
+		attachment.TextStatus = services.AttachmentTextPending
+	}
+
+	if err := h.storage.Upload(ctx, attachment.ObjectName, mimeType, bytes.NewReader(data)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao enviar arquivo: " + err.Error()})
+		return
+	}
+
+	service.Attachments = append(service.Attachments, attachment)
+	if _, err := h.typesenseClient.UpdatePrefRioServiceWithVersion(
+		ctx,
+		serviceID,
+		service,
+		middlewares.GetUserName(c),
+		middlewares.GetUserCPF(c),
+		"Inclusão do anexo "+filename,
+	); err != nil {
+		// Sem metadados o objeto ficaria órfão no bucket
+		h.deleteObject(ctx, attachment.ObjectName)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao registrar anexo: " + err.Error()})
+		return
+	}
+
+	if attachment.TextStatus == services.AttachmentTextPending {
+		go h.indexAttachmentText(ctx, serviceID, attachment, data, middlewares.GetUserName(c), middlewares.GetUserCPF(c))
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// indexAttachmentText extrai o texto do anexo e o grava no serviço, o que regenera o
+// search_content e o embedding com o conteúdo do arquivo
+func (h *AttachmentHandler) indexAttachmentText(ctx context.Context, serviceID string, attachment models.Attachment, data []byte, userName, userCPF string) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	text, err := h.extractor.Extract(ctx, attachment.MimeType, data)
+	status := services.AttachmentTextExtracted
+	if err != nil {
+		log.Printf("Aviso: erro ao extrair texto do anexo %s: %v", attachment.ID, err)
+		status = services.AttachmentTextFailed
+	}
+
+	// Recarrega o serviço: pode ter sido alterado durante a extração
+	service, err := h.typesenseClient.GetPrefRioService(ctx, serviceID)
+	if err != nil {
+		log.Printf("Aviso: serviço %s não encontrado ao indexar anexo: %v", serviceID, err)
+		return
+	}
+	index := findAttachment(service.Attachments, attachment.ID)
+	if index < 0 {
+		return // anexo removido durante a extração
+	}
+	service.Attachments[index].TextStatus = status
+	service.Attachments[index].ExtractedText = text
+
+	if _, err := h.typesenseClient.UpdatePrefRioServiceWithVersion(
+		ctx,
+		serviceID,
+		service,
+		userName,
+		userCPF,
+		"Indexação do texto do anexo "+attachment.Filename,
+	); err != nil {
+		log.Printf("Aviso: erro ao indexar texto do anexo %s: %v", attachment.ID, err)
+		return
+	}
+
+	if h.eventBus != nil {
+		h.eventBus.Publish(ctx, services.DocumentEvent{
+			Type:       services.DocumentUpdated,
+			Collection: services.PrefRioServicesCollection,
+			DocumentID: serviceID,
+		})
+	}
+}
+
+// ListAttachments godoc
+// @Summary Lista os anexos de um serviço
+// @Description Lista os metadados dos arquivos anexados ao serviço
+// @Tags admin
+// @Produce json
+// @Param id path string true "ID do serviço"
+// @Success 200 {array} models.Attachment
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/services/{id}/attachments [get]
+func (h *AttachmentHandler) ListAttachments(c *gin.Context) {
+	service, err := h.typesenseClient.GetPrefRioService(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Serviço não encontrado"})
+		return
+	}
+
+	attachments := service.Attachments
+	if attachments == nil {
+		attachments = []models.Attachment{}
+	}
+	c.JSON(http.StatusOK, attachments)
+}
+
+// DeleteAttachment godoc
+// @Summary Remove um anexo de um serviço
+// @Description Remove os metadados do anexo do serviço e apaga o arquivo do GCS
+// @Tags admin
+// @Produce json
+// @Param id path string true "ID do serviço"
+// @Param attachment_id path string true "ID do anexo"
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/services/{id}/attachments/{attachment_id} [delete]
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+
+	serviceID := c.Param("id")
+	ctx := writeContext(c)
+	service, err := h.typesenseClient.GetPrefRioService(ctx, serviceID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Serviço não encontrado"})
+		return
+	}
+
+	index := findAttachment(service.Attachments, c.Param("attachment_id"))
+	if index < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Anexo não encontrado"})
+		return
+	}
+	removed := service.Attachments[index]
+	service.Attachments = append(service.Attachments[:index], service.Attachments[index+1:]...)
+
+	if _, err := h.typesenseClient.UpdatePrefRioServiceWithVersion(
+		ctx,
+		serviceID,
+		service,
+		middlewares.GetUserName(c),
+		middlewares.GetUserCPF(c),
+		"Remoção do anexo "+removed.Filename,
+	); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover anexo: " + err.Error()})
+		return
+	}
+
+	h.deleteObject(ctx, removed.ObjectName)
+	c.Status(http.StatusNoContent)
+}
+
+// DownloadAttachment godoc
+// @Summary Download de anexo de um serviço
+// @Description Redireciona para uma URL assinada e temporária do arquivo. Com redirect=false, retorna a URL em JSON. Apenas anexos de serviços publicados.
+// @Tags search
+// @Produce json
+// @Param id path string true "ID do serviço"
+// @Param attachment_id path string true "ID do anexo"
+// @Param redirect query bool false "Redirecionar para o arquivo (padrão true)"
+// @Success 200 {object} models.AttachmentDownloadResponse
+// @Success 302
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/search/{id}/attachments/{attachment_id} [get]
+func (h *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+
+	service, err := h.typesenseClient.GetPrefRioService(c.Request.Context(), c.Param("id"))
+	if err != nil || service.Status != 1 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Serviço não encontrado"})
+		return
+	}
+
+	index := findAttachment(service.Attachments, c.Param("attachment_id"))
+	if index < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Anexo não encontrado"})
+		return
+	}
+	attachment := service.Attachments[index]
+
+	signedURL, err := h.storage.SignedURL(attachment.ObjectName, attachment.Filename, attachmentURLExpiry)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar URL de download: " + err.Error()})
+		return
+	}
+
+	if c.Query("redirect") == "false" {
+		c.JSON(http.StatusOK, models.AttachmentDownloadResponse{
+			URL:       signedURL,
+			ExpiresAt: time.Now().Add(attachmentURLExpiry).Unix(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, signedURL)
+}
+
+// available responde 503 quando o armazenamento de anexos não está configurado
+func (h *AttachmentHandler) available(c *gin.Context) bool {
+	if h.storage == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Armazenamento de anexos não configurado"})
+		return false
+	}
+	return true
+}
+
+func (h *AttachmentHandler) deleteObject(ctx context.Context, objectName string) {
+	if err := h.storage.Delete(ctx, objectName); err != nil {
+		log.Printf("Aviso: erro ao apagar anexo %s do GCS: %v", objectName, err)
+	}
+}
+
+// findAttachment retorna o índice do anexo pelo ID, ou -1
+func findAttachment(attachments []models.Attachment, id string) int {
+	for i, attachment := range attachments {
+		if attachment.ID == id {
+			return i
+		}
+	}
+	return -1
+}
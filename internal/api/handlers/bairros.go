@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// BairroHandler gerencia a lista canônica de bairros
+type BairroHandler struct {
+	neighborhoods *services.Neighborhoods
+}
+
+// NewBairroHandler cria um novo handler de bairros
+func NewBairroHandler(neighborhoods *services.Neighborhoods) *BairroHandler {
+	return &BairroHandler{neighborhoods: neighborhoods}
+}
+
+// ListBairros godoc
+// @Summary Lista os bairros da cidade
+// @Description Retorna a lista canônica de bairros com a região administrativa, a zona e os aliases aceitos no filtro bairro da busca
+// @Tags bairros
+// @Produce json
+// @Success 200 {object} models.BairroList
+// @Router /api/v1/bairros [get]
+func (h *BairroHandler) ListBairros(c *gin.Context) {
+	c.JSON(http.StatusOK, h.neighborhoods.List())
+}
+
+// ResolveBairro godoc
+// @Summary Resolve um termo em bairros
+// @Description Retorna os bairros correspondentes a um bairro, alias, região administrativa (ex.: RA Tijuca) ou zona (ex.: zona norte), como aplicados no filtro bairro da busca
+// @Tags bairros
+// @Produce json
+// @Param q query string true "Bairro, alias, região administrativa ou zona"
+// @Success 200 {object} models.BairroResolution
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/bairros/resolve [get]
+func (h *BairroHandler) ResolveBairro(c *gin.Context) {
+	term := strings.TrimSpace(c.Query("q"))
+	if term == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro q é obrigatório"})
+		return
+	}
+
+	resolution, err := h.neighborhoods.Resolve(term)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resolution)
+}
+
+// ImportBairros godoc
+// @Summary Substitui a lista canônica de bairros
+// @Description Grava a lista completa de bairros; bairros ausentes da nova lista são removidos. Nomes e aliases não podem se repetir entre bairros. Serviços já cadastrados mantêm os bairros gravados até a próxima edição.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body models.BairroImportRequest true "Lista de bairros"
+// @Success 200 {object} models.BairroList
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/bairros [put]
+func (h *BairroHandler) ImportBairros(c *gin.Context) {
+	var request models.BairroImportRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	list, err := h.neighborhoods.Import(writeContext(c), request.Bairros)
+	if errors.Is(err, services.ErrInvalidNeighborhood) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao importar bairros: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// resolveBairro resolve o parâmetro bairro da busca na lista canônica. Responde 400 e retorna
+// false se o termo não corresponde a bairro, região ou zona.
+func resolveBairro(c *gin.Context, neighborhoods *services.Neighborhoods, req *models.SearchRequest) bool {
+	if strings.TrimSpace(req.Bairro) == "" {
+		return true
+	}
+
+	resolution, err := neighborhoods.Resolve(req.Bairro)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bairro inválido",
+			"details": err.Error(),
+		})
+		return false
+	}
+	req.ParsedBairros = resolution.Bairros
+	return true
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/prefeitura-rio/app-busca-search/internal/concurrency"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+const (
+	// bulkImportMaxItems limite de serviços por importação
+	bulkImportMaxItems = 500
+	// bulkImportConcurrency serviços gravados em paralelo (cada um gera embedding no Gemini)
+	bulkImportConcurrency = 4
+)
+
+var errBulkImportEmpty = errors.New("nenhum serviço informado")
+
+// BulkImportServices godoc
+// @Summary Importa serviços em lote
+// @Description Cria vários serviços de uma vez a partir de um array JSON ou de NDJSON (um models.PrefRioServiceRequest por linha). Cada item passa pelas mesmas validações da criação individual e tem search_content e embedding gerados; falhas em um item não interrompem os demais e o resultado traz a situação de cada item, na ordem do corpo. Com dry_run=true apenas valida. Limite de 500 serviços por requisição.
+// @Tags admin
+// @Accept json
+// @Accept application/x-ndjson
+// @Produce json
+// @Param services body []models.PrefRioServiceRequest true "Serviços (array JSON ou NDJSON)"
+// @Param dry_run query bool false "Apenas valida os serviços"
+// @Success 200 {object} models.BulkImportResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Router /api/v1/admin/services/bulk [post]
+func (h *AdminHandler) BulkImportServices(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Corpo da requisição excede o limite de %d KB", maxBytesErr.Limit>>10)})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao ler o corpo da requisição: " + err.Error()})
+		return
+	}
+
+	items, err := decodeBulkImportBody(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+	if len(items) > bulkImportMaxItems {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Limite de %d serviços por importação excedido (%d)", bulkImportMaxItems, len(items))})
+		return
+	}
+
+	dryRun := isDryRun(c)
+	userName, userCPF := middlewares.GetUserName(c), middlewares.GetUserCPF(c)
+	result := &models.BulkImportResult{
+		DryRun: dryRun,
+		Total:  len(items),
+		Items:  make([]models.BulkImportItem, len(items)),
+	}
+	created := make([]*models.PrefRioService, len(items))
+
+	// Cada tarefa escreve apenas na própria posição, preservando a ordem do corpo
+	indexes := make([]int, len(items))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	err = concurrency.Run(writeContext(c), indexes, concurrency.Options{Concurrency: bulkImportConcurrency}, func(ctx context.Context, i int) error {
+		result.Items[i], created[i] = h.importService(ctx, i, items[i], dryRun, userName, userCPF)
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Importação interrompida: " + err.Error()})
+		return
+	}
+
+	for i, item := range result.Items {
+		if item.Status == models.BulkItemFailed {
+			result.Failed++
+			continue
+		}
+		result.Created++
+		h.classifyInBackground(c, created[i])
+		h.enrichInBackground(c, created[i])
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// importService valida e cria um serviço da importação em lote
+func (h *AdminHandler) importService(ctx context.Context, index int, raw json.RawMessage, dryRun bool, userName, userCPF string) (models.BulkImportItem, *models.PrefRioService) {
+	item := models.BulkImportItem{Index: index}
+
+	var request models.PrefRioServiceRequest
+	if err := json.Unmarshal(raw, &request); err != nil {
+		item.Status, item.Message = models.BulkItemFailed, "JSON inválido: "+err.Error()
+		return item, nil
+	}
+	item.NomeServico = request.NomeServico
+
+	bairros, regioes, err := h.validateServiceRequest(&request)
+	if err != nil {
+		item.Status, item.Message = models.BulkItemFailed, "Validação falhou: "+err.Error()
+		return item, nil
+	}
+
+	service := newServiceFromRequest(uuid.New().String(), &request, userName, bairros, regioes)
+	item.ID = service.ID
+	if dryRun {
+		item.Status = models.BulkItemWouldApply
+		return item, nil
+	}
+
+	createdService, err := h.typesenseClient.CreatePrefRioServiceWithVersion(ctx, service, userName, userCPF)
+	if err != nil {
+		item.Status, item.Message = models.BulkItemFailed, "Erro ao criar serviço: "+err.Error()
+		return item, nil
+	}
+	item.Status = models.BulkItemApplied
+	return item, createdService
+}
+
+// decodeBulkImportBody separa os itens de um array JSON ou de NDJSON (linhas em branco são
+// ignoradas). Os itens são decodificados um a um, para que um item inválido falhe sozinho.
+func decodeBulkImportBody(body []byte) ([]json.RawMessage, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil, errBulkImportEmpty
+	}
+
+	var items []json.RawMessage
+	if body[0] == '[' {
+		if err := json.Unmarshal(body, &items); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, line := range bytes.Split(body, []byte("\n")) {
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				items = append(items, json.RawMessage(line))
+			}
+		}
+	}
+
+	if len(items) == 0 {
+		return nil, errBulkImportEmpty
+	}
+	return items, nil
+}
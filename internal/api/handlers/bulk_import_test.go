@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"testing"
+)
+
+func TestDecodeBulkImportBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		items   []string
+		wantErr bool
+	}{
+		{"array", `[{"nome_servico":"A"}, {"nome_servico":"B"}]`, []string{`{"nome_servico":"A"}`, `{"nome_servico":"B"}`}, false},
+		{"ndjson", "{\"nome_servico\":\"A\"}\n\n  {\"nome_servico\":\"B\"}\r\n", []string{`{"nome_servico":"A"}`, `{"nome_servico":"B"}`}, false},
+		{"ndjson com linha inválida", "{\"nome_servico\":\"A\"}\n{inválido\n", []string{`{"nome_servico":"A"}`, `{inválido`}, false},
+		{"array inválido", `[{"nome_servico":"A"},`, nil, true},
+		{"vazio", "  \n", nil, true},
+		{"array vazio", `[]`, nil, true},
+	}
+
+	for _, tt := range tests {
+		items, err := decodeBulkImportBody([]byte(tt.body))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: erro = %v, esperado erro = %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if len(items) != len(tt.items) {
+			t.Errorf("%s: %d itens, esperados %d", tt.name, len(items), len(tt.items))
+			continue
+		}
+		for i, item := range items {
+			if string(item) != tt.items[i] {
+				t.Errorf("%s: item %d = %s, esperado %s", tt.name, i, item, tt.items[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// CacheHandler expõe a situação dos caches de busca e o descarte seletivo de entradas
+type CacheHandler struct {
+	inspector *services.CacheInspector
+}
+
+// NewCacheHandler cria um novo handler de caches
+func NewCacheHandler(inspector *services.CacheInspector) *CacheHandler {
+	return &CacheHandler{inspector: inspector}
+}
+
+// GetStats godoc
+// @Summary Situação dos caches de busca
+// @Description Entradas, capacidade, acertos, faltas e taxa de acerto dos caches desta réplica: resultados de busca (search), respostas dos endpoints públicos (response), análises de query (analysis), embeddings (embedding) e traduções (query_translation). Os contadores são zerados ao reiniciar o pod.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.CacheStatsResponse
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/admin/cache/stats [get]
+func (h *CacheHandler) GetStats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.inspector.Stats())
+}
+
+// DeleteKeys godoc
+// @Summary Descarta entradas dos caches por prefixo
+// @Description Descarta as entradas cujo prefixo tem a forma <cache>:<início da chave>. Ex.: search:iptu (buscas cuja query normalizada começa com "iptu"), response:/api/v1/services/ (respostas desse path), analysis: (todas as análises de query), embedding: ou query_translation:en:. Vale para a memória desta réplica e, nas respostas, para o Redis; para descartar tudo em todas as réplicas use /admin/ops/clear-caches.
+// @Tags admin
+// @Produce json
+// @Param prefix query string true "Prefixo no formato <cache>:<início da chave>"
+// @Success 200 {object} models.CacheInvalidationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/cache/keys [delete]
+func (h *CacheHandler) DeleteKeys(c *gin.Context) {
+	prefix := c.Query("prefix")
+	if prefix == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "prefix é obrigatório"})
+		return
+	}
+
+	result, err := h.inspector.Invalidate(writeContext(c), prefix)
+	switch {
+	case errors.Is(err, services.ErrCacheUnknown):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCacheDisabled):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusOK, result)
+	}
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// CatalogHandler gerencia o catálogo A–Z de serviços
+type CatalogHandler struct {
+	catalog *services.ServiceCatalog
+}
+
+// NewCatalogHandler cria um novo handler do catálogo A–Z
+func NewCatalogHandler(catalog *services.ServiceCatalog) *CatalogHandler {
+	return &CatalogHandler{catalog: catalog}
+}
+
+// GetCatalog godoc
+// @Summary Catálogo A–Z de serviços
+// @Description Retorna os serviços publicados cujo nome começa com a letra, em ordem alfabética, e a contagem de serviços de todas as letras para montar o índice A–Z. A letra e a ordenação não consideram acentos (É em E, Ç em C); nomes iniciados por dígito ficam em #. Sem letter, retorna todos os grupos.
+// @Tags services
+// @Produce json
+// @Param letter query string false "Letra do índice (A–Z ou #)" example(A)
+// @Success 200 {object} models.CatalogResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/catalog [get]
+func (h *CatalogHandler) GetCatalog(c *gin.Context) {
+	response, err := h.catalog.Get(c.Request.Context(), c.Query("letter"))
+	if errors.Is(err, services.ErrInvalidCatalogLetter) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao montar catálogo: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
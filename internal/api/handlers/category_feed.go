@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// CategoryFeedHandler expõe o feed RSS de novidades por categoria
+type CategoryFeedHandler struct {
+	feeds *services.CategoryFeedService
+}
+
+// NewCategoryFeedHandler cria um novo handler de feeds por categoria
+func NewCategoryFeedHandler(feeds *services.CategoryFeedService) *CategoryFeedHandler {
+	return &CategoryFeedHandler{feeds: feeds}
+}
+
+// GetCategoryFeed godoc
+// @Summary Feed RSS de novidades de uma categoria
+// @Description Feed RSS 2.0 com os serviços publicados recentemente ou com mudanças em campos públicos (requisitos, custos, canais etc.) na categoria, montado a partir do histórico de versões e sem identificar quem fez a alteração. Serviços despublicados ou que mudaram de categoria deixam o feed. Permite que associações de moradores e outros interessados assinem as mudanças dos serviços que lhes interessam.
+// @Tags categories
+// @Produce xml
+// @Param category path string true "Nome da categoria (tema_geral), ex: Educação"
+// @Success 200 {object} models.RSSFeed
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/categories/{category}/feed.xml [get]
+func (h *CategoryFeedHandler) GetCategoryFeed(c *gin.Context) {
+	feed, err := h.feeds.Build(c.Request.Context(), c.Param("category"), requestURL(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao montar feed: " + err.Error()})
+		return
+	}
+
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao serializar feed: " + err.Error()})
+		return
+	}
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", services.CategoryFeedTTL*60))
+	c.Data(http.StatusOK, "application/rss+xml; charset=utf-8", append([]byte(xml.Header), body...))
+}
+
+// requestURL reconstrói o endereço público da requisição, respeitando o proxy reverso
+func requestURL(c *gin.Context) string {
+	scheme := "http"
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	} else if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host + c.Request.URL.Path
+}
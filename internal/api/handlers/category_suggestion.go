@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
+)
+
+// CategorySuggestionHandler gerencia as sugestões automáticas de categoria dos serviços
+type CategorySuggestionHandler struct {
+	typesenseClient *typesense.Client
+	classifier      *services.CategoryClassifier
+}
+
+// NewCategorySuggestionHandler cria um novo handler de sugestões de categoria
+func NewCategorySuggestionHandler(client *typesense.Client, classifier *services.CategoryClassifier) *CategorySuggestionHandler {
+	return &CategorySuggestionHandler{
+		typesenseClient: client,
+		classifier:      classifier,
+	}
+}
+
+// GetSuggestion godoc
+// @Summary Retorna a sugestão de categoria pendente de um serviço
+// @Description Retorna a sugestão de tema_geral/sub_categoria gerada automaticamente e ainda não aceita nem rejeitada
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do serviço"
+// @Success 200 {object} models.CategorySuggestion
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/services/{id}/category-suggestion [get]
+func (h *CategorySuggestionHandler) GetSuggestion(c *gin.Context) {
+	suggestion, err := h.classifier.GetPendingSuggestion(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar sugestão: " + err.Error()})
+		return
+	}
+	if suggestion == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Nenhuma sugestão pendente para este serviço"})
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestion)
+}
+
+// ClassifyService godoc
+// @Summary Gera uma sugestão de categoria para o serviço
+// @Description Classifica o serviço com o Gemini (validando contra a taxonomia) mesmo que o tema atual seja válido. Substitui a sugestão pendente anterior.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do serviço"
+// @Success 201 {object} models.CategorySuggestion
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/services/{id}/category-suggestion [post]
+func (h *CategorySuggestionHandler) ClassifyService(c *gin.Context) {
+	if !h.classifier.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Classificação automática indisponível"})
+		return
+	}
+
+	ctx := writeContext(c)
+	service, err := h.typesenseClient.GetPrefRioService(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Serviço não encontrado"})
+		return
+	}
+
+	reason := h.classifier.ClassificationReason(ctx, service)
+	if reason == "" {
+		reason = models.ClassificationReasonManual
+	}
+
+	suggestion, err := h.classifier.Classify(ctx, service, reason)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao classificar serviço: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, suggestion)
+}
+
+// AcceptSuggestion godoc
+// @Summary Aceita a sugestão de categoria
+// @Description Aplica o tema_geral/sub_categoria sugeridos ao serviço (gerando nova versão) e registra o aceite
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do serviço"
+// @Success 200 {object} models.PrefRioService
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/services/{id}/category-suggestion/accept [post]
+func (h *CategorySuggestionHandler) AcceptSuggestion(c *gin.Context) {
+	ctx := writeContext(c)
+	service, suggestion, ok := h.loadPending(c)
+	if !ok {
+		return
+	}
+
+	updated, ok := h.applyCategory(c, service, suggestion.SuggestedTema, suggestion.SuggestedSubCategoria, "Categoria sugerida automaticamente aceita")
+	if !ok {
+		return
+	}
+
+	if err := h.classifier.Resolve(ctx, suggestion, models.CategorySuggestionAccepted, suggestion.SuggestedTema, suggestion.SuggestedSubCategoria, middlewares.GetUserName(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao registrar aceite: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// RejectSuggestion godoc
+// @Summary Rejeita a sugestão de categoria
+// @Description Descarta a sugestão. Se tema_geral for informado, ele é aplicado ao serviço e a sugestão é registrada como corrigida (usada para melhorar o prompt).
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do serviço"
+// @Param correction body models.RejectCategorySuggestionRequest false "Categoria correta (opcional)"
+// @Success 200 {object} models.CategorySuggestion
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/services/{id}/category-suggestion/reject [post]
+func (h *CategorySuggestionHandler) RejectSuggestion(c *gin.Context) {
+	var request models.RejectCategorySuggestionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+			return
+		}
+	}
+
+	ctx := writeContext(c)
+	service, suggestion, ok := h.loadPending(c)
+	if !ok {
+		return
+	}
+
+	status := models.CategorySuggestionRejected
+	finalTema := service.TemaGeral
+	finalSub := ""
+	if service.SubCategoria != nil {
+		finalSub = *service.SubCategoria
+	}
+
+	if request.TemaGeral != "" {
+		requestedSub := ""
+		if request.SubCategoria != nil {
+			requestedSub = *request.SubCategoria
+		}
+		tema, sub, valid := h.classifier.ValidateCategory(ctx, request.TemaGeral, requestedSub)
+		if !valid {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "tema_geral não pertence à taxonomia"})
+			return
+		}
+		if request.SubCategoria != nil && requestedSub != "" && sub == "" {
+			sub = requestedSub // subcategoria nova para o tema é aceita como informada
+		}
+		if _, ok := h.applyCategory(c, service, tema, sub, "Categoria corrigida pelo editor"); !ok {
+			return
+		}
+		status = models.CategorySuggestionCorrected
+		finalTema, finalSub = tema, sub
+	}
+
+	if err := h.classifier.Resolve(ctx, suggestion, status, finalTema, finalSub, middlewares.GetUserName(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao registrar rejeição: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestion)
+}
+
+// GetStats godoc
+// @Summary Estatísticas das sugestões de categoria
+// @Description Taxas de aceite e correção das sugestões automáticas, gerais e por versão do prompt, e as correções mais frequentes
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.CategorySuggestionStats
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/reports/category-suggestions [get]
+func (h *CategorySuggestionHandler) GetStats(c *gin.Context) {
+	stats, err := h.classifier.Stats(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao calcular estatísticas: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// loadPending busca o serviço e sua sugestão pendente, respondendo 404 se algum não existir
+func (h *CategorySuggestionHandler) loadPending(c *gin.Context) (*models.PrefRioService, *models.CategorySuggestion, bool) {
+	ctx := writeContext(c)
+	service, err := h.typesenseClient.GetPrefRioService(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Serviço não encontrado"})
+		return nil, nil, false
+	}
+
+	suggestion, err := h.classifier.GetPendingSuggestion(ctx, service.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar sugestão: " + err.Error()})
+		return nil, nil, false
+	}
+	if suggestion == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Nenhuma sugestão pendente para este serviço"})
+		return nil, nil, false
+	}
+
+	return service, suggestion, true
+}
+
+// applyCategory grava tema/subcategoria no serviço com rastreamento de versão
+func (h *CategorySuggestionHandler) applyCategory(c *gin.Context, service *models.PrefRioService, tema, subCategoria, reason string) (*models.PrefRioService, bool) {
+	service.TemaGeral = tema
+	service.SubCategoria = nil
+	if subCategoria != "" {
+		service.SubCategoria = &subCategoria
+	}
+
+	updated, err := h.typesenseClient.UpdatePrefRioServiceWithVersion(
+		writeContext(c),
+		service.ID,
+		service,
+		middlewares.GetUserName(c),
+		middlewares.GetUserCPF(c),
+		reason,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar serviço: " + err.Error()})
+		return nil, false
+	}
+
+	return updated, true
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// Identificação do usuário registrada nas versões geradas pela sincronização com o CMS
+const (
+	cmsSyncUserName = "CMS sync"
+	cmsSyncUserCPF  = "00000000000"
+)
+
+// CMSWebhookHandler aplica em prefrio_services_base as alterações notificadas pelo CMS de
+// origem, com as mesmas validações, embeddings e versões da edição pelo admin
+type CMSWebhookHandler struct {
+	admin  *AdminHandler
+	bus    *services.EventBus
+	secret []byte
+}
+
+// NewCMSWebhookHandler cria o handler. Sem segredo, o endpoint responde 503.
+func NewCMSWebhookHandler(admin *AdminHandler, bus *services.EventBus, secret string) *CMSWebhookHandler {
+	return &CMSWebhookHandler{
+		admin:  admin,
+		bus:    bus,
+		secret: []byte(secret),
+	}
+}
+
+// Receive godoc
+// @Summary Recebe alterações de serviços do CMS de origem
+// @Description Aplica em prefrio_services_base a criação, atualização ou exclusão notificada pelo CMS, com geração de embedding e versão atribuída a "CMS sync". A requisição deve ser assinada: X-CMS-Timestamp (unix, tolerância de 5 minutos) e X-CMS-Signature = sha256=<hex do HMAC-SHA256 de "<timestamp>.<corpo>"> com o segredo CMS_WEBHOOK_SECRET. create e update são idempotentes (criam o serviço se ele não existir e o atualizam se existir); delete de um serviço inexistente é ignorado.
+// @Tags ingest
+// @Accept json
+// @Produce json
+// @Param X-CMS-Timestamp header string true "Unix (segundos) do envio"
+// @Param X-CMS-Signature header string true "sha256=<hex do HMAC-SHA256>"
+// @Param event body models.CMSWebhookEvent true "Notificação do CMS"
+// @Success 200 {object} models.CMSWebhookResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/ingest/cms-webhook [post]
+func (h *CMSWebhookHandler) Receive(c *gin.Context) {
+	if len(h.secret) == 0 {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Webhook do CMS desabilitado (CMS_WEBHOOK_SECRET não configurado)"})
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao ler a requisição: " + err.Error()})
+		return
+	}
+	timestamp := c.GetHeader(services.CMSTimestampHeader)
+	signature := c.GetHeader(services.CMSSignatureHeader)
+	if err := services.VerifyCMSSignature(h.secret, timestamp, signature, body, time.Now()); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Assinatura do CMS rejeitada: " + err.Error()})
+		return
+	}
+
+	var event models.CMSWebhookEvent
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&event); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+	if err := binding.Validator.ValidateStruct(&event); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	if event.Event == models.CMSEventDelete {
+		h.delete(c, event)
+		return
+	}
+	h.upsert(c, event)
+}
+
+// upsert cria o serviço com o ID do CMS ou atualiza o existente
+func (h *CMSWebhookHandler) upsert(c *gin.Context, event models.CMSWebhookEvent) {
+	if event.Service == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "service é obrigatório em " + event.Event})
+		return
+	}
+	bairros, regioes, err := h.admin.validateServiceRequest(event.Service)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validação falhou: " + err.Error()})
+		return
+	}
+
+	ctx := writeContext(c)
+	client := h.admin.typesenseClient
+	result := models.CMSWebhookResult{Event: event.Event, ID: event.ID}
+
+	if existing, err := client.GetPrefRioService(ctx, event.ID); err == nil {
+		service := updatedServiceFromRequest(event.ID, existing, event.Service, bairros, regioes)
+		result.Service, err = client.UpdatePrefRioServiceWithVersion(ctx, event.ID, service, cmsSyncUserName, cmsSyncUserCPF, "Sincronização do CMS")
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar serviço: " + err.Error()})
+			return
+		}
+		result.Action = "updated"
+		h.publish(c, services.DocumentUpdated, event.ID)
+	} else {
+		service := newServiceFromRequest(event.ID, event.Service, cmsSyncUserName, bairros, regioes)
+		result.Service, err = client.CreatePrefRioServiceWithVersion(ctx, service, cmsSyncUserName, cmsSyncUserCPF)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar serviço: " + err.Error()})
+			return
+		}
+		result.Action = "created"
+		h.publish(c, services.DocumentCreated, event.ID)
+	}
+
+	h.admin.enrichInBackground(c, result.Service)
+	c.JSON(http.StatusOK, result)
+}
+
+// delete remove o serviço; serviços já removidos são ignorados
+func (h *CMSWebhookHandler) delete(c *gin.Context, event models.CMSWebhookEvent) {
+	ctx := writeContext(c)
+	client := h.admin.typesenseClient
+	result := models.CMSWebhookResult{Event: event.Event, ID: event.ID, Action: "ignored"}
+
+	if _, err := client.GetPrefRioService(ctx, event.ID); err != nil {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+	if err := client.DeletePrefRioServiceWithVersion(ctx, event.ID, cmsSyncUserName, cmsSyncUserCPF); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao deletar serviço: " + err.Error()})
+		return
+	}
+
+	result.Action = "deleted"
+	h.publish(c, services.DocumentDeleted, event.ID)
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *CMSWebhookHandler) publish(c *gin.Context, eventType services.DocumentEventType, id string) {
+	if h.bus == nil {
+		return
+	}
+	h.bus.Publish(c.Request.Context(), services.DocumentEvent{
+		Type:       eventType,
+		Collection: services.PrefRioServicesCollection,
+		DocumentID: id,
+	})
+}
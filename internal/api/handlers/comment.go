@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
+)
+
+// CommentHandler gerencia os comentários editoriais internos dos serviços
+type CommentHandler struct {
+	typesenseClient *typesense.Client
+	commentService  *services.CommentService
+}
+
+// NewCommentHandler cria um novo handler de comentários
+func NewCommentHandler(client *typesense.Client, commentService *services.CommentService) *CommentHandler {
+	return &CommentHandler{
+		typesenseClient: client,
+		commentService:  commentService,
+	}
+}
+
+// ListComments godoc
+// @Summary Lista os comentários de um serviço
+// @Description Retorna os comentários editoriais internos do serviço organizados em threads (respostas em replies)
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do serviço"
+// @Success 200 {object} models.ServiceCommentList
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/services/{id}/comments [get]
+func (h *CommentHandler) ListComments(c *gin.Context) {
+	serviceID := c.Param("id")
+	if !h.serviceExists(c, serviceID) {
+		return
+	}
+
+	comments, err := h.commentService.List(c.Request.Context(), serviceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar comentários: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, comments)
+}
+
+// CreateComment godoc
+// @Summary Comenta um serviço
+// @Description Registra um comentário interno (ou resposta, com parent_id). Usuários mencionados com @usuario são notificados via webhook
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do serviço"
+// @Param request body models.ServiceCommentRequest true "Comentário"
+// @Success 201 {object} models.ServiceComment
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/services/{id}/comments [post]
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	serviceID := c.Param("id")
+
+	var request models.ServiceCommentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+	if !h.serviceExists(c, serviceID) {
+		return
+	}
+
+	comment, err := h.commentService.Create(writeContext(c), serviceID, &request, middlewares.GetUserName(c), middlewares.GetUserCPF(c))
+	if err != nil {
+		if errors.Is(err, services.ErrCommentNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Comentário pai não encontrado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar comentário: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// UpdateComment godoc
+// @Summary Edita um comentário
+// @Description Edita o texto do comentário (apenas o autor ou administradores). Novas menções são notificadas via webhook
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do serviço"
+// @Param comment_id path string true "ID do comentário"
+// @Param request body models.ServiceCommentRequest true "Novo texto"
+// @Success 200 {object} models.ServiceComment
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/services/{id}/comments/{comment_id} [put]
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	var request models.ServiceCommentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	comment, err := h.commentService.Update(
+		writeContext(c),
+		c.Param("id"),
+		c.Param("comment_id"),
+		request.Body,
+		middlewares.GetUserCPF(c),
+		middlewares.IsAdmin(c),
+	)
+	if err != nil {
+		h.respondError(c, "Erro ao atualizar comentário: ", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// DeleteComment godoc
+// @Summary Remove um comentário
+// @Description Remove o comentário (apenas o autor ou administradores). O registro é mantido sem texto para preservar as respostas
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do serviço"
+// @Param comment_id path string true "ID do comentário"
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/services/{id}/comments/{comment_id} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	err := h.commentService.Delete(
+		writeContext(c),
+		c.Param("id"),
+		c.Param("comment_id"),
+		middlewares.GetUserCPF(c),
+		middlewares.IsAdmin(c),
+	)
+	if err != nil {
+		h.respondError(c, "Erro ao remover comentário: ", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *CommentHandler) serviceExists(c *gin.Context, serviceID string) bool {
+	if _, err := h.typesenseClient.GetPrefRioService(c.Request.Context(), serviceID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Serviço não encontrado"})
+		return false
+	}
+	return true
+}
+
+func (h *CommentHandler) respondError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrCommentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comentário não encontrado"})
+	case errors.Is(err, services.ErrCommentForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message + err.Error()})
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+// ConfigHandler recarrega a configuração sem reiniciar a aplicação
+type ConfigHandler struct {
+	store *config.Store
+}
+
+// NewConfigHandler cria um novo handler de configuração
+func NewConfigHandler(store *config.Store) *ConfigHandler {
+	return &ConfigHandler{store: store}
+}
+
+// ReloadConfig godoc
+// @Summary Recarrega os parâmetros de busca
+// @Description Relê do ambiente e do SEARCH_TUNABLES_FILE os parâmetros ajustáveis da busca (collections, configs das collections, orçamentos, alpha e thresholds padrão, diversidade) e os aplica às buscas seguintes, sem reiniciar os pods. Também disparado por SIGHUP. Com configuração inválida, a atual é mantida.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.ConfigReloadResponse
+// @Failure 401 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /api/v1/admin/config/reload [post]
+func (h *ConfigHandler) ReloadConfig(c *gin.Context) {
+	cfg, err := h.store.Reload()
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Configuração inválida, mantida a atual: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ConfigReloadResponse{
+		ReloadedAt:                h.store.ReloadedAt(),
+		SearchableCollections:     cfg.SearchableCollections,
+		SearchCollectionTimeoutMs: cfg.SearchCollectionTimeoutMs,
+		SearchHybridAlpha:         cfg.HybridAlpha(),
+	})
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// ConsistencyHandler gerencia os endpoints de verificação de consistência entre collections
+type ConsistencyHandler struct {
+	consistencyService *services.ConsistencyService
+}
+
+// NewConsistencyHandler cria um novo handler de consistência
+func NewConsistencyHandler(consistencyService *services.ConsistencyService) *ConsistencyHandler {
+	return &ConsistencyHandler{
+		consistencyService: consistencyService,
+	}
+}
+
+// Check godoc
+// @Summary Verifica a consistência entre collections
+// @Description Detecta versões órfãs, tombamentos apontando para serviços excluídos, entradas do hub_search sem documento de origem e divergências de alias/backup. Não altera dados.
+// @Tags consistency
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.ConsistencyReport
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/consistency [get]
+func (h *ConsistencyHandler) Check(c *gin.Context) {
+	report, err := h.consistencyService.Check(c.Request.Context(), false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao verificar consistência: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// Fix godoc
+// @Summary Verifica e corrige inconsistências entre collections
+// @Description Executa a verificação e remove tombamentos inválidos e entradas órfãs do hub_search. Versões órfãs (o histórico alimenta a reconstrução da collection) e divergências de alias/backup são apenas reportadas.
+// @Tags consistency
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.ConsistencyReport
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/consistency/fix [post]
+func (h *ConsistencyHandler) Fix(c *gin.Context) {
+	report, err := h.consistencyService.Check(c.Request.Context(), true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao corrigir inconsistências: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// ContentGapHandler expõe os temas de demanda que o catálogo não cobre
+type ContentGapHandler struct {
+	contentGaps *services.ContentGapService
+}
+
+// NewContentGapHandler cria um novo handler de lacunas de conteúdo
+func NewContentGapHandler(contentGaps *services.ContentGapService) *ContentGapHandler {
+	return &ContentGapHandler{contentGaps: contentGaps}
+}
+
+// GetLatestReport godoc
+// @Summary Temas de demanda sem cobertura no catálogo
+// @Description Retorna o relatório mais recente das queries sem resultados agrupadas por semelhança semântica (embeddings + k-means), com rótulo e sugestão de conteúdo gerados pelo Gemini, dos temas mais aos menos buscados. O relatório é gerado semanalmente pelo job content-gaps, que também pode ser disparado em /admin/jobs.
+// @Tags analytics
+// @Produce json
+// @Success 200 {object} models.ContentGapReport
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/analytics/content-gaps [get]
+func (h *ContentGapHandler) GetLatestReport(c *gin.Context) {
+	if h.contentGaps == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Relatório de lacunas de conteúdo desabilitado (exige analytics de jornadas e Gemini)"})
+		return
+	}
+
+	report, err := h.contentGaps.GetLatestReport(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar relatório: " + err.Error()})
+		return
+	}
+	if report == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Nenhum relatório gerado ainda"})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
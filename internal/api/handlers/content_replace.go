@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
+)
+
+// ContentHandler gerencia as ferramentas de edição em massa do conteúdo dos serviços
+type ContentHandler struct {
+	typesenseClient *typesense.Client
+}
+
+// NewContentHandler cria um novo handler de edição em massa
+func NewContentHandler(client *typesense.Client) *ContentHandler {
+	return &ContentHandler{typesenseClient: client}
+}
+
+// ReplaceContent godoc
+// @Summary Localiza e substitui texto no conteúdo dos serviços
+// @Description Procura o texto (literal ou expressão regular) nos campos selecionados dos serviços que atendem aos filtros. Sem apply, apenas retorna a pré-visualização com os trechos antes/depois; com apply=true grava os serviços afetados, gerando nova versão e novo embedding somente para eles. Campos aceitos: nome_servico, resumo, tempo_atendimento, custo_servico, resultado_solicitacao, descricao_completa, instrucoes_solicitante, servico_nao_cobre, documentos_necessarios, canais_digitais, canais_presenciais, legislacao_relacionada e buttons.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body models.ContentReplaceRequest true "Texto, substituição, campos e filtros"
+// @Success 200 {object} models.ContentReplaceResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/content/replace [post]
+func (h *ContentHandler) ReplaceContent(c *gin.Context) {
+	var request models.ContentReplaceRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	result, err := h.typesenseClient.ReplaceContent(
+		writeContext(c),
+		&request,
+		middlewares.GetUserName(c),
+		middlewares.GetUserCPF(c),
+	)
+	if err != nil {
+		if errors.Is(err, typesense.ErrInvalidContentReplace) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao substituir conteúdo: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
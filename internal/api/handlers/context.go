@@ -0,0 +1,13 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// writeContext retorna o contexto para operações de escrita e administração: mantém os valores
+// da requisição (ex: tenant), mas não é cancelado se o cliente desconectar no meio da operação
+func writeContext(c *gin.Context) context.Context {
+	return context.WithoutCancel(c.Request.Context())
+}
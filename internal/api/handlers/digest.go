@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// DigestHandler gerencia os endpoints dos resumos enviados aos órgãos gestores
+type DigestHandler struct {
+	digestService *services.DigestService
+}
+
+// NewDigestHandler cria um novo handler de resumos
+func NewDigestHandler(digestService *services.DigestService) *DigestHandler {
+	return &DigestHandler{digestService: digestService}
+}
+
+// GenerateDigests godoc
+// @Summary Gera os resumos dos órgãos gestores
+// @Description Monta os resumos (edições aguardando aprovação, links quebrados e pendências de qualidade) dos órgãos inscritos na frequência. Com deliver=true os resumos são enviados por webhook/e-mail; caso contrário apenas retornados (pré-visualização)
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param frequency query string false "Frequência dos resumos (daily, weekly)" default(weekly)
+// @Param deliver query bool false "Envia os resumos aos órgãos" default(false)
+// @Success 200 {object} models.DigestRun
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/reports/digests [post]
+func (h *DigestHandler) GenerateDigests(c *gin.Context) {
+	frequency := models.DigestFrequency(c.DefaultQuery("frequency", string(models.DigestWeekly)))
+	if frequency != models.DigestDaily && frequency != models.DigestWeekly {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "frequency deve ser daily ou weekly"})
+		return
+	}
+
+	deliver := false
+	if value := c.Query("deliver"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "deliver deve ser true ou false"})
+			return
+		}
+		deliver = parsed
+	}
+
+	run, err := h.digestService.GenerateDigests(writeContext(c), frequency, deliver)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar resumos: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
+)
+
+// DuplicatesHandler gerencia o relatório de serviços duplicados e a mesclagem das duplicatas
+type DuplicatesHandler struct {
+	duplicates      *services.DuplicateReportService
+	typesenseClient *typesense.Client
+	eventBus        *services.EventBus
+	threshold       float64
+}
+
+// NewDuplicatesHandler cria um novo handler de duplicatas
+func NewDuplicatesHandler(duplicates *services.DuplicateReportService, client *typesense.Client, eventBus *services.EventBus, threshold float64) *DuplicatesHandler {
+	return &DuplicatesHandler{
+		duplicates:      duplicates,
+		typesenseClient: client,
+		eventBus:        eventBus,
+		threshold:       threshold,
+	}
+}
+
+// GetLatestReport godoc
+// @Summary Retorna o último relatório de serviços duplicados
+// @Description Retorna os grupos de serviços publicados com embeddings quase idênticos, com o serviço canônico sugerido (o mais antigo do grupo) e os candidatos a tombamento nele, do mais para o menos similar. Cada candidato pode ser mesclado no canônico em POST /admin/services/{id}/merge/{sourceId}.
+// @Tags reports
+// @Produce json
+// @Success 200 {object} models.DuplicateReport
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/reports/duplicates [get]
+func (h *DuplicatesHandler) GetLatestReport(c *gin.Context) {
+	report, err := h.duplicates.GetLatestReport(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar relatório: " + err.Error()})
+		return
+	}
+
+	if report == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Nenhum relatório gerado ainda"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GenerateReport godoc
+// @Summary Gera o relatório de serviços duplicados
+// @Description Executa imediatamente o agrupamento (normalmente executado toda noite): busca os vizinhos mais próximos de cada serviço publicado no índice vetorial e agrupa os pares com similaridade a partir do threshold
+// @Tags reports
+// @Produce json
+// @Param threshold query number false "Similaridade de cosseno mínima entre duplicatas (0-1, padrão DUPLICATE_REPORT_THRESHOLD)"
+// @Success 201 {object} models.DuplicateReport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/reports/duplicates [post]
+func (h *DuplicatesHandler) GenerateReport(c *gin.Context) {
+	threshold := h.threshold
+	if value := c.Query("threshold"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 || parsed >= 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "threshold deve ser um número entre 0 e 1"})
+			return
+		}
+		threshold = parsed
+	}
+
+	report, err := h.duplicates.GenerateReport(c.Request.Context(), threshold, middlewares.GetUserName(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar relatório: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// MergeServices godoc
+// @Summary Mescla um serviço duplicado no serviço de destino
+// @Description Consolida dois serviços duplicados: os campos de conteúdo vazios no destino recebem os valores da origem e a origem é despublicada e descontinuada com replaced_by apontando para o destino, de modo que o slug da origem passa a redirecionar (301) para o destino. O redirecionamento é o do replaced_by; nenhum tombamento é criado (tombamentos mapeiam os serviços das collections legadas). A operação não é atômica: o destino é gravado antes da origem e, se a origem não puder ser gravada, o destino é restaurado por compensação (a mesclagem e a reversão ficam no histórico de versões do destino). Se a restauração também falhar, a resposta 500 informa os dois erros e o destino permanece mesclado. Uma nova versão é registrada em cada serviço gravado. Com dry_run=true apenas devolve a prévia dos dois serviços e dos campos copiados.
+// @Tags admin
+// @Produce json
+// @Param id path string true "ID do serviço de destino"
+// @Param sourceId path string true "ID do serviço de origem (duplicata)"
+// @Param dry_run query bool false "Apenas mostra o resultado da mesclagem"
+// @Success 200 {object} models.ServiceMergeResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/services/{id}/merge/{sourceId} [post]
+func (h *DuplicatesHandler) MergeServices(c *gin.Context) {
+	targetID, sourceID := c.Param("id"), c.Param("sourceId")
+	if targetID == sourceID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Um serviço não pode ser mesclado nele mesmo"})
+		return
+	}
+
+	ctx := writeContext(c)
+	target, err := h.typesenseClient.GetPrefRioService(ctx, targetID)
+	if err != nil {
+		respondServiceLookupError(c, err, "Serviço de destino não encontrado: "+targetID)
+		return
+	}
+	source, err := h.typesenseClient.GetPrefRioService(ctx, sourceID)
+	if err != nil {
+		respondServiceLookupError(c, err, "Serviço de origem não encontrado: "+sourceID)
+		return
+	}
+	if source.ReplacedBy != "" && source.ReplacedBy != targetID {
+		c.JSON(http.StatusConflict, gin.H{"error": "O serviço de origem já foi substituído por " + source.ReplacedBy})
+		return
+	}
+	// Mesclar em um serviço já substituído criaria uma cadeia de redirecionamentos (ou um ciclo,
+	// se o substituto for a própria origem)
+	if target.ReplacedBy == sourceID {
+		c.JSON(http.StatusConflict, gin.H{"error": "O serviço de destino é substituído pela origem; remova a descontinuação antes de mesclar"})
+		return
+	}
+	if target.ReplacedBy != "" {
+		c.JSON(http.StatusConflict, gin.H{"error": "O serviço de destino já foi substituído por " + target.ReplacedBy + "; mescle no serviço substituto"})
+		return
+	}
+
+	// As duas gravações não são atômicas: a cópia original do destino o restaura (com nova
+	// versão) caso a gravação da origem falhe
+	original := *target
+	merged := *target
+	copied := services.MergeMissingFields(&merged, source)
+
+	retired := *source
+	retired.Status = 0
+	retired.Deprecated = true
+	retired.ReplacedBy = targetID
+	retired.SunsetAt = nil
+
+	result := &models.ServiceMergeResult{DryRun: isDryRun(c), CopiedFields: copied, Target: &merged, Source: &retired}
+	if result.DryRun {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+
+	userName, userCPF := middlewares.GetUserName(c), middlewares.GetUserCPF(c)
+	reason := "Mesclagem do serviço " + sourceID
+	if len(copied) > 0 {
+		reason += " (campos copiados: " + strings.Join(copied, ", ") + ")"
+	}
+	result.Target, err = h.typesenseClient.UpdatePrefRioServiceWithVersion(ctx, targetID, &merged, userName, userCPF, reason)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar o serviço de destino: " + err.Error()})
+		return
+	}
+
+	result.Source, err = h.typesenseClient.UpdatePrefRioServiceWithVersion(ctx, sourceID, &retired, userName, userCPF, "Mesclado no serviço "+targetID)
+	if err != nil {
+		if _, restoreErr := h.typesenseClient.UpdatePrefRioServiceWithVersion(ctx, targetID, &original, userName, userCPF, "Reversão da mesclagem do serviço "+sourceID); restoreErr != nil {
+			log.Printf("Erro ao restaurar o serviço %s após falha na mesclagem: %v", targetID, restoreErr)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao despublicar o serviço de origem: " + err.Error() + "; o destino não pôde ser restaurado: " + restoreErr.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao despublicar o serviço de origem (destino restaurado): " + err.Error()})
+		return
+	}
+
+	// O middleware da rota publica o evento do destino
+	if h.eventBus != nil {
+		h.eventBus.Publish(ctx, services.DocumentEvent{
+			Type:       services.DocumentUpdated,
+			Collection: services.PrefRioServicesCollection,
+			DocumentID: sourceID,
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// respondServiceLookupError responde 404 quando o serviço não existe e 500 nas demais falhas da
+// consulta
+func respondServiceLookupError(c *gin.Context, err error, notFound string) {
+	if errors.Is(err, typesense.ErrServiceNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar serviço: " + err.Error()})
+}
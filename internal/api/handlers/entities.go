@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
+)
+
+// EntityHandler gerencia a extração de entidades (órgãos, documentos, prazos e valores) dos serviços
+type EntityHandler struct {
+	typesenseClient *typesense.Client
+	extractor       *services.EntityExtractor
+	eventBus        *services.EventBus
+}
+
+// NewEntityHandler cria um novo handler de entidades
+func NewEntityHandler(client *typesense.Client, extractor *services.EntityExtractor, eventBus *services.EventBus) *EntityHandler {
+	return &EntityHandler{
+		typesenseClient: client,
+		extractor:       extractor,
+		eventBus:        eventBus,
+	}
+}
+
+// ExtractEntities godoc
+// @Summary Extrai as entidades de um serviço
+// @Description Executa imediatamente a extração de entidades (normalmente feita em segundo plano ao salvar) e grava o resultado no serviço
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do serviço"
+// @Success 200 {object} models.ServiceEntities
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/services/{id}/entities [post]
+func (h *EntityHandler) ExtractEntities(c *gin.Context) {
+	ctx := writeContext(c)
+	service, err := h.typesenseClient.GetPrefRioService(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Serviço não encontrado"})
+		return
+	}
+
+	entities, err := h.typesenseClient.EnrichServiceEntities(ctx, service, h.extractor)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao extrair entidades: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, entities)
+}
+
+// BackfillEntities godoc
+// @Summary Extrai as entidades de todos os serviços
+// @Description Inicia em segundo plano a extração para serviços sem entidades ou editados após a última extração (todos, com force=true)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param force query bool false "Reextrai todos os serviços" default(false)
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/admin/services/entities/backfill [post]
+func (h *EntityHandler) BackfillEntities(c *gin.Context) {
+	force := false
+	if value := c.Query("force"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "force deve ser true ou false"})
+			return
+		}
+		force = parsed
+	}
+
+	ctx := writeContext(c)
+	go func() {
+		enriched, err := h.typesenseClient.BackfillServiceEntities(ctx, h.extractor, force)
+		if err != nil {
+			log.Printf("[Entidades] Backfill interrompido: %v", err)
+		}
+		log.Printf("[Entidades] Backfill concluído: %d serviço(s) enriquecido(s)", enriched)
+		h.invalidateCaches(ctx)
+	}()
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Extração de entidades iniciada"})
+}
+
+// invalidateCaches publica um evento sem documento específico, descartando as respostas em cache
+func (h *EntityHandler) invalidateCaches(ctx context.Context) {
+	if h.eventBus == nil {
+		return
+	}
+	h.eventBus.Publish(ctx, services.DocumentEvent{
+		Type:       services.DocumentUpdated,
+		Collection: services.PrefRioServicesCollection,
+	})
+}
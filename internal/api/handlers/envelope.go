@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// EnvelopeVersionHeader header da resposta com a versão do envelope usada
+const EnvelopeVersionHeader = "X-Envelope-Version"
+
+// envelopeMediaType media type versionado aceito em Accept
+// (ex.: application/vnd.prefrio.busca.v2+json)
+var envelopeMediaType = regexp.MustCompile(`application/vnd\.prefrio\.busca\.v(\d+)\+json`)
+
+// envelopes adaptadores de uma resposta para cada versão de envelope servida pelo handler. Uma
+// nova forma de resposta entra como mais um adaptador, sem um novo par handler/serviço: o
+// cliente escolhe a versão pelo parâmetro envelope ou pelo header Accept; sem escolha, recebe a
+// versão padrão.
+type envelopes[T any] struct {
+	defaultVersion string
+	adapters       map[string]func(*T) interface{}
+}
+
+// negotiate retorna a versão pedida pelo cliente. Versões não suportadas respondem 406 com a
+// lista das suportadas.
+func (e envelopes[T]) negotiate(c *gin.Context) (string, bool) {
+	version := c.Query("envelope")
+	if version == "" {
+		if match := envelopeMediaType.FindStringSubmatch(c.GetHeader("Accept")); match != nil {
+			version = match[1]
+		}
+	}
+	if version == "" {
+		return e.defaultVersion, true
+	}
+	if _, ok := e.adapters[version]; !ok {
+		c.JSON(http.StatusNotAcceptable, gin.H{
+			"error":     "Versão de envelope não suportada: " + version,
+			"supported": e.supported(),
+		})
+		return "", false
+	}
+	return version, true
+}
+
+// render responde com o envelope da versão negociada
+func (e envelopes[T]) render(c *gin.Context, status int, version string, response *T) {
+	c.Header(EnvelopeVersionHeader, version)
+	c.Header("Vary", "Accept")
+	c.JSON(status, e.adapters[version](response))
+}
+
+func (e envelopes[T]) supported() []string {
+	versions := make([]string, 0, len(e.adapters))
+	for version := range e.adapters {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// searchEnvelopes envelopes de /api/v1/search: 1 (ServiceDocument, padrão) e 2 (UnifiedDocument)
+var searchEnvelopes = envelopes[models.SearchResponse]{
+	defaultVersion: "1",
+	adapters: map[string]func(*models.SearchResponse) interface{}{
+		"1": func(r *models.SearchResponse) interface{} { return r },
+		"2": func(r *models.SearchResponse) interface{} { return services.UnifiedFromSearchResponse(r) },
+	},
+}
+
+// unifiedSearchEnvelopes envelopes de /api/v2/search: apenas 2 (UnifiedDocument)
+var unifiedSearchEnvelopes = envelopes[models.UnifiedSearchResponse]{
+	defaultVersion: "2",
+	adapters: map[string]func(*models.UnifiedSearchResponse) interface{}{
+		"2": func(r *models.UnifiedSearchResponse) interface{} { return r },
+	},
+}
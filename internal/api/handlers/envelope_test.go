@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestEnvelopesNegotiate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	e := envelopes[string]{
+		defaultVersion: "1",
+		adapters: map[string]func(*string) interface{}{
+			"1": func(s *string) interface{} { return gin.H{"value": *s} },
+			"2": func(s *string) interface{} { return gin.H{"data": gin.H{"value": *s}} },
+		},
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		accept  string
+		version string
+		status  int
+	}{
+		{"padrão", "", "application/json", "1", http.StatusOK},
+		{"parâmetro", "envelope=2", "", "2", http.StatusOK},
+		{"accept", "", "application/vnd.prefrio.busca.v2+json", "2", http.StatusOK},
+		{"parâmetro tem precedência", "envelope=1", "application/vnd.prefrio.busca.v2+json", "1", http.StatusOK},
+		{"não suportada", "envelope=4", "", "", http.StatusNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = httptest.NewRequest("GET", "/api/v1/search?"+tt.query, nil)
+		if tt.accept != "" {
+			c.Request.Header.Set("Accept", tt.accept)
+		}
+
+		version, ok := e.negotiate(c)
+		if ok {
+			value := "iptu"
+			e.render(c, http.StatusOK, version, &value)
+		}
+		if version != tt.version || recorder.Code != tt.status {
+			t.Errorf("%s: esperado versão %q/%d, obtido %q/%d", tt.name, tt.version, tt.status, version, recorder.Code)
+		}
+		if ok && recorder.Header().Get(EnvelopeVersionHeader) != tt.version {
+			t.Errorf("%s: header %s = %q", tt.name, EnvelopeVersionHeader, recorder.Header().Get(EnvelopeVersionHeader))
+		}
+	}
+}
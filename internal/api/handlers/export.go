@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
+	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+// ExportHandler exporta collections completas em streaming
+type ExportHandler struct {
+	typesenseClient *typesense.Client
+	exporter        *services.CollectionExporter
+	// slots limita a quantidade de exports simultâneos
+	slots chan struct{}
+}
+
+// NewExportHandler cria um novo handler de exportação com no máximo maxConcurrent exports simultâneos
+func NewExportHandler(client *typesense.Client, maxConcurrent int) *ExportHandler {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	return &ExportHandler{
+		typesenseClient: client,
+		exporter:        services.NewCollectionExporter(client.GetClient()),
+		slots:           make(chan struct{}, maxConcurrent),
+	}
+}
+
+// ExportServices godoc
+// @Summary Exporta todos os serviços
+// @Description Exporta os serviços da collection prefrio_services_base (sem embedding) em streaming, como array JSON ou JSONL. A resposta é enviada em chunks à medida que os documentos são lidos do Typesense.
+// @Tags admin
+// @Produce json
+// @Produce application/x-ndjson
+// @Param format query string false "Formato da resposta: json (padrão) ou jsonl"
+// @Param status query int false "Filtrar por status (0=rascunho, 1=publicado)"
+// @Param tema_geral query string false "Filtrar por tema geral"
+// @Success 200 {array} models.PrefRioService
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/services/export [get]
+func (h *ExportHandler) ExportServices(c *gin.Context) {
+	format := c.DefaultQuery("format", streamFormatJSON)
+	if format != streamFormatJSON && format != streamFormatJSONL {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Formato inválido. Use json ou jsonl"})
+		return
+	}
+
+	params := &api.ExportDocumentsParams{ExcludeFields: pointer.String("embedding,search_content")}
+	var filters []string
+	if status := c.Query("status"); status != "" {
+		statusInt, err := strconv.Atoi(status)
+		if err != nil || (statusInt != 0 && statusInt != 1) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Status inválido. Use 0 ou 1"})
+			return
+		}
+		filters = append(filters, fmt.Sprintf("status:=%d", statusInt))
+	}
+	if tema := c.Query("tema_geral"); tema != "" {
+		filters = append(filters, fmt.Sprintf("tema_geral:=`%s`", tema))
+	}
+	if filterBy := tenant.ScopeFilter(c.Request.Context(), strings.Join(filters, " && ")); filterBy != "" {
+		params.FilterBy = pointer.String(filterBy)
+	}
+
+	select {
+	case h.slots <- struct{}{}:
+		defer func() { <-h.slots }()
+	default:
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Muitas exportações em andamento. Tente novamente em instantes"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	body, err := h.typesenseClient.GetClient().Collection("prefrio_services_base").Documents().Export(ctx, params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao exportar serviços: " + err.Error()})
+		return
+	}
+	defer body.Close()
+
+	if err := streamJSON(c, format, jsonLines(body)); err != nil {
+		log.Printf("Export de serviços interrompido: %v", err)
+	}
+}
+
+// ExportCollection godoc
+// @Summary Exporta uma collection completa em JSONL
+// @Description Exporta em streaming todos os documentos de qualquer collection (ou alias) do Typesense, um por linha, pela API de export do Typesense. Os campos vetoriais (embeddings) são omitidos, a menos que include_embeddings=true. Usado em backups reproduzíveis fora das collections de backup das migrações; o mesmo export está disponível na ferramenta cmd/export.
+// @Tags admin
+// @Produce application/x-ndjson
+// @Param name path string true "Nome da collection ou alias"
+// @Param include_embeddings query bool false "Inclui os campos vetoriais" default(false)
+// @Param filter_by query string false "Filtro do Typesense (ex.: status:=1)"
+// @Success 200 {string} string "Documentos em JSONL"
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/collections/{name}/export [get]
+func (h *ExportHandler) ExportCollection(c *gin.Context) {
+	select {
+	case h.slots <- struct{}{}:
+		defer func() { <-h.slots }()
+	default:
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Muitas exportações em andamento. Tente novamente em instantes"})
+		return
+	}
+
+	name := c.Param("name")
+	body, err := h.exporter.Export(c.Request.Context(), name, services.CollectionExportOptions{
+		IncludeEmbeddings: c.Query("include_embeddings") == "true",
+		FilterBy:          c.Query("filter_by"),
+	})
+	switch {
+	case errors.Is(err, services.ErrExportCollectionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrExportNotTenantScoped):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer body.Close()
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.jsonl"`, name))
+	if err := streamJSON(c, streamFormatJSONL, jsonLines(body)); err != nil {
+		log.Printf("Export da collection %s interrompido: %v", name, err)
+	}
+}
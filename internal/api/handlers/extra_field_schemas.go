@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// ExtraFieldSchemaHandler gerencia os schemas de extra_fields por tema
+type ExtraFieldSchemaHandler struct {
+	schemas *services.ExtraFieldSchemas
+}
+
+// NewExtraFieldSchemaHandler cria um novo handler de schemas de extra_fields
+func NewExtraFieldSchemaHandler(schemas *services.ExtraFieldSchemas) *ExtraFieldSchemaHandler {
+	return &ExtraFieldSchemaHandler{schemas: schemas}
+}
+
+// ListSchemas godoc
+// @Summary Lista os schemas de extra_fields
+// @Description Retorna os schemas de extra_fields cadastrados por tema, com os campos promovidos a campos indexados
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.ExtraFieldSchemaList
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/admin/extra-fields/schemas [get]
+func (h *ExtraFieldSchemaHandler) ListSchemas(c *gin.Context) {
+	c.JSON(http.StatusOK, h.schemas.List())
+}
+
+// GetSchema godoc
+// @Summary Schema de extra_fields de um tema
+// @Tags admin
+// @Produce json
+// @Param tema path string true "Tema (tema_geral)"
+// @Success 200 {object} models.ExtraFieldSchema
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/admin/extra-fields/schemas/{tema} [get]
+func (h *ExtraFieldSchemaHandler) GetSchema(c *gin.Context) {
+	schema, err := h.schemas.Get(c.Param("tema"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, schema)
+}
+
+// SaveSchema godoc
+// @Summary Cria ou substitui o schema de extra_fields de um tema
+// @Description Define o JSON Schema (type, properties, required, additionalProperties, enum, minLength, maxLength, pattern, minimum, maximum, items, minItems, maxItems) usado para validar o extra_fields dos serviços do tema ao salvar. Serviços já cadastrados não são revalidados. Campos promovidos são indexados como extra_fields.<name> (filtráveis e, com facet, facetáveis) a partir da próxima migração de schema, que pode ser para a versão atual.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param tema path string true "Tema (tema_geral)"
+// @Param request body models.ExtraFieldSchemaRequest true "Schema e campos promovidos"
+// @Success 200 {object} models.ExtraFieldSchema
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/extra-fields/schemas/{tema} [put]
+func (h *ExtraFieldSchemaHandler) SaveSchema(c *gin.Context) {
+	var request models.ExtraFieldSchemaRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	schema, err := h.schemas.Save(writeContext(c), c.Param("tema"), &request, middlewares.GetUserName(c))
+	if errors.Is(err, services.ErrInvalidExtraFieldSchema) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao salvar schema: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, schema)
+}
+
+// DeleteSchema godoc
+// @Summary Remove o schema de extra_fields de um tema
+// @Description Os serviços do tema deixam de ter o extra_fields validado; campos promovidos deixam de ser indexados na próxima migração
+// @Tags admin
+// @Param tema path string true "Tema (tema_geral)"
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/extra-fields/schemas/{tema} [delete]
+func (h *ExtraFieldSchemaHandler) DeleteSchema(c *gin.Context) {
+	err := h.schemas.Delete(writeContext(c), c.Param("tema"))
+	if errors.Is(err, services.ErrExtraFieldSchemaNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover schema: " + err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// FreshnessHandler gerencia os endpoints do relatório de atualização de conteúdo
+type FreshnessHandler struct {
+	freshnessService *services.FreshnessService
+	staleMonths      int
+}
+
+// NewFreshnessHandler cria um novo handler de relatório de atualização
+func NewFreshnessHandler(freshnessService *services.FreshnessService, staleMonths int) *FreshnessHandler {
+	return &FreshnessHandler{
+		freshnessService: freshnessService,
+		staleMonths:      staleMonths,
+	}
+}
+
+// GetLatestReport godoc
+// @Summary Retorna o último relatório de atualização de conteúdo
+// @Description Retorna o relatório mais recente com serviços desatualizados e com legislação vencida
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.FreshnessReport
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/reports/freshness [get]
+func (h *FreshnessHandler) GetLatestReport(c *gin.Context) {
+	report, err := h.freshnessService.GetLatestReport(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar relatório: " + err.Error()})
+		return
+	}
+
+	if report == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Nenhum relatório gerado ainda"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GenerateReport godoc
+// @Summary Gera o relatório de atualização de conteúdo
+// @Description Executa imediatamente a geração do relatório (normalmente executado toda noite) e notifica os responsáveis via webhook
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param stale_months query int false "Meses sem atualização para considerar um serviço desatualizado" default(12)
+// @Success 201 {object} models.FreshnessReport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/reports/freshness [post]
+func (h *FreshnessHandler) GenerateReport(c *gin.Context) {
+	staleMonths := h.staleMonths
+	if value := c.Query("stale_months"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "stale_months deve ser um inteiro positivo"})
+			return
+		}
+		staleMonths = parsed
+	}
+
+	report, err := h.freshnessService.GenerateReport(c.Request.Context(), staleMonths, middlewares.GetUserName(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar relatório: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
@@ -6,18 +6,25 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
 	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
 )
 
 // HealthHandler gerencia os endpoints de health check
 type HealthHandler struct {
 	typesenseClient *typesense.Client
+	searchService   *services.SearchService
+	degradations    *services.DegradationMonitor
 }
 
 // NewHealthHandler cria um novo handler de health check
-func NewHealthHandler(client *typesense.Client) *HealthHandler {
+func NewHealthHandler(client *typesense.Client, searchService *services.SearchService, degradations *services.DegradationMonitor) *HealthHandler {
 	return &HealthHandler{
 		typesenseClient: client,
+		searchService:   searchService,
+		degradations:    degradations,
 	}
 }
 
@@ -27,6 +34,16 @@ type HealthResponse struct {
 	Checks    map[string]string `json:"checks,omitempty"`
 	Error     string            `json:"error,omitempty"`
 	Timestamp int64             `json:"timestamp"`
+	// Contadores de coalescência de buscas idênticas (apenas em /health)
+	SearchCoalescing *services.CoalescingStats `json:"search_coalescing,omitempty"`
+	// Contadores do cache de resultados de busca (apenas em /health, se habilitado)
+	SearchCache *services.SearchCacheStats `json:"search_cache,omitempty"`
+	// Dependências em falha e o comportamento aplicado (apenas em /health)
+	Degradations []models.Degradation `json:"degradations,omitempty"`
+	// Réplica somente leitura das buscas públicas (apenas em /health, se configurada)
+	SearchReplica *models.ReplicaStatus `json:"search_replica,omitempty"`
+	// Requisições abandonadas pelo cliente desde o início do processo (apenas em /health)
+	CanceledRequests int64 `json:"canceled_requests,omitempty"`
 }
 
 // Liveness godoc
@@ -110,8 +127,27 @@ func (h *HealthHandler) Health(c *gin.Context) {
 		response.Error = "Typesense connectivity check failed"
 	}
 
-	// Future: Add more checks here (Gemini API, etc.)
-	// response.Checks["gemini"] = "ok"
+	// Gemini e cache degradados não tornam a aplicação indisponível
+	response.Degradations = h.degradations.Active()
+	response.CanceledRequests = middlewares.CanceledRequests()
+	for _, degradation := range response.Degradations {
+		response.Checks[degradation.Dependency] = "degraded:" + degradation.Action
+	}
+
+	if h.searchService != nil {
+		stats := h.searchService.CoalescingStats()
+		response.SearchCoalescing = &stats
+		response.SearchCache = h.searchService.ResultCacheStats()
+
+		// Com a réplica fora de uso as buscas seguem no primário; não torna a aplicação indisponível
+		if replica := h.searchService.ReplicaStatus(); replica != nil {
+			response.SearchReplica = replica
+			response.Checks["typesense_replica"] = "ok"
+			if !replica.Healthy {
+				response.Checks["typesense_replica"] = "degraded:primary"
+			}
+		}
+	}
 
 	// Return appropriate status code
 	statusCode := http.StatusOK
@@ -122,6 +158,17 @@ func (h *HealthHandler) Health(c *gin.Context) {
 	c.JSON(statusCode, response)
 }
 
+// Degradations godoc
+// @Summary Degradações ativas
+// @Description Retorna a política de degradação por dependência (gemini, typesense, cache) e as falhas ativas nesta réplica com o comportamento aplicado: hybrid_to_keyword (buscas híbridas e com IA passam a textuais), retry_other_node (requisições repetidas nos demais nós do Typesense), bypass (Redis ignorado) ou fail. Respostas de busca degradadas trazem o campo degraded_mode.
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.DegradationStatus
+// @Router /health/degradations [get]
+func (h *HealthHandler) Degradations(c *gin.Context) {
+	c.JSON(http.StatusOK, h.degradations.Status())
+}
+
 // checkTypesense verifica a conectividade com o Typesense
 func (h *HealthHandler) checkTypesense(ctx context.Context) bool {
 	// Tenta verificar a saúde do Typesense usando a API Health
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// IndexStatsHandler expõe as métricas do cluster Typesense para os painéis de operação
+type IndexStatsHandler struct {
+	stats *services.IndexStatsService
+}
+
+// NewIndexStatsHandler cria um novo handler de métricas do índice
+func NewIndexStatsHandler(stats *services.IndexStatsService) *IndexStatsHandler {
+	return &IndexStatsHandler{stats: stats}
+}
+
+// GetStats godoc
+// @Summary Métricas do índice
+// @Description Retorna memória, disco e CPU do nó Typesense (/metrics.json), latência e vazão recentes (/stats.json) e a quantidade de documentos e campos de cada collection, com seus aliases. O Typesense não informa bytes por collection: memória e disco são do nó inteiro. Fontes indisponíveis são listadas em errors. O resultado é reaproveitado por alguns segundos.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.IndexStats
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/index/stats [get]
+func (h *IndexStatsHandler) GetStats(c *gin.Context) {
+	stats, err := h.stats.Stats(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao consultar métricas do índice: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
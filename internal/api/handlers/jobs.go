@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/jobs"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
+)
+
+// JobsHandler expõe as rotinas agendadas: situação, histórico, execução manual e pausa
+type JobsHandler struct {
+	runner *jobs.Runner
+}
+
+// NewJobsHandler cria um novo handler de jobs
+func NewJobsHandler(runner *jobs.Runner) *JobsHandler {
+	return &JobsHandler{runner: runner}
+}
+
+// ListJobs godoc
+// @Summary Lista as rotinas agendadas
+// @Description Retorna os jobs registrados com o agendamento (cron ou @every), a pausa, o próximo horário e a última execução no cluster
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.JobList
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/jobs [get]
+func (h *JobsHandler) ListJobs(c *gin.Context) {
+	list, err := h.runner.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar jobs: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// ListRuns godoc
+// @Summary Histórico de execuções de um job
+// @Description Retorna as últimas execuções do job (situação, duração e erro), das mais recentes para as mais antigas. O histórico é mantido por 30 dias.
+// @Tags admin
+// @Produce json
+// @Param name path string true "Nome do job"
+// @Param limit query int false "Quantidade de execuções (1-250, padrão 50)"
+// @Success 200 {object} models.JobRunList
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/jobs/{name}/runs [get]
+func (h *JobsHandler) ListRuns(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 250 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit deve ser um inteiro entre 1 e 250"})
+		return
+	}
+
+	runs, err := h.runner.Runs(c.Request.Context(), c.Param("name"), limit)
+	if err != nil {
+		h.respondError(c, err, "Erro ao buscar execuções")
+		return
+	}
+	c.JSON(http.StatusOK, runs)
+}
+
+// TriggerJob godoc
+// @Summary Executa um job imediatamente
+// @Description Inicia a execução do job nesta réplica, em segundo plano, inclusive se estiver pausado. Acompanhe o resultado em /runs.
+// @Tags admin
+// @Produce json
+// @Param name path string true "Nome do job"
+// @Success 202 {object} models.JobRun
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/jobs/{name}/run [post]
+func (h *JobsHandler) TriggerJob(c *gin.Context) {
+	run, err := h.runner.Trigger(c.Request.Context(), c.Param("name"), middlewares.GetUserName(c))
+	if err != nil {
+		h.respondError(c, err, "Erro ao executar job")
+		return
+	}
+	c.JSON(http.StatusAccepted, run)
+}
+
+// PauseJob godoc
+// @Summary Pausa um job
+// @Description Suspende as execuções agendadas do job em todas as réplicas até que seja retomado
+// @Tags admin
+// @Produce json
+// @Param name path string true "Nome do job"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/jobs/{name}/pause [post]
+func (h *JobsHandler) PauseJob(c *gin.Context) {
+	h.setPaused(c, true)
+}
+
+// ResumeJob godoc
+// @Summary Retoma um job pausado
+// @Description Volta a executar o job nos horários agendados
+// @Tags admin
+// @Produce json
+// @Param name path string true "Nome do job"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/jobs/{name}/resume [post]
+func (h *JobsHandler) ResumeJob(c *gin.Context) {
+	h.setPaused(c, false)
+}
+
+func (h *JobsHandler) setPaused(c *gin.Context, paused bool) {
+	name := c.Param("name")
+	if err := h.runner.SetPaused(c.Request.Context(), name, paused); err != nil {
+		h.respondError(c, err, "Erro ao atualizar job")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"job": name, "paused": paused})
+}
+
+func (h *JobsHandler) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, jobs.ErrJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job não encontrado"})
+	case errors.Is(err, jobs.ErrJobRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": "Job já está em execução"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message + ": " + err.Error()})
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// SessionIDHeader header com o ID de sessão anônimo gerado pelo cliente
+const SessionIDHeader = "X-Session-ID"
+
+// JourneyHandler registra cliques e expõe as jornadas de busca por sessão anônima
+type JourneyHandler struct {
+	analytics *services.JourneyAnalytics
+}
+
+// NewJourneyHandler cria um novo handler de jornadas
+func NewJourneyHandler(analytics *services.JourneyAnalytics) *JourneyHandler {
+	return &JourneyHandler{analytics: analytics}
+}
+
+// sessionID retorna o ID de sessão anônimo da requisição (header X-Session-ID ou parâmetro
+// session_id)
+func sessionID(c *gin.Context) string {
+	if id := c.GetHeader(SessionIDHeader); id != "" {
+		return id
+	}
+	return c.Query("session_id")
+}
+
+// RecordClick godoc
+// @Summary Registra o clique em um resultado de busca
+// @Description Registra o clique da sessão anônima (ID gerado pelo cliente, sem dados pessoais) em um serviço, encerrando a jornada de buscas da sessão
+// @Tags analytics
+// @Accept json
+// @Produce json
+// @Param request body models.JourneyClickRequest true "Clique"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/analytics/click [post]
+func (h *JourneyHandler) RecordClick(c *gin.Context) {
+	if h.analytics == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Analytics de jornadas desabilitado"})
+		return
+	}
+
+	var req models.JourneyClickRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	if err := h.analytics.RecordClick(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"status": "registrado"})
+}
+
+// GetJourneys godoc
+// @Summary Jornadas de busca por sessão
+// @Description Reconstrói as jornadas das sessões anônimas (buscas consecutivas até o primeiro clique ou 30 minutos de inatividade) e retorna os caminhos de refinamento de query mais comuns
+// @Tags analytics
+// @Produce json
+// @Param days query int false "Período analisado em dias" default(7)
+// @Param limit query int false "Quantidade de caminhos retornados" default(20)
+// @Success 200 {object} models.JourneyReport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/analytics/journeys [get]
+func (h *JourneyHandler) GetJourneys(c *gin.Context) {
+	if h.analytics == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Analytics de jornadas desabilitado"})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if err != nil || days < 1 || days > 90 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days deve ser um inteiro entre 1 e 90"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit deve ser um inteiro entre 1 e 200"})
+		return
+	}
+
+	report, err := h.analytics.Journeys(c.Request.Context(), days, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar jornadas: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// LegacySunsetHandler expõe o andamento da desativação das collections legadas
+type LegacySunsetHandler struct {
+	sunset *services.LegacySunsetService
+}
+
+// NewLegacySunsetHandler cria um novo handler de desativação das collections legadas
+func NewLegacySunsetHandler(sunset *services.LegacySunsetService) *LegacySunsetHandler {
+	return &LegacySunsetHandler{sunset: sunset}
+}
+
+// GetReport godoc
+// @Summary Plano de desativação das collections legadas
+// @Description Mede, por collection legada (1746_v2_llm, carioca-digital_v2_llm), os documentos ainda não tombados e a fração dos cliques em resultados que eles recebem, e gera a lista de tombamento priorizada pelos mais acessados. Sem analytics de jornadas, o tráfego sai zerado. O mesmo relatório é gerado pelo comando legacy-sunset.
+// @Tags tombamentos
+// @Produce json
+// @Param days query int false "Período dos cliques considerados, em dias" default(30)
+// @Param limit query int false "Itens da lista de tombamento (0 = todos)" default(100)
+// @Success 200 {object} models.LegacySunsetReport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/reports/legacy-sunset [get]
+func (h *LegacySunsetHandler) GetReport(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > 90 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days deve ser um inteiro entre 1 e 90"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit deve ser um inteiro maior ou igual a 0"})
+		return
+	}
+
+	report, err := h.sunset.Report(c.Request.Context(), days, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar relatório: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// MarkdownPreviewHandler renderiza os campos markdown dos serviços como os portais exibem
+type MarkdownPreviewHandler struct {
+	previewer *services.MarkdownPreviewer
+}
+
+// NewMarkdownPreviewHandler cria um novo handler de pré-visualização de markdown
+func NewMarkdownPreviewHandler(previewer *services.MarkdownPreviewer) *MarkdownPreviewHandler {
+	return &MarkdownPreviewHandler{previewer: previewer}
+}
+
+// PreviewMarkdown godoc
+// @Summary Pré-visualiza campos markdown de um serviço
+// @Description Renderiza os campos markdown (resumo, descricao_completa, instrucoes_solicitante etc.) com o mesmo sanitizador e renderizador usados pelos portais e retorna o HTML de cada campo com avisos: conteúdo removido pelo sanitizador, links quebrados, formatação não fechada e uso indevido de títulos. Com check_links os links externos também são verificados.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body models.MarkdownPreviewRequest true "Campos a renderizar"
+// @Success 200 {object} models.MarkdownPreviewResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/admin/preview/markdown [post]
+func (h *MarkdownPreviewHandler) PreviewMarkdown(c *gin.Context) {
+	var request models.MarkdownPreviewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	response, err := h.previewer.Preview(c.Request.Context(), &request)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownMarkdownField) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao renderizar markdown: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
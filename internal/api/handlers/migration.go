@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -139,15 +138,7 @@ func (h *MigrationHandler) Rollback(c *gin.Context) {
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/admin/migration/history [get]
 func (h *MigrationHandler) GetHistory(c *gin.Context) {
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		page = 1
-	}
-
-	perPage, err := strconv.Atoi(c.DefaultQuery("per_page", "10"))
-	if err != nil || perPage < 1 || perPage > 100 {
-		perPage = 10
-	}
+	page, perPage := parsePagination(c, 10, 100)
 
 	response, err := h.migrationService.GetHistory(c.Request.Context(), page, perPage)
 	if err != nil {
@@ -155,6 +146,7 @@ func (h *MigrationHandler) GetHistory(c *gin.Context) {
 		return
 	}
 
+	setPaginationLinks(c, page, response.PageInfo)
 	c.JSON(http.StatusOK, response)
 }
 
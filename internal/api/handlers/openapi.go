@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/swaggo/swag"
+)
+
+// OpenAPIHandler expõe a especificação da API gerada a partir das anotações dos handlers
+type OpenAPIHandler struct {
+	once sync.Once
+	spec []byte
+	err  error
+}
+
+// NewOpenAPIHandler cria um novo handler da especificação OpenAPI
+func NewOpenAPIHandler() *OpenAPIHandler {
+	return &OpenAPIHandler{}
+}
+
+// GetSpec godoc
+// @Summary Especificação OpenAPI da API
+// @Description Retorna a especificação (Swagger 2.0) gerada a partir das anotações dos handlers no build. Use para gerar clientes tipados (ex: just ts-client).
+// @Tags docs
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/openapi.json [get]
+func (h *OpenAPIHandler) GetSpec(c *gin.Context) {
+	h.once.Do(h.load)
+
+	if h.err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Especificação OpenAPI indisponível", "details": h.err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.Data(http.StatusOK, "application/json; charset=utf-8", h.spec)
+}
+
+// load lê a especificação registrada pelo pacote docs (gerado pelo swag) e adiciona
+// a definição de segurança JWT usada pelas rotas /admin
+func (h *OpenAPIHandler) load() {
+	raw, err := swag.ReadDoc()
+	if err != nil {
+		h.err = err
+		log.Printf("Aviso: especificação OpenAPI não registrada: %v", err)
+		return
+	}
+
+	var spec map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &spec); err != nil {
+		h.err = err
+		return
+	}
+
+	spec["securityDefinitions"] = map[string]interface{}{
+		"bearerAuth": map[string]interface{}{
+			"type": "apiKey",
+			"name": "Authorization",
+			"in":   "header",
+		},
+	}
+
+	h.spec, h.err = json.Marshal(spec)
+}
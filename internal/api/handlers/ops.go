@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/jobs"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// OpsHandler expõe as ações de recuperação do plantão, sem necessidade de acesso ao cluster
+type OpsHandler struct {
+	ops *services.OpsService
+}
+
+// NewOpsHandler cria um novo handler de operações
+func NewOpsHandler(ops *services.OpsService) *OpsHandler {
+	return &OpsHandler{ops: ops}
+}
+
+// ExecuteAction godoc
+// @Summary Executa uma ação de recuperação
+// @Description Ações: recreate-collection (recria vazia a collection de serviços ausente com o schema registrado da versão atual; target = alias ou collection, padrão prefrio_services_base), restore-backup-alias (aponta o alias prefrio_services_base para o backup mais recente e limpa os caches), clear-caches (descarta os caches de busca e de respostas em todas as réplicas) e restart-job (cancela a execução do job em andamento nesta réplica e o executa novamente; target = nome do job). Sem confirmation_token a ação não é executada: a resposta 409 descreve o que seria feito e traz o token; reenvie a mesma requisição com o token para confirmar. O token deixa de valer se o estado mudar antes da confirmação. Toda execução é registrada em /admin/ops/audit.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param action path string true "Ação" Enums(recreate-collection, restore-backup-alias, clear-caches, restart-job)
+// @Param request body models.OpsRequest false "Alvo e token de confirmação"
+// @Success 200 {object} models.OpsResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} models.OpsPlan
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/ops/{action} [post]
+func (h *OpsHandler) ExecuteAction(c *gin.Context) {
+	var request models.OpsRequest
+	if err := c.ShouldBindJSON(&request); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	result, err := h.ops.Execute(writeContext(c), models.OpsAction(c.Param("action")), &request, middlewares.GetUserName(c))
+	var confirmation *services.OpsConfirmationError
+	switch {
+	case errors.As(err, &confirmation):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":              err.Error(),
+			"action":             confirmation.Plan.Action,
+			"target":             confirmation.Plan.Target,
+			"description":        confirmation.Plan.Description,
+			"confirmation_token": confirmation.Plan.ConfirmationToken,
+		})
+	case errors.Is(err, services.ErrOpsUnknownAction):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrOpsInvalidTarget):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrOpsNotNeeded), errors.Is(err, services.ErrOpsMigrationActive), errors.Is(err, jobs.ErrJobRunning), errors.Is(err, jobs.ErrJobStuck):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao executar ação: " + err.Error()})
+	default:
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// ListAudit godoc
+// @Summary Auditoria das ações de recuperação
+// @Description Retorna as últimas ações executadas em /admin/ops (autor, alvo, resultado e erro), das mais recentes para as mais antigas
+// @Tags admin
+// @Produce json
+// @Param limit query int false "Quantidade de registros (1-250, padrão 50)"
+// @Success 200 {object} models.OpsAuditList
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/ops/audit [get]
+func (h *OpsHandler) ListAudit(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 250 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit deve ser um inteiro entre 1 e 250"})
+		return
+	}
+
+	list, err := h.ops.Audit(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar auditoria: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
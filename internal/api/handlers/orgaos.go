@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// OrgaoHandler gerencia o diretório de órgãos
+type OrgaoHandler struct {
+	orgaos *services.Orgaos
+}
+
+// NewOrgaoHandler cria um novo handler do diretório de órgãos
+func NewOrgaoHandler(orgaos *services.Orgaos) *OrgaoHandler {
+	return &OrgaoHandler{orgaos: orgaos}
+}
+
+// ListOrgaos godoc
+// @Summary Lista os órgãos da prefeitura
+// @Description Retorna o diretório de órgãos com sigla, nome, site e contato, ordenado por sigla
+// @Tags orgaos
+// @Produce json
+// @Success 200 {object} models.OrgaoList
+// @Router /api/v1/orgaos [get]
+func (h *OrgaoHandler) ListOrgaos(c *gin.Context) {
+	c.JSON(http.StatusOK, h.orgaos.List())
+}
+
+// GetOrgaoServices godoc
+// @Summary Lista os serviços publicados de um órgão
+// @Description Retorna os serviços publicados cujo orgao_gestor é a sigla ou o nome do órgão, dos mais recentemente atualizados aos mais antigos
+// @Tags orgaos
+// @Produce json
+// @Param sigla path string true "Sigla do órgão" example(SMS)
+// @Param page query int false "Página" default(1)
+// @Param per_page query int false "Resultados por página" default(20)
+// @Success 200 {object} models.OrgaoServicesResponse
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/orgaos/{sigla}/services [get]
+func (h *OrgaoHandler) GetOrgaoServices(c *gin.Context) {
+	page, perPage := parsePagination(c, 20, 100)
+
+	response, err := h.orgaos.Services(c.Request.Context(), c.Param("sigla"), page, perPage)
+	if errors.Is(err, services.ErrOrgaoNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	setPaginationLinks(c, page, response.PageInfo)
+	c.JSON(http.StatusOK, response)
+}
+
+// SaveOrgao godoc
+// @Summary Cria ou substitui um órgão
+// @Description Grava o órgão da sigla. Com o diretório preenchido, o orgao_gestor dos serviços passa a ser validado ao salvar contra a sigla ou o nome dos órgãos (sem considerar acentos nem maiúsculas). Serviços já cadastrados não são revalidados.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param sigla path string true "Sigla do órgão" example(SMS)
+// @Param request body models.OrgaoRequest true "Dados do órgão"
+// @Success 200 {object} models.Orgao
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/orgaos/{sigla} [put]
+func (h *OrgaoHandler) SaveOrgao(c *gin.Context) {
+	var request models.OrgaoRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	orgao, err := h.orgaos.Save(writeContext(c), c.Param("sigla"), &request, middlewares.GetUserName(c))
+	if errors.Is(err, services.ErrInvalidOrgao) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao salvar órgão: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, orgao)
+}
+
+// DeleteOrgao godoc
+// @Summary Remove um órgão
+// @Description Serviços que citam o órgão em orgao_gestor não são alterados, mas falham na validação ao serem salvos
+// @Tags admin
+// @Param sigla path string true "Sigla do órgão"
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/orgaos/{sigla} [delete]
+func (h *OrgaoHandler) DeleteOrgao(c *gin.Context) {
+	err := h.orgaos.Delete(writeContext(c), c.Param("sigla"))
+	if errors.Is(err, services.ErrOrgaoNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao remover órgão: " + err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
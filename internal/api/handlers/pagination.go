@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+// parsePagination lê page e per_page da query; valores inválidos usam a primeira página e
+// defaultPerPage
+func parsePagination(c *gin.Context, defaultPerPage, maxPerPage int) (page, perPage int) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	perPage, err = strconv.Atoi(c.DefaultQuery("per_page", strconv.Itoa(defaultPerPage)))
+	if err != nil || perPage < 1 || perPage > maxPerPage {
+		perPage = defaultPerPage
+	}
+	return page, perPage
+}
+
+// setPaginationLinks escreve o header Link (RFC 5988) da página atual, preservando os demais
+// parâmetros da query. Com next_cursor, o link next usa o cursor em vez da página.
+func setPaginationLinks(c *gin.Context, page int, info models.PageInfo) {
+	link := func(rel string, params map[string]string) string {
+		query := c.Request.URL.Query()
+		query.Del("cursor")
+		for key, value := range params {
+			query.Set(key, value)
+		}
+		if params["cursor"] != "" {
+			query.Del("page")
+		}
+		return fmt.Sprintf(`<%s?%s>; rel="%s"`, c.Request.URL.Path, query.Encode(), rel)
+	}
+	pageParam := func(p int) map[string]string { return map[string]string{"page": strconv.Itoa(p)} }
+
+	// Na continuação por cursor o total de páginas conta só o restante da listagem
+	byCursor := c.Query("cursor") != ""
+
+	var links []string
+	if info.TotalPages > 0 || byCursor {
+		links = append(links, link("first", pageParam(1)))
+	}
+	if info.HasPrev && !byCursor {
+		links = append(links, link("prev", pageParam(min(page-1, max(info.TotalPages, 1)))))
+	}
+	if info.NextCursor != "" {
+		links = append(links, link("next", map[string]string{"cursor": info.NextCursor}))
+	} else if info.HasNext {
+		links = append(links, link("next", pageParam(page+1)))
+	}
+	if info.TotalPages > 0 && !byCursor {
+		links = append(links, link("last", pageParam(info.TotalPages)))
+	}
+
+	if len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestParsePagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		query   string
+		page    int
+		perPage int
+	}{
+		{"", 1, 10},
+		{"page=3&per_page=50", 3, 50},
+		{"page=0&per_page=0", 1, 10},
+		{"page=abc&per_page=500", 1, 10},
+	}
+
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/api/v1/admin/services?"+tt.query, nil)
+
+		page, perPage := parsePagination(c, 10, 100)
+		if page != tt.page || perPage != tt.perPage {
+			t.Errorf("query %q: esperado %d/%d, obtido %d/%d", tt.query, tt.page, tt.perPage, page, perPage)
+		}
+	}
+}
+
+func TestSetPaginationLinks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		target   string
+		page     int
+		info     models.PageInfo
+		expected string
+	}{
+		{"página do meio", "/items?q=iptu&page=2&per_page=10", 2, models.NewPageInfo(2, 10, 45),
+			`</items?page=1&per_page=10&q=iptu>; rel="first", </items?page=1&per_page=10&q=iptu>; rel="prev", ` +
+				`</items?page=3&per_page=10&q=iptu>; rel="next", </items?page=5&per_page=10&q=iptu>; rel="last"`},
+		{"página única", "/items", 1, models.NewPageInfo(1, 10, 3),
+			`</items?page=1>; rel="first", </items?page=1>; rel="last"`},
+		{"next por cursor", "/items?page=1", 1, models.PageInfo{TotalPages: 3, HasNext: true, NextCursor: "abc"},
+			`</items?page=1>; rel="first", </items?cursor=abc>; rel="next", </items?page=3>; rel="last"`},
+		{"continuação por cursor", "/items?cursor=abc", 1, models.PageInfo{TotalPages: 1, HasPrev: true},
+			`</items?page=1>; rel="first"`},
+		{"página além do fim", "/items?page=9", 9, models.NewPageInfo(9, 10, 15),
+			`</items?page=1>; rel="first", </items?page=2>; rel="prev", </items?page=2>; rel="last"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", tt.target, nil)
+
+			setPaginationLinks(c, tt.page, tt.info)
+			if got := w.Header().Get("Link"); got != tt.expected {
+				t.Errorf("esperado %q, obtido %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// PrivacyHandler gerencia as solicitações do titular de dados (LGPD)
+type PrivacyHandler struct {
+	privacyService *services.PrivacyService
+}
+
+// NewPrivacyHandler cria um novo handler de solicitações do titular
+func NewPrivacyHandler(privacyService *services.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{privacyService: privacyService}
+}
+
+// ExportData godoc
+// @Summary Exporta os dados vinculados a um CPF
+// @Description Reúne todos os registros vinculados ao CPF do titular em todas as collections e gera um comprovante auditável. Administradores podem informar o CPF no corpo; os demais usuários exportam os próprios dados
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Param request body models.PrivacyRequest false "CPF do titular (apenas administradores)"
+// @Success 200 {object} models.PrivacyExport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/privacy/export [post]
+func (h *PrivacyHandler) ExportData(c *gin.Context) {
+	cpf, ok := h.subjectCPF(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.privacyService.Export(writeContext(c), cpf, middlewares.GetUserName(c))
+	if err != nil {
+		h.respondError(c, "Erro ao exportar dados: ", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+// DeleteData godoc
+// @Summary Elimina os dados vinculados a um CPF
+// @Description Remove os registros vinculados ao CPF do titular (buscas salvas, cliques, sessões) e anonimiza os que precisam ser mantidos como histórico (versões, migrações). Retorna o comprovante auditável da eliminação
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Param request body models.PrivacyRequest false "CPF do titular (apenas administradores)"
+// @Success 200 {object} models.PrivacyReceipt
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/privacy/delete [post]
+func (h *PrivacyHandler) DeleteData(c *gin.Context) {
+	cpf, ok := h.subjectCPF(c)
+	if !ok {
+		return
+	}
+
+	receipt, err := h.privacyService.Delete(writeContext(c), cpf, middlewares.GetUserName(c))
+	if err != nil {
+		h.respondError(c, "Erro ao eliminar dados: ", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, receipt)
+}
+
+// GetReceipt godoc
+// @Summary Retorna um comprovante de solicitação do titular
+// @Description Retorna o comprovante de exportação ou eliminação. Usuários não administradores só acessam os próprios comprovantes
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do comprovante"
+// @Success 200 {object} models.PrivacyReceipt
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/privacy/receipts/{id} [get]
+func (h *PrivacyHandler) GetReceipt(c *gin.Context) {
+	receipt, err := h.privacyService.GetReceipt(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar comprovante: " + err.Error()})
+		return
+	}
+
+	// Para não revelar a existência de comprovantes de terceiros, responde 404
+	if receipt == nil || (!middlewares.IsAdmin(c) && !h.ownsReceipt(c, receipt)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comprovante não encontrado"})
+		return
+	}
+
+	c.JSON(http.StatusOK, receipt)
+}
+
+// subjectCPF resolve o CPF do titular: o informado no corpo (apenas administradores) ou o
+// do próprio usuário autenticado
+func (h *PrivacyHandler) subjectCPF(c *gin.Context) (string, bool) {
+	var req models.PrivacyRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+			return "", false
+		}
+	}
+
+	userCPF := middlewares.GetUserCPF(c)
+	if req.CPF == "" {
+		if userCPF == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "CPF do titular não identificado"})
+			return "", false
+		}
+		return userCPF, true
+	}
+
+	if !middlewares.IsAdmin(c) && !sameCPF(req.CPF, userCPF) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Acesso negado: você só pode acessar seus próprios dados"})
+		return "", false
+	}
+
+	return req.CPF, true
+}
+
+func (h *PrivacyHandler) ownsReceipt(c *gin.Context, receipt *models.PrivacyReceipt) bool {
+	cpf, err := services.NormalizeCPF(middlewares.GetUserCPF(c))
+	return err == nil && h.privacyService.SubjectHash(cpf) == receipt.SubjectHash
+}
+
+func (h *PrivacyHandler) respondError(c *gin.Context, message string, err error) {
+	if errors.Is(err, services.ErrInvalidCPF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message + err.Error()})
+}
+
+func sameCPF(a, b string) bool {
+	normalizedA, errA := services.NormalizeCPF(a)
+	normalizedB, errB := services.NormalizeCPF(b)
+	return errA == nil && errB == nil && normalizedA == normalizedB
+}
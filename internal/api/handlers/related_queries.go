@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// RelatedQueriesHandler expõe as sugestões de queries relacionadas
+type RelatedQueriesHandler struct {
+	related *services.RelatedQueries
+}
+
+// NewRelatedQueriesHandler cria um novo handler de queries relacionadas
+func NewRelatedQueriesHandler(related *services.RelatedQueries) *RelatedQueriesHandler {
+	return &RelatedQueriesHandler{related: related}
+}
+
+// GetRelatedQueries godoc
+// @Summary Queries relacionadas ("quem buscou isto também buscou")
+// @Description Retorna as queries buscadas nas mesmas sessões que q, das mais frequentes às menos. A coocorrência é calculada diariamente das jornadas de busca (job related-queries) e só inclui pares vistos em várias sessões distintas. As buscas também trazem as 3 primeiras em related_queries.
+// @Tags search
+// @Produce json
+// @Param q query string true "Query"
+// @Param limit query int false "Quantidade de sugestões (máximo 10)" default(3)
+// @Success 200 {object} models.RelatedQueries
+// @Failure 400 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/related-queries [get]
+func (h *RelatedQueriesHandler) GetRelatedQueries(c *gin.Context) {
+	if h.related == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Queries relacionadas desabilitadas (exige analytics de jornadas)"})
+		return
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetro q é obrigatório"})
+		return
+	}
+	limit := services.RelatedQueriesShown
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > 10 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit deve ser um inteiro entre 1 e 10"})
+			return
+		}
+		limit = parsed
+	}
+
+	related := h.related.Lookup(query, limit)
+	if related == nil {
+		related = []string{}
+	}
+	c.JSON(http.StatusOK, models.RelatedQueries{Query: query, Related: related})
+}
@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
 	"github.com/prefeitura-rio/app-busca-search/internal/models"
 	"github.com/prefeitura-rio/app-busca-search/internal/services"
 	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
@@ -14,6 +17,9 @@ import (
 type SearchHandler struct {
 	searchService   *services.SearchService
 	typesenseClient *typesense.Client
+	journeys        *services.JourneyAnalytics
+	related         *services.RelatedQueries
+	neighborhoods   *services.Neighborhoods
 }
 
 // NewSearchHandler cria um novo handler de busca
@@ -24,6 +30,21 @@ func NewSearchHandler(searchService *services.SearchService, typesenseClient *ty
 	}
 }
 
+// SetJourneyAnalytics registra as buscas das sessões anônimas (header X-Session-ID) nas jornadas
+func (h *SearchHandler) SetJourneyAnalytics(journeys *services.JourneyAnalytics) {
+	h.journeys = journeys
+}
+
+// SetRelatedQueries inclui nas respostas as queries buscadas nas mesmas sessões
+func (h *SearchHandler) SetRelatedQueries(related *services.RelatedQueries) {
+	h.related = related
+}
+
+// SetNeighborhoods resolve o parâmetro bairro na lista canônica de bairros
+func (h *SearchHandler) SetNeighborhoods(neighborhoods *services.Neighborhoods) {
+	h.neighborhoods = neighborhoods
+}
+
 // Search godoc
 // @Summary Busca unificada de serviços públicos
 // @Description Executa busca com 4 estratégias: keyword (textual), semantic (vetorial), hybrid (combinada) ou ai (agente inteligente). Resposta inclui total_count (total do Typesense) e filtered_count (após aplicar thresholds).
@@ -35,6 +56,7 @@ func NewSearchHandler(searchService *services.SearchService, typesenseClient *ty
 // @Param page query int false "Número da página (mínimo: 1)" default(1)
 // @Param per_page query int false "Resultados por página (máximo: 100)" default(10)
 // @Param include_inactive query bool false "Incluir serviços inativos (status != 1)" default(false)
+// @Param include_out_of_window query bool false "Incluir serviços sazonais fora da janela de disponibilidade, sinalizados em metadata.availability (upcoming/closed)" default(false)
 // @Param alpha query number false "Alpha para busca hybrid (0-1). Alpha=0.3 significa 30% texto + 70% vetor." default(0.3)
 // @Param threshold_keyword query number false "Score mínimo para busca keyword (0-1, filtra text_match normalizado via log normalization)"
 // @Param threshold_semantic query number false "Score mínimo para busca semantic (0-1, filtra por similaridade vetorial)"
@@ -42,14 +64,31 @@ func NewSearchHandler(searchService *services.SearchService, typesenseClient *ty
 // @Param threshold_ai query number false "Score mínimo para busca AI com generate_scores=true (0-1, filtra por ai_score.final_score)"
 // @Param exclude_agent_exclusive query bool false "Se true, exclui serviços exclusivos para agentes IA (mostra apenas serviços para humanos)" default(false)
 // @Param generate_scores query bool false "Gera scores detalhados via LLM para os resultados (apenas type=ai)." default(false)
-// @Param recency_boost query bool false "Aplica boost por recência: docs atualizados nos últimos 30 dias mantêm score, docs mais antigos sofrem decay gradual" default(false)
+// @Param recency_boost query bool false "Aplica boost por recência com a curva configurada para a collection (padrão: docs atualizados nos últimos 30 dias mantêm score, docs mais antigos sofrem decay gradual até 0.5). O efeito por resultado fica em score_info.recency_contribution" default(false)
+// @Param prazo_max_dias query int false "Apenas serviços com prazo de até N dias (entidades extraídas da descrição)"
+// @Param valor_max query number false "Apenas serviços com valor de até N reais (entidades extraídas da descrição)"
+// @Param documento query string false "Apenas serviços que exigem o documento (ex.: CPF)"
+// @Param bairro query string false "Bairro, região administrativa (ex.: RA Tijuca) ou zona (ex.: zona norte). Serviços com atendimento localizado fora dos bairros correspondentes são excluídos; os que atendem toda a cidade continuam"
+// @Param lang query string false "Idioma da query (pt, en, es...). Vazio detecta automaticamente; queries em outros idiomas são traduzidas para o português (ver query_meta)"
+// @Param debug_annotations query bool false "Anexa a cada resultado (annotations) as intervenções que o afetaram: rebaixamentos por diversidade, disponibilidade ou descontinuação e a tradução da query. As intervenções são gravadas no evento da busca" default(false)
+// @Param no_cache query bool false "Apenas administradores (JWT com go:admin): ignora o cache de resultados e de análise de query; a resposta recalculada substitui a armazenada. Ignorado para os demais" default(false)
+// @Param X-Session-ID header string false "ID de sessão anônimo gerado pelo cliente (sem dados pessoais), usado para reconstruir as jornadas de busca"
+// @Param envelope query string false "Versão do envelope da resposta: 1 (ServiceDocument, padrão) ou 2 (UnifiedDocument). Também pode ser pedida no header Accept (application/vnd.prefrio.busca.v2+json); a versão servida volta no header X-Envelope-Version"
+// @Param Accept header string false "application/json ou application/vnd.prefrio.busca.vN+json"
 // @Success 200 {object} models.SearchResponse
 // @Failure 400 {object} map[string]string
+// @Failure 406 {object} map[string]interface{}
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/search [get]
 func (h *SearchHandler) Search(c *gin.Context) {
 	var req models.SearchRequest
 
+	version, ok := searchEnvelopes.negotiate(c)
+	if !ok {
+		return
+	}
+
 	// Bind e validação
 	if err := c.ShouldBindQuery(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -108,7 +147,12 @@ func (h *SearchHandler) Search(c *gin.Context) {
 		return
 	}
 
+	if !resolveBairro(c, h.neighborhoods, &req) {
+		return
+	}
+
 	// Executar busca
+	started := time.Now()
 	result, err := h.searchService.Search(c.Request.Context(), &req)
 	if err != nil {
 		if err == services.ErrSearchCanceled {
@@ -118,6 +162,23 @@ func (h *SearchHandler) Search(c *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, services.ErrDependencyUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "Busca indisponível: dependência em falha",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		var mismatch *services.EmbeddingModelMismatchError
+		if errors.As(err, &mismatch) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Busca vetorial indisponível: modelo de embeddings do índice difere do configurado",
+				"details": err.Error(),
+			})
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Erro ao executar busca",
 			"details": err.Error(),
@@ -125,12 +186,26 @@ func (h *SearchHandler) Search(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	if result.Safety != nil {
+		middlewares.MarkSensitiveQuery(c, result.Safety.Topic)
+	} else {
+		h.journeys.RecordSearch(c.Request.Context(), sessionID(c), req.Query, req.Type, result.TotalCount, time.Since(started), result.Interventions)
+
+		// A resposta pode ser compartilhada (cache): as sugestões vão numa cópia
+		if related := h.related.Lookup(req.Query, services.RelatedQueriesShown); len(related) > 0 {
+			withRelated := *result
+			withRelated.RelatedQueries = related
+			result = &withRelated
+		}
+	}
+
+	setPaginationLinks(c, result.Page, result.PageInfo)
+	searchEnvelopes.render(c, http.StatusOK, version, result)
 }
 
 // GetDocumentByID godoc
 // @Summary Busca um serviço por ID (UUID)
-// @Description Retorna os detalhes completos de um serviço específico através de busca direta por UUID no Typesense
+// @Description Retorna os detalhes completos de um serviço específico através de busca direta por UUID no Typesense. Serviços com canais presenciais trazem em transporte as estações próximas de cada endereço (ex.: "próximo ao BRT Alvorada").
 // @Tags search
 // @Accept json
 // @Produce json
@@ -163,12 +238,14 @@ func (h *SearchHandler) GetDocumentByID(c *gin.Context) {
 
 // GetServiceBySlug godoc
 // @Summary Busca um serviço por slug SEO-friendly
-// @Description Retorna os detalhes completos de um serviço através do slug. Se o slug for histórico (antigo), retorna 301 redirect para o slug atual.
+// @Description Retorna os detalhes completos de um serviço através do slug. Se o slug for histórico (antigo), retorna 301 redirect para o slug atual; o slug de um serviço despublicado e substituído (replaced_by, ex.: mesclado em outro) redireciona para o substituto publicado. Com as_of, retorna o serviço como estava publicado no instante informado, reconstruído do histórico de versões (aceita também o ID, inclusive de serviços removidos), com a vigência da versão; 404 se o serviço não estava publicado.
 // @Tags services
 // @Accept json
 // @Produce json
 // @Param slug path string true "Slug do serviço" example(matricula-escolar-abc123de)
+// @Param as_of query string false "Instante da consulta: timestamp unix, RFC3339 ou AAAA-MM-DD (fim do dia, horário de Brasília)"
 // @Success 200 {object} models.PrefRioService
+// @Success 200 {object} models.ServiceAsOf "Com as_of"
 // @Success 301 {object} map[string]interface{} "Redirect para slug atual (inclui serviço e headers Location)"
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -182,6 +259,11 @@ func (h *SearchHandler) GetServiceBySlug(c *gin.Context) {
 		return
 	}
 
+	if asOf := c.Query("as_of"); asOf != "" {
+		h.getServiceAsOf(c, slug, asOf)
+		return
+	}
+
 	ctx := c.Request.Context()
 
 	// Tenta buscar pelo slug atual
@@ -195,6 +277,22 @@ func (h *SearchHandler) GetServiceBySlug(c *gin.Context) {
 	}
 
 	if service != nil {
+		// Serviço despublicado e substituído (ex.: mesclado em outro) redireciona para o substituto
+		if service.Status != 1 && service.ReplacedBy != "" {
+			if replacement, err := h.typesenseClient.GetPrefRioService(ctx, service.ReplacedBy); err == nil && replacement.Status == 1 {
+				newLocation := fmt.Sprintf("/api/v1/services/%s", replacement.Slug)
+				c.Header("Location", newLocation)
+				c.JSON(http.StatusMovedPermanently, gin.H{
+					"id":           replacement.ID,
+					"slug":         replacement.Slug,
+					"old_slug":     slug,
+					"message":      "Este serviço foi substituído por outro",
+					"location":     newLocation,
+					"nome_servico": replacement.NomeServico,
+				})
+				return
+			}
+		}
 		c.JSON(http.StatusOK, service)
 		return
 	}
@@ -229,3 +327,40 @@ func (h *SearchHandler) GetServiceBySlug(c *gin.Context) {
 		"error": "Serviço não encontrado",
 	})
 }
+
+// getServiceAsOf responde o estado publicado do serviço no instante as_of. O serviço é
+// identificado pelo slug atual, histórico ou pelo ID; serviços removidos só pelo ID.
+func (h *SearchHandler) getServiceAsOf(c *gin.Context, idOrSlug, rawAsOf string) {
+	asOf, err := services.ParseAsOf(rawAsOf)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	serviceID := idOrSlug
+	service, err := h.typesenseClient.GetPrefRioServiceBySlug(ctx, idOrSlug)
+	if err == nil && service == nil {
+		service, err = h.typesenseClient.GetPrefRioServiceByHistoricalSlug(ctx, idOrSlug)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar serviço", "details": err.Error()})
+		return
+	}
+	if service != nil {
+		serviceID = service.ID
+	}
+
+	version, validUntil, err := h.typesenseClient.GetServiceVersionAsOf(ctx, serviceID, asOf)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar histórico", "details": err.Error()})
+		return
+	}
+
+	state, err := services.ServiceStateAsOf(serviceID, version, validUntil, asOf)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, state)
+}
@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
 	"github.com/prefeitura-rio/app-busca-search/internal/models"
 	"github.com/prefeitura-rio/app-busca-search/internal/services"
 )
@@ -13,6 +16,9 @@ import (
 // SearchHandlerV2 gerencia endpoints de busca v2 (multi-collection)
 type SearchHandlerV2 struct {
 	searchService *services.SearchServiceV2
+	journeys      *services.JourneyAnalytics
+	related       *services.RelatedQueries
+	neighborhoods *services.Neighborhoods
 }
 
 // NewSearchHandlerV2 cria um novo handler de busca v2
@@ -22,9 +28,24 @@ func NewSearchHandlerV2(searchService *services.SearchServiceV2) *SearchHandlerV
 	}
 }
 
+// SetJourneyAnalytics registra as buscas das sessões anônimas (header X-Session-ID) nas jornadas
+func (h *SearchHandlerV2) SetJourneyAnalytics(journeys *services.JourneyAnalytics) {
+	h.journeys = journeys
+}
+
+// SetRelatedQueries inclui nas respostas as queries buscadas nas mesmas sessões
+func (h *SearchHandlerV2) SetRelatedQueries(related *services.RelatedQueries) {
+	h.related = related
+}
+
+// SetNeighborhoods resolve o parâmetro bairro na lista canônica de bairros
+func (h *SearchHandlerV2) SetNeighborhoods(neighborhoods *services.Neighborhoods) {
+	h.neighborhoods = neighborhoods
+}
+
 // Search godoc
 // @Summary Busca unificada multi-coleção (v2)
-// @Description Executa busca em múltiplas coleções configuradas (services, courses, jobs). Suporta keyword, semantic e hybrid search. Retorna documentos com estrutura unificada incluindo campo 'collection' e 'type'.
+// @Description Executa busca em múltiplas coleções configuradas (services, courses, jobs). Suporta keyword, semantic e hybrid search. Retorna documentos com estrutura unificada incluindo campo 'collection' e 'type'. Cada coleção tem um tempo limite (timeout_ms em COLLECTION_CONFIGS ou SEARCH_COLLECTION_TIMEOUT_MS); coleções que o excedem são descartadas e listadas em dropped_collections (resultado parcial). O total de documentos buscados por requisição é limitado (SEARCH_MAX_FETCHED_DOCS) e dividido entre as coleções consultadas; buscas em coleções demais (SEARCH_MAX_COLLECTIONS), com per_page acima da parte de cada coleção ou com página além desse total retornam 422 com orientação (para páginas profundas, use o next_cursor).
 // @Tags search-v2
 // @Accept json
 // @Produce json
@@ -32,21 +53,41 @@ func NewSearchHandlerV2(searchService *services.SearchServiceV2) *SearchHandlerV
 // @Param type query string true "Tipo de busca: keyword, semantic, hybrid"
 // @Param page query int false "Número da página (mínimo: 1)" default(1)
 // @Param per_page query int false "Resultados por página (máximo: 100)" default(10)
+// @Param cursor query string false "Continuação da busca (next_cursor da resposta anterior); substitui page e permite paginar além dos primeiros 250 resultados de cada coleção. Os demais parâmetros devem ser os mesmos da busca original"
 // @Param include_inactive query bool false "Incluir documentos inativos (aplica-se apenas a coleções com filtro de status)" default(false)
+// @Param include_out_of_window query bool false "Incluir serviços sazonais fora da janela de disponibilidade, sinalizados em metadata.availability (upcoming/closed)" default(false)
 // @Param alpha query number false "Alpha para busca hybrid (0-1). Alpha=0.3 significa 30% texto + 70% vetor." default(0.3)
 // @Param threshold_keyword query number false "Score mínimo para busca keyword (0-1, filtra text_match normalizado)"
 // @Param threshold_semantic query number false "Score mínimo para busca semantic (0-1, filtra por similaridade vetorial)"
 // @Param threshold_hybrid query number false "Score mínimo para busca hybrid (0-1, filtra score híbrido)"
+// @Param recency_boost query bool false "Aplica boost por recência com a curva configurada em cada collection (recency em COLLECTION_CONFIGS) e reordena os resultados. O efeito por resultado fica em score_info.recency_contribution" default(false)
 // @Param search_fields query string false "Override dos campos de busca (comma-separated). Ex: titulo,descricao,conteudo"
 // @Param search_weights query string false "Override dos pesos de busca (comma-separated). Ex: 4,2,1"
+// @Param fields query string false "Campos extras em data (comma-separated) para collections com include_fields em COLLECTION_CONFIGS, que devolvem apenas os campos do documento unificado. Ex: orgao_gestor,custo"
+// @Param facet_by query string false "Campos para contagem de resultados por valor (comma-separated): tema_geral, orgao_gestor, publico_especifico, is_free. As contagens (facets) são somadas entre as collections buscadas que têm o campo (facet_fields em COLLECTION_CONFIGS; a de serviços tem todos). Ex: tema_geral,is_free"
 // @Param collections query string false "Filtrar busca por collections específicas (comma-separated). Ex: prefrio_services_base,hub_search. Se não especificado, busca em todas."
+// @Param bairro query string false "Bairro, região administrativa (ex.: RA Tijuca) ou zona (ex.: zona norte). Aplica-se a serviços e ao hub: entradas com atendimento localizado fora dos bairros correspondentes são excluídas"
+// @Param doc_types query string false "Filtrar busca pelo tipo das collections (comma-separated). Ex: news. A resposta traz type_counts com o total encontrado por tipo"
+// @Param lang query string false "Idioma da query (pt, en, es...). Vazio detecta automaticamente; queries em outros idiomas são traduzidas para o português (ver query_meta)"
+// @Param debug_annotations query bool false "Anexa a cada resultado (annotations) as intervenções que o afetaram (tradução da query). As intervenções são gravadas no evento da busca" default(false)
+// @Param X-Session-ID header string false "ID de sessão anônimo gerado pelo cliente (sem dados pessoais), usado para reconstruir as jornadas de busca"
+// @Param envelope query string false "Versão do envelope da resposta: 2 (UnifiedDocument, padrão). Também pode ser pedida no header Accept (application/vnd.prefrio.busca.v2+json); a versão servida volta no header X-Envelope-Version"
+// @Param Accept header string false "application/json ou application/vnd.prefrio.busca.vN+json"
 // @Success 200 {object} models.UnifiedSearchResponse
 // @Failure 400 {object} map[string]string
+// @Failure 406 {object} map[string]interface{}
+// @Failure 422 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Failure 504 {object} map[string]string
 // @Router /api/v2/search [get]
 func (h *SearchHandlerV2) Search(c *gin.Context) {
 	var req models.SearchRequest
 
+	version, ok := unifiedSearchEnvelopes.negotiate(c)
+	if !ok {
+		return
+	}
+
 	if err := c.ShouldBindQuery(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Parâmetros inválidos",
@@ -106,6 +147,16 @@ func (h *SearchHandlerV2) Search(c *gin.Context) {
 		}
 	}
 
+	facetBy, err := services.ParseFacetBy(req.FacetBy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Parâmetros inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+	req.ParsedFacetBy = facetBy
+
 	// Validar tipo de busca (v2 não suporta AI search ainda)
 	validTypes := map[models.SearchType]bool{
 		models.SearchTypeKeyword:  true,
@@ -121,7 +172,35 @@ func (h *SearchHandlerV2) Search(c *gin.Context) {
 		return
 	}
 
+	if !resolveBairro(c, h.neighborhoods, &req) {
+		return
+	}
+
+	started := time.Now()
 	result, err := h.searchService.Search(c.Request.Context(), &req)
+	if errors.Is(err, services.ErrInvalidSearchCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Parâmetros inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+	var fanOut *services.SearchFanOutError
+	if errors.As(err, &fanOut) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":    "Busca excede os limites de fan-out",
+			"details":  fanOut.Reason,
+			"guidance": fanOut.Guidance,
+		})
+		return
+	}
+	if errors.Is(err, services.ErrSearchTimeout) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{
+			"error":   "Tempo limite da busca excedido",
+			"details": err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Erro ao executar busca",
@@ -130,7 +209,21 @@ func (h *SearchHandlerV2) Search(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	if result.Safety != nil {
+		middlewares.MarkSensitiveQuery(c, result.Safety.Topic)
+	} else {
+		h.journeys.RecordSearch(c.Request.Context(), sessionID(c), req.Query, req.Type, result.TotalCount, time.Since(started), result.Interventions)
+
+		// A resposta pode ser compartilhada (cache): as sugestões vão numa cópia
+		if related := h.related.Lookup(req.Query, services.RelatedQueriesShown); len(related) > 0 {
+			withRelated := *result
+			withRelated.RelatedQueries = related
+			result = &withRelated
+		}
+	}
+
+	setPaginationLinks(c, result.Page, result.PageInfo)
+	unifiedSearchEnvelopes.render(c, http.StatusOK, version, result)
 }
 
 // GetDocumentByID godoc
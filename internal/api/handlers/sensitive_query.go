@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// SensitiveQueryHandler expõe as contagens de buscas sensíveis (única informação mantida sobre elas)
+type SensitiveQueryHandler struct {
+	classifier *services.SensitiveQueryClassifier
+}
+
+// NewSensitiveQueryHandler cria um novo handler de buscas sensíveis
+func NewSensitiveQueryHandler(classifier *services.SensitiveQueryClassifier) *SensitiveQueryHandler {
+	return &SensitiveQueryHandler{classifier: classifier}
+}
+
+// GetCounts godoc
+// @Summary Contagem de buscas sensíveis por tema
+// @Description Retorna quantas buscas sobre temas sensíveis (emergências de saúde, violência, suicídio) esta instância recebeu desde o início. As queries em si não são registradas.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]int64
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/admin/reports/sensitive-queries [get]
+func (h *SensitiveQueryHandler) GetCounts(c *gin.Context) {
+	c.JSON(http.StatusOK, h.classifier.Counts())
+}
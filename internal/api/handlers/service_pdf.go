@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
+)
+
+// ServicePDFHandler expõe a ficha em PDF dos serviços, impressa no atendimento presencial
+type ServicePDFHandler struct {
+	typesenseClient *typesense.Client
+	renderer        *services.ServicePDFRenderer
+}
+
+// NewServicePDFHandler cria um novo handler de fichas em PDF
+func NewServicePDFHandler(client *typesense.Client, renderer *services.ServicePDFRenderer) *ServicePDFHandler {
+	return &ServicePDFHandler{
+		typesenseClient: client,
+		renderer:        renderer,
+	}
+}
+
+// GetServicePDF godoc
+// @Summary Ficha do serviço em PDF
+// @Description Retorna a ficha de um serviço publicado em PDF (A4) para impressão nos balcões de atendimento: nome, órgão, categoria, resumo, descrição e instruções com o markdown convertido (links trazem o endereço por extenso), documentos necessários, tempo, custo, canais e legislação. O PDF é marcado (tagged), com título, idioma e estrutura de títulos, parágrafos e listas para leitores de tela. A ficha é gerada uma vez por versão do serviço; o ETag identifica a versão (If-None-Match responde 304). Aceita o ID ou o slug do serviço.
+// @Tags services
+// @Produce application/pdf
+// @Param id path string true "ID ou slug do serviço"
+// @Success 200 {file} file
+// @Success 304 "Ficha inalterada"
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/services/{id}/pdf [get]
+func (h *ServicePDFHandler) GetServicePDF(c *gin.Context) {
+	ctx := c.Request.Context()
+	service, ok := findPublishedService(c, h.typesenseClient, c.Param("slug"))
+	if !ok {
+		return
+	}
+
+	// Sem histórico de versões, a ficha é identificada apenas pela data de atualização. Versões
+	// pendentes não entram: o serviço publicado corresponde à última versão confirmada
+	var version int64
+	if latest, err := h.typesenseClient.GetLatestConfirmedServiceVersion(ctx, service.ID); err == nil && latest != nil {
+		version = latest.VersionNumber
+	}
+
+	etag := fmt.Sprintf(`"%s-%d-%d"`, service.ID, version, service.LastUpdate)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=300")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	data, err := h.renderer.Render(service, version)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar PDF: " + err.Error()})
+		return
+	}
+
+	filename := service.Slug
+	if filename == "" {
+		filename = service.ID
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s.pdf"`, filename))
+	c.Data(http.StatusOK, "application/pdf", data)
+}
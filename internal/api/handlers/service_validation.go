@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// ServiceValidationHandler valida lotes de serviços enviados por sistemas externos
+type ServiceValidationHandler struct {
+	validator *services.ServiceValidator
+}
+
+// NewServiceValidationHandler cria um novo handler de validação de serviços
+func NewServiceValidationHandler(validator *services.ServiceValidator) *ServiceValidationHandler {
+	return &ServiceValidationHandler{validator: validator}
+}
+
+// ValidateServices godoc
+// @Summary Valida um lote de serviços sem gravar
+// @Description Aplica a cada serviço do lote (até 100) a mesma validação do cadastro (campos obrigatórios e limites, janela de disponibilidade, extra_fields do tema, orgao_gestor e bairros) e retorna um relatório por serviço. Erros impedem o cadastro; avisos apontam tema ou subcategoria fora da taxonomia, links inválidos e serviços com o mesmo nome já cadastrados ou repetidos no lote. Com check_links os links externos também são verificados. Nada é gravado.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body models.ServiceValidationRequest true "Serviços a validar"
+// @Success 200 {object} models.ServiceValidationReport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/services/validate [post]
+func (h *ServiceValidationHandler) ValidateServices(c *gin.Context) {
+	var request models.ServiceValidationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	report, err := h.validator.Validate(c.Request.Context(), &request)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao validar serviços: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"iter"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	streamFormatJSON  = "json"
+	streamFormatJSONL = "jsonl"
+
+	// streamFlushEvery quantidade de itens escritos entre flushes do buffer de resposta
+	streamFlushEvery = 100
+)
+
+// streamJSON escreve os itens na resposta à medida que são produzidos, sem montar o
+// corpo completo em memória. Sem Content-Length, o Gin envia com chunked transfer encoding.
+// format "jsonl" gera um documento por linha; qualquer outro valor gera um array JSON.
+// Erros após o início da escrita só podem interromper a resposta, que fica truncada.
+func streamJSON(c *gin.Context, format string, items iter.Seq2[json.RawMessage, error]) error {
+	contentType := "application/json; charset=utf-8"
+	if format == streamFormatJSONL {
+		contentType = "application/x-ndjson; charset=utf-8"
+	}
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+
+	w := bufio.NewWriterSize(c.Writer, 32*1024)
+	count := 0
+
+	if format != streamFormatJSONL {
+		w.WriteString("[")
+	}
+
+	for item, err := range items {
+		if err != nil {
+			w.Flush()
+			return err
+		}
+
+		if format == streamFormatJSONL {
+			w.Write(item)
+			w.WriteString("\n")
+		} else {
+			if count > 0 {
+				w.WriteString(",")
+			}
+			w.Write(item)
+		}
+
+		count++
+		if count%streamFlushEvery == 0 {
+			if err := w.Flush(); err != nil {
+				// cliente desconectou
+				return err
+			}
+			c.Writer.Flush()
+		}
+	}
+
+	if format != streamFormatJSONL {
+		w.WriteString("]")
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
+
+// jsonLines itera sobre as linhas não vazias de um corpo JSONL (ex: export do Typesense)
+func jsonLines(body io.Reader) iter.Seq2[json.RawMessage, error] {
+	return func(yield func(json.RawMessage, error) bool) {
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 50*1024*1024)
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			if len(line) == 0 {
+				continue
+			}
+			if !yield(json.RawMessage(line), nil) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
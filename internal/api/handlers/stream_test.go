@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestStreamJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	body := "{\"id\":\"1\"}\n\n{\"id\":\"2\"}\n"
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{streamFormatJSON, `[{"id":"1"},{"id":"2"}]`},
+		{streamFormatJSONL, "{\"id\":\"1\"}\n{\"id\":\"2\"}\n"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		if err := streamJSON(c, tt.format, jsonLines(strings.NewReader(body))); err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+		if w.Body.String() != tt.expected {
+			t.Errorf("formato %s: esperado %q, obtido %q", tt.format, tt.expected, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// SynonymHandler gerencia a fila de candidatos a sinônimo minerados das jornadas de busca
+type SynonymHandler struct {
+	miner *services.SynonymMiner
+}
+
+// NewSynonymHandler cria um novo handler de sinônimos
+func NewSynonymHandler(miner *services.SynonymMiner) *SynonymHandler {
+	return &SynonymHandler{miner: miner}
+}
+
+// ListCandidates godoc
+// @Summary Lista os candidatos a sinônimo
+// @Description Retorna os pares de queries em que usuários reformularam a busca e clicaram em um resultado da reformulação (ex.: xerox → cópia autenticada), com a quantidade de sessões e jornadas e os serviços clicados como evidência. Os candidatos são gerados diariamente pelo job synonym-mining.
+// @Tags admin
+// @Produce json
+// @Param status query string false "pending, approved ou rejected (vazio = todos)" default(pending)
+// @Param page query int false "Página" default(1)
+// @Param per_page query int false "Candidatos por página" default(20)
+// @Success 200 {object} models.SynonymCandidateList
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/synonyms/candidates [get]
+func (h *SynonymHandler) ListCandidates(c *gin.Context) {
+	if h.miner == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Mineração de sinônimos desabilitada (exige analytics de jornadas)"})
+		return
+	}
+
+	status := models.SynonymCandidateStatus(c.DefaultQuery("status", string(models.SynonymCandidatePending)))
+	switch status {
+	case "", models.SynonymCandidatePending, models.SynonymCandidateApproved, models.SynonymCandidateRejected:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status deve ser pending, approved ou rejected"})
+		return
+	}
+	page, perPage := parsePagination(c, 20, 100)
+
+	list, err := h.miner.List(c.Request.Context(), status, page, perPage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar candidatos: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// ApproveCandidate godoc
+// @Summary Aprova um candidato a sinônimo
+// @Description Cria o sinônimo na collection de serviços: de mão única por padrão (buscar from também encontra to) ou, com multi_way, equivalência entre as duas queries
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do candidato"
+// @Param request body models.ApproveSynonymRequest false "Tipo do sinônimo"
+// @Success 200 {object} models.SynonymCandidate
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/synonyms/candidates/{id}/approve [post]
+func (h *SynonymHandler) ApproveCandidate(c *gin.Context) {
+	if h.miner == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Mineração de sinônimos desabilitada (exige analytics de jornadas)"})
+		return
+	}
+
+	var request models.ApproveSynonymRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+			return
+		}
+	}
+
+	candidate, err := h.miner.Approve(writeContext(c), c.Param("id"), &request, middlewares.GetUserName(c))
+	if !h.handleError(c, err, "Erro ao aprovar candidato: ") {
+		return
+	}
+	c.JSON(http.StatusOK, candidate)
+}
+
+// RejectCandidate godoc
+// @Summary Rejeita um candidato a sinônimo
+// @Description Descarta o candidato; o par não volta a ser proposto pela mineração
+// @Tags admin
+// @Produce json
+// @Param id path string true "ID do candidato"
+// @Success 200 {object} models.SynonymCandidate
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/synonyms/candidates/{id}/reject [post]
+func (h *SynonymHandler) RejectCandidate(c *gin.Context) {
+	if h.miner == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Mineração de sinônimos desabilitada (exige analytics de jornadas)"})
+		return
+	}
+
+	candidate, err := h.miner.Reject(writeContext(c), c.Param("id"), middlewares.GetUserName(c))
+	if !h.handleError(c, err, "Erro ao rejeitar candidato: ") {
+		return
+	}
+	c.JSON(http.StatusOK, candidate)
+}
+
+// handleError responde ao erro da revisão e retorna false se houve erro
+func (h *SynonymHandler) handleError(c *gin.Context, err error, message string) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrSynonymCandidateNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSynonymCandidateResolved):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message + err.Error()})
+	}
+	return false
+}
@@ -1,9 +1,7 @@
 package handlers
 
 import (
-	"context"
 	"net/http"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -51,7 +49,7 @@ func (h *TombamentoHandler) CreateTombamento(c *gin.Context) {
 		return
 	}
 
-	ctx := context.Background()
+	ctx := writeContext(c)
 
 	// Verifica se o serviço novo existe na prefrio_services_base
 	_, err := h.typesenseClient.GetPrefRioService(ctx, request.IDServicoNovo)
@@ -109,7 +107,7 @@ func (h *TombamentoHandler) GetTombamento(c *gin.Context) {
 	}
 
 	// Busca o tombamento
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	tombamento, err := h.typesenseClient.GetTombamento(ctx, tombamentoID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Tombamento não encontrado"})
@@ -134,16 +132,7 @@ func (h *TombamentoHandler) GetTombamento(c *gin.Context) {
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/admin/tombamentos [get]
 func (h *TombamentoHandler) ListTombamentos(c *gin.Context) {
-	// Parse de parâmetros de paginação
-	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		page = 1
-	}
-
-	perPage, err := strconv.Atoi(c.DefaultQuery("per_page", "10"))
-	if err != nil || perPage < 1 || perPage > 100 {
-		perPage = 10
-	}
+	page, perPage := parsePagination(c, 10, 100)
 
 	// Parse de filtros
 	filters := make(map[string]any)
@@ -160,13 +149,14 @@ func (h *TombamentoHandler) ListTombamentos(c *gin.Context) {
 	}
 
 	// Lista os tombamentos
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	response, err := h.typesenseClient.ListTombamentos(ctx, page, perPage, filters)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar tombamentos: " + err.Error()})
 		return
 	}
 
+	setPaginationLinks(c, page, response.PageInfo)
 	c.JSON(http.StatusOK, response)
 }
 
@@ -203,7 +193,7 @@ func (h *TombamentoHandler) UpdateTombamento(c *gin.Context) {
 		return
 	}
 
-	ctx := context.Background()
+	ctx := writeContext(c)
 
 	// Busca o tombamento existente para preservar dados
 	existingTombamento, err := h.typesenseClient.GetTombamento(ctx, tombamentoID)
@@ -261,7 +251,7 @@ func (h *TombamentoHandler) DeleteTombamento(c *gin.Context) {
 	}
 
 	// Deleta o tombamento
-	ctx := context.Background()
+	ctx := writeContext(c)
 	err := h.typesenseClient.DeleteTombamento(ctx, tombamentoID)
 	if err != nil {
 		if err.Error() == "tombamento não encontrado" {
@@ -304,7 +294,7 @@ func (h *TombamentoHandler) GetTombamentoByOldService(c *gin.Context) {
 	}
 
 	// Busca o tombamento
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	tombamento, err := h.typesenseClient.GetTombamentoByOldServiceID(ctx, origem, idServicoAntigo)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Tombamento não encontrado"})
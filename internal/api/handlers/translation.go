@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
+)
+
+// TranslationHandler gerencia o fluxo de tradução dos serviços
+type TranslationHandler struct {
+	typesenseClient    *typesense.Client
+	translationService *services.TranslationService
+}
+
+// NewTranslationHandler cria um novo handler de traduções
+func NewTranslationHandler(client *typesense.Client, translationService *services.TranslationService) *TranslationHandler {
+	return &TranslationHandler{
+		typesenseClient:    client,
+		translationService: translationService,
+	}
+}
+
+// ListTranslations godoc
+// @Summary Lista a situação das traduções de um serviço
+// @Description Retorna, para cada idioma configurado (TRANSLATION_LANGUAGES), a situação da tradução (missing, machine, reviewed) e se está desatualizada em relação ao conteúdo original
+// @Tags translations
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do serviço"
+// @Success 200 {object} models.ServiceTranslationList
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/services/{id}/translations [get]
+func (h *TranslationHandler) ListTranslations(c *gin.Context) {
+	service, err := h.typesenseClient.GetPrefRioService(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Serviço não encontrado"})
+		return
+	}
+
+	translations, err := h.translationService.List(c.Request.Context(), service)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar traduções: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, translations)
+}
+
+// MachineTranslate godoc
+// @Summary Traduz um serviço automaticamente
+// @Description Gera a tradução do serviço no idioma com o Gemini, registrada como machine (não indexada até a revisão). Uma tradução revisada e atual só é sobrescrita com force=true
+// @Tags translations
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do serviço"
+// @Param lang path string true "Idioma (ex.: en, es)"
+// @Param force query bool false "Sobrescreve tradução revisada" default(false)
+// @Success 200 {object} models.ServiceTranslation
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/services/{id}/translations/{lang}/machine [post]
+func (h *TranslationHandler) MachineTranslate(c *gin.Context) {
+	force, _ := strconv.ParseBool(c.DefaultQuery("force", "false"))
+
+	ctx := writeContext(c)
+	service, err := h.typesenseClient.GetPrefRioService(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Serviço não encontrado"})
+		return
+	}
+
+	translation, err := h.translationService.MachineTranslate(ctx, service, c.Param("lang"), force)
+	if err != nil {
+		h.respondError(c, "Erro ao traduzir serviço: ", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, translation)
+}
+
+// ReviewTranslation godoc
+// @Summary Registra a revisão humana de uma tradução
+// @Description Salva a tradução revisada (ou aprova a tradução automática, sem corpo) e a indexa no serviço em translations.{lang}, tornando-a pesquisável
+// @Tags translations
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do serviço"
+// @Param lang path string true "Idioma (ex.: en, es)"
+// @Param request body models.TranslationReviewRequest false "Campos revisados"
+// @Success 200 {object} models.ServiceTranslation
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/admin/services/{id}/translations/{lang}/review [put]
+func (h *TranslationHandler) ReviewTranslation(c *gin.Context) {
+	var request models.TranslationReviewRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+			return
+		}
+	}
+
+	ctx := writeContext(c)
+	service, err := h.typesenseClient.GetPrefRioService(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Serviço não encontrado"})
+		return
+	}
+
+	lang := c.Param("lang")
+	translation, err := h.translationService.Review(ctx, service, lang, request.Fields, middlewares.GetUserName(c))
+	if err != nil {
+		h.respondError(c, "Erro ao revisar tradução: ", err)
+		return
+	}
+
+	if err := h.typesenseClient.IndexServiceTranslation(ctx, service, lang, translation.Fields); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Tradução revisada, mas não indexada: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, translation)
+}
+
+func (h *TranslationHandler) respondError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrUnsupportedLanguage), errors.Is(err, services.ErrTranslationNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTranslationReviewed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTranslationUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message + err.Error()})
+	}
+}
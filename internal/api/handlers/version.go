@@ -1,13 +1,13 @@
 package handlers
 
 import (
-	"context"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
 	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
 	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
 )
 
@@ -23,13 +23,14 @@ func NewVersionHandler(client *typesense.Client) *VersionHandler {
 
 // ListServiceVersions godoc
 // @Summary Lista todas as versões de um serviço
-// @Description Retorna o histórico completo de versões de um serviço com paginação
+// @Description Retorna o histórico de versões de um serviço com paginação. Versões pendentes (gravadas antes da criação do serviço e ainda não confirmadas) são omitidas, a menos que include_pending=true; nesse caso vêm marcadas com pending=true.
 // @Tags versions
 // @Accept json
 // @Produce json
 // @Param id path string true "ID do serviço"
 // @Param page query int false "Página" default(1)
 // @Param per_page query int false "Resultados por página" default(10)
+// @Param include_pending query bool false "Inclui as versões pendentes"
 // @Success 200 {object} models.VersionHistory
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
@@ -42,16 +43,20 @@ func (h *VersionHandler) ListServiceVersions(c *gin.Context) {
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "10"))
+	page, perPage := parsePagination(c, 10, 100)
 
-	ctx := context.Background()
-	history, err := h.typesenseClient.ListServiceVersions(ctx, serviceID, page, perPage)
+	ctx := c.Request.Context()
+	list := h.typesenseClient.ListConfirmedServiceVersions
+	if c.Query("include_pending") == "true" {
+		list = h.typesenseClient.ListServiceVersions
+	}
+	history, err := list(ctx, serviceID, page, perPage)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar versões: " + err.Error()})
 		return
 	}
 
+	setPaginationLinks(c, history.Page, history.PageInfo)
 	c.JSON(http.StatusOK, history)
 }
 
@@ -83,7 +88,7 @@ func (h *VersionHandler) GetServiceVersion(c *gin.Context) {
 		return
 	}
 
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	version, err := h.typesenseClient.GetServiceVersionByNumber(ctx, serviceID, versionNum)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Versão não encontrada: " + err.Error()})
@@ -135,7 +140,7 @@ func (h *VersionHandler) CompareServiceVersions(c *gin.Context) {
 		return
 	}
 
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	diff, err := h.typesenseClient.CompareServiceVersions(ctx, serviceID, fromVersion, toVersion)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao comparar versões: " + err.Error()})
@@ -172,7 +177,7 @@ func (h *VersionHandler) RollbackService(c *gin.Context) {
 		return
 	}
 
-	ctx := context.Background()
+	ctx := writeContext(c)
 
 	// Busca a versão alvo do rollback
 	targetVersion, err := h.typesenseClient.GetServiceVersionByNumber(ctx, serviceID, request.ToVersion)
@@ -245,3 +250,40 @@ func (h *VersionHandler) RollbackService(c *gin.Context) {
 		"service":          updatedService,
 	})
 }
+
+// GetPublicChangelog godoc
+// @Summary Histórico público de alterações de um serviço
+// @Description Retorna o que mudou e quando em um serviço publicado (requisitos, custos, canais etc.), sem identificar quem fez a alteração. Aceita o ID ou o slug do serviço.
+// @Tags search
+// @Produce json
+// @Param id path string true "ID ou slug do serviço"
+// @Param page query int false "Página" default(1)
+// @Param per_page query int false "Versões analisadas por página (máx. 100)" default(20)
+// @Success 200 {object} models.ServiceChangelog
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/services/{id}/changelog [get]
+func (h *VersionHandler) GetPublicChangelog(c *gin.Context) {
+	ctx := c.Request.Context()
+	service, ok := findPublishedService(c, h.typesenseClient, c.Param("slug"))
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	history, err := h.typesenseClient.ListConfirmedServiceVersions(ctx, service.ID, page, perPage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar histórico: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ServiceChangelog{
+		ServiceID:   service.ID,
+		NomeServico: service.NomeServico,
+		Page:        history.Page,
+		PerPage:     perPage,
+		Entries:     services.BuildPublicChangelog(history.Versions),
+	})
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
+)
+
+// WhatsAppCardHandler expõe os cards de serviço usados pelo chatbot do WhatsApp
+type WhatsAppCardHandler struct {
+	typesenseClient *typesense.Client
+	cards           *services.WhatsAppCardBuilder
+}
+
+// NewWhatsAppCardHandler cria um novo handler de cards do WhatsApp
+func NewWhatsAppCardHandler(client *typesense.Client, cards *services.WhatsAppCardBuilder) *WhatsAppCardHandler {
+	return &WhatsAppCardHandler{
+		typesenseClient: client,
+		cards:           cards,
+	}
+}
+
+// GetWhatsAppCard godoc
+// @Summary Card do serviço para o WhatsApp
+// @Description Retorna a mensagem pré-formatada de um serviço publicado para o chatbot do WhatsApp: título, resumo em texto simples marcado com emojis (tempo, custo, documentos, órgão), deep link com os UTMs do canal whatsapp e até 3 respostas rápidas geradas dos botões ativos. Os textos respeitam os limites das mensagens interativas (título 60, corpo 1024 e respostas 20 caracteres). Aceita o ID ou o slug do serviço.
+// @Tags services
+// @Produce json
+// @Param id path string true "ID ou slug do serviço"
+// @Success 200 {object} models.WhatsAppCard
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/services/{id}/whatsapp-card [get]
+func (h *WhatsAppCardHandler) GetWhatsAppCard(c *gin.Context) {
+	service, ok := findPublishedService(c, h.typesenseClient, c.Param("slug"))
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, h.cards.Build(service))
+}
+
+// findPublishedService busca um serviço publicado pelo ID ou, se o ID não existir, pelo slug.
+// Serviços inexistentes ou não publicados respondem 404; demais falhas do Typesense, 500. Com
+// ok false a resposta já foi escrita.
+func findPublishedService(c *gin.Context, client *typesense.Client, idOrSlug string) (*models.PrefRioService, bool) {
+	ctx := c.Request.Context()
+
+	service, err := client.GetPrefRioService(ctx, idOrSlug)
+	if errors.Is(err, typesense.ErrServiceNotFound) {
+		service, err = client.GetPrefRioServiceBySlug(ctx, idOrSlug)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar serviço: " + err.Error()})
+		return nil, false
+	}
+	if service == nil || service.Status != 1 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Serviço não encontrado"})
+		return nil, false
+	}
+	return service, true
+}
@@ -3,13 +3,17 @@ package routes
 import (
 	"context"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prefeitura-rio/app-busca-search/internal/api/handlers"
 	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/jobs"
 	middlewares "github.com/prefeitura-rio/app-busca-search/internal/middleware"
 	"github.com/prefeitura-rio/app-busca-search/internal/migration/schemas"
+	"github.com/prefeitura-rio/app-busca-search/internal/newsroom"
+	"github.com/prefeitura-rio/app-busca-search/internal/pii"
 	"github.com/prefeitura-rio/app-busca-search/internal/services"
 	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
 	swaggerFiles "github.com/swaggo/files"
@@ -17,16 +21,44 @@ import (
 	"google.golang.org/genai"
 )
 
-func SetupRouter(cfg *config.Config) *gin.Engine {
-	r := gin.Default()
+// SetupRouter monta o router da API. ctx controla as rotinas em segundo plano (recargas
+// periódicas) e deve ser cancelado no encerramento do servidor.
+func SetupRouter(ctx context.Context, cfg *config.Config) *gin.Engine {
+	// Padrões adicionais de dados pessoais mascarados em logs, traces e analytics
+	if err := pii.Configure(cfg.PIIPatterns); err != nil {
+		log.Fatalf("PII_PATTERNS inválido: %v", err)
+	}
+
+	r := gin.New()
+	r.Use(middlewares.RequestLogger(), gin.Recovery())
 
 	r.Use(corsMiddleware())
 	r.Use(middlewares.RequestTiming()) // Add OpenTelemetry tracing
+	r.Use(middlewares.RequestCancellation())
+	r.Use(middlewares.CacheBypass()) // no_cache=true de administradores
+
+	// Particionamento por município (tenant), habilitado por TENANT_CONFIGS
+	if cfg.MultiTenant() {
+		r.Use(middlewares.TenantScope(cfg))
+	}
 
 	typesenseClient := typesense.NewClient(cfg)
 
+	// Serviços e tombamentos gravados sem tenant passam ao tenant padrão, para que o filtro por
+	// tenant os encontre; hub_search e as collections legadas continuam sem particionamento
+	if cfg.MultiTenant() {
+		go func() {
+			updated, err := typesenseClient.AssignDefaultTenant(context.Background())
+			if err != nil {
+				log.Printf("[Tenant] Erro ao atribuir o tenant padrão: %v", err)
+			}
+			if updated > 0 {
+				log.Printf("[Tenant] %d documentos atribuídos ao tenant padrão", updated)
+			}
+		}()
+	}
+
 	// Initialize Gemini client
-	ctx := context.Background()
 	geminiClient, err := genai.NewClient(ctx, &genai.ClientConfig{
 		APIKey: cfg.GeminiAPIKey,
 	})
@@ -39,10 +71,49 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 	cache := services.NewLRUCache(500)
 	cache.StartCleanupRoutine(5 * time.Minute)
 
+	// Eventos de escrita em documentos (usados para invalidar caches), retransmitidos
+	// entre réplicas via Redis pub/sub quando REDIS_URL está configurada
+	redisClient := services.NewRedisClient(cfg.RedisURL)
+	eventBus := services.NewEventBus()
+
+	// Política de degradação por dependência (DEGRADATION_POLICY) e degradações ativas
+	degradations := services.NewDegradationMonitor(cfg.DegradationPolicy, 2*time.Minute)
+	if len(cfg.TypesenseNodes) > 0 {
+		degradations.StartNodeProbe(fmt.Sprintf("%s://%s:%s", cfg.TypesenseProtocol, cfg.TypesenseHost, cfg.TypesensePort), 30*time.Second)
+	}
+
+	// Cache de respostas dos endpoints públicos, invalidado a cada escrita
+	var responseCache *services.ResponseCache
+	if cfg.ResponseCacheEnabled {
+		responseCache = services.NewResponseCache(1000, time.Duration(cfg.ResponseCacheTTL)*time.Second, redisClient)
+		responseCache.SetDegradationMonitor(degradations)
+		eventBus.Subscribe(responseCache.HandleDocumentEvent)
+	}
+
 	// Initialize handlers
-	adminHandler := handlers.NewAdminHandler(typesenseClient)
 	tombamentoHandler := handlers.NewTombamentoHandler(typesenseClient)
 	versionHandler := handlers.NewVersionHandler(typesenseClient)
+	whatsAppCardHandler := handlers.NewWhatsAppCardHandler(typesenseClient, services.NewWhatsAppCardBuilder(services.NewDeepLinkBuilder(cfg.DeepLinkChannels)))
+	servicePDFHandler := handlers.NewServicePDFHandler(typesenseClient, services.NewServicePDFRenderer(200))
+	exportHandler := handlers.NewExportHandler(typesenseClient, 2)
+
+	// Anexos dos serviços (GCS)
+	var attachmentStorage *services.GCSStorage
+	if cfg.GCSBucket != "" {
+		storage, err := services.NewGCSStorage(cfg.GCSBucket, cfg.GCSCredentialsFile)
+		if err != nil {
+			log.Printf("Aviso: anexos desabilitados: %v", err)
+		} else {
+			attachmentStorage = storage
+		}
+	}
+	attachmentExtractor := services.NewAttachmentTextExtractor(geminiClient, "gemini-2.5-flash")
+	attachmentHandler := handlers.NewAttachmentHandler(typesenseClient, attachmentStorage, attachmentExtractor, eventBus, cfg.AttachmentMaxSizeMB)
+
+	// Parâmetros de busca recarregáveis sem reiniciar (SIGHUP ou POST /admin/config/reload)
+	configStore := config.NewStore(cfg)
+	configStore.WatchSignals()
+	configHandler := handlers.NewConfigHandler(configStore)
 
 	// Initialize search service (direct search)
 	typesenseURL := fmt.Sprintf("%s://%s:%s", cfg.TypesenseProtocol, cfg.TypesenseHost, cfg.TypesensePort)
@@ -54,77 +125,458 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 		typesenseURL,
 		cfg.TypesenseAPIKey,
 	)
+	searchService.SetDegradationMonitor(degradations)
+	searchService.SetConfigStore(configStore)
+	searchService.SetTypesenseNodes(cfg.TypesenseNodes)
 	searchHandler := handlers.NewSearchHandler(searchService, typesenseClient)
 
+	// Buscas sensíveis (emergências, violência, suicídio): contatos de emergência e sem registro da query
+	sensitiveTopics, err := services.LoadSensitiveTopics(cfg.SensitiveTopics)
+	if err != nil {
+		log.Fatalf("Erro ao carregar temas sensíveis: %v", err)
+	}
+	sensitiveQueryClassifier := services.NewSensitiveQueryClassifier(sensitiveTopics)
+	sensitiveQueryHandler := handlers.NewSensitiveQueryHandler(sensitiveQueryClassifier)
+	searchService.SetSensitiveQueryClassifier(sensitiveQueryClassifier)
+	eventBus.Subscribe(searchService.HandleDocumentEvent)
+
+	// Cache de resultados de busca com stale-while-revalidate para queries quentes
+	var searchCache *services.SearchCache
+	if cfg.SearchCacheEnabled {
+		searchCache = services.NewSearchCache(cfg.SearchCacheSize, services.LoadSearchCacheTTLs(cfg.SearchCacheTTLs), cfg.SearchCacheHotHits)
+		searchService.SetResultCache(searchCache)
+		eventBus.Subscribe(searchCache.HandleDocumentEvent)
+	}
+	cacheHandler := handlers.NewCacheHandler(services.NewCacheInspector(cache, searchCache, responseCache))
+
+	// Mini-índice em memória que responde às buscas quando o Typesense está inacessível
+	if cfg.FallbackIndexEnabled {
+		fallbackIndex := services.NewFallbackIndex(typesenseClient.GetClient())
+		fallbackIndex.StartRefreshRoutine(ctx, time.Duration(cfg.FallbackIndexRefreshMinutes)*time.Minute)
+		searchService.SetFallbackIndex(fallbackIndex)
+	}
+
+	// Filtro de palavrões/ofensas nas buscas: apenas busca textual e bloqueio por reincidência
+	var abuseFilter *services.AbuseFilter
+	if cfg.AbuseFilterEnabled {
+		abuseFilter = services.NewAbuseFilter(
+			typesenseClient.GetClient(),
+			cfg.AbuseIncidentThreshold,
+			time.Duration(cfg.AbuseIncidentWindow)*time.Minute,
+			time.Duration(cfg.AbuseBlockMinutes)*time.Minute,
+		)
+		abuseFilter.StartRefreshRoutine(ctx, 5*time.Minute)
+	}
+	abuseFilterHandler := handlers.NewAbuseFilterHandler(abuseFilter)
+
 	// Initialize category services
 	popularityService := services.NewPopularityService()
 	categoryService := services.NewCategoryService(typesenseClient.GetClient(), popularityService)
+	if cfg.CategoryStatsDebounce > 0 {
+		// Contagens por categoria recontadas só nas categorias afetadas por cada escrita
+		categoryStats := services.NewCategoryStats(typesenseClient.GetClient(), time.Duration(cfg.CategoryStatsDebounce)*time.Second)
+		categoryService.SetStats(categoryStats)
+		eventBus.Subscribe(categoryStats.HandleDocumentEvent)
+	}
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	categoryFeedHandler := handlers.NewCategoryFeedHandler(services.NewCategoryFeedService(typesenseClient.GetClient()))
+
+	// Catálogo A–Z dos serviços publicados, agrupado em memória
+	serviceCatalog := services.NewServiceCatalog(typesenseClient.GetClient(), 10*time.Minute)
+	eventBus.Subscribe(serviceCatalog.HandleDocumentEvent)
+	catalogHandler := handlers.NewCatalogHandler(serviceCatalog)
+
+	// O relay assina por último para retransmitir o evento só após a invalidação local
+	if redisClient != nil {
+		services.NewInvalidationRelay(eventBus, redisClient).Start()
+	}
+
+	// Rotinas agendadas rodam uma vez por horário no cluster (sem Redis, em cada réplica), com
+	// histórico de execuções na collection _jobs
+	var jobLocker jobs.Locker = jobs.NewLocalLocker()
+	if redisClient != nil {
+		jobLocker = jobs.NewRedisLocker(redisClient)
+	}
+	jobRunner, err := jobs.NewRunner(jobLocker, jobs.NewTypesenseStore(typesenseClient.GetClient()), cfg.JobSchedules)
+	if err != nil {
+		log.Fatalf("Erro ao configurar jobs: %v", err)
+	}
+	jobsHandler := handlers.NewJobsHandler(jobRunner)
+
+	// Jornadas de busca por sessão anônima (refinamentos de query até o clique)
+	var journeyAnalytics *services.JourneyAnalytics
+	if cfg.JourneyAnalyticsEnabled {
+		journeyAnalytics = services.NewJourneyAnalytics(typesenseClient.GetClient())
+		if err := journeyAnalytics.StartRoutines(jobRunner, time.Minute, cfg.JourneyRetentionDays); err != nil {
+			log.Fatalf("Erro ao agendar job: %v", err)
+		}
+	}
+	searchHandler.SetJourneyAnalytics(journeyAnalytics)
+	journeyHandler := handlers.NewJourneyHandler(journeyAnalytics)
+
+	// Exportação anonimizada dos eventos de busca para a plataforma de dados
+	var analyticsExporter *services.AnalyticsExporter
+	if journeyAnalytics != nil {
+		analyticsExporter = services.NewAnalyticsExporter(typesenseClient.GetClient(), attachmentStorage, cfg.AnalyticsExportSalt, cfg.AnalyticsExportMaxEvents)
+	}
+	analyticsExportHandler := handlers.NewAnalyticsExportHandler(analyticsExporter)
+
+	// Candidatos a sinônimo minerados das reformulações de query seguidas de clique
+	var synonymMiner *services.SynonymMiner
+	if journeyAnalytics != nil {
+		synonymMiner = services.NewSynonymMiner(typesenseClient.GetClient(), journeyAnalytics, cfg.SynonymMiningMinSessions)
+		if err := synonymMiner.StartRoutine(jobRunner); err != nil {
+			log.Fatalf("Erro ao agendar job: %v", err)
+		}
+	}
+	synonymHandler := handlers.NewSynonymHandler(synonymMiner)
+
+	// Queries relacionadas pela coocorrência nas sessões ("quem buscou isto também buscou")
+	var relatedQueries *services.RelatedQueries
+	if journeyAnalytics != nil {
+		relatedQueries = services.NewRelatedQueries(typesenseClient.GetClient(), journeyAnalytics, cfg.RelatedQueriesMinSessions)
+		if err := relatedQueries.StartRoutines(jobRunner, time.Hour); err != nil {
+			log.Fatalf("Erro ao agendar job: %v", err)
+		}
+	}
+	searchHandler.SetRelatedQueries(relatedQueries)
+	relatedQueriesHandler := handlers.NewRelatedQueriesHandler(relatedQueries)
+
+	// Uso por API key de tenant: chamadas, latência e erros por rota
+	var apiKeyUsage *services.APIKeyUsage
+	if cfg.MultiTenant() && cfg.APIKeyUsageEnabled {
+		apiKeyUsage = services.NewAPIKeyUsage(typesenseClient.GetClient())
+		if err := apiKeyUsage.StartRoutines(jobRunner, time.Minute, cfg.APIKeyUsageRetentionDays); err != nil {
+			log.Fatalf("Erro ao agendar job: %v", err)
+		}
+		r.Use(middlewares.APIKeyUsage(cfg, apiKeyUsage))
+	}
+
+	// Quotas diárias flexíveis por API key, com burst e aumentos temporários
+	var apiKeyQuotas *services.APIKeyQuotas
+	if cfg.MultiTenant() {
+		var quotaCounter services.QuotaCounter = services.NewLocalQuotaCounter()
+		if redisClient != nil {
+			quotaCounter = services.NewRedisQuotaCounter(redisClient)
+		}
+		apiKeyQuotas = services.NewAPIKeyQuotas(typesenseClient.GetClient(), quotaCounter, cfg.APIKeyDailyQuota, cfg.APIKeyQuotaBurstPercent, cfg.APIKeyQuotaWarnPercent)
+		apiKeyQuotas.StartRefreshRoutine(ctx, time.Minute)
+		r.Use(middlewares.APIKeyQuota(cfg, apiKeyQuotas))
+	}
+	apiKeyHandler := handlers.NewAPIKeyHandler(cfg, apiKeyUsage, apiKeyQuotas)
+
+	// Notícias e eventos da cidade sincronizados do CMS da redação
+	if cfg.NewsroomCMSURL != "" {
+		newsroomClient := newsroom.NewClient(cfg.NewsroomCMSURL, cfg.NewsroomCMSToken)
+		if err := typesenseClient.StartNoticiasSyncRoutine(jobRunner, time.Duration(cfg.NewsroomSyncInterval)*time.Minute, newsroomClient); err != nil {
+			log.Fatalf("Erro ao agendar job: %v", err)
+		}
+	}
+
+	// Métricas do cluster Typesense para os painéis de operação
+	indexStatsHandler := handlers.NewIndexStatsHandler(services.NewIndexStatsService(typesenseClient.GetClient(), 15*time.Second))
+	aliasHandler := handlers.NewAliasHandler(services.NewAliases(typesenseClient.GetClient(), configStore))
+
+	// Pré-visualização dos campos markdown dos serviços
+	markdownPreviewHandler := handlers.NewMarkdownPreviewHandler(services.NewMarkdownPreviewer())
 
 	// Initialize subcategory services
 	subcategoryService := services.NewSubcategoryService(typesenseClient.GetClient(), popularityService)
 	subcategoryHandler := handlers.NewSubcategoryHandler(subcategoryService)
 
+	// Classificação automática de categoria ao salvar serviços
+	categoryClassifier := services.NewCategoryClassifier(typesenseClient.GetClient(), geminiClient, "gemini-2.5-flash", popularityService, subcategoryService)
+	categorySuggestionHandler := handlers.NewCategorySuggestionHandler(typesenseClient, categoryClassifier)
+
+	// Extração de entidades (prazos, valores, documentos, órgãos) ao salvar serviços
+	entityExtractor := services.NewEntityExtractor(geminiClient, "gemini-2.5-flash")
+	entityHandler := handlers.NewEntityHandler(typesenseClient, entityExtractor, eventBus)
+
+	// Schemas de extra_fields por tema, validados ao salvar serviços
+	extraFieldSchemas := services.NewExtraFieldSchemas(typesenseClient.GetClient())
+	extraFieldSchemas.StartRefreshRoutine(ctx, 5*time.Minute)
+	extraFieldSchemaHandler := handlers.NewExtraFieldSchemaHandler(extraFieldSchemas)
+
+	// Lista canônica de bairros: filtro bairro da busca e bairros atendidos pelos serviços
+	neighborhoods := services.NewNeighborhoods(typesenseClient.GetClient())
+	neighborhoods.StartRefreshRoutine(ctx, 5*time.Minute)
+	bairroHandler := handlers.NewBairroHandler(neighborhoods)
+	searchHandler.SetNeighborhoods(neighborhoods)
+
+	adminHandler := handlers.NewAdminHandler(typesenseClient, categoryClassifier, entityExtractor, cfg.AvailabilityWarningDays)
+	adminHandler.SetExtraFieldSchemas(extraFieldSchemas)
+	adminHandler.SetNeighborhoods(neighborhoods)
+
+	// Diretório de órgãos: valida o orgao_gestor dos serviços ao salvar
+	orgaos := services.NewOrgaos(typesenseClient.GetClient())
+	orgaos.StartRefreshRoutine(ctx, 5*time.Minute)
+	orgaoHandler := handlers.NewOrgaoHandler(orgaos)
+	adminHandler.SetOrgaos(orgaos)
+
+	// Validação em lote, sem gravar, dos serviços enviados por CMS externos
+	serviceValidationHandler := handlers.NewServiceValidationHandler(services.NewServiceValidator(typesenseClient.GetClient(), categoryClassifier, extraFieldSchemas, neighborhoods, orgaos))
+
+	// Transporte público próximo dos canais presenciais, geocodificados ao salvar serviços
+	if transportEnricher, err := services.NewTransportEnricherFromConfig(cfg); err != nil {
+		log.Printf("Aviso: enriquecimento de transporte desabilitado: %v", err)
+	} else if transportEnricher != nil {
+		adminHandler.SetTransportEnricher(transportEnricher)
+	}
+	contentHandler := handlers.NewContentHandler(typesenseClient)
+
+	// Traduções dos serviços (automática via Gemini + revisão humana)
+	translationService := services.NewTranslationService(typesenseClient.GetClient(), geminiClient, "gemini-2.5-flash", cfg.TranslationLanguages)
+	translationHandler := handlers.NewTranslationHandler(typesenseClient, translationService)
+
 	// Initialize v2 search service (multi-collection)
 	var embeddingService services.EmbeddingProvider
 	if geminiClient != nil {
 		embeddingService = services.NewGeminiEmbeddingProvider(geminiClient, cfg.GeminiEmbeddingModel, cache)
+
+		// Embeddings pré-computados das queries mais frequentes (refresh semanal)
+		if cfg.PrecomputedEmbeddingsTopN > 0 {
+			queryEmbeddings := services.NewQueryEmbeddingStore(typesenseClient.GetClient(), embeddingService, cfg.PrecomputedEmbeddingsTopN)
+			queryEmbeddings.StartRoutines(5*time.Minute, 7*24*time.Hour)
+			embeddingService = services.NewPrecomputedEmbeddingProvider(embeddingService, queryEmbeddings)
+			searchService.SetEmbeddingProvider(embeddingService)
+		}
 	}
+
+	// Temas de demanda sem cobertura: agrupamento semanal das queries sem resultados
+	var contentGapService *services.ContentGapService
+	if journeyAnalytics != nil && embeddingService != nil {
+		contentGapService = services.NewContentGapService(typesenseClient.GetClient(), embeddingService, geminiClient, "gemini-2.5-flash", cfg.ContentGapsDays, cfg.ContentGapsMaxQueries)
+		if err := contentGapService.StartWeeklyRoutine(jobRunner); err != nil {
+			log.Fatalf("Erro ao agendar job: %v", err)
+		}
+	}
+	contentGapHandler := handlers.NewContentGapHandler(contentGapService)
 	searchServiceV2 := services.NewSearchServiceV2(
 		typesenseClient.GetClient(),
 		embeddingService,
 		cfg,
 	)
+	searchServiceV2.SetSensitiveQueryClassifier(sensitiveQueryClassifier)
+	searchServiceV2.SetConfigStore(configStore)
+
+	// Buscas públicas na réplica somente leitura, isoladas da carga de reindexação no primário
+	if cfg.TypesenseReplicaURL != "" {
+		replicaRouter := services.NewReplicaRouter(typesenseClient.GetClient(), cfg.TypesenseReplicaURL, cfg.TypesenseReplicaAPIKey)
+		replicaRouter.StartHealthCheck(time.Duration(cfg.TypesenseReplicaHealthInterval) * time.Second)
+		searchService.SetReplicaRouter(replicaRouter)
+		searchServiceV2.SetReplicaRouter(replicaRouter)
+	}
+
+	// Buscas em inglês, espanhol etc. são traduzidas para o português antes de consultar o índice
+	if cfg.QueryTranslationEnabled && geminiClient != nil {
+		queryTranslator := services.NewQueryTranslator(geminiClient, "gemini-2.5-flash", cache)
+		searchService.SetQueryTranslator(queryTranslator)
+		searchServiceV2.SetQueryTranslator(queryTranslator)
+	}
 	searchHandlerV2 := handlers.NewSearchHandlerV2(searchServiceV2)
+	searchHandlerV2.SetJourneyAnalytics(journeyAnalytics)
+	searchHandlerV2.SetRelatedQueries(relatedQueries)
+	searchHandlerV2.SetNeighborhoods(neighborhoods)
 
 	// Initialize migration services
 	schemaRegistry := schemas.NewRegistry()
 	migrationService := services.NewMigrationService(typesenseClient.GetClient(), schemaRegistry)
+	migrationService.SetExtraFieldSchemas(extraFieldSchemas)
 	migrationHandler := handlers.NewMigrationHandler(migrationService, schemaRegistry)
+	opsHandler := handlers.NewOpsHandler(services.NewOpsService(typesenseClient.GetClient(), migrationService, jobRunner, eventBus))
 	migrationLockMiddleware := middlewares.NewMigrationLockMiddleware(migrationService)
 
+	// Initialize freshness report (relatório noturno de atualização de conteúdo)
+	webhookNotifier := services.NewWebhookNotifier(cfg.NotificationWebhookURL)
+	freshnessService := services.NewFreshnessService(typesenseClient.GetClient(), webhookNotifier)
+	freshnessService.SetJourneyAnalytics(journeyAnalytics)
+	freshnessHandler := handlers.NewFreshnessHandler(freshnessService, cfg.FreshnessStaleMonths)
+	if cfg.FreshnessReportEnabled {
+		if err := freshnessService.StartNightlyRoutine(jobRunner, cfg.FreshnessReportHour, cfg.FreshnessStaleMonths); err != nil {
+			log.Fatalf("Erro ao agendar job: %v", err)
+		}
+	}
+
+	// Relatório noturno de serviços duplicados (embeddings quase idênticos)
+	duplicateReportService := services.NewDuplicateReportService(typesenseClient.GetClient())
+	duplicatesHandler := handlers.NewDuplicatesHandler(duplicateReportService, typesenseClient, eventBus, cfg.DuplicateReportThreshold)
+	if cfg.DuplicateReportEnabled {
+		if err := duplicateReportService.StartNightlyRoutine(jobRunner, cfg.DuplicateReportHour, cfg.DuplicateReportThreshold); err != nil {
+			log.Fatalf("Erro ao agendar job: %v", err)
+		}
+	}
+
+	// Relatório de atividade editorial (edições por semana e rascunhos parados)
+	activityService := services.NewActivityService(typesenseClient.GetClient())
+	activityHandler := handlers.NewActivityHandler(activityService, cfg.StaleDraftDays)
+
+	// Comentários editoriais internos dos serviços (menções notificadas via webhook)
+	commentService := services.NewCommentService(typesenseClient.GetClient(), webhookNotifier)
+	commentHandler := handlers.NewCommentHandler(typesenseClient, commentService)
+
+	// Initialize owner digests (resumos periódicos por órgão gestor)
+	mailer := services.NewMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPassword, cfg.SMTPFrom)
+	digestService := services.NewDigestService(typesenseClient.GetClient(), freshnessService, webhookNotifier, mailer, cfg.DigestSubscriptions, cfg.DigestDefaultFrequency)
+	digestHandler := handlers.NewDigestHandler(digestService)
+	if len(cfg.DigestSubscriptions) > 0 || cfg.DigestDefaultFrequency != "" {
+		if err := digestService.StartRoutine(jobRunner, cfg.DigestHour); err != nil {
+			log.Fatalf("Erro ao agendar job: %v", err)
+		}
+	}
+
+	// Reconciliação do histórico de versões: versões pendentes de criações interrompidas e
+	// serviços sem versão 1
+	if err := typesenseClient.StartVersionReconcileRoutine(jobRunner); err != nil {
+		log.Fatalf("Erro ao agendar job: %v", err)
+	}
+
+	// Campos plaintext consultados pela busca textual, para serviços indexados antes deles
+	// existirem ou alterados fora da API (após o deploy, disparar o job em /admin/jobs)
+	if err := typesenseClient.StartPlaintextBackfillRoutine(jobRunner); err != nil {
+		log.Fatalf("Erro ao agendar job: %v", err)
+	}
+
+	// Despublicação automática dos serviços descontinuados com sunset vencido
+	if cfg.SunsetCheckInterval > 0 {
+		err := typesenseClient.StartSunsetRoutine(jobRunner, time.Duration(cfg.SunsetCheckInterval)*time.Minute, func(ctx context.Context, serviceID string) {
+			eventBus.Publish(ctx, services.DocumentEvent{
+				Type:       services.DocumentUpdated,
+				Collection: services.PrefRioServicesCollection,
+				DocumentID: serviceID,
+			})
+		})
+		if err != nil {
+			log.Fatalf("Erro ao agendar job: %v", err)
+		}
+	}
+
+	// Janelas de disponibilidade dos serviços sazonais: despublica no fim, republica na abertura
+	if cfg.AvailabilityCheckInterval > 0 {
+		warnWithin := time.Duration(cfg.AvailabilityWarningDays) * 24 * time.Hour
+		err := typesenseClient.StartAvailabilityRoutine(jobRunner, time.Duration(cfg.AvailabilityCheckInterval)*time.Minute, warnWithin, webhookNotifier, func(ctx context.Context, serviceID string) {
+			eventBus.Publish(ctx, services.DocumentEvent{
+				Type:       services.DocumentUpdated,
+				Collection: services.PrefRioServicesCollection,
+				DocumentID: serviceID,
+			})
+		})
+		if err != nil {
+			log.Fatalf("Erro ao agendar job: %v", err)
+		}
+	}
+
+	// Solicitações do titular de dados (LGPD): exportação e eliminação por CPF
+	privacyService := services.NewPrivacyService(typesenseClient.GetClient(), services.DefaultPrivacySources(), cfg.PrivacyReceiptSecret)
+	privacyHandler := handlers.NewPrivacyHandler(privacyService)
+
+	// Initialize consistency checker
+	consistencyService := services.NewConsistencyService(typesenseClient.GetClient())
+	consistencyHandler := handlers.NewConsistencyHandler(consistencyService)
+
+	// Desativação das collections legadas: relatório e congelamento (LEGACY_COLLECTIONS_FROZEN)
+	legacySunsetHandler := handlers.NewLegacySunsetHandler(services.NewLegacySunsetService(typesenseClient.GetClient(), journeyAnalytics, cfg))
+	adminHandler.SetLegacyFrozen(cfg.LegacyCollectionsFrozen)
+
+	// Alterações de serviços enviadas pelo CMS de origem (CMS_WEBHOOK_SECRET)
+	cmsWebhookHandler := handlers.NewCMSWebhookHandler(adminHandler, eventBus, cfg.CMSWebhookSecret)
+
 	// Initialize health handler
-	healthHandler := handlers.NewHealthHandler(typesenseClient)
+	healthHandler := handlers.NewHealthHandler(typesenseClient, searchService, degradations)
+
+	// Especificação OpenAPI (gerada das anotações swag) para geração de clientes
+	openAPIHandler := handlers.NewOpenAPIHandler()
 
 	// Health check endpoints (no /api/v1 prefix for K8s probes and uptime monitoring)
 	r.GET("/liveness", healthHandler.Liveness)   // K8s liveness probe
 	r.GET("/readiness", healthHandler.Readiness) // K8s readiness probe
 	r.GET("/health", healthHandler.Health)       // Uptime monitoring (comprehensive)
+	r.GET("/health/degradations", healthHandler.Degradations)
 
 	// v1 API (services only - backward compatibility)
 	api := r.Group("/api/v1")
 	{
+		// Especificação OpenAPI
+		api.GET("/openapi.json", openAPIHandler.GetSpec)
+
 		// Unified search endpoints
-		api.GET("/search", searchHandler.Search)
-		api.GET("/search/:id", searchHandler.GetDocumentByID)
+		api.GET("/search", middlewares.AbuseFilter(abuseFilter), searchHandler.Search)
+		api.GET("/search/:id", middlewares.CacheResponse(responseCache), searchHandler.GetDocumentByID)
+		api.GET("/search/:id/attachments/:attachment_id", attachmentHandler.DownloadAttachment)
+		api.GET("/related-queries", relatedQueriesHandler.GetRelatedQueries)
 
 		// SEO-friendly service endpoint (by slug)
-		api.GET("/services/:slug", searchHandler.GetServiceBySlug)
+		api.GET("/services/:slug", middlewares.CacheResponse(responseCache), searchHandler.GetServiceBySlug)
+
+		// Histórico público de alterações (aceita ID ou slug)
+		api.GET("/services/:slug/changelog", middlewares.CacheResponse(responseCache), versionHandler.GetPublicChangelog)
+
+		// Card do serviço para o chatbot do WhatsApp (aceita ID ou slug)
+		api.GET("/services/:slug/whatsapp-card", middlewares.CacheResponse(responseCache), whatsAppCardHandler.GetWhatsAppCard)
+
+		// Ficha do serviço em PDF para o atendimento presencial (aceita ID ou slug)
+		api.GET("/services/:slug/pdf", servicePDFHandler.GetServicePDF)
 
 		// Category endpoints
-		api.GET("/categories", categoryHandler.GetCategories)
+		api.GET("/categories", middlewares.CacheResponse(responseCache), categoryHandler.GetCategories)
+
+		// Feed RSS de serviços novos ou atualizados por categoria
+		api.GET("/categories/:category/feed.xml", categoryFeedHandler.GetCategoryFeed)
+
+		// Catálogo A–Z de serviços
+		api.GET("/catalog", middlewares.CacheResponse(responseCache), catalogHandler.GetCatalog)
 
 		// Subcategory endpoints
-		api.GET("/categories/:category/subcategories", subcategoryHandler.GetSubcategories)
-		api.GET("/subcategories/:subcategory/services", subcategoryHandler.GetServicesBySubcategory)
+		api.GET("/categories/:category/subcategories", middlewares.CacheResponse(responseCache), subcategoryHandler.GetSubcategories)
+		api.GET("/subcategories/:subcategory/services", middlewares.CacheResponse(responseCache), subcategoryHandler.GetServicesBySubcategory)
+
+		// Cliques nos resultados, para as jornadas de busca
+		api.POST("/analytics/click", journeyHandler.RecordClick)
+
+		// Webhook assinado (HMAC) do CMS de origem, bloqueado durante migrações
+		api.POST("/ingest/cms-webhook", middlewares.MaxBodySize(int64(cfg.AdminMaxBodyKB)<<10, nil), migrationLockMiddleware.BlockCUD(), cmsWebhookHandler.Receive)
+
+		// Bairros aceitos no filtro bairro da busca
+		api.GET("/bairros", bairroHandler.ListBairros)
+		api.GET("/bairros/resolve", bairroHandler.ResolveBairro)
+
+		// Diretório de órgãos
+		api.GET("/orgaos", orgaoHandler.ListOrgaos)
+		api.GET("/orgaos/:sigla/services", middlewares.CacheResponse(responseCache), orgaoHandler.GetOrgaoServices)
 	}
 
 	// v2 API (multi-collection search)
 	apiV2 := r.Group("/api/v2")
 	{
 		// Multi-collection search endpoints
-		apiV2.GET("/search", searchHandlerV2.Search)
+		apiV2.GET("/search", middlewares.AbuseFilter(abuseFilter), searchHandlerV2.Search)
 		apiV2.GET("/search/:id", searchHandlerV2.GetDocumentByID)
 	}
 
+	// Solicitações do titular de dados (LGPD) com autenticação JWT
+	privacy := api.Group("/privacy")
+	privacy.Use(middlewares.JWTAuthMiddleware())
+	privacy.Use(middlewares.RequireJWTAuth())
+	{
+		privacy.POST("/export", privacyHandler.ExportData)
+		privacy.POST("/delete", privacyHandler.DeleteData)
+		privacy.GET("/receipts/:id", privacyHandler.GetReceipt)
+	}
+
 	// Rotas administrativas com autenticação JWT
 	admin := api.Group("/admin")
 	admin.Use(middlewares.JWTAuthMiddleware()) // Extrai dados do JWT
 	admin.Use(middlewares.RequireJWTAuth())    // Verifica apenas se está autenticado
+	admin.Use(middlewares.MaxBodySize(int64(cfg.AdminMaxBodyKB)<<10, map[string]int64{
+		// Uploads de anexos têm limite próprio (validado também no handler)
+		"/api/v1/admin/services/:id/attachments": int64(cfg.AttachmentMaxSizeMB+1) << 20,
+		// Importação em lote recebe centenas de serviços
+		"/api/v1/admin/services/bulk": int64(cfg.BulkImportMaxBodyMB) << 20,
+	}))
 	{
 		// Rotas de serviços com bloqueio de CUD durante migrações
 		servicesGroup := admin.Group("/services")
 		servicesGroup.Use(migrationLockMiddleware.BlockCUD()) // Bloqueia CUD durante migrações
+		servicesGroup.Use(middlewares.PublishDocumentEvents(eventBus, services.PrefRioServicesCollection))
 		{
 			// Criar serviço
 			servicesGroup.POST("", adminHandler.CreateService)
@@ -132,6 +584,27 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 			// Listar serviços (GET não é bloqueado)
 			servicesGroup.GET("", adminHandler.ListServices)
 
+			// Exportar serviços em streaming (GET não é bloqueado)
+			servicesGroup.GET("/export", exportHandler.ExportServices)
+
+			// Importação de serviços em lote (array JSON ou NDJSON, com dry-run)
+			servicesGroup.POST("/bulk", adminHandler.BulkImportServices)
+
+			// Transição de status em lote (publish/unpublish/archive, com dry-run)
+			servicesGroup.POST("/bulk-status", adminHandler.BulkUpdateStatus)
+
+			// Serviços sazonais cuja janela de disponibilidade encerra em breve
+			servicesGroup.GET("/availability/closing", adminHandler.ListClosingAvailabilityWindows)
+
+			// Regeneração dos deep links por canal (após mudar templates ou UTMs)
+			servicesGroup.POST("/deep-links/backfill", adminHandler.RefreshDeepLinks)
+
+			// Backfill dos campos plaintext indexados (serviços gravados antes da persistência)
+			servicesGroup.POST("/plaintext/backfill", adminHandler.RefreshPlaintextFields)
+
+			// Backfill das entidades extraídas
+			servicesGroup.POST("/entities/backfill", entityHandler.BackfillEntities)
+
 			// Buscar serviço por ID (GET não é bloqueado)
 			servicesGroup.GET("/:id", adminHandler.GetService)
 
@@ -147,6 +620,32 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 			// Despublicar serviço
 			servicesGroup.PATCH("/:id/unpublish", adminHandler.UnpublishService)
 
+			// Descontinuar serviço / remover descontinuação
+			servicesGroup.PATCH("/:id/deprecate", adminHandler.DeprecateService)
+			servicesGroup.PATCH("/:id/undeprecate", adminHandler.UndeprecateService)
+
+			// Mesclar um serviço duplicado (origem) no serviço de destino, com dry-run
+			servicesGroup.POST("/:id/merge/:sourceId", duplicatesHandler.MergeServices)
+
+			// Entidades extraídas da descrição
+			servicesGroup.POST("/:id/entities", entityHandler.ExtractEntities)
+
+			// Sugestão automática de categoria
+			servicesGroup.GET("/:id/category-suggestion", categorySuggestionHandler.GetSuggestion)
+			servicesGroup.POST("/:id/category-suggestion", categorySuggestionHandler.ClassifyService)
+			servicesGroup.POST("/:id/category-suggestion/accept", categorySuggestionHandler.AcceptSuggestion)
+			servicesGroup.POST("/:id/category-suggestion/reject", categorySuggestionHandler.RejectSuggestion)
+
+			// Traduções do serviço
+			servicesGroup.GET("/:id/translations", translationHandler.ListTranslations)
+			servicesGroup.POST("/:id/translations/:lang/machine", translationHandler.MachineTranslate)
+			servicesGroup.PUT("/:id/translations/:lang/review", translationHandler.ReviewTranslation)
+
+			// Anexos do serviço
+			servicesGroup.GET("/:id/attachments", attachmentHandler.ListAttachments)
+			servicesGroup.POST("/:id/attachments", attachmentHandler.UploadAttachment)
+			servicesGroup.DELETE("/:id/attachments/:attachment_id", attachmentHandler.DeleteAttachment)
+
 			// Rotas de versionamento (GET não é bloqueado)
 			servicesGroup.GET("/:id/versions", versionHandler.ListServiceVersions)
 			servicesGroup.GET("/:id/versions/:version", versionHandler.GetServiceVersion)
@@ -154,9 +653,30 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 			servicesGroup.POST("/:id/rollback", versionHandler.RollbackService)
 		}
 
+		// Edição em massa do conteúdo dos serviços (bloqueada durante migrações)
+		content := admin.Group("/content")
+		content.Use(migrationLockMiddleware.BlockCUD())
+		content.Use(middlewares.PublishDocumentEvents(eventBus, services.PrefRioServicesCollection))
+		{
+			// Localizar e substituir (pré-visualização ou aplicação)
+			content.POST("/replace", contentHandler.ReplaceContent)
+		}
+
+		// Comentários editoriais (collection própria: não bloqueados por migrações nem invalidam o cache)
+		comments := admin.Group("/services/:id/comments")
+		{
+			comments.GET("", commentHandler.ListComments)
+			comments.POST("", commentHandler.CreateComment)
+			comments.PUT("/:comment_id", commentHandler.UpdateComment)
+			comments.DELETE("/:comment_id", commentHandler.DeleteComment)
+		}
+
 		// Rotas de tombamentos com bloqueio de CUD durante migrações
 		tombamentos := admin.Group("/tombamentos")
 		tombamentos.Use(migrationLockMiddleware.BlockCUD()) // Bloqueia CUD durante migrações
+		// Somente leitura com as collections legadas congeladas (LEGACY_COLLECTIONS_FROZEN)
+		tombamentos.Use(middlewares.LegacyFreeze(cfg.LegacyCollectionsFrozen))
+		tombamentos.Use(middlewares.PublishDocumentEvents(eventBus, services.TombamentosCollection))
 		{
 			// Criar tombamento
 			tombamentos.POST("", tombamentoHandler.CreateTombamento)
@@ -195,6 +715,143 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 			// Listar schemas disponíveis
 			migration.GET("/schemas", migrationHandler.ListSchemas)
 		}
+
+		// Verificação de consistência entre collections (fix bloqueado durante migrações)
+		consistency := admin.Group("/consistency")
+		consistency.Use(migrationLockMiddleware.BlockCUD())
+		{
+			consistency.GET("", consistencyHandler.Check)
+			consistency.POST("/fix", consistencyHandler.Fix)
+		}
+
+		// Lista de termos do filtro de abuso das buscas
+		abuse := admin.Group("/abuse-filter")
+		{
+			abuse.GET("/words", abuseFilterHandler.GetWords)
+			abuse.PUT("/words", abuseFilterHandler.UpdateWords)
+		}
+
+		// Ações de recuperação do plantão (com token de confirmação e auditoria)
+		opsGroup := admin.Group("/ops")
+		{
+			opsGroup.GET("/audit", opsHandler.ListAudit)
+			opsGroup.POST("/:action", opsHandler.ExecuteAction)
+		}
+
+		// Rotinas agendadas: situação, histórico, execução manual e pausa
+		jobsGroup := admin.Group("/jobs")
+		{
+			jobsGroup.GET("", jobsHandler.ListJobs)
+			jobsGroup.GET("/:name/runs", jobsHandler.ListRuns)
+			jobsGroup.POST("/:name/run", jobsHandler.TriggerJob)
+			jobsGroup.POST("/:name/pause", jobsHandler.PauseJob)
+			jobsGroup.POST("/:name/resume", jobsHandler.ResumeJob)
+		}
+
+		// Schemas de extra_fields por tema
+		extraFieldsGroup := admin.Group("/extra-fields/schemas")
+		{
+			extraFieldsGroup.GET("", extraFieldSchemaHandler.ListSchemas)
+			extraFieldsGroup.GET("/:tema", extraFieldSchemaHandler.GetSchema)
+			extraFieldsGroup.PUT("/:tema", extraFieldSchemaHandler.SaveSchema)
+			extraFieldsGroup.DELETE("/:tema", extraFieldSchemaHandler.DeleteSchema)
+		}
+
+		// Jornadas de busca: caminhos de refinamento mais comuns
+		admin.GET("/analytics/journeys", journeyHandler.GetJourneys)
+		admin.GET("/analytics/export", analyticsExportHandler.ExportAnalytics)
+		admin.GET("/analytics/exports/:id", analyticsExportHandler.GetAnalyticsExport)
+		admin.GET("/analytics/content-gaps", contentGapHandler.GetLatestReport)
+
+		// Fila de candidatos a sinônimo para aprovação humana
+		admin.GET("/synonyms/candidates", synonymHandler.ListCandidates)
+		admin.POST("/synonyms/candidates/:id/approve", synonymHandler.ApproveCandidate)
+		admin.POST("/synonyms/candidates/:id/reject", synonymHandler.RejectCandidate)
+
+		// Lista canônica de bairros
+		admin.PUT("/bairros", bairroHandler.ImportBairros)
+
+		// Diretório de órgãos
+		admin.GET("/orgaos", orgaoHandler.ListOrgaos)
+		admin.PUT("/orgaos/:sigla", orgaoHandler.SaveOrgao)
+		admin.DELETE("/orgaos/:sigla", orgaoHandler.DeleteOrgao)
+
+		// API keys dos tenants e uso por key
+		admin.GET("/api-keys", apiKeyHandler.ListAPIKeys)
+		admin.GET("/api-keys/:id/usage", apiKeyHandler.GetAPIKeyUsage)
+		admin.GET("/api-keys/:id/quota", apiKeyHandler.GetAPIKeyQuota)
+		admin.PUT("/api-keys/:id/quota", apiKeyHandler.SetAPIKeyQuota)
+		admin.POST("/api-keys/:id/quota/boosts", apiKeyHandler.GrantQuotaBoost)
+		admin.DELETE("/api-keys/:id/quota/boosts/:boost_id", apiKeyHandler.RevokeQuotaBoost)
+
+		// Métricas do índice (memória, disco, latência e tamanho das collections)
+		admin.GET("/index/stats", indexStatsHandler.GetStats)
+
+		// Export completo de qualquer collection em JSONL (backups fora das migrações)
+		admin.GET("/collections/:name/export", exportHandler.ExportCollection)
+
+		// Aliases do Typesense (aliases em uso pela configuração têm salvaguardas; alterações
+		// bloqueadas durante migrações, que também reapontam aliases)
+		aliases := admin.Group("/aliases")
+		aliases.Use(migrationLockMiddleware.BlockCUD())
+		{
+			aliases.GET("", aliasHandler.ListAliases)
+			aliases.POST("", aliasHandler.SaveAlias)
+			aliases.DELETE("/:name", aliasHandler.DeleteAlias)
+		}
+
+		// Validação em lote sem gravar (fora do grupo de serviços: não é escrita e não é
+		// bloqueada durante migrações)
+		admin.POST("/services/validate", serviceValidationHandler.ValidateServices)
+
+		// Recarga dos parâmetros de busca (collections, orçamentos, alpha, thresholds)
+		admin.POST("/config/reload", configHandler.ReloadConfig)
+
+		// Situação dos caches de busca e descarte seletivo de entradas
+		cacheGroup := admin.Group("/cache")
+		{
+			cacheGroup.GET("/stats", cacheHandler.GetStats)
+			cacheGroup.DELETE("/keys", cacheHandler.DeleteKeys)
+		}
+
+		// Formulário de serviço gerado do modelo e da validação, para o back-office
+		admin.GET("/meta/service-form", adminHandler.GetServiceForm)
+
+		// Pré-visualização de markdown com o renderizador dos portais
+		preview := admin.Group("/preview")
+		{
+			preview.POST("/markdown", markdownPreviewHandler.PreviewMarkdown)
+		}
+
+		// Relatórios
+		reports := admin.Group("/reports")
+		{
+			// Relatório de atualização de conteúdo
+			reports.GET("/freshness", freshnessHandler.GetLatestReport)
+			reports.POST("/freshness", freshnessHandler.GenerateReport)
+
+			// Grupos de serviços duplicados; a mesclagem é feita em /admin/services/:id/merge/:sourceId
+			reports.GET("/duplicates", duplicatesHandler.GetLatestReport)
+			reports.POST("/duplicates", duplicatesHandler.GenerateReport)
+
+			// Atividade editorial por usuário/órgão e rascunhos parados
+			reports.GET("/activity", activityHandler.GetActivity)
+
+			// Resumos por órgão gestor
+			reports.POST("/digests", digestHandler.GenerateDigests)
+
+			// Taxas de aceite/correção das sugestões de categoria
+			reports.GET("/category-suggestions", categorySuggestionHandler.GetStats)
+
+			// Contagem de buscas sensíveis por tema
+			reports.GET("/sensitive-queries", sensitiveQueryHandler.GetCounts)
+
+			// Buscas ofensivas por cliente e bloqueios vigentes
+			reports.GET("/abuse", abuseFilterHandler.GetIncidents)
+
+			// Documentos legados não tombados, tráfego e lista de tombamento priorizada
+			reports.GET("/legacy-sunset", legacySunsetHandler.GetReport)
+		}
 	}
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -206,7 +863,7 @@ func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-API-Key, X-Session-ID")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
@@ -0,0 +1,54 @@
+package concurrency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter espaça as chamadas a um serviço externo (ex.: embeddings no Gemini) em no máximo N
+// por segundo, compartilhado entre as tarefas do pool
+type Limiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// NewLimiter cria um limitador de perSecond chamadas por segundo. Com perSecond <= 0 retorna
+// nil, que não limita.
+func NewLimiter(perSecond float64) *Limiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &Limiter{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// Wait aguarda a vez da próxima chamada ou o cancelamento do contexto
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	at := l.next
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
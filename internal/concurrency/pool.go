@@ -0,0 +1,165 @@
+// Package concurrency executa tarefas em paralelo com concorrência limitada, cancelamento por
+// contexto, novas tentativas por tarefa e acompanhamento do progresso
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Options configura o pool
+type Options struct {
+	Concurrency int           // tarefas simultâneas (mínimo 1)
+	Retries     int           // novas tentativas de uma tarefa que falhou
+	RetryDelay  time.Duration // espera antes da primeira nova tentativa; dobra a cada tentativa
+	StopOnError bool          // a primeira falha definitiva cancela as demais tarefas
+
+	// Retryable indica se a falha é transitória e merece nova tentativa; as demais são
+	// definitivas na primeira ocorrência. nil considera todas as falhas transitórias.
+	Retryable func(error) bool
+
+	// OnProgress é chamado após cada tarefa concluída, uma chamada por vez
+	OnProgress func(Progress)
+}
+
+// Progress andamento do pool: tarefas enviadas, concluídas e, entre as concluídas, as que
+// falharam após todas as tentativas, além das novas tentativas executadas
+type Progress struct {
+	Submitted int
+	Done      int
+	Failed    int
+	Retries   int
+}
+
+// Pool executa as tarefas enviadas por Go com no máximo Options.Concurrency simultâneas. Go
+// bloqueia enquanto todas as vagas estão ocupadas, de modo que um produtor (ex.: paginação de
+// uma collection) avança no ritmo dos workers e para assim que o contexto é cancelado.
+type Pool struct {
+	parent context.Context
+	ctx    context.Context
+	group  *errgroup.Group
+	opts   Options
+
+	mu       sync.Mutex
+	progress Progress
+	errs     []error
+}
+
+// New cria o pool. O contexto retornado é cancelado quando ctx é cancelado ou, com
+// StopOnError, na primeira falha definitiva.
+func New(ctx context.Context, opts Options) (*Pool, context.Context) {
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(opts.Concurrency)
+	return &Pool{parent: ctx, ctx: groupCtx, group: group, opts: opts}, groupCtx
+}
+
+// Go envia a tarefa ao pool, bloqueando até haver uma vaga. Retorna o erro do contexto, sem
+// enviar a tarefa, se o pool já foi cancelado.
+func (p *Pool) Go(task func(ctx context.Context) error) error {
+	if err := p.ctx.Err(); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.progress.Submitted++
+	p.mu.Unlock()
+
+	p.group.Go(func() error {
+		err := p.run(task)
+		p.finish(err)
+		if err != nil && p.opts.StopOnError {
+			return err
+		}
+		return nil
+	})
+	return nil
+}
+
+// Wait aguarda as tarefas enviadas. Com StopOnError retorna a primeira falha; sem ele, todas as
+// falhas combinadas (errors.Join). O cancelamento do contexto pai também é retornado.
+func (p *Pool) Wait() error {
+	err := p.group.Wait()
+	if p.opts.StopOnError && err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.errs) > 0 {
+		return errors.Join(p.errs...)
+	}
+	return p.parent.Err()
+}
+
+// Progress retorna o andamento atual
+func (p *Pool) Progress() Progress {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.progress
+}
+
+// Errors retorna as falhas definitivas das tarefas concluídas, na ordem de conclusão
+func (p *Pool) Errors() []error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]error(nil), p.errs...)
+}
+
+// run executa a tarefa com as novas tentativas configuradas, apenas para falhas transitórias
+func (p *Pool) run(task func(ctx context.Context) error) error {
+	delay := p.opts.RetryDelay
+	for attempt := 0; ; attempt++ {
+		if err := p.ctx.Err(); err != nil {
+			return err
+		}
+		err := task(p.ctx)
+		if err == nil || attempt >= p.opts.Retries {
+			return err
+		}
+		if p.opts.Retryable != nil && !p.opts.Retryable(err) {
+			return err
+		}
+
+		select {
+		case <-p.ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+
+		p.mu.Lock()
+		p.progress.Retries++
+		p.mu.Unlock()
+	}
+}
+
+func (p *Pool) finish(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress.Done++
+	if err != nil {
+		p.progress.Failed++
+		p.errs = append(p.errs, err)
+	}
+	if p.opts.OnProgress != nil {
+		p.opts.OnProgress(p.progress)
+	}
+}
+
+// Run executa task para cada item com o pool e aguarda o fim (ver Pool.Wait)
+func Run[T any](ctx context.Context, items []T, opts Options, task func(ctx context.Context, item T) error) error {
+	pool, _ := New(ctx, opts)
+	for _, item := range items {
+		if err := pool.Go(func(ctx context.Context) error { return task(ctx, item) }); err != nil {
+			break
+		}
+	}
+	return pool.Wait()
+}
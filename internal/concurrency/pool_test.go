@@ -0,0 +1,173 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunLimitsConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	var progress []Progress
+	items := make([]int, 20)
+
+	err := Run(context.Background(), items, Options{
+		Concurrency: 3,
+		OnProgress:  func(p Progress) { progress = append(progress, p) },
+	}, func(ctx context.Context, _ int) error {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := peak.Load()
+			if current <= old || peak.CompareAndSwap(old, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if peak.Load() > 3 {
+		t.Errorf("concorrência máxima %d, esperado no máximo 3", peak.Load())
+	}
+	if len(progress) != 20 || progress[19] != (Progress{Submitted: 20, Done: 20}) {
+		t.Errorf("progresso inesperado: %d chamadas, última %+v", len(progress), progress[len(progress)-1])
+	}
+}
+
+func TestRunRetriesAndCollectsFailures(t *testing.T) {
+	var attempts atomic.Int32
+	errFatal := errors.New("falha definitiva")
+
+	err := Run(context.Background(), []string{"instavel", "quebrado", "ok"}, Options{Concurrency: 2, Retries: 2}, func(ctx context.Context, item string) error {
+		switch item {
+		case "instavel":
+			if attempts.Add(1) < 3 {
+				return errors.New("falha temporária")
+			}
+		case "quebrado":
+			return errFatal
+		}
+		return nil
+	})
+	if !errors.Is(err, errFatal) {
+		t.Errorf("esperado errFatal, obtido %v", err)
+	}
+	if attempts.Load() != 3 {
+		t.Errorf("esperadas 3 tentativas, obtidas %d", attempts.Load())
+	}
+}
+
+func TestPoolStopOnErrorCancelsProducer(t *testing.T) {
+	errFatal := errors.New("falha definitiva")
+	pool, ctx := New(context.Background(), Options{Concurrency: 1, StopOnError: true})
+
+	submitted := 0
+	for page := 0; page < 100; page++ {
+		err := pool.Go(func(ctx context.Context) error {
+			if page == 2 {
+				return errFatal
+			}
+			return nil
+		})
+		if err != nil {
+			break
+		}
+		submitted++
+	}
+	if err := pool.Wait(); !errors.Is(err, errFatal) {
+		t.Errorf("esperado errFatal, obtido %v", err)
+	}
+	if ctx.Err() == nil {
+		t.Error("contexto do pool deveria estar cancelado")
+	}
+	if submitted >= 100 {
+		t.Error("o produtor deveria parar após a falha")
+	}
+}
+
+func TestRunCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls atomic.Int32
+	err := Run(ctx, []int{1, 2, 3}, Options{Concurrency: 2}, func(ctx context.Context, _ int) error {
+		calls.Add(1)
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || calls.Load() != 0 {
+		t.Errorf("esperado context.Canceled sem execuções, obtido %v (%d execuções)", err, calls.Load())
+	}
+}
+
+func TestPoolCountsRetries(t *testing.T) {
+	var attempts atomic.Int32
+	pool, _ := New(context.Background(), Options{Concurrency: 2, Retries: 3})
+	pool.Go(func(ctx context.Context) error {
+		if attempts.Add(1) < 3 {
+			return errors.New("falha temporária")
+		}
+		return nil
+	})
+	pool.Go(func(ctx context.Context) error { return errors.New("falha definitiva") })
+	pool.Wait()
+
+	if got := pool.Progress(); got != (Progress{Submitted: 2, Done: 2, Failed: 1, Retries: 5}) {
+		t.Errorf("progresso inesperado: %+v", got)
+	}
+}
+
+func TestPoolRetriesOnlyRetryableErrors(t *testing.T) {
+	errTransient := errors.New("429")
+	var transient, permanent atomic.Int32
+	pool, _ := New(context.Background(), Options{
+		Concurrency: 2,
+		Retries:     3,
+		Retryable:   func(err error) bool { return errors.Is(err, errTransient) },
+	})
+	pool.Go(func(ctx context.Context) error {
+		if transient.Add(1) < 3 {
+			return errTransient
+		}
+		return nil
+	})
+	pool.Go(func(ctx context.Context) error {
+		permanent.Add(1)
+		return errors.New("400")
+	})
+	pool.Wait()
+
+	if transient.Load() != 3 || permanent.Load() != 1 {
+		t.Errorf("tentativas: %d transitória (esperado 3), %d definitiva (esperado 1)", transient.Load(), permanent.Load())
+	}
+	if got := pool.Progress(); got != (Progress{Submitted: 2, Done: 2, Failed: 1, Retries: 2}) {
+		t.Errorf("progresso inesperado: %+v", got)
+	}
+}
+
+func TestLimiterSpacesCalls(t *testing.T) {
+	limiter := NewLimiter(100)
+	start := time.Now()
+	for range 5 {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+	}
+	// a primeira chamada é imediata; as 4 seguintes esperam 10ms cada
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("chamadas deveriam ser espaçadas, 5 chamadas em %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := NewLimiter(0.1).Wait(ctx); err != context.Canceled {
+		t.Errorf("esperado cancelamento, obtido %v", err)
+	}
+	if err := (*Limiter)(nil).Wait(context.Background()); err != nil {
+		t.Errorf("limitador nil não deveria limitar: %v", err)
+	}
+}
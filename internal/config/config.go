@@ -1,24 +1,302 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 // CollectionConfig holds field mapping configuration for a Typesense collection
 type CollectionConfig struct {
-	Type          string   `json:"type"`                     // "service", "course", "job"
-	TitleField    string   `json:"title_field"`              // Field name for title (used in response mapping)
-	DescField     string   `json:"desc_field"`               // Field name for description (used in response mapping)
-	FilterField   string   `json:"filter_field,omitempty"`   // Optional: field to filter by (e.g., "status")
-	FilterValue   string   `json:"filter_value,omitempty"`   // Optional: value to filter for (e.g., "1")
-	SearchFields  []string `json:"search_fields,omitempty"`  // Fields to search (query_by). Falls back to [title_field, desc_field]
-	SearchWeights []int    `json:"search_weights,omitempty"` // Weights for search fields (query_by_weights). Falls back to [3, 1]
+	Type          string         `json:"type"`                     // "service", "course", "job", "news"
+	TitleField    string         `json:"title_field"`              // Field name for title (used in response mapping)
+	DescField     string         `json:"desc_field"`               // Field name for description (used in response mapping)
+	FilterField   string         `json:"filter_field,omitempty"`   // Optional: field to filter by (e.g., "status")
+	FilterValue   string         `json:"filter_value,omitempty"`   // Optional: value to filter for (e.g., "1")
+	SearchFields  []string       `json:"search_fields,omitempty"`  // Fields to search (query_by). Falls back to [title_field, desc_field]
+	SearchWeights []int          `json:"search_weights,omitempty"` // Weights for search fields (query_by_weights). Falls back to [3, 1]
+	Recency       *RecencyConfig `json:"recency,omitempty"`        // Recency boost decay. Falls back to DefaultRecencyConfig
+	TimeoutMs     int            `json:"timeout_ms,omitempty"`     // Search budget in v2 multi-collection search. Falls back to SEARCH_COLLECTION_TIMEOUT_MS
+	FieldMapping  *FieldMapping  `json:"field_mapping,omitempty"`  // Source fields of the unified title/description/category/url
+	IncludeFields []string       `json:"include_fields,omitempty"` // Extra fields kept in v2 results. When set, results carry only the unified fields plus these
+	FacetFields   []string       `json:"facet_fields,omitempty"`   // Fields counted when requested by facet_by in v2 search. Collections without them are not faceted
+}
+
+// FieldMapping names the source fields presented as the unified title, description, category and
+// url of v2 results, so collections with their own field names (e.g. the legacy 1746_v2_llm and
+// carioca-digital_v2_llm) read the same as the others. Empty fields are left out of the response.
+type FieldMapping struct {
+	Title       string `json:"title,omitempty"`       // Falls back to title_field
+	Description string `json:"description,omitempty"` // Falls back to desc_field
+	Category    string `json:"category,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+// Fields returns the collection's field mapping with the title and description fallbacks applied
+func (c *CollectionConfig) Fields() FieldMapping {
+	var mapping FieldMapping
+	if c.FieldMapping != nil {
+		mapping = *c.FieldMapping
+	}
+	if mapping.Title == "" {
+		mapping.Title = c.TitleField
+	}
+	if mapping.Description == "" {
+		mapping.Description = c.DescField
+	}
+	return mapping
+}
+
+// ProjectedFields returns the include_fields projection of v2 multi-search: the id, the fields
+// read by the unified document (field mapping and recency) and the configured and requested
+// extras. Returns "" when the collection has no include_fields, so full documents are returned.
+func (c *CollectionConfig) ProjectedFields(extra []string) string {
+	if c == nil || len(c.IncludeFields) == 0 {
+		return ""
+	}
+
+	mapping := c.Fields()
+	fields := []string{"id", mapping.Title, mapping.Description, mapping.Category, mapping.URL}
+	fields = append(fields, c.GetRecency().Fields...)
+	fields = append(fields, c.IncludeFields...)
+	fields = append(fields, extra...)
+
+	seen := make(map[string]bool, len(fields))
+	projected := make([]string, 0, len(fields))
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" || seen[field] {
+			continue
+		}
+		seen[field] = true
+		projected = append(projected, field)
+	}
+	return strings.Join(projected, ",")
+}
+
+// Recency decay curves
+const (
+	RecencyCurveExponential = "exponential" // factor halves every half_life_days after the grace period
+	RecencyCurveStep        = "step"        // factor of the first step whose max_days covers the age
+)
+
+// RecencyConfig holds how the recency boost (recency_boost=true) decays the score of a
+// collection's documents with their age
+type RecencyConfig struct {
+	Curve        string        `json:"curve"`                    // "exponential" (default) or "step"
+	GraceDays    float64       `json:"grace_days"`               // Age without penalty
+	HalfLifeDays float64       `json:"half_life_days,omitempty"` // Exponential: days after the grace period to halve the factor
+	Floor        float64       `json:"floor"`                    // Minimum factor, also used for documents without a timestamp
+	Fields       []string      `json:"fields,omitempty"`         // Timestamp fields (unix seconds); the most recent is used. Falls back to [last_update]
+	Steps        []RecencyStep `json:"steps,omitempty"`          // Step: ordered by max_days; older documents get the floor
+	Always       bool          `json:"always,omitempty"`         // Apply the boost even without recency_boost=true (e.g. news)
+}
+
+// RecencyStep factor applied to documents up to MaxDays old
+type RecencyStep struct {
+	MaxDays float64 `json:"max_days"`
+	Factor  float64 `json:"factor"`
+}
+
+// DefaultRecencyConfig returns the recency decay used when a collection has no recency config:
+// no penalty for 30 days, then exponential decay reaching the 0.5 floor in about a year
+func DefaultRecencyConfig() RecencyConfig {
+	return RecencyConfig{
+		Curve:        RecencyCurveExponential,
+		GraceDays:    30,
+		HalfLifeDays: 335,
+		Floor:        0.5,
+		Fields:       []string{"last_update"},
+	}
+}
+
+// Validate checks the curve and the factor ranges
+func (r *RecencyConfig) Validate() error {
+	if r.Floor < 0 || r.Floor > 1 {
+		return fmt.Errorf("floor must be between 0 and 1")
+	}
+	switch r.Curve {
+	case "", RecencyCurveExponential:
+		if r.HalfLifeDays < 0 {
+			return fmt.Errorf("half_life_days must be positive")
+		}
+	case RecencyCurveStep:
+		if len(r.Steps) == 0 {
+			return fmt.Errorf("step curve requires steps")
+		}
+		for i, step := range r.Steps {
+			if step.Factor < 0 || step.Factor > 1 {
+				return fmt.Errorf("step factor must be between 0 and 1")
+			}
+			if i > 0 && step.MaxDays <= r.Steps[i-1].MaxDays {
+				return fmt.Errorf("steps must be ordered by increasing max_days")
+			}
+		}
+	default:
+		return fmt.Errorf("unknown curve %q (use %s or %s)", r.Curve, RecencyCurveExponential, RecencyCurveStep)
+	}
+	return nil
+}
+
+// GetRecency returns the collection's recency config with defaults for the unset curve,
+// half-life and fields
+func (c *CollectionConfig) GetRecency() RecencyConfig {
+	defaults := DefaultRecencyConfig()
+	if c == nil || c.Recency == nil {
+		return defaults
+	}
+
+	recency := *c.Recency
+	if recency.Curve == "" {
+		recency.Curve = defaults.Curve
+	}
+	if recency.HalfLifeDays == 0 {
+		recency.HalfLifeDays = defaults.HalfLifeDays
+	}
+	if len(recency.Fields) == 0 {
+		recency.Fields = defaults.Fields
+	}
+	return recency
+}
+
+// NoticiasCollectionName is the collection of city news and events synced from the newsroom CMS
+const NoticiasCollectionName = "noticias"
+
+// DefaultNoticiasCollectionConfig returns the config used for the noticias collection when it is
+// searchable but missing from COLLECTION_CONFIGS: news lose half their weight every two weeks,
+// whether or not the request asks for the recency boost
+func DefaultNoticiasCollectionConfig() *CollectionConfig {
+	return &CollectionConfig{
+		Type:          "news",
+		TitleField:    "title",
+		DescField:     "summary",
+		FilterField:   "status",
+		FilterValue:   "1",
+		SearchFields:  []string{"title", "summary", "body", "tags"},
+		SearchWeights: []int{4, 2, 1, 2},
+		Recency: &RecencyConfig{
+			Curve:        RecencyCurveExponential,
+			GraceDays:    2,
+			HalfLifeDays: 14,
+			Floor:        0.1,
+			Fields:       []string{"published_at"},
+			Always:       true,
+		},
+	}
+}
+
+// TenantConfig holds the settings of one tenant (municipality) served by this deployment
+type TenantConfig struct {
+	Name                  string   `json:"name"`
+	APIKeys               []string `json:"api_keys,omitempty"`               // API keys (X-API-Key) that identify the tenant
+	Domains               []string `json:"domains,omitempty"`                // Hosts that identify the tenant (e.g. busca.niteroi.rj.gov.br)
+	GatewayBaseURL        string   `json:"gateway_base_url,omitempty"`       // Falls back to GATEWAY_BASE_URL
+	SearchableCollections []string `json:"searchable_collections,omitempty"` // Falls back to SEARCHABLE_COLLECTIONS (v2 API)
+}
+
+// SearchCacheTTL holds how long (seconds) cached results of one search type are fresh and, after
+// that, how long they may still be served while being refreshed in the background
+type SearchCacheTTL struct {
+	Fresh int `json:"fresh"`
+	Stale int `json:"stale"`
+}
+
+// DigestSubscription holds how an orgao_gestor receives its periodic digest
+type DigestSubscription struct {
+	Frequency string   `json:"frequency"`        // "daily" or "weekly"
+	Emails    []string `json:"emails,omitempty"` // Requires SMTP_HOST
+	Webhook   bool     `json:"webhook"`          // Sends the digest to NOTIFICATION_WEBHOOK_URL
+}
+
+// DeepLinkChannel holds how the canonical URL of a service is built for one channel.
+// URLTemplate accepts {slug}, {id}, {nome} and {url} (the web link, already with UTMs);
+// UTMCampaign accepts {slug} and {tema}.
+type DeepLinkChannel struct {
+	URLTemplate string `json:"url_template"`
+	UTMSource   string `json:"utm_source,omitempty"`
+	UTMMedium   string `json:"utm_medium,omitempty"`
+	UTMCampaign string `json:"utm_campaign,omitempty"`
+}
+
+// DefaultDeepLinkChannels returns the channels generated when DEEP_LINK_CHANNELS is not set
+func DefaultDeepLinkChannels() map[string]*DeepLinkChannel {
+	return map[string]*DeepLinkChannel{
+		"web": {
+			URLTemplate: "https://prefeitura.rio/servicos/{slug}",
+			UTMSource:   "busca",
+			UTMMedium:   "web",
+			UTMCampaign: "{tema}",
+		},
+		"app": {
+			URLTemplate: "https://prefeitura.rio/servicos/{slug}",
+			UTMSource:   "app",
+			UTMMedium:   "app",
+			UTMCampaign: "{tema}",
+		},
+		"whatsapp": {
+			URLTemplate: "https://wa.me/?text={nome}%20{url}",
+			UTMSource:   "whatsapp",
+			UTMMedium:   "social",
+			UTMCampaign: "{tema}",
+		},
+	}
+}
+
+// Dependencies covered by the degradation policy
+const (
+	DependencyGemini    = "gemini"
+	DependencyTypesense = "typesense"
+	DependencyCache     = "cache"
+)
+
+// Behaviors applied when a dependency fails
+const (
+	DegradeHybridToKeyword = "hybrid_to_keyword" // gemini: AI and hybrid searches fall back to keyword search
+	DegradeRetryOtherNode  = "retry_other_node"  // typesense: requests are retried on the TYPESENSE_NODES
+	DegradeBypass          = "bypass"            // cache: Redis is skipped and responses are computed
+	DegradeFail            = "fail"              // the request fails
+)
+
+// degradationActions lists the behaviors each dependency supports
+var degradationActions = map[string][]string{
+	DependencyGemini:    {DegradeHybridToKeyword, DegradeFail},
+	DependencyTypesense: {DegradeRetryOtherNode, DegradeFail},
+	DependencyCache:     {DegradeBypass},
+}
+
+// DefaultDegradationPolicy returns the behavior per dependency used when DEGRADATION_POLICY is not set
+func DefaultDegradationPolicy() map[string]string {
+	return map[string]string{
+		DependencyGemini:    DegradeHybridToKeyword,
+		DependencyTypesense: DegradeRetryOtherNode,
+		DependencyCache:     DegradeBypass,
+	}
+}
+
+// ValidateDegradationPolicy checks that every dependency is known and its behavior supported
+func ValidateDegradationPolicy(policy map[string]string) error {
+	for dependency, action := range policy {
+		actions, ok := degradationActions[dependency]
+		if !ok {
+			return fmt.Errorf("unknown dependency %q", dependency)
+		}
+		supported := false
+		for _, candidate := range actions {
+			supported = supported || candidate == action
+		}
+		if !supported {
+			return fmt.Errorf("behavior %q is not supported for %s (use one of %s)", action, dependency, strings.Join(actions, ", "))
+		}
+	}
+	return nil
 }
 
 // GetSearchFields returns the fields to search, with fallback to title and desc
@@ -46,11 +324,26 @@ type Config struct {
 	TypesensePort     string
 	TypesenseAPIKey   string
 	TypesenseProtocol string
+	// Additional Typesense node URLs used for failover (TYPESENSE_NODES, comma-separated)
+	TypesenseNodes []string
+	// Read-only replica serving the public searches (TYPESENSE_REPLICA_URL, empty disables);
+	// writes and admin reads stay on the primary. The API key defaults to TYPESENSE_API_KEY.
+	TypesenseReplicaURL    string
+	TypesenseReplicaAPIKey string
+	// Seconds between replica health probes (TYPESENSE_REPLICA_HEALTH_INTERVAL)
+	TypesenseReplicaHealthInterval int
+
+	// Behavior per dependency failure (DEGRADATION_POLICY JSON merged over the defaults,
+	// e.g. {"gemini":"fail"}); see DefaultDegradationPolicy
+	DegradationPolicy map[string]string
 
 	ServerPort string
 
 	GeminiAPIKey         string
 	GeminiEmbeddingModel string
+	// EmbeddingDimStrict refuses to start when the services schema num_dim differs from the
+	// embedding model dimension (writes with mismatched vectors are always rejected)
+	EmbeddingDimStrict bool
 
 	// Tracing configuration
 	TracingEnabled  bool
@@ -59,9 +352,202 @@ type Config struct {
 	// Gateway configuration for URL wrapping
 	GatewayBaseURL string
 
+	// Webhook for system notifications (empty disables notifications)
+	NotificationWebhookURL string
+
+	// Nightly content freshness report
+	FreshnessReportEnabled bool
+	FreshnessReportHour    int
+	FreshnessStaleMonths   int
+
+	// Duplicate report: nightly clustering of published services with near-identical embeddings
+	DuplicateReportEnabled   bool
+	DuplicateReportHour      int
+	DuplicateReportThreshold float64
+
+	// Days without edits before a draft is flagged in the activity report
+	StaleDraftDays int
+
+	// Response cache for public endpoints (REDIS_URL empty = in-memory only)
+	ResponseCacheEnabled bool
+	ResponseCacheTTL     int // seconds
+	RedisURL             string
+
+	// Search result cache with stale-while-revalidate (SEARCH_CACHE_TTLS JSON keyed by search type,
+	// e.g. {"ai":{"fresh":600,"stale":3600}}, merged over the defaults; seconds)
+	SearchCacheEnabled bool
+	SearchCacheSize    int
+	SearchCacheHotHits int // accesses before a query is served stale while revalidating
+	SearchCacheTTLs    map[string]*SearchCacheTTL
+
+	// In-memory keyword index of the published services (title and summary) answering searches
+	// while Typesense is unreachable; rebuilt every FALLBACK_INDEX_REFRESH_MINUTES
+	FallbackIndexEnabled        bool
+	FallbackIndexRefreshMinutes int
+
+	// Seconds a category waits without new writes before its service counts are recomputed
+	// (0 disables the cached category counts)
+	CategoryStatsDebounce int
+
+	// Precomputed embeddings for the most frequent queries (0 disables)
+	PrecomputedEmbeddingsTopN int
+
+	// Schedule overrides for background jobs, keyed by job name (JOB_SCHEDULES JSON with cron
+	// expressions or "@every <duration>", e.g. {"freshness-report":"30 2 * * *"})
+	JobSchedules map[string]string
+
+	// Interval (minutes) between checks for deprecated services past their sunset (0 disables)
+	SunsetCheckInterval int
+
+	// Interval (minutes) between checks of seasonal availability windows (0 disables) and how many
+	// days before a window closes the owners are warned
+	AvailabilityCheckInterval int
+	AvailabilityWarningDays   int
+
+	// Service attachments stored in GCS (empty bucket disables attachments)
+	GCSBucket           string
+	GCSCredentialsFile  string
+	AttachmentMaxSizeMB int
+
+	// Max request body size (KB) on admin endpoints, except attachment uploads (0 disables)
+	AdminMaxBodyKB int
+	// Max request body size (MB) of the admin services bulk import
+	BulkImportMaxBodyMB int
+
+	// Content owner digests (DIGEST_SUBSCRIPTIONS keyed by orgao_gestor)
+	DigestSubscriptions    map[string]*DigestSubscription
+	DigestDefaultFrequency string // Frequency for orgaos without subscription (empty = no digest)
+	DigestHour             int
+
+	// SMTP for e-mail notifications (empty host disables e-mail)
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUser     string
+	SMTPPassword string
+	SMTPFrom     string
+
+	// Sensitive query topics (JSON list merged over the defaults by id)
+	SensitiveTopics string
+
+	// Abuse filter: offensive queries within the window per client before a temporary block
+	// (ABUSE_INCIDENT_THRESHOLD 0 disables blocking; ABUSE_FILTER_ENABLED=false disables the filter)
+	AbuseFilterEnabled     bool
+	AbuseIncidentThreshold int
+	AbuseIncidentWindow    int // minutes
+	AbuseBlockMinutes      int
+
+	// Extra PII patterns scrubbed from logs, traces and analytics (JSON list of {name, pattern, replacement})
+	PIIPatterns string
+
+	// Key for the HMAC that identifies data subjects in LGPD receipts (empty = plain SHA-256)
+	PrivacyReceiptSecret string
+
+	// Shared secret of the HMAC-signed create/update/delete notifications pushed by the upstream
+	// CMS to /api/v1/ingest/cms-webhook (empty disables the endpoint)
+	CMSWebhookSecret string
+
+	// Bearer token accepted by the MCP server's SSE transport besides the tenant API keys
+	// (empty = only tenant API keys are accepted)
+	MCPToken string
+
+	// Languages offered for service translation (TRANSLATION_LANGUAGES, comma-separated)
+	TranslationLanguages []string
+
+	// Translate queries detected in other languages to Portuguese before searching
+	QueryTranslationEnabled bool
+
+	// Result diversity: max results from the same orgao_gestor / tema within the first
+	// DIVERSITY_WINDOW positions of page 1 (0 disables the limit)
+	DiversityMaxPerOrgao int
+	DiversityMaxPerTema  int
+	DiversityWindow      int
+
+	// Anonymous search journeys (client session IDs): events kept for JOURNEY_RETENTION_DAYS
+	JourneyAnalyticsEnabled bool
+	JourneyRetentionDays    int
+
+	// Anonymized journey export for the data platform: larger ranges than
+	// ANALYTICS_EXPORT_MAX_EVENTS run as async jobs written to GCS_BUCKET. Query and session
+	// hashes are keyed with ANALYTICS_EXPORT_SALT (empty = plain SHA-256).
+	AnalyticsExportMaxEvents int
+	AnalyticsExportSalt      string
+
+	// Weekly report clustering the CONTENT_GAPS_MAX_QUERIES most searched zero-result queries of
+	// the last CONTENT_GAPS_DAYS days (requires journeys and Gemini)
+	ContentGapsDays       int
+	ContentGapsMaxQueries int
+
+	// Synonym candidates mined from query reformulations followed by a click, proposed once
+	// seen in SYNONYM_MINING_MIN_SESSIONS distinct sessions (requires journeys)
+	SynonymMiningMinSessions int
+
+	// Related query suggestions ("people also searched") from queries searched in the same
+	// sessions, suggested once seen together in RELATED_QUERIES_MIN_SESSIONS distinct sessions
+	// (requires journeys)
+	RelatedQueriesMinSessions int
+
+	// Final stage of the legacy collection sunset (1746_v2_llm, carioca-digital_v2_llm): the
+	// collections become read-only in this API and tombamentos can no longer change
+	LegacyCollectionsFrozen bool
+
+	// Per API key usage (route, status, latency) recorded for tenant API keys; events kept for
+	// API_KEY_USAGE_RETENTION_DAYS
+	APIKeyUsageEnabled       bool
+	APIKeyUsageRetentionDays int
+
+	// Soft daily quota per API key (0 = no quota) unless overridden by an admin: responses warn
+	// above API_KEY_QUOTA_WARNING_PERCENT, calls over the quota use the burst allowance
+	// (API_KEY_QUOTA_BURST_PERCENT of the quota) and are refused only after it
+	APIKeyDailyQuota        int
+	APIKeyQuotaBurstPercent int
+	APIKeyQuotaWarnPercent  int
+
+	// Newsroom CMS (WordPress REST API) synced into the noticias collection every
+	// NEWSROOM_SYNC_INTERVAL minutes (empty URL disables the sync)
+	NewsroomCMSURL       string
+	NewsroomCMSToken     string
+	NewsroomSyncInterval int
+
+	// Public transport near presence channels: addresses geocoded by a Nominatim instance and
+	// matched against the stops in TRANSPORT_STOPS_FILE (CSV; empty disables the enrichment)
+	GeocoderURL          string
+	GeocoderUserAgent    string
+	TransportStopsFile   string
+	TransportMaxDistance int // meters
+	TransportMaxStops    int
+
+	// Deep links per channel (DEEP_LINK_CHANNELS merged over the defaults by channel name;
+	// a channel with empty url_template is disabled)
+	DeepLinkChannels map[string]*DeepLinkChannel
+
+	// Tenants by ID (empty disables multi-tenant partitioning)
+	Tenants map[string]*TenantConfig
+
 	// Multi-collection search configuration (v2 API)
 	SearchableCollections []string
 	CollectionConfigs     map[string]*CollectionConfig
+
+	// Default search budget per collection (SEARCH_COLLECTION_TIMEOUT_MS; 0 waits for every
+	// collection). Collections exceeding it are dropped from the response.
+	SearchCollectionTimeoutMs int
+	// Fan-out guardrails (v2 API): maximum hits fetched per search across all collections
+	// (SEARCH_MAX_FETCHED_DOCS; the per-collection window shrinks as more collections are
+	// searched) and maximum collections per search (SEARCH_MAX_COLLECTIONS). 0 disables either.
+	SearchMaxFetchedDocs int
+	SearchMaxCollections int
+
+	// Default hybrid alpha for requests that omit it (SEARCH_HYBRID_ALPHA)
+	SearchHybridAlpha float64
+	// Default minimum scores for requests that omit them (SEARCH_DEFAULT_THRESHOLDS JSON,
+	// e.g. {"keyword":0.2,"hybrid":0.4})
+	SearchDefaultThresholds ScoreThresholds
+	// Score multiplier for services marked fixar_destaque whose destaque_until has not passed
+	// (SEARCH_DESTAQUE_BOOST, 1 disables the boost)
+	SearchDestaqueBoost float64
+
+	// Overrides for the search tunables in .env format (SEARCH_TUNABLES_FILE, e.g. a mounted
+	// ConfigMap), applied at startup and on every reload
+	SearchTunablesFile string
 }
 
 func LoadConfig() *Config {
@@ -73,10 +559,15 @@ func LoadConfig() *Config {
 		TypesenseAPIKey:   getEnv("TYPESENSE_API_KEY", ""),
 		TypesenseProtocol: getEnv("TYPESENSE_PROTOCOL", "http"),
 
+		TypesenseReplicaURL:            strings.TrimSuffix(getEnv("TYPESENSE_REPLICA_URL", ""), "/"),
+		TypesenseReplicaAPIKey:         getEnv("TYPESENSE_REPLICA_API_KEY", ""),
+		TypesenseReplicaHealthInterval: getEnvInt("TYPESENSE_REPLICA_HEALTH_INTERVAL", 10),
+
 		ServerPort: getEnv("SERVER_PORT", "8080"),
 
 		GeminiAPIKey:         getEnv("GEMINI_API_KEY", ""),
 		GeminiEmbeddingModel: getEnv("GEMINI_EMBEDDING_MODEL", "gemini-embedding-001"),
+		EmbeddingDimStrict:   getEnv("EMBEDDING_DIM_STRICT", "false") == "true",
 
 		// Tracing configuration
 		TracingEnabled:  getEnv("TRACING_ENABLED", "false") == "true",
@@ -85,47 +576,329 @@ func LoadConfig() *Config {
 		// Gateway configuration
 		GatewayBaseURL: getEnv("GATEWAY_BASE_URL", ""),
 
-		CollectionConfigs: make(map[string]*CollectionConfig),
+		// Notifications
+		NotificationWebhookURL: getEnv("NOTIFICATION_WEBHOOK_URL", ""),
+
+		// Freshness report
+		FreshnessReportEnabled: getEnv("FRESHNESS_REPORT_ENABLED", "true") == "true",
+		FreshnessReportHour:    getEnvInt("FRESHNESS_REPORT_HOUR", 3),
+		FreshnessStaleMonths:   getEnvInt("FRESHNESS_STALE_MONTHS", 12),
+
+		// Duplicate report
+		DuplicateReportEnabled:   getEnv("DUPLICATE_REPORT_ENABLED", "true") == "true",
+		DuplicateReportHour:      getEnvInt("DUPLICATE_REPORT_HOUR", 4),
+		DuplicateReportThreshold: getEnvFloat("DUPLICATE_REPORT_THRESHOLD", 0.92),
+
+		// Editorial activity report
+		StaleDraftDays: getEnvInt("STALE_DRAFT_DAYS", 30),
+
+		// Response cache
+		ResponseCacheEnabled: getEnv("RESPONSE_CACHE_ENABLED", "true") == "true",
+		ResponseCacheTTL:     getEnvInt("RESPONSE_CACHE_TTL", 300),
+		RedisURL:             getEnv("REDIS_URL", ""),
+
+		// Search result cache
+		SearchCacheEnabled: getEnv("SEARCH_CACHE_ENABLED", "true") == "true",
+		SearchCacheSize:    getEnvInt("SEARCH_CACHE_SIZE", 2000),
+		SearchCacheHotHits: getEnvInt("SEARCH_CACHE_HOT_HITS", 3),
+
+		// Last-resort search index
+		FallbackIndexEnabled:        getEnv("FALLBACK_INDEX_ENABLED", "false") == "true",
+		FallbackIndexRefreshMinutes: getEnvInt("FALLBACK_INDEX_REFRESH_MINUTES", 5),
+
+		// Incremental category counts
+		CategoryStatsDebounce: getEnvInt("CATEGORY_STATS_DEBOUNCE", 30),
+
+		// Precomputed query embeddings
+		PrecomputedEmbeddingsTopN: getEnvInt("PRECOMPUTED_EMBEDDINGS_TOP_N", 1000),
+
+		// Deprecated services auto-unpublish
+		SunsetCheckInterval: getEnvInt("SUNSET_CHECK_INTERVAL", 60),
+
+		// Seasonal availability windows
+		AvailabilityCheckInterval: getEnvInt("AVAILABILITY_CHECK_INTERVAL", 15),
+		AvailabilityWarningDays:   getEnvInt("AVAILABILITY_WARNING_DAYS", 7),
+
+		// Service attachments
+		GCSBucket:           getEnv("GCS_BUCKET", ""),
+		GCSCredentialsFile:  getEnv("GCS_CREDENTIALS_FILE", ""),
+		AttachmentMaxSizeMB: getEnvInt("ATTACHMENT_MAX_SIZE_MB", 20),
+
+		// Admin request body limit
+		AdminMaxBodyKB:      getEnvInt("ADMIN_MAX_BODY_KB", 1024),
+		BulkImportMaxBodyMB: getEnvInt("BULK_IMPORT_MAX_BODY_MB", 20),
+
+		// Sensitive query topics
+		SensitiveTopics: getEnv("SENSITIVE_TOPICS", ""),
+
+		// Abuse filter
+		AbuseFilterEnabled:     getEnv("ABUSE_FILTER_ENABLED", "true") == "true",
+		AbuseIncidentThreshold: getEnvInt("ABUSE_INCIDENT_THRESHOLD", 5),
+		AbuseIncidentWindow:    getEnvInt("ABUSE_INCIDENT_WINDOW", 10),
+		AbuseBlockMinutes:      getEnvInt("ABUSE_BLOCK_MINUTES", 15),
+
+		// PII scrubbing
+		PIIPatterns: getEnv("PII_PATTERNS", ""),
+
+		// Query translation
+		QueryTranslationEnabled: getEnv("QUERY_TRANSLATION_ENABLED", "true") == "true",
+
+		// Search journeys
+		JourneyAnalyticsEnabled:   getEnv("JOURNEY_ANALYTICS_ENABLED", "true") == "true",
+		JourneyRetentionDays:      getEnvInt("JOURNEY_RETENTION_DAYS", 30),
+		AnalyticsExportMaxEvents:  getEnvInt("ANALYTICS_EXPORT_MAX_EVENTS", 100000),
+		AnalyticsExportSalt:       getEnv("ANALYTICS_EXPORT_SALT", ""),
+		ContentGapsDays:           getEnvInt("CONTENT_GAPS_DAYS", 30),
+		ContentGapsMaxQueries:     getEnvInt("CONTENT_GAPS_MAX_QUERIES", 500),
+		SynonymMiningMinSessions:  getEnvInt("SYNONYM_MINING_MIN_SESSIONS", 3),
+		RelatedQueriesMinSessions: getEnvInt("RELATED_QUERIES_MIN_SESSIONS", 3),
+
+		// Legacy collections sunset
+		LegacyCollectionsFrozen: getEnv("LEGACY_COLLECTIONS_FROZEN", "false") == "true",
+
+		// API key usage
+		APIKeyUsageEnabled:       getEnv("API_KEY_USAGE_ENABLED", "true") == "true",
+		APIKeyUsageRetentionDays: getEnvInt("API_KEY_USAGE_RETENTION_DAYS", 90),
+
+		// API key quotas
+		APIKeyDailyQuota:        getEnvInt("API_KEY_DAILY_QUOTA", 0),
+		APIKeyQuotaBurstPercent: getEnvInt("API_KEY_QUOTA_BURST_PERCENT", 20),
+		APIKeyQuotaWarnPercent:  getEnvInt("API_KEY_QUOTA_WARNING_PERCENT", 80),
+
+		// Newsroom CMS
+		NewsroomCMSURL:       getEnv("NEWSROOM_CMS_URL", ""),
+		NewsroomCMSToken:     getEnv("NEWSROOM_CMS_TOKEN", ""),
+		NewsroomSyncInterval: getEnvInt("NEWSROOM_SYNC_INTERVAL", 15),
+
+		// Transport enrichment
+		GeocoderURL:          getEnv("GEOCODER_URL", "https://nominatim.openstreetmap.org"),
+		GeocoderUserAgent:    getEnv("GEOCODER_USER_AGENT", "app-busca-search"),
+		TransportStopsFile:   getEnv("TRANSPORT_STOPS_FILE", ""),
+		TransportMaxDistance: getEnvInt("TRANSPORT_MAX_DISTANCE_METERS", 800),
+		TransportMaxStops:    getEnvInt("TRANSPORT_MAX_STOPS", 3),
+
+		// LGPD data subject requests
+		PrivacyReceiptSecret: getEnv("PRIVACY_RECEIPT_SECRET", ""),
+
+		// Upstream CMS sync
+		CMSWebhookSecret: getEnv("CMS_WEBHOOK_SECRET", ""),
+
+		// MCP server
+		MCPToken: getEnv("MCP_TOKEN", ""),
+
+		// Content owner digests
+		DigestDefaultFrequency: getEnv("DIGEST_DEFAULT_FREQUENCY", "weekly"),
+		DigestHour:             getEnvInt("DIGEST_HOUR", 8),
+
+		// SMTP
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUser:     getEnv("SMTP_USER", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", ""),
+	}
+
+	// Search tunables: collections, budgets and defaults, reloadable at runtime (see Store)
+	cfg.SearchTunablesFile = getEnv("SEARCH_TUNABLES_FILE", "")
+	if err := cfg.loadSearchTunables(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Parse additional Typesense nodes (optional)
+	for _, node := range strings.Split(getEnv("TYPESENSE_NODES", ""), ",") {
+		if node = strings.TrimSpace(node); node != "" {
+			cfg.TypesenseNodes = append(cfg.TypesenseNodes, strings.TrimSuffix(node, "/"))
+		}
+	}
+
+	if cfg.TypesenseReplicaAPIKey == "" {
+		cfg.TypesenseReplicaAPIKey = cfg.TypesenseAPIKey
+	}
+	if cfg.TypesenseReplicaHealthInterval <= 0 {
+		cfg.TypesenseReplicaHealthInterval = 10
+	}
+	if cfg.FallbackIndexRefreshMinutes <= 0 {
+		cfg.FallbackIndexRefreshMinutes = 5
+	}
+
+	// Parse degradation policy JSON (optional, overrides the defaults by dependency)
+	cfg.DegradationPolicy = DefaultDegradationPolicy()
+	if policyJSON := os.Getenv("DEGRADATION_POLICY"); policyJSON != "" {
+		var policy map[string]string
+		if err := json.Unmarshal([]byte(policyJSON), &policy); err != nil {
+			log.Fatalf("Failed to parse DEGRADATION_POLICY JSON: %v", err)
+		}
+		if err := ValidateDegradationPolicy(policy); err != nil {
+			log.Fatalf("Invalid DEGRADATION_POLICY: %v", err)
+		}
+		for dependency, action := range policy {
+			cfg.DegradationPolicy[dependency] = action
+		}
+	}
+
+	// Parse translation languages (empty disables translations)
+	for _, lang := range strings.Split(getEnv("TRANSLATION_LANGUAGES", "en,es"), ",") {
+		if lang = strings.ToLower(strings.TrimSpace(lang)); lang != "" {
+			cfg.TranslationLanguages = append(cfg.TranslationLanguages, lang)
+		}
 	}
 
-	// Parse searchable collections (REQUIRED for v2 API)
-	collectionsCSV := os.Getenv("SEARCHABLE_COLLECTIONS")
-	if collectionsCSV == "" {
-		log.Fatal("SEARCHABLE_COLLECTIONS environment variable is required but not set")
+	// Parse job schedule overrides JSON (optional)
+	if schedulesJSON := os.Getenv("JOB_SCHEDULES"); schedulesJSON != "" {
+		if err := json.Unmarshal([]byte(schedulesJSON), &cfg.JobSchedules); err != nil {
+			log.Fatalf("Failed to parse JOB_SCHEDULES JSON: %v", err)
+		}
 	}
-	cfg.SearchableCollections = strings.Split(collectionsCSV, ",")
-	for i := range cfg.SearchableCollections {
-		cfg.SearchableCollections[i] = strings.TrimSpace(cfg.SearchableCollections[i])
+
+	// Parse search cache TTLs JSON (optional, overrides the defaults by search type)
+	if ttlsJSON := os.Getenv("SEARCH_CACHE_TTLS"); ttlsJSON != "" {
+		if err := json.Unmarshal([]byte(ttlsJSON), &cfg.SearchCacheTTLs); err != nil {
+			log.Fatalf("Failed to parse SEARCH_CACHE_TTLS JSON: %v", err)
+		}
 	}
 
-	// Parse collection configs JSON (REQUIRED for v2 API)
-	configsJSON := os.Getenv("COLLECTION_CONFIGS")
-	if configsJSON == "" {
-		log.Fatal("COLLECTION_CONFIGS environment variable is required but not set")
+	// Parse deep link channels JSON (optional, overrides the defaults by channel)
+	cfg.DeepLinkChannels = DefaultDeepLinkChannels()
+	if channelsJSON := os.Getenv("DEEP_LINK_CHANNELS"); channelsJSON != "" {
+		var channels map[string]*DeepLinkChannel
+		if err := json.Unmarshal([]byte(channelsJSON), &channels); err != nil {
+			log.Fatalf("Failed to parse DEEP_LINK_CHANNELS JSON: %v", err)
+		}
+		for name, channel := range channels {
+			if channel == nil || channel.URLTemplate == "" {
+				delete(cfg.DeepLinkChannels, name)
+				continue
+			}
+			cfg.DeepLinkChannels[name] = channel
+		}
 	}
 
-	if err := json.Unmarshal([]byte(configsJSON), &cfg.CollectionConfigs); err != nil {
-		log.Fatalf("Failed to parse COLLECTION_CONFIGS JSON: %v", err)
+	// Parse digest subscriptions JSON (optional)
+	if subscriptionsJSON := os.Getenv("DIGEST_SUBSCRIPTIONS"); subscriptionsJSON != "" {
+		if err := json.Unmarshal([]byte(subscriptionsJSON), &cfg.DigestSubscriptions); err != nil {
+			log.Fatalf("Failed to parse DIGEST_SUBSCRIPTIONS JSON: %v", err)
+		}
 	}
 
-	// Validate that all searchable collections have configs
-	for _, collName := range cfg.SearchableCollections {
-		if _, exists := cfg.CollectionConfigs[collName]; !exists {
-			log.Fatalf("Collection '%s' is in SEARCHABLE_COLLECTIONS but missing from COLLECTION_CONFIGS", collName)
+	// Parse tenant configs JSON (optional)
+	if tenantsJSON := os.Getenv("TENANT_CONFIGS"); tenantsJSON != "" {
+		if err := json.Unmarshal([]byte(tenantsJSON), &cfg.Tenants); err != nil {
+			log.Fatalf("Failed to parse TENANT_CONFIGS JSON: %v", err)
+		}
+		if err := cfg.validateTenantCollections(); err != nil {
+			log.Fatal(err)
 		}
 	}
 
 	return cfg
 }
 
+// MultiTenant reports whether multi-tenant partitioning is enabled
+func (c *Config) MultiTenant() bool {
+	return len(c.Tenants) > 0
+}
+
+// ResolveTenant returns the tenant identified by the API key or, failing that, by the host.
+// ok is false when neither matches a configured tenant.
+func (c *Config) ResolveTenant(apiKey, host string) (string, bool) {
+	if id, ok := c.TenantForAPIKey(apiKey); ok {
+		return id, true
+	}
+
+	if host != "" {
+		for id, tenant := range c.Tenants {
+			for _, domain := range tenant.Domains {
+				if strings.EqualFold(domain, host) {
+					return id, true
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+// TenantForAPIKey returns the tenant that owns the API key
+func (c *Config) TenantForAPIKey(apiKey string) (string, bool) {
+	if apiKey == "" {
+		return "", false
+	}
+	for id, tenant := range c.Tenants {
+		for _, key := range tenant.APIKeys {
+			if key == apiKey {
+				return id, true
+			}
+		}
+	}
+	return "", false
+}
+
+// APIKeyID returns the public identifier of an API key (a SHA-256 prefix), used in usage
+// reports so the key itself is never stored or exposed
+func APIKeyID(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return "key_" + hex.EncodeToString(sum[:6])
+}
+
+// GatewayBaseURLFor returns the gateway base URL of the tenant, falling back to the global one
+func (c *Config) GatewayBaseURLFor(tenantID string) string {
+	if tenant, ok := c.Tenants[tenantID]; ok && tenant.GatewayBaseURL != "" {
+		return tenant.GatewayBaseURL
+	}
+	return c.GatewayBaseURL
+}
+
+// SearchableCollectionsFor returns the v2 searchable collections of the tenant, falling back to the global list
+func (c *Config) SearchableCollectionsFor(tenantID string) []string {
+	if tenant, ok := c.Tenants[tenantID]; ok && len(tenant.SearchableCollections) > 0 {
+		return tenant.SearchableCollections
+	}
+	return c.SearchableCollections
+}
+
 // GetCollectionConfig returns the config for a specific collection
 func (c *Config) GetCollectionConfig(name string) *CollectionConfig {
 	return c.CollectionConfigs[name]
 }
 
+// CollectionTimeout returns the collection's search budget in v2 multi-collection search
+// (0 = no budget)
+func (c *Config) CollectionTimeout(name string) time.Duration {
+	if collConfig := c.CollectionConfigs[name]; collConfig != nil && collConfig.TimeoutMs > 0 {
+		return time.Duration(collConfig.TimeoutMs) * time.Millisecond
+	}
+	return time.Duration(max(c.SearchCollectionTimeoutMs, 0)) * time.Millisecond
+}
+
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		log.Printf("Invalid integer for %s=%q, using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		log.Printf("Invalid number for %s=%q, using default %g", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
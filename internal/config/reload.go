@@ -0,0 +1,267 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// ScoreThresholds holds the default minimum score per search type (nil applies no threshold)
+type ScoreThresholds struct {
+	Keyword  *float64 `json:"keyword,omitempty"`
+	Semantic *float64 `json:"semantic,omitempty"`
+	Hybrid   *float64 `json:"hybrid,omitempty"`
+	AI       *float64 `json:"ai,omitempty"`
+}
+
+// defaultHybridAlpha weighs 70% text and 30% vector in hybrid searches
+const defaultHybridAlpha = 0.3
+
+// DestaqueBoost returns the score multiplier for featured services, 1 (no boost) when unset
+func (c *Config) DestaqueBoost() float64 {
+	if c == nil || c.SearchDestaqueBoost < 1 {
+		return 1
+	}
+	return c.SearchDestaqueBoost
+}
+
+// HybridAlpha returns the default hybrid alpha, falling back to 0.3 when unset or out of range
+func (c *Config) HybridAlpha() float64 {
+	if c == nil || c.SearchHybridAlpha <= 0 || c.SearchHybridAlpha > 1 {
+		return defaultHybridAlpha
+	}
+	return c.SearchHybridAlpha
+}
+
+// Store holds the current configuration snapshot. Requests take the snapshot once and use it
+// throughout, so a reload never mixes old and new settings within a request. Only the search
+// tunables (see loadSearchTunables) change on reload; everything else keeps the startup values.
+type Store struct {
+	current    atomic.Pointer[Config]
+	reloadedAt atomic.Int64
+
+	mu sync.Mutex // serializes reloads
+}
+
+// NewStore creates the store with the configuration loaded at startup
+func NewStore(cfg *Config) *Store {
+	s := &Store{}
+	s.current.Store(cfg)
+	s.reloadedAt.Store(time.Now().Unix())
+	return s
+}
+
+// Current returns the current snapshot (nil without a store)
+func (s *Store) Current() *Config {
+	if s == nil {
+		return nil
+	}
+	return s.current.Load()
+}
+
+// ReloadedAt returns when the current snapshot was loaded (unix)
+func (s *Store) ReloadedAt() int64 {
+	return s.reloadedAt.Load()
+}
+
+// Reload re-reads the search tunables from the environment and SEARCH_TUNABLES_FILE into a new
+// snapshot. On error the current snapshot is kept.
+func (s *Store) Reload() (*Config, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := *s.current.Load()
+	if err := next.loadSearchTunables(); err != nil {
+		return nil, err
+	}
+	if err := next.validateTenantCollections(); err != nil {
+		return nil, err
+	}
+
+	s.current.Store(&next)
+	s.reloadedAt.Store(time.Now().Unix())
+	log.Printf("Configuration reloaded: collections=%v", next.SearchableCollections)
+	return &next, nil
+}
+
+// WatchSignals reloads the configuration on every SIGHUP
+func (s *Store) WatchSignals() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		for range signals {
+			if _, err := s.Reload(); err != nil {
+				log.Printf("Configuration reload failed, keeping the current settings: %v", err)
+			}
+		}
+	}()
+}
+
+// loadSearchTunables reads the settings that can change without a restart: searchable
+// collections, collection configs (field mappings, recency, budgets), the default budget, hybrid
+// alpha and thresholds, the featured services boost, and result diversity. Values in
+// SEARCH_TUNABLES_FILE take precedence over the environment. Maps and slices are always
+// replaced, never modified, so snapshots taken before a reload stay intact.
+func (c *Config) loadSearchTunables() error {
+	lookup, err := tunablesLookup(c.SearchTunablesFile)
+	if err != nil {
+		return err
+	}
+	intValue := func(key string, defaultValue int) (int, error) {
+		value, ok := lookup(key)
+		if !ok {
+			return defaultValue, nil
+		}
+		parsed, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return 0, fmt.Errorf("invalid integer for %s=%q", key, value)
+		}
+		return parsed, nil
+	}
+
+	// Searchable collections (REQUIRED for v2 API)
+	collectionsCSV, _ := lookup("SEARCHABLE_COLLECTIONS")
+	if collectionsCSV == "" {
+		return fmt.Errorf("SEARCHABLE_COLLECTIONS environment variable is required but not set")
+	}
+	collections := strings.Split(collectionsCSV, ",")
+	for i := range collections {
+		collections[i] = strings.TrimSpace(collections[i])
+	}
+
+	// Collection configs JSON (REQUIRED for v2 API)
+	configsJSON, _ := lookup("COLLECTION_CONFIGS")
+	if configsJSON == "" {
+		return fmt.Errorf("COLLECTION_CONFIGS environment variable is required but not set")
+	}
+	configs := make(map[string]*CollectionConfig)
+	if err := json.Unmarshal([]byte(configsJSON), &configs); err != nil {
+		return fmt.Errorf("failed to parse COLLECTION_CONFIGS JSON: %w", err)
+	}
+
+	// The noticias collection has a built-in config
+	if _, exists := configs[NoticiasCollectionName]; !exists {
+		configs[NoticiasCollectionName] = DefaultNoticiasCollectionConfig()
+	}
+
+	// Validate that all searchable collections have configs
+	for _, collName := range collections {
+		if _, exists := configs[collName]; !exists {
+			return fmt.Errorf("collection '%s' is in SEARCHABLE_COLLECTIONS but missing from COLLECTION_CONFIGS", collName)
+		}
+	}
+	for collName, collConfig := range configs {
+		if collConfig.Recency == nil {
+			continue
+		}
+		if err := collConfig.Recency.Validate(); err != nil {
+			return fmt.Errorf("invalid recency config for collection '%s': %w", collName, err)
+		}
+	}
+
+	timeoutMs, err := intValue("SEARCH_COLLECTION_TIMEOUT_MS", 1500)
+	if err != nil {
+		return err
+	}
+
+	maxFetched, err := intValue("SEARCH_MAX_FETCHED_DOCS", 1000)
+	if err != nil {
+		return err
+	}
+	maxCollections, err := intValue("SEARCH_MAX_COLLECTIONS", 8)
+	if err != nil {
+		return err
+	}
+	if maxCollections > 0 && len(collections) > maxCollections {
+		return fmt.Errorf("SEARCHABLE_COLLECTIONS has %d collections, above SEARCH_MAX_COLLECTIONS=%d", len(collections), maxCollections)
+	}
+
+	alpha := defaultHybridAlpha
+	if value, ok := lookup("SEARCH_HYBRID_ALPHA"); ok {
+		alpha, err = strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || alpha <= 0 || alpha > 1 {
+			return fmt.Errorf("invalid SEARCH_HYBRID_ALPHA=%q: must be in (0, 1]", value)
+		}
+	}
+
+	destaqueBoost := 1.0
+	if value, ok := lookup("SEARCH_DESTAQUE_BOOST"); ok {
+		destaqueBoost, err = strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || destaqueBoost < 1 {
+			return fmt.Errorf("invalid SEARCH_DESTAQUE_BOOST=%q: must be >= 1", value)
+		}
+	}
+
+	var thresholds ScoreThresholds
+	if thresholdsJSON, ok := lookup("SEARCH_DEFAULT_THRESHOLDS"); ok && thresholdsJSON != "" {
+		if err := json.Unmarshal([]byte(thresholdsJSON), &thresholds); err != nil {
+			return fmt.Errorf("failed to parse SEARCH_DEFAULT_THRESHOLDS JSON: %w", err)
+		}
+	}
+
+	// Result diversity
+	maxPerOrgao, err := intValue("DIVERSITY_MAX_PER_ORGAO", 3)
+	if err != nil {
+		return err
+	}
+	maxPerTema, err := intValue("DIVERSITY_MAX_PER_TEMA", 0)
+	if err != nil {
+		return err
+	}
+	window, err := intValue("DIVERSITY_WINDOW", 10)
+	if err != nil {
+		return err
+	}
+
+	c.SearchableCollections = collections
+	c.CollectionConfigs = configs
+	c.SearchCollectionTimeoutMs = timeoutMs
+	c.SearchMaxFetchedDocs = maxFetched
+	c.SearchMaxCollections = maxCollections
+	c.SearchHybridAlpha = alpha
+	c.SearchDefaultThresholds = thresholds
+	c.SearchDestaqueBoost = destaqueBoost
+	c.DiversityMaxPerOrgao = maxPerOrgao
+	c.DiversityMaxPerTema = maxPerTema
+	c.DiversityWindow = window
+	return nil
+}
+
+// tunablesLookup looks keys up in the tunables file first and then in the environment
+func tunablesLookup(path string) (func(key string) (string, bool), error) {
+	overrides := map[string]string{}
+	if path != "" {
+		var err error
+		if overrides, err = godotenv.Read(path); err != nil {
+			return nil, fmt.Errorf("failed to read SEARCH_TUNABLES_FILE: %w", err)
+		}
+	}
+	return func(key string) (string, bool) {
+		if value, ok := overrides[key]; ok {
+			return value, true
+		}
+		return os.LookupEnv(key)
+	}, nil
+}
+
+// validateTenantCollections checks that every tenant collection has a config
+func (c *Config) validateTenantCollections() error {
+	for id, tenant := range c.Tenants {
+		for _, collName := range tenant.SearchableCollections {
+			if _, exists := c.CollectionConfigs[collName]; !exists {
+				return fmt.Errorf("collection '%s' of tenant '%s' is missing from COLLECTION_CONFIGS", collName, id)
+			}
+		}
+	}
+	return nil
+}
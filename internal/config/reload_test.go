@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStoreReload(t *testing.T) {
+	t.Setenv("SEARCHABLE_COLLECTIONS", "a")
+	t.Setenv("COLLECTION_CONFIGS", `{"a":{"type":"service"},"b":{"type":"service","timeout_ms":300}}`)
+	t.Setenv("DIVERSITY_MAX_PER_ORGAO", "3")
+
+	file := filepath.Join(t.TempDir(), "tunables.env")
+	if err := os.WriteFile(file, []byte("DIVERSITY_MAX_PER_ORGAO=2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Config{SearchTunablesFile: file}
+	if err := cfg.loadSearchTunables(); err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if cfg.DiversityMaxPerOrgao != 2 || cfg.HybridAlpha() != 0.3 || cfg.SearchCollectionTimeoutMs != 1500 {
+		t.Fatalf("valores iniciais inesperados: %+v", cfg)
+	}
+
+	store := NewStore(cfg)
+	initial := store.Current()
+
+	// O arquivo tem precedência sobre o ambiente
+	os.WriteFile(file, []byte("SEARCHABLE_COLLECTIONS=a,b\nSEARCH_HYBRID_ALPHA=0.5\nSEARCH_DEFAULT_THRESHOLDS={\"keyword\":0.2}\n"), 0o644)
+	next, err := store.Reload()
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if store.Current() != next || len(next.SearchableCollections) != 2 || next.HybridAlpha() != 0.5 ||
+		next.SearchDefaultThresholds.Keyword == nil || *next.SearchDefaultThresholds.Keyword != 0.2 {
+		t.Errorf("snapshot recarregado inesperado: %+v", next)
+	}
+	if next.DiversityMaxPerOrgao != 3 || next.CollectionTimeout("b").Milliseconds() != 300 {
+		t.Errorf("esperados os valores do ambiente e das configs das collections: %+v", next)
+	}
+
+	// O snapshot anterior não é alterado
+	if len(initial.SearchableCollections) != 1 || initial.HybridAlpha() != 0.3 {
+		t.Errorf("snapshot anterior alterado: %+v", initial)
+	}
+
+	// Configuração inválida mantém a atual
+	os.WriteFile(file, []byte("SEARCHABLE_COLLECTIONS=a,c\n"), 0o644)
+	if _, err := store.Reload(); err == nil {
+		t.Error("esperado erro para collection sem config")
+	}
+	if store.Current() != next {
+		t.Error("configuração inválida substituiu o snapshot atual")
+	}
+}
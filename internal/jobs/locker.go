@@ -0,0 +1,93 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Locker concede chaves exclusivas por tempo limitado (leases). TryLock retorna false quando
+// a chave já pertence a outra réplica; Unlock libera a chave apenas se ainda for do owner.
+type Locker interface {
+	TryLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+	Unlock(ctx context.Context, key, owner string) error
+}
+
+// unlockScript remove a chave somente se o valor ainda for o owner (o lease pode ter expirado
+// e sido adquirido por outra réplica)
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// RedisLocker implementa o lease com SET NX PX no Redis compartilhado entre as réplicas
+type RedisLocker struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisLocker cria o locker distribuído sobre o cliente Redis
+func NewRedisLocker(client *redis.Client) *RedisLocker {
+	return &RedisLocker{client: client, prefix: "app-busca-search:jobs:"}
+}
+
+// TryLock tenta adquirir a chave; o lease expira sozinho após ttl
+func (l *RedisLocker) TryLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	return l.client.SetNX(ctx, l.prefix+key, owner, ttl).Result()
+}
+
+// Unlock libera a chave antes do fim do lease
+func (l *RedisLocker) Unlock(ctx context.Context, key, owner string) error {
+	return unlockScript.Run(ctx, l.client, []string{l.prefix + key}, owner).Err()
+}
+
+// LocalLocker concede as chaves apenas dentro do processo. Usado sem Redis, quando cada réplica
+// executa os jobs por conta própria.
+type LocalLocker struct {
+	mu     sync.Mutex
+	leases map[string]localLease
+}
+
+type localLease struct {
+	owner   string
+	expires time.Time
+}
+
+// NewLocalLocker cria o locker em memória
+func NewLocalLocker() *LocalLocker {
+	return &LocalLocker{leases: make(map[string]localLease)}
+}
+
+// TryLock adquire a chave se não houver lease vigente
+func (l *LocalLocker) TryLock(_ context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	for k, lease := range l.leases {
+		if !lease.expires.After(now) {
+			delete(l.leases, k)
+		}
+	}
+
+	if _, held := l.leases[key]; held {
+		return false, nil
+	}
+	l.leases[key] = localLease{owner: owner, expires: now.Add(ttl)}
+	return true, nil
+}
+
+// Unlock libera a chave se ainda for do owner
+func (l *LocalLocker) Unlock(_ context.Context, key, owner string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if lease, held := l.leases[key]; held && lease.owner == owner {
+		delete(l.leases, key)
+	}
+	return nil
+}
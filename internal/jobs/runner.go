@@ -0,0 +1,380 @@
+// Package jobs agenda as rotinas em segundo plano que devem rodar uma única vez por horário
+// no cluster, mesmo com várias réplicas da API no ar, e registra o histórico das execuções.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+// Situação das execuções
+const (
+	RunRunning = "running"
+	RunSuccess = "success"
+	RunFailed  = "failed"
+)
+
+// Origem das execuções
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+// historyRetention por quanto tempo o histórico de execuções é mantido
+const historyRetention = 30 * 24 * time.Hour
+
+var (
+	// ErrJobNotFound indica job não registrado
+	ErrJobNotFound = errors.New("job não encontrado")
+
+	// ErrJobRunning indica que o job já está em execução
+	ErrJobRunning = errors.New("job já está em execução")
+
+	// ErrJobStuck indica execução que não terminou após ser cancelada
+	ErrJobStuck = errors.New("execução do job não terminou após o cancelamento")
+)
+
+// restartPoll intervalo entre as verificações do fim da execução cancelada por Restart
+const restartPoll = 50 * time.Millisecond
+
+// Job é a execução de uma rotina agendada
+type Job func(ctx context.Context) error
+
+type registeredJob struct {
+	name     string
+	expr     string
+	schedule Schedule
+	timeout  time.Duration
+	run      Job
+	running  atomic.Bool
+
+	mu     sync.Mutex
+	cancel context.CancelFunc // cancela a execução em andamento nesta réplica
+}
+
+func (j *registeredJob) setCancel(cancel context.CancelFunc) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.cancel = cancel
+}
+
+// cancelRun cancela o contexto da execução em andamento nesta réplica, se houver
+func (j *registeredJob) cancelRun() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.cancel != nil {
+		j.cancel()
+	}
+}
+
+// Runner executa jobs em horários definidos por expressões cron. Todas as réplicas calculam o
+// mesmo horário e só a que adquirir o lease do horário no Locker executa o job; o lease não é
+// liberado ao fim da execução, para que réplicas atrasadas não repitam o mesmo horário. As
+// execuções e as pausas ficam no Store, compartilhadas entre as réplicas.
+type Runner struct {
+	locker    Locker
+	store     Store
+	owner     string
+	overrides map[string]string
+
+	mu   sync.RWMutex
+	jobs map[string]*registeredJob
+}
+
+// NewRunner cria o runner sobre o locker (RedisLocker com várias réplicas) e o store do
+// histórico. overrides substitui o agendamento padrão dos jobs pelo nome (JOB_SCHEDULES).
+func NewRunner(locker Locker, store Store, overrides map[string]string) (*Runner, error) {
+	for name, expr := range overrides {
+		if _, err := ParseSchedule(expr); err != nil {
+			return nil, fmt.Errorf("agendamento do job %s: %w", name, err)
+		}
+	}
+
+	hostname, _ := os.Hostname()
+	r := &Runner{
+		locker:    locker,
+		store:     store,
+		owner:     fmt.Sprintf("%s/%s", hostname, uuid.New().String()[:8]),
+		overrides: overrides,
+		jobs:      make(map[string]*registeredJob),
+	}
+
+	if err := r.Register("jobs-history-cleanup", "0 4 * * *", 5*time.Minute, func(ctx context.Context) error {
+		return r.store.DeleteRunsBefore(ctx, time.Now().Add(-historyRetention).Unix())
+	}); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Register registra o job e inicia seu agendamento. schedule é uma expressão cron ou
+// @every <duração> (ver ParseSchedule); timeout limita cada execução.
+func (r *Runner) Register(name, schedule string, timeout time.Duration, job Job) error {
+	if override, ok := r.overrides[name]; ok {
+		schedule = override
+	}
+	parsed, err := ParseSchedule(schedule)
+	if err != nil {
+		return fmt.Errorf("agendamento do job %s: %w", name, err)
+	}
+
+	j := &registeredJob{name: name, expr: schedule, schedule: parsed, timeout: timeout, run: job}
+
+	r.mu.Lock()
+	if _, exists := r.jobs[name]; exists {
+		r.mu.Unlock()
+		return fmt.Errorf("job %s já registrado", name)
+	}
+	r.jobs[name] = j
+	r.mu.Unlock()
+
+	go r.loop(j)
+	return nil
+}
+
+// Trigger executa o job imediatamente nesta réplica, em segundo plano, e retorna a execução
+// iniciada. Execuções manuais simultâneas no cluster são recusadas com ErrJobRunning.
+func (r *Runner) Trigger(ctx context.Context, name, user string) (*models.JobRun, error) {
+	j, err := r.job(name)
+	if err != nil {
+		return nil, err
+	}
+
+	key := name + ":manual"
+	acquired, err := r.locker.TryLock(ctx, key, r.owner, j.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao adquirir lease do job %s: %w", name, err)
+	}
+	if !acquired || !j.running.CompareAndSwap(false, true) {
+		if acquired {
+			r.locker.Unlock(ctx, key, r.owner)
+		}
+		return nil, ErrJobRunning
+	}
+
+	run := r.startRun(ctx, j, TriggerManual, user)
+	started := *run
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), j.timeout)
+		defer cancel()
+		r.finishRun(ctx, j, run)
+		if err := r.locker.Unlock(ctx, key, r.owner); err != nil {
+			log.Printf("[Jobs] Erro ao liberar lease do job %s: %v", name, err)
+		}
+	}()
+
+	return &started, nil
+}
+
+// Restart cancela a execução do job em andamento nesta réplica, aguarda até grace pelo seu fim e
+// executa o job novamente (ver Trigger). Jobs que ignoram o cancelamento do contexto não podem ser
+// interrompidos: após grace retorna ErrJobStuck. Execuções em outras réplicas não são canceladas.
+func (r *Runner) Restart(ctx context.Context, name, user string, grace time.Duration) (*models.JobRun, error) {
+	j, err := r.job(name)
+	if err != nil {
+		return nil, err
+	}
+	j.cancelRun()
+
+	deadline := time.Now().Add(grace)
+	for {
+		if !j.running.Load() {
+			run, err := r.Trigger(ctx, name, user)
+			if !errors.Is(err, ErrJobRunning) {
+				return run, err
+			}
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrJobStuck
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(restartPoll):
+		}
+	}
+}
+
+// SetPaused pausa ou retoma as execuções agendadas do job em todas as réplicas. Execuções
+// manuais continuam permitidas.
+func (r *Runner) SetPaused(ctx context.Context, name string, paused bool) error {
+	if _, err := r.job(name); err != nil {
+		return err
+	}
+	return r.store.SetPaused(ctx, name, paused, time.Now().Unix())
+}
+
+// List retorna os jobs registrados com a última execução e o próximo horário
+func (r *Runner) List(ctx context.Context) (*models.JobList, error) {
+	paused, err := r.store.PausedJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	registered := make([]*registeredJob, 0, len(r.jobs))
+	for _, j := range r.jobs {
+		registered = append(registered, j)
+	}
+	r.mu.RUnlock()
+	sort.Slice(registered, func(a, b int) bool { return registered[a].name < registered[b].name })
+
+	now := time.Now()
+	list := &models.JobList{Total: len(registered), Jobs: make([]models.JobInfo, 0, len(registered))}
+	for _, j := range registered {
+		info := models.JobInfo{
+			Name:     j.name,
+			Schedule: j.expr,
+			Paused:   paused[j.name],
+			Running:  j.running.Load(),
+		}
+		if next := j.schedule.Next(now); !next.IsZero() {
+			info.NextRun = next.Unix()
+		}
+
+		runs, err := r.store.ListRuns(ctx, j.name, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(runs) > 0 {
+			info.LastRun = &runs[0]
+		}
+		list.Jobs = append(list.Jobs, info)
+	}
+	return list, nil
+}
+
+// Runs retorna as últimas execuções do job
+func (r *Runner) Runs(ctx context.Context, name string, limit int) (*models.JobRunList, error) {
+	if _, err := r.job(name); err != nil {
+		return nil, err
+	}
+
+	runs, err := r.store.ListRuns(ctx, name, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &models.JobRunList{Job: name, Total: len(runs), Runs: runs}, nil
+}
+
+func (r *Runner) job(name string) (*registeredJob, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	j, ok := r.jobs[name]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return j, nil
+}
+
+// loop aguarda cada horário do agendamento e tenta executar o job
+func (r *Runner) loop(j *registeredJob) {
+	for {
+		slot := j.schedule.Next(time.Now())
+		if slot.IsZero() {
+			log.Printf("[Jobs] Job %s sem próximas execuções para %q", j.name, j.expr)
+			return
+		}
+		time.Sleep(time.Until(slot))
+		r.runScheduled(j, slot)
+	}
+}
+
+// runScheduled executa o job do horário se ele não estiver pausado e esta réplica adquirir o
+// lease. Sem acesso ao locker o horário é pulado: é preferível atrasar a rotina a executá-la
+// em todas as réplicas.
+func (r *Runner) runScheduled(j *registeredJob, slot time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), j.timeout)
+	defer cancel()
+
+	paused, err := r.store.PausedJobs(ctx)
+	if err != nil {
+		log.Printf("[Jobs] Erro ao consultar pausa do job %s: %v", j.name, err)
+	} else if paused[j.name] {
+		return
+	}
+
+	hold := j.schedule.Next(slot).Sub(slot)
+	acquired, err := r.locker.TryLock(ctx, slotKey(j.name, slot), r.owner, hold)
+	if err != nil {
+		log.Printf("[Jobs] Erro ao adquirir lease do job %s, execução pulada: %v", j.name, err)
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	if !j.running.CompareAndSwap(false, true) {
+		log.Printf("[Jobs] Job %s ainda em execução, horário %s pulado", j.name, slot.Format(time.RFC3339))
+		return
+	}
+	r.finishRun(ctx, j, r.startRun(ctx, j, TriggerSchedule, ""))
+}
+
+// startRun registra o início da execução
+func (r *Runner) startRun(ctx context.Context, j *registeredJob, trigger, user string) *models.JobRun {
+	run := &models.JobRun{
+		ID:          uuid.New().String(),
+		Job:         j.name,
+		Status:      RunRunning,
+		Trigger:     trigger,
+		TriggeredBy: user,
+		Owner:       r.owner,
+		StartedAt:   time.Now().Unix(),
+	}
+	if err := r.store.SaveRun(ctx, run); err != nil {
+		log.Printf("[Jobs] %v", err)
+	}
+	return run
+}
+
+// finishRun executa o job e registra o resultado; libera o job para novas execuções
+func (r *Runner) finishRun(ctx context.Context, j *registeredJob, run *models.JobRun) {
+	defer j.running.Store(false)
+
+	ctx, cancel := context.WithCancel(ctx)
+	j.setCancel(cancel)
+	defer func() {
+		j.setCancel(nil)
+		cancel()
+	}()
+
+	start := time.Now()
+	err := j.run(ctx)
+	elapsed := time.Since(start)
+
+	run.FinishedAt = time.Now().Unix()
+	run.DurationMs = elapsed.Milliseconds()
+	run.Status = RunSuccess
+	if err != nil {
+		run.Status = RunFailed
+		run.Error = err.Error()
+		log.Printf("[Jobs] Job %s falhou após %s: %v", j.name, elapsed.Round(time.Millisecond), err)
+	} else {
+		log.Printf("[Jobs] Job %s concluído em %s", j.name, elapsed.Round(time.Millisecond))
+	}
+
+	// O contexto do job pode ter expirado; o registro do resultado usa um prazo próprio
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	if err := r.store.SaveRun(saveCtx, run); err != nil {
+		log.Printf("[Jobs] %v", err)
+	}
+}
+
+// slotKey identifica a execução do job no horário
+func slotKey(name string, slot time.Time) string {
+	return fmt.Sprintf("%s:%d", name, slot.Unix())
+}
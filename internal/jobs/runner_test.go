@@ -0,0 +1,212 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+// memoryStore Store em memória para os testes
+type memoryStore struct {
+	mu     sync.Mutex
+	runs   map[string]models.JobRun
+	paused map[string]bool
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{runs: make(map[string]models.JobRun), paused: make(map[string]bool)}
+}
+
+func (s *memoryStore) SaveRun(_ context.Context, run *models.JobRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs[run.ID] = *run
+	return nil
+}
+
+func (s *memoryStore) ListRuns(_ context.Context, job string, limit int) ([]models.JobRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	runs := []models.JobRun{}
+	for _, run := range s.runs {
+		if run.Job == job {
+			runs = append(runs, run)
+		}
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].StartedAt > runs[j].StartedAt })
+	if len(runs) > limit {
+		runs = runs[:limit]
+	}
+	return runs, nil
+}
+
+func (s *memoryStore) DeleteRunsBefore(context.Context, int64) error { return nil }
+
+func (s *memoryStore) SetPaused(_ context.Context, job string, paused bool, _ int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paused[job] = paused
+	return nil
+}
+
+func (s *memoryStore) PausedJobs(context.Context) (map[string]bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	paused := make(map[string]bool)
+	for job, p := range s.paused {
+		if p {
+			paused[job] = true
+		}
+	}
+	return paused, nil
+}
+
+func newTestRunner(t *testing.T, locker Locker, store Store) *Runner {
+	t.Helper()
+	runner, err := NewRunner(locker, store, nil)
+	if err != nil {
+		t.Fatalf("erro ao criar runner: %v", err)
+	}
+	return runner
+}
+
+// registerTestJob registra o job com agendamento distante, para que só rode quando chamado
+func registerTestJob(t *testing.T, runner *Runner, name string, job Job) *registeredJob {
+	t.Helper()
+	if err := runner.Register(name, "0 0 1 1 *", time.Second, job); err != nil {
+		t.Fatalf("erro ao registrar job: %v", err)
+	}
+	j, _ := runner.job(name)
+	return j
+}
+
+func TestRunnerRunsSlotOnce(t *testing.T) {
+	locker, store := NewLocalLocker(), newMemoryStore()
+
+	var runs atomic.Int64
+	job := func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}
+
+	slot := time.Date(2025, 3, 10, 3, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		runner := newTestRunner(t, locker, store)
+		runner.runScheduled(registerTestJob(t, runner, "freshness-report", job), slot)
+	}
+	if runs.Load() != 1 {
+		t.Fatalf("horário deveria ser executado uma única vez, executado %d vezes", runs.Load())
+	}
+
+	history, _ := store.ListRuns(context.Background(), "freshness-report", 10)
+	if len(history) != 1 || history[0].Status != RunSuccess || history[0].Trigger != TriggerSchedule {
+		t.Errorf("histórico inesperado: %+v", history)
+	}
+}
+
+func TestRunnerPauseAndManualTrigger(t *testing.T) {
+	store := newMemoryStore()
+	runner := newTestRunner(t, NewLocalLocker(), store)
+
+	release := make(chan struct{})
+	var runs atomic.Int64
+	j := registerTestJob(t, runner, "sunset", func(context.Context) error {
+		runs.Add(1)
+		<-release
+		return errors.New("typesense indisponível")
+	})
+
+	if err := runner.SetPaused(context.Background(), "sunset", true); err != nil {
+		t.Fatalf("erro ao pausar: %v", err)
+	}
+	runner.runScheduled(j, time.Date(2025, 3, 10, 3, 0, 0, 0, time.UTC))
+	if runs.Load() != 0 {
+		t.Fatal("job pausado não deveria executar no horário agendado")
+	}
+
+	// A execução manual ignora a pausa, mas não duas ao mesmo tempo
+	run, err := runner.Trigger(context.Background(), "sunset", "admin")
+	if err != nil || run.Status != RunRunning || run.TriggeredBy != "admin" {
+		t.Fatalf("execução manual inesperada: %+v, %v", run, err)
+	}
+	if _, err := runner.Trigger(context.Background(), "sunset", "admin"); !errors.Is(err, ErrJobRunning) {
+		t.Errorf("esperado ErrJobRunning, obtido %v", err)
+	}
+	close(release)
+
+	deadline := time.Now().Add(time.Second)
+	for j.running.Load() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	history, _ := runner.Runs(context.Background(), "sunset", 10)
+	if history.Total != 1 || history.Runs[0].Status != RunFailed || history.Runs[0].Error == "" {
+		t.Errorf("falha deveria ser registrada: %+v", history)
+	}
+
+	if _, err := runner.Trigger(context.Background(), "inexistente", "admin"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("esperado ErrJobNotFound, obtido %v", err)
+	}
+}
+
+func TestRunnerRestartCancelsStuckRun(t *testing.T) {
+	runner := newTestRunner(t, NewLocalLocker(), newMemoryStore())
+
+	var runs atomic.Int64
+	registerTestJob(t, runner, "noticias-sync", func(ctx context.Context) error {
+		if runs.Add(1) == 1 {
+			<-ctx.Done() // primeira execução travada até ser cancelada
+			return ctx.Err()
+		}
+		return nil
+	})
+
+	first, err := runner.Trigger(context.Background(), "noticias-sync", "admin")
+	if err != nil {
+		t.Fatalf("erro ao executar job: %v", err)
+	}
+	second, err := runner.Restart(context.Background(), "noticias-sync", "plantao", time.Second)
+	if err != nil {
+		t.Fatalf("erro ao reiniciar job: %v", err)
+	}
+	if second.ID == first.ID || second.TriggeredBy != "plantao" {
+		t.Errorf("nova execução inesperada: %+v", second)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runs.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if runs.Load() != 2 {
+		t.Errorf("esperadas 2 execuções, obtidas %d", runs.Load())
+	}
+}
+
+func TestRunnerRestartReportsRunIgnoringCancel(t *testing.T) {
+	runner := newTestRunner(t, NewLocalLocker(), newMemoryStore())
+
+	release := make(chan struct{})
+	defer close(release)
+	registerTestJob(t, runner, "digest", func(context.Context) error {
+		<-release // ignora o cancelamento
+		return nil
+	})
+
+	if _, err := runner.Trigger(context.Background(), "digest", "admin"); err != nil {
+		t.Fatalf("erro ao executar job: %v", err)
+	}
+	if _, err := runner.Restart(context.Background(), "digest", "plantao", 100*time.Millisecond); !errors.Is(err, ErrJobStuck) {
+		t.Errorf("esperado ErrJobStuck, obtido %v", err)
+	}
+}
+
+func TestNewRunnerRejectsInvalidOverride(t *testing.T) {
+	if _, err := NewRunner(NewLocalLocker(), newMemoryStore(), map[string]string{"sunset": "61 * * * *"}); err == nil {
+		t.Error("agendamento inválido deveria ser recusado")
+	}
+}
@@ -0,0 +1,163 @@
+package jobs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule calcula os horários de execução de um job
+type Schedule interface {
+	// Next retorna o primeiro horário de execução estritamente posterior a t
+	Next(t time.Time) time.Time
+}
+
+// ParseSchedule interpreta uma expressão cron de 5 campos (minuto hora dia mês dia-da-semana,
+// com *, listas, intervalos e passos) ou os atalhos @hourly, @daily, @weekly e @every <duração>.
+// Os horários seguem o fuso local do processo.
+func ParseSchedule(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	switch expr {
+	case "@hourly":
+		expr = "0 * * * *"
+	case "@daily":
+		expr = "0 0 * * *"
+	case "@weekly":
+		expr = "0 0 * * 1"
+	}
+
+	if rest, ok := strings.CutPrefix(expr, "@every "); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil || interval < time.Minute {
+			return nil, fmt.Errorf("intervalo inválido em %q (mínimo 1m)", expr)
+		}
+		return intervalSchedule(interval), nil
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expressão cron inválida %q: esperados 5 campos", expr)
+	}
+
+	var s cronSchedule
+	var err error
+	if s.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("minuto inválido em %q: %w", expr, err)
+	}
+	if s.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("hora inválida em %q: %w", expr, err)
+	}
+	if s.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("dia do mês inválido em %q: %w", expr, err)
+	}
+	if s.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("mês inválido em %q: %w", expr, err)
+	}
+	if s.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("dia da semana inválido em %q: %w", expr, err)
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1 // 7 também é domingo
+	}
+	s.domAny = fields[2] == "*"
+	s.dowAny = fields[4] == "*"
+
+	return s, nil
+}
+
+// intervalSchedule executa a cada intervalo, alinhado ao relógio (ex.: 15m → :00, :15, ...)
+type intervalSchedule time.Duration
+
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	return t.Truncate(time.Duration(s)).Add(time.Duration(s))
+}
+
+// cronSchedule guarda os valores aceitos de cada campo como bits
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+func (s cronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	// Limite de busca: qualquer expressão válida ocorre em até 5 anos (ex.: 29 de fevereiro)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// matchDay segue a regra do cron: com dia do mês e dia da semana restritos, basta um coincidir
+func (s cronSchedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// parseCronField converte um campo (ex.: "*/15", "1-5", "0,30") em bits
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if before, after, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(after)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("passo inválido %q", part)
+			}
+			rangePart, step = before, n
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = strconv.Atoi(from); err != nil {
+				return 0, fmt.Errorf("valor inválido %q", part)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(to); err != nil {
+					return 0, fmt.Errorf("valor inválido %q", part)
+				}
+			} else if step > 1 {
+				hi = max // "5/10" equivale a "5-max/10"
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("valor fora do intervalo %d-%d: %q", min, max, part)
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
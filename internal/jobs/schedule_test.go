@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 7, 30, 0, time.UTC) // segunda-feira
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"@every 15m", time.Date(2025, 3, 10, 14, 15, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, 3, 10, 14, 15, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2025, 3, 11, 3, 0, 0, 0, time.UTC)},
+		{"0 20 * * *", time.Date(2025, 3, 10, 20, 0, 0, 0, time.UTC)},
+		{"30 8 * * 1-5", time.Date(2025, 3, 11, 8, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * 0", time.Date(2025, 3, 16, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 7", time.Date(2025, 3, 16, 9, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		schedule, err := ParseSchedule(tt.expr)
+		if err != nil {
+			t.Errorf("%q: erro inesperado: %v", tt.expr, err)
+			continue
+		}
+		if got := schedule.Next(now); !got.Equal(tt.want) {
+			t.Errorf("%q: próximo horário %s, esperado %s", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "0 24 * * *", "5-1 * * * *", "*/0 * * * *", "@every 10s"} {
+		if _, err := ParseSchedule(expr); err == nil {
+			t.Errorf("%q deveria ser recusada", expr)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+// Collection guarda o histórico de execuções e a pausa dos jobs, compartilhados entre as réplicas
+const Collection = "_jobs"
+
+// Tipos de documento da collection _jobs
+const (
+	kindRun   = "run"
+	kindState = "state"
+)
+
+// Store persiste o histórico de execuções e o estado (pausa) dos jobs
+type Store interface {
+	SaveRun(ctx context.Context, run *models.JobRun) error
+	ListRuns(ctx context.Context, job string, limit int) ([]models.JobRun, error)
+	DeleteRunsBefore(ctx context.Context, before int64) error
+	SetPaused(ctx context.Context, job string, paused bool, updatedAt int64) error
+	PausedJobs(ctx context.Context) (map[string]bool, error)
+}
+
+// TypesenseStore implementa o Store na collection _jobs
+type TypesenseStore struct {
+	client *typesense.Client
+}
+
+// NewTypesenseStore cria o store sobre o cliente Typesense
+func NewTypesenseStore(client *typesense.Client) *TypesenseStore {
+	return &TypesenseStore{client: client}
+}
+
+// SaveRun grava (ou atualiza) a execução
+func (s *TypesenseStore) SaveRun(ctx context.Context, run *models.JobRun) error {
+	if err := s.ensureCollection(ctx); err != nil {
+		return err
+	}
+
+	doc := map[string]interface{}{
+		"id":           run.ID,
+		"kind":         kindRun,
+		"job":          run.Job,
+		"status":       run.Status,
+		"trigger":      run.Trigger,
+		"triggered_by": run.TriggeredBy,
+		"owner":        run.Owner,
+		"started_at":   run.StartedAt,
+		"finished_at":  run.FinishedAt,
+		"duration_ms":  run.DurationMs,
+		"error":        run.Error,
+		"updated_at":   run.StartedAt,
+	}
+	if _, err := s.client.Collection(Collection).Documents().Upsert(ctx, doc, &api.DocumentIndexParameters{}); err != nil {
+		return fmt.Errorf("erro ao registrar execução do job %s: %w", run.Job, err)
+	}
+	return nil
+}
+
+// ListRuns retorna as últimas execuções do job, das mais recentes para as mais antigas
+func (s *TypesenseStore) ListRuns(ctx context.Context, job string, limit int) ([]models.JobRun, error) {
+	if err := s.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := s.client.Collection(Collection).Documents().Search(ctx, &api.SearchCollectionParams{
+		Q:        pointer.String("*"),
+		FilterBy: pointer.String(fmt.Sprintf("kind:=%s && job:=`%s`", kindRun, job)),
+		SortBy:   pointer.String("started_at:desc"),
+		PerPage:  pointer.Int(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar execuções do job %s: %w", job, err)
+	}
+
+	runs := []models.JobRun{}
+	if result.Hits != nil {
+		for _, hit := range *result.Hits {
+			if hit.Document != nil {
+				runs = append(runs, runFromDocument(*hit.Document))
+			}
+		}
+	}
+	return runs, nil
+}
+
+// DeleteRunsBefore remove as execuções iniciadas antes do timestamp
+func (s *TypesenseStore) DeleteRunsBefore(ctx context.Context, before int64) error {
+	if err := s.ensureCollection(ctx); err != nil {
+		return err
+	}
+
+	filter := fmt.Sprintf("kind:=%s && started_at:<%d", kindRun, before)
+	if _, err := s.client.Collection(Collection).Documents().Delete(ctx, &api.DeleteDocumentsParams{FilterBy: pointer.String(filter)}); err != nil {
+		return fmt.Errorf("erro ao remover execuções antigas: %w", err)
+	}
+	return nil
+}
+
+// SetPaused grava a pausa do job, respeitada por todas as réplicas
+func (s *TypesenseStore) SetPaused(ctx context.Context, job string, paused bool, updatedAt int64) error {
+	if err := s.ensureCollection(ctx); err != nil {
+		return err
+	}
+
+	doc := map[string]interface{}{
+		"id":         "state-" + job,
+		"kind":       kindState,
+		"job":        job,
+		"paused":     paused,
+		"updated_at": updatedAt,
+	}
+	if _, err := s.client.Collection(Collection).Documents().Upsert(ctx, doc, &api.DocumentIndexParameters{}); err != nil {
+		return fmt.Errorf("erro ao gravar pausa do job %s: %w", job, err)
+	}
+	return nil
+}
+
+// PausedJobs retorna os jobs pausados
+func (s *TypesenseStore) PausedJobs(ctx context.Context) (map[string]bool, error) {
+	if err := s.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := s.client.Collection(Collection).Documents().Search(ctx, &api.SearchCollectionParams{
+		Q:        pointer.String("*"),
+		FilterBy: pointer.String(fmt.Sprintf("kind:=%s && paused:=true", kindState)),
+		PerPage:  pointer.Int(250),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar jobs pausados: %w", err)
+	}
+
+	paused := make(map[string]bool)
+	if result.Hits != nil {
+		for _, hit := range *result.Hits {
+			if hit.Document == nil {
+				continue
+			}
+			if job, ok := (*hit.Document)["job"].(string); ok {
+				paused[job] = true
+			}
+		}
+	}
+	return paused, nil
+}
+
+// ensureCollection garante que a collection _jobs existe
+func (s *TypesenseStore) ensureCollection(ctx context.Context) error {
+	_, err := s.client.Collection(Collection).Retrieve(ctx)
+	if err == nil {
+		return nil
+	}
+
+	schema := &api.CollectionSchema{
+		Name: Collection,
+		Fields: []api.Field{
+			{Name: "kind", Type: "string", Facet: pointer.True()},
+			{Name: "job", Type: "string", Facet: pointer.True()},
+			{Name: "status", Type: "string", Facet: pointer.True(), Optional: pointer.True()},
+			{Name: "trigger", Type: "string", Facet: pointer.True(), Optional: pointer.True()},
+			{Name: "triggered_by", Type: "string", Optional: pointer.True()},
+			{Name: "owner", Type: "string", Optional: pointer.True()},
+			{Name: "started_at", Type: "int64", Optional: pointer.True()},
+			{Name: "finished_at", Type: "int64", Optional: pointer.True()},
+			{Name: "duration_ms", Type: "int64", Optional: pointer.True()},
+			{Name: "error", Type: "string", Optional: pointer.True()},
+			{Name: "paused", Type: "bool", Facet: pointer.True(), Optional: pointer.True()},
+			{Name: "updated_at", Type: "int64", Facet: pointer.False()},
+		},
+		DefaultSortingField: pointer.String("updated_at"),
+	}
+
+	if _, err := s.client.Collections().Create(ctx, schema); err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %w", Collection, err)
+	}
+	return nil
+}
+
+func runFromDocument(doc map[string]interface{}) models.JobRun {
+	run := models.JobRun{}
+	run.ID, _ = doc["id"].(string)
+	run.Job, _ = doc["job"].(string)
+	run.Status, _ = doc["status"].(string)
+	run.Trigger, _ = doc["trigger"].(string)
+	run.TriggeredBy, _ = doc["triggered_by"].(string)
+	run.Owner, _ = doc["owner"].(string)
+	run.Error, _ = doc["error"].(string)
+	if value, ok := doc["started_at"].(float64); ok {
+		run.StartedAt = int64(value)
+	}
+	if value, ok := doc["finished_at"].(float64); ok {
+		run.FinishedAt = int64(value)
+	}
+	if value, ok := doc["duration_ms"].(float64); ok {
+		run.DurationMs = int64(value)
+	}
+	return run
+}
@@ -0,0 +1,210 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
+)
+
+const (
+	// ProtocolVersion versão do Model Context Protocol suportada
+	ProtocolVersion = "2024-11-05"
+
+	serverName    = "app-busca-search"
+	serverVersion = "1.0.0"
+)
+
+// Códigos de erro JSON-RPC 2.0
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// rpcRequest representa uma requisição (ou notificação) JSON-RPC 2.0
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcResponse representa uma resposta JSON-RPC 2.0
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError representa um erro JSON-RPC 2.0
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Server expõe o mecanismo de busca como servidor MCP
+type Server struct {
+	searchService   *services.SearchService
+	categoryService *services.CategoryService
+	typesenseClient *typesense.Client
+	tools           []Tool
+}
+
+// NewServer cria um novo servidor MCP
+func NewServer(searchService *services.SearchService, categoryService *services.CategoryService, typesenseClient *typesense.Client) *Server {
+	s := &Server{
+		searchService:   searchService,
+		categoryService: categoryService,
+		typesenseClient: typesenseClient,
+	}
+	s.tools = s.buildTools()
+	return s
+}
+
+// ServeStdio processa mensagens JSON-RPC delimitadas por linha (transporte stdio)
+func (s *Server) ServeStdio(ctx context.Context, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	var mu sync.Mutex
+	encoder := json.NewEncoder(out)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		response := s.HandleMessage(ctx, line)
+		if response == nil {
+			continue
+		}
+
+		mu.Lock()
+		err := encoder.Encode(response)
+		mu.Unlock()
+		if err != nil {
+			return fmt.Errorf("erro ao escrever resposta: %v", err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("erro ao ler entrada: %v", err)
+	}
+
+	return nil
+}
+
+// HandleMessage processa uma mensagem JSON-RPC e retorna a resposta (nil para notificações)
+func (s *Server) HandleMessage(ctx context.Context, data []byte) *rpcResponse {
+	var req rpcRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return errorResponse(json.RawMessage("null"), codeParseError, "JSON inválido")
+	}
+
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(req.ID, codeInvalidRequest, "requisição JSON-RPC inválida")
+	}
+
+	// Notificações não possuem id e não recebem resposta
+	isNotification := len(req.ID) == 0
+
+	result, rpcErr := s.dispatch(ctx, &req)
+	if isNotification {
+		return nil
+	}
+
+	if rpcErr != nil {
+		return errorResponse(req.ID, rpcErr.Code, rpcErr.Message)
+	}
+
+	return &rpcResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  result,
+	}
+}
+
+// dispatch encaminha a requisição para o método correspondente
+func (s *Server) dispatch(ctx context.Context, req *rpcRequest) (interface{}, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": ProtocolVersion,
+			"capabilities": map[string]interface{}{
+				"tools": map[string]interface{}{},
+			},
+			"serverInfo": map[string]interface{}{
+				"name":    serverName,
+				"version": serverVersion,
+			},
+		}, nil
+	case "notifications/initialized", "notifications/cancelled":
+		return nil, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": s.tools}, nil
+	case "tools/call":
+		var params toolCallParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("parâmetros inválidos: %v", err)}
+		}
+		return s.callTool(ctx, &params)
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("método '%s' não suportado", req.Method)}
+	}
+}
+
+// callTool executa uma ferramenta e converte o resultado para o formato MCP
+func (s *Server) callTool(ctx context.Context, params *toolCallParams) (interface{}, *rpcError) {
+	tool := s.findTool(params.Name)
+	if tool == nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("ferramenta '%s' não encontrada", params.Name)}
+	}
+
+	args := params.Arguments
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+
+	result, err := tool.handler(ctx, args)
+	if err != nil {
+		// Erros de execução vão no resultado para que o modelo possa reagir
+		log.Printf("[MCP] Erro na ferramenta %s: %v", params.Name, err)
+		return toolResult{
+			Content: []toolContent{{Type: "text", Text: err.Error()}},
+			IsError: true,
+		}, nil
+	}
+
+	text, err := json.Marshal(result)
+	if err != nil {
+		return nil, &rpcError{Code: codeInternalError, Message: fmt.Sprintf("erro ao serializar resultado: %v", err)}
+	}
+
+	return toolResult{
+		Content: []toolContent{{Type: "text", Text: string(text)}},
+	}, nil
+}
+
+func errorResponse(id json.RawMessage, code int, message string) *rpcResponse {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &rpcResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   &rpcError{Code: code, Message: message},
+	}
+}
@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestHandleMessage(t *testing.T) {
+	s := NewServer(nil, nil, nil)
+	ctx := context.Background()
+
+	t.Run("initialize retorna capabilities", func(t *testing.T) {
+		resp := s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`))
+		if resp == nil || resp.Error != nil {
+			t.Fatalf("resposta inesperada: %+v", resp)
+		}
+		result := resp.Result.(map[string]interface{})
+		if result["protocolVersion"] != ProtocolVersion {
+			t.Errorf("protocolVersion = %v, esperado %s", result["protocolVersion"], ProtocolVersion)
+		}
+	})
+
+	t.Run("notificação não gera resposta", func(t *testing.T) {
+		resp := s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+		if resp != nil {
+			t.Errorf("esperado nil, obtido %+v", resp)
+		}
+	})
+
+	t.Run("tools/list lista as ferramentas", func(t *testing.T) {
+		resp := s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`))
+		data, _ := json.Marshal(resp.Result)
+		for _, name := range []string{"search", "get_document", "list_categories"} {
+			if !strings.Contains(string(data), `"name":"`+name+`"`) {
+				t.Errorf("ferramenta %s ausente em %s", name, data)
+			}
+		}
+	})
+
+	t.Run("método desconhecido", func(t *testing.T) {
+		resp := s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":3,"method":"foo"}`))
+		if resp.Error == nil || resp.Error.Code != codeMethodNotFound {
+			t.Errorf("esperado erro %d, obtido %+v", codeMethodNotFound, resp.Error)
+		}
+	})
+
+	t.Run("JSON inválido", func(t *testing.T) {
+		resp := s.HandleMessage(ctx, []byte(`{`))
+		if resp.Error == nil || resp.Error.Code != codeParseError {
+			t.Errorf("esperado erro %d, obtido %+v", codeParseError, resp.Error)
+		}
+	})
+
+	t.Run("argumento obrigatório ausente vira erro da ferramenta", func(t *testing.T) {
+		resp := s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"search","arguments":{}}}`))
+		result, ok := resp.Result.(toolResult)
+		if !ok || !result.IsError {
+			t.Errorf("esperado resultado com isError, obtido %+v", resp)
+		}
+	})
+}
+
+func TestServeStdio(t *testing.T) {
+	s := NewServer(nil, nil, nil)
+	in := strings.NewReader("{\"jsonrpc\":\"2.0\",\"id\":1,\"method\":\"ping\"}\n\n{\"jsonrpc\":\"2.0\",\"method\":\"notifications/initialized\"}\n")
+	var out bytes.Buffer
+
+	if err := s.ServeStdio(context.Background(), in, &out); err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("esperada 1 resposta, obtidas %d: %q", len(lines), out.String())
+	}
+	if !strings.Contains(lines[0], `"id":1`) {
+		t.Errorf("resposta sem id: %s", lines[0])
+	}
+}
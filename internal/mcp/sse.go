@@ -0,0 +1,167 @@
+package mcp
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
+)
+
+// sseSession representa uma conexão SSE aberta por um cliente MCP
+type sseSession struct {
+	messages chan []byte
+	tenantID string
+}
+
+// Authenticator identifica o tenant da credencial de uma requisição HTTP; ok é false quando a
+// credencial está ausente ou é inválida
+type Authenticator func(r *http.Request) (tenantID string, ok bool)
+
+// APIKeyAuthenticator aceita as mesmas API keys de tenant da API HTTP (X-API-Key ou
+// Authorization: Bearer) e o MCP_TOKEN, que opera no tenant padrão
+func APIKeyAuthenticator(cfg *config.Config) Authenticator {
+	return func(r *http.Request) (string, bool) {
+		credential := r.Header.Get("X-API-Key")
+		if credential == "" {
+			credential = strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		}
+		if credential == "" {
+			return "", false
+		}
+
+		if tenantID, ok := cfg.TenantForAPIKey(credential); ok {
+			return tenantID, true
+		}
+		if cfg.MCPToken != "" && subtle.ConstantTimeCompare([]byte(credential), []byte(cfg.MCPToken)) == 1 {
+			return tenant.DefaultID, true
+		}
+		return "", false
+	}
+}
+
+// SSEHandler implementa o transporte HTTP+SSE do MCP:
+// GET /sse abre o stream e POST /message?session_id=... envia requisições.
+// As duas rotas exigem credencial; as mensagens de uma sessão só são aceitas do mesmo tenant
+// que abriu o stream e as ferramentas consultam apenas os documentos desse tenant.
+type SSEHandler struct {
+	server       *Server
+	authenticate Authenticator
+	mu           sync.RWMutex
+	sessions     map[string]*sseSession
+}
+
+// NewSSEHandler cria um novo handler HTTP para o transporte SSE
+func NewSSEHandler(server *Server, authenticate Authenticator) *SSEHandler {
+	return &SSEHandler{
+		server:       server,
+		authenticate: authenticate,
+		sessions:     make(map[string]*sseSession),
+	}
+}
+
+// ServeHTTP autentica a requisição e roteia entre o stream SSE e o endpoint de mensagens
+func (h *SSEHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := h.authenticate(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "credencial ausente ou inválida", http.StatusUnauthorized)
+		return
+	}
+	r = r.WithContext(tenant.WithTenant(r.Context(), tenantID))
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/sse":
+		h.handleStream(w, r)
+	case r.Method == http.MethodPost && r.URL.Path == "/message":
+		h.handleMessage(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *SSEHandler) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming não suportado", http.StatusInternalServerError)
+		return
+	}
+
+	sessionID := uuid.New().String()
+	tenantID, _ := tenant.FromContext(r.Context())
+	session := &sseSession{messages: make(chan []byte, 16), tenantID: tenantID}
+
+	h.mu.Lock()
+	h.sessions[sessionID] = session
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		delete(h.sessions, sessionID)
+		h.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	// Primeiro evento informa ao cliente onde enviar as mensagens
+	fmt.Fprintf(w, "event: endpoint\ndata: /message?session_id=%s\n\n", sessionID)
+	flusher.Flush()
+
+	log.Printf("[MCP] Sessão SSE iniciada: %s", sessionID)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			log.Printf("[MCP] Sessão SSE encerrada: %s", sessionID)
+			return
+		case msg := <-session.messages:
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", msg)
+			flusher.Flush()
+		}
+	}
+}
+
+func (h *SSEHandler) handleMessage(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.URL.Query().Get("session_id")
+
+	h.mu.RLock()
+	session, exists := h.sessions[sessionID]
+	h.mu.RUnlock()
+
+	if tenantID, _ := tenant.FromContext(r.Context()); !exists || session.tenantID != tenantID {
+		http.Error(w, "sessão não encontrada", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 10*1024*1024))
+	if err != nil {
+		http.Error(w, "erro ao ler corpo da requisição", http.StatusBadRequest)
+		return
+	}
+
+	response := h.server.HandleMessage(r.Context(), body)
+	if response != nil {
+		data, err := json.Marshal(response)
+		if err != nil {
+			http.Error(w, "erro ao serializar resposta", http.StatusInternalServerError)
+			return
+		}
+
+		select {
+		case session.messages <- data:
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,43 @@
+package mcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+)
+
+func TestSSEHandlerAuth(t *testing.T) {
+	cfg := &config.Config{
+		MCPToken: "token-mcp",
+		Tenants:  map[string]*config.TenantConfig{"niteroi": {APIKeys: []string{"chave-niteroi"}}},
+	}
+	h := NewSSEHandler(NewServer(nil, nil, nil), APIKeyAuthenticator(cfg))
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		status int
+	}{
+		{"sem credencial", "", "", http.StatusUnauthorized},
+		{"token inválido", "Authorization", "Bearer outro", http.StatusUnauthorized},
+		{"MCP_TOKEN", "Authorization", "Bearer token-mcp", http.StatusNotFound},
+		{"API key de tenant", "X-API-Key", "chave-niteroi", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Sessão inexistente: autenticado recebe 404, não autenticado 401
+			req := httptest.NewRequest(http.MethodPost, "/message?session_id=x", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, esperado %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/typesense"
+)
+
+// Tool descreve uma ferramenta exposta via MCP
+type Tool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+
+	handler func(ctx context.Context, args map[string]interface{}) (interface{}, error)
+}
+
+type toolCallParams struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+type toolContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type toolResult struct {
+	Content []toolContent `json:"content"`
+	IsError bool          `json:"isError,omitempty"`
+}
+
+// buildTools registra as ferramentas disponíveis (REGISTRAR AQUI NOVAS FERRAMENTAS)
+func (s *Server) buildTools() []Tool {
+	return []Tool{
+		{
+			Name:        "search",
+			Description: "Busca serviços públicos da Prefeitura do Rio no catálogo municipal. Retorna título, descrição, categoria e metadados de cada serviço.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "Texto da busca",
+					},
+					"type": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"keyword", "semantic", "hybrid", "ai"},
+						"description": "Tipo de busca (padrão: hybrid)",
+					},
+					"page": map[string]interface{}{
+						"type":        "integer",
+						"description": "Número da página (padrão: 1)",
+					},
+					"per_page": map[string]interface{}{
+						"type":        "integer",
+						"description": "Resultados por página (padrão: 10, máximo: 100)",
+					},
+				},
+				"required": []string{"query"},
+			},
+			handler: s.toolSearch,
+		},
+		{
+			Name:        "get_document",
+			Description: "Retorna os detalhes completos de um serviço a partir do seu ID (UUID) ou slug.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"id": map[string]interface{}{
+						"type":        "string",
+						"description": "UUID ou slug do serviço",
+					},
+				},
+				"required": []string{"id"},
+			},
+			handler: s.toolGetDocument,
+		},
+		{
+			Name:        "list_categories",
+			Description: "Lista as categorias de serviços com a quantidade de serviços publicados em cada uma.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"sort_by": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"popularity", "count", "alpha"},
+						"description": "Critério de ordenação (padrão: popularity)",
+					},
+				},
+			},
+			handler: s.toolListCategories,
+		},
+	}
+}
+
+func (s *Server) findTool(name string) *Tool {
+	for i := range s.tools {
+		if s.tools[i].Name == name {
+			return &s.tools[i]
+		}
+	}
+	return nil
+}
+
+// maxSearchPerPage limite de resultados por página, o mesmo do endpoint /api/v1/search
+const maxSearchPerPage = 100
+
+// toolSearch executa a busca usando o mesmo serviço do endpoint /api/v1/search
+func (s *Server) toolSearch(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	query := strings.TrimSpace(argString(args, "query"))
+	if query == "" {
+		return nil, fmt.Errorf("parâmetro 'query' é obrigatório")
+	}
+
+	searchType := models.SearchType(argString(args, "type"))
+	if searchType == "" {
+		searchType = models.SearchTypeHybrid
+	}
+
+	switch searchType {
+	case models.SearchTypeKeyword, models.SearchTypeSemantic, models.SearchTypeHybrid, models.SearchTypeAI:
+	default:
+		return nil, fmt.Errorf("tipo de busca inválido: %s (válidos: keyword, semantic, hybrid, ai)", searchType)
+	}
+
+	excludeAgentExclusive := false
+	req := &models.SearchRequest{
+		Query:                 query,
+		Type:                  searchType,
+		Page:                  argInt(args, "page", 1),
+		PerPage:               min(argInt(args, "per_page", 10), maxSearchPerPage),
+		ExcludeAgentExclusive: &excludeAgentExclusive,
+	}
+
+	return s.searchService.Search(ctx, req)
+}
+
+// toolGetDocument busca um serviço publicado por ID, com fallback para slug. Rascunhos e
+// serviços despublicados são tratados como inexistentes, como nas rotas públicas
+func (s *Server) toolGetDocument(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	id := strings.TrimSpace(argString(args, "id"))
+	if id == "" {
+		return nil, fmt.Errorf("parâmetro 'id' é obrigatório")
+	}
+
+	service, err := s.typesenseClient.GetPrefRioService(ctx, id)
+	// Apenas um ID inexistente cai no slug; falhas de rede ou do Typesense são devolvidas
+	if errors.Is(err, typesense.ErrServiceNotFound) {
+		service, err = s.typesenseClient.GetPrefRioServiceBySlug(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if service == nil || service.Status != 1 {
+		return nil, fmt.Errorf("serviço não encontrado: %s", id)
+	}
+
+	return service, nil
+}
+
+// toolListCategories lista categorias via facet search
+func (s *Server) toolListCategories(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	req := &models.CategoryRequest{
+		SortBy: argString(args, "sort_by"),
+	}
+
+	return s.categoryService.GetCategories(ctx, req)
+}
+
+func argString(args map[string]interface{}, key string) string {
+	if v, ok := args[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func argInt(args map[string]interface{}, key string, defaultValue int) int {
+	switch v := args[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return defaultValue
+}
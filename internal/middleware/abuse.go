@@ -0,0 +1,52 @@
+package middlewares
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// AbusiveQueryKey marca no contexto Gin que a busca foi classificada como ofensiva
+const AbusiveQueryKey = "abusive_query"
+
+// AbuseFilter classifica a query (parâmetro q) das buscas: queries ofensivas são atendidas só
+// pela busca textual e contam como incidente do cliente; clientes que excedem o limite de
+// incidentes recebem 429 até o fim do bloqueio
+func AbuseFilter(filter *services.AbuseFilter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if filter == nil {
+			c.Next()
+			return
+		}
+
+		clientID := abuseClientID(c)
+		if remaining := filter.BlockedFor(clientID); remaining > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(remaining.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Muitas buscas com conteúdo ofensivo. Tente novamente mais tarde."})
+			c.Abort()
+			return
+		}
+
+		if filter.Detect(c.Query("q")) {
+			filter.RecordIncident(clientID)
+			c.Set(AbusiveQueryKey, true)
+			c.Request = c.Request.WithContext(services.WithAbusiveQuery(c.Request.Context()))
+		}
+
+		c.Next()
+	}
+}
+
+// abuseClientID identifica o cliente pela API key (apenas o hash é mantido) ou pelo IP
+func abuseClientID(c *gin.Context) string {
+	if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+		sum := sha256.Sum256([]byte(apiKey))
+		return "key:" + hex.EncodeToString(sum[:6])
+	}
+	return "ip:" + c.ClientIP()
+}
@@ -0,0 +1,45 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// APIKeyQuota aplica a quota diária das API keys de tenant (X-API-Key). As respostas informam
+// o limite e o consumo nos headers X-Quota-*; perto do limite ou usando o burst, X-Quota-Warning
+// explica a situação. Só as chamadas além da quota e do burst recebem 429, até a virada do dia.
+func APIKeyQuota(cfg *config.Config, quotas *services.APIKeyQuotas) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := c.GetHeader("X-API-Key")
+		if _, ok := cfg.TenantForAPIKey(apiKey); quotas == nil || !ok {
+			c.Next()
+			return
+		}
+
+		decision := quotas.Check(c.Request.Context(), config.APIKeyID(apiKey))
+		if decision == nil {
+			c.Next()
+			return
+		}
+
+		c.Header("X-Quota-Limit", strconv.Itoa(decision.Limit))
+		c.Header("X-Quota-Remaining", strconv.FormatInt(decision.Remaining, 10))
+		c.Header("X-Quota-Reset", strconv.FormatInt(decision.ResetAt.Unix(), 10))
+		if decision.Warning != "" {
+			c.Header("X-Quota-Warning", decision.Warning)
+		}
+
+		if decision.Blocked {
+			c.Header("Retry-After", strconv.Itoa(int(time.Until(decision.ResetAt).Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Quota diária da API key esgotada. Tente novamente após a renovação."})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,39 @@
+package middlewares
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// APIKeyUsage registra rota, status e latência das requisições feitas com API keys de tenant
+// (X-API-Key) para os relatórios de uso por key. Requisições sem key, com key desconhecida ou
+// para rotas inexistentes não são registradas.
+func APIKeyUsage(cfg *config.Config, usage *services.APIKeyUsage) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := c.GetHeader("X-API-Key")
+		tenantID, ok := cfg.TenantForAPIKey(apiKey)
+		if usage == nil || !ok {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+
+		if c.FullPath() == "" {
+			return
+		}
+		usage.Record(models.APIKeyUsageEvent{
+			KeyID:     config.APIKeyID(apiKey),
+			Tenant:    tenantID,
+			Route:     c.Request.Method + " " + c.FullPath(),
+			Status:    c.Writer.Status(),
+			LatencyMs: time.Since(start).Milliseconds(),
+			Timestamp: start.Unix(),
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxBodySize limita o tamanho do corpo das requisições: corpos com Content-Length acima do
+// limite recebem 413 sem serem lidos e os demais são interrompidos ao exceder o limite.
+// routeLimits define limites próprios por rota (caminho registrado no Gin, ex.: uploads).
+func MaxBodySize(limit int64, routeLimits map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		routeLimit := limit
+		if custom, ok := routeLimits[c.FullPath()]; ok {
+			routeLimit = custom
+		}
+		if routeLimit <= 0 {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > routeLimit {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Corpo da requisição excede o limite de %d KB", routeLimit>>10)})
+			c.Abort()
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, routeLimit)
+		c.Next()
+	}
+}
@@ -0,0 +1,33 @@
+package middlewares
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// noCacheParam parâmetro que pede respostas recalculadas, fora do cache
+const noCacheParam = "no_cache"
+
+// CacheBypass atende no_cache=true apenas de administradores (JWT com go:admin): a requisição
+// ignora as respostas em cache e a resposta recalculada substitui a armazenada. Para os demais o
+// parâmetro é ignorado, evitando que clientes públicos forcem buscas caras.
+func CacheBypass() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Query(noCacheParam) == "true" && isAdminToken(c.GetHeader("Authorization")) {
+			c.Request = c.Request.WithContext(services.WithCacheBypass(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
+
+// isAdminToken indica se o JWT do header Authorization tem a role go:admin. Assim como em
+// JWTAuthMiddleware, a assinatura é validada pelo Istio.
+func isAdminToken(authHeader string) bool {
+	if authHeader == "" {
+		return false
+	}
+	claims, err := parseJWTClaims(strings.TrimPrefix(authHeader, "Bearer "))
+	return err == nil && extractPrimaryRole(claims) == "ADMIN"
+}
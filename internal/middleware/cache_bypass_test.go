@@ -0,0 +1,46 @@
+package middlewares
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+func TestCacheBypassOnlyForAdmins(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CacheBypass())
+
+	var bypassed bool
+	router.GET("/busca", func(c *gin.Context) {
+		bypassed = services.CacheBypassed(c.Request.Context())
+	})
+
+	token := func(role string) string {
+		payload := base64.RawURLEncoding.EncodeToString([]byte(`{"preferred_username":"12345678900","resource_access":{"superapp":{"roles":["` + role + `"]}}}`))
+		return "Bearer header." + payload + ".assinatura"
+	}
+	cases := []struct {
+		name, url, auth string
+		expected        bool
+	}{
+		{"admin", "/busca?no_cache=true", token("go:admin"), true},
+		{"admin sem no_cache", "/busca", token("go:admin"), false},
+		{"usuário", "/busca?no_cache=true", token("admin:login"), false},
+		{"anônimo", "/busca?no_cache=true", "", false},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+		if tc.auth != "" {
+			req.Header.Set("Authorization", tc.auth)
+		}
+		router.ServeHTTP(httptest.NewRecorder(), req)
+		if bypassed != tc.expected {
+			t.Errorf("%s: esperado bypass=%v, obtido %v", tc.name, tc.expected, bypassed)
+		}
+	}
+}
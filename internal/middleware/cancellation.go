@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// canceledRequests total de requisições abandonadas pelo cliente desde o início do processo
+var canceledRequests atomic.Int64
+
+// CanceledRequests retorna o total de requisições canceladas pelo cliente (ver RequestCancellation)
+func CanceledRequests() int64 {
+	return canceledRequests.Load()
+}
+
+// RequestCancellation conta as requisições cujo cliente desconectou antes da resposta. Os
+// handlers de leitura repassam c.Request.Context() ao Gemini e ao Typesense, que interrompem o
+// trabalho ao cancelamento; escritas usam writeContext e seguem até o fim.
+func RequestCancellation() gin.HandlerFunc {
+	counter, _ := otel.Meter("http").Int64Counter(
+		"http.canceled_requests",
+		metric.WithDescription("Requisições canceladas pelo cliente antes da resposta, por rota"),
+	)
+	return func(c *gin.Context) {
+		c.Next()
+
+		if !errors.Is(c.Request.Context().Err(), context.Canceled) {
+			return
+		}
+		canceledRequests.Add(1)
+		if counter != nil {
+			counter.Add(context.WithoutCancel(c.Request.Context()), 1, metric.WithAttributes(attribute.String("http.route", c.FullPath())))
+		}
+	}
+}
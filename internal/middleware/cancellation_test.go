@@ -0,0 +1,46 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestCancellationCountsCanceledRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestCancellation())
+
+	var handlerErr error
+	router.GET("/busca", func(c *gin.Context) {
+		// simula o trabalho (Gemini/Typesense) interrompido pelo contexto da requisição
+		<-c.Request.Context().Done()
+		handlerErr = c.Request.Context().Err()
+		c.Status(http.StatusRequestTimeout)
+	})
+	router.GET("/ok", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	before := CanceledRequests()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodGet, "/busca", nil).WithContext(ctx)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if handlerErr != context.Canceled {
+		t.Errorf("esperado cancelamento propagado ao handler, obtido %v", handlerErr)
+	}
+	if got := CanceledRequests() - before; got != 1 {
+		t.Errorf("esperada 1 requisição cancelada, obtidas %d", got)
+	}
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+	if got := CanceledRequests() - before; got != 1 {
+		t.Errorf("requisição concluída não deve ser contada, obtidas %d", got)
+	}
+}
@@ -0,0 +1,26 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LegacyFrozenResponse resposta das operações recusadas com as collections legadas congeladas
+var LegacyFrozenResponse = gin.H{
+	"error":   "Collections legadas congeladas",
+	"message": "As collections legadas estão somente leitura (LEGACY_COLLECTIONS_FROZEN). Tombamentos não podem mais ser criados, alterados ou removidos.",
+	"code":    "LEGACY_COLLECTIONS_FROZEN",
+}
+
+// LegacyFreeze retorna um handler Gin que, com frozen, recusa as operações CUD. Aplicado às
+// rotas que alteram o que as collections legadas servem (tombamentos).
+func LegacyFreeze(frozen bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if frozen && isCUDMethod(c.Request.Method) {
+			c.AbortWithStatusJSON(http.StatusConflict, LegacyFrozenResponse)
+			return
+		}
+		c.Next()
+	}
+}
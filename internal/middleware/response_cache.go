@@ -0,0 +1,103 @@
+package middlewares
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
+)
+
+// cachingWriter copia o corpo da resposta enquanto ele é enviado ao cliente
+type cachingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *cachingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *cachingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// CacheResponse retorna um handler Gin que serve respostas GET do cache, usando
+// o path e os parâmetros da query como chave. Apenas respostas 200 são armazenadas.
+// Requisições com no_cache de administradores (CacheBypass) não leem o cache, mas
+// atualizam a entrada com a resposta recalculada.
+func CacheResponse(cache *services.ResponseCache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cache == nil || c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		// Encode ordena os parâmetros, então a ordem na URL não altera a chave
+		query := c.Request.URL.Query()
+		query.Del(noCacheParam)
+		key := c.Request.URL.Path + "?" + query.Encode()
+		ctx := c.Request.Context()
+		if tenantID, ok := tenant.FromContext(ctx); ok {
+			key = tenantID + ":" + key
+		}
+
+		bypass := services.CacheBypassed(ctx)
+		if !bypass {
+			if data, ok := cache.Get(ctx, key); ok {
+				c.Header("X-Cache", "HIT")
+				c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+				c.Abort()
+				return
+			}
+		}
+
+		generation := cache.Generation()
+		writer := &cachingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		if bypass {
+			c.Header("X-Cache", "BYPASS")
+		} else {
+			c.Header("X-Cache", "MISS")
+		}
+
+		c.Next()
+
+		if writer.Status() == http.StatusOK && writer.body.Len() > 0 {
+			cache.Set(ctx, key, generation, writer.body.Bytes())
+		}
+	}
+}
+
+// PublishDocumentEvents retorna um handler Gin que publica um evento no EventBus
+// para cada operação CUD concluída com sucesso na collection informada
+func PublishDocumentEvents(bus *services.EventBus, collection string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if bus == nil || !isCUDMethod(c.Request.Method) || c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+
+		eventType := services.DocumentUpdated
+		switch c.Request.Method {
+		case http.MethodPost:
+			eventType = services.DocumentCreated
+			if c.Param("id") != "" {
+				// POST em um recurso existente (ex: rollback) altera o documento
+				eventType = services.DocumentUpdated
+			}
+		case http.MethodDelete:
+			eventType = services.DocumentDeleted
+		}
+
+		bus.Publish(c.Request.Context(), services.DocumentEvent{
+			Type:       eventType,
+			Collection: collection,
+			DocumentID: c.Param("id"),
+		})
+	}
+}
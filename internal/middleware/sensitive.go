@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/pii"
+)
+
+// SensitiveQueryKey marca no contexto Gin que a requisição contém uma busca sensível, para
+// que a URL (com a query) não seja registrada em logs nem traces
+const SensitiveQueryKey = "sensitive_query"
+
+// MarkSensitiveQuery marca a requisição como busca sensível
+func MarkSensitiveQuery(c *gin.Context, topicID string) {
+	c.Set(SensitiveQueryKey, topicID)
+}
+
+// IsSensitiveQuery indica se a requisição foi marcada como busca sensível
+func IsSensitiveQuery(c *gin.Context) bool {
+	return c.GetString(SensitiveQueryKey) != ""
+}
+
+// RequestLogger registra as requisições no formato do logger padrão do Gin, omitindo a query
+// string das buscas sensíveis e mascarando dados pessoais das demais
+func RequestLogger() gin.HandlerFunc {
+	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
+		path := pii.ScrubURL(param.Request.URL)
+		if topicID, _ := param.Keys[SensitiveQueryKey].(string); topicID != "" {
+			path = param.Request.URL.Path + " [query sensível omitida]"
+		}
+
+		if param.Latency > time.Minute {
+			param.Latency = param.Latency.Truncate(time.Second)
+		}
+		return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v\n%s",
+			param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+			param.StatusCode,
+			param.Latency,
+			param.ClientIP,
+			param.Method,
+			path,
+			param.ErrorMessage,
+		)
+	})
+}
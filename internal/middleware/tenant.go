@@ -0,0 +1,46 @@
+package middlewares
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
+)
+
+// TenantKey chave do tenant da requisição no contexto Gin
+const TenantKey = "tenant"
+
+// TenantScope identifica o tenant (município) da requisição pela API key (X-API-Key) ou
+// pelo domínio e o associa ao contexto da requisição, restringindo as consultas aos seus
+// documentos. Requisições sem API key e de domínio desconhecido usam o tenant padrão.
+func TenantScope(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := c.GetHeader("X-API-Key")
+
+		host := c.Request.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		tenantID, ok := cfg.ResolveTenant(apiKey, host)
+		if !ok {
+			if apiKey != "" {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "API key inválida"})
+				c.Abort()
+				return
+			}
+			tenantID = tenant.DefaultID
+		}
+
+		c.Set(TenantKey, tenantID)
+		c.Request = c.Request.WithContext(tenant.WithTenant(c.Request.Context(), tenantID))
+		c.Next()
+	}
+}
+
+// GetTenant obtém o tenant da requisição (vazio quando o particionamento está desabilitado)
+func GetTenant(c *gin.Context) string {
+	return c.GetString(TenantKey)
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prefeitura-rio/app-busca-search/internal/pii"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -21,7 +22,7 @@ func RequestTiming() gin.HandlerFunc {
 		// Set span attributes
 		span.SetAttributes(
 			attribute.String("http.method", c.Request.Method),
-			attribute.String("http.url", c.Request.URL.String()),
+			attribute.String("http.url", pii.ScrubURL(c.Request.URL)),
 			attribute.String("http.route", c.FullPath()),
 			attribute.String("http.user_agent", c.Request.UserAgent()),
 			attribute.String("http.client_ip", c.ClientIP()),
@@ -38,6 +39,11 @@ func RequestTiming() gin.HandlerFunc {
 		duration := time.Since(start)
 		status := c.Writer.Status()
 
+		// Sensitive searches must not leave the query in traces
+		if IsSensitiveQuery(c) {
+			span.SetAttributes(attribute.String("http.url", c.Request.URL.Path))
+		}
+
 		// Add response attributes
 		span.SetAttributes(
 			attribute.Int("http.status_code", status),
@@ -2,6 +2,8 @@ package schemas
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/typesense/typesense-go/v3/typesense/api"
@@ -40,6 +42,21 @@ func (r *Registry) registerBuiltinSchemas() {
 	r.Register(SchemaV1())
 	r.Register(SchemaV2())
 	r.Register(SchemaV3())
+	r.Register(SchemaV4())
+	r.Register(SchemaV5())
+	r.Register(SchemaV6())
+	r.Register(SchemaV7())
+	r.Register(SchemaV8())
+	r.Register(SchemaV9())
+	r.Register(SchemaV10())
+	r.Register(SchemaV11())
+	r.Register(SchemaV12())
+	r.Register(SchemaV13())
+	r.Register(SchemaV14())
+	r.Register(SchemaV15())
+	r.Register(SchemaV16())
+	r.Register(SchemaV17())
+	r.Register(SchemaV18())
 }
 
 // Register registra um novo schema
@@ -49,7 +66,7 @@ func (r *Registry) Register(schema *SchemaDefinition) {
 
 	r.schemas[schema.Version] = schema
 
-	if r.currentVersion == "" || schema.Version > r.currentVersion {
+	if r.currentVersion == "" || compareVersions(schema.Version, r.currentVersion) > 0 {
 		r.currentVersion = schema.Version
 	}
 }
@@ -100,6 +117,25 @@ func (r *Registry) ListVersions() []string {
 	return versions
 }
 
+// compareVersions compara versões no formato "vN" numericamente ("v10" > "v9"), retornando
+// -1, 0 ou 1. Versões fora do formato são comparadas como texto.
+func compareVersions(a, b string) int {
+	na, errA := strconv.Atoi(strings.TrimPrefix(a, "v"))
+	nb, errB := strconv.Atoi(strings.TrimPrefix(b, "v"))
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+
+	switch {
+	case na < nb:
+		return -1
+	case na > nb:
+		return 1
+	default:
+		return 0
+	}
+}
+
 // Helper functions para criação de schemas
 
 // StringPtr retorna um ponteiro para string
@@ -0,0 +1,25 @@
+package schemas
+
+import "testing"
+
+func TestCompareVersionsIsNumeric(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"v10", "v9", 1},
+		{"v2", "v10", -1},
+		{"v9", "v9", 0},
+	}
+	for _, c := range cases {
+		if got := compareVersions(c.a, c.b); got != c.want {
+			t.Errorf("compareVersions(%q, %q) = %d, esperado %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestRegistryCurrentVersionIsLatest(t *testing.T) {
+	if got := NewRegistry().GetCurrentVersion(); got != "v18" {
+		t.Errorf("versão atual = %s, esperado v18", got)
+	}
+}
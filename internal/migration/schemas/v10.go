@@ -0,0 +1,24 @@
+package schemas
+
+import "github.com/typesense/typesense-go/v3/typesense/api"
+
+// SchemaV10 adiciona os deep links por canal (deep_links.<canal>), gerados a partir do slug com
+// os parâmetros UTM gerenciados. Apenas armazenados, não indexados.
+func SchemaV10() *SchemaDefinition {
+	v9 := SchemaV9()
+
+	fields := make([]api.Field, 0, len(v9.Fields)+1)
+	fields = append(fields, v9.Fields...)
+	fields = append(fields,
+		api.Field{Name: "deep_links", Type: "object", Optional: BoolPtr(true), Index: BoolPtr(false)},
+	)
+
+	return &SchemaDefinition{
+		Version:      "v10",
+		Name:         "prefrio_services_base",
+		SortingField: "last_update",
+		NestedFields: true,
+		Fields:       fields,
+		Transform:    transformV6, // deep links são gerados no backfill
+	}
+}
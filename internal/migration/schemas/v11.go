@@ -0,0 +1,46 @@
+package schemas
+
+import "github.com/typesense/typesense-go/v3/typesense/api"
+
+// SchemaV11 adiciona a janela de disponibilidade dos serviços sazonais (available_from e
+// available_until, 0 = sem limite) e a marcação dos despublicados pelo fim da janela
+func SchemaV11() *SchemaDefinition {
+	v10 := SchemaV10()
+
+	fields := make([]api.Field, 0, len(v10.Fields)+3)
+	fields = append(fields, v10.Fields...)
+	fields = append(fields,
+		api.Field{Name: "available_from", Type: "int64", Facet: BoolPtr(false), Optional: BoolPtr(true)},
+		api.Field{Name: "available_until", Type: "int64", Facet: BoolPtr(false), Optional: BoolPtr(true)},
+		api.Field{Name: "seasonal_paused", Type: "bool", Facet: BoolPtr(true), Optional: BoolPtr(true)},
+	)
+
+	return &SchemaDefinition{
+		Version:      "v11",
+		Name:         "prefrio_services_base",
+		SortingField: "last_update",
+		NestedFields: true,
+		Fields:       fields,
+		Transform:    transformV11,
+	}
+}
+
+// transformV11 preenche a janela sem limites nos documentos existentes, já que a busca pública
+// filtra por available_from/available_until e documentos sem os campos não atenderiam o filtro
+func transformV11(doc map[string]interface{}) (map[string]interface{}, error) {
+	doc, err := transformV6(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range []string{"available_from", "available_until"} {
+		if _, ok := doc[field].(float64); !ok {
+			doc[field] = 0
+		}
+	}
+	if _, ok := doc["seasonal_paused"].(bool); !ok {
+		doc["seasonal_paused"] = false
+	}
+
+	return doc, nil
+}
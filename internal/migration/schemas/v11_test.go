@@ -0,0 +1,18 @@
+package schemas
+
+import "testing"
+
+func TestTransformV11FillsOpenWindow(t *testing.T) {
+	doc, err := transformV11(map[string]interface{}{"id": "x", "nome_servico": "Matrícula"})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if doc["available_from"] != 0 || doc["available_until"] != 0 || doc["seasonal_paused"] != false {
+		t.Errorf("janela inesperada: %v %v %v", doc["available_from"], doc["available_until"], doc["seasonal_paused"])
+	}
+
+	doc, _ = transformV11(map[string]interface{}{"id": "y", "available_until": float64(1700000000)})
+	if doc["available_until"] != float64(1700000000) {
+		t.Errorf("janela existente não deveria ser sobrescrita: %v", doc["available_until"])
+	}
+}
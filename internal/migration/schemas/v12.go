@@ -0,0 +1,65 @@
+package schemas
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+// SchemaV12 tipa os botões dos serviços (action_type, phone e message). Os campos são
+// subcampos de buttons, indexado como object[], e não mudam a lista de campos do v11.
+func SchemaV12() *SchemaDefinition {
+	v11 := SchemaV11()
+
+	return &SchemaDefinition{
+		Version:      "v12",
+		Name:         "prefrio_services_base",
+		SortingField: "last_update",
+		NestedFields: true,
+		Fields:       v11.Fields,
+		Transform:    transformV12,
+	}
+}
+
+// transformV12 infere o action_type dos botões existentes pela URL e normaliza URLs e
+// telefones. Botões que não passam na validação do tipo são desabilitados, mantendo os dados
+// para que os editores os corrijam.
+func transformV12(doc map[string]interface{}) (map[string]interface{}, error) {
+	doc, err := transformV11(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, ok := doc["buttons"].([]interface{})
+	if !ok || len(raw) == 0 {
+		return doc, nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler buttons: %w", err)
+	}
+	var buttons []models.Button
+	if err := json.Unmarshal(data, &buttons); err != nil {
+		return nil, fmt.Errorf("buttons inválidos: %w", err)
+	}
+
+	for i := range buttons {
+		if err := buttons[i].Normalize(); err != nil {
+			buttons[i].IsEnabled = false
+		}
+	}
+
+	data, err = json.Marshal(buttons)
+	if err != nil {
+		return nil, err
+	}
+	var normalized []interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	doc["buttons"] = normalized
+
+	return doc, nil
+}
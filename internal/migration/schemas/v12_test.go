@@ -0,0 +1,36 @@
+package schemas
+
+import "testing"
+
+func TestTransformV12TypesButtons(t *testing.T) {
+	doc, err := transformV12(map[string]interface{}{
+		"id": "x",
+		"buttons": []interface{}{
+			map[string]interface{}{"titulo": "Portal", "url_service": "http://carioca.rio/iptu", "is_enabled": true},
+			map[string]interface{}{"titulo": "Central", "url_service": "tel:1746", "is_enabled": true},
+			map[string]interface{}{"titulo": "Chat", "url_service": "https://wa.me/5521988887777?text=Oi", "is_enabled": true},
+			map[string]interface{}{"titulo": "E-mail", "url_service": "mailto:a@rio.rj.gov.br", "is_enabled": true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	buttons := doc["buttons"].([]interface{})
+	expected := []struct {
+		actionType string
+		url        string
+		enabled    bool
+	}{
+		{"external_link", "https://carioca.rio/iptu", true},
+		{"phone", "tel:1746", true},
+		{"whatsapp", "https://wa.me/5521988887777?text=Oi", true},
+		{"external_link", "mailto:a@rio.rj.gov.br", false},
+	}
+	for i, want := range expected {
+		button := buttons[i].(map[string]interface{})
+		if button["action_type"] != want.actionType || button["url_service"] != want.url || button["is_enabled"] != want.enabled {
+			t.Errorf("buttons[%d] = %v, esperado %+v", i, button, want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package schemas
+
+import (
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+// SchemaV13 adiciona a chave fonética do nome do serviço (nome_servico_fonetico), consultada
+// quando a busca textual não encontra resultados
+func SchemaV13() *SchemaDefinition {
+	v12 := SchemaV12()
+
+	fields := make([]api.Field, 0, len(v12.Fields)+1)
+	fields = append(fields, v12.Fields...)
+	fields = append(fields,
+		api.Field{Name: "nome_servico_fonetico", Type: "string", Facet: BoolPtr(false), Optional: BoolPtr(true)},
+	)
+
+	return &SchemaDefinition{
+		Version:      "v13",
+		Name:         "prefrio_services_base",
+		SortingField: "last_update",
+		NestedFields: true,
+		Fields:       fields,
+		Transform:    transformV13,
+	}
+}
+
+// transformV13 gera a chave fonética a partir do nome_servico
+func transformV13(doc map[string]interface{}) (map[string]interface{}, error) {
+	doc, err := transformV12(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if nome, ok := doc["nome_servico"].(string); ok {
+		doc["nome_servico_fonetico"] = utils.PhoneticKey(nome)
+	}
+
+	return doc, nil
+}
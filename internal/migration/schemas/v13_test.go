@@ -0,0 +1,13 @@
+package schemas
+
+import "testing"
+
+func TestTransformV13GeneratesPhoneticKey(t *testing.T) {
+	doc, err := transformV13(map[string]interface{}{"id": "x", "nome_servico": "Alvará de Funcionamento"})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if got := doc["nome_servico_fonetico"]; got != "AVR D FNSNMNT" {
+		t.Errorf("nome_servico_fonetico = %v", got)
+	}
+}
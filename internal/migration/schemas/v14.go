@@ -0,0 +1,35 @@
+package schemas
+
+import "github.com/prefeitura-rio/app-busca-search/internal/utils"
+
+// SchemaV14 reindexa o search_content com as formas canônicas de referências legais
+// ("Lei 1234/2020" → lei1234 lei12342020), protocolos e termos com hífen. Os campos são os
+// mesmos da v13.
+func SchemaV14() *SchemaDefinition {
+	v13 := SchemaV13()
+
+	return &SchemaDefinition{
+		Version:      "v14",
+		Name:         "prefrio_services_base",
+		SortingField: "last_update",
+		NestedFields: true,
+		Fields:       v13.Fields,
+		Transform:    transformV14,
+	}
+}
+
+// transformV14 acrescenta ao search_content os tokens normalizados que ainda não estão nele
+func transformV14(doc map[string]interface{}) (map[string]interface{}, error) {
+	doc, err := transformV13(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if content, ok := doc["search_content"].(string); ok {
+		if tokens := utils.IndexTokens(content); tokens != "" {
+			doc["search_content"] = content + " " + tokens
+		}
+	}
+
+	return doc, nil
+}
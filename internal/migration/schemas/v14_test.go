@@ -0,0 +1,20 @@
+package schemas
+
+import "testing"
+
+func TestTransformV14AppendsNormalizedTokens(t *testing.T) {
+	doc, err := transformV14(map[string]interface{}{"id": "x", "search_content": "Isenção prevista na Lei nº 1.234/2020"})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	want := "Isenção prevista na Lei nº 1.234/2020 lei1234 lei12342020 12342020"
+	if got := doc["search_content"]; got != want {
+		t.Errorf("search_content = %v", got)
+	}
+
+	// Reaplicar a transformação não duplica os tokens
+	again, _ := transformV14(doc)
+	if got := again["search_content"]; got != want {
+		t.Errorf("search_content após reaplicar = %v", got)
+	}
+}
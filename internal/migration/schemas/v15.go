@@ -0,0 +1,45 @@
+package schemas
+
+import "github.com/typesense/typesense-go/v3/typesense/api"
+
+// SchemaV15 adiciona os bairros e regiões administrativas atendidos pelos serviços com
+// atendimento localizado, filtráveis pelo parâmetro bairro da busca
+func SchemaV15() *SchemaDefinition {
+	v14 := SchemaV14()
+
+	fields := make([]api.Field, 0, len(v14.Fields)+3)
+	fields = append(fields, v14.Fields...)
+	fields = append(fields,
+		api.Field{Name: "bairros", Type: "string[]", Facet: BoolPtr(true), Optional: BoolPtr(true)},
+		api.Field{Name: "regioes_administrativas", Type: "string[]", Facet: BoolPtr(true), Optional: BoolPtr(true)},
+		api.Field{Name: "restrito_bairros", Type: "bool", Facet: BoolPtr(true), Optional: BoolPtr(true)},
+	)
+
+	return &SchemaDefinition{
+		Version:      "v15",
+		Name:         "prefrio_services_base",
+		SortingField: "last_update",
+		NestedFields: true,
+		Fields:       fields,
+		Transform:    transformV15,
+	}
+}
+
+// transformV15 marca como restritos os serviços com bairros; os demais atendem toda a cidade
+func transformV15(doc map[string]interface{}) (map[string]interface{}, error) {
+	doc, err := transformV14(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	restrito := false
+	switch bairros := doc["bairros"].(type) {
+	case []interface{}:
+		restrito = len(bairros) > 0
+	case []string:
+		restrito = len(bairros) > 0
+	}
+	doc["restrito_bairros"] = restrito
+
+	return doc, nil
+}
@@ -0,0 +1,21 @@
+package schemas
+
+import "testing"
+
+func TestTransformV15MarksRestrictedServices(t *testing.T) {
+	doc, err := transformV15(map[string]interface{}{"id": "x", "bairros": []interface{}{"Tijuca"}})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if doc["restrito_bairros"] != true {
+		t.Errorf("restrito_bairros = %v, esperado true", doc["restrito_bairros"])
+	}
+
+	doc, err = transformV15(map[string]interface{}{"id": "y"})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if doc["restrito_bairros"] != false {
+		t.Errorf("serviço sem bairros deveria atender toda a cidade: %v", doc["restrito_bairros"])
+	}
+}
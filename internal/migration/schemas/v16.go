@@ -0,0 +1,24 @@
+package schemas
+
+import "github.com/typesense/typesense-go/v3/typesense/api"
+
+// SchemaV16 adiciona o transporte público próximo dos canais presenciais (transporte), gerado
+// pelo enriquecimento com geocodificação. O campo é apenas armazenado, sem índice.
+func SchemaV16() *SchemaDefinition {
+	v15 := SchemaV15()
+
+	fields := make([]api.Field, 0, len(v15.Fields)+1)
+	fields = append(fields, v15.Fields...)
+	fields = append(fields,
+		api.Field{Name: "transporte", Type: "object", Facet: BoolPtr(false), Optional: BoolPtr(true), Index: BoolPtr(false)},
+	)
+
+	return &SchemaDefinition{
+		Version:      "v16",
+		Name:         "prefrio_services_base",
+		SortingField: "last_update",
+		NestedFields: true,
+		Fields:       fields,
+		Transform:    transformV15,
+	}
+}
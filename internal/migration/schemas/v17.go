@@ -0,0 +1,56 @@
+package schemas
+
+import (
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+// SchemaV17 adiciona o hash do embedding (embedding_hash, SHA-256 do modelo e do vetor), gravado
+// também nas versões, que permite reaproveitar o vetor quando o conteúdo não muda. O campo é
+// apenas armazenado, sem índice.
+func SchemaV17() *SchemaDefinition {
+	v16 := SchemaV16()
+
+	fields := make([]api.Field, 0, len(v16.Fields)+1)
+	fields = append(fields, v16.Fields...)
+	fields = append(fields,
+		api.Field{Name: "embedding_hash", Type: "string", Facet: BoolPtr(false), Optional: BoolPtr(true), Index: BoolPtr(false)},
+	)
+
+	return &SchemaDefinition{
+		Version:      "v17",
+		Name:         "prefrio_services_base",
+		SortingField: "last_update",
+		NestedFields: true,
+		Fields:       fields,
+		Transform:    transformV17,
+	}
+}
+
+// transformV17 calcula o hash do embedding dos documentos existentes
+func transformV17(doc map[string]interface{}) (map[string]interface{}, error) {
+	doc, err := transformV15(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	values, _ := doc["embedding"].([]interface{})
+	embedding := make([]float64, 0, len(values))
+	for _, value := range values {
+		f, ok := value.(float64)
+		if !ok {
+			embedding = nil
+			break
+		}
+		embedding = append(embedding, f)
+	}
+
+	model, _ := doc["embedding_model"].(string)
+	if hash := utils.EmbeddingHash(model, embedding); hash != "" {
+		doc["embedding_hash"] = hash
+	} else {
+		delete(doc, "embedding_hash")
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,27 @@
+package schemas
+
+import (
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+)
+
+func TestTransformV17EmbeddingHash(t *testing.T) {
+	doc, err := transformV17(map[string]interface{}{
+		"id":              "x",
+		"nome_servico":    "IPTU",
+		"embedding":       []interface{}{0.1, 0.2, 0.3},
+		"embedding_model": "gemini-embedding-001",
+	})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if expected := utils.EmbeddingHash("gemini-embedding-001", []float64{0.1, 0.2, 0.3}); doc["embedding_hash"] != expected {
+		t.Errorf("embedding_hash = %v, esperado %s", doc["embedding_hash"], expected)
+	}
+
+	doc, _ = transformV17(map[string]interface{}{"id": "y", "nome_servico": "Sem vetor", "embedding_hash": "antigo"})
+	if _, ok := doc["embedding_hash"]; ok {
+		t.Error("documento sem embedding não deveria ter embedding_hash")
+	}
+}
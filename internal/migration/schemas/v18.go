@@ -0,0 +1,24 @@
+package schemas
+
+import "github.com/typesense/typesense-go/v3/typesense/api"
+
+// SchemaV18 adiciona o fim do boost de destaque na busca (destaque_until, 0 = sem limite).
+// Documentos sem o campo mantêm o boost enquanto fixar_destaque estiver marcado.
+func SchemaV18() *SchemaDefinition {
+	v17 := SchemaV17()
+
+	fields := make([]api.Field, 0, len(v17.Fields)+1)
+	fields = append(fields, v17.Fields...)
+	fields = append(fields,
+		api.Field{Name: "destaque_until", Type: "int64", Facet: BoolPtr(false), Optional: BoolPtr(true)},
+	)
+
+	return &SchemaDefinition{
+		Version:      "v18",
+		Name:         "prefrio_services_base",
+		SortingField: "last_update",
+		NestedFields: true,
+		Fields:       fields,
+		Transform:    transformV17,
+	}
+}
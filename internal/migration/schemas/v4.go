@@ -0,0 +1,50 @@
+package schemas
+
+import "github.com/typesense/typesense-go/v3/typesense/api"
+
+// legacyEmbeddingModel modelo usado para gerar todos os embeddings anteriores ao v4
+const legacyEmbeddingModel = "gemini-embedding-001"
+
+// SchemaV4 adiciona o modelo e a dimensão do embedding de cada documento, permitindo que a
+// busca detecte vetores incompatíveis com o modelo configurado para as queries
+func SchemaV4() *SchemaDefinition {
+	v3 := SchemaV3()
+
+	fields := make([]api.Field, 0, len(v3.Fields)+2)
+	fields = append(fields, v3.Fields...)
+	fields = append(fields,
+		api.Field{Name: "embedding_model", Type: "string", Facet: BoolPtr(true), Optional: BoolPtr(true)},
+		api.Field{Name: "embedding_dim", Type: "int32", Facet: BoolPtr(true), Optional: BoolPtr(true)},
+	)
+
+	return &SchemaDefinition{
+		Version:      "v4",
+		Name:         "prefrio_services_base",
+		SortingField: "last_update",
+		NestedFields: true,
+		Fields:       fields,
+		Transform:    transformV4,
+	}
+}
+
+// transformV4 preenche os metadados de embedding dos documentos existentes
+func transformV4(doc map[string]interface{}) (map[string]interface{}, error) {
+	doc, err := transformV3(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	embedding, _ := doc["embedding"].([]interface{})
+	if len(embedding) == 0 {
+		delete(doc, "embedding_model")
+		delete(doc, "embedding_dim")
+		return doc, nil
+	}
+
+	if model, _ := doc["embedding_model"].(string); model == "" {
+		doc["embedding_model"] = legacyEmbeddingModel
+	}
+	doc["embedding_dim"] = len(embedding)
+
+	return doc, nil
+}
@@ -0,0 +1,22 @@
+package schemas
+
+import "testing"
+
+func TestTransformV4(t *testing.T) {
+	doc, err := transformV4(map[string]interface{}{
+		"id":           "cffe0736-80a6",
+		"nome_servico": "IPTU",
+		"embedding":    []interface{}{0.1, 0.2, 0.3},
+	})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if doc["embedding_model"] != legacyEmbeddingModel || doc["embedding_dim"] != 3 {
+		t.Errorf("metadados inesperados: model=%v dim=%v", doc["embedding_model"], doc["embedding_dim"])
+	}
+
+	doc, _ = transformV4(map[string]interface{}{"id": "x", "nome_servico": "Sem vetor"})
+	if _, ok := doc["embedding_model"]; ok {
+		t.Error("documento sem embedding não deveria ter embedding_model")
+	}
+}
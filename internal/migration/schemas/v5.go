@@ -0,0 +1,40 @@
+package schemas
+
+import "github.com/typesense/typesense-go/v3/typesense/api"
+
+// SchemaV5 adiciona os metadados de descontinuação dos serviços (deprecated, replaced_by e
+// sunset_at), usados para rebaixar serviços descontinuados na busca e despublicá-los no sunset
+func SchemaV5() *SchemaDefinition {
+	v4 := SchemaV4()
+
+	fields := make([]api.Field, 0, len(v4.Fields)+3)
+	fields = append(fields, v4.Fields...)
+	fields = append(fields,
+		api.Field{Name: "deprecated", Type: "bool", Facet: BoolPtr(true), Optional: BoolPtr(true)},
+		api.Field{Name: "replaced_by", Type: "string", Optional: BoolPtr(true)},
+		api.Field{Name: "sunset_at", Type: "int64", Optional: BoolPtr(true)},
+	)
+
+	return &SchemaDefinition{
+		Version:      "v5",
+		Name:         "prefrio_services_base",
+		SortingField: "last_update",
+		NestedFields: true,
+		Fields:       fields,
+		Transform:    transformV5,
+	}
+}
+
+// transformV5 marca os documentos existentes como não descontinuados
+func transformV5(doc map[string]interface{}) (map[string]interface{}, error) {
+	doc, err := transformV4(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := doc["deprecated"].(bool); !ok {
+		doc["deprecated"] = false
+	}
+
+	return doc, nil
+}
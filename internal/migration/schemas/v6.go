@@ -0,0 +1,41 @@
+package schemas
+
+import (
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+// SchemaV6 adiciona o tenant (município) de cada serviço, permitindo que a mesma base
+// atenda portais de outras cidades
+func SchemaV6() *SchemaDefinition {
+	v5 := SchemaV5()
+
+	fields := make([]api.Field, 0, len(v5.Fields)+1)
+	fields = append(fields, v5.Fields...)
+	fields = append(fields,
+		api.Field{Name: "tenant", Type: "string", Facet: BoolPtr(true), Optional: BoolPtr(true)},
+	)
+
+	return &SchemaDefinition{
+		Version:      "v6",
+		Name:         "prefrio_services_base",
+		SortingField: "last_update",
+		NestedFields: true,
+		Fields:       fields,
+		Transform:    transformV6,
+	}
+}
+
+// transformV6 atribui os documentos existentes ao tenant padrão
+func transformV6(doc map[string]interface{}) (map[string]interface{}, error) {
+	doc, err := transformV5(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if t, _ := doc["tenant"].(string); t == "" {
+		doc["tenant"] = tenant.DefaultID
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,18 @@
+package schemas
+
+import "testing"
+
+func TestTransformV6AssignsDefaultTenant(t *testing.T) {
+	doc, err := transformV6(map[string]interface{}{"id": "x", "nome_servico": "IPTU"})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if doc["tenant"] != "rio" || doc["deprecated"] != false {
+		t.Errorf("campos inesperados: tenant=%v deprecated=%v", doc["tenant"], doc["deprecated"])
+	}
+
+	doc, _ = transformV6(map[string]interface{}{"id": "y", "nome_servico": "ISS", "tenant": "niteroi"})
+	if doc["tenant"] != "niteroi" {
+		t.Errorf("tenant existente não deveria ser sobrescrito: %v", doc["tenant"])
+	}
+}
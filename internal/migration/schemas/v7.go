@@ -0,0 +1,25 @@
+package schemas
+
+import "github.com/typesense/typesense-go/v3/typesense/api"
+
+// SchemaV7 adiciona os anexos dos serviços (arquivos no GCS), com nome e tipo indexados
+func SchemaV7() *SchemaDefinition {
+	v6 := SchemaV6()
+
+	fields := make([]api.Field, 0, len(v6.Fields)+3)
+	fields = append(fields, v6.Fields...)
+	fields = append(fields,
+		api.Field{Name: "attachments", Type: "object[]", Optional: BoolPtr(true)},
+		api.Field{Name: "attachments.filename", Type: "string[]", Optional: BoolPtr(true)},
+		api.Field{Name: "attachments.mime_type", Type: "string[]", Facet: BoolPtr(true), Optional: BoolPtr(true)},
+	)
+
+	return &SchemaDefinition{
+		Version:      "v7",
+		Name:         "prefrio_services_base",
+		SortingField: "last_update",
+		NestedFields: true,
+		Fields:       fields,
+		Transform:    transformV6, // documentos existentes não têm anexos
+	}
+}
@@ -0,0 +1,28 @@
+package schemas
+
+import "github.com/typesense/typesense-go/v3/typesense/api"
+
+// SchemaV8 adiciona as entidades extraídas da descrição (órgãos, documentos, prazos e valores)
+// como campos estruturados, permitindo filtros como "prazo de até 5 dias"
+func SchemaV8() *SchemaDefinition {
+	v7 := SchemaV7()
+
+	fields := make([]api.Field, 0, len(v7.Fields)+5)
+	fields = append(fields, v7.Fields...)
+	fields = append(fields,
+		api.Field{Name: "entities", Type: "object", Optional: BoolPtr(true)},
+		api.Field{Name: "entities.orgaos", Type: "string[]", Facet: BoolPtr(true), Optional: BoolPtr(true)},
+		api.Field{Name: "entities.documentos", Type: "string[]", Facet: BoolPtr(true), Optional: BoolPtr(true)},
+		api.Field{Name: "entities.prazo_dias_min", Type: "int32", Optional: BoolPtr(true)},
+		api.Field{Name: "entities.valor_min", Type: "float", Optional: BoolPtr(true)},
+	)
+
+	return &SchemaDefinition{
+		Version:      "v8",
+		Name:         "prefrio_services_base",
+		SortingField: "last_update",
+		NestedFields: true,
+		Fields:       fields,
+		Transform:    transformV6, // entidades são preenchidas pelo backfill de enriquecimento
+	}
+}
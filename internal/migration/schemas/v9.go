@@ -0,0 +1,24 @@
+package schemas
+
+import "github.com/typesense/typesense-go/v3/typesense/api"
+
+// SchemaV9 adiciona as traduções revisadas por idioma (translations.<idioma>.<campo>), indexadas
+// como campos aninhados para permitir a busca no idioma
+func SchemaV9() *SchemaDefinition {
+	v8 := SchemaV8()
+
+	fields := make([]api.Field, 0, len(v8.Fields)+1)
+	fields = append(fields, v8.Fields...)
+	fields = append(fields,
+		api.Field{Name: "translations", Type: "object", Optional: BoolPtr(true)},
+	)
+
+	return &SchemaDefinition{
+		Version:      "v9",
+		Name:         "prefrio_services_base",
+		SortingField: "last_update",
+		NestedFields: true,
+		Fields:       fields,
+		Transform:    transformV6, // traduções são indexadas na revisão
+	}
+}
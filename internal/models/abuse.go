@@ -0,0 +1,30 @@
+package models
+
+// AbuseWordList lista de termos ofensivos gerida pelos administradores
+type AbuseWordList struct {
+	Words     []string `json:"words"`
+	UpdatedBy string   `json:"updated_by,omitempty"`
+	UpdatedAt int64    `json:"updated_at,omitempty"`
+	Default   bool     `json:"default"` // true enquanto a lista padrão não foi substituída
+}
+
+// AbuseWordListRequest substitui a lista de termos ofensivos
+type AbuseWordListRequest struct {
+	Words []string `json:"words" binding:"required,min=1,max=2000"`
+}
+
+// AbuseClientIncidents incidentes de um cliente na janela de contagem
+type AbuseClientIncidents struct {
+	Client       string `json:"client"` // "ip:<endereço>" ou "key:<hash da API key>"
+	Incidents    int    `json:"incidents"`
+	BlockedUntil int64  `json:"blocked_until,omitempty"` // unix; presente enquanto o cliente está bloqueado
+}
+
+// AbuseReport situação do filtro de abuso desde o início do processo
+type AbuseReport struct {
+	TotalIncidents int64                  `json:"total_incidents"`
+	Threshold      int                    `json:"threshold"`      // incidentes na janela que bloqueiam o cliente (0 = sem bloqueio)
+	WindowMinutes  int                    `json:"window_minutes"` // janela de contagem
+	BlockMinutes   int                    `json:"block_minutes"`  // duração do bloqueio
+	Clients        []AbuseClientIncidents `json:"clients"`
+}
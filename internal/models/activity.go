@@ -0,0 +1,30 @@
+package models
+
+// ActivityCount edições de um usuário ou órgão gestor por semana
+type ActivityCount struct {
+	Key   string         `json:"key"`
+	Weeks map[string]int `json:"weeks"` // Início da semana (segunda-feira, AAAA-MM-DD) -> edições
+	Total int            `json:"total"`
+}
+
+// StaleDraft serviço em rascunho sem edições há mais de N dias
+type StaleDraft struct {
+	ServiceID     string   `json:"service_id"`
+	NomeServico   string   `json:"nome_servico"`
+	OrgaoGestor   []string `json:"orgao_gestor,omitempty"`
+	LastEditedBy  string   `json:"last_edited_by"`
+	LastEditedAt  int64    `json:"last_edited_at"`
+	DaysIdle      int      `json:"days_idle"`
+	VersionNumber int64    `json:"version_number"`
+}
+
+// ActivityReport atividade editorial calculada a partir do histórico de versões
+type ActivityReport struct {
+	GeneratedAt    int64           `json:"generated_at"`
+	Weeks          []string        `json:"weeks"`
+	StaleAfterDays int             `json:"stale_after_days"`
+	TotalEdits     int             `json:"total_edits"`
+	ByUser         []ActivityCount `json:"by_user"`
+	ByOrgao        []ActivityCount `json:"by_orgao"`
+	StaleDrafts    []StaleDraft    `json:"stale_drafts"`
+}
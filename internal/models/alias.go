@@ -0,0 +1,24 @@
+package models
+
+// CollectionAlias alias do Typesense e a collection para a qual aponta. InUseBy lista as
+// referências da configuração ao alias; aliases em uso não podem ser removidos e só são
+// reapontados com token de confirmação.
+type CollectionAlias struct {
+	Name           string   `json:"name"`
+	CollectionName string   `json:"collection_name"`
+	InUseBy        []string `json:"in_use_by,omitempty"`
+}
+
+// AliasList aliases do Typesense, ordenados por nome
+type AliasList struct {
+	Total   int               `json:"total"`
+	Aliases []CollectionAlias `json:"aliases"`
+}
+
+// AliasRequest cria ou reaponta um alias. ConfirmationToken é exigido ao reapontar um alias
+// em uso e é retornado pela primeira tentativa sem ele.
+type AliasRequest struct {
+	Name              string `json:"name" binding:"required,max=200"`
+	CollectionName    string `json:"collection_name" binding:"required,max=200"`
+	ConfirmationToken string `json:"confirmation_token,omitempty"`
+}
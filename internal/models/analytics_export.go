@@ -0,0 +1,67 @@
+package models
+
+// Formatos da exportação de analytics
+const (
+	AnalyticsExportJSON = "json" // JSON por linha (NDJSON)
+	AnalyticsExportCSV  = "csv"
+)
+
+// Representação da query na exportação de analytics
+const (
+	AnalyticsQueryHash = "hash" // Apenas o hash da query
+	AnalyticsQueryText = "text" // Hash e texto mascarado
+)
+
+// AnalyticsExportStatus estado de uma exportação assíncrona
+type AnalyticsExportStatus string
+
+const (
+	AnalyticsExportRunning   AnalyticsExportStatus = "running"
+	AnalyticsExportCompleted AnalyticsExportStatus = "completed"
+	AnalyticsExportFailed    AnalyticsExportStatus = "failed"
+)
+
+// AnalyticsExportRequest período e formato da exportação (timestamps unix, to exclusivo)
+type AnalyticsExportRequest struct {
+	From      int64  `json:"from"`
+	To        int64  `json:"to"`
+	Format    string `json:"format"`
+	QueryMode string `json:"query_mode"`
+}
+
+// AnalyticsExportRow evento de busca ou clique anonimizado. A sessão e a query são
+// identificadas por hashes, que permitem agrupar eventos sem revelar o identificador; o texto
+// da query (já mascarado na gravação) só é incluído no modo text.
+type AnalyticsExportRow struct {
+	EventType   string `json:"event_type"` // search ou click
+	Timestamp   int64  `json:"timestamp"`
+	SessionHash string `json:"session_hash"`
+	QueryHash   string `json:"query_hash,omitempty"`
+	Query       string `json:"query,omitempty"`
+	SearchType  string `json:"search_type,omitempty"`
+	LatencyMs   int64  `json:"latency_ms,omitempty"`
+	ResultCount int    `json:"result_count,omitempty"`
+	ClickedRank int    `json:"clicked_rank,omitempty"` // posição do resultado clicado (1 = primeiro)
+	ServiceID   string `json:"service_id,omitempty"`
+
+	// Intervenções que afetaram os resultados da busca (ResultAnnotation.Key)
+	Interventions []string `json:"interventions,omitempty"`
+}
+
+// AnalyticsExportJob exportação assíncrona gravada no bucket; DownloadURL é gerada ao
+// consultar uma exportação concluída
+type AnalyticsExportJob struct {
+	ID          string                `json:"id"`
+	Status      AnalyticsExportStatus `json:"status"`
+	From        int64                 `json:"from"`
+	To          int64                 `json:"to"`
+	Format      string                `json:"format"`
+	QueryMode   string                `json:"query_mode"`
+	Events      int                   `json:"events"`
+	ObjectName  string                `json:"object_name,omitempty"`
+	DownloadURL string                `json:"download_url,omitempty"`
+	Error       string                `json:"error,omitempty"`
+	RequestedBy string                `json:"requested_by,omitempty"`
+	CreatedAt   int64                 `json:"created_at"`
+	FinishedAt  int64                 `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// Tipos de intervenção anotados nos resultados (debug_annotations)
+const (
+	AnnotationDemotion = "demotion" // resultado rebaixado após a ordenação por relevância
+	AnnotationRewrite  = "rewrite"  // query reescrita antes da busca
+)
+
+// Regras de intervenção
+const (
+	AnnotationRuleDiversity    = "diversity"    // limite por órgão gestor ou tema no topo da primeira página
+	AnnotationRuleAvailability = "availability" // serviço fora da janela de disponibilidade
+	AnnotationRuleDeprecated   = "deprecated"   // serviço descontinuado
+	AnnotationRuleTranslation  = "translation"  // query traduzida para o português
+)
+
+// ResultAnnotation intervenção que afetou um resultado da busca
+type ResultAnnotation struct {
+	Type   string `json:"type"`
+	Rule   string `json:"rule"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Key identifica a intervenção nos eventos de busca: tipo:regra, prefixado pelo ID do
+// resultado quando a intervenção afetou apenas ele
+func (a ResultAnnotation) Key(resultID string) string {
+	if resultID == "" {
+		return a.Type + ":" + a.Rule
+	}
+	return resultID + ":" + a.Type + ":" + a.Rule
+}
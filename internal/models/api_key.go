@@ -0,0 +1,106 @@
+package models
+
+// APIKeyUsageEvent requisição feita com uma API key de tenant, registrada pelo middleware de uso.
+// A key é identificada pelo ID público (prefixo do hash), nunca pelo valor.
+type APIKeyUsageEvent struct {
+	KeyID     string `json:"key_id"`
+	Tenant    string `json:"tenant"`
+	Route     string `json:"route"` // método e rota (ex.: GET /api/v1/search)
+	Status    int    `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// APIKeyInfo API key configurada, sem o valor
+type APIKeyInfo struct {
+	ID     string `json:"id"`
+	Tenant string `json:"tenant"`
+	Suffix string `json:"suffix"` // últimos caracteres da key, para conferência
+}
+
+// APIKeyList API keys configuradas nos tenants
+type APIKeyList struct {
+	Total int          `json:"total"`
+	Keys  []APIKeyInfo `json:"keys"`
+}
+
+// APIKeyRouteUsage chamadas, erros e latência de uma rota
+type APIKeyRouteUsage struct {
+	Route        string  `json:"route"`
+	Calls        int     `json:"calls"`
+	ClientErrors int     `json:"client_errors"` // respostas 4xx
+	ServerErrors int     `json:"server_errors"` // respostas 5xx
+	ErrorRate    float64 `json:"error_rate"`    // (4xx + 5xx) / chamadas
+	LatencyP50Ms int64   `json:"latency_p50_ms"`
+	LatencyP95Ms int64   `json:"latency_p95_ms"`
+	LatencyP99Ms int64   `json:"latency_p99_ms"`
+}
+
+// APIKeyDailyUsage uso da key em um dia (horário de Brasília), por rota
+type APIKeyDailyUsage struct {
+	Date      string             `json:"date"` // AAAA-MM-DD
+	Calls     int                `json:"calls"`
+	ErrorRate float64            `json:"error_rate"`
+	Routes    []APIKeyRouteUsage `json:"routes"`
+}
+
+// APIKeyUsageReport uso da API key no período: totais por rota e série diária
+type APIKeyUsageReport struct {
+	KeyID       string             `json:"key_id"`
+	Tenant      string             `json:"tenant"`
+	From        string             `json:"from"` // AAAA-MM-DD
+	To          string             `json:"to"`   // AAAA-MM-DD, inclusivo
+	Calls       int                `json:"calls"`
+	ErrorRate   float64            `json:"error_rate"`
+	Routes      []APIKeyRouteUsage `json:"routes"` // período inteiro, por volume
+	Days        []APIKeyDailyUsage `json:"days"`   // todos os dias do período, inclusive sem chamadas
+	Truncated   bool               `json:"truncated,omitempty"`
+	GeneratedAt int64              `json:"generated_at"`
+}
+
+// APIKeyQuotaBoost aumento temporário da quota diária da key (ex.: durante campanhas)
+type APIKeyQuotaBoost struct {
+	ID        string `json:"id"`
+	Extra     int    `json:"extra"` // chamadas diárias adicionais
+	Reason    string `json:"reason,omitempty"`
+	GrantedBy string `json:"granted_by,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// APIKeyQuota quota diária personalizada de uma key, com os aumentos temporários concedidos.
+// Keys sem quota personalizada usam a quota padrão (API_KEY_DAILY_QUOTA).
+type APIKeyQuota struct {
+	KeyID        string             `json:"key_id"`
+	DailyQuota   int                `json:"daily_quota"`   // 0 = sem quota
+	BurstPercent int                `json:"burst_percent"` // excedente tolerado acima da quota, em %
+	Custom       bool               `json:"custom"`        // quota definida pelo admin
+	Boosts       []APIKeyQuotaBoost `json:"boosts"`
+	UpdatedAt    int64              `json:"updated_at,omitempty"`
+	UpdatedBy    string             `json:"updated_by,omitempty"`
+}
+
+// APIKeyQuotaRequest define a quota diária personalizada da key
+type APIKeyQuotaRequest struct {
+	DailyQuota   int `json:"daily_quota" binding:"min=0"`
+	BurstPercent int `json:"burst_percent" binding:"min=0,max=1000"`
+}
+
+// APIKeyQuotaBoostRequest concede um aumento temporário da quota
+type APIKeyQuotaBoostRequest struct {
+	Extra         int    `json:"extra" binding:"required,min=1"`
+	DurationHours int    `json:"duration_hours" binding:"required,min=1,max=2160"`
+	Reason        string `json:"reason" binding:"max=500"`
+}
+
+// APIKeyQuotaStatus consumo do dia da key frente à quota vigente
+type APIKeyQuotaStatus struct {
+	APIKeyQuota
+	Tenant    string `json:"tenant"`
+	Boost     int    `json:"boost"`     // soma dos aumentos vigentes
+	Limit     int    `json:"limit"`     // quota + aumentos
+	Burst     int    `json:"burst"`     // chamadas toleradas além do limite
+	Used      int64  `json:"used"`      // chamadas no dia (horário de Brasília)
+	Remaining int64  `json:"remaining"` // chamadas até o limite
+	ResetAt   int64  `json:"reset_at"`  // meia-noite seguinte, horário de Brasília
+}
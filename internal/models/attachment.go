@@ -0,0 +1,21 @@
+package models
+
+// Attachment representa um arquivo (formulário, modelo) anexado a um serviço e armazenado no GCS
+type Attachment struct {
+	ID         string `json:"id"`
+	Filename   string `json:"filename"`
+	MimeType   string `json:"mime_type"`
+	Size       int64  `json:"size"`
+	ObjectName string `json:"object_name"`
+	UploadedAt int64  `json:"uploaded_at"`
+	UploadedBy string `json:"uploaded_by,omitempty"`
+	// Texto extraído (camada de texto ou OCR), indexado no search_content do serviço
+	TextStatus    string `json:"text_status,omitempty"`
+	ExtractedText string `json:"extracted_text,omitempty"`
+}
+
+// AttachmentDownloadResponse representa a URL assinada de download de um anexo
+type AttachmentDownloadResponse struct {
+	URL       string `json:"url"`
+	ExpiresAt int64  `json:"expires_at"`
+}
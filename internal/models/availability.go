@@ -0,0 +1,16 @@
+package models
+
+// AvailabilityWarning aviso de janela de disponibilidade prestes a encerrar
+type AvailabilityWarning struct {
+	ID             string   `json:"id"`
+	NomeServico    string   `json:"nome_servico"`
+	OrgaoGestor    []string `json:"orgao_gestor"`
+	AvailableUntil int64    `json:"available_until"`
+	HoursLeft      int      `json:"hours_left"`
+}
+
+// AvailabilityWarningList serviços publicados cuja janela encerra em breve
+type AvailabilityWarningList struct {
+	WithinDays int                   `json:"within_days"`
+	Services   []AvailabilityWarning `json:"services"`
+}
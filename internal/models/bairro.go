@@ -0,0 +1,37 @@
+package models
+
+// Bairro bairro da lista canônica da cidade, com a região administrativa e a zona a que pertence
+type Bairro struct {
+	ID                   string   `json:"id"`
+	Nome                 string   `json:"nome" binding:"required"`
+	RegiaoAdministrativa string   `json:"regiao_administrativa" binding:"required"` // ex.: Tijuca, Méier
+	Zona                 string   `json:"zona" binding:"required"`                  // Centro, Norte, Sul ou Oeste
+	Aliases              []string `json:"aliases,omitempty"`                        // grafias alternativas (ex.: Jacarepaguá → JPA)
+	UpdatedAt            int64    `json:"updated_at"`
+}
+
+// BairroList lista canônica de bairros
+type BairroList struct {
+	Total   int      `json:"total"`
+	Bairros []Bairro `json:"bairros"`
+}
+
+// BairroImportRequest substitui a lista canônica de bairros
+type BairroImportRequest struct {
+	Bairros []Bairro `json:"bairros" binding:"required,min=1,dive"`
+}
+
+// Abrangência do termo resolvido na lista de bairros
+const (
+	BairroMatchBairro = "bairro"
+	BairroMatchRegiao = "regiao_administrativa"
+	BairroMatchZona   = "zona"
+)
+
+// BairroResolution bairros correspondentes a um termo (bairro, alias, região administrativa ou zona)
+type BairroResolution struct {
+	Termo   string   `json:"termo"`
+	Tipo    string   `json:"tipo"` // bairro, regiao_administrativa ou zona
+	Nome    string   `json:"nome"` // nome canônico do bairro, região ou zona
+	Bairros []string `json:"bairros"`
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+)
+
+// Tipos de ação dos botões
+const (
+	ButtonActionExternalLink  = "external_link"   // link externo (url_service em https)
+	ButtonActionPrefLoginFlow = "pref_login_flow" // login gov.br/Carioca; url_service é o destino após o login
+	ButtonActionWhatsApp      = "whatsapp"        // conversa no WhatsApp com phone e message opcional
+	ButtonActionPhone         = "phone"           // ligação para phone
+)
+
+// Normalize valida os campos exigidos pelo action_type e os normaliza: URLs em https, telefones
+// em E.164 e url_service gerado para whatsapp (https://wa.me) e phone (tel:). Botões sem
+// action_type, cadastrados antes dos tipos, têm o tipo inferido pela URL.
+func (b *Button) Normalize() error {
+	if b.ActionType == "" {
+		b.inferActionType()
+	}
+
+	switch b.ActionType {
+	case ButtonActionExternalLink, ButtonActionPrefLoginFlow:
+		normalized, err := utils.EnforceHTTPS(b.URLService)
+		if err != nil {
+			return fmt.Errorf("url_service: %w", err)
+		}
+		b.URLService = normalized
+		b.Phone = ""
+		b.Message = ""
+
+	case ButtonActionWhatsApp:
+		phone, err := utils.NormalizePhone(b.Phone)
+		if err != nil || !strings.HasPrefix(phone, "+") {
+			return fmt.Errorf("phone: %w", utils.ErrInvalidPhone)
+		}
+		b.Phone = phone
+		b.Message = strings.TrimSpace(b.Message)
+		b.URLService = "https://wa.me/" + strings.TrimPrefix(phone, "+")
+		if b.Message != "" {
+			b.URLService += "?text=" + url.QueryEscape(b.Message)
+		}
+
+	case ButtonActionPhone:
+		phone, err := utils.NormalizePhone(b.Phone)
+		if err != nil {
+			return fmt.Errorf("phone: %w", err)
+		}
+		b.Phone = phone
+		b.Message = ""
+		b.URLService = "tel:" + phone
+
+	default:
+		return fmt.Errorf("action_type inválido: %q", b.ActionType)
+	}
+	return nil
+}
+
+// inferActionType identifica o tipo pela URL de botões antigos: tel: vira phone, links do
+// WhatsApp viram whatsapp (com telefone e mensagem extraídos) e o restante, external_link
+func (b *Button) inferActionType() {
+	raw := strings.TrimSpace(b.URLService)
+	b.ActionType = ButtonActionExternalLink
+
+	if strings.HasPrefix(strings.ToLower(raw), "tel:") {
+		b.ActionType = ButtonActionPhone
+		if b.Phone == "" {
+			b.Phone = raw[len("tel:"):]
+		}
+		return
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return
+	}
+	host := strings.TrimPrefix(strings.ToLower(parsed.Host), "www.")
+	switch host {
+	case "wa.me":
+		b.ActionType = ButtonActionWhatsApp
+		if b.Phone == "" {
+			b.Phone = "+" + strings.Trim(parsed.Path, "/")
+		}
+	case "api.whatsapp.com", "web.whatsapp.com":
+		b.ActionType = ButtonActionWhatsApp
+		if b.Phone == "" {
+			b.Phone = "+" + strings.TrimPrefix(strings.TrimSpace(parsed.Query().Get("phone")), "+")
+		}
+	default:
+		return
+	}
+	if b.Message == "" {
+		b.Message = parsed.Query().Get("text")
+	}
+}
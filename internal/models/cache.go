@@ -0,0 +1,30 @@
+package models
+
+// CacheInfo situação de um cache nesta réplica
+type CacheInfo struct {
+	Name      string  `json:"name"`
+	Enabled   bool    `json:"enabled"`
+	Entries   int     `json:"entries"`
+	Capacity  int     `json:"capacity,omitempty"` // capacidade própria; os caches compartilhados dividem shared_capacity
+	Hits      int64   `json:"hits"`
+	StaleHits int64   `json:"stale_hits,omitempty"` // search: respostas vencidas servidas durante a revalidação
+	RedisHits int64   `json:"redis_hits,omitempty"` // response: respostas servidas do Redis
+	Misses    int64   `json:"misses"`
+	Bypassed  int64   `json:"bypassed,omitempty"` // search: buscas com no_cache
+	HitRatio  float64 `json:"hit_ratio"`          // acertos / acessos; 0 sem acessos
+}
+
+// CacheStatsResponse situação dos caches de busca desta réplica. Análises de query, embeddings
+// e traduções dividem o mesmo cache em memória (shared_entries/shared_capacity).
+type CacheStatsResponse struct {
+	Caches         []CacheInfo `json:"caches"`
+	SharedEntries  int         `json:"shared_entries"`
+	SharedCapacity int         `json:"shared_capacity"`
+}
+
+// CacheInvalidationResponse resultado do descarte de entradas por prefixo
+type CacheInvalidationResponse struct {
+	Cache   string `json:"cache"`
+	Prefix  string `json:"prefix"`
+	Removed int    `json:"removed"`
+}
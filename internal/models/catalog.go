@@ -0,0 +1,30 @@
+package models
+
+// CatalogService serviço publicado no catálogo A–Z
+type CatalogService struct {
+	ID          string `json:"id"`
+	NomeServico string `json:"nome_servico"`
+	Slug        string `json:"slug"`
+	TemaGeral   string `json:"tema_geral"`
+	Resumo      string `json:"resumo,omitempty"`
+}
+
+// CatalogLetter letra do índice A–Z com o total de serviços; "#" agrupa os nomes que não
+// começam com letra
+type CatalogLetter struct {
+	Letter string `json:"letter"`
+	Count  int    `json:"count"`
+}
+
+// CatalogGroup serviços de uma letra, em ordem alfabética
+type CatalogGroup struct {
+	Letter   string           `json:"letter"`
+	Services []CatalogService `json:"services"`
+}
+
+// CatalogResponse índice A–Z com as contagens de todas as letras e os grupos solicitados
+type CatalogResponse struct {
+	Letters []CatalogLetter `json:"letters"`
+	Groups  []CatalogGroup  `json:"groups"`
+	Total   int             `json:"total"` // serviços nos grupos retornados
+}
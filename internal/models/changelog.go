@@ -0,0 +1,26 @@
+package models
+
+// ChangelogFieldChange representa a mudança de um campo público do serviço
+type ChangelogFieldChange struct {
+	Field    string      `json:"field"`
+	Label    string      `json:"label"`
+	OldValue interface{} `json:"old_value,omitempty"`
+	NewValue interface{} `json:"new_value,omitempty"`
+}
+
+// ChangelogEntry representa uma alteração pública de um serviço, sem dados de quem a fez
+type ChangelogEntry struct {
+	Version    int64                  `json:"version"`
+	ChangedAt  int64                  `json:"changed_at"`
+	ChangeType string                 `json:"change_type"`
+	Changes    []ChangelogFieldChange `json:"changes"`
+}
+
+// ServiceChangelog representa o histórico público de alterações de um serviço
+type ServiceChangelog struct {
+	ServiceID   string           `json:"service_id"`
+	NomeServico string           `json:"nome_servico"`
+	Page        int              `json:"page"`
+	PerPage     int              `json:"per_page"`
+	Entries     []ChangelogEntry `json:"entries"`
+}
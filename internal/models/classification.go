@@ -0,0 +1,73 @@
+package models
+
+// CategorySuggestionStatus situação de uma sugestão de categoria
+type CategorySuggestionStatus string
+
+const (
+	CategorySuggestionPending    CategorySuggestionStatus = "pending"    // Aguardando decisão do editor
+	CategorySuggestionAccepted   CategorySuggestionStatus = "accepted"   // Editor aplicou a sugestão
+	CategorySuggestionRejected   CategorySuggestionStatus = "rejected"   // Editor descartou a sugestão sem corrigir
+	CategorySuggestionCorrected  CategorySuggestionStatus = "corrected"  // Editor escolheu outra categoria
+	CategorySuggestionSuperseded CategorySuggestionStatus = "superseded" // Substituída por uma classificação mais recente
+)
+
+// Motivos para classificar um serviço
+const (
+	ClassificationReasonEmpty        = "empty"        // tema_geral vazio
+	ClassificationReasonInconsistent = "inconsistent" // tema_geral fora da taxonomia
+	ClassificationReasonManual       = "manual"       // solicitada pelo editor
+)
+
+// CategorySuggestion sugestão de tema_geral/sub_categoria gerada pelo Gemini para um serviço
+type CategorySuggestion struct {
+	ID                    string                   `json:"id"`
+	ServiceID             string                   `json:"service_id"`
+	NomeServico           string                   `json:"nome_servico"`
+	Reason                string                   `json:"reason"`
+	OriginalTema          string                   `json:"original_tema"`
+	OriginalSubCategoria  string                   `json:"original_sub_categoria,omitempty"`
+	SuggestedTema         string                   `json:"suggested_tema"`
+	SuggestedSubCategoria string                   `json:"suggested_sub_categoria,omitempty"`
+	Confidence            float64                  `json:"confidence"`
+	Justificativa         string                   `json:"justificativa,omitempty"`
+	Model                 string                   `json:"model"`
+	PromptVersion         string                   `json:"prompt_version"`
+	Status                CategorySuggestionStatus `json:"status"`
+	CreatedAt             int64                    `json:"created_at"`
+	ResolvedAt            int64                    `json:"resolved_at,omitempty"`
+	ResolvedBy            string                   `json:"resolved_by,omitempty"`
+	FinalTema             string                   `json:"final_tema,omitempty"`
+	FinalSubCategoria     string                   `json:"final_sub_categoria,omitempty"`
+}
+
+// RejectCategorySuggestionRequest corpo da rejeição de uma sugestão. Se tema_geral for
+// informado, ele é aplicado ao serviço e a sugestão é registrada como corrigida.
+type RejectCategorySuggestionRequest struct {
+	TemaGeral    string  `json:"tema_geral"`
+	SubCategoria *string `json:"sub_categoria,omitempty"`
+}
+
+// CategorySuggestionRates contadores e taxas de um conjunto de sugestões
+type CategorySuggestionRates struct {
+	Total          int     `json:"total"`
+	Pending        int     `json:"pending"`
+	Accepted       int     `json:"accepted"`
+	Rejected       int     `json:"rejected"`
+	Corrected      int     `json:"corrected"`
+	AcceptanceRate float64 `json:"acceptance_rate"` // aceitas / resolvidas
+	CorrectionRate float64 `json:"correction_rate"` // corrigidas / resolvidas
+}
+
+// CategorySuggestionStats estatísticas das sugestões, gerais e por versão do prompt
+type CategorySuggestionStats struct {
+	CategorySuggestionRates
+	ByPromptVersion map[string]*CategorySuggestionRates `json:"by_prompt_version"`
+	TopCorrections  []CategoryCorrection                `json:"top_corrections"`
+}
+
+// CategoryCorrection par sugerido → escolhido pelo editor e quantas vezes ocorreu
+type CategoryCorrection struct {
+	SuggestedTema string `json:"suggested_tema"`
+	FinalTema     string `json:"final_tema"`
+	Count         int    `json:"count"`
+}
@@ -0,0 +1,23 @@
+package models
+
+// Eventos enviados pelo CMS de origem dos serviços
+const (
+	CMSEventCreate = "create"
+	CMSEventUpdate = "update"
+	CMSEventDelete = "delete"
+)
+
+// CMSWebhookEvent notificação de alteração de um serviço no CMS de origem
+type CMSWebhookEvent struct {
+	Event   string                 `json:"event" binding:"required,oneof=create update delete"`
+	ID      string                 `json:"id" binding:"required"` // ID do serviço em prefrio_services_base
+	Service *PrefRioServiceRequest `json:"service,omitempty"`     // Obrigatório em create e update
+}
+
+// CMSWebhookResult resultado da aplicação da notificação
+type CMSWebhookResult struct {
+	Event   string          `json:"event"`
+	ID      string          `json:"id"`
+	Action  string          `json:"action"` // created, updated, deleted ou ignored
+	Service *PrefRioService `json:"service,omitempty"`
+}
@@ -0,0 +1,30 @@
+package models
+
+// ServiceComment comentário editorial interno sobre um serviço. Respostas apontam para o
+// comentário pai (parent_id) e são aninhadas em replies na listagem.
+type ServiceComment struct {
+	ID         string           `json:"id"`
+	ServiceID  string           `json:"service_id"`
+	ParentID   string           `json:"parent_id,omitempty"`
+	Body       string           `json:"body"`
+	AuthorName string           `json:"author_name"`
+	AuthorCPF  string           `json:"-"` // Usado apenas para verificar a autoria
+	Mentions   []string         `json:"mentions,omitempty"`
+	CreatedAt  int64            `json:"created_at"`
+	UpdatedAt  int64            `json:"updated_at,omitempty"`
+	Deleted    bool             `json:"deleted,omitempty"` // Removido, mantido para preservar as respostas
+	Replies    []ServiceComment `json:"replies,omitempty"`
+}
+
+// ServiceCommentRequest dados para criar ou editar um comentário
+type ServiceCommentRequest struct {
+	Body     string `json:"body" binding:"required,max=5000"`
+	ParentID string `json:"parent_id,omitempty"` // Ignorado na edição
+}
+
+// ServiceCommentList comentários de um serviço organizados em threads
+type ServiceCommentList struct {
+	ServiceID string           `json:"service_id"`
+	Total     int              `json:"total"`
+	Threads   []ServiceComment `json:"threads"`
+}
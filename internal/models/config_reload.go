@@ -0,0 +1,9 @@
+package models
+
+// ConfigReloadResponse resultado da recarga da configuração: os parâmetros de busca em vigor
+type ConfigReloadResponse struct {
+	ReloadedAt                int64    `json:"reloaded_at"`
+	SearchableCollections     []string `json:"searchable_collections"`
+	SearchCollectionTimeoutMs int      `json:"search_collection_timeout_ms"`
+	SearchHybridAlpha         float64  `json:"search_hybrid_alpha"`
+}
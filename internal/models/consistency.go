@@ -0,0 +1,35 @@
+package models
+
+// ConsistencyIssueType identifica o tipo de inconsistência encontrada entre collections
+type ConsistencyIssueType string
+
+const (
+	ConsistencyOrphanVersion      ConsistencyIssueType = "orphan_version"      // service_versions sem serviço vivo (e sem versão de exclusão)
+	ConsistencyDanglingTombamento ConsistencyIssueType = "dangling_tombamento" // tombamento apontando para serviço inexistente
+	ConsistencyOrphanHubEntry     ConsistencyIssueType = "orphan_hub_entry"    // hub_search cujo documento de origem sumiu
+	ConsistencyAliasMismatch      ConsistencyIssueType = "alias_mismatch"      // alias não aponta para a collection esperada
+	ConsistencyMissingBackup      ConsistencyIssueType = "missing_backup"      // migração referencia backup inexistente
+	ConsistencyUntrackedBackup    ConsistencyIssueType = "untracked_backup"    // collection de backup sem registro de migração
+)
+
+// ConsistencyIssue representa uma inconsistência encontrada
+type ConsistencyIssue struct {
+	Type       ConsistencyIssueType `json:"type"`
+	Collection string               `json:"collection"`
+	DocumentID string               `json:"document_id,omitempty"`
+	Reference  string               `json:"reference,omitempty"` // ID ou collection referenciada que não existe
+	Detail     string               `json:"detail"`
+	Fixable    bool                 `json:"fixable"`
+	Fixed      bool                 `json:"fixed"`
+	FixError   string               `json:"fix_error,omitempty"`
+}
+
+// ConsistencyReport representa o resultado de uma verificação de consistência
+type ConsistencyReport struct {
+	CheckedAt  int64                        `json:"checked_at"`
+	DurationMs int64                        `json:"duration_ms"`
+	FixApplied bool                         `json:"fix_applied"`
+	Summary    map[ConsistencyIssueType]int `json:"summary"`
+	TotalFixed int                          `json:"total_fixed"`
+	Issues     []ConsistencyIssue           `json:"issues"`
+}
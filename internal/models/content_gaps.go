@@ -0,0 +1,28 @@
+package models
+
+// ContentGapQuery query sem resultados e quantas vezes foi buscada no período
+type ContentGapQuery struct {
+	Query string `json:"query"`
+	Count int    `json:"count"`
+}
+
+// ContentGapCluster tema de demanda formado por queries sem resultados semanticamente
+// próximas. Label e Suggestion vêm do Gemini; sem ele, o label é a query mais buscada.
+type ContentGapCluster struct {
+	Label      string            `json:"label"`
+	Suggestion string            `json:"suggestion,omitempty"` // conteúdo sugerido para cobrir o tema
+	Searches   int               `json:"searches"`             // buscas sem resultado somadas das queries
+	Queries    []ContentGapQuery `json:"queries"`              // queries mais buscadas do tema
+}
+
+// ContentGapReport temas de demanda que o catálogo não cobre, dos mais aos menos buscados
+type ContentGapReport struct {
+	ID                string              `json:"id,omitempty"`
+	GeneratedAt       int64               `json:"generated_at"`
+	GeneratedBy       string              `json:"generated_by"`
+	Days              int                 `json:"days"`
+	ZeroResultQueries int                 `json:"zero_result_queries"` // queries distintas sem resultados
+	ClusteredQueries  int                 `json:"clustered_queries"`   // queries agrupadas (as mais buscadas)
+	Clusters          []ContentGapCluster `json:"clusters"`
+	Notes             []string            `json:"notes,omitempty"`
+}
@@ -0,0 +1,50 @@
+package models
+
+// ContentReplaceFilters restringe os serviços considerados na substituição
+type ContentReplaceFilters struct {
+	IDs          []string `json:"ids,omitempty"`
+	Status       *int     `json:"status,omitempty"`
+	TemaGeral    string   `json:"tema_geral,omitempty"`
+	SubCategoria string   `json:"sub_categoria,omitempty"`
+	Autor        string   `json:"autor,omitempty"`
+}
+
+// ContentReplaceRequest representa uma substituição de texto no conteúdo dos serviços
+type ContentReplaceRequest struct {
+	Find       string                `json:"find" binding:"required"`
+	Replace    string                `json:"replace"`
+	Regex      bool                  `json:"regex"`            // find é uma expressão regular (replace aceita $1, ${nome})
+	IgnoreCase bool                  `json:"ignore_case"`      // Ignora maiúsculas/minúsculas
+	Fields     []string              `json:"fields,omitempty"` // Vazio: todos os campos de texto
+	Filters    ContentReplaceFilters `json:"filters"`
+	Apply      bool                  `json:"apply"`            // false: apenas pré-visualiza
+	Reason     string                `json:"reason,omitempty"` // Justificativa registrada nas versões
+}
+
+// ContentReplaceMatch ocorrências em um campo, com um trecho antes e depois da substituição
+type ContentReplaceMatch struct {
+	Field       string `json:"field"`
+	Occurrences int    `json:"occurrences"`
+	Before      string `json:"before"`
+	After       string `json:"after"`
+}
+
+// ContentReplaceItem serviço afetado pela substituição
+type ContentReplaceItem struct {
+	ServiceID   string                `json:"service_id"`
+	NomeServico string                `json:"nome_servico"`
+	Matches     []ContentReplaceMatch `json:"matches"`
+	Status      string                `json:"status"` // BulkItemWouldApply, BulkItemApplied ou BulkItemFailed
+	Message     string                `json:"message,omitempty"`
+}
+
+// ContentReplaceResult resultado da pré-visualização ou aplicação da substituição
+type ContentReplaceResult struct {
+	Applied     bool                 `json:"applied"`
+	Fields      []string             `json:"fields"`
+	Scanned     int                  `json:"scanned"`
+	Affected    int                  `json:"affected"`
+	Occurrences int                  `json:"occurrences"`
+	Failed      int                  `json:"failed"`
+	Items       []ContentReplaceItem `json:"items"`
+}
@@ -0,0 +1,27 @@
+package models
+
+// Degradation falha ativa de uma dependência e o comportamento aplicado pela política
+type Degradation struct {
+	Dependency  string `json:"dependency"`           // gemini, typesense, cache
+	Action      string `json:"action"`               // hybrid_to_keyword, retry_other_node, bypass, fail
+	Since       int64  `json:"since"`                // primeira falha do episódio
+	LastSeenAt  int64  `json:"last_seen_at"`         // falha mais recente
+	Occurrences int64  `json:"occurrences"`          // falhas registradas no episódio
+	LastError   string `json:"last_error,omitempty"` // mensagem da falha mais recente
+}
+
+// DegradationStatus política de degradação e degradações ativas nesta réplica
+type DegradationStatus struct {
+	Degraded  bool              `json:"degraded"`
+	Policy    map[string]string `json:"policy"`
+	Active    []Degradation     `json:"active"`
+	Timestamp int64             `json:"timestamp"`
+}
+
+// ReplicaStatus estado da réplica somente leitura usada pelas buscas públicas
+type ReplicaStatus struct {
+	URL       string `json:"url"`
+	Healthy   bool   `json:"healthy"` // false: buscas direcionadas ao primário
+	Since     int64  `json:"since"`   // última mudança de estado
+	LastError string `json:"last_error,omitempty"`
+}
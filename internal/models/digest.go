@@ -0,0 +1,46 @@
+package models
+
+// DigestFrequency periodicidade do resumo enviado aos órgãos gestores
+type DigestFrequency string
+
+const (
+	DigestDaily  DigestFrequency = "daily"
+	DigestWeekly DigestFrequency = "weekly"
+)
+
+// DigestServiceRef identifica um serviço citado no resumo
+type DigestServiceRef struct {
+	ServiceID   string `json:"service_id"`
+	NomeServico string `json:"nome_servico"`
+	LastUpdate  int64  `json:"last_update"`
+}
+
+// DigestBrokenLink representa um link de serviço que não respondeu corretamente
+type DigestBrokenLink struct {
+	ServiceID   string `json:"service_id"`
+	NomeServico string `json:"nome_servico"`
+	URL         string `json:"url"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// OwnerDigest resume a situação dos serviços de um órgão gestor
+type OwnerDigest struct {
+	OrgaoGestor     string             `json:"orgao_gestor"`
+	Frequency       DigestFrequency    `json:"frequency"`
+	GeneratedAt     int64              `json:"generated_at"`
+	TotalServices   int                `json:"total_services"`
+	PendingApproval []DigestServiceRef `json:"pending_approval"`
+	BrokenLinks     []DigestBrokenLink `json:"broken_links"`
+	QualityIssues   []FreshnessIssue   `json:"quality_issues"`
+	Notes           []string           `json:"notes,omitempty"`
+	Recipients      []string           `json:"recipients,omitempty"`
+}
+
+// DigestRun representa uma execução da geração de resumos
+type DigestRun struct {
+	GeneratedAt int64           `json:"generated_at"`
+	Frequency   DigestFrequency `json:"frequency"`
+	Delivered   bool            `json:"delivered"`
+	Digests     []OwnerDigest   `json:"digests"`
+}
@@ -1,8 +1,6 @@
 package models
 
 import (
-	"encoding/json"
-
 	"github.com/prefeitura-rio/app-busca-search/internal/utils"
 )
 
@@ -118,13 +116,17 @@ type AgentsConfig struct {
 	ExclusiveForAgents bool   `json:"exclusive_for_agents" typesense:"exclusive_for_agents"`
 }
 
-// Button representa um botão de ação para o serviço
+// Button representa um botão de ação para o serviço. O action_type define os campos exigidos
+// (ver Button.Normalize); url_service é gerado para whatsapp e phone.
 type Button struct {
-	Titulo     string `json:"titulo"`
-	Descricao  string `json:"descricao"`
+	Titulo     string `json:"titulo" validate:"max=200"`
+	Descricao  string `json:"descricao" validate:"max=2000"`
 	IsEnabled  bool   `json:"is_enabled"`
 	Ordem      int    `json:"ordem"`
-	URLService string `json:"url_service"`
+	ActionType string `json:"action_type,omitempty" validate:"omitempty,oneof=external_link pref_login_flow whatsapp phone"`
+	URLService string `json:"url_service" validate:"max=2048"`
+	Phone      string `json:"phone,omitempty" validate:"max=32"`
+	Message    string `json:"message,omitempty" validate:"max=1000"`
 }
 
 // PrefRioService representa um serviço da collection prefrio_services_base
@@ -157,53 +159,67 @@ type PrefRioService struct {
 	CreatedAt             int64                  `json:"created_at" typesense:"created_at"`
 	LastUpdate            int64                  `json:"last_update" typesense:"last_update"`
 	SearchContent         string                 `json:"search_content" typesense:"search_content"`
+	NomeServicoFonetico   string                 `json:"nome_servico_fonetico,omitempty" typesense:"nome_servico_fonetico,optional"` // chave fonética do nome, gerada na indexação
 	Buttons               []Button               `json:"buttons" typesense:"buttons,optional"`
 	Embedding             []float64              `json:"embedding,omitempty" typesense:"embedding,optional"`
+	EmbeddingModel        string                 `json:"embedding_model,omitempty" typesense:"embedding_model,optional"`
+	EmbeddingDim          int                    `json:"embedding_dim,omitempty" typesense:"embedding_dim,optional"`
+	EmbeddingHash         string                 `json:"embedding_hash,omitempty" typesense:"embedding_hash,optional"` // utils.EmbeddingHash do vetor
 	Slug                  string                 `json:"slug" typesense:"slug"`
 	SlugHistory           []string               `json:"slug_history,omitempty" typesense:"slug_history,optional"`
+	Deprecated            bool                   `json:"deprecated" typesense:"deprecated,optional"`
+	ReplacedBy            string                 `json:"replaced_by" typesense:"replaced_by,optional"` // ID do serviço substituto
+	SunsetAt              *int64                 `json:"sunset_at" typesense:"sunset_at,optional"`     // despublicação automática (unix)
+	Tenant                string                 `json:"tenant,omitempty" typesense:"tenant,optional"` // município dono do serviço
+	Attachments           []Attachment           `json:"attachments" typesense:"attachments,optional"`
+	Entities              *ServiceEntities       `json:"entities,omitempty" typesense:"entities,optional"`                               // extraídas automaticamente
+	Translations          ServiceTranslations    `json:"translations,omitempty" typesense:"translations,optional"`                       // traduções revisadas por idioma
+	DeepLinks             map[string]string      `json:"deep_links,omitempty" typesense:"deep_links,optional"`                           // URLs canônicas por canal, com UTMs
+	AvailableFrom         int64                  `json:"available_from" typesense:"available_from,optional"`                             // início da janela sazonal (unix, 0 = sem limite)
+	AvailableUntil        int64                  `json:"available_until" typesense:"available_until,optional"`                           // fim da janela sazonal (unix, 0 = sem limite)
+	SeasonalPaused        bool                   `json:"seasonal_paused" typesense:"seasonal_paused,optional"`                           // despublicado no fim da janela, republicado na próxima abertura
+	DestaqueUntil         int64                  `json:"destaque_until,omitempty" typesense:"destaque_until,optional"`                   // fim do boost de fixar_destaque na busca (unix, 0 = sem limite)
+	Bairros               []string               `json:"bairros,omitempty" typesense:"bairros,optional"`                                 // bairros atendidos (vazio = toda a cidade)
+	RegioesAdmin          []string               `json:"regioes_administrativas,omitempty" typesense:"regioes_administrativas,optional"` // derivadas dos bairros
+	RestritoBairros       bool                   `json:"restrito_bairros" typesense:"restrito_bairros,optional"`                         // atendimento limitado aos bairros
+	Transporte            *ServiceTransport      `json:"transporte,omitempty" typesense:"transporte,optional"`                           // estações próximas dos canais presenciais
+
+	// Textos sem formatação markdown, gerados na indexação (resumo_plaintext é indexado para a busca)
+	ResumoPlaintext                string   `json:"resumo_plaintext,omitempty" typesense:"resumo_plaintext,optional"`
+	ResultadoSolicitacaoPlaintext  string   `json:"resultado_solicitacao_plaintext,omitempty" typesense:"resultado_solicitacao_plaintext,optional"`
+	DescricaoCompletaPlaintext     string   `json:"descricao_completa_plaintext,omitempty" typesense:"descricao_completa_plaintext,optional"`
+	DocumentosNecessariosPlaintext []string `json:"documentos_necessarios_plaintext,omitempty" typesense:"documentos_necessarios_plaintext,optional"`
+	InstrucoesSolicitantePlaintext string   `json:"instrucoes_solicitante_plaintext,omitempty" typesense:"instrucoes_solicitante_plaintext,optional"`
 }
 
-// MarshalJSON customiza a serialização JSON para adicionar campos plaintext
-func (s *PrefRioService) MarshalJSON() ([]byte, error) {
-	// Cria um alias para evitar recursão infinita
-	type Alias PrefRioService
-
-	// Cria estrutura auxiliar com todos os campos originais mais os plaintext
-	return json.Marshal(&struct {
-		*Alias
-		ResumoPlaintext                string   `json:"resumo_plaintext,omitempty"`
-		ResultadoSolicitacaoPlaintext  string   `json:"resultado_solicitacao_plaintext,omitempty"`
-		DescricaoCompletaPlaintext     string   `json:"descricao_completa_plaintext,omitempty"`
-		DocumentosNecessariosPlaintext []string `json:"documentos_necessarios_plaintext,omitempty"`
-		InstrucoesSolicitantePlaintext string   `json:"instrucoes_solicitante_plaintext,omitempty"`
-	}{
-		Alias:                          (*Alias)(s),
-		ResumoPlaintext:                utils.StripMarkdown(s.Resumo),
-		ResultadoSolicitacaoPlaintext:  utils.StripMarkdown(s.ResultadoSolicitacao),
-		DescricaoCompletaPlaintext:     utils.StripMarkdown(s.DescricaoCompleta),
-		DocumentosNecessariosPlaintext: utils.StripMarkdownArray(s.DocumentosNecessarios),
-		InstrucoesSolicitantePlaintext: utils.StripMarkdown(s.InstrucoesSolicitante),
-	})
+// SetPlaintextFields grava as versões sem formatação markdown dos textos do serviço; chamado na
+// gravação e na reindexação, não a cada resposta
+func (s *PrefRioService) SetPlaintextFields() {
+	s.ResumoPlaintext = utils.StripMarkdown(s.Resumo)
+	s.ResultadoSolicitacaoPlaintext = utils.StripMarkdown(s.ResultadoSolicitacao)
+	s.DescricaoCompletaPlaintext = utils.StripMarkdown(s.DescricaoCompleta)
+	s.DocumentosNecessariosPlaintext = utils.StripMarkdownArray(s.DocumentosNecessarios)
+	s.InstrucoesSolicitantePlaintext = utils.StripMarkdown(s.InstrucoesSolicitante)
 }
 
 // PrefRioServiceRequest representa os dados de entrada para criar/atualizar um serviço
 type PrefRioServiceRequest struct {
 	NomeServico           string                 `json:"nome_servico" validate:"required,max=20000"`
-	OrgaoGestor           []string               `json:"orgao_gestor" validate:"required,min=1"`
+	OrgaoGestor           []string               `json:"orgao_gestor" validate:"required,min=1,max=50,dive,max=500"`
 	Resumo                string                 `json:"resumo" validate:"required,max=20000"`
 	TempoAtendimento      string                 `json:"tempo_atendimento,omitempty" validate:"max=20000"`
 	CustoServico          string                 `json:"custo_servico,omitempty" validate:"max=20000"`
 	ResultadoSolicitacao  string                 `json:"resultado_solicitacao,omitempty" validate:"max=20000"`
 	DescricaoCompleta     string                 `json:"descricao_completa,omitempty" validate:"max=20000"`
-	DocumentosNecessarios []string               `json:"documentos_necessarios"`
+	DocumentosNecessarios []string               `json:"documentos_necessarios" validate:"max=100,dive,max=20000"`
 	InstrucoesSolicitante string                 `json:"instrucoes_solicitante" validate:"max=20000"`
-	CanaisDigitais        []string               `json:"canais_digitais"`
-	CanaisPresenciais     []string               `json:"canais_presenciais"`
+	CanaisDigitais        []string               `json:"canais_digitais" validate:"max=100,dive,max=2000"`
+	CanaisPresenciais     []string               `json:"canais_presenciais" validate:"max=100,dive,max=2000"`
 	ServicoNaoCobre       string                 `json:"servico_nao_cobre" validate:"max=20000"`
-	LegislacaoRelacionada []string               `json:"legislacao_relacionada"`
-	TemaGeral             string                 `json:"tema_geral" validate:"required,max=20000"`
+	LegislacaoRelacionada []string               `json:"legislacao_relacionada" validate:"max=100,dive,max=2000"`
+	TemaGeral             string                 `json:"tema_geral" validate:"max=20000"` // vazio: categoria sugerida automaticamente
 	SubCategoria          *string                `json:"sub_categoria,omitempty" validate:"omitempty,max=20000"`
-	PublicoEspecifico     []string               `json:"publico_especifico" validate:"required,min=1"`
+	PublicoEspecifico     []string               `json:"publico_especifico" validate:"required,min=1,max=50,dive,max=500"`
 	FixarDestaque         bool                   `json:"fixar_destaque"`
 	AwaitingApproval      bool                   `json:"awaiting_approval"`
 	PublishedAt           *int64                 `json:"published_at,omitempty"`
@@ -211,7 +227,95 @@ type PrefRioServiceRequest struct {
 	Agents                *AgentsConfig          `json:"agents,omitempty"`
 	ExtraFields           map[string]interface{} `json:"extra_fields,omitempty"`
 	Status                int                    `json:"status" validate:"min=0,max=1"`
-	Buttons               []Button               `json:"buttons"`
+	Buttons               []Button               `json:"buttons" validate:"max=20,dive"`
+	AvailableFrom         int64                  `json:"available_from,omitempty" validate:"min=0"`         // início da janela sazonal (unix)
+	AvailableUntil        int64                  `json:"available_until,omitempty" validate:"min=0"`        // fim da janela sazonal (unix)
+	DestaqueUntil         int64                  `json:"destaque_until,omitempty" validate:"min=0"`         // fim do boost de fixar_destaque na busca (unix)
+	Bairros               []string               `json:"bairros,omitempty" validate:"max=200,dive,max=200"` // bairros, regiões administrativas ou zonas atendidos (vazio = toda a cidade)
+}
+
+// DeprecateServiceRequest representa os dados para marcar um serviço como descontinuado
+type DeprecateServiceRequest struct {
+	ReplacedBy string `json:"replaced_by,omitempty"` // ID do serviço substituto
+	SunsetAt   *int64 `json:"sunset_at,omitempty"`   // momento da despublicação automática (unix)
+}
+
+// BulkStatusAction transição de status aplicada em lote
+type BulkStatusAction string
+
+const (
+	BulkStatusPublish   BulkStatusAction = "publish"   // Publica e marca como aprovado
+	BulkStatusUnpublish BulkStatusAction = "unpublish" // Volta para rascunho aguardando aprovação
+	BulkStatusArchive   BulkStatusAction = "archive"   // Despublica e descontinua, fora da fila de aprovação
+)
+
+// Situação de cada serviço no resultado da transição em lote
+const (
+	BulkItemApplied    = "applied"     // Transição aplicada (nova versão gerada)
+	BulkItemWouldApply = "would_apply" // Dry-run: a transição seria aplicada
+	BulkItemSkipped    = "skipped"     // Serviço já estava no estado desejado
+	BulkItemFailed     = "failed"      // Validação ou gravação falhou
+)
+
+// BulkStatusRequest representa uma transição de status em lote
+type BulkStatusRequest struct {
+	IDs    []string         `json:"ids" binding:"required,min=1,max=500"`
+	Action BulkStatusAction `json:"action" binding:"required,oneof=publish unpublish archive"`
+	DryRun bool             `json:"dry_run"`          // Apenas valida, sem alterar os serviços
+	Reason string           `json:"reason,omitempty"` // Justificativa registrada nas versões
+}
+
+// BulkStatusItem resultado da transição para um serviço
+type BulkStatusItem struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// BulkStatusResult resultado da transição de status em lote
+type BulkStatusResult struct {
+	Action  BulkStatusAction `json:"action"`
+	DryRun  bool             `json:"dry_run"`
+	Total   int              `json:"total"`
+	Applied int              `json:"applied"` // Aplicadas (ou que seriam aplicadas, em dry-run)
+	Skipped int              `json:"skipped"`
+	Failed  int              `json:"failed"`
+	Items   []BulkStatusItem `json:"items"`
+}
+
+// BulkImportItem resultado da importação de um serviço
+type BulkImportItem struct {
+	Index       int    `json:"index"`        // Posição do item no corpo (a partir de 0)
+	ID          string `json:"id,omitempty"` // ID do serviço criado (ou que seria criado, em dry-run)
+	NomeServico string `json:"nome_servico,omitempty"`
+	Status      string `json:"status"` // BulkItemApplied, BulkItemWouldApply ou BulkItemFailed
+	Message     string `json:"message,omitempty"`
+}
+
+// BulkImportResult resultado da importação de serviços em lote
+type BulkImportResult struct {
+	DryRun  bool             `json:"dry_run"`
+	Total   int              `json:"total"`
+	Created int              `json:"created"` // Criados (ou que seriam criados, em dry-run)
+	Failed  int              `json:"failed"`
+	Items   []BulkImportItem `json:"items"`
+}
+
+// PlaintextBackfillResult resultado da regeneração dos campos plaintext dos serviços
+type PlaintextBackfillResult struct {
+	Scanned int      `json:"scanned"`
+	Updated int      `json:"updated"` // Serviços cujos campos plaintext mudaram
+	Failed  int      `json:"failed"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// DeepLinkBackfillResult resultado da regeneração dos deep links dos serviços
+type DeepLinkBackfillResult struct {
+	Channels []string `json:"channels"`
+	Scanned  int      `json:"scanned"`
+	Updated  int      `json:"updated"` // Serviços cujos deep links mudaram
+	Failed   int      `json:"failed"`
+	Errors   []string `json:"errors,omitempty"`
 }
 
 // PrefRioServiceResponse representa a resposta de listagem de serviços
@@ -219,5 +323,7 @@ type PrefRioServiceResponse struct {
 	Found    int              `json:"found"`
 	OutOf    int              `json:"out_of"`
 	Page     int              `json:"page"`
+	PerPage  int              `json:"per_page"`
 	Services []PrefRioService `json:"services"`
+	PageInfo
 }
@@ -0,0 +1,40 @@
+package models
+
+// DuplicateService serviço de um grupo de possíveis duplicatas
+type DuplicateService struct {
+	ID          string   `json:"id"`
+	NomeServico string   `json:"nome_servico"`
+	TemaGeral   string   `json:"tema_geral"`
+	OrgaoGestor []string `json:"orgao_gestor,omitempty"`
+	CreatedAt   int64    `json:"created_at"`
+	Similarity  float64  `json:"similarity,omitempty"` // similaridade de cosseno com o canônico (ou com o serviço mais próximo do grupo)
+}
+
+// DuplicateCluster grupo de serviços publicados com embeddings quase idênticos. O canônico é a
+// sugestão de serviço a manter; os candidatos, de tombamento no canônico.
+type DuplicateCluster struct {
+	Canonical  DuplicateService   `json:"canonical"`
+	Candidates []DuplicateService `json:"candidates"`
+}
+
+// DuplicateReport relatório de possíveis duplicatas entre os serviços publicados
+type DuplicateReport struct {
+	ID              string             `json:"id,omitempty"`
+	GeneratedAt     int64              `json:"generated_at"`
+	GeneratedBy     string             `json:"generated_by"`
+	Threshold       float64            `json:"threshold"` // similaridade mínima para considerar dois serviços duplicatas
+	TotalServices   int                `json:"total_services"`
+	Compared        int                `json:"compared"`         // serviços com embedding comparados
+	SkippedServices []string           `json:"skipped_services"` // serviços sem embedding comparável
+	Clusters        []DuplicateCluster `json:"clusters"`
+}
+
+// ServiceMergeResult resultado (ou prévia, com dry_run) da mesclagem de um serviço duplicado no
+// destino: os campos vazios do destino recebem os da origem e a origem é despublicada, com
+// replaced_by apontando para o destino
+type ServiceMergeResult struct {
+	DryRun       bool            `json:"dry_run"`
+	CopiedFields []string        `json:"copied_fields"` // campos do destino preenchidos com os da origem
+	Target       *PrefRioService `json:"target"`
+	Source       *PrefRioService `json:"source"`
+}
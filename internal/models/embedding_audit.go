@@ -0,0 +1,52 @@
+package models
+
+// EmbeddingDriftReason motivo de um documento constar na auditoria de embeddings
+type EmbeddingDriftReason string
+
+const (
+	EmbeddingDrifted       EmbeddingDriftReason = "drift"          // vetor armazenado distante do gerado para o search_content atual
+	EmbeddingMissing       EmbeddingDriftReason = "missing"        // documento sem vetor armazenado
+	EmbeddingModelMismatch EmbeddingDriftReason = "model_mismatch" // vetor de outro modelo ou dimensão, não comparável
+	EmbeddingForced        EmbeddingDriftReason = "forced"         // vetor em dia, regravado por Force
+)
+
+// EmbeddingAuditOptions parâmetros da auditoria de embeddings
+type EmbeddingAuditOptions struct {
+	SampleSize  int     // documentos sorteados (0 = todos)
+	Threshold   float64 // distância de cosseno a partir da qual o vetor é considerado desatualizado
+	Seed        int64   // semente do sorteio, para repetir a mesma amostra
+	Concurrency int     // embeddings gerados em paralelo
+	Fix         bool    // regrava os vetores dos documentos reportados
+	Force       bool    // regrava os vetores de todos os documentos sorteados, mesmo os em dia (implica Fix)
+	FilterBy    string  // filter_by do Typesense que restringe os documentos auditados (vazio = todos)
+}
+
+// EmbeddingDrift documento cujo vetor armazenado não reflete o conteúdo atual
+type EmbeddingDrift struct {
+	ID          string               `json:"id"`
+	NomeServico string               `json:"nome_servico"`
+	Reason      EmbeddingDriftReason `json:"reason"`
+	Distance    float64              `json:"distance,omitempty"`
+	StoredModel string               `json:"stored_model,omitempty"`
+	LastUpdate  int64                `json:"last_update"`
+	Fixed       bool                 `json:"fixed"`
+	FixError    string               `json:"fix_error,omitempty"`
+}
+
+// EmbeddingAuditReport resultado da auditoria de embeddings
+type EmbeddingAuditReport struct {
+	CheckedAt      int64            `json:"checked_at"`
+	DurationMs     int64            `json:"duration_ms"`
+	Model          string           `json:"model"`
+	Threshold      float64          `json:"threshold"`
+	Filter         string           `json:"filter,omitempty"` // filter_by aplicado aos documentos
+	TotalDocuments int              `json:"total_documents"`  // documentos que atendem ao filtro
+	Sampled        int              `json:"sampled"`
+	Checked        int              `json:"checked"` // documentos comparados (sem erro ao gerar o embedding)
+	Errors         []string         `json:"errors,omitempty"`
+	MeanDistance   float64          `json:"mean_distance"` // entre os documentos com vetor comparável
+	MaxDistance    float64          `json:"max_distance"`
+	Drifted        []EmbeddingDrift `json:"drifted"`
+	FixApplied     bool             `json:"fix_applied"`
+	TotalFixed     int              `json:"total_fixed"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// ServiceEntities entidades extraídas da descrição do serviço (órgãos, documentos exigidos,
+// prazos e valores), indexadas como campos estruturados para filtros e snippets
+type ServiceEntities struct {
+	Orgaos       []string  `json:"orgaos"`
+	Documentos   []string  `json:"documentos"`
+	Prazos       []string  `json:"prazos"`                   // trechos originais (ex.: "5 dias úteis")
+	PrazoDiasMin *int      `json:"prazo_dias_min,omitempty"` // menor prazo mencionado, em dias
+	Valores      []float64 `json:"valores"`                  // valores em reais
+	ValorMin     *float64  `json:"valor_min,omitempty"`
+	Source       string    `json:"source"` // gemini ou local
+	ExtractedAt  int64     `json:"extracted_at"`
+}
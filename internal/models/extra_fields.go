@@ -0,0 +1,29 @@
+package models
+
+// ExtraFieldSchema schema JSON dos extra_fields dos serviços de um tema, validado ao salvar
+type ExtraFieldSchema struct {
+	Tema      string                 `json:"tema"`
+	Schema    map[string]interface{} `json:"schema"`   // JSON Schema (subconjunto suportado) com type object na raiz
+	Promoted  []PromotedExtraField   `json:"promoted"` // campos indexados no Typesense como extra_fields.<name>
+	UpdatedBy string                 `json:"updated_by,omitempty"`
+	UpdatedAt int64                  `json:"updated_at"`
+}
+
+// PromotedExtraField campo de primeiro nível do extra_fields indexado no Typesense. A indexação
+// passa a valer na próxima migração de schema.
+type PromotedExtraField struct {
+	Name  string `json:"name" binding:"required"`
+	Facet bool   `json:"facet"`
+}
+
+// ExtraFieldSchemaRequest cria ou substitui o schema de extra_fields de um tema
+type ExtraFieldSchemaRequest struct {
+	Schema   map[string]interface{} `json:"schema" binding:"required"`
+	Promoted []PromotedExtraField   `json:"promoted" binding:"max=20,dive"`
+}
+
+// ExtraFieldSchemaList schemas de extra_fields cadastrados
+type ExtraFieldSchemaList struct {
+	Schemas []ExtraFieldSchema `json:"schemas"`
+	Total   int                `json:"total"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "encoding/xml"
+
+// RSSFeed documento RSS 2.0 com as novidades de uma categoria
+type RSSFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel RSSChannel `xml:"channel"`
+}
+
+// RSSChannel canal do feed
+type RSSChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	TTL           int       `xml:"ttl,omitempty"` // minutos sugeridos entre consultas
+	Items         []RSSItem `xml:"item"`
+}
+
+// RSSItem serviço publicado ou atualizado
+type RSSItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link,omitempty"`
+	Description string  `xml:"description"`
+	Category    string  `xml:"category"`
+	GUID        RSSGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+// RSSGUID identificador único do item (serviço e versão)
+type RSSGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
@@ -0,0 +1,50 @@
+package models
+
+// FreshnessIssueType identifica o tipo de problema de atualização encontrado
+type FreshnessIssueType string
+
+const (
+	FreshnessIssueStale              FreshnessIssueType = "stale"               // Serviço sem atualização há N meses
+	FreshnessIssueExpiredLegislation FreshnessIssueType = "expired_legislation" // Legislação relacionada com data vencida ou revogada
+)
+
+// FreshnessIssue representa um serviço sinalizado no relatório de atualização
+type FreshnessIssue struct {
+	ServiceID   string             `json:"service_id"`
+	NomeServico string             `json:"nome_servico"`
+	TemaGeral   string             `json:"tema_geral"`
+	Autor       string             `json:"autor"`
+	OrgaoGestor []string           `json:"orgao_gestor,omitempty"`
+	Type        FreshnessIssueType `json:"type"`
+	LastUpdate  int64              `json:"last_update"`
+	DaysStale   int                `json:"days_stale,omitempty"`
+	Detail      string             `json:"detail,omitempty"`
+}
+
+// CategoryClickThroughDrop categoria cujo click-through caiu entre duas janelas consecutivas.
+// O click-through é a razão entre os cliques nos serviços da categoria e o total de buscas da
+// janela.
+type CategoryClickThroughDrop struct {
+	TemaGeral            string  `json:"tema_geral"`
+	PreviousClicks       int     `json:"previous_clicks"`
+	CurrentClicks        int     `json:"current_clicks"`
+	PreviousClickThrough float64 `json:"previous_click_through"`
+	CurrentClickThrough  float64 `json:"current_click_through"`
+	Change               float64 `json:"change"` // variação relativa (-0.4 = queda de 40%)
+}
+
+// FreshnessReport representa o relatório de atualização do conteúdo
+type FreshnessReport struct {
+	ID                      string                     `json:"id,omitempty"`
+	GeneratedAt             int64                      `json:"generated_at"`
+	GeneratedBy             string                     `json:"generated_by"`
+	StaleAfterMonths        int                        `json:"stale_after_months"`
+	TotalServices           int                        `json:"total_services"`
+	StaleCount              int                        `json:"stale_count"`
+	ExpiredLegislationCount int                        `json:"expired_legislation_count"`
+	Issues                  []FreshnessIssue           `json:"issues"`
+	ClickThroughWindowDays  int                        `json:"click_through_window_days,omitempty"`
+	ClickThroughDrops       []CategoryClickThroughDrop `json:"click_through_drops"`
+	Notes                   []string                   `json:"notes,omitempty"`
+	NotifiedOwners          int                        `json:"notified_owners"`
+}
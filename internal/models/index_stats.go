@@ -0,0 +1,54 @@
+package models
+
+// IndexStats métricas do cluster Typesense e das collections, expostas para os painéis de
+// operação sem acesso direto ao Typesense
+type IndexStats struct {
+	CollectedAt    int64                  `json:"collected_at"`
+	Memory         IndexMemoryStats       `json:"memory"`
+	Disk           IndexDiskStats         `json:"disk"`
+	CPUPercent     float64                `json:"cpu_percent"`
+	Latency        IndexLatencyStats      `json:"latency"`
+	TotalDocuments int64                  `json:"total_documents"`
+	Collections    []IndexCollectionStats `json:"collections"`
+	Metrics        map[string]float64     `json:"metrics"`          // todas as métricas numéricas do /metrics.json
+	Errors         []string               `json:"errors,omitempty"` // fontes que não puderam ser consultadas
+}
+
+// IndexMemoryStats memória do nó e do processo Typesense, em bytes
+type IndexMemoryStats struct {
+	SystemTotalBytes   int64   `json:"system_total_bytes"`
+	SystemUsedBytes    int64   `json:"system_used_bytes"`
+	ActiveBytes        int64   `json:"active_bytes"`
+	AllocatedBytes     int64   `json:"allocated_bytes"`
+	ResidentBytes      int64   `json:"resident_bytes"`
+	FragmentationRatio float64 `json:"fragmentation_ratio"`
+}
+
+// IndexDiskStats disco do nó, em bytes
+type IndexDiskStats struct {
+	TotalBytes int64 `json:"total_bytes"`
+	UsedBytes  int64 `json:"used_bytes"`
+}
+
+// IndexLatencyStats latência média (ms) e vazão (requisições/s) recentes, do /stats.json
+type IndexLatencyStats struct {
+	SearchMs            float64 `json:"search_ms"`
+	WriteMs             float64 `json:"write_ms"`
+	ImportMs            float64 `json:"import_ms"`
+	DeleteMs            float64 `json:"delete_ms"`
+	SearchPerSecond     float64 `json:"search_per_second"`
+	WritePerSecond      float64 `json:"write_per_second"`
+	TotalPerSecond      float64 `json:"total_per_second"`
+	OverloadedPerSecond float64 `json:"overloaded_per_second"`
+	PendingWriteBatches float64 `json:"pending_write_batches"`
+}
+
+// IndexCollectionStats tamanho de uma collection. O Typesense não informa bytes por collection;
+// o consumo de memória e disco é do nó inteiro.
+type IndexCollectionStats struct {
+	Name      string   `json:"name"`
+	Documents int64    `json:"documents"`
+	Fields    int      `json:"fields"`
+	CreatedAt int64    `json:"created_at,omitempty"`
+	Aliases   []string `json:"aliases,omitempty"`
+}
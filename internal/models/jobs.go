@@ -0,0 +1,38 @@
+package models
+
+// JobRun execução de uma rotina agendada, registrada na collection _jobs
+type JobRun struct {
+	ID          string `json:"id"`
+	Job         string `json:"job"`
+	Status      string `json:"status"`  // running, success, failed
+	Trigger     string `json:"trigger"` // schedule ou manual
+	TriggeredBy string `json:"triggered_by,omitempty"`
+	Owner       string `json:"owner"` // réplica que executou
+	StartedAt   int64  `json:"started_at"`
+	FinishedAt  int64  `json:"finished_at,omitempty"`
+	DurationMs  int64  `json:"duration_ms,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// JobInfo rotina registrada e sua situação
+type JobInfo struct {
+	Name     string  `json:"name"`
+	Schedule string  `json:"schedule"` // expressão cron ou @every <duração>
+	Paused   bool    `json:"paused"`
+	Running  bool    `json:"running"` // em execução nesta réplica
+	NextRun  int64   `json:"next_run,omitempty"`
+	LastRun  *JobRun `json:"last_run,omitempty"`
+}
+
+// JobList rotinas registradas
+type JobList struct {
+	Total int       `json:"total"`
+	Jobs  []JobInfo `json:"jobs"`
+}
+
+// JobRunList histórico de execuções de uma rotina, das mais recentes para as mais antigas
+type JobRunList struct {
+	Job   string   `json:"job"`
+	Total int      `json:"total"`
+	Runs  []JobRun `json:"runs"`
+}
@@ -0,0 +1,54 @@
+package models
+
+// Tipos de eventos das jornadas de busca
+const (
+	JourneyEventSearch = "search"
+	JourneyEventClick  = "click"
+)
+
+// JourneyEvent busca ou clique de uma sessão anônima. A sessão é um identificador gerado
+// pelo cliente, sem dados pessoais; a query é gravada mascarada e normalizada.
+type JourneyEvent struct {
+	SessionID   string `json:"session_id"`
+	Type        string `json:"type"` // search ou click
+	Query       string `json:"query,omitempty"`
+	ServiceID   string `json:"service_id,omitempty"`
+	Position    int    `json:"position,omitempty"`     // posição do resultado clicado (1 = primeiro)
+	ResultCount int    `json:"result_count,omitempty"` // resultados da busca
+	SearchType  string `json:"search_type,omitempty"`  // keyword, semantic, hybrid ou ai
+	LatencyMs   int64  `json:"latency_ms,omitempty"`   // duração da busca
+	Timestamp   int64  `json:"timestamp"`
+
+	// Intervenções que afetaram os resultados da busca (ResultAnnotation.Key), para atribuir
+	// os desfechos a elas
+	Interventions []string `json:"interventions,omitempty"`
+}
+
+// JourneyClickRequest clique em um resultado de busca, enviado pelo cliente
+type JourneyClickRequest struct {
+	SessionID string `json:"session_id" binding:"required"`
+	ServiceID string `json:"service_id" binding:"required"`
+	Query     string `json:"query"`
+	Position  int    `json:"position"`
+}
+
+// JourneyPath sequência de refinamentos de query repetida em várias jornadas, com o serviço
+// clicado ao final (vazio se a jornada terminou sem clique)
+type JourneyPath struct {
+	Queries   []string `json:"queries"`
+	ServiceID string   `json:"service_id,omitempty"`
+	Clicked   bool     `json:"clicked"`
+	Count     int      `json:"count"`
+}
+
+// JourneyReport jornadas reconstruídas das sessões no período
+type JourneyReport struct {
+	Days              int           `json:"days"`
+	Sessions          int           `json:"sessions"`
+	Journeys          int           `json:"journeys"`
+	JourneysWithClick int           `json:"journeys_with_click"`
+	RefinedJourneys   int           `json:"refined_journeys"`     // jornadas com mais de uma query
+	AvgQueriesToClick float64       `json:"avg_queries_to_click"` // queries até o clique, nas jornadas com clique
+	Paths             []JourneyPath `json:"paths"`                // refinamentos mais comuns
+	GeneratedAt       int64         `json:"generated_at"`
+}
@@ -0,0 +1,33 @@
+package models
+
+// LegacySunsetReport andamento da desativação das collections legadas: documentos ainda não
+// tombados, o tráfego que recebem e a lista priorizada do que tombar a seguir
+type LegacySunsetReport struct {
+	GeneratedAt int64                    `json:"generated_at"`
+	DurationMs  int64                    `json:"duration_ms"`
+	Days        int                      `json:"days"`         // janela dos cliques considerados
+	Frozen      bool                     `json:"frozen"`       // collections legadas somente leitura (LEGACY_COLLECTIONS_FROZEN)
+	TotalClicks int                      `json:"total_clicks"` // cliques em resultados de todas as collections no período
+	Collections []LegacyCollectionSunset `json:"collections"`
+	Worklist    []LegacySunsetItem       `json:"worklist"`
+}
+
+// LegacyCollectionSunset situação de uma collection legada
+type LegacyCollectionSunset struct {
+	Collection     string  `json:"collection"`
+	TotalDocuments int     `json:"total_documents"`
+	Tombados       int     `json:"tombados"`
+	Remaining      int     `json:"remaining"`     // documentos ainda não tombados
+	Clicks         int     `json:"clicks"`        // cliques nos documentos não tombados
+	TrafficShare   float64 `json:"traffic_share"` // fração de total_clicks (0-1)
+}
+
+// LegacySunsetItem documento legado ainda não tombado, na ordem em que deve ser tombado
+type LegacySunsetItem struct {
+	Priority     int     `json:"priority"` // 1 = o mais acessado
+	Collection   string  `json:"collection"`
+	ID           string  `json:"id"`
+	Title        string  `json:"title,omitempty"` // campo de título do field_mapping da collection
+	Clicks       int     `json:"clicks"`
+	TrafficShare float64 `json:"traffic_share"`
+}
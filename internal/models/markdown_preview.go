@@ -0,0 +1,33 @@
+package models
+
+// Tipos de aviso da pré-visualização de markdown
+const (
+	MarkdownWarningSanitized  = "sanitized"           // HTML ou link executável removido
+	MarkdownWarningBrokenLink = "broken_link"         // link vazio, inválido ou inacessível
+	MarkdownWarningUnclosed   = "unclosed_formatting" // marcação sem fechamento (**, _, `, ~~, [)
+	MarkdownWarningHeading    = "heading_misuse"      // título de nível 1, salto de nível ou vazio
+)
+
+// MarkdownPreviewRequest campos em markdown a renderizar, pelo nome do campo do serviço
+type MarkdownPreviewRequest struct {
+	Fields     map[string]string `json:"fields" binding:"required,min=1,max=20"`
+	CheckLinks bool              `json:"check_links"` // verifica se os links externos respondem
+}
+
+// MarkdownWarning problema encontrado em um campo
+type MarkdownWarning struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// MarkdownPreviewField HTML renderizado e avisos de um campo
+type MarkdownPreviewField struct {
+	HTML     string            `json:"html"`
+	Warnings []MarkdownWarning `json:"warnings"`
+}
+
+// MarkdownPreviewResponse resultado da pré-visualização por campo
+type MarkdownPreviewResponse struct {
+	Fields        map[string]MarkdownPreviewField `json:"fields"`
+	TotalWarnings int                             `json:"total_warnings"`
+}
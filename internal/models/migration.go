@@ -72,7 +72,9 @@ type MigrationHistoryResponse struct {
 	Found      int                    `json:"found"`
 	OutOf      int                    `json:"out_of"`
 	Page       int                    `json:"page"`
+	PerPage    int                    `json:"per_page"`
 	Migrations []MigrationHistoryItem `json:"migrations"`
+	PageInfo
 }
 
 // MigrationRollbackRequest representa uma solicitação de rollback
@@ -0,0 +1,23 @@
+package models
+
+// Noticia notícia ou evento da cidade publicado pela redação, indexado na collection noticias
+type Noticia struct {
+	ID          string   `json:"id"`
+	SourceID    string   `json:"source_id"` // ID do post no CMS da redação
+	Title       string   `json:"title"`
+	Summary     string   `json:"summary,omitempty"`
+	Body        string   `json:"body"`
+	URL         string   `json:"url,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	PublishedAt int64    `json:"published_at"`
+	UpdatedAt   int64    `json:"updated_at"`
+	Status      int32    `json:"status"` // 1 = publicada
+}
+
+// NoticiasSyncResult resultado de uma sincronização com o CMS da redação
+type NoticiasSyncResult struct {
+	Since    int64 `json:"since"` // alterações a partir deste timestamp
+	Fetched  int   `json:"fetched"`
+	Upserted int   `json:"upserted"`
+	Removed  int   `json:"removed"` // despublicadas ou excluídas no CMS
+}
@@ -0,0 +1,61 @@
+package models
+
+// OpsAction ação de recuperação executada pelo plantão em /admin/ops
+type OpsAction string
+
+const (
+	OpsRecreateCollection OpsAction = "recreate-collection"  // Recria a collection de serviços ausente a partir do schema registrado
+	OpsRestoreBackupAlias OpsAction = "restore-backup-alias" // Aponta o alias de serviços para o backup mais recente
+	OpsClearCaches        OpsAction = "clear-caches"         // Descarta os caches de busca e de respostas em todas as réplicas
+	OpsRestartJob         OpsAction = "restart-job"          // Cancela a execução travada de um job e o executa novamente
+)
+
+// Situação das ações registradas na auditoria
+const (
+	OpsStatusSuccess = "success"
+	OpsStatusFailed  = "failed"
+)
+
+// OpsRequest executa uma ação de recuperação. Sem confirmation_token a ação não é executada: a
+// resposta (409) descreve o que seria feito e traz o token que a confirma.
+type OpsRequest struct {
+	Target            string `json:"target,omitempty"` // collection, alias ou job, conforme a ação
+	ConfirmationToken string `json:"confirmation_token,omitempty"`
+}
+
+// OpsPlan descreve o que a ação fará e o token que a confirma. O token deixa de valer se o
+// estado mudar antes da confirmação (ex.: o alias foi reapontado, um novo backup foi criado).
+type OpsPlan struct {
+	Action            OpsAction `json:"action"`
+	Target            string    `json:"target,omitempty"`
+	Description       string    `json:"description"`
+	ConfirmationToken string    `json:"confirmation_token"`
+}
+
+// OpsResult resultado de uma ação executada
+type OpsResult struct {
+	Action     OpsAction `json:"action"`
+	Target     string    `json:"target,omitempty"`
+	Detail     string    `json:"detail"`
+	Job        *JobRun   `json:"job,omitempty"` // execução iniciada por restart-job
+	ExecutedBy string    `json:"executed_by"`
+	ExecutedAt int64     `json:"executed_at"`
+}
+
+// OpsAuditEntry registro de uma ação executada (ou que falhou) pelo plantão
+type OpsAuditEntry struct {
+	ID        string    `json:"id"`
+	Action    OpsAction `json:"action"`
+	Target    string    `json:"target,omitempty"`
+	User      string    `json:"user"`
+	Status    string    `json:"status"` // OpsStatusSuccess ou OpsStatusFailed
+	Detail    string    `json:"detail,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt int64     `json:"created_at"`
+}
+
+// OpsAuditList últimas ações registradas, das mais recentes para as mais antigas
+type OpsAuditList struct {
+	Total   int             `json:"total"`
+	Entries []OpsAuditEntry `json:"entries"`
+}
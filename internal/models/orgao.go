@@ -0,0 +1,35 @@
+package models
+
+// Orgao órgão da prefeitura no diretório de órgãos; os valores de orgao_gestor dos serviços
+// devem corresponder à sigla ou ao nome de um órgão cadastrado
+type Orgao struct {
+	Sigla     string `json:"sigla"`
+	Nome      string `json:"nome"`
+	Site      string `json:"site,omitempty"`
+	Contato   string `json:"contato,omitempty"` // e-mail ou telefone de atendimento
+	UpdatedBy string `json:"updated_by,omitempty"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+// OrgaoRequest cria ou substitui um órgão (a sigla vem do path)
+type OrgaoRequest struct {
+	Nome    string `json:"nome" binding:"required,max=500"`
+	Site    string `json:"site" binding:"omitempty,url,max=500"`
+	Contato string `json:"contato" binding:"max=500"`
+}
+
+// OrgaoList órgãos cadastrados
+type OrgaoList struct {
+	Total  int     `json:"total"`
+	Orgaos []Orgao `json:"orgaos"`
+}
+
+// OrgaoServicesResponse serviços publicados de um órgão
+type OrgaoServicesResponse struct {
+	Orgao    Orgao            `json:"orgao"`
+	Services []PrefRioService `json:"services"`
+	Found    int              `json:"found"`
+	Page     int              `json:"page"`
+	PerPage  int              `json:"per_page"`
+	PageInfo
+}
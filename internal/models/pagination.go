@@ -0,0 +1,23 @@
+package models
+
+// PageInfo metadados de paginação comuns às listagens e buscas; acompanham os headers Link
+// (RFC 5988) com as páginas first, prev, next e last
+type PageInfo struct {
+	TotalPages int    `json:"total_pages"`
+	HasNext    bool   `json:"has_next"`
+	HasPrev    bool   `json:"has_prev"`
+	NextCursor string `json:"next_cursor,omitempty"` // continuação estável pelo parâmetro cursor, onde suportado
+}
+
+// NewPageInfo calcula a paginação da página (a partir de 1) sobre total itens
+func NewPageInfo(page, perPage, total int) PageInfo {
+	if perPage < 1 {
+		return PageInfo{}
+	}
+	totalPages := (total + perPage - 1) / perPage
+	return PageInfo{
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}
+}
@@ -0,0 +1,54 @@
+package models
+
+// PrivacyRequestType identifica o tipo de solicitação do titular (LGPD, art. 18)
+type PrivacyRequestType string
+
+const (
+	PrivacyRequestExport PrivacyRequestType = "export" // Acesso/portabilidade dos dados
+	PrivacyRequestDelete PrivacyRequestType = "delete" // Eliminação dos dados
+)
+
+// PrivacyAction indica o que foi feito com os registros de uma collection
+type PrivacyAction string
+
+const (
+	PrivacyActionExport    PrivacyAction = "export"    // Registros incluídos na exportação
+	PrivacyActionDelete    PrivacyAction = "delete"    // Registros removidos
+	PrivacyActionAnonymize PrivacyAction = "anonymize" // Registros mantidos (histórico) sem identificação do titular
+)
+
+// PrivacyRequest corpo das solicitações de exportação e eliminação. O CPF só pode ser
+// informado por administradores; os demais usuários atuam sobre o próprio CPF.
+type PrivacyRequest struct {
+	CPF string `json:"cpf,omitempty"`
+}
+
+// PrivacyCollectionResult resultado da solicitação em uma collection
+type PrivacyCollectionResult struct {
+	Collection string        `json:"collection"`
+	Field      string        `json:"field"`
+	Action     PrivacyAction `json:"action"`
+	Records    int           `json:"records"`
+	Skipped    string        `json:"skipped,omitempty"` // Motivo quando a collection não foi processada
+	Error      string        `json:"error,omitempty"`
+}
+
+// PrivacyReceipt comprovante auditável de uma solicitação do titular. Não guarda o CPF,
+// apenas seu hash; o digest cobre todo o conteúdo do comprovante.
+type PrivacyReceipt struct {
+	ID           string                    `json:"id"`
+	Type         PrivacyRequestType        `json:"type"`
+	SubjectHash  string                    `json:"subject_hash"`
+	RequestedBy  string                    `json:"requested_by"`
+	RequestedAt  int64                     `json:"requested_at"`
+	Collections  []PrivacyCollectionResult `json:"collections"`
+	TotalRecords int                       `json:"total_records"`
+	Complete     bool                      `json:"complete"` // false se alguma collection falhou
+	Digest       string                    `json:"digest"`
+}
+
+// PrivacyExport dados do titular agrupados por collection, com o comprovante da exportação
+type PrivacyExport struct {
+	Receipt PrivacyReceipt                      `json:"receipt"`
+	Records map[string][]map[string]interface{} `json:"records"`
+}
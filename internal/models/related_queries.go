@@ -0,0 +1,15 @@
+package models
+
+// RelatedQueries queries buscadas nas mesmas sessões que Query ("quem buscou isto também
+// buscou"), das mais frequentes às menos
+type RelatedQueries struct {
+	Query   string   `json:"query"`
+	Related []string `json:"related"`
+}
+
+// RelatedQueriesResult resultado do cálculo das queries relacionadas
+type RelatedQueriesResult struct {
+	Sessions int `json:"sessions"` // sessões com mais de uma query distinta
+	Queries  int `json:"queries"`  // queries com ao menos uma relacionada
+	Removed  int `json:"removed"`  // queries sem relacionadas desde o cálculo anterior
+}
@@ -49,14 +49,17 @@ const (
 
 // ScoreInfo contém informações sobre os scores de relevância de um documento
 type ScoreInfo struct {
-	TextMatchNormalized *float64 `json:"text_match_normalized,omitempty"` // Score normalizado 0-1 do text_match
-	VectorSimilarity    *float64 `json:"vector_similarity,omitempty"`     // Similaridade vetorial 0-1 (1 = idêntico)
-	HybridScore         *float64 `json:"hybrid_score,omitempty"`          // Score híbrido combinado 0-1
-	RecencyFactor       *float64 `json:"recency_factor,omitempty"`        // Fator de recência aplicado (1.0 = recente, decai com o tempo)
-	FinalScore          *float64 `json:"final_score,omitempty"`           // Score final após aplicar recency boost
-	ThresholdApplied    string   `json:"threshold_applied,omitempty"`     // Tipo de threshold aplicado: "keyword", "semantic", "hybrid", "none"
-	ThresholdValue      *float64 `json:"threshold_value,omitempty"`       // Valor do threshold aplicado
-	PassedThreshold     bool     `json:"passed_threshold"`                // Se passou no threshold
+	TextMatchNormalized  *float64 `json:"text_match_normalized,omitempty"` // Score normalizado 0-1 do text_match
+	VectorSimilarity     *float64 `json:"vector_similarity,omitempty"`     // Similaridade vetorial 0-1 (1 = idêntico)
+	HybridScore          *float64 `json:"hybrid_score,omitempty"`          // Score híbrido combinado 0-1
+	RecencyFactor        *float64 `json:"recency_factor,omitempty"`        // Fator de recência aplicado (1.0 = recente, decai com o tempo)
+	FinalScore           *float64 `json:"final_score,omitempty"`           // Score final após aplicar recency boost
+	RecencyContribution  *float64 `json:"recency_contribution,omitempty"`  // final_score - score base: efeito do recency boost (≤ 0)
+	DestaqueBoost        *float64 `json:"destaque_boost,omitempty"`        // Fator aplicado aos serviços em destaque (fixar_destaque)
+	DestaqueContribution *float64 `json:"destaque_contribution,omitempty"` // Efeito do boost de destaque no final_score (≥ 0)
+	ThresholdApplied     string   `json:"threshold_applied,omitempty"`     // Tipo de threshold aplicado: "keyword", "semantic", "hybrid", "none"
+	ThresholdValue       *float64 `json:"threshold_value,omitempty"`       // Valor do threshold aplicado
+	PassedThreshold      bool     `json:"passed_threshold"`                // Se passou no threshold
 }
 
 // SearchRequest representa uma requisição de busca
@@ -69,16 +72,53 @@ type SearchRequest struct {
 	Alpha                 float64         `form:"alpha"` // Para hybrid (default 0.3)
 	ScoreThreshold        *ScoreThreshold `form:"score_threshold,omitempty"`
 	ExcludeAgentExclusive *bool           `form:"exclude_agent_exclusive"`
-	GenerateScores        bool            `form:"generate_scores"` // Gerar AI scores via LLM (apenas para type=ai)
-	RecencyBoost          bool            `form:"recency_boost"`   // Aplica boost por recência (docs recentes têm score maior)
+	GenerateScores        bool            `form:"generate_scores"`   // Gerar AI scores via LLM (apenas para type=ai)
+	RecencyBoost          bool            `form:"recency_boost"`     // Aplica boost por recência (docs recentes têm score maior)
+	Lang                  string          `form:"lang"`              // Idioma da query (pt, en, es...); vazio detecta automaticamente
+	DebugAnnotations      bool            `form:"debug_annotations"` // Anexa a cada resultado as intervenções que o afetaram (rebaixamentos, reescrita da query)
+
+	// Filtros por entidades extraídas da descrição
+	PrazoMaxDias *int     `form:"prazo_max_dias"` // menor prazo do serviço ≤ N dias
+	ValorMax     *float64 `form:"valor_max"`      // menor valor cobrado ≤ N reais
+	Documento    string   `form:"documento"`      // exige o documento (ex.: CPF)
+
+	// Serviços sazonais fora da janela de disponibilidade são ocultados; com include_out_of_window
+	// são retornados no fim, sinalizados em metadata.availability (upcoming ou closed)
+	IncludeOutOfWindow bool `form:"include_out_of_window"`
+
+	// Bairro, alias, região administrativa ou zona ("zona norte"): restringe os serviços com
+	// atendimento localizado aos bairros correspondentes; os que atendem toda a cidade continuam
+	Bairro        string   `form:"bairro"`
+	ParsedBairros []string `form:"-" json:"-"` // bairros resolvidos pelo handler na lista canônica
 
 	// V2-only: Override search configuration per request
 	SearchFields  string `form:"search_fields"`  // Comma-separated fields (e.g., "titulo,descricao,conteudo")
 	SearchWeights string `form:"search_weights"` // Comma-separated weights (e.g., "4,2,1")
 	Collections   string `form:"collections"`    // Comma-separated collections to search (e.g., "prefrio_services_base,hub_search")
+	DocTypes      string `form:"doc_types"`      // Comma-separated collection types to search (e.g., "news")
+	Cursor        string `form:"cursor"`         // next_cursor of the previous page; replaces page
+	Fields        string `form:"fields"`         // Comma-separated extra fields for collections with include_fields (e.g., "orgao_gestor,custo")
+	FacetBy       string `form:"facet_by"`       // Comma-separated fields to count results by (e.g., "tema_geral,is_free")
 
 	// Parsed collections (internal use, populated by handler)
 	ParsedCollections []string `form:"-" json:"-"`
+
+	// Parsed facet_by fields (internal use, populated by handler)
+	ParsedFacetBy []string `form:"-" json:"-"`
+
+	// Decoded cursor, or the first position of the search when paging by page (populated by the service)
+	ParsedCursor *SearchCursor `form:"-" json:"-"`
+
+	// Hits fetched from each collection, within the fan-out budget (populated by the service)
+	Window int `form:"-" json:"-"`
+}
+
+// SearchCursor posição de continuação da busca v2: a página seguinte e quantos resultados de cada
+// collection já foram consumidos, mais a impressão digital dos parâmetros da busca que o gerou
+type SearchCursor struct {
+	Page        int            `json:"p"`
+	Offsets     map[string]int `json:"o"`
+	Fingerprint string         `json:"f"`
 }
 
 // ServiceDocument representa um documento de serviço retornado pela busca
@@ -89,21 +129,37 @@ type ServiceDocument struct {
 	Category    string                 `json:"category"`
 	Subcategory *string                `json:"subcategory,omitempty"`
 	Slug        string                 `json:"slug,omitempty"`
+	DeepLinks   map[string]string      `json:"deep_links,omitempty"` // URLs canônicas por canal
 	Status      int32                  `json:"status"`
 	CreatedAt   int64                  `json:"created_at"`
 	UpdatedAt   int64                  `json:"updated_at"`
 	Metadata    map[string]interface{} `json:"metadata"`
+	Annotations []ResultAnnotation     `json:"annotations,omitempty"` // Intervenções que afetaram o resultado (debug_annotations)
 }
 
 // SearchResponse representa a resposta de uma busca
 type SearchResponse struct {
-	Results       []*ServiceDocument     `json:"results"`
-	TotalCount    int                    `json:"total_count"`    // Total original do Typesense
-	FilteredCount int                    `json:"filtered_count"` // Após aplicar thresholds
-	Page          int                    `json:"page"`
-	PerPage       int                    `json:"per_page"`
-	SearchType    SearchType             `json:"search_type"`
-	Metadata      map[string]interface{} `json:"metadata,omitempty"` // Para AI search
+	Results        []*ServiceDocument     `json:"results"`
+	TotalCount     int                    `json:"total_count"`    // Total original do Typesense
+	FilteredCount  int                    `json:"filtered_count"` // Após aplicar thresholds
+	Page           int                    `json:"page"`
+	PerPage        int                    `json:"per_page"`
+	SearchType     SearchType             `json:"search_type"`
+	Safety         *SafetyBlock           `json:"safety,omitempty"`          // Contatos de emergência para buscas sensíveis
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`        // Para AI search
+	DegradedMode   []string               `json:"degraded_mode,omitempty"`   // Degradações aplicadas (dependência:comportamento)
+	QueryMeta      *QueryMeta             `json:"query_meta,omitempty"`      // Idioma detectado e tradução da query
+	RelatedQueries []string               `json:"related_queries,omitempty"` // Queries buscadas nas mesmas sessões (até 3)
+	Interventions  []string               `json:"-"`                         // Intervenções aplicadas (ResultAnnotation.Key), gravadas no evento da busca
+	PageInfo
+}
+
+// QueryMeta idioma da query e, para buscas em outros idiomas, a tradução usada no índice pt-BR
+type QueryMeta struct {
+	OriginalQuery   string `json:"original_query"`
+	TranslatedQuery string `json:"translated_query,omitempty"`
+	Language        string `json:"language"`
+	Translated      bool   `json:"translated"`
 }
 
 // AISearchMetrics métricas do AI Agent Search
@@ -129,13 +185,35 @@ type QueryAnalysis struct {
 // ============================================================================
 
 // UnifiedDocument represents a document from any collection (v2 API)
-// Uses pure data passthrough - no field normalization
+// Data is a pure passthrough; only the common fields are normalized
 type UnifiedDocument struct {
 	ID         string                 `json:"id"`
 	Collection string                 `json:"collection"` // Which collection this document belongs to
 	Type       string                 `json:"type"`       // Document type from collection config (service, course, job, etc.)
 	Data       map[string]interface{} `json:"data"`       // Raw document data from Typesense
 	ScoreInfo  *ScoreInfo             `json:"score_info,omitempty"`
+
+	// Common fields read from data through the collection's field_mapping
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Category    string `json:"category,omitempty"`
+	URL         string `json:"url,omitempty"`
+
+	// Interventions that affected the result (debug_annotations)
+	Annotations []ResultAnnotation `json:"annotations,omitempty"`
+}
+
+// SearchFacet contagens de um campo pedido em facet_by, somadas entre as collections buscadas,
+// das mais frequentes para as menos frequentes
+type SearchFacet struct {
+	Field  string            `json:"field"`
+	Counts []FacetValueCount `json:"counts"`
+}
+
+// FacetValueCount resultados encontrados com um valor do campo
+type FacetValueCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
 }
 
 // UnifiedSearchResponse represents multi-collection search response (v2 API)
@@ -146,6 +224,20 @@ type UnifiedSearchResponse struct {
 	Page          int                    `json:"page"`
 	PerPage       int                    `json:"per_page"`
 	SearchType    SearchType             `json:"search_type"`
-	Collections   []string               `json:"collections"`        // Which collections were searched
-	Metadata      map[string]interface{} `json:"metadata,omitempty"` // Para AI search
+	Collections   []string               `json:"collections"`           // Which collections were searched
+	TypeCounts    map[string]int         `json:"type_counts,omitempty"` // Resultados encontrados por tipo (service, news...) para facetas
+	Facets        []SearchFacet          `json:"facets,omitempty"`      // Contagens por valor dos campos pedidos em facet_by
+	Safety        *SafetyBlock           `json:"safety,omitempty"`      // Contatos de emergência para buscas sensíveis
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`    // Para AI search
+	QueryMeta     *QueryMeta             `json:"query_meta,omitempty"`  // Idioma detectado e tradução da query
+
+	// Collections descartadas por exceder o tempo limite da busca (resultado parcial)
+	DroppedCollections []string `json:"dropped_collections,omitempty"`
+
+	// Queries buscadas nas mesmas sessões (até 3)
+	RelatedQueries []string `json:"related_queries,omitempty"`
+
+	// Interventions applied (ResultAnnotation.Key), recorded in the search event
+	Interventions []string `json:"-"`
+	PageInfo
 }
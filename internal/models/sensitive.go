@@ -0,0 +1,28 @@
+package models
+
+// EmergencyContact canal de ajuda exibido para buscas sensíveis
+type EmergencyContact struct {
+	Name        string `json:"name"`
+	Phone       string `json:"phone,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// SensitiveTopic tema sensível (emergência de saúde, violência, suicídio) identificado por
+// palavras-chave na query
+type SensitiveTopic struct {
+	ID       string             `json:"id"`
+	Label    string             `json:"label"`
+	Keywords []string           `json:"keywords"`
+	Message  string             `json:"message"`
+	Contacts []EmergencyContact `json:"contacts"`
+	Disabled bool               `json:"disabled,omitempty"` // remove um tema padrão via configuração
+}
+
+// SafetyBlock bloco exibido acima dos resultados quando a busca é sensível
+type SafetyBlock struct {
+	Topic    string             `json:"topic"`
+	Label    string             `json:"label"`
+	Message  string             `json:"message"`
+	Contacts []EmergencyContact `json:"contacts"`
+}
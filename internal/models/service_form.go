@@ -0,0 +1,38 @@
+package models
+
+// Tipos dos campos do formulário de serviço
+const (
+	FormFieldString   = "string"
+	FormFieldText     = "text" // texto longo, aceita markdown
+	FormFieldList     = "string[]"
+	FormFieldBoolean  = "boolean"
+	FormFieldInteger  = "integer"
+	FormFieldDateTime = "datetime" // timestamp unix em segundos
+	FormFieldObject   = "object"
+	FormFieldObjects  = "object[]"
+)
+
+// FormField campo editável do formulário de serviço, com as regras da validação do servidor
+type FormField struct {
+	Name          string              `json:"name"` // nome no JSON da requisição
+	Label         string              `json:"label"`
+	Type          string              `json:"type"`
+	Required      bool                `json:"required"`
+	MaxLength     int                 `json:"max_length,omitempty"`     // caracteres (texto) ou de cada item (lista)
+	MinItems      int                 `json:"min_items,omitempty"`      // listas
+	MaxItems      int                 `json:"max_items,omitempty"`      // listas
+	Min           *int64              `json:"min,omitempty"`            // números
+	Max           *int64              `json:"max,omitempty"`            // números
+	Options       []string            `json:"options,omitempty"`        // valores aceitos ou sugeridos
+	OptionsStrict bool                `json:"options_strict,omitempty"` // true: só os valores de options são aceitos
+	DependsOn     string              `json:"depends_on,omitempty"`     // campo que define as opções (ex.: sub_categoria por tema_geral)
+	OptionsBy     map[string][]string `json:"options_by,omitempty"`     // opções por valor do campo depends_on
+	Description   string              `json:"description,omitempty"`
+	Fields        []FormField         `json:"fields,omitempty"` // campos dos objetos
+}
+
+// ServiceForm descrição do formulário de criação e edição de serviços
+type ServiceForm struct {
+	Fields      []FormField `json:"fields"`
+	GeneratedAt int64       `json:"generated_at"`
+}
@@ -0,0 +1,24 @@
+package models
+
+// Operações de escrita simuladas com dry_run
+const (
+	PreviewOperationCreate  = "create"
+	PreviewOperationUpdate  = "update"
+	PreviewOperationPublish = "publish"
+)
+
+// ServiceWritePreview resultado de uma escrita em dry-run: o serviço como seria gravado (com o
+// gateway nas URLs e o search_content gerado) e as mudanças que a versão registraria. Nada é
+// persistido e nenhum embedding é gerado.
+type ServiceWritePreview struct {
+	DryRun    bool            `json:"dry_run"`
+	Operation string          `json:"operation"` // create, update ou publish
+	Service   *PrefRioService `json:"service"`
+	Changes   []FieldChange   `json:"changes"`
+
+	// Reembedding indica se a escrita geraria um novo embedding; ReembeddingFields lista os
+	// campos alterados que compõem o search_content. Sem campos alterados, o embedding é
+	// regerado quando o vetor gravado é de outro modelo ou não tem hash.
+	Reembedding       bool     `json:"reembedding"`
+	ReembeddingFields []string `json:"reembedding_fields"`
+}
@@ -0,0 +1,53 @@
+package models
+
+// Códigos dos problemas da validação em lote
+const (
+	ServiceIssueSchema       = "schema"       // regra das tags de validação do cadastro
+	ServiceIssueAvailability = "availability" // janela de disponibilidade inconsistente
+	ServiceIssueInput        = "input"        // extra_fields ou botões inválidos
+	ServiceIssueExtraFields  = "extra_fields" // extra_fields fora do schema do tema
+	ServiceIssueOrgao        = "orgao_gestor" // órgão fora do diretório de órgãos
+	ServiceIssueBairros      = "bairros"      // bairro, região ou zona desconhecido
+	ServiceIssueTaxonomy     = "taxonomy"     // tema ou subcategoria fora da taxonomia
+	ServiceIssueURL          = "url"          // link inválido ou inacessível
+	ServiceIssueDuplicate    = "duplicate"    // serviço com o mesmo nome ou ID
+)
+
+// ServiceValidationItem serviço a validar; com id, a validação considera a atualização do
+// serviço existente (não é duplicata de si mesmo)
+type ServiceValidationItem struct {
+	ID string `json:"id,omitempty"`
+	PrefRioServiceRequest
+}
+
+// ServiceValidationRequest lote de serviços a validar sem gravar
+type ServiceValidationRequest struct {
+	Services   []ServiceValidationItem `json:"services" binding:"required,min=1,max=100"`
+	CheckLinks bool                    `json:"check_links"` // verifica se os links externos respondem
+}
+
+// ServiceValidationIssue problema encontrado em um serviço
+type ServiceValidationIssue struct {
+	Code    string `json:"code"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ServiceValidationResult resultado de um serviço do lote. Erros impedem o cadastro; avisos não.
+type ServiceValidationResult struct {
+	Index       int                      `json:"index"`
+	ID          string                   `json:"id,omitempty"`
+	NomeServico string                   `json:"nome_servico"`
+	Valid       bool                     `json:"valid"`
+	Errors      []ServiceValidationIssue `json:"errors"`
+	Warnings    []ServiceValidationIssue `json:"warnings"`
+}
+
+// ServiceValidationReport relatório da validação em lote
+type ServiceValidationReport struct {
+	Total    int                       `json:"total"`
+	Valid    int                       `json:"valid"`
+	Invalid  int                       `json:"invalid"`
+	Warnings int                       `json:"warnings"`
+	Results  []ServiceValidationResult `json:"results"`
+}
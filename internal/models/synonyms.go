@@ -0,0 +1,47 @@
+package models
+
+// SynonymCandidateStatus situação de um candidato a sinônimo
+type SynonymCandidateStatus string
+
+const (
+	SynonymCandidatePending  SynonymCandidateStatus = "pending"  // Aguardando revisão
+	SynonymCandidateApproved SynonymCandidateStatus = "approved" // Sinônimo criado no Typesense
+	SynonymCandidateRejected SynonymCandidateStatus = "rejected" // Descartado; não é proposto de novo
+)
+
+// SynonymCandidate par de queries em que usuários reformularam a busca (From) e clicaram em
+// um resultado da reformulação (To), proposto como sinônimo para aprovação humana
+type SynonymCandidate struct {
+	ID         string                 `json:"id"`
+	From       string                 `json:"from"`
+	To         string                 `json:"to"`
+	Sessions   int                    `json:"sessions"`              // sessões distintas com a reformulação seguida de clique
+	Journeys   int                    `json:"journeys"`              // jornadas com a reformulação seguida de clique
+	ServiceIDs []string               `json:"service_ids,omitempty"` // serviços mais clicados após a reformulação
+	Status     SynonymCandidateStatus `json:"status"`
+	CreatedAt  int64                  `json:"created_at"`
+	UpdatedAt  int64                  `json:"updated_at"`
+	ResolvedAt int64                  `json:"resolved_at,omitempty"`
+	ResolvedBy string                 `json:"resolved_by,omitempty"`
+	SynonymID  string                 `json:"synonym_id,omitempty"` // sinônimo criado na aprovação
+}
+
+// SynonymCandidateList candidatos a sinônimo, dos com mais evidência aos com menos
+type SynonymCandidateList struct {
+	Total      int                `json:"total"`
+	Candidates []SynonymCandidate `json:"candidates"`
+}
+
+// ApproveSynonymRequest aprovação de um candidato. Por padrão o sinônimo é de mão única (buscar
+// From também encontra To); com multi_way as duas queries passam a ser equivalentes.
+type ApproveSynonymRequest struct {
+	MultiWay bool `json:"multi_way"`
+}
+
+// SynonymMiningResult resultado de uma execução da mineração de sinônimos
+type SynonymMiningResult struct {
+	Journeys int `json:"journeys"` // jornadas com reformulação seguida de clique
+	Pairs    int `json:"pairs"`    // pares de queries distintos encontrados
+	Proposed int `json:"proposed"` // novos candidatos na fila
+	Updated  int `json:"updated"`  // candidatos pendentes com evidência atualizada
+}
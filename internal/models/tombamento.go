@@ -9,6 +9,7 @@ type Tombamento struct {
 	CriadoEm        int64  `json:"criado_em" typesense:"criado_em"`
 	CriadoPor       string `json:"criado_por" validate:"required,max=20000" typesense:"criado_por"`
 	Observacoes     string `json:"observacoes,omitempty" validate:"max=20000" typesense:"observacoes,optional"`
+	Tenant          string `json:"tenant,omitempty" typesense:"tenant,optional"`
 }
 
 // TombamentoRequest representa os dados de entrada para criar/atualizar um tombamento
@@ -24,5 +25,7 @@ type TombamentoResponse struct {
 	Found       int          `json:"found"`
 	OutOf       int          `json:"out_of"`
 	Page        int          `json:"page"`
+	PerPage     int          `json:"per_page"`
 	Tombamentos []Tombamento `json:"tombamentos"`
+	PageInfo
 }
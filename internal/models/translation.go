@@ -0,0 +1,49 @@
+package models
+
+// TranslationStatus situação da tradução de um serviço em um idioma
+type TranslationStatus string
+
+const (
+	TranslationMissing  TranslationStatus = "missing"  // Sem tradução
+	TranslationMachine  TranslationStatus = "machine"  // Traduzido automaticamente, aguardando revisão
+	TranslationReviewed TranslationStatus = "reviewed" // Revisado por uma pessoa (indexado na busca)
+)
+
+// ServiceTranslationFields campos traduzidos de um serviço
+type ServiceTranslationFields struct {
+	NomeServico           string   `json:"nome_servico"`
+	Resumo                string   `json:"resumo"`
+	ResultadoSolicitacao  string   `json:"resultado_solicitacao,omitempty"`
+	DescricaoCompleta     string   `json:"descricao_completa,omitempty"`
+	InstrucoesSolicitante string   `json:"instrucoes_solicitante,omitempty"`
+	DocumentosNecessarios []string `json:"documentos_necessarios,omitempty"`
+}
+
+// ServiceTranslations traduções revisadas indexadas no serviço, por idioma (ex.: "en")
+type ServiceTranslations map[string]*ServiceTranslationFields
+
+// ServiceTranslation tradução de um serviço em um idioma
+type ServiceTranslation struct {
+	ServiceID    string                    `json:"service_id"`
+	Language     string                    `json:"language"`
+	Status       TranslationStatus         `json:"status"`
+	Outdated     bool                      `json:"outdated"` // O conteúdo original mudou depois da tradução
+	Fields       *ServiceTranslationFields `json:"fields,omitempty"`
+	SourceHash   string                    `json:"source_hash,omitempty"`
+	Model        string                    `json:"model,omitempty"`
+	TranslatedAt int64                     `json:"translated_at,omitempty"`
+	ReviewedBy   string                    `json:"reviewed_by,omitempty"`
+	ReviewedAt   int64                     `json:"reviewed_at,omitempty"`
+}
+
+// ServiceTranslationList situação das traduções de um serviço em cada idioma configurado
+type ServiceTranslationList struct {
+	ServiceID    string               `json:"service_id"`
+	Translations []ServiceTranslation `json:"translations"`
+}
+
+// TranslationReviewRequest revisão humana de uma tradução. Sem fields, a tradução automática
+// existente é aprovada como está.
+type TranslationReviewRequest struct {
+	Fields *ServiceTranslationFields `json:"fields,omitempty"`
+}
@@ -0,0 +1,43 @@
+package models
+
+// Modais de transporte público
+const (
+	ModalBRT    = "brt"
+	ModalMetro  = "metro"
+	ModalVLT    = "vlt"
+	ModalTrem   = "trem"
+	ModalBarcas = "barcas"
+)
+
+// TransportStop estação ou parada de transporte público próxima de um canal presencial
+type TransportStop struct {
+	Nome            string `json:"nome"`
+	Modal           string `json:"modal"` // brt, metro, vlt, trem ou barcas
+	DistanciaMetros int    `json:"distancia_metros"`
+	Descricao       string `json:"descricao"` // ex.: "próximo ao BRT Alvorada"
+}
+
+// PresencialLocation canal presencial geocodificado, com as estações mais próximas
+type PresencialLocation struct {
+	Canal      string          `json:"canal"` // texto original do canal presencial
+	Lat        float64         `json:"lat"`
+	Lng        float64         `json:"lng"`
+	Transporte []TransportStop `json:"transporte"`
+}
+
+// ServiceTransport transporte público próximo dos canais presenciais do serviço. Canais que não
+// puderam ser geocodificados ficam fora de Locations.
+type ServiceTransport struct {
+	Locations  []PresencialLocation `json:"locations"`
+	EnrichedAt int64                `json:"enriched_at"`
+}
+
+// TransportEnrichReport resultado do enriquecimento em lote dos canais presenciais
+type TransportEnrichReport struct {
+	Checked    int      `json:"checked"`  // serviços com canais presenciais verificados
+	Enriched   int      `json:"enriched"` // serviços regravados
+	Locations  int      `json:"locations"`
+	Unresolved []string `json:"unresolved"` // canais não geocodificados
+	Errors     []string `json:"errors"`
+	DurationMs int64    `json:"duration_ms"`
+}
@@ -46,11 +46,26 @@ type ServiceVersion struct {
 	Status                int      `json:"status" typesense:"status"`
 	SearchContent         string   `json:"search_content" validate:"max=20000" typesense:"search_content"`
 
-	// Hash do embedding para verificação (não armazenamos o embedding completo)
+	// Hash do embedding (utils.EmbeddingHash) para verificação e reaproveitamento do vetor (não
+	// armazenamos o embedding completo)
 	EmbeddingHash string `json:"embedding_hash,omitempty" validate:"max=20000" typesense:"embedding_hash,optional"`
 
 	// Campos de mudança (armazenados como JSON string no Typesense)
 	ChangedFieldsJSON string `json:"changed_fields_json,omitempty" validate:"max=20000" typesense:"changed_fields_json,optional"`
+
+	// Versão 1 gravada antes do documento do serviço, ainda não confirmada pela criação. Fica
+	// fora do histórico público e da listagem administrativa (exceto com include_pending)
+	Pending bool `json:"pending,omitempty" typesense:"pending,optional"`
+}
+
+// ServiceAsOf representa o estado publicado de um serviço em um instante, reconstruído a partir
+// do histórico de versões
+type ServiceAsOf struct {
+	AsOf          int64           `json:"as_of"`
+	VersionNumber int64           `json:"version_number"`
+	ValidFrom     int64           `json:"valid_from"`            // criação da versão
+	ValidUntil    int64           `json:"valid_until,omitempty"` // criação da versão seguinte; ausente se ainda vigente
+	Service       *PrefRioService `json:"service"`
 }
 
 // VersionDiff representa a diferença entre duas versões
@@ -74,7 +89,9 @@ type VersionHistory struct {
 	Found    int              `json:"found"`
 	OutOf    int              `json:"out_of"`
 	Page     int              `json:"page"`
+	PerPage  int              `json:"per_page"`
 	Versions []ServiceVersion `json:"versions"`
+	PageInfo
 }
 
 // VersionCompareRequest representa uma solicitação de comparação entre versões
@@ -94,3 +111,11 @@ type AuditLogFilter struct {
 	Page       int    `json:"page"`
 	PerPage    int    `json:"per_page"`
 }
+
+// VersionReconcileResult resultado da reconciliação entre os serviços e o histórico de versões
+type VersionReconcileResult struct {
+	Confirmed  int      `json:"confirmed"`  // versões pendentes de serviços criados, confirmadas
+	Discarded  int      `json:"discarded"`  // versões pendentes de serviços não criados, removidas
+	Backfilled int      `json:"backfilled"` // serviços sem histórico que receberam a versão 1
+	Errors     []string `json:"errors,omitempty"`
+}
@@ -0,0 +1,49 @@
+package models
+
+// VersionRebuildDiffType tipo de divergência entre a collection reconstruída e a atual
+type VersionRebuildDiffType string
+
+const (
+	RebuildMissingInVersions   VersionRebuildDiffType = "missing_in_versions"   // documento da collection atual sem versões: não é reconstruído
+	RebuildMissingInCollection VersionRebuildDiffType = "missing_in_collection" // serviço reconstruído ausente da collection atual
+	RebuildFieldMismatch       VersionRebuildDiffType = "field_mismatch"        // conteúdo da última versão difere do documento atual
+)
+
+// VersionRebuildOptions parâmetros da reconstrução dos serviços a partir das versões
+type VersionRebuildOptions struct {
+	Target         string  // collection de destino (criada se não existir)
+	DryRun         bool    // apenas reconstrói em memória e compara, sem gravar
+	SkipEmbeddings bool    // não regenera os embeddings
+	Concurrency    int     // embeddings gerados em paralelo
+	RateLimit      float64 // chamadas de embedding por segundo (0 = sem limite)
+	EmbedBatch     int     // conteúdos por chamada de embedding (até 100; 0 ou 1 = um por chamada)
+}
+
+// VersionRebuildDiff divergência encontrada na verificação
+type VersionRebuildDiff struct {
+	Type        VersionRebuildDiffType `json:"type"`
+	ServiceID   string                 `json:"service_id"`
+	NomeServico string                 `json:"nome_servico"`
+	Fields      []string               `json:"fields,omitempty"`
+}
+
+// VersionRebuildReport resultado da reconstrução
+type VersionRebuildReport struct {
+	StartedAt           int64                          `json:"started_at"`
+	DurationMs          int64                          `json:"duration_ms"`
+	Source              string                         `json:"source"` // collection atual usada na verificação
+	Target              string                         `json:"target"`
+	DryRun              bool                           `json:"dry_run"`
+	TotalVersions       int                            `json:"total_versions"`
+	TotalServices       int                            `json:"total_services"`
+	SkippedDeleted      int                            `json:"skipped_deleted"` // serviços cuja última versão é uma remoção
+	Rebuilt             int                            `json:"rebuilt"`         // documentos gravados no destino
+	EmbeddingsGenerated int                            `json:"embeddings_generated"`
+	EmbeddingsReused    int                            `json:"embeddings_reused"`  // vetores da collection atual com o hash registrado na versão
+	EmbeddingRetries    int                            `json:"embedding_retries"`  // novas tentativas após falhas (ex.: 429 do Gemini)
+	EmbeddingRequests   int                            `json:"embedding_requests"` // chamadas ao Gemini, incluindo novas tentativas
+	TargetDocuments     int                            `json:"target_documents"`   // contagem do destino após a gravação
+	Errors              []string                       `json:"errors,omitempty"`
+	Diffs               []VersionRebuildDiff           `json:"diffs"`
+	Summary             map[VersionRebuildDiffType]int `json:"summary"`
+}
@@ -0,0 +1,21 @@
+package models
+
+// WhatsAppCard mensagem pré-formatada de um serviço para o chatbot do WhatsApp, em texto simples
+// com a formatação do WhatsApp (*negrito*) e os limites das mensagens interativas
+type WhatsAppCard struct {
+	ServiceID    string               `json:"service_id"`
+	Title        string               `json:"title"`               // cabeçalho (até 60 caracteres)
+	Body         string               `json:"body"`                // resumo marcado com emojis (até 1024 caracteres)
+	DeepLink     string               `json:"deep_link,omitempty"` // página do serviço com os UTMs do canal whatsapp
+	QuickReplies []WhatsAppQuickReply `json:"quick_replies"`       // até 3, dos botões ativos do serviço
+	Text         string               `json:"text"`                // mensagem completa, para envio sem botões
+}
+
+// WhatsAppQuickReply resposta rápida gerada de um botão do serviço. O chatbot recebe o ID quando
+// o cidadão toca a resposta e executa a ação do botão original.
+type WhatsAppQuickReply struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"` // até 20 caracteres
+	ActionType string `json:"action_type,omitempty"`
+	URL        string `json:"url,omitempty"`
+}
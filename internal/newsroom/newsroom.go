@@ -0,0 +1,182 @@
+// Package newsroom lê as notícias e eventos publicados no CMS da redação (WordPress REST API)
+// para indexação na collection noticias.
+package newsroom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+// perPage máximo aceito pela API do WordPress
+const perPage = 100
+
+var (
+	htmlTag    = regexp.MustCompile(`(?s)<[^>]*>`)
+	blockClose = regexp.MustCompile(`(?i)</(p|div|li|h[1-6])>|<br\s*/?>`)
+	blankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// Client consulta os posts do CMS da redação
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient cria o cliente para o CMS em baseURL (ex.: https://prefeitura.rio). token, se
+// informado, é enviado como Bearer (necessário para ler posts despublicados).
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// post campos usados do post do WordPress (/wp-json/wp/v2/posts?_embed=wp:term)
+type post struct {
+	ID          int64  `json:"id"`
+	Status      string `json:"status"`
+	Link        string `json:"link"`
+	DateGMT     string `json:"date_gmt"`
+	ModifiedGMT string `json:"modified_gmt"`
+	Title       struct {
+		Rendered string `json:"rendered"`
+	} `json:"title"`
+	Excerpt struct {
+		Rendered string `json:"rendered"`
+	} `json:"excerpt"`
+	Content struct {
+		Rendered string `json:"rendered"`
+	} `json:"content"`
+	Embedded struct {
+		Terms [][]struct {
+			Name     string `json:"name"`
+			Taxonomy string `json:"taxonomy"`
+		} `json:"wp:term"`
+	} `json:"_embedded"`
+}
+
+// ListModified retorna as notícias alteradas após since, da mais antiga para a mais recente
+func (c *Client) ListModified(ctx context.Context, since time.Time) ([]models.Noticia, error) {
+	var noticias []models.Noticia
+
+	for page, totalPages := 1, 1; page <= totalPages; page++ {
+		params := url.Values{}
+		params.Set("per_page", strconv.Itoa(perPage))
+		params.Set("page", strconv.Itoa(page))
+		params.Set("orderby", "modified")
+		params.Set("order", "asc")
+		params.Set("_embed", "wp:term")
+		if !since.IsZero() {
+			params.Set("modified_after", since.UTC().Format("2006-01-02T15:04:05"))
+		}
+		if c.token != "" {
+			params.Set("status", "publish,draft,private,trash")
+		}
+
+		posts, pages, err := c.fetchPosts(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		totalPages = pages
+
+		for _, p := range posts {
+			noticias = append(noticias, p.toNoticia())
+		}
+	}
+
+	return noticias, nil
+}
+
+func (c *Client) fetchPosts(ctx context.Context, params url.Values) ([]post, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/wp-json/wp/v2/posts?"+params.Encode(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao acessar o CMS da redação: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, 0, fmt.Errorf("CMS da redação retornou status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var posts []post
+	if err := json.NewDecoder(resp.Body).Decode(&posts); err != nil {
+		return nil, 0, fmt.Errorf("resposta inválida do CMS da redação: %w", err)
+	}
+
+	totalPages, _ := strconv.Atoi(resp.Header.Get("X-WP-TotalPages"))
+	return posts, totalPages, nil
+}
+
+func (p post) toNoticia() models.Noticia {
+	var tags []string
+	for _, terms := range p.Embedded.Terms {
+		for _, term := range terms {
+			if term.Taxonomy == "post_tag" || term.Taxonomy == "category" {
+				tags = append(tags, html.UnescapeString(term.Name))
+			}
+		}
+	}
+
+	status := int32(0)
+	if p.Status == "publish" {
+		status = 1
+	}
+
+	sourceID := strconv.FormatInt(p.ID, 10)
+	return models.Noticia{
+		ID:          "wp-" + sourceID,
+		SourceID:    sourceID,
+		Title:       HTMLToText(p.Title.Rendered),
+		Summary:     HTMLToText(p.Excerpt.Rendered),
+		Body:        HTMLToText(p.Content.Rendered),
+		URL:         p.Link,
+		Tags:        tags,
+		PublishedAt: parseGMT(p.DateGMT),
+		UpdatedAt:   parseGMT(p.ModifiedGMT),
+		Status:      status,
+	}
+}
+
+// HTMLToText converte o HTML renderizado do post em texto, mantendo quebras de parágrafo
+func HTMLToText(s string) string {
+	s = blockClose.ReplaceAllString(s, "\n")
+	s = htmlTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// parseGMT converte as datas do WordPress (sem fuso, em UTC) para unix
+func parseGMT(value string) int64 {
+	t, err := time.Parse("2006-01-02T15:04:05", value)
+	if err != nil {
+		return 0
+	}
+	return t.Unix()
+}
@@ -0,0 +1,56 @@
+package newsroom
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestListModifiedPaginatesAndMapsPosts(t *testing.T) {
+	pages := map[string]string{
+		"1": `[{"id": 10, "status": "publish", "link": "https://prefeitura.rio/noticia-10", "date_gmt": "2026-03-01T12:00:00", "modified_gmt": "2026-03-02T08:00:00",
+			"title": {"rendered": "Vacina&ccedil;&atilde;o no fim de semana"}, "excerpt": {"rendered": "<p>Postos abertos</p>"},
+			"content": {"rendered": "<p>Primeiro par&aacute;grafo.</p><p>Segundo <strong>par&aacute;grafo</strong>.</p>"},
+			"_embedded": {"wp:term": [[{"name": "Saúde", "taxonomy": "category"}], [{"name": "vacina", "taxonomy": "post_tag"}]]}}]`,
+		"2": `[{"id": 11, "status": "draft", "title": {"rendered": "Rascunho"}, "content": {"rendered": ""}, "modified_gmt": "2026-03-03T08:00:00"}]`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/wp-json/wp/v2/posts" {
+			t.Errorf("path inesperado: %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("modified_after"); got != "2026-03-01T00:00:00" {
+			t.Errorf("modified_after = %q", got)
+		}
+		w.Header().Set("X-WP-TotalPages", "2")
+		w.Write([]byte(pages[r.URL.Query().Get("page")]))
+	}))
+	defer server.Close()
+
+	noticias, err := NewClient(server.URL, "").ListModified(context.Background(), time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if len(noticias) != 2 {
+		t.Fatalf("notícias = %d, esperado 2", len(noticias))
+	}
+
+	n := noticias[0]
+	if n.ID != "wp-10" || n.Title != "Vacinação no fim de semana" || n.Status != 1 {
+		t.Errorf("notícia mapeada incorretamente: %+v", n)
+	}
+	if n.Body != "Primeiro parágrafo.\nSegundo parágrafo." {
+		t.Errorf("body = %q", n.Body)
+	}
+	if len(n.Tags) != 2 || n.Tags[0] != "Saúde" || n.Tags[1] != "vacina" {
+		t.Errorf("tags = %v", n.Tags)
+	}
+	if n.PublishedAt != time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC).Unix() {
+		t.Errorf("published_at = %d", n.PublishedAt)
+	}
+	if noticias[1].Status != 0 {
+		t.Errorf("rascunho deveria ter status 0")
+	}
+}
@@ -0,0 +1,412 @@
+// Package pdf gera documentos PDF marcados (tagged PDF) simples, com títulos, parágrafos e
+// listas nas fontes padrão Helvetica. A árvore de estrutura, o idioma e o título do documento
+// permitem a leitura por leitores de tela; rodapés e marcadores de lista são artefatos.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// Página A4 em pontos
+const (
+	pageWidth    = 595.28
+	pageHeight   = 841.89
+	margin       = 56.0
+	footerY      = 32.0
+	footerSize   = 8.0
+	listIndent   = 16.0
+	lineSpacing  = 1.35
+	contentWidth = pageWidth - 2*margin
+)
+
+type blockKind int
+
+const (
+	blockHeading1 blockKind = iota
+	blockHeading2
+	blockHeading3
+	blockParagraph
+	blockList
+)
+
+// style fonte, tamanho e espaçamentos de um tipo de bloco
+type style struct {
+	tag         string
+	bold        bool
+	size        float64
+	spaceBefore float64
+	spaceAfter  float64
+}
+
+var styles = map[blockKind]style{
+	blockHeading1:  {tag: "H1", bold: true, size: 18, spaceAfter: 8},
+	blockHeading2:  {tag: "H2", bold: true, size: 13, spaceBefore: 12, spaceAfter: 4},
+	blockHeading3:  {tag: "H3", bold: true, size: 11, spaceBefore: 6, spaceAfter: 2},
+	blockParagraph: {tag: "P", size: 10.5, spaceAfter: 6},
+	blockList:      {tag: "L", size: 10.5, spaceAfter: 6},
+}
+
+type block struct {
+	kind  blockKind
+	text  string
+	items []string
+}
+
+// Document documento em construção. Os blocos são dispostos em páginas A4 apenas em Render.
+type Document struct {
+	title  string
+	lang   string
+	footer string
+	blocks []block
+}
+
+// New cria um documento com o título (metadados e leitores de tela) e o idioma (ex.: pt-BR)
+func New(title, lang string) *Document {
+	return &Document{title: title, lang: lang}
+}
+
+// SetFooter define o texto impresso no rodapé de todas as páginas, ao lado da numeração
+func (d *Document) SetFooter(text string) {
+	d.footer = text
+}
+
+// Heading adiciona um título de nível 1 a 3
+func (d *Document) Heading(level int, text string) {
+	kind := blockHeading3
+	switch level {
+	case 1:
+		kind = blockHeading1
+	case 2:
+		kind = blockHeading2
+	}
+	if text = strings.TrimSpace(text); text != "" {
+		d.blocks = append(d.blocks, block{kind: kind, text: text})
+	}
+}
+
+// Paragraph adiciona um parágrafo; quebras de linha viram espaços
+func (d *Document) Paragraph(text string) {
+	if text = strings.Join(strings.Fields(text), " "); text != "" {
+		d.blocks = append(d.blocks, block{kind: blockParagraph, text: text})
+	}
+}
+
+// List adiciona uma lista com marcadores; itens vazios são ignorados
+func (d *Document) List(items []string) {
+	var kept []string
+	for _, item := range items {
+		if item = strings.Join(strings.Fields(item), " "); item != "" {
+			kept = append(kept, item)
+		}
+	}
+	if len(kept) > 0 {
+		d.blocks = append(d.blocks, block{kind: blockList, items: kept})
+	}
+}
+
+// element nó da árvore de estrutura: um contêiner (Document, L, LI) ou um bloco de texto com o
+// conteúdo marcado em uma ou mais páginas
+type element struct {
+	tag      string
+	parent   *element
+	children []*element
+	marks    []mark
+	obj      int
+}
+
+// mark sequência de conteúdo marcado (MCID) de um elemento em uma página
+type mark struct {
+	page int
+	mcid int
+}
+
+type page struct {
+	content bytes.Buffer
+	owners  []*element // elemento de cada MCID da página
+	obj     int
+}
+
+// layout dispõe os blocos nas páginas, acompanhando a posição vertical
+type layout struct {
+	pages []*page
+	y     float64
+}
+
+func (l *layout) current() *page {
+	return l.pages[len(l.pages)-1]
+}
+
+func (l *layout) newPage() {
+	l.pages = append(l.pages, &page{})
+	l.y = pageHeight - margin
+}
+
+// ensure abre uma nova página quando a altura não cabe na atual
+func (l *layout) ensure(height float64) bool {
+	if l.y-height < margin {
+		l.newPage()
+		return true
+	}
+	return false
+}
+
+// beginMark abre o conteúdo marcado do elemento na página atual
+func (l *layout) beginMark(owner *element) {
+	p := l.current()
+	mcid := len(p.owners)
+	p.owners = append(p.owners, owner)
+	owner.marks = append(owner.marks, mark{page: len(l.pages) - 1, mcid: mcid})
+	fmt.Fprintf(&p.content, "/%s <</MCID %d>> BDC\n", owner.tag, mcid)
+}
+
+func (l *layout) endMark() {
+	l.current().content.WriteString("EMC\n")
+}
+
+// writeLines escreve as linhas do elemento a partir de x, abrindo novas páginas conforme
+// necessário. Cada página recebe uma sequência de conteúdo marcado do elemento.
+func (l *layout) writeLines(owner *element, lines [][]byte, x float64, st style) {
+	leading := st.size * lineSpacing
+	open := false
+	for _, line := range lines {
+		l.ensure(leading)
+		if !open {
+			l.beginMark(owner)
+			open = true
+		}
+		l.y -= leading
+		writeText(&l.current().content, line, x, l.y+(leading-st.size)/2, st)
+		if l.y-leading < margin {
+			l.endMark()
+			open = false
+		}
+	}
+	if open {
+		l.endMark()
+	}
+}
+
+// Render dispõe o documento em páginas A4 e devolve o PDF
+func (d *Document) Render() ([]byte, error) {
+	root := &element{tag: "Document"}
+	l := &layout{}
+	l.newPage()
+
+	for i, b := range d.blocks {
+		st := styles[b.kind]
+		if i > 0 {
+			l.y -= st.spaceBefore
+		}
+
+		if b.kind == blockList {
+			list := &element{tag: "L", parent: root}
+			root.children = append(root.children, list)
+			for _, item := range b.items {
+				li := &element{tag: "LI", parent: list}
+				body := &element{tag: "LBody", parent: li}
+				li.children = []*element{body}
+				list.children = append(list.children, li)
+
+				lines := wrap(encode(item), st, contentWidth-listIndent)
+				// O marcador acompanha a primeira linha do item
+				l.ensure(st.size * lineSpacing)
+				bulletY := l.y - st.size*lineSpacing + (st.size*lineSpacing-st.size)/2
+				writeArtifact(&l.current().content, []byte{0x95}, margin+4, bulletY, st)
+				l.writeLines(body, lines, margin+listIndent, st)
+				l.y -= 2
+			}
+		} else {
+			elem := &element{tag: st.tag, parent: root}
+			root.children = append(root.children, elem)
+			// O título não fica sozinho no fim da página
+			if b.kind != blockParagraph {
+				l.ensure(st.size*lineSpacing + styles[blockParagraph].size*lineSpacing*2)
+			}
+			l.writeLines(elem, wrap(encode(b.text), st, contentWidth), margin, st)
+		}
+		l.y -= st.spaceAfter
+	}
+
+	d.writeFooters(l.pages)
+	return d.serialize(root, l.pages)
+}
+
+// writeFooters numera as páginas no rodapé, como artefatos
+func (d *Document) writeFooters(pages []*page) {
+	st := style{size: footerSize}
+	footer := encode(d.footer)
+	for i, p := range pages {
+		if len(footer) > 0 {
+			writeArtifact(&p.content, footer, margin, footerY, st)
+		}
+		number := encode(fmt.Sprintf("Página %d de %d", i+1, len(pages)))
+		writeArtifact(&p.content, number, pageWidth-margin-textWidth(number, st), footerY, st)
+	}
+}
+
+func writeText(buf *bytes.Buffer, text []byte, x, y float64, st style) {
+	font := "F1"
+	if st.bold {
+		font = "F2"
+	}
+	fmt.Fprintf(buf, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, st.size, x, y, escape(text))
+}
+
+func writeArtifact(buf *bytes.Buffer, text []byte, x, y float64, st style) {
+	buf.WriteString("/Artifact BMC\n")
+	writeText(buf, text, x, y, st)
+	buf.WriteString("EMC\n")
+}
+
+// serialize grava os objetos do PDF: catálogo, páginas, fontes, árvore de estrutura e info
+func (d *Document) serialize(root *element, pages []*page) ([]byte, error) {
+	const (
+		catalogObj = iota + 1
+		pagesObj
+		regularFontObj
+		boldFontObj
+		structTreeObj
+		infoObj
+		firstFreeObj
+	)
+
+	next := firstFreeObj
+	for _, p := range pages {
+		p.obj = next
+		next += 2 // página e conteúdo
+	}
+	var elements []*element
+	var number func(e *element)
+	number = func(e *element) {
+		e.obj = next
+		next++
+		elements = append(elements, e)
+		for _, child := range e.children {
+			number(child)
+		}
+	}
+	number(root)
+
+	w := &writer{offsets: make([]int, next)}
+	w.buf.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+
+	w.object(catalogObj, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R /StructTreeRoot %d 0 R /MarkInfo << /Marked true >> /Lang %s /ViewerPreferences << /DisplayDocTitle true >> >>",
+		pagesObj, structTreeObj, "("+string(escape([]byte(d.lang)))+")"))
+
+	kids := make([]string, len(pages))
+	for i, p := range pages {
+		kids[i] = ref(p.obj)
+	}
+	w.object(pagesObj, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	w.object(regularFontObj, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	w.object(boldFontObj, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	// A parent tree liga cada MCID da página ao elemento de estrutura que o contém
+	var nums strings.Builder
+	for i, p := range pages {
+		owners := make([]string, len(p.owners))
+		for j, owner := range p.owners {
+			owners[j] = ref(owner.obj)
+		}
+		fmt.Fprintf(&nums, "%d [%s] ", i, strings.Join(owners, " "))
+	}
+	w.object(structTreeObj, fmt.Sprintf("<< /Type /StructTreeRoot /K [%s] /ParentTree << /Nums [%s] >> /ParentTreeNextKey %d >>",
+		ref(root.obj), strings.TrimSpace(nums.String()), len(pages)))
+	w.object(infoObj, fmt.Sprintf("<< /Title %s /Producer (app-busca-search) >>", textString(d.title)))
+
+	for i, p := range pages {
+		w.object(p.obj, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> /Contents %d 0 R /StructParents %d /Tabs /S >>",
+			pagesObj, pageWidth, pageHeight, regularFontObj, boldFontObj, p.obj+1, i))
+		if err := w.stream(p.obj+1, p.content.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, e := range elements {
+		var kids []string
+		for _, child := range e.children {
+			kids = append(kids, ref(child.obj))
+		}
+		for _, m := range e.marks {
+			kids = append(kids, fmt.Sprintf("<< /Type /MCR /Pg %s /MCID %d >>", ref(pages[m.page].obj), m.mcid))
+		}
+		parent := ref(structTreeObj)
+		if e.parent != nil {
+			parent = ref(e.parent.obj)
+		}
+		w.object(e.obj, fmt.Sprintf("<< /Type /StructElem /S /%s /P %s /K [%s] >>", e.tag, parent, strings.Join(kids, " ")))
+	}
+
+	w.trailer(catalogObj, infoObj)
+	return w.buf.Bytes(), nil
+}
+
+// writer acompanha a posição de cada objeto para a tabela xref
+type writer struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+func (w *writer) object(obj int, body string) {
+	w.offsets[obj] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", obj, body)
+}
+
+func (w *writer) stream(obj int, data []byte) error {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(data); err != nil {
+		return fmt.Errorf("erro ao comprimir página: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("erro ao comprimir página: %w", err)
+	}
+
+	w.offsets[obj] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", obj, compressed.Len())
+	w.buf.Write(compressed.Bytes())
+	w.buf.WriteString("\nendstream\nendobj\n")
+	return nil
+}
+
+func (w *writer) trailer(catalogObj, infoObj int) {
+	start := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets))
+	for _, offset := range w.offsets[1:] {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(w.offsets), catalogObj, infoObj, start)
+}
+
+func ref(obj int) string {
+	return fmt.Sprintf("%d 0 R", obj)
+}
+
+// textString codifica um texto de metadados em UTF-16BE, aceito para qualquer idioma
+func textString(text string) string {
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, unit := range utf16.Encode([]rune(text)) {
+		fmt.Fprintf(&b, "%04X", unit)
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+// escape protege os delimitadores de uma string literal do PDF
+func escape(text []byte) []byte {
+	var out []byte
+	for _, c := range text {
+		switch c {
+		case '(', ')', '\\':
+			out = append(out, '\\', c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,110 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRenderTaggedDocument(t *testing.T) {
+	doc := New("Matrícula escolar", "pt-BR")
+	doc.SetFooter("Versão 3")
+	doc.Heading(1, "Matrícula escolar")
+	for i := 0; i < 60; i++ {
+		doc.Paragraph(strings.Repeat("Inscrição na rede municipal (ensino fundamental). ", 6))
+	}
+	doc.Heading(2, "Documentos necessários")
+	doc.List([]string{"CPF do responsável", "", "Comprovante de residência"})
+
+	data, err := doc.Render()
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-1.7")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatal("cabeçalho ou trailer ausente")
+	}
+
+	// Cada entrada da xref aponta para o início do objeto correspondente
+	start, err := strconv.Atoi(string(regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(data)[1]))
+	if err != nil || !bytes.HasPrefix(data[start:], []byte("xref")) {
+		t.Fatalf("startxref inválido: %v", err)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(data[start:], -1)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		if prefix := fmt.Sprintf("%d 0 obj", i+1); !bytes.HasPrefix(data[offset:], []byte(prefix)) {
+			t.Fatalf("xref do objeto %d aponta para %q", i+1, data[offset:offset+10])
+		}
+	}
+
+	for _, expected := range []string{"/MarkInfo << /Marked true >>", "/StructTreeRoot", "/DisplayDocTitle true", "/S /H1", "/S /H2", "/S /L", "/S /LBody", "/Tabs /S"} {
+		if !bytes.Contains(data, []byte(expected)) {
+			t.Errorf("esperado %q no documento", expected)
+		}
+	}
+	if pages := bytes.Count(data, []byte("/Type /Page ")); pages < 2 {
+		t.Errorf("esperadas ao menos 2 páginas, obtidas %d", pages)
+	}
+	if items := bytes.Count(data, []byte("/S /LI ")); items != 2 {
+		t.Errorf("esperados 2 itens de lista, obtidos %d", items)
+	}
+
+	// O texto é gravado em WinAnsi e o rodapé é artefato
+	content := decompressStreams(t, data)
+	if !bytes.Contains(content, []byte("(Matr\xedcula escolar) Tj")) {
+		t.Error("título não codificado em WinAnsi")
+	}
+	if !bytes.Contains(content, []byte("/Artifact BMC\nBT /F1 8.00 Tf 56.00 32.00 Td (Vers\xe3o 3) Tj ET\nEMC")) {
+		t.Error("rodapé ausente ou fora de artefato")
+	}
+	if bytes.Count(content, []byte("BDC")) != bytes.Count(content, []byte("EMC"))-bytes.Count(content, []byte("/Artifact BMC")) {
+		t.Error("conteúdo marcado desbalanceado")
+	}
+}
+
+func TestWrap(t *testing.T) {
+	st := style{size: 10}
+	lines := wrap(encode("um texto com algumas palavras para quebrar"), st, 60)
+	for _, line := range lines {
+		if textWidth(line, st) > 60 {
+			t.Errorf("linha %q excede a largura", line)
+		}
+	}
+	if joined := string(bytes.Join(lines, []byte(" "))); joined != "um texto com algumas palavras para quebrar" {
+		t.Errorf("texto alterado pela quebra: %q", joined)
+	}
+
+	// Palavras maiores que a linha são quebradas
+	long := wrap(encode("https://carioca.rio/servicos/"+strings.Repeat("a", 80)), st, 100)
+	if len(long) < 2 {
+		t.Errorf("esperada quebra da palavra longa, obtidas %d linhas", len(long))
+	}
+}
+
+func TestEncode(t *testing.T) {
+	if got := encode("Ação – “ok” 😀 ẞ"); !bytes.Equal(got, []byte("A\xe7\xe3o \x96 \x93ok\x94  ?")) {
+		t.Errorf("codificação inesperada: %q", got)
+	}
+}
+
+func decompressStreams(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var content []byte
+	for _, match := range regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindAllSubmatch(data, -1) {
+		reader, err := zlib.NewReader(bytes.NewReader(match[1]))
+		if err != nil {
+			t.Fatalf("stream inválido: %v", err)
+		}
+		decoded, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("stream inválido: %v", err)
+		}
+		content = append(content, decoded...)
+	}
+	return content
+}
@@ -0,0 +1,157 @@
+package pdf
+
+import (
+	"unicode"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Larguras (em milésimos do tamanho da fonte) dos caracteres ASCII 32-126 da Helvetica e da
+// Helvetica-Bold, conforme as métricas AFM das fontes padrão
+var (
+	regularASCII = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	boldASCII = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// wideChars larguras dos caracteres WinAnsi sem letra base ASCII; as das duas fontes diferem
+// pouco, e a maior é usada para que a linha nunca ultrapasse a margem
+var wideChars = map[rune]int{
+	'Æ': 1000, 'æ': 889, 'Ø': 778, 'ø': 611, 'ß': 611, '×': 584, '÷': 584, '\u00a0': 278,
+	'•': 350, '–': 556, '—': 1000, '…': 1000, '‘': 278, '’': 278, '“': 500, '”': 500,
+	'º': 365, 'ª': 370, '°': 400, '€': 556, '§': 556, '©': 737, '®': 737,
+}
+
+var (
+	regularWidths = buildWidths(regularASCII)
+	boldWidths    = buildWidths(boldASCII)
+)
+
+// buildWidths monta a largura de cada byte WinAnsi: as letras acentuadas têm a largura da
+// letra base
+func buildWidths(ascii [95]int) [256]int {
+	var widths [256]int
+	for b := 0; b < 256; b++ {
+		widths[b] = 556
+		r := charmap.Windows1252.DecodeByte(byte(b))
+		if width, ok := wideChars[r]; ok {
+			widths[b] = width
+			continue
+		}
+		base := []rune(norm.NFD.String(string(r)))[0]
+		if base >= 32 && base <= 126 {
+			widths[b] = ascii[base-32]
+		}
+	}
+	return widths
+}
+
+// encode converte o texto para WinAnsi, a codificação das fontes padrão. Caracteres sem
+// representação perdem o acento ou viram '?'; controles e emojis são descartados.
+func encode(text string) []byte {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		if r < 32 {
+			if r == '\t' {
+				out = append(out, ' ')
+			}
+			continue
+		}
+		if b, ok := charmap.Windows1252.EncodeRune(r); ok {
+			out = append(out, b)
+			continue
+		}
+		if base := []rune(norm.NFD.String(string(r)))[0]; base != r {
+			if b, ok := charmap.Windows1252.EncodeRune(base); ok {
+				out = append(out, b)
+				continue
+			}
+		}
+		if unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsPunct(r) {
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+// textWidth largura do texto codificado, em pontos
+func textWidth(text []byte, st style) float64 {
+	widths := &regularWidths
+	if st.bold {
+		widths = &boldWidths
+	}
+	total := 0
+	for _, b := range text {
+		total += widths[b]
+	}
+	return float64(total) * st.size / 1000
+}
+
+// wrap quebra o texto em linhas de até width pontos, nos espaços. Palavras maiores que a linha
+// (ex.: URLs longas) são quebradas onde for preciso.
+func wrap(text []byte, st style, width float64) [][]byte {
+	var lines [][]byte
+	var line []byte
+	for _, word := range splitWords(text) {
+		candidate := word
+		if len(line) > 0 {
+			candidate = append(append(append([]byte{}, line...), ' '), word...)
+		}
+		if textWidth(candidate, st) <= width {
+			line = candidate
+			continue
+		}
+		if len(line) > 0 {
+			lines = append(lines, line)
+			line = nil
+		}
+		for textWidth(word, st) > width {
+			cut := 1
+			for cut < len(word) && textWidth(word[:cut+1], st) <= width {
+				cut++
+			}
+			lines = append(lines, word[:cut])
+			word = word[cut:]
+		}
+		line = word
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func splitWords(text []byte) [][]byte {
+	var words [][]byte
+	start := -1
+	for i, b := range text {
+		if b == ' ' {
+			if start >= 0 {
+				words = append(words, text[start:i])
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, text[start:])
+	}
+	return words
+}
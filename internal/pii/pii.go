@@ -0,0 +1,131 @@
+// Package pii remove dados pessoais (CPF, telefones, endereços, e-mails) de textos antes que
+// sejam gravados em logs, traces ou registros de analytics.
+package pii
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Pattern regra de remoção: trechos que casam com Pattern são trocados por Replacement
+type Pattern struct {
+	Name        string `json:"name"`
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
+}
+
+type rule struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+// Scrubber aplica as regras em ordem (as mais específicas primeiro)
+type Scrubber struct {
+	rules []rule
+}
+
+// DefaultPatterns regras padrão. A ordem importa: CNPJ antes de CPF e CPF antes de telefone,
+// para que números longos não sejam reconhecidos parcialmente.
+func DefaultPatterns() []Pattern {
+	return []Pattern{
+		{Name: "email", Pattern: `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`, Replacement: "[EMAIL]"},
+		{Name: "cnpj", Pattern: `\b\d{2}\.?\d{3}\.?\d{3}/?\d{4}-?\d{2}\b`, Replacement: "[CNPJ]"},
+		{Name: "cpf", Pattern: `\b\d{3}\.?\d{3}\.?\d{3}[-.]?\d{2}\b`, Replacement: "[CPF]"},
+		{Name: "telefone", Pattern: `(?:\+?55[\s-]?)?(?:\(\d{2}\)\s?|\b\d{2}[\s-])?\b9?\d{4}[\s-]?\d{4}\b`, Replacement: "[TELEFONE]"},
+		{Name: "cep", Pattern: `\b\d{5}-\d{3}\b`, Replacement: "[CEP]"},
+		{Name: "endereco", Pattern: `(?i)\b(?:rua|r\.|avenida|av\.?|travessa|tv\.|estrada|estr\.|pra[çc]a|alameda|al\.|rodovia|largo|beco|ladeira|servid[ãa]o)\s+[^\d,;\n]{2,60}?,?\s*(?:n[º°o.]?\s*)?\d{1,5}\b`, Replacement: "[ENDERECO]"},
+	}
+}
+
+// NewScrubber compila as regras informadas
+func NewScrubber(patterns []Pattern) (*Scrubber, error) {
+	s := &Scrubber{rules: make([]rule, 0, len(patterns))}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("padrão de PII %q inválido: %w", p.Name, err)
+		}
+		replacement := p.Replacement
+		if replacement == "" {
+			replacement = "[" + strings.ToUpper(p.Name) + "]"
+		}
+		s.rules = append(s.rules, rule{re: re, replacement: replacement})
+	}
+	return s, nil
+}
+
+// Scrub retorna o texto com os dados pessoais substituídos
+func (s *Scrubber) Scrub(text string) string {
+	if text == "" {
+		return text
+	}
+	for _, r := range s.rules {
+		text = r.re.ReplaceAllLiteralString(text, r.replacement)
+	}
+	return text
+}
+
+var current atomic.Pointer[Scrubber]
+
+func init() {
+	scrubber, err := NewScrubber(DefaultPatterns())
+	if err != nil {
+		panic(err)
+	}
+	current.Store(scrubber)
+}
+
+// Configure substitui o scrubber global pelas regras padrão acrescidas das regras em JSON
+// (PII_PATTERNS), aplicadas depois das padrão
+func Configure(extraJSON string) error {
+	patterns := DefaultPatterns()
+	if strings.TrimSpace(extraJSON) != "" {
+		var extra []Pattern
+		if err := json.Unmarshal([]byte(extraJSON), &extra); err != nil {
+			return fmt.Errorf("PII_PATTERNS inválido: %w", err)
+		}
+		patterns = append(patterns, extra...)
+	}
+
+	scrubber, err := NewScrubber(patterns)
+	if err != nil {
+		return err
+	}
+	current.Store(scrubber)
+	return nil
+}
+
+// Scrub remove dados pessoais do texto com o scrubber global
+func Scrub(text string) string {
+	return current.Load().Scrub(text)
+}
+
+// ScrubURL retorna o caminho da URL com os valores da query string sem dados pessoais
+func ScrubURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	if u.RawQuery == "" {
+		return u.Path
+	}
+
+	values := u.Query()
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		for _, value := range values[key] {
+			parts = append(parts, url.QueryEscape(key)+"="+url.QueryEscape(Scrub(value)))
+		}
+	}
+	return u.Path + "?" + strings.Join(parts, "&")
+}
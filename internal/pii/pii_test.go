@@ -0,0 +1,74 @@
+package pii
+
+import (
+	"bufio"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// rawPII detecta resíduos que não podem chegar ao armazenamento: sequências longas de
+// dígitos (CPF, telefone) e e-mails
+var rawPII = regexp.MustCompile(`\d{3}[.\s-]?\d{3}[.\s-]?\d{3,}|\d{4}-\d{4}|@[A-Za-z0-9.-]+\.`)
+
+func TestScrubCorpus(t *testing.T) {
+	file, err := os.Open("testdata/corpus.tsv")
+	if err != nil {
+		t.Fatalf("erro ao abrir corpus: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	cases := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		input, expected, ok := strings.Cut(line, "\t")
+		if !ok {
+			t.Fatalf("linha inválida no corpus: %q", line)
+		}
+		cases++
+
+		got := Scrub(input)
+		if got != expected {
+			t.Errorf("Scrub(%q) = %q, want %q", input, got, expected)
+		}
+		if rawPII.MatchString(got) {
+			t.Errorf("Scrub(%q) deixou PII: %q", input, got)
+		}
+	}
+	if cases == 0 {
+		t.Fatal("corpus vazio")
+	}
+}
+
+func TestScrubURL(t *testing.T) {
+	u, _ := url.Parse("/api/v1/search?type=keyword&q=cpf+123.456.789-09")
+
+	got := ScrubURL(u)
+	if strings.Contains(got, "789") {
+		t.Errorf("ScrubURL deixou PII: %q", got)
+	}
+	if !strings.HasPrefix(got, "/api/v1/search?q=cpf+%5BCPF%5D") || !strings.Contains(got, "type=keyword") {
+		t.Errorf("ScrubURL = %q", got)
+	}
+}
+
+func TestConfigureAddsPatterns(t *testing.T) {
+	defer Configure("")
+
+	if err := Configure(`[{"name": "matricula", "pattern": "\\bmatr[ií]cula\\s+\\d+"}]`); err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if got := Scrub("matrícula 123 e cpf 12345678909"); got != "[MATRICULA] e cpf [CPF]" {
+		t.Errorf("Scrub = %q", got)
+	}
+
+	if err := Configure(`[{"name": "x", "pattern": "("}]`); err == nil {
+		t.Error("esperado erro para padrão inválido")
+	}
+}
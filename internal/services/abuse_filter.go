@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+const (
+	AbuseFilterCollection = "abuse_filter_words"
+	abuseWordListID       = "default"
+)
+
+// ErrEmptyAbuseWordList indica lista de termos sem nenhum termo válido
+var ErrEmptyAbuseWordList = errors.New("a lista deve ter ao menos um termo")
+
+type abusiveQueryKey struct{}
+
+// WithAbusiveQuery marca o contexto da busca como ofensiva. Buscas marcadas não passam pelas
+// etapas com LLM/embeddings e são atendidas apenas pela busca textual.
+func WithAbusiveQuery(ctx context.Context) context.Context {
+	return context.WithValue(ctx, abusiveQueryKey{}, true)
+}
+
+// IsAbusiveQuery indica se a busca foi marcada como ofensiva
+func IsAbusiveQuery(ctx context.Context) bool {
+	abusive, _ := ctx.Value(abusiveQueryKey{}).(bool)
+	return abusive
+}
+
+// AbuseFilter detecta palavrões e ofensas nas queries por uma lista de termos gerida pelos
+// administradores e conta os incidentes por cliente, bloqueando temporariamente quem excede
+// o limite dentro da janela de contagem
+type AbuseFilter struct {
+	client    *typesense.Client
+	threshold int
+	window    time.Duration
+	blockFor  time.Duration
+
+	mu        sync.RWMutex
+	list      models.AbuseWordList
+	words     []string // termos normalizados
+	incidents map[string][]time.Time
+	blocked   map[string]time.Time
+	total     int64
+}
+
+// NewAbuseFilter cria o filtro com a lista padrão. threshold 0 desabilita o bloqueio.
+func NewAbuseFilter(client *typesense.Client, threshold int, window, blockFor time.Duration) *AbuseFilter {
+	af := &AbuseFilter{
+		client:    client,
+		threshold: threshold,
+		window:    window,
+		blockFor:  blockFor,
+		incidents: make(map[string][]time.Time),
+		blocked:   make(map[string]time.Time),
+	}
+	af.setList(models.AbuseWordList{Words: DefaultAbuseWords(), Default: true})
+	return af
+}
+
+// Detect indica se a query contém algum termo da lista
+func (af *AbuseFilter) Detect(query string) bool {
+	if af == nil {
+		return false
+	}
+
+	normalized := normalizeSensitiveText(query)
+	af.mu.RLock()
+	defer af.mu.RUnlock()
+	for _, word := range af.words {
+		if containsWord(normalized, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordIncident contabiliza uma busca ofensiva do cliente e retorna true quando o cliente
+// atingiu o limite e passou a ser bloqueado
+func (af *AbuseFilter) RecordIncident(clientID string) bool {
+	now := time.Now()
+
+	af.mu.Lock()
+	defer af.mu.Unlock()
+
+	af.total++
+	recent := pruneIncidents(af.incidents[clientID], now.Add(-af.window))
+	recent = append(recent, now)
+	af.incidents[clientID] = recent
+
+	if af.threshold > 0 && len(recent) >= af.threshold {
+		af.blocked[clientID] = now.Add(af.blockFor)
+		delete(af.incidents, clientID)
+		log.Printf("[Abuso] Cliente %s bloqueado por %v após %d buscas ofensivas", clientID, af.blockFor, len(recent))
+		return true
+	}
+	return false
+}
+
+// BlockedFor retorna quanto tempo falta para o bloqueio do cliente expirar (0 se não bloqueado)
+func (af *AbuseFilter) BlockedFor(clientID string) time.Duration {
+	if af == nil {
+		return 0
+	}
+
+	af.mu.RLock()
+	until, ok := af.blocked[clientID]
+	af.mu.RUnlock()
+	if !ok {
+		return 0
+	}
+
+	remaining := time.Until(until)
+	if remaining <= 0 {
+		af.mu.Lock()
+		delete(af.blocked, clientID)
+		af.mu.Unlock()
+		return 0
+	}
+	return remaining
+}
+
+// Report retorna os incidentes por cliente na janela atual e os bloqueios vigentes
+func (af *AbuseFilter) Report() *models.AbuseReport {
+	now := time.Now()
+
+	af.mu.Lock()
+	defer af.mu.Unlock()
+
+	byClient := make(map[string]*models.AbuseClientIncidents)
+	for clientID, times := range af.incidents {
+		recent := pruneIncidents(times, now.Add(-af.window))
+		if len(recent) == 0 {
+			delete(af.incidents, clientID)
+			continue
+		}
+		af.incidents[clientID] = recent
+		byClient[clientID] = &models.AbuseClientIncidents{Client: clientID, Incidents: len(recent)}
+	}
+	for clientID, until := range af.blocked {
+		if !until.After(now) {
+			delete(af.blocked, clientID)
+			continue
+		}
+		entry, ok := byClient[clientID]
+		if !ok {
+			entry = &models.AbuseClientIncidents{Client: clientID}
+			byClient[clientID] = entry
+		}
+		entry.BlockedUntil = until.Unix()
+	}
+
+	report := &models.AbuseReport{
+		TotalIncidents: af.total,
+		Threshold:      af.threshold,
+		WindowMinutes:  int(af.window.Minutes()),
+		BlockMinutes:   int(af.blockFor.Minutes()),
+		Clients:        make([]models.AbuseClientIncidents, 0, len(byClient)),
+	}
+	for _, entry := range byClient {
+		report.Clients = append(report.Clients, *entry)
+	}
+	sort.Slice(report.Clients, func(i, j int) bool {
+		if report.Clients[i].Incidents != report.Clients[j].Incidents {
+			return report.Clients[i].Incidents > report.Clients[j].Incidents
+		}
+		return report.Clients[i].Client < report.Clients[j].Client
+	})
+
+	return report
+}
+
+// WordList retorna a lista de termos em uso
+func (af *AbuseFilter) WordList() models.AbuseWordList {
+	af.mu.RLock()
+	defer af.mu.RUnlock()
+	list := af.list
+	list.Words = append([]string(nil), af.list.Words...)
+	return list
+}
+
+// UpdateWords substitui a lista de termos e a persiste para as demais réplicas
+func (af *AbuseFilter) UpdateWords(ctx context.Context, words []string, userName string) (*models.AbuseWordList, error) {
+	cleaned := cleanAbuseWords(words)
+	if len(cleaned) == 0 {
+		return nil, ErrEmptyAbuseWordList
+	}
+
+	if err := af.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+
+	list := models.AbuseWordList{Words: cleaned, UpdatedBy: userName, UpdatedAt: time.Now().Unix()}
+	doc := map[string]interface{}{
+		"id":         abuseWordListID,
+		"words":      list.Words,
+		"updated_by": list.UpdatedBy,
+		"updated_at": list.UpdatedAt,
+	}
+	if _, err := af.client.Collection(AbuseFilterCollection).Documents().Upsert(ctx, doc, &api.DocumentIndexParameters{}); err != nil {
+		return nil, fmt.Errorf("erro ao salvar lista de termos: %w", err)
+	}
+
+	af.setList(list)
+	return &list, nil
+}
+
+// Reload carrega a lista persistida (mantém a atual se não houver lista salva)
+func (af *AbuseFilter) Reload(ctx context.Context) error {
+	doc, err := af.client.Collection(AbuseFilterCollection).Document(abuseWordListID).Retrieve(ctx)
+	if err != nil {
+		if IsNotFoundError(err) {
+			return nil
+		}
+		return fmt.Errorf("erro ao carregar lista de termos: %w", err)
+	}
+
+	list := models.AbuseWordList{}
+	if raw, ok := doc["words"].([]interface{}); ok {
+		for _, w := range raw {
+			if word, ok := w.(string); ok {
+				list.Words = append(list.Words, word)
+			}
+		}
+	}
+	list.UpdatedBy, _ = doc["updated_by"].(string)
+	list.UpdatedAt = getInt64(doc, "updated_at")
+	if len(list.Words) == 0 {
+		return nil
+	}
+
+	af.setList(list)
+	return nil
+}
+
+// StartRefreshRoutine recarrega periodicamente a lista até o cancelamento de ctx, propagando
+// alterações feitas em outras réplicas
+func (af *AbuseFilter) StartRefreshRoutine(ctx context.Context, interval time.Duration) {
+	startReloadLoop(ctx, "Abuso", interval, af.Reload)
+}
+
+func (af *AbuseFilter) setList(list models.AbuseWordList) {
+	normalized := make([]string, 0, len(list.Words))
+	for _, word := range list.Words {
+		if word = normalizeSensitiveText(word); word != "" {
+			normalized = append(normalized, word)
+		}
+	}
+
+	af.mu.Lock()
+	af.list = list
+	af.words = normalized
+	af.mu.Unlock()
+}
+
+// ensureCollection garante que a collection abuse_filter_words existe
+func (af *AbuseFilter) ensureCollection(ctx context.Context) error {
+	_, err := af.client.Collection(AbuseFilterCollection).Retrieve(ctx)
+	if err == nil {
+		return nil
+	}
+
+	schema := &api.CollectionSchema{
+		Name: AbuseFilterCollection,
+		Fields: []api.Field{
+			{Name: "words", Type: "string[]", Index: pointer.False(), Optional: pointer.True()},
+			{Name: "updated_by", Type: "string", Index: pointer.False(), Optional: pointer.True()},
+			{Name: "updated_at", Type: "int64", Facet: pointer.False()},
+		},
+		DefaultSortingField: pointer.String("updated_at"),
+	}
+
+	if _, err := af.client.Collections().Create(ctx, schema); err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %w", AbuseFilterCollection, err)
+	}
+
+	return nil
+}
+
+// pruneIncidents descarta os incidentes anteriores ao início da janela
+func pruneIncidents(times []time.Time, since time.Time) []time.Time {
+	kept := times[:0]
+	for _, t := range times {
+		if t.After(since) {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// cleanAbuseWords remove espaços, duplicados e termos vazios, preservando a grafia informada
+func cleanAbuseWords(words []string) []string {
+	seen := make(map[string]bool, len(words))
+	cleaned := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		key := normalizeSensitiveText(word)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, word)
+	}
+	return cleaned
+}
+
+// DefaultAbuseWords palavrões e ofensas comuns em português usados até a lista ser substituída
+func DefaultAbuseWords() []string {
+	return []string{
+		"porra", "caralho", "merda", "puta", "puto", "putaria", "foda", "foda-se", "fodase", "foder",
+		"fdp", "filho da puta", "vsf", "vai se foder", "vtnc", "vai tomar no cu", "tnc", "pqp",
+		"buceta", "cacete", "arrombado", "arrombada", "desgraçado", "desgraçada", "otario", "otaria",
+		"babaca", "imbecil", "idiota", "corno", "cuzao", "bosta", "escroto", "escrota", "vagabundo", "vagabunda",
+	}
+}
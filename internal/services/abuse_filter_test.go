@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAbuseFilterDetect(t *testing.T) {
+	af := NewAbuseFilter(nil, 3, time.Minute, time.Minute)
+
+	cases := map[string]bool{
+		"segunda via do IPTU":          false,
+		"CADÊ A PORRA DO IPTU":         true,
+		"vai tomar no cu prefeitura":   true,
+		"computador para escola":       false, // "puta" dentro de outra palavra não conta
+		"matrícula escolar Desgraçado": true,
+	}
+	for query, want := range cases {
+		if got := af.Detect(query); got != want {
+			t.Errorf("Detect(%q) = %v, esperado %v", query, got, want)
+		}
+	}
+}
+
+func TestAbuseFilterEscalation(t *testing.T) {
+	af := NewAbuseFilter(nil, 3, time.Minute, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if af.RecordIncident("ip:1.2.3.4") {
+			t.Fatalf("cliente bloqueado cedo demais (incidente %d)", i+1)
+		}
+	}
+	if af.BlockedFor("ip:1.2.3.4") != 0 {
+		t.Fatal("cliente não deveria estar bloqueado antes do limite")
+	}
+	if !af.RecordIncident("ip:1.2.3.4") {
+		t.Fatal("cliente deveria ser bloqueado ao atingir o limite")
+	}
+	if af.BlockedFor("ip:1.2.3.4") <= 0 || af.BlockedFor("ip:5.6.7.8") != 0 {
+		t.Error("bloqueio deveria valer apenas para o cliente reincidente")
+	}
+
+	report := af.Report()
+	if report.TotalIncidents != 3 || len(report.Clients) != 1 || report.Clients[0].BlockedUntil == 0 {
+		t.Errorf("relatório inesperado: %+v", report)
+	}
+}
+
+func TestAbusiveQueryContext(t *testing.T) {
+	if IsAbusiveQuery(context.Background()) {
+		t.Fatal("contexto sem marcação não deveria ser ofensivo")
+	}
+	if !IsAbusiveQuery(WithAbusiveQuery(context.Background())) {
+		t.Fatal("contexto marcado deveria ser ofensivo")
+	}
+}
+
+func TestCleanAbuseWords(t *testing.T) {
+	got := cleanAbuseWords([]string{" Porra ", "porra", "", "Caralho"})
+	if len(got) != 2 || got[0] != "Porra" || got[1] != "Caralho" {
+		t.Errorf("lista inesperada: %v", got)
+	}
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+// activityVersionFields campos das versões necessários ao relatório (sem snapshot completo nem CPF)
+const activityVersionFields = "service_id,version_number,created_at,created_by,change_type,nome_servico,orgao_gestor,status"
+
+// ActivityService calcula a atividade editorial a partir da collection service_versions
+type ActivityService struct {
+	client *typesense.Client
+}
+
+// NewActivityService cria um novo serviço de relatório de atividade
+func NewActivityService(client *typesense.Client) *ActivityService {
+	return &ActivityService{client: client}
+}
+
+// GenerateReport agrega as edições das últimas semanas por usuário e órgão gestor e lista os
+// rascunhos sem edições há mais de staleDays dias
+func (as *ActivityService) GenerateReport(ctx context.Context, weeks, staleDays int) (*models.ActivityReport, error) {
+	versions, err := as.fetchVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildActivityReport(versions, time.Now(), weeks, staleDays), nil
+}
+
+// fetchVersions busca todas as versões (campos reduzidos), da mais antiga para a mais recente
+func (as *ActivityService) fetchVersions(ctx context.Context) ([]models.ServiceVersion, error) {
+	versions := []models.ServiceVersion{}
+
+	perPage := 250
+	for page := 1; ; page++ {
+		searchParams := &api.SearchCollectionParams{
+			Q:             pointer.String("*"),
+			Page:          pointer.Int(page),
+			PerPage:       pointer.Int(perPage),
+			SortBy:        pointer.String("created_at:asc"),
+			IncludeFields: pointer.String(activityVersionFields),
+		}
+
+		result, err := as.client.Collection(ServiceVersionsCollection).Documents().Search(ctx, searchParams)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar versões (página %d): %w", page, err)
+		}
+		if result.Hits == nil {
+			break
+		}
+
+		for _, hit := range *result.Hits {
+			if hit.Document == nil {
+				continue
+			}
+			docBytes, err := json.Marshal(*hit.Document)
+			if err != nil {
+				continue
+			}
+			var version models.ServiceVersion
+			if err := json.Unmarshal(docBytes, &version); err == nil {
+				versions = append(versions, version)
+			}
+		}
+		if len(*result.Hits) < perPage {
+			break
+		}
+	}
+
+	return versions, nil
+}
+
+// buildActivityReport monta o relatório a partir das versões
+func buildActivityReport(versions []models.ServiceVersion, now time.Time, weeks, staleDays int) *models.ActivityReport {
+	if weeks < 1 {
+		weeks = 8
+	}
+	if staleDays < 1 {
+		staleDays = 30
+	}
+
+	report := &models.ActivityReport{
+		GeneratedAt:    now.Unix(),
+		StaleAfterDays: staleDays,
+		ByUser:         []models.ActivityCount{},
+		ByOrgao:        []models.ActivityCount{},
+		StaleDrafts:    []models.StaleDraft{},
+	}
+
+	currentWeek := weekStart(now)
+	for i := weeks - 1; i >= 0; i-- {
+		report.Weeks = append(report.Weeks, currentWeek.AddDate(0, 0, -7*i).Format("2006-01-02"))
+	}
+	windowStart := currentWeek.AddDate(0, 0, -7*(weeks-1)).Unix()
+
+	byUser := make(map[string]*models.ActivityCount)
+	byOrgao := make(map[string]*models.ActivityCount)
+	latest := make(map[string]models.ServiceVersion)
+
+	for _, version := range versions {
+		if current, ok := latest[version.ServiceID]; !ok || version.VersionNumber > current.VersionNumber {
+			latest[version.ServiceID] = version
+		}
+
+		if version.CreatedAt < windowStart || version.CreatedAt > now.Unix() {
+			continue
+		}
+		week := weekStart(time.Unix(version.CreatedAt, 0).In(now.Location())).Format("2006-01-02")
+		report.TotalEdits++
+
+		user := version.CreatedBy
+		if user == "" {
+			user = "sem_usuario"
+		}
+		addActivity(byUser, user, week)
+
+		orgaos := version.OrgaoGestor
+		if len(orgaos) == 0 {
+			orgaos = []string{"sem_orgao"}
+		}
+		for _, orgao := range orgaos {
+			addActivity(byOrgao, orgao, week)
+		}
+	}
+
+	report.ByUser = sortedActivity(byUser)
+	report.ByOrgao = sortedActivity(byOrgao)
+
+	staleCutoff := now.AddDate(0, 0, -staleDays).Unix()
+	for _, version := range latest {
+		if version.Status != 0 || version.ChangeType == "delete" || version.CreatedAt >= staleCutoff {
+			continue
+		}
+		report.StaleDrafts = append(report.StaleDrafts, models.StaleDraft{
+			ServiceID:     version.ServiceID,
+			NomeServico:   version.NomeServico,
+			OrgaoGestor:   version.OrgaoGestor,
+			LastEditedBy:  version.CreatedBy,
+			LastEditedAt:  version.CreatedAt,
+			DaysIdle:      int(now.Sub(time.Unix(version.CreatedAt, 0)).Hours() / 24),
+			VersionNumber: version.VersionNumber,
+		})
+	}
+	sort.Slice(report.StaleDrafts, func(i, j int) bool {
+		if report.StaleDrafts[i].DaysIdle != report.StaleDrafts[j].DaysIdle {
+			return report.StaleDrafts[i].DaysIdle > report.StaleDrafts[j].DaysIdle
+		}
+		return report.StaleDrafts[i].ServiceID < report.StaleDrafts[j].ServiceID
+	})
+
+	return report
+}
+
+func addActivity(counts map[string]*models.ActivityCount, key, week string) {
+	count, ok := counts[key]
+	if !ok {
+		count = &models.ActivityCount{Key: key, Weeks: make(map[string]int)}
+		counts[key] = count
+	}
+	count.Weeks[week]++
+	count.Total++
+}
+
+// sortedActivity ordena por total de edições (decrescente) e depois pela chave
+func sortedActivity(counts map[string]*models.ActivityCount) []models.ActivityCount {
+	result := make([]models.ActivityCount, 0, len(counts))
+	for _, count := range counts {
+		result = append(result, *count)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total != result[j].Total {
+			return result[i].Total > result[j].Total
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// weekStart retorna a segunda-feira (00:00) da semana de t
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return day.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestBuildActivityReport(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC) // quinta-feira
+	day := func(daysAgo int) int64 { return now.AddDate(0, 0, -daysAgo).Unix() }
+
+	versions := []models.ServiceVersion{
+		{ServiceID: "a", VersionNumber: 1, CreatedAt: day(60), CreatedBy: "Ana", OrgaoGestor: []string{"SMS"}, Status: 0},
+		{ServiceID: "b", VersionNumber: 1, CreatedAt: day(9), CreatedBy: "Ana", OrgaoGestor: []string{"SMS"}, Status: 0},
+		{ServiceID: "b", VersionNumber: 2, CreatedAt: day(1), CreatedBy: "Bruno", OrgaoGestor: []string{"SMS", "SMF"}, Status: 1},
+		{ServiceID: "c", VersionNumber: 1, CreatedAt: day(2), CreatedBy: "Ana", Status: 0},
+		{ServiceID: "d", VersionNumber: 3, CreatedAt: day(90), CreatedBy: "Bruno", ChangeType: "delete", Status: 0},
+	}
+
+	report := buildActivityReport(versions, now, 2, 30)
+
+	if len(report.Weeks) != 2 || report.Weeks[0] != "2026-10-05" || report.Weeks[1] != "2026-10-12" {
+		t.Fatalf("semanas inesperadas: %v", report.Weeks)
+	}
+	if report.TotalEdits != 3 {
+		t.Errorf("esperado 3 edições na janela, obtido %d", report.TotalEdits)
+	}
+
+	if len(report.ByUser) != 2 || report.ByUser[0].Key != "Ana" || report.ByUser[0].Total != 2 {
+		t.Fatalf("atividade por usuário inesperada: %+v", report.ByUser)
+	}
+	if report.ByUser[0].Weeks["2026-10-05"] != 1 || report.ByUser[0].Weeks["2026-10-12"] != 1 {
+		t.Errorf("semanas da Ana inesperadas: %v", report.ByUser[0].Weeks)
+	}
+
+	orgaos := map[string]int{}
+	for _, count := range report.ByOrgao {
+		orgaos[count.Key] = count.Total
+	}
+	if orgaos["SMS"] != 2 || orgaos["SMF"] != 1 || orgaos["sem_orgao"] != 1 {
+		t.Errorf("atividade por órgão inesperada: %v", orgaos)
+	}
+
+	// Apenas "a": "b" foi publicado, "c" é recente e "d" foi excluído
+	if len(report.StaleDrafts) != 1 || report.StaleDrafts[0].ServiceID != "a" || report.StaleDrafts[0].DaysIdle != 60 {
+		t.Errorf("rascunhos parados inesperados: %+v", report.StaleDrafts)
+	}
+}
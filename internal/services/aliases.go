@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+// aliasNamePattern nomes aceitos pelo Typesense na URL do alias
+var aliasNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,200}$`)
+
+// aliasBuiltinCollections collections lidas pela aplicação independentemente da configuração
+var aliasBuiltinCollections = []string{
+	PrefRioServicesCollection,
+	ServiceVersionsCollection,
+	HubSearchCollection,
+	TombamentosCollection,
+	config.NoticiasCollectionName,
+}
+
+var (
+	// ErrAliasNotFound indica alias inexistente
+	ErrAliasNotFound = errors.New("alias não encontrado")
+
+	// ErrInvalidAlias indica nome de alias ou collection de destino inválidos
+	ErrInvalidAlias = errors.New("alias inválido")
+
+	// ErrAliasInUse indica alias referenciado pela configuração
+	ErrAliasInUse = errors.New("alias em uso pela configuração")
+
+	// ErrAliasConfirmationRequired indica reapontamento de alias em uso sem token válido
+	ErrAliasConfirmationRequired = errors.New("reapontar alias em uso exige confirmação")
+)
+
+// AliasConfirmationError carrega o token que confirma o reapontamento de um alias em uso.
+// O token identifica o alias e as collections de origem e destino: deixa de valer se o alias
+// mudar antes da confirmação. Não é segredo; só evita reapontamentos acidentais.
+type AliasConfirmationError struct {
+	Alias string
+	From  string
+	To    string
+	Token string
+}
+
+func (e *AliasConfirmationError) Error() string {
+	return fmt.Sprintf("%s: %s aponta para %s; reenvie com confirmation_token para apontá-lo para %s", ErrAliasConfirmationRequired, e.Alias, e.From, e.To)
+}
+
+func (e *AliasConfirmationError) Unwrap() error {
+	return ErrAliasConfirmationRequired
+}
+
+// Aliases administra os aliases do Typesense com salvaguardas contra quebrar a busca: aliases
+// referenciados pela configuração não podem ser removidos e só são reapontados com confirmação
+type Aliases struct {
+	client *typesense.Client
+	store  *config.Store
+}
+
+// NewAliases cria o administrador de aliases
+func NewAliases(client *typesense.Client, store *config.Store) *Aliases {
+	return &Aliases{client: client, store: store}
+}
+
+// List retorna os aliases ordenados por nome, com as referências da configuração a cada um
+func (a *Aliases) List(ctx context.Context) (*models.AliasList, error) {
+	aliases, err := a.client.Aliases().Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar aliases: %w", err)
+	}
+
+	list := &models.AliasList{Aliases: make([]models.CollectionAlias, 0, len(aliases))}
+	for _, alias := range aliases {
+		if alias.Name == nil {
+			continue
+		}
+		list.Aliases = append(list.Aliases, a.describe(*alias.Name, alias.CollectionName))
+	}
+	sort.Slice(list.Aliases, func(i, j int) bool { return list.Aliases[i].Name < list.Aliases[j].Name })
+	list.Total = len(list.Aliases)
+	return list, nil
+}
+
+// Get retorna o alias
+func (a *Aliases) Get(ctx context.Context, name string) (*models.CollectionAlias, error) {
+	alias, err := a.client.Alias(name).Retrieve(ctx)
+	if err != nil {
+		if IsNotFoundError(err) {
+			return nil, ErrAliasNotFound
+		}
+		return nil, fmt.Errorf("erro ao consultar alias: %w", err)
+	}
+	described := a.describe(name, alias.CollectionName)
+	return &described, nil
+}
+
+// Save cria o alias ou o reaponta para a collection. A collection de destino deve existir.
+// Reapontar um alias em uso exige o token retornado em AliasConfirmationError.
+func (a *Aliases) Save(ctx context.Context, request *models.AliasRequest, userName string) (*models.CollectionAlias, error) {
+	name := request.Name
+	if !aliasNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("%w: nome %q", ErrInvalidAlias, name)
+	}
+	if request.CollectionName == name {
+		return nil, fmt.Errorf("%w: o alias não pode apontar para si mesmo", ErrInvalidAlias)
+	}
+	if _, err := a.client.Collection(request.CollectionName).Retrieve(ctx); err != nil {
+		if IsNotFoundError(err) {
+			return nil, fmt.Errorf("%w: collection %s não existe", ErrInvalidAlias, request.CollectionName)
+		}
+		return nil, fmt.Errorf("erro ao consultar collection: %w", err)
+	}
+
+	current, err := a.Get(ctx, name)
+	if err != nil && !errors.Is(err, ErrAliasNotFound) {
+		return nil, err
+	}
+	if current != nil && current.CollectionName == request.CollectionName {
+		return current, nil
+	}
+	if current != nil && len(current.InUseBy) > 0 {
+		token := aliasConfirmationToken(name, current.CollectionName, request.CollectionName)
+		if request.ConfirmationToken != token {
+			return nil, &AliasConfirmationError{Alias: name, From: current.CollectionName, To: request.CollectionName, Token: token}
+		}
+	}
+
+	if _, err := a.client.Aliases().Upsert(ctx, name, &api.CollectionAliasSchema{CollectionName: request.CollectionName}); err != nil {
+		return nil, fmt.Errorf("erro ao salvar alias: %w", err)
+	}
+	if current != nil {
+		log.Printf("[Aliases] %s reapontou %s de %s para %s", userName, name, current.CollectionName, request.CollectionName)
+	} else {
+		log.Printf("[Aliases] %s criou %s apontando para %s", userName, name, request.CollectionName)
+	}
+
+	saved := a.describe(name, request.CollectionName)
+	return &saved, nil
+}
+
+// Delete remove o alias; aliases referenciados pela configuração não podem ser removidos
+func (a *Aliases) Delete(ctx context.Context, name, userName string) error {
+	current, err := a.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(current.InUseBy) > 0 {
+		return fmt.Errorf("%w: %s é referenciado por %v", ErrAliasInUse, name, current.InUseBy)
+	}
+
+	if _, err := a.client.Alias(name).Delete(ctx); err != nil {
+		if IsNotFoundError(err) {
+			return ErrAliasNotFound
+		}
+		return fmt.Errorf("erro ao remover alias: %w", err)
+	}
+	log.Printf("[Aliases] %s removeu %s (apontava para %s)", userName, name, current.CollectionName)
+	return nil
+}
+
+func (a *Aliases) describe(name, collectionName string) models.CollectionAlias {
+	return models.CollectionAlias{
+		Name:           name,
+		CollectionName: collectionName,
+		InUseBy:        aliasReferences(a.store.Current(), name),
+	}
+}
+
+// aliasReferences lista onde a configuração usa o nome: collections internas, collections
+// buscáveis, configs de collection e collections dos tenants
+func aliasReferences(cfg *config.Config, name string) []string {
+	var references []string
+	if slices.Contains(aliasBuiltinCollections, name) {
+		references = append(references, "collection interna")
+	}
+	if cfg == nil {
+		return references
+	}
+	if slices.Contains(cfg.SearchableCollections, name) {
+		references = append(references, "SEARCHABLE_COLLECTIONS")
+	}
+	if _, ok := cfg.CollectionConfigs[name]; ok {
+		references = append(references, "COLLECTION_CONFIGS")
+	}
+	var tenants []string
+	for id, tenant := range cfg.Tenants {
+		if slices.Contains(tenant.SearchableCollections, name) {
+			tenants = append(tenants, "tenant "+id)
+		}
+	}
+	sort.Strings(tenants)
+	return append(references, tenants...)
+}
+
+// aliasConfirmationToken identifica o reapontamento do alias de uma collection para outra
+func aliasConfirmationToken(name, from, to string) string {
+	sum := sha256.Sum256([]byte(name + "\x00" + from + "\x00" + to))
+	return hex.EncodeToString(sum[:6])
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+)
+
+func TestAliasReferences(t *testing.T) {
+	cfg := &config.Config{
+		SearchableCollections: []string{"servicos", "cursos"},
+		CollectionConfigs:     map[string]*config.CollectionConfig{"servicos": {}, "vagas": {}},
+		Tenants: map[string]*config.TenantConfig{
+			"niteroi": {SearchableCollections: []string{"servicos"}},
+			"marica":  {SearchableCollections: []string{"servicos", "vagas"}},
+		},
+	}
+
+	cases := map[string][]string{
+		"servicos":                {"SEARCHABLE_COLLECTIONS", "COLLECTION_CONFIGS", "tenant marica", "tenant niteroi"},
+		"vagas":                   {"COLLECTION_CONFIGS", "tenant marica"},
+		PrefRioServicesCollection: {"collection interna"},
+		"servicos_v2":             nil,
+	}
+	for name, expected := range cases {
+		if got := aliasReferences(cfg, name); !reflect.DeepEqual(got, expected) {
+			t.Errorf("%s: esperado %v, obtido %v", name, expected, got)
+		}
+	}
+}
+
+func TestAliasConfirmationToken(t *testing.T) {
+	token := aliasConfirmationToken("servicos", "servicos_v1", "servicos_v2")
+	if token != aliasConfirmationToken("servicos", "servicos_v1", "servicos_v2") {
+		t.Error("token deveria ser determinístico")
+	}
+	// Token deixa de valer se o alias mudou ou o destino é outro
+	if token == aliasConfirmationToken("servicos", "servicos_v3", "servicos_v2") || token == aliasConfirmationToken("servicos", "servicos_v1", "servicos_v3") {
+		t.Error("token deveria depender da origem e do destino")
+	}
+
+	err := error(&AliasConfirmationError{Alias: "servicos", From: "servicos_v1", To: "servicos_v2", Token: token})
+	if !errors.Is(err, ErrAliasConfirmationRequired) {
+		t.Error("AliasConfirmationError deveria corresponder a ErrAliasConfirmationRequired")
+	}
+}
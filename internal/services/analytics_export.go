@@ -0,0 +1,373 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+const (
+	// AnalyticsExportsCollection guarda o estado das exportações assíncronas
+	AnalyticsExportsCollection = "analytics_exports"
+
+	// analyticsExportURLExpiry validade da URL de download de uma exportação concluída
+	analyticsExportURLExpiry = time.Hour
+	// analyticsExportTimeout limite de uma exportação assíncrona
+	analyticsExportTimeout = 30 * time.Minute
+)
+
+var (
+	// ErrInvalidAnalyticsExport indica período, formato ou modo de query inválidos
+	ErrInvalidAnalyticsExport = errors.New("exportação inválida")
+
+	// ErrAnalyticsExportTooLarge indica período acima do limite da exportação síncrona sem
+	// bucket para a exportação assíncrona
+	ErrAnalyticsExportTooLarge = errors.New("período excede o limite de eventos da exportação")
+)
+
+// analyticsCSVHeader colunas do CSV, na ordem de AnalyticsExportRow
+var analyticsCSVHeader = []string{"event_type", "timestamp", "session_hash", "query_hash", "query", "search_type", "latency_ms", "result_count", "clicked_rank", "service_id", "interventions"}
+
+// AnalyticsExporter exporta os eventos de busca e clique (search_journeys) anonimizados para a
+// plataforma de dados. Períodos de até maxEvents eventos são transmitidos na própria resposta;
+// os maiores são gravados no bucket em segundo plano.
+type AnalyticsExporter struct {
+	client    *typesense.Client
+	storage   *GCSStorage // nil desabilita a exportação assíncrona
+	salt      []byte
+	maxEvents int
+}
+
+// NewAnalyticsExporter cria o exportador. salt é a chave do HMAC dos hashes de sessão e query
+// (vazio = SHA-256 simples).
+func NewAnalyticsExporter(client *typesense.Client, storage *GCSStorage, salt string, maxEvents int) *AnalyticsExporter {
+	return &AnalyticsExporter{client: client, storage: storage, salt: []byte(salt), maxEvents: maxEvents}
+}
+
+// MaxEvents retorna o limite de eventos da exportação síncrona
+func (e *AnalyticsExporter) MaxEvents() int {
+	return e.maxEvents
+}
+
+// AsyncAvailable indica se há bucket para exportações assíncronas
+func (e *AnalyticsExporter) AsyncAvailable() bool {
+	return e.storage != nil
+}
+
+// Validate normaliza e valida o pedido de exportação
+func (e *AnalyticsExporter) Validate(req *models.AnalyticsExportRequest) error {
+	if req.Format == "" {
+		req.Format = models.AnalyticsExportJSON
+	}
+	if req.QueryMode == "" {
+		req.QueryMode = models.AnalyticsQueryHash
+	}
+	if req.From <= 0 || req.To <= req.From {
+		return fmt.Errorf("%w: from deve ser anterior a to", ErrInvalidAnalyticsExport)
+	}
+	if req.Format != models.AnalyticsExportJSON && req.Format != models.AnalyticsExportCSV {
+		return fmt.Errorf("%w: format deve ser json ou csv", ErrInvalidAnalyticsExport)
+	}
+	if req.QueryMode != models.AnalyticsQueryHash && req.QueryMode != models.AnalyticsQueryText {
+		return fmt.Errorf("%w: query deve ser hash ou text", ErrInvalidAnalyticsExport)
+	}
+	return nil
+}
+
+// Count retorna quantos eventos o período tem
+func (e *AnalyticsExporter) Count(ctx context.Context, req *models.AnalyticsExportRequest) (int, error) {
+	result, err := e.client.Collection(JourneyEventsCollection).Documents().Search(ctx, &api.SearchCollectionParams{
+		Q:        pointer.String("*"),
+		FilterBy: pointer.String(analyticsExportFilter(req)),
+		PerPage:  pointer.Int(0),
+	})
+	if err != nil {
+		if IsNotFoundError(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("erro ao contar eventos: %w", err)
+	}
+	if result.Found == nil {
+		return 0, nil
+	}
+	return *result.Found, nil
+}
+
+// Write transmite os eventos do período para w, no máximo limit (0 = sem limite), e retorna
+// quantos foram escritos. Os eventos saem na ordem do export do Typesense, não por timestamp.
+func (e *AnalyticsExporter) Write(ctx context.Context, w io.Writer, req *models.AnalyticsExportRequest, limit int) (int, error) {
+	body, err := e.client.Collection(JourneyEventsCollection).Documents().Export(ctx, &api.ExportDocumentsParams{
+		FilterBy: pointer.String(analyticsExportFilter(req)),
+	})
+	if err != nil {
+		if IsNotFoundError(err) {
+			body = io.NopCloser(strings.NewReader(""))
+		} else {
+			return 0, fmt.Errorf("erro ao exportar eventos: %w", err)
+		}
+	}
+	defer body.Close()
+
+	writeRow := analyticsRowWriter(w, req.Format)
+	written := 0
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if limit > 0 && written >= limit {
+			break
+		}
+		var event models.JourneyEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		if err := writeRow(e.row(event, req.QueryMode)); err != nil {
+			return written, err
+		}
+		written++
+	}
+	if err := scanner.Err(); err != nil {
+		return written, fmt.Errorf("erro ao ler eventos exportados: %w", err)
+	}
+	return written, writeRow(nil)
+}
+
+// StartAsync registra a exportação e a grava no bucket em segundo plano
+func (e *AnalyticsExporter) StartAsync(ctx context.Context, req *models.AnalyticsExportRequest, requestedBy string) (*models.AnalyticsExportJob, error) {
+	if e.storage == nil {
+		return nil, fmt.Errorf("%w: exportação assíncrona exige GCS_BUCKET", ErrAnalyticsExportTooLarge)
+	}
+	if err := e.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+
+	id := uuid.New().String()
+	job := &models.AnalyticsExportJob{
+		ID:          id,
+		Status:      models.AnalyticsExportRunning,
+		From:        req.From,
+		To:          req.To,
+		Format:      req.Format,
+		QueryMode:   req.QueryMode,
+		ObjectName:  fmt.Sprintf("analytics-exports/%s.%s", id, analyticsExportExtension(req.Format)),
+		RequestedBy: requestedBy,
+		CreatedAt:   time.Now().Unix(),
+	}
+	if err := e.saveJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	go e.runAsync(*req, job)
+	return job, nil
+}
+
+func (e *AnalyticsExporter) runAsync(req models.AnalyticsExportRequest, job *models.AnalyticsExportJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), analyticsExportTimeout)
+	defer cancel()
+
+	reader, writer := io.Pipe()
+	written := make(chan int, 1)
+	go func() {
+		count, err := e.Write(ctx, writer, &req, 0)
+		written <- count
+		writer.CloseWithError(err)
+	}()
+
+	err := e.storage.Upload(ctx, job.ObjectName, AnalyticsExportContentType(req.Format), reader)
+	reader.CloseWithError(err)
+	job.Events = <-written
+	job.FinishedAt = time.Now().Unix()
+	job.Status = models.AnalyticsExportCompleted
+	if err != nil {
+		job.Status = models.AnalyticsExportFailed
+		job.Error = err.Error()
+		log.Printf("[AnalyticsExport] Exportação %s falhou: %v", job.ID, err)
+	}
+
+	saveCtx, saveCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer saveCancel()
+	if err := e.saveJob(saveCtx, job); err != nil {
+		log.Printf("[AnalyticsExport] Erro ao registrar exportação %s: %v", job.ID, err)
+	}
+}
+
+// Job retorna a exportação assíncrona (nil se não existir), com a URL de download quando
+// concluída
+func (e *AnalyticsExporter) Job(ctx context.Context, id string) (*models.AnalyticsExportJob, error) {
+	doc, err := e.client.Collection(AnalyticsExportsCollection).Document(id).Retrieve(ctx)
+	if err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar exportação: %w", err)
+	}
+
+	raw, _ := doc["job"].(string)
+	var job models.AnalyticsExportJob
+	if err := json.Unmarshal([]byte(raw), &job); err != nil {
+		return nil, fmt.Errorf("erro ao deserializar exportação: %w", err)
+	}
+
+	if job.Status == models.AnalyticsExportCompleted && e.storage != nil {
+		filename := fmt.Sprintf("analytics-%s.%s", time.Unix(job.From, 0).Format("20060102"), analyticsExportExtension(job.Format))
+		if url, err := e.storage.SignedURL(job.ObjectName, filename, analyticsExportURLExpiry); err != nil {
+			job.Error = err.Error()
+		} else {
+			job.DownloadURL = url
+		}
+	}
+	return &job, nil
+}
+
+func (e *AnalyticsExporter) saveJob(ctx context.Context, job *models.AnalyticsExportJob) error {
+	raw, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar exportação: %w", err)
+	}
+	doc := map[string]interface{}{
+		"id":           job.ID,
+		"status":       string(job.Status),
+		"requested_by": job.RequestedBy,
+		"created_at":   job.CreatedAt,
+		"job":          string(raw),
+	}
+	if _, err := e.client.Collection(AnalyticsExportsCollection).Documents().Upsert(ctx, doc, &api.DocumentIndexParameters{}); err != nil {
+		return fmt.Errorf("erro ao salvar exportação: %w", err)
+	}
+	return nil
+}
+
+// row anonimiza o evento: sessão e query viram hashes e o texto da query só é mantido no
+// modo text
+func (e *AnalyticsExporter) row(event models.JourneyEvent, queryMode string) *models.AnalyticsExportRow {
+	row := &models.AnalyticsExportRow{
+		EventType:     event.Type,
+		Timestamp:     event.Timestamp,
+		SessionHash:   e.hash("session:" + event.SessionID),
+		SearchType:    event.SearchType,
+		LatencyMs:     event.LatencyMs,
+		ResultCount:   event.ResultCount,
+		ServiceID:     event.ServiceID,
+		Interventions: event.Interventions,
+	}
+	if event.Type == models.JourneyEventClick {
+		row.ClickedRank = event.Position
+	}
+	if event.Query != "" {
+		row.QueryHash = e.hash("query:" + event.Query)
+		if queryMode == models.AnalyticsQueryText {
+			row.Query = event.Query
+		}
+	}
+	return row
+}
+
+func (e *AnalyticsExporter) hash(value string) string {
+	if len(e.salt) == 0 {
+		sum := sha256.Sum256([]byte(value))
+		return hex.EncodeToString(sum[:16])
+	}
+	mac := hmac.New(sha256.New, e.salt)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// analyticsRowWriter escreve as linhas no formato pedido; nil encerra a escrita
+func analyticsRowWriter(w io.Writer, format string) func(*models.AnalyticsExportRow) error {
+	if format == models.AnalyticsExportCSV {
+		writer := csv.NewWriter(w)
+		headerWritten := false
+		return func(row *models.AnalyticsExportRow) error {
+			if !headerWritten {
+				headerWritten = true
+				if err := writer.Write(analyticsCSVHeader); err != nil {
+					return err
+				}
+			}
+			if row != nil {
+				writer.Write([]string{
+					row.EventType,
+					strconv.FormatInt(row.Timestamp, 10),
+					row.SessionHash,
+					row.QueryHash,
+					row.Query,
+					row.SearchType,
+					strconv.FormatInt(row.LatencyMs, 10),
+					strconv.Itoa(row.ResultCount),
+					strconv.Itoa(row.ClickedRank),
+					row.ServiceID,
+					strings.Join(row.Interventions, ";"),
+				})
+			}
+			writer.Flush()
+			return writer.Error()
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	return func(row *models.AnalyticsExportRow) error {
+		if row == nil {
+			return nil
+		}
+		return encoder.Encode(row)
+	}
+}
+
+func analyticsExportFilter(req *models.AnalyticsExportRequest) string {
+	return fmt.Sprintf("timestamp:>=%d && timestamp:<%d", req.From, req.To)
+}
+
+func analyticsExportExtension(format string) string {
+	if format == models.AnalyticsExportCSV {
+		return "csv"
+	}
+	return "ndjson"
+}
+
+// AnalyticsExportContentType retorna o Content-Type do formato da exportação
+func AnalyticsExportContentType(format string) string {
+	if format == models.AnalyticsExportCSV {
+		return "text/csv"
+	}
+	return "application/x-ndjson"
+}
+
+func (e *AnalyticsExporter) ensureCollection(ctx context.Context) error {
+	_, err := e.client.Collection(AnalyticsExportsCollection).Retrieve(ctx)
+	if err == nil {
+		return nil
+	}
+
+	schema := &api.CollectionSchema{
+		Name: AnalyticsExportsCollection,
+		Fields: []api.Field{
+			{Name: "status", Type: "string", Facet: pointer.True()},
+			{Name: "requested_by", Type: "string", Facet: pointer.True()},
+			{Name: "created_at", Type: "int64", Facet: pointer.False()},
+			{Name: "job", Type: "string", Index: pointer.False(), Optional: pointer.True()},
+		},
+		DefaultSortingField: pointer.String("created_at"),
+	}
+
+	if _, err := e.client.Collections().Create(ctx, schema); err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %w", AnalyticsExportsCollection, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestAnalyticsExportRow(t *testing.T) {
+	exporter := NewAnalyticsExporter(nil, nil, "segredo", 10)
+	search := models.JourneyEvent{SessionID: "sessao-123", Type: models.JourneyEventSearch, Query: "segunda via iptu", SearchType: "hybrid", LatencyMs: 42, ResultCount: 7, Timestamp: 100}
+	click := models.JourneyEvent{SessionID: "sessao-123", Type: models.JourneyEventClick, Query: "segunda via iptu", ServiceID: "svc-1", Position: 2, Timestamp: 110}
+
+	hashed := exporter.row(search, models.AnalyticsQueryHash)
+	if hashed.Query != "" || hashed.QueryHash == "" {
+		t.Errorf("modo hash deveria omitir o texto da query: %+v", hashed)
+	}
+	if strings.Contains(hashed.SessionHash, "sessao") || hashed.SearchType != "hybrid" || hashed.LatencyMs != 42 || hashed.ClickedRank != 0 {
+		t.Errorf("linha de busca inesperada: %+v", hashed)
+	}
+
+	clicked := exporter.row(click, models.AnalyticsQueryText)
+	if clicked.Query != "segunda via iptu" || clicked.ClickedRank != 2 {
+		t.Errorf("linha de clique inesperada: %+v", clicked)
+	}
+	// Hashes permitem juntar busca e clique da mesma sessão e query
+	if clicked.SessionHash != hashed.SessionHash || clicked.QueryHash != hashed.QueryHash {
+		t.Error("hashes da mesma sessão/query deveriam coincidir")
+	}
+	if NewAnalyticsExporter(nil, nil, "outro", 10).row(search, models.AnalyticsQueryHash).SessionHash == hashed.SessionHash {
+		t.Error("hash deveria depender do salt")
+	}
+}
+
+func TestAnalyticsRowWriter(t *testing.T) {
+	row := &models.AnalyticsExportRow{EventType: "search", Timestamp: 100, SessionHash: "s", QueryHash: "q", SearchType: "keyword", ResultCount: 3}
+
+	var csvOut bytes.Buffer
+	write := analyticsRowWriter(&csvOut, models.AnalyticsExportCSV)
+	if err := write(row); err != nil {
+		t.Fatal(err)
+	}
+	if err := write(nil); err != nil {
+		t.Fatal(err)
+	}
+	expected := strings.Join(analyticsCSVHeader, ",") + "\nsearch,100,s,q,,keyword,0,3,0,,\n"
+	if csvOut.String() != expected {
+		t.Errorf("CSV inesperado:\n%s", csvOut.String())
+	}
+
+	// Sem eventos o CSV ainda tem o cabeçalho
+	var empty bytes.Buffer
+	if err := analyticsRowWriter(&empty, models.AnalyticsExportCSV)(nil); err != nil || empty.String() != strings.Join(analyticsCSVHeader, ",")+"\n" {
+		t.Errorf("CSV vazio inesperado: %q (%v)", empty.String(), err)
+	}
+
+	var jsonOut bytes.Buffer
+	write = analyticsRowWriter(&jsonOut, models.AnalyticsExportJSON)
+	write(row)
+	write(nil)
+	if jsonOut.String() != `{"event_type":"search","timestamp":100,"session_hash":"s","query_hash":"q","search_type":"keyword","result_count":3}`+"\n" {
+		t.Errorf("JSON inesperado: %s", jsonOut.String())
+	}
+}
+
+func TestAnalyticsExportValidate(t *testing.T) {
+	exporter := NewAnalyticsExporter(nil, nil, "", 10)
+
+	req := &models.AnalyticsExportRequest{From: 100, To: 200}
+	if err := exporter.Validate(req); err != nil || req.Format != models.AnalyticsExportJSON || req.QueryMode != models.AnalyticsQueryHash {
+		t.Errorf("padrões inesperados: %+v (%v)", req, err)
+	}
+	for _, invalid := range []*models.AnalyticsExportRequest{
+		{From: 200, To: 100},
+		{From: 100, To: 200, Format: "parquet"},
+		{From: 100, To: 200, QueryMode: "raw"},
+	} {
+		if err := exporter.Validate(invalid); !errors.Is(err, ErrInvalidAnalyticsExport) {
+			t.Errorf("%+v: esperado ErrInvalidAnalyticsExport, obtido %v", invalid, err)
+		}
+	}
+}
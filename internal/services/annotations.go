@@ -0,0 +1,53 @@
+package services
+
+import "github.com/prefeitura-rio/app-busca-search/internal/models"
+
+// resultAnnotations intervenções aplicadas a cada resultado de uma busca
+type resultAnnotations map[*models.ServiceDocument][]models.ResultAnnotation
+
+// resultPositions registra a posição atual de cada resultado, antes de uma etapa de reordenação
+func resultPositions(results []*models.ServiceDocument) map[*models.ServiceDocument]int {
+	positions := make(map[*models.ServiceDocument]int, len(results))
+	for i, doc := range results {
+		positions[doc] = i
+	}
+	return positions
+}
+
+// demoted anota com a regra os resultados que perderam posições desde before e que a regra
+// afetou (affected nil considera todos)
+func (ra resultAnnotations) demoted(results []*models.ServiceDocument, before map[*models.ServiceDocument]int, rule string, affected func(*models.ServiceDocument) bool) {
+	for i, doc := range results {
+		if i <= before[doc] || (affected != nil && !affected(doc)) {
+			continue
+		}
+		ra[doc] = append(ra[doc], models.ResultAnnotation{Type: models.AnnotationDemotion, Rule: rule})
+	}
+}
+
+// apply retorna as intervenções no formato dos eventos de busca, na ordem dos resultados, e as
+// anexa aos resultados se attach
+func (ra resultAnnotations) apply(results []*models.ServiceDocument, attach bool) []string {
+	var keys []string
+	for _, doc := range results {
+		for _, annotation := range ra[doc] {
+			keys = append(keys, annotation.Key(doc.ID))
+		}
+		if attach {
+			doc.Annotations = ra[doc]
+		}
+	}
+	return keys
+}
+
+// translationAnnotation anotação da query traduzida (nil se não houve tradução)
+func translationAnnotation(meta *models.QueryMeta) *models.ResultAnnotation {
+	if meta == nil || !meta.Translated {
+		return nil
+	}
+	return &models.ResultAnnotation{
+		Type:   models.AnnotationRewrite,
+		Rule:   models.AnnotationRuleTranslation,
+		Detail: meta.Language + ": " + meta.OriginalQuery + " → " + meta.TranslatedQuery,
+	}
+}
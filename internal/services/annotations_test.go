@@ -0,0 +1,45 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestResultAnnotations(t *testing.T) {
+	results := []*models.ServiceDocument{
+		{ID: "antigo", Metadata: map[string]interface{}{"deprecated": true}},
+		{ID: "a"},
+		{ID: "b"},
+	}
+
+	annotations := resultAnnotations{}
+	before := resultPositions(results)
+	demoteDeprecated(results)
+	annotations.demoted(results, before, models.AnnotationRuleDeprecated, isDeprecated)
+
+	keys := annotations.apply(results, false)
+	if expected := []string{"antigo:demotion:deprecated"}; !reflect.DeepEqual(keys, expected) {
+		t.Errorf("esperado %v, obtido %v", expected, keys)
+	}
+	if results[2].Annotations != nil {
+		t.Error("anotações anexadas sem debug_annotations")
+	}
+
+	annotations.apply(results, true)
+	if len(results[2].Annotations) != 1 || results[2].Annotations[0].Rule != models.AnnotationRuleDeprecated {
+		t.Errorf("anotação inesperada: %+v", results[2].Annotations)
+	}
+	if results[0].Annotations != nil || results[1].Annotations != nil {
+		t.Error("resultados promovidos não devem ser anotados")
+	}
+
+	if translationAnnotation(&models.QueryMeta{Language: "pt"}) != nil {
+		t.Error("query não traduzida não deve ser anotada")
+	}
+	rewrite := translationAnnotation(&models.QueryMeta{OriginalQuery: "tax", TranslatedQuery: "imposto", Language: "en", Translated: true})
+	if rewrite == nil || rewrite.Key("") != "rewrite:translation" {
+		t.Errorf("anotação de tradução inesperada: %+v", rewrite)
+	}
+}
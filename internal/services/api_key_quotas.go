@@ -0,0 +1,408 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+// APIKeyQuotasCollection guarda as quotas personalizadas e os aumentos temporários por key
+const APIKeyQuotasCollection = "api_key_quotas"
+
+// ErrQuotaBoostNotFound indica aumento de quota inexistente na key
+var ErrQuotaBoostNotFound = errors.New("aumento de quota não encontrado")
+
+// QuotaCounter conta as chamadas diárias de cada key. Com várias réplicas, o contador precisa
+// ser compartilhado (RedisQuotaCounter).
+type QuotaCounter interface {
+	Increment(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	Get(ctx context.Context, key string) (int64, error)
+}
+
+// RedisQuotaCounter contador compartilhado entre as réplicas
+type RedisQuotaCounter struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisQuotaCounter cria o contador sobre o cliente Redis
+func NewRedisQuotaCounter(client *redis.Client) *RedisQuotaCounter {
+	return &RedisQuotaCounter{client: client, prefix: "app-busca-search:quota:"}
+}
+
+// Increment soma uma chamada; a chave expira após ttl a partir da primeira chamada
+func (c *RedisQuotaCounter) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	count, err := c.client.Incr(ctx, c.prefix+key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if count == 1 {
+		c.client.Expire(ctx, c.prefix+key, ttl)
+	}
+	return count, nil
+}
+
+// Get retorna as chamadas contadas
+func (c *RedisQuotaCounter) Get(ctx context.Context, key string) (int64, error) {
+	count, err := c.client.Get(ctx, c.prefix+key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return count, err
+}
+
+// LocalQuotaCounter contador em memória, por réplica. Usado sem Redis.
+type LocalQuotaCounter struct {
+	mu     sync.Mutex
+	counts map[string]localCount
+}
+
+type localCount struct {
+	value   int64
+	expires time.Time
+}
+
+// NewLocalQuotaCounter cria o contador em memória
+func NewLocalQuotaCounter() *LocalQuotaCounter {
+	return &LocalQuotaCounter{counts: make(map[string]localCount)}
+}
+
+// Increment soma uma chamada, removendo os contadores expirados
+func (c *LocalQuotaCounter) Increment(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, count := range c.counts {
+		if !count.expires.After(now) {
+			delete(c.counts, k)
+		}
+	}
+
+	count, ok := c.counts[key]
+	if !ok {
+		count.expires = now.Add(ttl)
+	}
+	count.value++
+	c.counts[key] = count
+	return count.value, nil
+}
+
+// Get retorna as chamadas contadas
+func (c *LocalQuotaCounter) Get(_ context.Context, key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	count, ok := c.counts[key]
+	if !ok || !count.expires.After(time.Now()) {
+		return 0, nil
+	}
+	return count.value, nil
+}
+
+// QuotaDecision resultado da verificação de quota de uma chamada
+type QuotaDecision struct {
+	Limit     int
+	Burst     int
+	Used      int64
+	Remaining int64
+	ResetAt   time.Time
+	Warning   string // vazio fora da faixa de alerta
+	Blocked   bool   // limite e burst esgotados
+}
+
+// APIKeyQuotas aplica quotas diárias flexíveis às API keys: acima de warningPercent da quota as
+// respostas trazem alerta, acima da quota a key ainda pode usar o burst (burst_percent da quota)
+// e só depois as chamadas são recusadas até a virada do dia. Quotas personalizadas e aumentos
+// temporários ficam na collection api_key_quotas.
+type APIKeyQuotas struct {
+	client         *typesense.Client
+	counter        QuotaCounter
+	defaultQuota   int
+	burstPercent   int
+	warningPercent int
+
+	mu     sync.RWMutex
+	quotas map[string]models.APIKeyQuota // chave: ID público da key
+}
+
+// NewAPIKeyQuotas cria as quotas com a quota diária padrão das keys sem quota personalizada
+// (0 = sem quota)
+func NewAPIKeyQuotas(client *typesense.Client, counter QuotaCounter, defaultQuota, burstPercent, warningPercent int) *APIKeyQuotas {
+	return &APIKeyQuotas{
+		client:         client,
+		counter:        counter,
+		defaultQuota:   defaultQuota,
+		burstPercent:   burstPercent,
+		warningPercent: warningPercent,
+		quotas:         make(map[string]models.APIKeyQuota),
+	}
+}
+
+// Check conta a chamada da key e decide se ela é atendida; nil para keys sem quota. Falhas no
+// contador liberam a chamada.
+func (q *APIKeyQuotas) Check(ctx context.Context, keyID string) *QuotaDecision {
+	now := time.Now()
+	quota := q.quota(keyID)
+	limit, _ := quotaLimit(quota, now)
+	if limit == 0 {
+		return nil
+	}
+
+	day, reset := quotaDay(now)
+	used, err := q.counter.Increment(ctx, keyID+":"+day, reset.Sub(now)+time.Hour)
+	if err != nil {
+		log.Printf("[Quotas] Erro ao contar chamada da key %s: %v", keyID, err)
+		return nil
+	}
+	return evaluateQuota(quota, used, q.warningPercent, now)
+}
+
+// Status retorna a quota vigente e o consumo do dia da key
+func (q *APIKeyQuotas) Status(ctx context.Context, keyID, tenantID string) (*models.APIKeyQuotaStatus, error) {
+	now := time.Now()
+	quota := q.quota(keyID)
+	day, reset := quotaDay(now)
+	used, err := q.counter.Get(ctx, keyID+":"+day)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar consumo da key: %w", err)
+	}
+
+	limit, boost := quotaLimit(quota, now)
+	status := &models.APIKeyQuotaStatus{
+		APIKeyQuota: quota,
+		Tenant:      tenantID,
+		Boost:       boost,
+		Limit:       limit,
+		Burst:       limit * quota.BurstPercent / 100,
+		Used:        used,
+		Remaining:   max(int64(limit)-used, 0),
+		ResetAt:     reset.Unix(),
+	}
+	return status, nil
+}
+
+// SetQuota define a quota personalizada da key, mantendo os aumentos vigentes
+func (q *APIKeyQuotas) SetQuota(ctx context.Context, keyID string, req models.APIKeyQuotaRequest, user string) (*models.APIKeyQuota, error) {
+	quota := q.quota(keyID)
+	quota.DailyQuota = req.DailyQuota
+	quota.BurstPercent = req.BurstPercent
+	quota.Custom = true
+	return q.save(ctx, quota, user)
+}
+
+// GrantBoost concede um aumento temporário da quota da key
+func (q *APIKeyQuotas) GrantBoost(ctx context.Context, keyID string, req models.APIKeyQuotaBoostRequest, user string) (*models.APIKeyQuota, error) {
+	now := time.Now()
+	quota := q.quota(keyID)
+	quota.Boosts = append(activeBoosts(quota.Boosts, now), models.APIKeyQuotaBoost{
+		ID:        uuid.New().String(),
+		Extra:     req.Extra,
+		Reason:    req.Reason,
+		GrantedBy: user,
+		CreatedAt: now.Unix(),
+		ExpiresAt: now.Add(time.Duration(req.DurationHours) * time.Hour).Unix(),
+	})
+	return q.save(ctx, quota, user)
+}
+
+// RevokeBoost encerra um aumento de quota antes do vencimento
+func (q *APIKeyQuotas) RevokeBoost(ctx context.Context, keyID, boostID, user string) (*models.APIKeyQuota, error) {
+	quota := q.quota(keyID)
+	boosts := make([]models.APIKeyQuotaBoost, 0, len(quota.Boosts))
+	for _, boost := range quota.Boosts {
+		if boost.ID != boostID {
+			boosts = append(boosts, boost)
+		}
+	}
+	if len(boosts) == len(quota.Boosts) {
+		return nil, ErrQuotaBoostNotFound
+	}
+	quota.Boosts = activeBoosts(boosts, time.Now())
+	return q.save(ctx, quota, user)
+}
+
+// quota retorna a quota da key: a personalizada, se houver, ou a padrão
+func (q *APIKeyQuotas) quota(keyID string) models.APIKeyQuota {
+	q.mu.RLock()
+	quota, ok := q.quotas[keyID]
+	q.mu.RUnlock()
+	if !ok {
+		quota = models.APIKeyQuota{KeyID: keyID, DailyQuota: q.defaultQuota, BurstPercent: q.burstPercent}
+	} else if !quota.Custom {
+		quota.DailyQuota = q.defaultQuota
+		quota.BurstPercent = q.burstPercent
+	}
+	quota.Boosts = append([]models.APIKeyQuotaBoost{}, quota.Boosts...)
+	return quota
+}
+
+// save persiste a quota da key para as demais réplicas
+func (q *APIKeyQuotas) save(ctx context.Context, quota models.APIKeyQuota, user string) (*models.APIKeyQuota, error) {
+	if err := q.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+	quota.UpdatedAt = time.Now().Unix()
+	quota.UpdatedBy = user
+
+	doc := map[string]interface{}{
+		"id":            quota.KeyID,
+		"key_id":        quota.KeyID,
+		"daily_quota":   quota.DailyQuota,
+		"burst_percent": quota.BurstPercent,
+		"custom":        quota.Custom,
+		"boosts":        quota.Boosts,
+		"updated_at":    quota.UpdatedAt,
+		"updated_by":    quota.UpdatedBy,
+	}
+	if _, err := q.client.Collection(APIKeyQuotasCollection).Documents().Upsert(ctx, doc, &api.DocumentIndexParameters{}); err != nil {
+		return nil, fmt.Errorf("erro ao salvar quota da key %s: %w", quota.KeyID, err)
+	}
+
+	q.mu.Lock()
+	q.quotas[quota.KeyID] = quota
+	q.mu.Unlock()
+	return &quota, nil
+}
+
+// Reload carrega as quotas persistidas, substituindo as em memória
+func (q *APIKeyQuotas) Reload(ctx context.Context) error {
+	quotas := make(map[string]models.APIKeyQuota)
+	for page := 1; ; page++ {
+		result, err := q.client.Collection(APIKeyQuotasCollection).Documents().Search(ctx, &api.SearchCollectionParams{
+			Q:       pointer.String("*"),
+			Page:    pointer.Int(page),
+			PerPage: pointer.Int(250),
+		})
+		if err != nil {
+			if IsNotFoundError(err) {
+				break
+			}
+			return fmt.Errorf("erro ao carregar quotas: %w", err)
+		}
+		if result.Hits == nil || len(*result.Hits) == 0 {
+			break
+		}
+
+		for _, hit := range *result.Hits {
+			if hit.Document == nil {
+				continue
+			}
+			data, _ := json.Marshal(*hit.Document)
+			var quota models.APIKeyQuota
+			if err := json.Unmarshal(data, &quota); err != nil {
+				log.Printf("[Quotas] Quota ignorada: %v", err)
+				continue
+			}
+			quotas[quota.KeyID] = quota
+		}
+		if len(*result.Hits) < 250 {
+			break
+		}
+	}
+
+	q.mu.Lock()
+	q.quotas = quotas
+	q.mu.Unlock()
+	return nil
+}
+
+// StartRefreshRoutine recarrega periodicamente as quotas até o cancelamento de ctx,
+// propagando alterações feitas em outras réplicas
+func (q *APIKeyQuotas) StartRefreshRoutine(ctx context.Context, interval time.Duration) {
+	startReloadLoop(ctx, "Quotas", interval, q.Reload)
+}
+
+// ensureCollection garante que a collection api_key_quotas existe
+func (q *APIKeyQuotas) ensureCollection(ctx context.Context) error {
+	_, err := q.client.Collection(APIKeyQuotasCollection).Retrieve(ctx)
+	if err == nil {
+		return nil
+	}
+
+	schema := &api.CollectionSchema{
+		Name: APIKeyQuotasCollection,
+		Fields: []api.Field{
+			{Name: "key_id", Type: "string"},
+			{Name: "daily_quota", Type: "int32", Facet: pointer.False()},
+			{Name: "burst_percent", Type: "int32", Facet: pointer.False()},
+			{Name: "custom", Type: "bool", Facet: pointer.False()},
+			{Name: "boosts", Type: "object[]", Optional: pointer.True(), Index: pointer.False()},
+			{Name: "updated_at", Type: "int64", Facet: pointer.False()},
+			{Name: "updated_by", Type: "string", Optional: pointer.True(), Index: pointer.False()},
+		},
+		DefaultSortingField: pointer.String("updated_at"),
+		EnableNestedFields:  pointer.True(),
+	}
+
+	if _, err := q.client.Collections().Create(ctx, schema); err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %w", APIKeyQuotasCollection, err)
+	}
+	return nil
+}
+
+// activeBoosts retorna os aumentos ainda vigentes
+func activeBoosts(boosts []models.APIKeyQuotaBoost, now time.Time) []models.APIKeyQuotaBoost {
+	active := make([]models.APIKeyQuotaBoost, 0, len(boosts))
+	for _, boost := range boosts {
+		if boost.ExpiresAt > now.Unix() {
+			active = append(active, boost)
+		}
+	}
+	return active
+}
+
+// quotaLimit retorna o limite do dia (quota + aumentos vigentes) e a soma dos aumentos. Keys
+// sem quota não têm limite, mesmo com aumentos.
+func quotaLimit(quota models.APIKeyQuota, now time.Time) (limit, boost int) {
+	if quota.DailyQuota == 0 {
+		return 0, 0
+	}
+	for _, b := range activeBoosts(quota.Boosts, now) {
+		boost += b.Extra
+	}
+	return quota.DailyQuota + boost, boost
+}
+
+// evaluateQuota decide a chamada de número used no dia
+func evaluateQuota(quota models.APIKeyQuota, used int64, warningPercent int, now time.Time) *QuotaDecision {
+	limit, _ := quotaLimit(quota, now)
+	_, reset := quotaDay(now)
+	decision := &QuotaDecision{
+		Limit:     limit,
+		Burst:     limit * quota.BurstPercent / 100,
+		Used:      used,
+		Remaining: max(int64(limit)-used, 0),
+		ResetAt:   reset,
+	}
+
+	switch {
+	case used > int64(limit+decision.Burst):
+		decision.Blocked = true
+		decision.Warning = "quota diária e burst esgotados"
+	case used > int64(limit):
+		decision.Warning = fmt.Sprintf("quota diária excedida; usando o burst (%d de %d chamadas)", used-int64(limit), decision.Burst)
+	case used*100 >= int64(limit*warningPercent):
+		decision.Warning = fmt.Sprintf("%d%% da quota diária utilizada", used*100/int64(limit))
+	}
+	return decision
+}
+
+// quotaDay retorna o dia (horário de Brasília) e o início do dia seguinte, quando as quotas
+// são renovadas
+func quotaDay(now time.Time) (string, time.Time) {
+	local := now.In(brasilia)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, brasilia)
+	return start.Format("2006-01-02"), start.AddDate(0, 0, 1)
+}
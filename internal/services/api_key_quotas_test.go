@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestEvaluateQuota(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, brasilia)
+	quota := models.APIKeyQuota{KeyID: "key_x", DailyQuota: 100, BurstPercent: 20}
+
+	tests := []struct {
+		used      int64
+		remaining int64
+		warning   bool
+		blocked   bool
+	}{
+		{50, 50, false, false},
+		{80, 20, true, false}, // faixa de alerta
+		{110, 0, true, false}, // usando o burst
+		{120, 0, true, false}, // último do burst
+		{121, 0, true, true},  // quota e burst esgotados
+	}
+	for _, tt := range tests {
+		d := evaluateQuota(quota, tt.used, 80, now)
+		if d.Limit != 100 || d.Burst != 20 || d.Remaining != tt.remaining || (d.Warning != "") != tt.warning || d.Blocked != tt.blocked {
+			t.Errorf("used=%d: decisão = %+v", tt.used, d)
+		}
+	}
+
+	d := evaluateQuota(quota, 1, 80, now)
+	if want := time.Date(2026, 3, 11, 0, 0, 0, 0, brasilia); !d.ResetAt.Equal(want) {
+		t.Errorf("reset = %v, esperado %v", d.ResetAt, want)
+	}
+}
+
+func TestQuotaLimitBoosts(t *testing.T) {
+	now := time.Now()
+	quota := models.APIKeyQuota{DailyQuota: 1000, Boosts: []models.APIKeyQuotaBoost{
+		{ID: "a", Extra: 500, ExpiresAt: now.Add(time.Hour).Unix()},
+		{ID: "b", Extra: 300, ExpiresAt: now.Add(-time.Hour).Unix()}, // vencido
+	}}
+
+	limit, boost := quotaLimit(quota, now)
+	if limit != 1500 || boost != 500 {
+		t.Errorf("limite = %d, aumento = %d; esperado 1500 e 500", limit, boost)
+	}
+
+	// Sem quota, aumentos não criam limite
+	quota.DailyQuota = 0
+	if limit, _ := quotaLimit(quota, now); limit != 0 {
+		t.Errorf("limite sem quota = %d, esperado 0", limit)
+	}
+}
+
+func TestAPIKeyQuotasDefaults(t *testing.T) {
+	q := NewAPIKeyQuotas(nil, NewLocalQuotaCounter(), 100, 10, 80)
+	q.quotas["key_custom"] = models.APIKeyQuota{KeyID: "key_custom", DailyQuota: 5000, BurstPercent: 50, Custom: true}
+	q.quotas["key_boost"] = models.APIKeyQuota{KeyID: "key_boost", DailyQuota: 1, Boosts: []models.APIKeyQuotaBoost{{ID: "a", Extra: 10}}}
+
+	if quota := q.quota("key_default"); quota.DailyQuota != 100 || quota.BurstPercent != 10 || quota.Custom {
+		t.Errorf("quota padrão = %+v", quota)
+	}
+	if quota := q.quota("key_custom"); quota.DailyQuota != 5000 || quota.BurstPercent != 50 {
+		t.Errorf("quota personalizada = %+v", quota)
+	}
+	// Sem quota personalizada, a key com aumentos segue a quota padrão
+	if quota := q.quota("key_boost"); quota.DailyQuota != 100 || len(quota.Boosts) != 1 {
+		t.Errorf("quota com aumento = %+v", quota)
+	}
+
+	for i := 0; i < 79; i++ {
+		q.Check(context.Background(), "key_default")
+	}
+	if d := q.Check(context.Background(), "key_default"); d == nil || d.Used != 80 || d.Warning == "" {
+		t.Errorf("80ª chamada = %+v", d)
+	}
+
+	noQuota := NewAPIKeyQuotas(nil, NewLocalQuotaCounter(), 0, 10, 80)
+	if d := noQuota.Check(context.Background(), "key_default"); d != nil {
+		t.Errorf("key sem quota = %+v, esperado nil", d)
+	}
+}
+
+func TestLocalQuotaCounter(t *testing.T) {
+	counter := NewLocalQuotaCounter()
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		if n, _ := counter.Increment(ctx, "k", time.Hour); n != int64(i) {
+			t.Errorf("incremento %d = %d", i, n)
+		}
+	}
+	if n, _ := counter.Get(ctx, "k"); n != 3 {
+		t.Errorf("contagem = %d, esperado 3", n)
+	}
+
+	counter.Increment(ctx, "expirado", -time.Second)
+	if n, _ := counter.Get(ctx, "expirado"); n != 0 {
+		t.Errorf("contador expirado = %d, esperado 0", n)
+	}
+}
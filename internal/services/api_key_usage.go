@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/jobs"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+const (
+	APIKeyUsageCollection = "api_key_usage"
+
+	// apiKeyUsageMaxPending limita os eventos acumulados entre flushes
+	apiKeyUsageMaxPending = 50000
+	// apiKeyUsageMaxEvents limita os eventos lidos para montar o relatório
+	apiKeyUsageMaxEvents = 200000
+)
+
+// APIKeyUsage registra as requisições feitas com API keys de tenant (rota, status e latência),
+// emitidas pelo middleware de uso. Os eventos são acumulados em memória e persistidos
+// periodicamente na collection api_key_usage.
+type APIKeyUsage struct {
+	client *typesense.Client
+
+	mu      sync.Mutex
+	pending []models.APIKeyUsageEvent
+}
+
+// NewAPIKeyUsage cria o registro de uso das API keys
+func NewAPIKeyUsage(client *typesense.Client) *APIKeyUsage {
+	return &APIKeyUsage{client: client}
+}
+
+// Record registra uma requisição; descartada se o limite de eventos pendentes foi atingido
+func (u *APIKeyUsage) Record(event models.APIKeyUsageEvent) {
+	if u == nil {
+		return
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if len(u.pending) >= apiKeyUsageMaxPending {
+		return
+	}
+	u.pending = append(u.pending, event)
+}
+
+// Flush persiste os eventos acumulados desde o último flush
+func (u *APIKeyUsage) Flush(ctx context.Context) error {
+	u.mu.Lock()
+	pending := u.pending
+	u.pending = nil
+	u.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if err := u.ensureCollection(ctx); err != nil {
+		return err
+	}
+
+	for start := 0; start < len(pending); start += 500 {
+		end := min(start+500, len(pending))
+
+		docs := make([]interface{}, 0, end-start)
+		for _, event := range pending[start:end] {
+			docs = append(docs, event)
+		}
+		if _, err := u.client.Collection(APIKeyUsageCollection).Documents().Import(ctx, docs, &api.ImportDocumentsParams{
+			Action: pointer.Any(api.Create),
+		}); err != nil {
+			return fmt.Errorf("erro ao persistir uso das API keys: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// StartRoutines inicia o flush periódico dos eventos e agenda a remoção diária dos eventos
+// mais antigos que retentionDays (job api-key-usage-cleanup)
+func (u *APIKeyUsage) StartRoutines(runner *jobs.Runner, flushInterval time.Duration, retentionDays int) error {
+	go func() {
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			if err := u.Flush(ctx); err != nil {
+				log.Printf("[APIKeyUsage] Erro ao persistir eventos: %v", err)
+			}
+			cancel()
+		}
+	}()
+
+	return runner.Register("api-key-usage-cleanup", "45 4 * * *", 10*time.Minute, func(ctx context.Context) error {
+		if err := u.ensureCollection(ctx); err != nil {
+			return err
+		}
+		before := time.Now().AddDate(0, 0, -retentionDays).Unix()
+		_, err := u.client.Collection(APIKeyUsageCollection).Documents().Delete(ctx, &api.DeleteDocumentsParams{
+			FilterBy: pointer.String(fmt.Sprintf("timestamp:<%d", before)),
+		})
+		return err
+	})
+}
+
+// ParseUsageDay interpreta uma data AAAA-MM-DD no horário de Brasília; vazia é o dia atual
+func ParseUsageDay(raw string) (time.Time, error) {
+	if raw == "" {
+		now := time.Now().In(brasilia)
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, brasilia), nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", raw, brasilia)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("data inválida %q: use AAAA-MM-DD", raw)
+	}
+	return day, nil
+}
+
+// Usage retorna o uso da key entre os dias from e to (inclusivo, horário de Brasília)
+func (u *APIKeyUsage) Usage(ctx context.Context, keyID, tenantID string, from, to time.Time) (*models.APIKeyUsageReport, error) {
+	if err := u.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+
+	events, truncated, err := u.fetchEvents(ctx, keyID, from.Unix(), to.AddDate(0, 0, 1).Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	report := buildAPIKeyUsageReport(events, from, to)
+	report.KeyID = keyID
+	report.Tenant = tenantID
+	report.Truncated = truncated
+	report.GeneratedAt = time.Now().Unix()
+	return report, nil
+}
+
+// fetchEvents busca os eventos da key no intervalo [since, until)
+func (u *APIKeyUsage) fetchEvents(ctx context.Context, keyID string, since, until int64) ([]models.APIKeyUsageEvent, bool, error) {
+	var events []models.APIKeyUsageEvent
+	perPage := 250
+	filter := fmt.Sprintf("key_id:=`%s` && timestamp:>=%d && timestamp:<%d", keyID, since, until)
+
+	for page := 1; ; page++ {
+		if len(events) >= apiKeyUsageMaxEvents {
+			return events, true, nil
+		}
+
+		result, err := u.client.Collection(APIKeyUsageCollection).Documents().Search(ctx, &api.SearchCollectionParams{
+			Q:        pointer.String("*"),
+			FilterBy: pointer.String(filter),
+			SortBy:   pointer.String("timestamp:asc"),
+			Page:     pointer.Int(page),
+			PerPage:  pointer.Int(perPage),
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("erro ao buscar uso das API keys: %w", err)
+		}
+		if result.Hits == nil || len(*result.Hits) == 0 {
+			break
+		}
+
+		for _, hit := range *result.Hits {
+			if hit.Document == nil {
+				continue
+			}
+			doc := *hit.Document
+			events = append(events, models.APIKeyUsageEvent{
+				KeyID:     getString(doc, "key_id"),
+				Tenant:    getString(doc, "tenant"),
+				Route:     getString(doc, "route"),
+				Status:    int(getInt64(doc, "status")),
+				LatencyMs: getInt64(doc, "latency_ms"),
+				Timestamp: getInt64(doc, "timestamp"),
+			})
+		}
+		if len(*result.Hits) < perPage {
+			break
+		}
+	}
+
+	return events, false, nil
+}
+
+// routeStats acumula as chamadas de uma rota
+type routeStats struct {
+	calls, clientErrors, serverErrors int
+	latencies                         []int64
+}
+
+func (s *routeStats) add(event models.APIKeyUsageEvent) {
+	s.calls++
+	switch {
+	case event.Status >= 500:
+		s.serverErrors++
+	case event.Status >= 400:
+		s.clientErrors++
+	}
+	s.latencies = append(s.latencies, event.LatencyMs)
+}
+
+// buildAPIKeyUsageReport agrega os eventos por rota no período e por dia (horário de Brasília).
+// Todos os dias entre from e to aparecem na série, inclusive os sem chamadas.
+func buildAPIKeyUsageReport(events []models.APIKeyUsageEvent, from, to time.Time) *models.APIKeyUsageReport {
+	report := &models.APIKeyUsageReport{
+		From:   from.Format("2006-01-02"),
+		To:     to.Format("2006-01-02"),
+		Routes: []models.APIKeyRouteUsage{},
+		Days:   []models.APIKeyDailyUsage{},
+	}
+
+	total := make(map[string]*routeStats)
+	daily := make(map[string]map[string]*routeStats)
+	for _, event := range events {
+		day := time.Unix(event.Timestamp, 0).In(brasilia).Format("2006-01-02")
+		if daily[day] == nil {
+			daily[day] = make(map[string]*routeStats)
+		}
+		for _, stats := range []map[string]*routeStats{total, daily[day]} {
+			if stats[event.Route] == nil {
+				stats[event.Route] = &routeStats{}
+			}
+			stats[event.Route].add(event)
+		}
+	}
+
+	report.Routes = summarizeRoutes(total)
+	report.Calls, report.ErrorRate = totalCalls(report.Routes)
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		usage := models.APIKeyDailyUsage{Date: date, Routes: summarizeRoutes(daily[date])}
+		usage.Calls, usage.ErrorRate = totalCalls(usage.Routes)
+		report.Days = append(report.Days, usage)
+	}
+
+	return report
+}
+
+// summarizeRoutes calcula as taxas de erro e os percentis de latência, ordenando as rotas por
+// volume de chamadas
+func summarizeRoutes(stats map[string]*routeStats) []models.APIKeyRouteUsage {
+	routes := make([]models.APIKeyRouteUsage, 0, len(stats))
+	for route, s := range stats {
+		sort.Slice(s.latencies, func(a, b int) bool { return s.latencies[a] < s.latencies[b] })
+		routes = append(routes, models.APIKeyRouteUsage{
+			Route:        route,
+			Calls:        s.calls,
+			ClientErrors: s.clientErrors,
+			ServerErrors: s.serverErrors,
+			ErrorRate:    errorRate(s.clientErrors+s.serverErrors, s.calls),
+			LatencyP50Ms: latencyPercentile(s.latencies, 50),
+			LatencyP95Ms: latencyPercentile(s.latencies, 95),
+			LatencyP99Ms: latencyPercentile(s.latencies, 99),
+		})
+	}
+	sort.Slice(routes, func(a, b int) bool {
+		if routes[a].Calls != routes[b].Calls {
+			return routes[a].Calls > routes[b].Calls
+		}
+		return routes[a].Route < routes[b].Route
+	})
+	return routes
+}
+
+// totalCalls soma as chamadas das rotas e calcula a taxa de erro geral
+func totalCalls(routes []models.APIKeyRouteUsage) (int, float64) {
+	calls, errors := 0, 0
+	for _, route := range routes {
+		calls += route.Calls
+		errors += route.ClientErrors + route.ServerErrors
+	}
+	return calls, errorRate(errors, calls)
+}
+
+func errorRate(errors, calls int) float64 {
+	if calls == 0 {
+		return 0
+	}
+	return math.Round(float64(errors)/float64(calls)*10000) / 10000
+}
+
+// latencyPercentile percentil pelo método nearest-rank sobre latências ordenadas
+func latencyPercentile(sorted []int64, p int) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(float64(p) / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+func (u *APIKeyUsage) ensureCollection(ctx context.Context) error {
+	_, err := u.client.Collection(APIKeyUsageCollection).Retrieve(ctx)
+	if err == nil {
+		return nil
+	}
+
+	schema := &api.CollectionSchema{
+		Name: APIKeyUsageCollection,
+		Fields: []api.Field{
+			{Name: "key_id", Type: "string", Facet: pointer.True()},
+			{Name: "tenant", Type: "string", Facet: pointer.True()},
+			{Name: "route", Type: "string", Facet: pointer.True()},
+			{Name: "status", Type: "int32", Facet: pointer.False()},
+			{Name: "latency_ms", Type: "int64", Facet: pointer.False()},
+			{Name: "timestamp", Type: "int64", Facet: pointer.False()},
+		},
+		DefaultSortingField: pointer.String("timestamp"),
+	}
+
+	if _, err := u.client.Collections().Create(ctx, schema); err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %w", APIKeyUsageCollection, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestLatencyPercentile(t *testing.T) {
+	latencies := []int64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}
+	tests := []struct {
+		p    int
+		want int64
+	}{
+		{50, 50},
+		{95, 100},
+		{99, 100},
+		{10, 10},
+	}
+	for _, tt := range tests {
+		if got := latencyPercentile(latencies, tt.p); got != tt.want {
+			t.Errorf("p%d = %d, esperado %d", tt.p, got, tt.want)
+		}
+	}
+	if got := latencyPercentile(nil, 95); got != 0 {
+		t.Errorf("sem latências = %d, esperado 0", got)
+	}
+}
+
+func TestBuildAPIKeyUsageReport(t *testing.T) {
+	from, _ := ParseUsageDay("2026-03-01")
+	to, _ := ParseUsageDay("2026-03-03")
+	at := func(day, hour int) int64 {
+		return time.Date(2026, 3, day, hour, 0, 0, 0, brasilia).Unix()
+	}
+
+	events := []models.APIKeyUsageEvent{
+		{Route: "GET /api/v1/search", Status: 200, LatencyMs: 40, Timestamp: at(1, 10)},
+		{Route: "GET /api/v1/search", Status: 200, LatencyMs: 60, Timestamp: at(1, 11)},
+		{Route: "GET /api/v1/search", Status: 500, LatencyMs: 900, Timestamp: at(1, 23)},
+		{Route: "GET /api/v1/search/:id", Status: 404, LatencyMs: 5, Timestamp: at(3, 0)},
+	}
+
+	report := buildAPIKeyUsageReport(events, from, to)
+
+	if report.From != "2026-03-01" || report.To != "2026-03-03" || report.Calls != 4 || report.ErrorRate != 0.5 {
+		t.Errorf("totais = %+v", report)
+	}
+	if len(report.Routes) != 2 {
+		t.Fatalf("rotas = %+v", report.Routes)
+	}
+	search := report.Routes[0]
+	if search.Route != "GET /api/v1/search" || search.Calls != 3 || search.ServerErrors != 1 || search.ClientErrors != 0 {
+		t.Errorf("rota de busca = %+v", search)
+	}
+	if search.LatencyP50Ms != 60 || search.LatencyP95Ms != 900 || search.ErrorRate != 0.3333 {
+		t.Errorf("latência/erros da busca = %+v", search)
+	}
+
+	// Todos os dias do período aparecem, inclusive os sem chamadas
+	if len(report.Days) != 3 {
+		t.Fatalf("dias = %+v", report.Days)
+	}
+	if report.Days[0].Calls != 3 || report.Days[1].Calls != 0 || len(report.Days[1].Routes) != 0 || report.Days[2].Calls != 1 {
+		t.Errorf("série diária = %+v", report.Days)
+	}
+	if report.Days[2].Routes[0].ClientErrors != 1 || report.Days[2].ErrorRate != 1 {
+		t.Errorf("dia 3 = %+v", report.Days[2])
+	}
+}
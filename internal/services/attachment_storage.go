@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/auth/credentials"
+	"cloud.google.com/go/auth/httptransport"
+)
+
+const (
+	gcsHost           = "storage.googleapis.com"
+	gcsReadWriteScope = "https://www.googleapis.com/auth/devstorage.read_write"
+)
+
+// GCSStorage armazena anexos de serviços em um bucket do Google Cloud Storage e gera
+// URLs assinadas (V4) para download, sem expor o bucket publicamente
+type GCSStorage struct {
+	bucket     string
+	httpClient *http.Client
+	signer     *gcsSigner
+}
+
+// gcsSigner assina URLs com a chave privada da service account
+type gcsSigner struct {
+	email string
+	key   *rsa.PrivateKey
+}
+
+// NewGCSStorage cria o storage para o bucket informado. credentialsFile é o JSON de uma
+// service account; sem ele são usadas as credenciais padrão do ambiente, mas URLs
+// assinadas ficam indisponíveis (exigem a chave privada).
+func NewGCSStorage(bucket, credentialsFile string) (*GCSStorage, error) {
+	if bucket == "" {
+		return nil, errors.New("bucket não configurado")
+	}
+
+	creds, err := credentials.DetectDefault(&credentials.DetectOptions{
+		Scopes:          []string{gcsReadWriteScope},
+		CredentialsFile: credentialsFile,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar credenciais do GCS: %w", err)
+	}
+
+	httpClient, err := httptransport.NewClient(&httptransport.Options{Credentials: creds})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar cliente HTTP do GCS: %w", err)
+	}
+	httpClient.Timeout = 2 * time.Minute
+
+	storage := &GCSStorage{bucket: bucket, httpClient: httpClient}
+	if credentialsFile != "" {
+		signer, err := loadGCSSigner(credentialsFile)
+		if err != nil {
+			return nil, err
+		}
+		storage.signer = signer
+	}
+
+	return storage, nil
+}
+
+// Upload grava o objeto no bucket
+func (s *GCSStorage) Upload(ctx context.Context, objectName, contentType string, body io.Reader) error {
+	endpoint := fmt.Sprintf("https://%s/upload/storage/v1/b/%s/o?uploadType=media&name=%s",
+		gcsHost, url.PathEscape(s.bucket), url.QueryEscape(objectName))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	return s.do(req)
+}
+
+// Delete remove o objeto do bucket. Objetos inexistentes não são considerados erro.
+func (s *GCSStorage) Delete(ctx context.Context, objectName string) error {
+	endpoint := fmt.Sprintf("https://%s/storage/v1/b/%s/o/%s",
+		gcsHost, url.PathEscape(s.bucket), url.PathEscape(objectName))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := s.do(req); err != nil && !strings.Contains(err.Error(), "404") {
+		return err
+	}
+	return nil
+}
+
+// SignedURL gera uma URL de download válida pelo período informado
+func (s *GCSStorage) SignedURL(objectName, filename string, expires time.Duration) (string, error) {
+	if s.signer == nil {
+		return "", errors.New("URLs assinadas exigem GCS_CREDENTIALS_FILE com a chave da service account")
+	}
+	return s.signer.sign(s.bucket, objectName, filename, expires, time.Now())
+}
+
+func (s *GCSStorage) do(req *http.Request) error {
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao acessar o GCS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("GCS retornou status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// loadGCSSigner lê o e-mail e a chave privada do JSON da service account
+func loadGCSSigner(credentialsFile string) (*gcsSigner, error) {
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler credenciais do GCS: %w", err)
+	}
+
+	var account struct {
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+	}
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("credenciais do GCS inválidas: %w", err)
+	}
+
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return nil, errors.New("chave privada da service account ausente ou inválida")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("chave privada da service account inválida: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("chave privada da service account não é RSA")
+	}
+
+	return &gcsSigner{email: account.ClientEmail, key: key}, nil
+}
+
+// sign gera uma URL assinada V4 (GOOG4-RSA-SHA256) para GET do objeto. O filename define
+// o nome sugerido ao navegador no download.
+func (g *gcsSigner) sign(bucket, objectName, filename string, expires time.Duration, now time.Time) (string, error) {
+	now = now.UTC()
+	timestamp := now.Format("20060102T150405Z")
+	scope := now.Format("20060102") + "/auto/storage/goog4_request"
+
+	query := map[string]string{
+		"X-Goog-Algorithm":     "GOOG4-RSA-SHA256",
+		"X-Goog-Credential":    g.email + "/" + scope,
+		"X-Goog-Date":          timestamp,
+		"X-Goog-Expires":       fmt.Sprintf("%d", int(expires.Seconds())),
+		"X-Goog-SignedHeaders": "host",
+	}
+	if filename != "" {
+		query["response-content-disposition"] = fmt.Sprintf("attachment; filename=%q", filename)
+	}
+	canonicalQuery := canonicalQueryString(query)
+
+	path := "/" + bucket + "/" + escapeObjectPath(objectName)
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		path,
+		canonicalQuery,
+		"host:" + gcsHost + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"GOOG4-RSA-SHA256",
+		timestamp,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	digest := sha256.Sum256([]byte(stringToSign))
+	signature, err := rsa.SignPKCS1v15(nil, g.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("erro ao assinar URL: %w", err)
+	}
+
+	return fmt.Sprintf("https://%s%s?%s&X-Goog-Signature=%s", gcsHost, path, canonicalQuery, hex.EncodeToString(signature)), nil
+}
+
+// canonicalQueryString ordena e codifica os parâmetros conforme RFC 3986
+func canonicalQueryString(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = rfc3986Escape(key) + "=" + rfc3986Escape(params[key])
+	}
+	return strings.Join(parts, "&")
+}
+
+// escapeObjectPath codifica o nome do objeto preservando as barras
+func escapeObjectPath(objectName string) string {
+	segments := strings.Split(objectName, "/")
+	for i, segment := range segments {
+		segments[i] = rfc3986Escape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func rfc3986Escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGCSSignerSignedURL(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := &gcsSigner{email: "busca@projeto.iam.gserviceaccount.com", key: key}
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	signed, err := signer.sign("anexos", "services/abc/1.pdf", "Formulário IPTU.pdf", 15*time.Minute, now)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	parsed, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("URL inválida: %v", err)
+	}
+	if parsed.Host != gcsHost || parsed.Path != "/anexos/services/abc/1.pdf" {
+		t.Errorf("destino inesperado: %s%s", parsed.Host, parsed.Path)
+	}
+
+	query := parsed.Query()
+	if query.Get("X-Goog-Credential") != "busca@projeto.iam.gserviceaccount.com/20250310/auto/storage/goog4_request" {
+		t.Errorf("credencial inesperada: %s", query.Get("X-Goog-Credential"))
+	}
+	if query.Get("X-Goog-Expires") != "900" || query.Get("X-Goog-Date") != "20250310T120000Z" {
+		t.Errorf("validade inesperada: %s %s", query.Get("X-Goog-Expires"), query.Get("X-Goog-Date"))
+	}
+	if len(query.Get("X-Goog-Signature")) != 512 {
+		t.Errorf("assinatura com tamanho inesperado: %d", len(query.Get("X-Goog-Signature")))
+	}
+	if strings.Contains(parsed.RawQuery, "+") {
+		t.Error("espaços devem ser codificados como %20")
+	}
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/genai"
+)
+
+// maxAttachmentTextLength limite de caracteres extraídos por anexo
+const maxAttachmentTextLength = 50000
+
+// Situação da extração de texto de um anexo
+const (
+	AttachmentTextPending     = "pending"
+	AttachmentTextExtracted   = "extracted"
+	AttachmentTextUnsupported = "unsupported"
+	AttachmentTextFailed      = "failed"
+)
+
+const attachmentOCRPrompt = `Transcreva integralmente o texto deste documento, na ordem de leitura.
+Inclua títulos, campos de formulário e tabelas (uma linha por registro).
+Responda apenas com o texto transcrito, sem comentários.`
+
+// AttachmentTextExtractor extrai o texto dos anexos para indexação: arquivos de texto são
+// lidos diretamente e PDFs/imagens passam por OCR no Gemini (camada de texto ou imagem)
+type AttachmentTextExtractor struct {
+	geminiClient *genai.Client
+	model        string
+}
+
+// NewAttachmentTextExtractor cria o extrator. Sem cliente Gemini, apenas arquivos de texto são suportados.
+func NewAttachmentTextExtractor(geminiClient *genai.Client, model string) *AttachmentTextExtractor {
+	return &AttachmentTextExtractor{geminiClient: geminiClient, model: model}
+}
+
+// Supports indica se o tipo MIME tem extração de texto disponível
+func (e *AttachmentTextExtractor) Supports(mimeType string) bool {
+	switch {
+	case strings.HasPrefix(mimeType, "text/"):
+		return true
+	case mimeType == "application/pdf", strings.HasPrefix(mimeType, "image/"):
+		return e.geminiClient != nil
+	default:
+		return false
+	}
+}
+
+// Extract retorna o texto do arquivo, limitado a maxAttachmentTextLength caracteres
+func (e *AttachmentTextExtractor) Extract(ctx context.Context, mimeType string, data []byte) (string, error) {
+	if !e.Supports(mimeType) {
+		return "", fmt.Errorf("extração de texto não suportada para %s", mimeType)
+	}
+
+	var text string
+	if strings.HasPrefix(mimeType, "text/") {
+		if !utf8.Valid(data) {
+			return "", fmt.Errorf("arquivo de texto não está em UTF-8")
+		}
+		text = string(data)
+	} else {
+		content := genai.NewContentFromParts([]*genai.Part{
+			genai.NewPartFromBytes(data, mimeType),
+			genai.NewPartFromText(attachmentOCRPrompt),
+		}, genai.RoleUser)
+
+		resp, err := e.geminiClient.Models.GenerateContent(ctx, e.model, []*genai.Content{content}, nil)
+		if err != nil {
+			return "", fmt.Errorf("erro no OCR do anexo: %w", err)
+		}
+		text = resp.Text()
+	}
+
+	return truncateText(strings.TrimSpace(text), maxAttachmentTextLength), nil
+}
+
+// truncateText corta o texto em no máximo limit caracteres sem quebrar runas
+func truncateText(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:limit])
+}
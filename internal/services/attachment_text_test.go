@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestAttachmentTextExtractorWithoutGemini(t *testing.T) {
+	extractor := NewAttachmentTextExtractor(nil, "")
+
+	if extractor.Supports("application/pdf") {
+		t.Error("PDF exige OCR no Gemini")
+	}
+	if !extractor.Supports("text/csv") {
+		t.Error("arquivos de texto não dependem do Gemini")
+	}
+
+	text, err := extractor.Extract(context.Background(), "text/plain", []byte("  Edital nº 12/2025\n"))
+	if err != nil || text != "Edital nº 12/2025" {
+		t.Errorf("texto inesperado: %q (%v)", text, err)
+	}
+
+	long := strings.Repeat("á", maxAttachmentTextLength+10)
+	text, _ = extractor.Extract(context.Background(), "text/plain", []byte(long))
+	if len([]rune(text)) != maxAttachmentTextLength {
+		t.Errorf("texto deveria ser truncado em %d caracteres", maxAttachmentTextLength)
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+// Situação de um serviço sazonal em relação à sua janela de disponibilidade
+const (
+	AvailabilityOpen     = ""         // Dentro da janela (ou sem janela)
+	AvailabilityUpcoming = "upcoming" // Janela ainda não abriu
+	AvailabilityClosed   = "closed"   // Janela já encerrou
+)
+
+// AvailabilityClosingEvent evento enviado ao webhook quando a janela de um serviço publicado
+// está prestes a encerrar
+const AvailabilityClosingEvent = "service.availability_closing"
+
+// ValidateAvailabilityWindow valida a janela informada pelo editor (0 = sem limite)
+func ValidateAvailabilityWindow(from, until int64) error {
+	if from > 0 && until > 0 && until <= from {
+		return errors.New("available_until deve ser posterior a available_from")
+	}
+	return nil
+}
+
+// AvailabilityState retorna a situação da janela [from, until) no instante now
+func AvailabilityState(from, until, now int64) string {
+	switch {
+	case from > 0 && now < from:
+		return AvailabilityUpcoming
+	case until > 0 && now >= until:
+		return AvailabilityClosed
+	default:
+		return AvailabilityOpen
+	}
+}
+
+// availabilityFilter restringe a busca aos serviços dentro da janela de disponibilidade
+func availabilityFilter(now int64) string {
+	return fmt.Sprintf("available_from:<=%d && (available_until:=0 || available_until:>%d)", now, now)
+}
+
+// flagAvailability sinaliza em metadata.availability os serviços fora da janela e os move para
+// o fim dos resultados, preservando a ordem relativa de relevância dentro de cada grupo
+func flagAvailability(results []*models.ServiceDocument, now int64) {
+	outOfWindow := make(map[*models.ServiceDocument]bool)
+	for _, doc := range results {
+		if doc == nil || doc.Metadata == nil {
+			continue
+		}
+		state := AvailabilityState(getInt64(doc.Metadata, "available_from"), getInt64(doc.Metadata, "available_until"), now)
+		if state != AvailabilityOpen {
+			doc.Metadata["availability"] = state
+			outOfWindow[doc] = true
+		}
+	}
+	if len(outOfWindow) == 0 {
+		return
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return !outOfWindow[results[i]] && outOfWindow[results[j]]
+	})
+}
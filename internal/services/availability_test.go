@@ -0,0 +1,70 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestAvailabilityState(t *testing.T) {
+	cases := []struct {
+		from, until, now int64
+		want             string
+	}{
+		{0, 0, 100, AvailabilityOpen},
+		{50, 150, 100, AvailabilityOpen},
+		{150, 0, 100, AvailabilityUpcoming},
+		{0, 100, 100, AvailabilityClosed},
+	}
+	for _, c := range cases {
+		if got := AvailabilityState(c.from, c.until, c.now); got != c.want {
+			t.Errorf("AvailabilityState(%d, %d, %d) = %q, esperado %q", c.from, c.until, c.now, got, c.want)
+		}
+	}
+}
+
+func TestValidateAvailabilityWindow(t *testing.T) {
+	if err := ValidateAvailabilityWindow(200, 100); err == nil {
+		t.Error("janela invertida deveria ser rejeitada")
+	}
+	if err := ValidateAvailabilityWindow(100, 0); err != nil {
+		t.Errorf("janela sem fim deveria ser aceita: %v", err)
+	}
+}
+
+func TestFlagAvailabilityDemotesOutOfWindow(t *testing.T) {
+	results := []*models.ServiceDocument{
+		{ID: "fechado", Metadata: map[string]interface{}{"available_from": float64(0), "available_until": float64(50)}},
+		{ID: "aberto", Metadata: map[string]interface{}{"available_from": float64(0), "available_until": float64(0)}},
+		{ID: "futuro", Metadata: map[string]interface{}{"available_from": float64(500), "available_until": float64(0)}},
+	}
+
+	flagAvailability(results, 100)
+
+	var order []string
+	for _, doc := range results {
+		order = append(order, doc.ID)
+	}
+	if strings.Join(order, ",") != "aberto,fechado,futuro" {
+		t.Errorf("ordem inesperada: %v", order)
+	}
+	if results[1].Metadata["availability"] != AvailabilityClosed || results[2].Metadata["availability"] != AvailabilityUpcoming {
+		t.Errorf("sinalização inesperada: %v %v", results[1].Metadata, results[2].Metadata)
+	}
+	if _, ok := results[0].Metadata["availability"]; ok {
+		t.Error("serviço dentro da janela não deveria ser sinalizado")
+	}
+}
+
+func TestBuildFilterByHidesOutOfWindow(t *testing.T) {
+	filter := buildFilterBy(t.Context(), &models.SearchRequest{})
+	if !strings.Contains(filter, "available_from:<=") {
+		t.Errorf("filtro sem janela de disponibilidade: %q", filter)
+	}
+
+	filter = buildFilterBy(t.Context(), &models.SearchRequest{IncludeOutOfWindow: true})
+	if strings.Contains(filter, "available_from") {
+		t.Errorf("include_out_of_window não deveria filtrar a janela: %q", filter)
+	}
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+// Caches inspecionáveis em /admin/cache. Os prefixos de descarte têm a forma "<cache>:<chave>".
+const (
+	CacheSearch           = "search"            // resultados de busca (chave: query normalizada)
+	CacheResponse         = "response"          // respostas dos endpoints públicos (chave: [tenant:]path?query)
+	CacheAnalysis         = "analysis"          // análises de query por LLM (chave: query)
+	CacheEmbedding        = "embedding"         // embeddings de queries (chave: sha256 do texto)
+	CacheQueryTranslation = "query_translation" // traduções de query (chave: idioma:query)
+)
+
+var (
+	// ErrCacheUnknown indica prefixo sem um cache conhecido
+	ErrCacheUnknown = errors.New("cache desconhecido")
+
+	// ErrCacheDisabled indica cache desabilitado na configuração
+	ErrCacheDisabled = errors.New("cache desabilitado")
+)
+
+type cacheBypassKey struct{}
+
+// WithCacheBypass marca o contexto para ignorar as entradas em cache: a busca é recalculada e a
+// resposta nova substitui a armazenada. Embeddings e traduções não dependem do acervo e continuam
+// sendo reaproveitados.
+func WithCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassKey{}, true)
+}
+
+// CacheBypassed indica se o contexto pede para ignorar as entradas em cache
+func CacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(cacheBypassKey{}).(bool)
+	return bypass
+}
+
+// CacheInspector reúne os contadores e o descarte seletivo dos caches de busca desta réplica
+type CacheInspector struct {
+	shared   *LRUCache
+	search   *SearchCache
+	response *ResponseCache
+}
+
+// NewCacheInspector cria o inspetor e passa a contar os acessos do cache compartilhado por tipo
+// de entrada. search e response podem ser nil (desabilitados).
+func NewCacheInspector(shared *LRUCache, search *SearchCache, response *ResponseCache) *CacheInspector {
+	shared.TrackNamespaces(CacheAnalysis, CacheEmbedding, CacheQueryTranslation)
+	return &CacheInspector{shared: shared, search: search, response: response}
+}
+
+// Stats retorna tamanho, acertos e faltas de cada cache
+func (ci *CacheInspector) Stats() *models.CacheStatsResponse {
+	response := &models.CacheStatsResponse{
+		SharedEntries:  ci.shared.Size(),
+		SharedCapacity: ci.shared.Capacity(),
+	}
+
+	searchInfo := models.CacheInfo{Name: CacheSearch}
+	if ci.search != nil {
+		stats := ci.search.Stats()
+		searchInfo = models.CacheInfo{
+			Name:      CacheSearch,
+			Enabled:   true,
+			Entries:   stats.Entries,
+			Capacity:  ci.search.Capacity(),
+			Hits:      stats.Hits,
+			StaleHits: stats.StaleHits,
+			Misses:    stats.Misses,
+			Bypassed:  stats.Bypassed,
+			HitRatio:  hitRatio(stats.Hits+stats.StaleHits, stats.Misses),
+		}
+	}
+
+	responseInfo := models.CacheInfo{Name: CacheResponse}
+	if ci.response != nil {
+		stats := ci.response.Stats()
+		responseInfo = models.CacheInfo{
+			Name:      CacheResponse,
+			Enabled:   true,
+			Entries:   stats.Entries,
+			Capacity:  ci.response.Capacity(),
+			Hits:      stats.Hits,
+			RedisHits: stats.RedisHits,
+			Misses:    stats.Misses,
+			HitRatio:  hitRatio(stats.Hits+stats.RedisHits, stats.Misses),
+		}
+	}
+	response.Caches = append(response.Caches, searchInfo, responseInfo)
+
+	namespaces := ci.shared.NamespaceStats()
+	for _, name := range []string{CacheAnalysis, CacheEmbedding, CacheQueryTranslation} {
+		stats := namespaces[name]
+		response.Caches = append(response.Caches, models.CacheInfo{
+			Name:     name,
+			Enabled:  true,
+			Entries:  stats.Entries,
+			Hits:     stats.Hits,
+			Misses:   stats.Misses,
+			HitRatio: hitRatio(stats.Hits, stats.Misses),
+		})
+	}
+	return response
+}
+
+// Invalidate descarta as entradas cujo prefixo tem a forma "<cache>:<início da chave>"
+// (ex.: "search:iptu", "response:/api/v1/services/", "analysis:"). O descarte vale para a
+// memória desta réplica e, no cache de respostas, também para o Redis.
+func (ci *CacheInspector) Invalidate(ctx context.Context, prefix string) (*models.CacheInvalidationResponse, error) {
+	name, keyPrefix, found := strings.Cut(prefix, ":")
+	if !found {
+		return nil, fmt.Errorf("%w: use o formato <cache>:<início da chave>", ErrCacheUnknown)
+	}
+
+	result := &models.CacheInvalidationResponse{Cache: name, Prefix: keyPrefix}
+	switch name {
+	case CacheSearch:
+		if ci.search == nil {
+			return nil, fmt.Errorf("%w: %s", ErrCacheDisabled, name)
+		}
+		result.Removed = ci.search.DeleteQueryPrefix(keyPrefix)
+	case CacheResponse:
+		if ci.response == nil {
+			return nil, fmt.Errorf("%w: %s", ErrCacheDisabled, name)
+		}
+		removed, err := ci.response.DeletePrefix(ctx, keyPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao descartar respostas no Redis: %w", err)
+		}
+		result.Removed = removed
+	case CacheAnalysis, CacheEmbedding, CacheQueryTranslation:
+		result.Removed = ci.shared.DeletePrefix(name + ":" + keyPrefix)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrCacheUnknown, name)
+	}
+	return result, nil
+}
+
+func hitRatio(hits, misses int64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
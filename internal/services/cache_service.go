@@ -2,6 +2,7 @@ package services
 
 import (
 	"container/list"
+	"strings"
 	"sync"
 	"time"
 )
@@ -11,6 +12,7 @@ type Cache interface {
 	Get(key string) interface{}
 	Set(key string, value interface{}, ttl time.Duration)
 	Delete(key string)
+	DeletePrefix(prefix string) int
 	Clear()
 	Size() int
 }
@@ -22,12 +24,22 @@ type cacheEntry struct {
 	expiration time.Time
 }
 
+// CacheStats contadores de acesso de um cache (ou de um namespace do cache compartilhado)
+type CacheStats struct {
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+	Entries int   `json:"entries"`
+}
+
 // LRUCache implementa um cache LRU (Least Recently Used) thread-safe
 type LRUCache struct {
 	capacity int
 	mu       sync.RWMutex
 	cache    map[string]*list.Element
 	lruList  *list.List
+
+	// Acessos por namespace (prefixo da chave até o primeiro ':'), apenas dos rastreados
+	namespaces map[string]*CacheStats
 }
 
 // NewLRUCache cria um novo cache LRU com a capacidade especificada
@@ -39,25 +51,49 @@ func NewLRUCache(capacity int) *LRUCache {
 	}
 }
 
+// TrackNamespaces passa a contar acertos e faltas das chaves "<namespace>:..." informadas.
+// Deve ser chamado antes de o cache entrar em uso.
+func (c *LRUCache) TrackNamespaces(namespaces ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.namespaces == nil {
+		c.namespaces = make(map[string]*CacheStats, len(namespaces))
+	}
+	for _, namespace := range namespaces {
+		c.namespaces[namespace] = &CacheStats{}
+	}
+}
+
 // Get recupera um valor do cache
 func (c *LRUCache) Get(key string) interface{} {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	stats := c.namespaces[keyNamespace(key)]
 	if element, found := c.cache[key]; found {
 		entry := element.Value.(*cacheEntry)
 
 		// Verificar se expirou
 		if time.Now().After(entry.expiration) {
 			c.removeElement(element)
+			if stats != nil {
+				stats.Misses++
+			}
 			return nil
 		}
 
 		// Mover para o final da lista (mais recentemente usado)
 		c.lruList.MoveToBack(element)
+		if stats != nil {
+			stats.Hits++
+		}
 		return entry.value
 	}
 
+	if stats != nil {
+		stats.Misses++
+	}
 	return nil
 }
 
@@ -105,6 +141,22 @@ func (c *LRUCache) Delete(key string) {
 	}
 }
 
+// DeletePrefix remove todos os itens cuja chave começa com o prefixo, retornando quantos foram removidos
+func (c *LRUCache) DeletePrefix(prefix string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for key, element := range c.cache {
+		if strings.HasPrefix(key, prefix) {
+			c.removeElement(element)
+			removed++
+		}
+	}
+
+	return removed
+}
+
 // Clear limpa todo o cache
 func (c *LRUCache) Clear() {
 	c.mu.Lock()
@@ -122,6 +174,35 @@ func (c *LRUCache) Size() int {
 	return c.lruList.Len()
 }
 
+// Capacity retorna a quantidade máxima de itens do cache
+func (c *LRUCache) Capacity() int {
+	return c.capacity
+}
+
+// NamespaceStats retorna acertos, faltas e itens de cada namespace rastreado
+func (c *LRUCache) NamespaceStats() map[string]CacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := make(map[string]CacheStats, len(c.namespaces))
+	for namespace, counters := range c.namespaces {
+		stats[namespace] = CacheStats{Hits: counters.Hits, Misses: counters.Misses}
+	}
+	for key := range c.cache {
+		if namespaceStats, ok := stats[keyNamespace(key)]; ok {
+			namespaceStats.Entries++
+			stats[keyNamespace(key)] = namespaceStats
+		}
+	}
+	return stats
+}
+
+// keyNamespace retorna o prefixo da chave até o primeiro ':'
+func keyNamespace(key string) string {
+	namespace, _, _ := strings.Cut(key, ":")
+	return namespace
+}
+
 // removeElement remove um elemento da lista e do mapa (deve ser chamado com lock)
 func (c *LRUCache) removeElement(element *list.Element) {
 	c.lruList.Remove(element)
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUCacheDeletePrefix(t *testing.T) {
+	cache := NewLRUCache(10)
+	cache.Set("analysis:iptu", 1, time.Minute)
+	cache.Set("analysis:multa", 2, time.Minute)
+	cache.Set("embedding:iptu", 3, time.Minute)
+
+	if removed := cache.DeletePrefix("analysis:"); removed != 2 {
+		t.Errorf("esperadas 2 remoções, obtidas %d", removed)
+	}
+	if cache.Get("embedding:iptu") == nil || cache.Size() != 1 {
+		t.Error("entradas fora do prefixo não deveriam ser removidas")
+	}
+}
+
+func TestLRUCacheNamespaceStats(t *testing.T) {
+	cache := NewLRUCache(10)
+	cache.TrackNamespaces(CacheAnalysis, CacheEmbedding)
+	cache.Set("analysis:iptu", 1, time.Minute)
+	cache.Set("analysis:multa", 2, time.Minute)
+	cache.Set("index:embedding_metadata", 3, time.Minute)
+
+	cache.Get("analysis:iptu")
+	cache.Get("analysis:iptu")
+	cache.Get("analysis:alvara")
+	cache.Get("embedding:iptu")
+	cache.Get("index:embedding_metadata")
+
+	stats := cache.NamespaceStats()
+	if got := stats[CacheAnalysis]; got != (CacheStats{Hits: 2, Misses: 1, Entries: 2}) {
+		t.Errorf("analysis: obtido %+v", got)
+	}
+	if got := stats[CacheEmbedding]; got != (CacheStats{Misses: 1}) {
+		t.Errorf("embedding: obtido %+v", got)
+	}
+	if _, ok := stats["index"]; ok || len(stats) != 2 {
+		t.Errorf("apenas os namespaces rastreados deveriam ser contados: %v", stats)
+	}
+}
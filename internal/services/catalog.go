@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+	"github.com/typesense/typesense-go/v3/typesense"
+)
+
+// catalogOtherLetter grupo dos serviços cujo nome não começa com letra
+const catalogOtherLetter = "#"
+
+// ErrInvalidCatalogLetter indica letra fora do índice A–Z
+var ErrInvalidCatalogLetter = errors.New("letra inválida: use de A a Z ou #")
+
+// catalogIndex serviços publicados agrupados pela letra inicial
+type catalogIndex struct {
+	groups   map[string][]models.CatalogService
+	loadedAt time.Time
+}
+
+// ServiceCatalog mantém em memória o índice A–Z dos serviços publicados, por tenant. O índice é
+// descartado a cada escrita em serviços e expira após o ttl, para acompanhar a abertura e o
+// fechamento das janelas de disponibilidade dos serviços sazonais.
+type ServiceCatalog struct {
+	client *typesense.Client
+	ttl    time.Duration
+
+	// list retorna os serviços publicados e disponíveis do tenant do contexto
+	list func(ctx context.Context) ([]models.PrefRioService, error)
+
+	mu      sync.Mutex
+	indexes map[string]*catalogIndex
+}
+
+// NewServiceCatalog cria o catálogo A–Z com o ttl informado
+func NewServiceCatalog(client *typesense.Client, ttl time.Duration) *ServiceCatalog {
+	sc := &ServiceCatalog{
+		client:  client,
+		ttl:     ttl,
+		indexes: make(map[string]*catalogIndex),
+	}
+	sc.list = sc.listPublished
+	return sc
+}
+
+// Get retorna o índice de letras e os serviços da letra (sem acento, maiúscula ou minúscula);
+// sem letra, retorna todos os grupos
+func (sc *ServiceCatalog) Get(ctx context.Context, letter string) (*models.CatalogResponse, error) {
+	letter = strings.TrimSpace(letter)
+	if letter != "" {
+		normalized := catalogLetter(letter)
+		if len([]rune(letter)) != 1 || (normalized == catalogOtherLetter && letter != catalogOtherLetter) {
+			return nil, ErrInvalidCatalogLetter
+		}
+		letter = normalized
+	}
+
+	index, err := sc.index(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return index.response(letter), nil
+}
+
+// HandleDocumentEvent descarta os índices após escritas em serviços
+func (sc *ServiceCatalog) HandleDocumentEvent(ctx context.Context, event DocumentEvent) {
+	if event.Collection != PrefRioServicesCollection {
+		return
+	}
+	sc.mu.Lock()
+	sc.indexes = make(map[string]*catalogIndex)
+	sc.mu.Unlock()
+}
+
+// index retorna o índice do tenant da requisição, recarregando-o se expirado
+func (sc *ServiceCatalog) index(ctx context.Context) (*catalogIndex, error) {
+	key, _ := tenant.FromContext(ctx)
+
+	sc.mu.Lock()
+	index, ok := sc.indexes[key]
+	sc.mu.Unlock()
+	if ok && time.Since(index.loadedAt) < sc.ttl {
+		return index, nil
+	}
+
+	services, err := sc.list(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar serviços do catálogo: %w", err)
+	}
+	index = buildCatalogIndex(services)
+
+	sc.mu.Lock()
+	sc.indexes[key] = index
+	sc.mu.Unlock()
+	return index, nil
+}
+
+// listPublished lista os serviços publicados dentro da janela de disponibilidade
+func (sc *ServiceCatalog) listPublished(ctx context.Context) ([]models.PrefRioService, error) {
+	filterBy := tenant.ScopeFilter(ctx, "status:=1 && "+availabilityFilter(time.Now().Unix()))
+
+	var all []models.PrefRioService
+	for page := 1; ; page++ {
+		services, found, err := fetchServicesPage(ctx, sc.client, filterBy, page, 250)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, services...)
+		if len(services) == 0 || page*250 >= found {
+			return all, nil
+		}
+	}
+}
+
+// buildCatalogIndex agrupa os serviços pela letra inicial do nome, em ordem alfabética sem
+// considerar acentos nem maiúsculas
+func buildCatalogIndex(services []models.PrefRioService) *catalogIndex {
+	index := &catalogIndex{groups: make(map[string][]models.CatalogService), loadedAt: time.Now()}
+	keys := make(map[string]string, len(services))
+	for _, service := range services {
+		name := strings.TrimSpace(service.NomeServico)
+		if name == "" {
+			continue
+		}
+		keys[service.ID] = utils.NormalizarCategoria(name)
+
+		letter := catalogLetter(name)
+		index.groups[letter] = append(index.groups[letter], models.CatalogService{
+			ID:          service.ID,
+			NomeServico: name,
+			Slug:        service.Slug,
+			TemaGeral:   service.TemaGeral,
+			Resumo:      utils.StripMarkdown(service.Resumo),
+		})
+	}
+
+	for _, group := range index.groups {
+		sort.SliceStable(group, func(i, j int) bool {
+			if keys[group[i].ID] != keys[group[j].ID] {
+				return keys[group[i].ID] < keys[group[j].ID]
+			}
+			return group[i].NomeServico < group[j].NomeServico
+		})
+	}
+	return index
+}
+
+// response monta a resposta com todas as letras do índice e o grupo da letra (todos sem letra)
+func (index *catalogIndex) response(letter string) *models.CatalogResponse {
+	letters := catalogLetters(index.groups)
+	response := &models.CatalogResponse{
+		Letters: make([]models.CatalogLetter, 0, len(letters)),
+		Groups:  []models.CatalogGroup{},
+	}
+	for _, l := range letters {
+		services := index.groups[l]
+		response.Letters = append(response.Letters, models.CatalogLetter{Letter: l, Count: len(services)})
+		if (letter == "" || letter == l) && len(services) > 0 {
+			response.Groups = append(response.Groups, models.CatalogGroup{Letter: l, Services: services})
+			response.Total += len(services)
+		}
+	}
+	return response
+}
+
+// catalogLetters letras de A a Z e, se houver serviços, o grupo #
+func catalogLetters(groups map[string][]models.CatalogService) []string {
+	letters := make([]string, 0, 27)
+	for l := 'A'; l <= 'Z'; l++ {
+		letters = append(letters, string(l))
+	}
+	if len(groups[catalogOtherLetter]) > 0 {
+		letters = append(letters, catalogOtherLetter)
+	}
+	return letters
+}
+
+// catalogLetter letra do índice do nome: a primeira letra ou dígito, sem acento e em maiúscula;
+// nomes iniciados por dígito ou sem letras vão para o grupo #
+func catalogLetter(name string) string {
+	for _, r := range utils.NormalizarCategoria(name) {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return string(unicode.ToUpper(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return catalogOtherLetter
+		}
+	}
+	return catalogOtherLetter
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestCatalogLetter(t *testing.T) {
+	tests := map[string]string{
+		"Matrícula escolar":  "M",
+		"Éxito":              "E",
+		"ônibus":             "O",
+		"Çedilha":            "C",
+		"\"Aluguel social\"": "A",
+		"1ª via de IPTU":     "#",
+		"":                   "#",
+	}
+	for name, expected := range tests {
+		if got := catalogLetter(name); got != expected {
+			t.Errorf("%q: esperado %q, obtido %q", name, expected, got)
+		}
+	}
+}
+
+func TestServiceCatalogGet(t *testing.T) {
+	sc := NewServiceCatalog(nil, time.Hour)
+	loads := 0
+	sc.list = func(ctx context.Context) ([]models.PrefRioService, error) {
+		loads++
+		return []models.PrefRioService{
+			{ID: "1", NomeServico: "Emissão de guia", Slug: "emissao"},
+			{ID: "2", NomeServico: "Éxito escolar", Slug: "exito"},
+			{ID: "3", NomeServico: "educação infantil", Slug: "educacao"},
+			{ID: "4", NomeServico: "2ª via de IPTU", Slug: "iptu"},
+			{ID: "5", NomeServico: "Alvará", Slug: "alvara", Resumo: "**Licença** de funcionamento"},
+		}, nil
+	}
+
+	response, err := sc.Get(context.Background(), "é")
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if len(response.Groups) != 1 || response.Groups[0].Letter != "E" || response.Total != 3 {
+		t.Fatalf("grupos inesperados: %+v", response.Groups)
+	}
+	var names []string
+	for _, service := range response.Groups[0].Services {
+		names = append(names, service.NomeServico)
+	}
+	if expected := []string{"educação infantil", "Emissão de guia", "Éxito escolar"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("ordem esperada %v, obtida %v", expected, names)
+	}
+	if len(response.Letters) != 27 || response.Letters[26] != (models.CatalogLetter{Letter: "#", Count: 1}) {
+		t.Errorf("letras inesperadas: %+v", response.Letters)
+	}
+
+	all, err := sc.Get(context.Background(), "")
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if len(all.Groups) != 3 || all.Total != 5 || all.Groups[0].Services[0].Resumo != "Licença de funcionamento" {
+		t.Errorf("catálogo completo inesperado: %+v", all)
+	}
+	if loads != 1 {
+		t.Errorf("esperado 1 carregamento, obtidos %d", loads)
+	}
+
+	sc.HandleDocumentEvent(context.Background(), DocumentEvent{Collection: PrefRioServicesCollection})
+	if _, err := sc.Get(context.Background(), "A"); err != nil || loads != 2 {
+		t.Errorf("escrita não descartou o índice: %d carregamentos, erro %v", loads, err)
+	}
+
+	for _, letter := range []string{"AB", "1", "?"} {
+		if _, err := sc.Get(context.Background(), letter); !errors.Is(err, ErrInvalidCatalogLetter) {
+			t.Errorf("letra %q: esperado ErrInvalidCatalogLetter, obtido %v", letter, err)
+		}
+	}
+}
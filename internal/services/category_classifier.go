@@ -0,0 +1,485 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+	"google.golang.org/genai"
+)
+
+const (
+	CategorySuggestionsCollection = "category_suggestions"
+
+	// categoryPromptVersion identifica o prompt nas estatísticas de correção; incremente ao alterá-lo
+	categoryPromptVersion = "v1"
+
+	taxonomyTTL          = 10 * time.Minute
+	maxPromptCorrections = 5
+)
+
+const categoryClassificationPrompt = `Classifique o serviço público da Prefeitura do Rio abaixo na taxonomia de categorias.
+
+Serviço:
+Nome: %s
+Órgão gestor: %s
+Resumo: %s
+Descrição: %s
+
+Taxonomia (tema_geral: subcategorias conhecidas):
+%s
+%s
+Regras:
+- tema_geral deve ser exatamente um dos temas da taxonomia
+- sub_categoria deve ser uma das subcategorias do tema escolhido ou vazia se nenhuma se aplicar
+- confidence: 0-1 (quão claro é o enquadramento)
+
+Retorne APENAS o JSON:
+{"tema_geral": "...", "sub_categoria": "...", "confidence": 0.9, "justificativa": "..."}`
+
+// CategoryClassifier sugere tema_geral/sub_categoria via Gemini para serviços sem categoria ou
+// com categoria fora da taxonomia, guardando as sugestões para aceite pelo editor
+type CategoryClassifier struct {
+	client        *typesense.Client
+	geminiClient  *genai.Client
+	model         string
+	popularity    *PopularityService
+	subcategories *SubcategoryService
+
+	mu         sync.Mutex
+	taxonomy   map[string][]string
+	taxonomyAt time.Time
+}
+
+// NewCategoryClassifier cria o classificador. Sem cliente Gemini, a classificação fica desabilitada.
+func NewCategoryClassifier(client *typesense.Client, geminiClient *genai.Client, model string, popularity *PopularityService, subcategories *SubcategoryService) *CategoryClassifier {
+	return &CategoryClassifier{
+		client:        client,
+		geminiClient:  geminiClient,
+		model:         model,
+		popularity:    popularity,
+		subcategories: subcategories,
+	}
+}
+
+// Enabled indica se a classificação automática está disponível
+func (cc *CategoryClassifier) Enabled() bool {
+	return cc != nil && cc.geminiClient != nil
+}
+
+// Taxonomy retorna os temas conhecidos com suas subcategorias em uso (cache de 10 minutos)
+func (cc *CategoryClassifier) Taxonomy(ctx context.Context) map[string][]string {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if cc.taxonomy != nil && time.Since(cc.taxonomyAt) < taxonomyTTL {
+		return cc.taxonomy
+	}
+
+	taxonomy := make(map[string][]string)
+	for tema := range cc.popularity.GetAllCategories() {
+		taxonomy[tema] = []string{}
+		resp, err := cc.subcategories.GetSubcategories(ctx, &models.SubcategoryRequest{Category: tema, SortBy: "count"})
+		if err != nil {
+			log.Printf("[Classificação] Erro ao buscar subcategorias de %s: %v", tema, err)
+			continue
+		}
+		for _, sub := range resp.Subcategories {
+			taxonomy[tema] = append(taxonomy[tema], sub.Name)
+		}
+	}
+
+	cc.taxonomy = taxonomy
+	cc.taxonomyAt = time.Now()
+	return taxonomy
+}
+
+// ClassificationReason retorna por que o serviço precisa de classificação ("" se o tema é válido)
+func (cc *CategoryClassifier) ClassificationReason(ctx context.Context, service *models.PrefRioService) string {
+	if strings.TrimSpace(service.TemaGeral) == "" {
+		return models.ClassificationReasonEmpty
+	}
+	if _, _, ok := matchTaxonomy(cc.Taxonomy(ctx), service.TemaGeral, ""); !ok {
+		return models.ClassificationReasonInconsistent
+	}
+	return ""
+}
+
+// HandleServiceSaved é chamado após salvar um serviço: gera uma sugestão se o tema está vazio
+// ou inconsistente, ou resolve a sugestão pendente se o editor já corrigiu o tema
+func (cc *CategoryClassifier) HandleServiceSaved(ctx context.Context, service *models.PrefRioService, userName string) {
+	reason := cc.ClassificationReason(ctx, service)
+	if reason != "" {
+		if _, err := cc.Classify(ctx, service, reason); err != nil {
+			log.Printf("[Classificação] Erro ao classificar serviço %s: %v", service.ID, err)
+		}
+		return
+	}
+
+	pending, err := cc.GetPendingSuggestion(ctx, service.ID)
+	if err != nil || pending == nil {
+		return
+	}
+
+	subCategoria := ""
+	if service.SubCategoria != nil {
+		subCategoria = *service.SubCategoria
+	}
+	status := models.CategorySuggestionCorrected
+	if utils.NormalizarCategoria(pending.SuggestedTema) == utils.NormalizarCategoria(service.TemaGeral) {
+		status = models.CategorySuggestionAccepted
+	}
+	if err := cc.Resolve(ctx, pending, status, service.TemaGeral, subCategoria, userName); err != nil {
+		log.Printf("[Classificação] Erro ao resolver sugestão %s: %v", pending.ID, err)
+	}
+}
+
+// Classify pede ao Gemini uma categoria para o serviço, valida contra a taxonomia e salva a
+// sugestão como pendente (substituindo sugestões pendentes anteriores)
+func (cc *CategoryClassifier) Classify(ctx context.Context, service *models.PrefRioService, reason string) (*models.CategorySuggestion, error) {
+	if !cc.Enabled() {
+		return nil, fmt.Errorf("classificação automática indisponível: Gemini não configurado")
+	}
+
+	taxonomy := cc.Taxonomy(ctx)
+	corrections, err := cc.recentCorrections(ctx)
+	if err != nil {
+		log.Printf("[Classificação] Erro ao buscar correções recentes: %v", err)
+	}
+
+	prompt := buildClassificationPrompt(service, taxonomy, corrections)
+	config := &genai.GenerateContentConfig{ResponseMIMEType: "application/json"}
+	resp, err := cc.geminiClient.Models.GenerateContent(ctx, cc.model, []*genai.Content{genai.NewContentFromText(prompt, genai.RoleUser)}, config)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao chamar Gemini: %w", err)
+	}
+
+	var answer struct {
+		TemaGeral     string  `json:"tema_geral"`
+		SubCategoria  string  `json:"sub_categoria"`
+		Confidence    float64 `json:"confidence"`
+		Justificativa string  `json:"justificativa"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(resp.Text())), &answer); err != nil {
+		return nil, fmt.Errorf("erro ao parsear JSON do Gemini: %w", err)
+	}
+
+	tema, sub, ok := matchTaxonomy(taxonomy, answer.TemaGeral, answer.SubCategoria)
+	if !ok {
+		return nil, fmt.Errorf("tema sugerido fora da taxonomia: %q", answer.TemaGeral)
+	}
+
+	suggestion := &models.CategorySuggestion{
+		ID:                    uuid.New().String(),
+		ServiceID:             service.ID,
+		NomeServico:           service.NomeServico,
+		Reason:                reason,
+		OriginalTema:          service.TemaGeral,
+		SuggestedTema:         tema,
+		SuggestedSubCategoria: sub,
+		Confidence:            clampConfidence(answer.Confidence),
+		Justificativa:         answer.Justificativa,
+		Model:                 cc.model,
+		PromptVersion:         categoryPromptVersion,
+		Status:                models.CategorySuggestionPending,
+		CreatedAt:             time.Now().Unix(),
+	}
+	if service.SubCategoria != nil {
+		suggestion.OriginalSubCategoria = *service.SubCategoria
+	}
+
+	if previous, err := cc.GetPendingSuggestion(ctx, service.ID); err == nil && previous != nil {
+		if err := cc.Resolve(ctx, previous, models.CategorySuggestionSuperseded, "", "", ""); err != nil {
+			log.Printf("[Classificação] Erro ao substituir sugestão %s: %v", previous.ID, err)
+		}
+	}
+
+	if err := cc.save(ctx, suggestion); err != nil {
+		return nil, fmt.Errorf("erro ao salvar sugestão: %w", err)
+	}
+
+	return suggestion, nil
+}
+
+// ValidateCategory confere tema/subcategoria contra a taxonomia e retorna os nomes canônicos
+func (cc *CategoryClassifier) ValidateCategory(ctx context.Context, tema, subCategoria string) (string, string, bool) {
+	return matchTaxonomy(cc.Taxonomy(ctx), tema, subCategoria)
+}
+
+// GetPendingSuggestion retorna a sugestão pendente mais recente do serviço (nil se não houver)
+func (cc *CategoryClassifier) GetPendingSuggestion(ctx context.Context, serviceID string) (*models.CategorySuggestion, error) {
+	suggestions, _, err := cc.search(ctx, fmt.Sprintf("service_id:=`%s` && status:=%s", serviceID, models.CategorySuggestionPending), 1, 1)
+	if err != nil || len(suggestions) == 0 {
+		return nil, err
+	}
+	return &suggestions[0], nil
+}
+
+// Resolve registra a decisão do editor sobre a sugestão
+func (cc *CategoryClassifier) Resolve(ctx context.Context, suggestion *models.CategorySuggestion, status models.CategorySuggestionStatus, finalTema, finalSubCategoria, userName string) error {
+	suggestion.Status = status
+	suggestion.ResolvedAt = time.Now().Unix()
+	suggestion.ResolvedBy = userName
+	suggestion.FinalTema = finalTema
+	suggestion.FinalSubCategoria = finalSubCategoria
+	return cc.save(ctx, suggestion)
+}
+
+// Stats calcula as taxas de aceite e correção das sugestões, gerais e por versão do prompt
+func (cc *CategoryClassifier) Stats(ctx context.Context) (*models.CategorySuggestionStats, error) {
+	const perPage = 250
+	var all []models.CategorySuggestion
+	for page := 1; ; page++ {
+		suggestions, found, err := cc.search(ctx, "", page, perPage)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, suggestions...)
+		if len(suggestions) == 0 || page*perPage >= found {
+			break
+		}
+	}
+	return computeSuggestionStats(all), nil
+}
+
+// recentCorrections retorna as últimas correções feitas pelos editores, usadas como exemplos no prompt
+func (cc *CategoryClassifier) recentCorrections(ctx context.Context) ([]models.CategorySuggestion, error) {
+	suggestions, _, err := cc.search(ctx, "status:="+string(models.CategorySuggestionCorrected), 1, maxPromptCorrections)
+	return suggestions, err
+}
+
+func (cc *CategoryClassifier) search(ctx context.Context, filterBy string, page, perPage int) ([]models.CategorySuggestion, int, error) {
+	if err := cc.ensureCollection(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	searchParams := &api.SearchCollectionParams{
+		Q:       pointer.String("*"),
+		Page:    pointer.Int(page),
+		PerPage: pointer.Int(perPage),
+		SortBy:  pointer.String("created_at:desc"),
+	}
+	if filterBy != "" {
+		searchParams.FilterBy = pointer.String(filterBy)
+	}
+
+	result, err := cc.client.Collection(CategorySuggestionsCollection).Documents().Search(ctx, searchParams)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao buscar sugestões: %w", err)
+	}
+
+	suggestions := []models.CategorySuggestion{}
+	if result.Hits != nil {
+		for _, hit := range *result.Hits {
+			if hit.Document == nil {
+				continue
+			}
+			raw, _ := (*hit.Document)["suggestion"].(string)
+			var suggestion models.CategorySuggestion
+			if err := json.Unmarshal([]byte(raw), &suggestion); err == nil {
+				suggestions = append(suggestions, suggestion)
+			}
+		}
+	}
+
+	found := 0
+	if result.Found != nil {
+		found = int(*result.Found)
+	}
+	return suggestions, found, nil
+}
+
+// save grava (ou atualiza) a sugestão na collection category_suggestions
+func (cc *CategoryClassifier) save(ctx context.Context, suggestion *models.CategorySuggestion) error {
+	if err := cc.ensureCollection(ctx); err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(suggestion)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar sugestão: %w", err)
+	}
+
+	doc := map[string]interface{}{
+		"id":             suggestion.ID,
+		"service_id":     suggestion.ServiceID,
+		"status":         string(suggestion.Status),
+		"prompt_version": suggestion.PromptVersion,
+		"created_at":     suggestion.CreatedAt,
+		"suggestion":     string(raw),
+	}
+
+	_, err = cc.client.Collection(CategorySuggestionsCollection).Documents().Upsert(ctx, doc, &api.DocumentIndexParameters{})
+	return err
+}
+
+// ensureCollection garante que a collection category_suggestions existe
+func (cc *CategoryClassifier) ensureCollection(ctx context.Context) error {
+	_, err := cc.client.Collection(CategorySuggestionsCollection).Retrieve(ctx)
+	if err == nil {
+		return nil
+	}
+
+	schema := &api.CollectionSchema{
+		Name: CategorySuggestionsCollection,
+		Fields: []api.Field{
+			{Name: "service_id", Type: "string", Facet: pointer.True()},
+			{Name: "status", Type: "string", Facet: pointer.True()},
+			{Name: "prompt_version", Type: "string", Facet: pointer.True()},
+			{Name: "created_at", Type: "int64", Facet: pointer.False()},
+			{Name: "suggestion", Type: "string", Index: pointer.False(), Optional: pointer.True()},
+		},
+		DefaultSortingField: pointer.String("created_at"),
+	}
+
+	if _, err := cc.client.Collections().Create(ctx, schema); err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %w", CategorySuggestionsCollection, err)
+	}
+
+	return nil
+}
+
+// buildClassificationPrompt monta o prompt com a taxonomia e exemplos de correções recentes
+func buildClassificationPrompt(service *models.PrefRioService, taxonomy map[string][]string, corrections []models.CategorySuggestion) string {
+	temas := make([]string, 0, len(taxonomy))
+	for tema := range taxonomy {
+		temas = append(temas, tema)
+	}
+	sort.Strings(temas)
+
+	var taxonomyText strings.Builder
+	for _, tema := range temas {
+		fmt.Fprintf(&taxonomyText, "- %s: %s\n", tema, strings.Join(taxonomy[tema], ", "))
+	}
+
+	var examples strings.Builder
+	if len(corrections) > 0 {
+		examples.WriteString("\nCorreções recentes feitas pelos editores (siga o mesmo critério):\n")
+		for _, correction := range corrections {
+			fmt.Fprintf(&examples, "- %q: sugerido %q, correto %q\n", correction.NomeServico, correction.SuggestedTema, correction.FinalTema)
+		}
+	}
+
+	return fmt.Sprintf(categoryClassificationPrompt,
+		service.NomeServico,
+		strings.Join(service.OrgaoGestor, ", "),
+		truncateText(service.Resumo, 2000),
+		truncateText(service.DescricaoCompleta, 4000),
+		taxonomyText.String(),
+		examples.String(),
+	)
+}
+
+// matchTaxonomy localiza tema e subcategoria na taxonomia ignorando acentos e caixa. Uma
+// subcategoria desconhecida é descartada (retorna vazia) sem invalidar o tema.
+func matchTaxonomy(taxonomy map[string][]string, tema, subCategoria string) (string, string, bool) {
+	normalizedTema := utils.NormalizarCategoria(strings.TrimSpace(tema))
+	for canonical, subs := range taxonomy {
+		if utils.NormalizarCategoria(canonical) != normalizedTema {
+			continue
+		}
+		normalizedSub := utils.NormalizarCategoria(strings.TrimSpace(subCategoria))
+		for _, sub := range subs {
+			if normalizedSub != "" && utils.NormalizarCategoria(sub) == normalizedSub {
+				return canonical, sub, true
+			}
+		}
+		return canonical, "", true
+	}
+	return "", "", false
+}
+
+func clampConfidence(confidence float64) float64 {
+	if confidence < 0 {
+		return 0
+	}
+	if confidence > 1 {
+		return 1
+	}
+	return confidence
+}
+
+// computeSuggestionStats agrega contadores e taxas; sugestões substituídas não entram nas taxas
+func computeSuggestionStats(suggestions []models.CategorySuggestion) *models.CategorySuggestionStats {
+	stats := &models.CategorySuggestionStats{
+		ByPromptVersion: make(map[string]*models.CategorySuggestionRates),
+		TopCorrections:  []models.CategoryCorrection{},
+	}
+
+	corrections := make(map[[2]string]int)
+	for _, suggestion := range suggestions {
+		if suggestion.Status == models.CategorySuggestionSuperseded {
+			continue
+		}
+		version := stats.ByPromptVersion[suggestion.PromptVersion]
+		if version == nil {
+			version = &models.CategorySuggestionRates{}
+			stats.ByPromptVersion[suggestion.PromptVersion] = version
+		}
+		for _, rates := range []*models.CategorySuggestionRates{&stats.CategorySuggestionRates, version} {
+			countSuggestion(rates, suggestion.Status)
+		}
+		if suggestion.Status == models.CategorySuggestionCorrected {
+			corrections[[2]string{suggestion.SuggestedTema, suggestion.FinalTema}]++
+		}
+	}
+
+	computeRates(&stats.CategorySuggestionRates)
+	for _, version := range stats.ByPromptVersion {
+		computeRates(version)
+	}
+
+	for pair, count := range corrections {
+		stats.TopCorrections = append(stats.TopCorrections, models.CategoryCorrection{
+			SuggestedTema: pair[0],
+			FinalTema:     pair[1],
+			Count:         count,
+		})
+	}
+	sort.Slice(stats.TopCorrections, func(i, j int) bool {
+		a, b := stats.TopCorrections[i], stats.TopCorrections[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.SuggestedTema+a.FinalTema < b.SuggestedTema+b.FinalTema
+	})
+	if len(stats.TopCorrections) > 10 {
+		stats.TopCorrections = stats.TopCorrections[:10]
+	}
+
+	return stats
+}
+
+func countSuggestion(rates *models.CategorySuggestionRates, status models.CategorySuggestionStatus) {
+	rates.Total++
+	switch status {
+	case models.CategorySuggestionPending:
+		rates.Pending++
+	case models.CategorySuggestionAccepted:
+		rates.Accepted++
+	case models.CategorySuggestionRejected:
+		rates.Rejected++
+	case models.CategorySuggestionCorrected:
+		rates.Corrected++
+	}
+}
+
+func computeRates(rates *models.CategorySuggestionRates) {
+	resolved := rates.Accepted + rates.Rejected + rates.Corrected
+	if resolved == 0 {
+		return
+	}
+	rates.AcceptanceRate = float64(rates.Accepted) / float64(resolved)
+	rates.CorrectionRate = float64(rates.Corrected) / float64(resolved)
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestMatchTaxonomy(t *testing.T) {
+	taxonomy := map[string][]string{
+		"Saúde":    {"Vacinação", "Consultas"},
+		"Educação": {},
+	}
+
+	tests := []struct {
+		tema, sub         string
+		wantTema, wantSub string
+		wantOK            bool
+	}{
+		{"saude", "vacinacao", "Saúde", "Vacinação", true},
+		{" SAÚDE ", "Inexistente", "Saúde", "", true},
+		{"Educação", "", "Educação", "", true},
+		{"Esportes", "", "", "", false},
+		{"", "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		tema, sub, ok := matchTaxonomy(taxonomy, tt.tema, tt.sub)
+		if tema != tt.wantTema || sub != tt.wantSub || ok != tt.wantOK {
+			t.Errorf("matchTaxonomy(%q, %q) = (%q, %q, %v), want (%q, %q, %v)",
+				tt.tema, tt.sub, tema, sub, ok, tt.wantTema, tt.wantSub, tt.wantOK)
+		}
+	}
+}
+
+func TestComputeSuggestionStats(t *testing.T) {
+	suggestions := []models.CategorySuggestion{
+		{PromptVersion: "v1", Status: models.CategorySuggestionAccepted},
+		{PromptVersion: "v1", Status: models.CategorySuggestionCorrected, SuggestedTema: "Cidade", FinalTema: "Obras"},
+		{PromptVersion: "v2", Status: models.CategorySuggestionCorrected, SuggestedTema: "Cidade", FinalTema: "Obras"},
+		{PromptVersion: "v2", Status: models.CategorySuggestionRejected},
+		{PromptVersion: "v2", Status: models.CategorySuggestionPending},
+		{PromptVersion: "v2", Status: models.CategorySuggestionSuperseded},
+	}
+
+	stats := computeSuggestionStats(suggestions)
+
+	if stats.Total != 5 || stats.Pending != 1 {
+		t.Errorf("expected 5 counted suggestions (1 pending), got total=%d pending=%d", stats.Total, stats.Pending)
+	}
+	if stats.CorrectionRate != 0.5 || stats.AcceptanceRate != 0.25 {
+		t.Errorf("unexpected rates: acceptance=%v correction=%v", stats.AcceptanceRate, stats.CorrectionRate)
+	}
+	if v1 := stats.ByPromptVersion["v1"]; v1 == nil || v1.CorrectionRate != 0.5 {
+		t.Errorf("unexpected v1 stats: %+v", v1)
+	}
+	if len(stats.TopCorrections) != 1 || stats.TopCorrections[0].Count != 2 {
+		t.Errorf("expected Cidade→Obras twice, got %+v", stats.TopCorrections)
+	}
+}
+
+func TestBuildClassificationPromptIncludesCorrections(t *testing.T) {
+	service := &models.PrefRioService{NomeServico: "Tapa-buraco", OrgaoGestor: []string{"SECONSERVA"}}
+	taxonomy := map[string][]string{"Obras": {"Vias"}, "Cidade": {}}
+	corrections := []models.CategorySuggestion{
+		{NomeServico: "Poda de árvore", SuggestedTema: "Cidade", FinalTema: "Meio Ambiente"},
+	}
+
+	prompt := buildClassificationPrompt(service, taxonomy, corrections)
+
+	for _, want := range []string{"Tapa-buraco", "- Obras: Vias", "Poda de árvore", "Meio Ambiente"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+}
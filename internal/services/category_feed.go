@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+const (
+	// categoryFeedItems itens por feed
+	categoryFeedItems = 30
+	// categoryFeedScan versões mais recentes analisadas; parte delas não altera campos públicos
+	categoryFeedScan = 250
+	// CategoryFeedTTL minutos sugeridos aos leitores entre consultas ao feed
+	CategoryFeedTTL = 60
+)
+
+// CategoryFeedService monta o feed RSS de serviços novos ou atualizados de uma categoria, a
+// partir do histórico de versões, para assinatura por associações de moradores e afins
+type CategoryFeedService struct {
+	client   *typesense.Client
+	versions *VersionService
+}
+
+// NewCategoryFeedService cria um novo serviço de feed por categoria
+func NewCategoryFeedService(client *typesense.Client) *CategoryFeedService {
+	return &CategoryFeedService{
+		client:   client,
+		versions: NewVersionService(client),
+	}
+}
+
+// Build monta o feed da categoria. Entram as publicações (serviços criados já publicados ou
+// publicados depois) e as atualizações de campos públicos feitas com o serviço publicado, com
+// as mesmas regras do changelog público; serviços que saíram da categoria ou foram
+// despublicados desde então são omitidos. link é o endereço do próprio feed.
+func (fs *CategoryFeedService) Build(ctx context.Context, category, link string) (*models.RSSFeed, error) {
+	category = strings.ReplaceAll(strings.TrimSpace(category), "`", "")
+
+	versions, err := fs.versions.searchVersions(ctx, &api.SearchCollectionParams{
+		Q:        pointer.String("*"),
+		FilterBy: pointer.String(fmt.Sprintf("tema_geral:=`%s` && status:=1", category)),
+		SortBy:   pointer.String("created_at:desc"),
+		PerPage:  pointer.Int(categoryFeedScan),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar versões da categoria: %w", err)
+	}
+
+	selected := selectFeedVersions(versions, categoryFeedItems)
+	current, err := fs.publishedServices(ctx, category, selected)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &models.RSSFeed{
+		Version: "2.0",
+		Channel: models.RSSChannel{
+			Title:       "Serviços da Prefeitura do Rio: " + category,
+			Link:        link,
+			Description: fmt.Sprintf("Serviços novos e atualizados na categoria %s", category),
+			Language:    "pt-BR",
+			TTL:         CategoryFeedTTL,
+			Items:       []models.RSSItem{},
+		},
+	}
+	for _, fv := range selected {
+		service, ok := current[fv.version.ServiceID]
+		if !ok {
+			continue
+		}
+		feed.Channel.Items = append(feed.Channel.Items, newFeedItem(fv, category, service.DeepLinks["web"]))
+	}
+	if len(feed.Channel.Items) > 0 {
+		feed.Channel.LastBuildDate = feed.Channel.Items[0].PubDate
+	}
+	return feed, nil
+}
+
+// feedVersion versão que entra no feed, com as mudanças públicas que a justificam
+type feedVersion struct {
+	version models.ServiceVersion
+	changes []models.ChangelogFieldChange
+	isNew   bool
+}
+
+// selectFeedVersions escolhe, das versões mais recentes para as mais antigas, as publicações e
+// as atualizações com mudanças em campos públicos (versões pendentes nunca foram confirmadas)
+func selectFeedVersions(versions []models.ServiceVersion, limit int) []feedVersion {
+	var selected []feedVersion
+	for _, version := range versions {
+		if len(selected) == limit {
+			break
+		}
+		if version.Pending {
+			continue
+		}
+		entries := BuildPublicChangelog([]models.ServiceVersion{version})
+		if len(entries) == 0 {
+			continue
+		}
+		switch entry := entries[0]; entry.ChangeType {
+		case "create", "publish":
+			selected = append(selected, feedVersion{version: version, isNew: true})
+		case "update", "rollback":
+			selected = append(selected, feedVersion{version: version, changes: entry.Changes})
+		}
+	}
+	return selected
+}
+
+// publishedServices busca os serviços das versões que continuam publicados na categoria
+func (fs *CategoryFeedService) publishedServices(ctx context.Context, category string, selected []feedVersion) (map[string]models.PrefRioService, error) {
+	current := make(map[string]models.PrefRioService)
+	if len(selected) == 0 {
+		return current, nil
+	}
+
+	seen := make(map[string]bool, len(selected))
+	var ids []string
+	for _, fv := range selected {
+		if !seen[fv.version.ServiceID] {
+			seen[fv.version.ServiceID] = true
+			ids = append(ids, fv.version.ServiceID)
+		}
+	}
+
+	filterBy := tenant.ScopeFilter(ctx, fmt.Sprintf("id:[%s] && tema_geral:=`%s` && status:=1", strings.Join(ids, ","), category))
+	result, err := fs.client.Collection(CollectionName).Documents().Search(ctx, &api.SearchCollectionParams{
+		Q:             pointer.String("*"),
+		FilterBy:      pointer.String(filterBy),
+		PerPage:       pointer.Int(len(ids)),
+		ExcludeFields: pointer.String("embedding,search_content"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar serviços da categoria: %w", err)
+	}
+
+	for _, service := range servicesFromHits(result) {
+		current[service.ID] = service
+	}
+	return current, nil
+}
+
+// newFeedItem monta o item do feed; atualizações listam os campos alterados antes do resumo
+func newFeedItem(fv feedVersion, category, link string) models.RSSItem {
+	title := "Novo serviço: " + fv.version.NomeServico
+	description := fv.version.Resumo
+	if !fv.isNew {
+		title = "Serviço atualizado: " + fv.version.NomeServico
+		labels := make([]string, len(fv.changes))
+		for i, change := range fv.changes {
+			labels[i] = change.Label
+		}
+		description = fmt.Sprintf("Alterações: %s. %s", strings.Join(labels, ", "), fv.version.Resumo)
+	}
+
+	return models.RSSItem{
+		Title:       title,
+		Link:        link,
+		Description: description,
+		Category:    category,
+		GUID: models.RSSGUID{
+			Value: fmt.Sprintf("%s:v%d", fv.version.ServiceID, fv.version.VersionNumber),
+		},
+		PubDate: time.Unix(fv.version.CreatedAt, 0).UTC().Format(time.RFC1123Z),
+	}
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestSelectFeedVersions(t *testing.T) {
+	versions := []models.ServiceVersion{
+		{ServiceID: "a", VersionNumber: 5, ChangeType: "update", Status: 1, ChangedFieldsJSON: changesJSON(t,
+			models.FieldChange{FieldName: "custo_servico", OldValue: "R$ 10", NewValue: "R$ 15"},
+			models.FieldChange{FieldName: "canais_digitais", OldValue: nil, NewValue: []string{"app"}},
+		)},
+		{ServiceID: "a", VersionNumber: 4, ChangeType: "update", Status: 1, ChangedFieldsJSON: changesJSON(t,
+			models.FieldChange{FieldName: "autor", OldValue: "A", NewValue: "B"},
+		)},
+		{ServiceID: "b", VersionNumber: 2, ChangeType: "update", Status: 1, Pending: true, ChangedFieldsJSON: changesJSON(t,
+			models.FieldChange{FieldName: "resumo", OldValue: "x", NewValue: "y"},
+		)},
+		{ServiceID: "c", VersionNumber: 3, ChangeType: "update", Status: 1, ChangedFieldsJSON: changesJSON(t,
+			models.FieldChange{FieldName: "status", OldValue: 0, NewValue: 1},
+		)},
+		{ServiceID: "d", VersionNumber: 1, ChangeType: "create", Status: 1},
+		{ServiceID: "e", VersionNumber: 1, ChangeType: "create", Status: 1},
+	}
+
+	selected := selectFeedVersions(versions, 3)
+	if len(selected) != 3 {
+		t.Fatalf("esperadas 3 versões, obtidas %d", len(selected))
+	}
+	got := []string{selected[0].version.ServiceID, selected[1].version.ServiceID, selected[2].version.ServiceID}
+	if strings.Join(got, ",") != "a,c,d" {
+		t.Errorf("esperadas a,c,d (sem campo interno, pendente nem além do limite), obtidas %v", got)
+	}
+	if selected[0].isNew || len(selected[0].changes) != 2 {
+		t.Errorf("atualização com duas mudanças públicas esperada, obtida %+v", selected[0])
+	}
+	if !selected[1].isNew || !selected[2].isNew {
+		t.Error("publicação e criação publicada devem entrar como novos serviços")
+	}
+
+	item := newFeedItem(selected[0], "Educação", "https://prefeitura.rio/servicos/a")
+	if !strings.HasPrefix(item.Title, "Serviço atualizado") || !strings.Contains(item.Description, "Custo do serviço, Canais digitais") {
+		t.Errorf("item de atualização inesperado: %+v", item)
+	}
+	if item.GUID.Value != "a:v5" || item.GUID.IsPermaLink {
+		t.Errorf("guid inesperado: %+v", item.GUID)
+	}
+}
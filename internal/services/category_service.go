@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
 	"github.com/typesense/typesense-go/v3/typesense"
 	"github.com/typesense/typesense-go/v3/typesense/api"
 	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
@@ -17,6 +18,7 @@ import (
 type CategoryService struct {
 	client            *typesense.Client
 	popularityService *PopularityService
+	stats             *CategoryStats
 }
 
 // NewCategoryService cria um novo serviço de categorias
@@ -27,6 +29,12 @@ func NewCategoryService(client *typesense.Client, popularityService *PopularityS
 	}
 }
 
+// SetStats habilita o cache de contagens por categoria, recontado de forma incremental
+// após escritas (ver CategoryStats.HandleDocumentEvent)
+func (cs *CategoryService) SetStats(stats *CategoryStats) {
+	cs.stats = stats
+}
+
 // GetCategories retorna categorias com contadores e opcionalmente serviços filtrados
 func (cs *CategoryService) GetCategories(ctx context.Context, req *models.CategoryRequest) (*models.CategoryResponse, error) {
 	// Validações e defaults
@@ -105,6 +113,17 @@ func (cs *CategoryService) fetchCategoriesWithFacets(ctx context.Context, includ
 		// Apenas publicados (status = 1)
 		filterBy = "status:=1"
 	}
+	filterBy = tenant.ScopeFilter(ctx, filterBy)
+
+	// Contagens em cache, mantidas atualizadas pelas recontagens incrementais
+	var statsKey categoryStatsKey
+	if cs.stats != nil {
+		statsKey.tenant, _ = tenant.FromContext(ctx)
+		statsKey.includeInactive = includeInactive
+		if counts, ok := cs.stats.Get(statsKey); ok {
+			return categoriesFromCounts(counts), nil
+		}
+	}
 
 	// Query com facet em tema_geral
 	searchParams := &api.SearchCollectionParams{
@@ -130,9 +149,29 @@ func (cs *CategoryService) fetchCategoriesWithFacets(ctx context.Context, includ
 		return nil, err
 	}
 
+	if cs.stats != nil {
+		counts := make(map[string]int, len(categories))
+		for _, cat := range categories {
+			counts[cat.Name] = cat.Count
+		}
+		cs.stats.Store(statsKey, counts)
+	}
+
 	return categories, nil
 }
 
+// categoriesFromCounts converte as contagens em cache em categorias
+func categoriesFromCounts(counts map[string]int) []*models.Category {
+	categories := make([]*models.Category, 0, len(counts))
+	for name, count := range counts {
+		categories = append(categories, &models.Category{
+			Name:  name,
+			Count: count,
+		})
+	}
+	return categories
+}
+
 // extractCategoriesFromFacets extrai categorias dos resultados de facet search
 func (cs *CategoryService) extractCategoriesFromFacets(result *api.SearchResult) ([]*models.Category, error) {
 	categories := []*models.Category{}
@@ -210,6 +249,7 @@ func (cs *CategoryService) getServicesByCategory(ctx context.Context, category s
 		// Filtrar por categoria E status publicado
 		filterBy = fmt.Sprintf("tema_geral:=`%s` && status:=1", category)
 	}
+	filterBy = tenant.ScopeFilter(ctx, filterBy)
 
 	searchParams := &api.SearchCollectionParams{
 		Q:        pointer.String("*"),
@@ -0,0 +1,342 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+// categoryStatsKey escopo das contagens de categorias: tenant da requisição (vazio sem
+// particionamento) e inclusão de serviços não publicados
+type categoryStatsKey struct {
+	tenant          string
+	includeInactive bool
+}
+
+// categoryDocState categoria e status conhecidos de um serviço, usados para saber quais
+// categorias uma escrita afeta
+type categoryDocState struct {
+	category string
+	status   int
+}
+
+// CategoryStatsStats contadores das recontagens incrementais de categorias
+type CategoryStatsStats struct {
+	Refreshes     int64 `json:"refreshes"`      // recontagens de categoria executadas
+	FullReloads   int64 `json:"full_reloads"`   // recontagens completas (cache vazio ou invalidado)
+	PendingTimers int   `json:"pending_timers"` // categorias aguardando o debounce
+}
+
+// CategoryStats mantém em memória as contagens de serviços por categoria. Escritas em serviços
+// (eventos do EventBus) agendam a recontagem apenas das categorias afetadas — a nova e, se o
+// tema_geral mudou, a anterior — com debounce por categoria, evitando recontagens completas.
+type CategoryStats struct {
+	debounce time.Duration
+
+	// count conta os serviços da categoria no escopo; lookup retorna a categoria e o status
+	// atuais do serviço (ok false se não existe); listDocs lista todos os serviços
+	count    func(ctx context.Context, key categoryStatsKey, category string) (int, error)
+	lookup   func(ctx context.Context, id string) (categoryDocState, bool, error)
+	listDocs func(ctx context.Context) (map[string]categoryDocState, error)
+
+	mu     sync.Mutex
+	counts map[categoryStatsKey]map[string]int
+	docs   map[string]categoryDocState
+	timers map[string]*time.Timer
+
+	refreshes   atomic.Int64
+	fullReloads atomic.Int64
+}
+
+// NewCategoryStats cria o cache de contagens de categorias com o debounce informado
+func NewCategoryStats(client *typesense.Client, debounce time.Duration) *CategoryStats {
+	cs := newCategoryStats(debounce)
+	cs.count = func(ctx context.Context, key categoryStatsKey, category string) (int, error) {
+		return countCategoryServices(ctx, client, key, category)
+	}
+	cs.lookup = func(ctx context.Context, id string) (categoryDocState, bool, error) {
+		return lookupCategoryDoc(ctx, client, id)
+	}
+	cs.listDocs = func(ctx context.Context) (map[string]categoryDocState, error) {
+		return listCategoryDocs(ctx, client)
+	}
+	return cs
+}
+
+func newCategoryStats(debounce time.Duration) *CategoryStats {
+	return &CategoryStats{
+		debounce: debounce,
+		counts:   make(map[categoryStatsKey]map[string]int),
+		timers:   make(map[string]*time.Timer),
+	}
+}
+
+// Get retorna uma cópia das contagens do escopo, se já calculadas
+func (cs *CategoryStats) Get(key categoryStatsKey) (map[string]int, bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	counts, ok := cs.counts[key]
+	if !ok {
+		return nil, false
+	}
+	copied := make(map[string]int, len(counts))
+	for name, count := range counts {
+		copied[name] = count
+	}
+	return copied, true
+}
+
+// Store grava as contagens completas de um escopo (resultado do facet search)
+func (cs *CategoryStats) Store(key categoryStatsKey, counts map[string]int) {
+	cs.fullReloads.Add(1)
+	cs.mu.Lock()
+	cs.counts[key] = counts
+	cs.mu.Unlock()
+}
+
+// Stats retorna os contadores das recontagens
+func (cs *CategoryStats) Stats() CategoryStatsStats {
+	cs.mu.Lock()
+	pending := len(cs.timers)
+	cs.mu.Unlock()
+
+	return CategoryStatsStats{
+		Refreshes:     cs.refreshes.Load(),
+		FullReloads:   cs.fullReloads.Load(),
+		PendingTimers: pending,
+	}
+}
+
+// HandleDocumentEvent identifica as categorias afetadas pela escrita e agenda sua recontagem.
+// A consulta ao documento roda em segundo plano para não atrasar a resposta da escrita.
+func (cs *CategoryStats) HandleDocumentEvent(ctx context.Context, event DocumentEvent) {
+	if event.Collection != PrefRioServicesCollection {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		cs.applyEvent(ctx, event)
+	}()
+}
+
+func (cs *CategoryStats) applyEvent(ctx context.Context, event DocumentEvent) {
+	if err := cs.ensureDocs(ctx); err != nil {
+		log.Printf("[Categorias] Erro ao listar serviços, contagens serão recalculadas: %v", err)
+		cs.invalidate()
+		return
+	}
+
+	// Escritas sem ID (ex.: importações em lote) podem afetar qualquer categoria
+	if event.DocumentID == "" {
+		cs.invalidate()
+		return
+	}
+
+	current, exists := categoryDocState{}, false
+	if event.Type != DocumentDeleted {
+		var err error
+		current, exists, err = cs.lookup(ctx, event.DocumentID)
+		if err != nil {
+			log.Printf("[Categorias] Erro ao consultar serviço %s, contagens serão recalculadas: %v", event.DocumentID, err)
+			cs.invalidate()
+			return
+		}
+	}
+
+	cs.mu.Lock()
+	previous, known := cs.docs[event.DocumentID]
+	if exists {
+		cs.docs[event.DocumentID] = current
+	} else {
+		delete(cs.docs, event.DocumentID)
+	}
+	cs.mu.Unlock()
+
+	switch {
+	case !known && !exists:
+		return
+	case !known:
+		cs.schedule(current.category)
+	case !exists:
+		cs.schedule(previous.category)
+	case previous != current:
+		// Mudança de tema_geral afeta as duas categorias; de status, as contagens de publicados
+		cs.schedule(current.category)
+		if previous.category != current.category {
+			cs.schedule(previous.category)
+		}
+	}
+}
+
+// ensureDocs carrega na primeira escrita a categoria de todos os serviços, necessária para
+// saber de qual categoria um serviço alterado ou removido saiu
+func (cs *CategoryStats) ensureDocs(ctx context.Context) error {
+	cs.mu.Lock()
+	loaded := cs.docs != nil
+	cs.mu.Unlock()
+	if loaded {
+		return nil
+	}
+
+	docs, err := cs.listDocs(ctx)
+	if err != nil {
+		return err
+	}
+
+	cs.mu.Lock()
+	if cs.docs == nil {
+		cs.docs = docs
+	}
+	cs.mu.Unlock()
+	return nil
+}
+
+// schedule agenda a recontagem da categoria; novas escritas na mesma categoria durante o
+// debounce adiam a recontagem, que roda uma única vez
+func (cs *CategoryStats) schedule(category string) {
+	if category == "" {
+		return
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if timer, ok := cs.timers[category]; ok {
+		timer.Reset(cs.debounce)
+		return
+	}
+	cs.timers[category] = time.AfterFunc(cs.debounce, func() {
+		cs.mu.Lock()
+		delete(cs.timers, category)
+		cs.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		cs.refresh(ctx, category)
+	})
+}
+
+// refresh recalcula a contagem da categoria em todos os escopos em cache. Escopos com falha
+// são descartados e recalculados por completo na próxima consulta.
+func (cs *CategoryStats) refresh(ctx context.Context, category string) {
+	cs.mu.Lock()
+	keys := make([]categoryStatsKey, 0, len(cs.counts))
+	for key := range cs.counts {
+		keys = append(keys, key)
+	}
+	cs.mu.Unlock()
+
+	for _, key := range keys {
+		count, err := cs.count(ctx, key, category)
+
+		cs.mu.Lock()
+		counts, ok := cs.counts[key]
+		switch {
+		case !ok:
+		case err != nil:
+			log.Printf("[Categorias] Erro ao recontar categoria %q: %v", category, err)
+			delete(cs.counts, key)
+		case count == 0:
+			delete(counts, category)
+		default:
+			counts[category] = count
+		}
+		cs.mu.Unlock()
+	}
+	cs.refreshes.Add(1)
+}
+
+// invalidate descarta as contagens e o mapa de serviços, forçando a recontagem completa
+func (cs *CategoryStats) invalidate() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.counts = make(map[categoryStatsKey]map[string]int)
+	cs.docs = nil
+}
+
+// categoryScopeContext reconstrói o contexto de tenant do escopo
+func categoryScopeContext(ctx context.Context, key categoryStatsKey) context.Context {
+	if key.tenant == "" {
+		return ctx
+	}
+	return tenant.WithTenant(ctx, key.tenant)
+}
+
+func countCategoryServices(ctx context.Context, client *typesense.Client, key categoryStatsKey, category string) (int, error) {
+	filterBy := fmt.Sprintf("tema_geral:=`%s`", category)
+	if !key.includeInactive {
+		filterBy += " && status:=1"
+	}
+	filterBy = tenant.ScopeFilter(categoryScopeContext(ctx, key), filterBy)
+
+	result, err := client.Collection(CollectionName).Documents().Search(ctx, &api.SearchCollectionParams{
+		Q:        pointer.String("*"),
+		FilterBy: pointer.String(filterBy),
+		PerPage:  pointer.Int(0),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if result.Found == nil {
+		return 0, nil
+	}
+	return *result.Found, nil
+}
+
+func lookupCategoryDoc(ctx context.Context, client *typesense.Client, id string) (categoryDocState, bool, error) {
+	doc, err := client.Collection(CollectionName).Document(id).Retrieve(ctx)
+	if err != nil {
+		if IsNotFoundError(err) {
+			return categoryDocState{}, false, nil
+		}
+		return categoryDocState{}, false, err
+	}
+	return categoryDocState{
+		category: getString(doc, "tema_geral"),
+		status:   int(getInt64(doc, "status")),
+	}, true, nil
+}
+
+func listCategoryDocs(ctx context.Context, client *typesense.Client) (map[string]categoryDocState, error) {
+	docs := make(map[string]categoryDocState)
+	for page := 1; ; page++ {
+		result, err := client.Collection(CollectionName).Documents().Search(ctx, &api.SearchCollectionParams{
+			Q:             pointer.String("*"),
+			IncludeFields: pointer.String("id,tema_geral,status"),
+			Page:          pointer.Int(page),
+			PerPage:       pointer.Int(250),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if result.Hits == nil || len(*result.Hits) == 0 {
+			return docs, nil
+		}
+
+		for _, hit := range *result.Hits {
+			if hit.Document == nil {
+				continue
+			}
+			doc := *hit.Document
+			docs[getString(doc, "id")] = categoryDocState{
+				category: getString(doc, "tema_geral"),
+				status:   int(getInt64(doc, "status")),
+			}
+		}
+
+		if result.Found == nil || page*250 >= *result.Found {
+			return docs, nil
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCategoryStatsRefreshesAffectedCategories(t *testing.T) {
+	cs := newCategoryStats(20 * time.Millisecond)
+
+	var mu sync.Mutex
+	counted := map[string]int{}
+	current := map[string]categoryDocState{
+		"a": {category: "Saúde", status: 1},
+		"b": {category: "Educação", status: 1},
+	}
+	cs.listDocs = func(context.Context) (map[string]categoryDocState, error) {
+		return map[string]categoryDocState{
+			"a": {category: "Saúde", status: 1},
+			"b": {category: "Transporte", status: 1},
+		}, nil
+	}
+	cs.lookup = func(_ context.Context, id string) (categoryDocState, bool, error) {
+		state, ok := current[id]
+		return state, ok, nil
+	}
+	cs.count = func(_ context.Context, _ categoryStatsKey, category string) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		counted[category]++
+		return 7, nil
+	}
+
+	key := categoryStatsKey{}
+	cs.Store(key, map[string]int{"Saúde": 1, "Transporte": 1})
+
+	// Escritas que não mudam categoria nem status não agendam recontagem
+	for i := 0; i < 3; i++ {
+		cs.applyEvent(context.Background(), DocumentEvent{Type: DocumentUpdated, Collection: PrefRioServicesCollection, DocumentID: "a"})
+	}
+	// A mudança de status agenda a recontagem uma única vez, mesmo com escritas repetidas
+	current["a"] = categoryDocState{category: "Saúde", status: 0}
+	cs.applyEvent(context.Background(), DocumentEvent{Type: DocumentUpdated, Collection: PrefRioServicesCollection, DocumentID: "a"})
+	cs.applyEvent(context.Background(), DocumentEvent{Type: DocumentUpdated, Collection: PrefRioServicesCollection, DocumentID: "a"})
+	// A mudança de tema_geral reconta a categoria nova e a anterior
+	cs.applyEvent(context.Background(), DocumentEvent{Type: DocumentUpdated, Collection: PrefRioServicesCollection, DocumentID: "b"})
+
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if counted["Saúde"] != 1 || counted["Educação"] != 1 || counted["Transporte"] != 1 {
+		t.Errorf("recontagens inesperadas: %v", counted)
+	}
+
+	counts, ok := cs.Get(key)
+	if !ok || counts["Educação"] != 7 || counts["Transporte"] != 7 {
+		t.Errorf("contagens não atualizadas: %v", counts)
+	}
+}
+
+func TestCategoryStatsInvalidatesOnUnknownWrite(t *testing.T) {
+	cs := newCategoryStats(time.Minute)
+	cs.listDocs = func(context.Context) (map[string]categoryDocState, error) {
+		return map[string]categoryDocState{}, nil
+	}
+
+	key := categoryStatsKey{tenant: "rio"}
+	cs.Store(key, map[string]int{"Saúde": 1})
+
+	// Escritas sem ID de documento (ex.: lote) descartam as contagens
+	cs.applyEvent(context.Background(), DocumentEvent{Type: DocumentCreated, Collection: PrefRioServicesCollection})
+	if _, ok := cs.Get(key); ok {
+		t.Error("contagens deveriam ser descartadas")
+	}
+}
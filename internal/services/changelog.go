@@ -0,0 +1,104 @@
+package services
+
+import (
+	"encoding/json"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+// publicChangelogFields campos exibidos no changelog público, com o rótulo para o cidadão.
+// Campos internos (autor, destaque, aprovação, search_content) nunca são expostos.
+var publicChangelogFields = map[string]string{
+	"nome_servico":           "Nome do serviço",
+	"orgao_gestor":           "Órgão gestor",
+	"resumo":                 "Resumo",
+	"tempo_atendimento":      "Tempo de atendimento",
+	"custo_servico":          "Custo do serviço",
+	"is_free":                "Gratuito",
+	"resultado_solicitacao":  "Resultado da solicitação",
+	"descricao_completa":     "Descrição completa",
+	"documentos_necessarios": "Documentos necessários",
+	"instrucoes_solicitante": "Instruções ao solicitante",
+	"canais_digitais":        "Canais digitais",
+	"canais_presenciais":     "Canais presenciais",
+	"servico_nao_cobre":      "O que o serviço não cobre",
+	"legislacao_relacionada": "Legislação relacionada",
+	"tema_geral":             "Categoria",
+	"publico_especifico":     "Público específico",
+}
+
+// BuildPublicChangelog converte as versões de um serviço em entradas de changelog público.
+// Apenas alterações feitas com o serviço publicado entram no changelog (rascunhos nunca
+// foram visíveis), além das publicações e despublicações. Autor, CPF e justificativa
+// interna não são expostos, e versões sem mudanças em campos públicos são omitidas.
+func BuildPublicChangelog(versions []models.ServiceVersion) []models.ChangelogEntry {
+	entries := []models.ChangelogEntry{}
+
+	for _, version := range versions {
+		fieldChanges := parseFieldChanges(version.ChangedFieldsJSON)
+		entry := models.ChangelogEntry{
+			Version:    version.VersionNumber,
+			ChangedAt:  version.CreatedAt,
+			ChangeType: version.ChangeType,
+			Changes:    []models.ChangelogFieldChange{},
+		}
+
+		switch {
+		case version.ChangeType == "delete":
+			// remoção do serviço: registra apenas o evento
+		case version.Status != 1:
+			if !statusChanged(fieldChanges) {
+				continue
+			}
+			entry.ChangeType = "unpublish"
+		default:
+			if statusChanged(fieldChanges) {
+				entry.ChangeType = "publish"
+			}
+			entry.Changes = publicFieldChanges(fieldChanges)
+			if len(entry.Changes) == 0 && entry.ChangeType != "create" && entry.ChangeType != "publish" {
+				continue
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// statusChanged indica se a versão alterou o status de publicação
+func statusChanged(changes []models.FieldChange) bool {
+	for _, change := range changes {
+		if change.FieldName == "status" {
+			return true
+		}
+	}
+	return false
+}
+
+func parseFieldChanges(changedFieldsJSON string) []models.FieldChange {
+	var changes []models.FieldChange
+	if changedFieldsJSON != "" {
+		_ = json.Unmarshal([]byte(changedFieldsJSON), &changes)
+	}
+	return changes
+}
+
+// publicFieldChanges filtra as mudanças registradas na versão para os campos públicos
+func publicFieldChanges(fieldChanges []models.FieldChange) []models.ChangelogFieldChange {
+	changes := []models.ChangelogFieldChange{}
+	for _, change := range fieldChanges {
+		label, ok := publicChangelogFields[change.FieldName]
+		if !ok {
+			continue
+		}
+		changes = append(changes, models.ChangelogFieldChange{
+			Field:    change.FieldName,
+			Label:    label,
+			OldValue: change.OldValue,
+			NewValue: change.NewValue,
+		})
+	}
+	return changes
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func changesJSON(t *testing.T, changes ...models.FieldChange) string {
+	t.Helper()
+	data, err := json.Marshal(changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestBuildPublicChangelog(t *testing.T) {
+	versions := []models.ServiceVersion{
+		{
+			VersionNumber: 4, ChangeType: "update", Status: 1, CreatedBy: "Fulano", CreatedByCPF: "12345678900",
+			ChangedFieldsJSON: changesJSON(t,
+				models.FieldChange{FieldName: "custo_servico", OldValue: "R$ 10", NewValue: "R$ 15"},
+				models.FieldChange{FieldName: "autor", OldValue: "A", NewValue: "B"},
+			),
+		},
+		{
+			VersionNumber: 3, ChangeType: "update", Status: 1,
+			ChangedFieldsJSON: changesJSON(t, models.FieldChange{FieldName: "search_content", OldValue: "a", NewValue: "b"}),
+		},
+		{
+			VersionNumber: 2, ChangeType: "update", Status: 1,
+			ChangedFieldsJSON: changesJSON(t, models.FieldChange{FieldName: "status", OldValue: 0, NewValue: 1}),
+		},
+		{
+			VersionNumber: 1, ChangeType: "create", Status: 0,
+			ChangedFieldsJSON: changesJSON(t, models.FieldChange{FieldName: "resumo", NewValue: "rascunho"}),
+		},
+	}
+
+	entries := BuildPublicChangelog(versions)
+	if len(entries) != 2 {
+		t.Fatalf("esperado 2 entradas públicas, obtido %d: %+v", len(entries), entries)
+	}
+
+	if entries[0].Version != 4 || len(entries[0].Changes) != 1 || entries[0].Changes[0].Field != "custo_servico" {
+		t.Errorf("entrada inesperada: %+v", entries[0])
+	}
+	if entries[1].Version != 2 || entries[1].ChangeType != "publish" {
+		t.Errorf("publicação não identificada: %+v", entries[1])
+	}
+
+	data, _ := json.Marshal(entries)
+	for _, secret := range []string{"12345678900", "Fulano", "rascunho"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("changelog público expõe %q", secret)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers da assinatura das notificações do CMS
+const (
+	CMSSignatureHeader = "X-CMS-Signature" // sha256=<hex do HMAC-SHA256 de "<timestamp>.<corpo>">
+	CMSTimestampHeader = "X-CMS-Timestamp" // unix (segundos) do envio
+)
+
+// cmsSignatureTolerance diferença máxima entre o envio e o recebimento, contra reenvios
+// capturados (replay)
+const cmsSignatureTolerance = 5 * time.Minute
+
+// SignCMSPayload retorna a assinatura esperada no header X-CMS-Signature
+func SignCMSPayload(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyCMSSignature confere a assinatura HMAC do corpo e se o timestamp está dentro da
+// tolerância
+func VerifyCMSSignature(secret []byte, timestamp, signature string, body []byte, now time.Time) error {
+	if timestamp == "" || signature == "" {
+		return errors.New("assinatura ausente")
+	}
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("timestamp inválido")
+	}
+	if age := now.Sub(time.Unix(sent, 0)); age > cmsSignatureTolerance || age < -cmsSignatureTolerance {
+		return errors.New("timestamp fora da tolerância")
+	}
+	expected := SignCMSPayload(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(strings.TrimSpace(signature))) {
+		return errors.New("assinatura inválida")
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerifyCMSSignature(t *testing.T) {
+	secret := []byte("segredo")
+	body := []byte(`{"event":"update","id":"svc-1"}`)
+	now := time.Unix(1_700_000_000, 0)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature := SignCMSPayload(secret, timestamp, body)
+
+	if err := VerifyCMSSignature(secret, timestamp, signature, body, now.Add(time.Minute)); err != nil {
+		t.Fatalf("valid signature rejected: %v", err)
+	}
+
+	cases := map[string]struct {
+		secret    []byte
+		timestamp string
+		signature string
+		body      []byte
+		now       time.Time
+	}{
+		"missing signature": {secret, timestamp, "", body, now},
+		"tampered body":     {secret, timestamp, signature, []byte(`{"event":"delete","id":"svc-1"}`), now},
+		"wrong secret":      {[]byte("outro"), timestamp, signature, body, now},
+		"replayed":          {secret, timestamp, signature, body, now.Add(10 * time.Minute)},
+		"bad timestamp":     {secret, "ontem", signature, body, now},
+	}
+	for name, tc := range cases {
+		if err := VerifyCMSSignature(tc.secret, tc.timestamp, tc.signature, tc.body, tc.now); err == nil {
+			t.Errorf("%s: signature accepted", name)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+var (
+	// ErrExportCollectionNotFound indica collection (ou alias) inexistente
+	ErrExportCollectionNotFound = errors.New("collection não encontrada")
+
+	// ErrExportNotTenantScoped indica collection sem campo de tenant exportada numa requisição de tenant
+	ErrExportNotTenantScoped = errors.New("collection não particionada por tenant")
+)
+
+// CollectionExportOptions opções do export de uma collection
+type CollectionExportOptions struct {
+	IncludeEmbeddings bool   // mantém os campos vetoriais (omitidos por padrão)
+	FilterBy          string // filtro do Typesense (vazio = todos os documentos)
+}
+
+// CollectionExporter exporta o conteúdo completo de collections do Typesense em JSONL pela API
+// de export, que lê o índice em streaming em vez de paginar buscas. Usado em backups fora das
+// collections de backup das migrações.
+type CollectionExporter struct {
+	client *typesense.Client
+}
+
+// NewCollectionExporter cria o exportador de collections
+func NewCollectionExporter(client *typesense.Client) *CollectionExporter {
+	return &CollectionExporter{client: client}
+}
+
+// Schema retorna o schema da collection (ou da collection apontada pelo alias)
+func (e *CollectionExporter) Schema(ctx context.Context, collection string) (*api.CollectionResponse, error) {
+	schema, err := e.client.Collection(collection).Retrieve(ctx)
+	if err != nil {
+		if IsNotFoundError(err) {
+			return nil, fmt.Errorf("%w: %s", ErrExportCollectionNotFound, collection)
+		}
+		return nil, fmt.Errorf("erro ao consultar collection %s: %w", collection, err)
+	}
+	return schema, nil
+}
+
+// Export abre o export da collection: um documento JSON por linha. Em requisições de um tenant
+// apenas os documentos dele são exportados. O corpo deve ser fechado pelo chamador.
+func (e *CollectionExporter) Export(ctx context.Context, collection string, opts CollectionExportOptions) (io.ReadCloser, error) {
+	schema, err := e.Schema(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+	if _, scoped := tenant.FromContext(ctx); scoped && !hasField(schema.Fields, tenant.Field) {
+		return nil, fmt.Errorf("%w: %s", ErrExportNotTenantScoped, collection)
+	}
+
+	params := &api.ExportDocumentsParams{}
+	if !opts.IncludeEmbeddings {
+		if fields := vectorFields(schema.Fields); len(fields) > 0 {
+			params.ExcludeFields = pointer.String(strings.Join(fields, ","))
+		}
+	}
+	if filterBy := tenant.ScopeFilter(ctx, opts.FilterBy); filterBy != "" {
+		params.FilterBy = pointer.String(filterBy)
+	}
+
+	body, err := e.client.Collection(collection).Documents().Export(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao exportar collection %s: %w", collection, err)
+	}
+	return body, nil
+}
+
+// vectorFields retorna os campos vetoriais (com num_dim) do schema
+func vectorFields(fields []api.Field) []string {
+	var names []string
+	for _, field := range fields {
+		if field.NumDim != nil && *field.NumDim > 0 {
+			names = append(names, field.Name)
+		}
+	}
+	return names
+}
+
+func hasField(fields []api.Field, name string) bool {
+	for _, field := range fields {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+func TestVectorFields(t *testing.T) {
+	fields := []api.Field{
+		{Name: "nome_servico", Type: "string"},
+		{Name: "embedding", Type: "float[]", NumDim: pointer.Int(768)},
+		{Name: "scores", Type: "float[]"},
+		{Name: "title_embedding", Type: "float[]", NumDim: pointer.Int(256)},
+	}
+
+	if got := vectorFields(fields); !reflect.DeepEqual(got, []string{"embedding", "title_embedding"}) {
+		t.Errorf("campos vetoriais: obtido %v", got)
+	}
+	if vectorFields(fields[:1]) != nil {
+		t.Error("schema sem campos vetoriais não deveria excluir campos")
+	}
+}
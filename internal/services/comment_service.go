@@ -0,0 +1,359 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+const (
+	ServiceCommentsCollection = "service_comments"
+
+	// CommentMentionEvent evento enviado ao webhook quando um comentário menciona usuários
+	CommentMentionEvent = "service_comment.mention"
+)
+
+var (
+	// ErrCommentNotFound indica comentário inexistente (ou de outro serviço)
+	ErrCommentNotFound = errors.New("comentário não encontrado")
+
+	// ErrCommentForbidden indica edição/remoção de comentário de outro autor
+	ErrCommentForbidden = errors.New("apenas o autor ou um administrador pode alterar o comentário")
+)
+
+// mentionPattern captura menções como @joao.silva ou @maria_souza
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}._@-])@([\p{L}\p{N}][\p{L}\p{N}._-]*[\p{L}\p{N}])`)
+
+// CommentService gerencia os comentários editoriais dos serviços
+type CommentService struct {
+	client   *typesense.Client
+	notifier *WebhookNotifier
+}
+
+// NewCommentService cria um novo serviço de comentários
+func NewCommentService(client *typesense.Client, notifier *WebhookNotifier) *CommentService {
+	return &CommentService{
+		client:   client,
+		notifier: notifier,
+	}
+}
+
+// List retorna os comentários do serviço organizados em threads
+func (cs *CommentService) List(ctx context.Context, serviceID string) (*models.ServiceCommentList, error) {
+	if err := cs.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+
+	comments := []models.ServiceComment{}
+	perPage := 250
+	for page := 1; ; page++ {
+		searchParams := &api.SearchCollectionParams{
+			Q:        pointer.String("*"),
+			FilterBy: pointer.String(fmt.Sprintf("service_id:=`%s`", serviceID)),
+			Page:     pointer.Int(page),
+			PerPage:  pointer.Int(perPage),
+			SortBy:   pointer.String("created_at:asc"),
+		}
+
+		result, err := cs.client.Collection(ServiceCommentsCollection).Documents().Search(ctx, searchParams)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar comentários: %w", err)
+		}
+		if result.Hits == nil {
+			break
+		}
+		for _, hit := range *result.Hits {
+			if hit.Document != nil {
+				comments = append(comments, commentFromDocument(*hit.Document))
+			}
+		}
+		if len(*result.Hits) < perPage {
+			break
+		}
+	}
+
+	return &models.ServiceCommentList{
+		ServiceID: serviceID,
+		Total:     len(comments),
+		Threads:   buildCommentThreads(comments),
+	}, nil
+}
+
+// Create registra o comentário e notifica os usuários mencionados
+func (cs *CommentService) Create(ctx context.Context, serviceID string, req *models.ServiceCommentRequest, authorName, authorCPF string) (*models.ServiceComment, error) {
+	if err := cs.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+
+	if req.ParentID != "" {
+		if _, err := cs.get(ctx, serviceID, req.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	comment := &models.ServiceComment{
+		ID:         uuid.New().String(),
+		ServiceID:  serviceID,
+		ParentID:   req.ParentID,
+		Body:       strings.TrimSpace(req.Body),
+		AuthorName: authorName,
+		AuthorCPF:  authorCPF,
+		Mentions:   extractMentions(req.Body),
+		CreatedAt:  time.Now().Unix(),
+	}
+
+	if _, err := cs.client.Collection(ServiceCommentsCollection).Documents().Create(ctx, commentToDocument(comment), &api.DocumentIndexParameters{}); err != nil {
+		return nil, fmt.Errorf("erro ao salvar comentário: %w", err)
+	}
+
+	cs.notifyMentions(ctx, comment, comment.Mentions)
+	return comment, nil
+}
+
+// Update edita o texto do comentário. Apenas menções novas são notificadas.
+func (cs *CommentService) Update(ctx context.Context, serviceID, commentID, body, userCPF string, isAdmin bool) (*models.ServiceComment, error) {
+	comment, err := cs.get(ctx, serviceID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Deleted {
+		return nil, ErrCommentNotFound
+	}
+	if !isAdmin && comment.AuthorCPF != userCPF {
+		return nil, ErrCommentForbidden
+	}
+
+	previous := comment.Mentions
+	comment.Body = strings.TrimSpace(body)
+	comment.Mentions = extractMentions(body)
+	comment.UpdatedAt = time.Now().Unix()
+
+	update := map[string]interface{}{
+		"body":       comment.Body,
+		"mentions":   comment.Mentions,
+		"updated_at": comment.UpdatedAt,
+	}
+	if _, err := cs.client.Collection(ServiceCommentsCollection).Document(commentID).Update(ctx, update, &api.DocumentIndexParameters{}); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar comentário: %w", err)
+	}
+
+	cs.notifyMentions(ctx, comment, newMentions(previous, comment.Mentions))
+	return comment, nil
+}
+
+// Delete remove o comentário. O registro é mantido sem texto para preservar as respostas.
+func (cs *CommentService) Delete(ctx context.Context, serviceID, commentID, userCPF string, isAdmin bool) error {
+	comment, err := cs.get(ctx, serviceID, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.Deleted {
+		return nil
+	}
+	if !isAdmin && comment.AuthorCPF != userCPF {
+		return ErrCommentForbidden
+	}
+
+	update := map[string]interface{}{
+		"body":       "",
+		"mentions":   []string{},
+		"deleted":    true,
+		"updated_at": time.Now().Unix(),
+	}
+	if _, err := cs.client.Collection(ServiceCommentsCollection).Document(commentID).Update(ctx, update, &api.DocumentIndexParameters{}); err != nil {
+		return fmt.Errorf("erro ao remover comentário: %w", err)
+	}
+	return nil
+}
+
+// get busca o comentário garantindo que pertence ao serviço
+func (cs *CommentService) get(ctx context.Context, serviceID, commentID string) (*models.ServiceComment, error) {
+	doc, err := cs.client.Collection(ServiceCommentsCollection).Document(commentID).Retrieve(ctx)
+	if err != nil {
+		if IsNotFoundError(err) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar comentário: %w", err)
+	}
+
+	comment := commentFromDocument(doc)
+	if comment.ServiceID != serviceID {
+		return nil, ErrCommentNotFound
+	}
+	return &comment, nil
+}
+
+// notifyMentions envia ao webhook o comentário com os usuários mencionados
+func (cs *CommentService) notifyMentions(ctx context.Context, comment *models.ServiceComment, mentions []string) {
+	if len(mentions) == 0 || !cs.notifier.Enabled() {
+		return
+	}
+
+	payload := map[string]interface{}{
+		"service_id": comment.ServiceID,
+		"comment_id": comment.ID,
+		"parent_id":  comment.ParentID,
+		"author":     comment.AuthorName,
+		"body":       comment.Body,
+		"mentions":   mentions,
+	}
+	if err := cs.notifier.Notify(ctx, CommentMentionEvent, payload); err != nil {
+		log.Printf("[Comentários] Erro ao notificar menções do comentário %s: %v", comment.ID, err)
+	}
+}
+
+// ensureCollection garante que a collection service_comments existe
+func (cs *CommentService) ensureCollection(ctx context.Context) error {
+	_, err := cs.client.Collection(ServiceCommentsCollection).Retrieve(ctx)
+	if err == nil {
+		return nil
+	}
+
+	schema := &api.CollectionSchema{
+		Name: ServiceCommentsCollection,
+		Fields: []api.Field{
+			{Name: "service_id", Type: "string", Facet: pointer.True()},
+			{Name: "parent_id", Type: "string", Optional: pointer.True()},
+			{Name: "body", Type: "string", Optional: pointer.True()},
+			{Name: "author_name", Type: "string", Facet: pointer.True()},
+			{Name: "author_cpf", Type: "string", Facet: pointer.False()},
+			{Name: "mentions", Type: "string[]", Facet: pointer.True(), Optional: pointer.True()},
+			{Name: "created_at", Type: "int64", Facet: pointer.False()},
+			{Name: "updated_at", Type: "int64", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "deleted", Type: "bool", Facet: pointer.True(), Optional: pointer.True()},
+		},
+		DefaultSortingField: pointer.String("created_at"),
+	}
+
+	if _, err := cs.client.Collections().Create(ctx, schema); err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %w", ServiceCommentsCollection, err)
+	}
+
+	return nil
+}
+
+func commentToDocument(comment *models.ServiceComment) map[string]interface{} {
+	mentions := comment.Mentions
+	if mentions == nil {
+		mentions = []string{}
+	}
+	return map[string]interface{}{
+		"id":          comment.ID,
+		"service_id":  comment.ServiceID,
+		"parent_id":   comment.ParentID,
+		"body":        comment.Body,
+		"author_name": comment.AuthorName,
+		"author_cpf":  comment.AuthorCPF,
+		"mentions":    mentions,
+		"created_at":  comment.CreatedAt,
+		"updated_at":  comment.UpdatedAt,
+		"deleted":     comment.Deleted,
+	}
+}
+
+func commentFromDocument(doc map[string]interface{}) models.ServiceComment {
+	comment := models.ServiceComment{}
+	comment.ID, _ = doc["id"].(string)
+	comment.ServiceID, _ = doc["service_id"].(string)
+	comment.ParentID, _ = doc["parent_id"].(string)
+	comment.Body, _ = doc["body"].(string)
+	comment.AuthorName, _ = doc["author_name"].(string)
+	comment.AuthorCPF, _ = doc["author_cpf"].(string)
+	comment.Deleted, _ = doc["deleted"].(bool)
+	if value, ok := doc["created_at"].(float64); ok {
+		comment.CreatedAt = int64(value)
+	}
+	if value, ok := doc["updated_at"].(float64); ok {
+		comment.UpdatedAt = int64(value)
+	}
+	if mentions, ok := doc["mentions"].([]interface{}); ok {
+		for _, mention := range mentions {
+			if s, ok := mention.(string); ok {
+				comment.Mentions = append(comment.Mentions, s)
+			}
+		}
+	}
+	return comment
+}
+
+// extractMentions retorna os usuários mencionados (@usuario), sem repetição e em minúsculas
+func extractMentions(body string) []string {
+	var mentions []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		mention := strings.ToLower(match[1])
+		if !seen[mention] {
+			seen[mention] = true
+			mentions = append(mentions, mention)
+		}
+	}
+	return mentions
+}
+
+// newMentions retorna as menções de current que não estavam em previous
+func newMentions(previous, current []string) []string {
+	known := make(map[string]bool, len(previous))
+	for _, mention := range previous {
+		known[mention] = true
+	}
+	var added []string
+	for _, mention := range current {
+		if !known[mention] {
+			added = append(added, mention)
+		}
+	}
+	return added
+}
+
+// buildCommentThreads aninha as respostas sob os comentários pai (em ordem cronológica).
+// Respostas cujo pai não existe mais são exibidas como threads.
+func buildCommentThreads(comments []models.ServiceComment) []models.ServiceComment {
+	byID := make(map[string]bool, len(comments))
+	children := make(map[string][]models.ServiceComment)
+	for _, comment := range comments {
+		byID[comment.ID] = true
+	}
+
+	var roots []models.ServiceComment
+	for _, comment := range comments {
+		if comment.ParentID != "" && byID[comment.ParentID] && comment.ParentID != comment.ID {
+			children[comment.ParentID] = append(children[comment.ParentID], comment)
+		} else {
+			roots = append(roots, comment)
+		}
+	}
+
+	var attach func(comment models.ServiceComment, depth int) models.ServiceComment
+	attach = func(comment models.ServiceComment, depth int) models.ServiceComment {
+		if depth > 50 {
+			return comment
+		}
+		for _, child := range children[comment.ID] {
+			comment.Replies = append(comment.Replies, attach(child, depth+1))
+		}
+		sort.SliceStable(comment.Replies, func(i, j int) bool {
+			return comment.Replies[i].CreatedAt < comment.Replies[j].CreatedAt
+		})
+		return comment
+	}
+
+	threads := make([]models.ServiceComment, 0, len(roots))
+	for _, root := range roots {
+		threads = append(threads, attach(root, 0))
+	}
+	sort.SliceStable(threads, func(i, j int) bool {
+		return threads[i].CreatedAt < threads[j].CreatedAt
+	})
+	return threads
+}
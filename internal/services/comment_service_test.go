@@ -0,0 +1,52 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestExtractMentions(t *testing.T) {
+	got := extractMentions("@Joao.Silva pode revisar? cc @maria_souza e @joao.silva. E-mail: fulano@rio.rj.gov.br")
+	want := []string{"joao.silva", "maria_souza"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractMentions = %v, want %v", got, want)
+	}
+
+	if got := newMentions([]string{"joao.silva"}, []string{"joao.silva", "ana"}); !reflect.DeepEqual(got, []string{"ana"}) {
+		t.Errorf("newMentions = %v", got)
+	}
+}
+
+func TestBuildCommentThreads(t *testing.T) {
+	comments := []models.ServiceComment{
+		{ID: "a", CreatedAt: 1},
+		{ID: "b", ParentID: "a", CreatedAt: 2},
+		{ID: "c", CreatedAt: 3},
+		{ID: "d", ParentID: "b", CreatedAt: 4},
+		{ID: "e", ParentID: "removido", CreatedAt: 5},
+	}
+
+	threads := buildCommentThreads(comments)
+	if len(threads) != 3 || threads[0].ID != "a" || threads[1].ID != "c" || threads[2].ID != "e" {
+		t.Fatalf("threads inesperadas: %+v", threads)
+	}
+	if len(threads[0].Replies) != 1 || threads[0].Replies[0].ID != "b" || threads[0].Replies[0].Replies[0].ID != "d" {
+		t.Errorf("respostas aninhadas inesperadas: %+v", threads[0].Replies)
+	}
+}
+
+func TestCommentDocumentRoundTrip(t *testing.T) {
+	comment := &models.ServiceComment{ID: "x", ServiceID: "s", Body: "ok @ana", AuthorCPF: "123", Mentions: []string{"ana"}, CreatedAt: 10}
+
+	// Simula o documento retornado pelo Typesense (JSON: números como float64, listas como []interface{})
+	doc := commentToDocument(comment)
+	doc["created_at"] = float64(10)
+	doc["mentions"] = []interface{}{"ana"}
+
+	got := commentFromDocument(doc)
+	if got.ID != "x" || got.AuthorCPF != "123" || got.CreatedAt != 10 || !reflect.DeepEqual(got.Mentions, []string{"ana"}) {
+		t.Errorf("comentário inesperado: %+v", got)
+	}
+}
@@ -0,0 +1,389 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+const (
+	ServiceVersionsCollection = "service_versions"
+	TombamentosCollection     = "tombamentos_overlay"
+	HubSearchCollection       = "hub_search"
+)
+
+// ConsistencyService detecta (e opcionalmente corrige) inconsistências entre collections
+type ConsistencyService struct {
+	client *typesense.Client
+}
+
+// NewConsistencyService cria um novo verificador de consistência
+func NewConsistencyService(client *typesense.Client) *ConsistencyService {
+	return &ConsistencyService{
+		client: client,
+	}
+}
+
+// Check executa todas as verificações. Com fix=true remove os documentos órfãos encontrados;
+// versões órfãs são apenas reportadas
+func (cs *ConsistencyService) Check(ctx context.Context, fix bool) (*models.ConsistencyReport, error) {
+	start := time.Now()
+
+	report := &models.ConsistencyReport{
+		CheckedAt:  start.Unix(),
+		FixApplied: fix,
+		Summary:    make(map[models.ConsistencyIssueType]int),
+		Issues:     []models.ConsistencyIssue{},
+	}
+
+	liveServices, err := cs.exportIDs(ctx, CollectionName, "")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar serviços: %w", err)
+	}
+
+	checks := []struct {
+		name string
+		run  func() ([]models.ConsistencyIssue, error)
+	}{
+		{"versões órfãs", func() ([]models.ConsistencyIssue, error) { return cs.checkOrphanVersions(ctx, liveServices) }},
+		{"tombamentos", func() ([]models.ConsistencyIssue, error) { return cs.checkTombamentos(ctx, liveServices) }},
+		{"hub_search", func() ([]models.ConsistencyIssue, error) { return cs.checkHubEntries(ctx) }},
+		{"aliases e backups", func() ([]models.ConsistencyIssue, error) { return cs.checkAliasesAndBackups(ctx) }},
+	}
+
+	for _, check := range checks {
+		issues, err := check.run()
+		if err != nil {
+			return nil, fmt.Errorf("erro ao verificar %s: %w", check.name, err)
+		}
+		report.Issues = append(report.Issues, issues...)
+	}
+
+	if fix {
+		for i := range report.Issues {
+			if !report.Issues[i].Fixable {
+				continue
+			}
+			if err := cs.fixIssue(ctx, &report.Issues[i]); err != nil {
+				report.Issues[i].FixError = err.Error()
+				log.Printf("[Consistency] Erro ao corrigir %s %s: %v", report.Issues[i].Type, report.Issues[i].DocumentID, err)
+				continue
+			}
+			report.Issues[i].Fixed = true
+			report.TotalFixed++
+		}
+	}
+
+	for _, issue := range report.Issues {
+		report.Summary[issue.Type]++
+	}
+	report.DurationMs = time.Since(start).Milliseconds()
+
+	return report, nil
+}
+
+// checkOrphanVersions encontra serviços com versões mas sem documento vivo.
+// Serviços excluídos pelo fluxo normal possuem uma versão "delete" e não são considerados órfãos.
+// Versões pendentes pertencem a criações em andamento ou interrompidas e são resolvidas pela
+// reconciliação do histórico, não por esta verificação; versões criadas dentro de
+// VersionPendingGrace também são ignoradas. As inconsistências são apenas reportadas: o histórico
+// é a fonte da reconstrução da collection (RebuildFromVersions) e nunca é removido pelo --fix.
+func (cs *ConsistencyService) checkOrphanVersions(ctx context.Context, liveServices map[string]map[string]interface{}) ([]models.ConsistencyIssue, error) {
+	versions, err := cs.exportDocuments(ctx, ServiceVersionsCollection, "id,service_id,change_type,pending,created_at")
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-VersionPendingGrace).Unix()
+	versionCount := make(map[string]int)
+	deleted := make(map[string]bool)
+	for _, version := range versions {
+		if pending, _ := version["pending"].(bool); pending {
+			continue
+		}
+		if createdAt, _ := version["created_at"].(float64); int64(createdAt) > cutoff {
+			continue
+		}
+		serviceID := getString(version, "service_id")
+		versionCount[serviceID]++
+		if getString(version, "change_type") == "delete" {
+			deleted[serviceID] = true
+		}
+	}
+
+	issues := []models.ConsistencyIssue{}
+	for _, serviceID := range sortedKeys(versionCount) {
+		if _, alive := liveServices[serviceID]; alive || deleted[serviceID] {
+			continue
+		}
+		issues = append(issues, models.ConsistencyIssue{
+			Type:       models.ConsistencyOrphanVersion,
+			Collection: ServiceVersionsCollection,
+			DocumentID: serviceID,
+			Reference:  serviceID,
+			Detail:     fmt.Sprintf("%d versões para serviço inexistente sem registro de exclusão", versionCount[serviceID]),
+		})
+	}
+
+	return issues, nil
+}
+
+// checkTombamentos encontra tombamentos cujo serviço novo não existe mais
+func (cs *ConsistencyService) checkTombamentos(ctx context.Context, liveServices map[string]map[string]interface{}) ([]models.ConsistencyIssue, error) {
+	tombamentos, err := cs.exportDocuments(ctx, TombamentosCollection, "id,origem,id_servico_antigo,id_servico_novo")
+	if err != nil {
+		return nil, err
+	}
+
+	issues := []models.ConsistencyIssue{}
+	for _, tombamento := range tombamentos {
+		novoID := getString(tombamento, "id_servico_novo")
+		if _, alive := liveServices[novoID]; alive {
+			continue
+		}
+		issues = append(issues, models.ConsistencyIssue{
+			Type:       models.ConsistencyDanglingTombamento,
+			Collection: TombamentosCollection,
+			DocumentID: getString(tombamento, "id"),
+			Reference:  novoID,
+			Detail: fmt.Sprintf("tombamento de %s/%s aponta para serviço inexistente",
+				getString(tombamento, "origem"), getString(tombamento, "id_servico_antigo")),
+			Fixable: true,
+		})
+	}
+
+	return issues, nil
+}
+
+// checkHubEntries encontra entradas do hub_search cujo documento de origem sumiu
+func (cs *ConsistencyService) checkHubEntries(ctx context.Context) ([]models.ConsistencyIssue, error) {
+	entries, err := cs.exportDocuments(ctx, HubSearchCollection, "id,source_collection,source_id")
+	if err != nil {
+		return nil, err
+	}
+
+	sourceIDs := make(map[string]map[string]map[string]interface{})
+	missingCollections := make(map[string]bool)
+
+	issues := []models.ConsistencyIssue{}
+	for _, entry := range entries {
+		sourceCollection := getString(entry, "source_collection")
+		sourceID := getString(entry, "source_id")
+
+		if _, loaded := sourceIDs[sourceCollection]; !loaded && !missingCollections[sourceCollection] {
+			ids, err := cs.exportIDs(ctx, sourceCollection, "")
+			if err != nil {
+				if !IsNotFoundError(err) {
+					return nil, fmt.Errorf("erro ao listar collection de origem %s: %w", sourceCollection, err)
+				}
+				missingCollections[sourceCollection] = true
+			} else {
+				sourceIDs[sourceCollection] = ids
+			}
+		}
+
+		detail := ""
+		if missingCollections[sourceCollection] {
+			detail = fmt.Sprintf("collection de origem %s não existe", sourceCollection)
+		} else if _, exists := sourceIDs[sourceCollection][sourceID]; !exists {
+			detail = fmt.Sprintf("documento de origem %s/%s não existe", sourceCollection, sourceID)
+		}
+
+		if detail != "" {
+			issues = append(issues, models.ConsistencyIssue{
+				Type:       models.ConsistencyOrphanHubEntry,
+				Collection: HubSearchCollection,
+				DocumentID: getString(entry, "id"),
+				Reference:  sourceCollection + "/" + sourceID,
+				Detail:     detail,
+				Fixable:    true,
+			})
+		}
+	}
+
+	return issues, nil
+}
+
+// checkAliasesAndBackups compara o alias de serviços e os backups com o histórico de migrações
+func (cs *ConsistencyService) checkAliasesAndBackups(ctx context.Context) ([]models.ConsistencyIssue, error) {
+	collections, err := cs.client.Collections().Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar collections: %w", err)
+	}
+	existing := make(map[string]bool)
+	for _, coll := range collections {
+		existing[coll.Name] = true
+	}
+
+	aliases, err := cs.client.Aliases().Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar aliases: %w", err)
+	}
+
+	migrations, err := cs.exportDocuments(ctx, MigrationControlCollection, "")
+	if err != nil {
+		return nil, err
+	}
+
+	issues := []models.ConsistencyIssue{}
+
+	// Alias deve apontar para uma collection existente e, se houver migrações, para o destino da última concluída
+	var latest map[string]interface{}
+	for _, migration := range migrations {
+		if getString(migration, "status") != string(models.MigrationStatusCompleted) {
+			continue
+		}
+		if latest == nil || getInt64(migration, "completed_at") > getInt64(latest, "completed_at") {
+			latest = migration
+		}
+	}
+
+	for _, alias := range aliases {
+		if alias.Name == nil || *alias.Name != PrefRioServicesCollection {
+			continue
+		}
+		if !existing[alias.CollectionName] {
+			issues = append(issues, models.ConsistencyIssue{
+				Type:       models.ConsistencyAliasMismatch,
+				Collection: PrefRioServicesCollection,
+				Reference:  alias.CollectionName,
+				Detail:     "alias aponta para collection inexistente",
+			})
+			continue
+		}
+		if latest != nil {
+			expected := getString(latest, "target_collection")
+			if expected != "" && expected != alias.CollectionName {
+				issues = append(issues, models.ConsistencyIssue{
+					Type:       models.ConsistencyAliasMismatch,
+					Collection: PrefRioServicesCollection,
+					Reference:  alias.CollectionName,
+					Detail:     fmt.Sprintf("alias aponta para %s, mas a última migração concluída (%s) usa %s", alias.CollectionName, getString(latest, "id"), expected),
+				})
+			}
+		}
+	}
+
+	// Backups referenciados por migrações devem existir, e todo backup deve estar registrado
+	tracked := make(map[string]bool)
+	for _, migration := range migrations {
+		backup := getString(migration, "backup_collection")
+		if backup == "" {
+			continue
+		}
+		tracked[backup] = true
+		if getString(migration, "status") == string(models.MigrationStatusCompleted) && !existing[backup] {
+			issues = append(issues, models.ConsistencyIssue{
+				Type:       models.ConsistencyMissingBackup,
+				Collection: MigrationControlCollection,
+				DocumentID: getString(migration, "id"),
+				Reference:  backup,
+				Detail:     "migração concluída referencia backup inexistente (rollback impossível)",
+			})
+		}
+	}
+
+	for _, name := range sortedKeys(existing) {
+		if strings.HasPrefix(name, BackupCollectionPrefix) && !tracked[name] {
+			issues = append(issues, models.ConsistencyIssue{
+				Type:       models.ConsistencyUntrackedBackup,
+				Collection: name,
+				Detail:     "collection de backup sem registro em _migration_control",
+			})
+		}
+	}
+
+	return issues, nil
+}
+
+// fixIssue remove o(s) documento(s) órfão(s) associados à inconsistência
+func (cs *ConsistencyService) fixIssue(ctx context.Context, issue *models.ConsistencyIssue) error {
+	switch issue.Type {
+	case models.ConsistencyDanglingTombamento, models.ConsistencyOrphanHubEntry:
+		_, err := cs.client.Collection(issue.Collection).Document(issue.DocumentID).Delete(ctx)
+		return err
+	default:
+		return fmt.Errorf("tipo %s não é corrigível automaticamente", issue.Type)
+	}
+}
+
+// exportIDs retorna o conjunto de IDs de uma collection
+func (cs *ConsistencyService) exportIDs(ctx context.Context, collection, filterBy string) (map[string]map[string]interface{}, error) {
+	params := &api.ExportDocumentsParams{IncludeFields: pointer.String("id")}
+	if filterBy != "" {
+		params.FilterBy = pointer.String(filterBy)
+	}
+
+	docs, err := cs.export(ctx, collection, params)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]map[string]interface{}, len(docs))
+	for _, doc := range docs {
+		ids[getString(doc, "id")] = doc
+	}
+	return ids, nil
+}
+
+// exportDocuments exporta os documentos de uma collection (vazio se a collection não existir)
+func (cs *ConsistencyService) exportDocuments(ctx context.Context, collection, includeFields string) ([]map[string]interface{}, error) {
+	params := &api.ExportDocumentsParams{}
+	if includeFields != "" {
+		params.IncludeFields = pointer.String(includeFields)
+	}
+
+	docs, err := cs.export(ctx, collection, params)
+	if err != nil {
+		if IsNotFoundError(err) {
+			return []map[string]interface{}{}, nil
+		}
+		return nil, fmt.Errorf("erro ao exportar %s: %w", collection, err)
+	}
+	return docs, nil
+}
+
+func (cs *ConsistencyService) export(ctx context.Context, collection string, params *api.ExportDocumentsParams) ([]map[string]interface{}, error) {
+	body, err := cs.client.Collection(collection).Documents().Export(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return decodeExportedDocuments(body)
+}
+
+// decodeExportedDocuments lê os documentos do JSONL retornado pelo export do Typesense
+func decodeExportedDocuments(body io.Reader) ([]map[string]interface{}, error) {
+	docs := []map[string]interface{}{}
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 50*1024*1024)
+	for scanner.Scan() {
+		var doc map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &doc); err != nil {
+			return nil, fmt.Errorf("erro ao deserializar documento exportado: %w", err)
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, scanner.Err()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
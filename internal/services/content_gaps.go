@@ -0,0 +1,464 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prefeitura-rio/app-busca-search/internal/jobs"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+	"google.golang.org/genai"
+)
+
+const (
+	ContentGapReportsCollection = "content_gap_reports"
+
+	// contentGapMaxClusters limita os temas de um relatório
+	contentGapMaxClusters = 25
+	// contentGapClusterQueries queries mantidas em cada tema do relatório
+	contentGapClusterQueries = 15
+	// contentGapKMeansIterations limita as iterações do k-means
+	contentGapKMeansIterations = 50
+)
+
+const contentGapLabelPrompt = `Os grupos abaixo reúnem buscas feitas no portal de serviços da Prefeitura do Rio que não retornaram nenhum resultado. Cada grupo lista as buscas mais frequentes (com a quantidade).
+
+%s
+Para cada grupo, dê um rótulo curto (até 6 palavras) que descreva a necessidade do cidadão e sugira, em uma frase, o conteúdo (serviço, página ou informação) que o catálogo deveria ter para atendê-la.
+
+Retorne APENAS o JSON:
+{"clusters": [{"id": 0, "label": "...", "suggestion": "..."}]}`
+
+// ContentGapService agrupa por semelhança semântica as queries recentes sem resultados
+// (eventos de search_journeys) e gera o relatório de temas de demanda que o catálogo não
+// cobre, para orientar a criação de conteúdo
+type ContentGapService struct {
+	client       *typesense.Client
+	embeddings   EmbeddingProvider
+	geminiClient *genai.Client
+	model        string
+	days         int
+	maxQueries   int
+}
+
+// NewContentGapService cria o serviço. São agrupadas as maxQueries queries sem resultados mais
+// buscadas nos últimos days dias; sem cliente Gemini, os temas são rotulados pela query mais
+// buscada.
+func NewContentGapService(client *typesense.Client, embeddings EmbeddingProvider, geminiClient *genai.Client, model string, days, maxQueries int) *ContentGapService {
+	return &ContentGapService{
+		client:       client,
+		embeddings:   embeddings,
+		geminiClient: geminiClient,
+		model:        model,
+		days:         days,
+		maxQueries:   maxQueries,
+	}
+}
+
+// GenerateReport agrupa as queries sem resultados do período, rotula os temas e salva o
+// relatório
+func (gs *ContentGapService) GenerateReport(ctx context.Context, generatedBy string) (*models.ContentGapReport, error) {
+	counts, err := gs.zeroResultQueries(ctx, time.Now().AddDate(0, 0, -gs.days).Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ContentGapReport{
+		ID:                uuid.New().String(),
+		GeneratedAt:       time.Now().Unix(),
+		GeneratedBy:       generatedBy,
+		Days:              gs.days,
+		ZeroResultQueries: len(counts),
+		Clusters:          []models.ContentGapCluster{},
+	}
+
+	queries := topQueries(counts, gs.maxQueries)
+	vectors, queries := gs.embed(ctx, queries, report)
+	report.ClusteredQueries = len(queries)
+
+	if len(queries) > 0 {
+		weights := make([]float64, len(queries))
+		for i, query := range queries {
+			weights[i] = float64(counts[query])
+		}
+		assignments := kMeans(vectors, weights, contentGapClusterCount(len(queries)))
+		report.Clusters = buildContentGapClusters(queries, counts, assignments)
+		gs.labelClusters(ctx, report)
+	}
+
+	if err := gs.saveReport(ctx, report); err != nil {
+		return nil, fmt.Errorf("erro ao salvar relatório: %w", err)
+	}
+
+	log.Printf("[ContentGaps] Relatório %s gerado: %d queries sem resultado, %d temas",
+		report.ID, report.ZeroResultQueries, len(report.Clusters))
+	return report, nil
+}
+
+// GetLatestReport retorna o relatório mais recente (nil se nenhum foi gerado)
+func (gs *ContentGapService) GetLatestReport(ctx context.Context) (*models.ContentGapReport, error) {
+	if err := gs.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := gs.client.Collection(ContentGapReportsCollection).Documents().Search(ctx, &api.SearchCollectionParams{
+		Q:       pointer.String("*"),
+		Page:    pointer.Int(1),
+		PerPage: pointer.Int(1),
+		SortBy:  pointer.String("generated_at:desc"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar relatório: %w", err)
+	}
+	if result.Hits == nil || len(*result.Hits) == 0 || (*result.Hits)[0].Document == nil {
+		return nil, nil
+	}
+
+	raw, _ := (*(*result.Hits)[0].Document)["report"].(string)
+	var report models.ContentGapReport
+	if err := json.Unmarshal([]byte(raw), &report); err != nil {
+		return nil, fmt.Errorf("erro ao deserializar relatório: %w", err)
+	}
+	return &report, nil
+}
+
+// StartWeeklyRoutine agenda a geração semanal do relatório (segundas, 5h), executada por uma
+// única réplica (job content-gaps)
+func (gs *ContentGapService) StartWeeklyRoutine(runner *jobs.Runner) error {
+	return runner.Register("content-gaps", "0 5 * * 1", 30*time.Minute, func(ctx context.Context) error {
+		if _, err := gs.GenerateReport(ctx, "scheduler"); err != nil {
+			return fmt.Errorf("erro ao gerar relatório agendado: %w", err)
+		}
+		return nil
+	})
+}
+
+// zeroResultQueries conta as buscas sem resultados desde o timestamp, por query. Buscas sem
+// resultados são gravadas sem result_count.
+func (gs *ContentGapService) zeroResultQueries(ctx context.Context, since int64) (map[string]int, error) {
+	counts := make(map[string]int)
+	body, err := gs.client.Collection(JourneyEventsCollection).Documents().Export(ctx, &api.ExportDocumentsParams{
+		FilterBy:      pointer.String(fmt.Sprintf("type:=%s && timestamp:>=%d", models.JourneyEventSearch, since)),
+		IncludeFields: pointer.String("query,result_count"),
+	})
+	if err != nil {
+		if IsNotFoundError(err) {
+			return counts, nil
+		}
+		return nil, fmt.Errorf("erro ao exportar buscas: %w", err)
+	}
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event models.JourneyEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || event.ResultCount > 0 || event.Query == "" {
+			continue
+		}
+		counts[event.Query]++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler buscas exportadas: %w", err)
+	}
+	return counts, nil
+}
+
+// embed gera os embeddings das queries e retorna os vetores normalizados com as queries que
+// os obtiveram. Falhas parciais viram notas no relatório.
+func (gs *ContentGapService) embed(ctx context.Context, queries []string, report *models.ContentGapReport) ([][]float64, []string) {
+	if len(queries) == 0 {
+		return nil, nil
+	}
+	if gs.embeddings == nil {
+		report.Notes = append(report.Notes, "embeddings indisponíveis: queries não agrupadas")
+		return nil, nil
+	}
+
+	embeddings, err := gs.embeddings.GenerateBatch(ctx, queries)
+	if err != nil {
+		report.Notes = append(report.Notes, fmt.Sprintf("embeddings: %v", err))
+	}
+
+	vectors := make([][]float64, 0, len(queries))
+	embedded := make([]string, 0, len(queries))
+	for i, query := range queries {
+		if i >= len(embeddings) || len(embeddings[i]) == 0 {
+			continue
+		}
+		if vector := unitVector(embeddings[i]); vector != nil {
+			vectors = append(vectors, vector)
+			embedded = append(embedded, query)
+		}
+	}
+	return vectors, embedded
+}
+
+// labelClusters rotula os temas via Gemini; sem Gemini ou em caso de falha mantém a query
+// mais buscada como rótulo
+func (gs *ContentGapService) labelClusters(ctx context.Context, report *models.ContentGapReport) {
+	if gs.geminiClient == nil {
+		return
+	}
+
+	var groups strings.Builder
+	for i, cluster := range report.Clusters {
+		fmt.Fprintf(&groups, "Grupo %d:\n", i)
+		for _, query := range cluster.Queries {
+			fmt.Fprintf(&groups, "- %s (%d)\n", query.Query, query.Count)
+		}
+		groups.WriteString("\n")
+	}
+
+	prompt := fmt.Sprintf(contentGapLabelPrompt, groups.String())
+	config := &genai.GenerateContentConfig{ResponseMIMEType: "application/json"}
+	resp, err := gs.geminiClient.Models.GenerateContent(ctx, gs.model, []*genai.Content{genai.NewContentFromText(prompt, genai.RoleUser)}, config)
+	if err != nil {
+		report.Notes = append(report.Notes, fmt.Sprintf("rótulos: erro ao chamar Gemini: %v", err))
+		return
+	}
+
+	var answer struct {
+		Clusters []struct {
+			ID         int    `json:"id"`
+			Label      string `json:"label"`
+			Suggestion string `json:"suggestion"`
+		} `json:"clusters"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(resp.Text())), &answer); err != nil {
+		report.Notes = append(report.Notes, fmt.Sprintf("rótulos: erro ao parsear JSON do Gemini: %v", err))
+		return
+	}
+	for _, labeled := range answer.Clusters {
+		if labeled.ID < 0 || labeled.ID >= len(report.Clusters) || strings.TrimSpace(labeled.Label) == "" {
+			continue
+		}
+		report.Clusters[labeled.ID].Label = strings.TrimSpace(labeled.Label)
+		report.Clusters[labeled.ID].Suggestion = strings.TrimSpace(labeled.Suggestion)
+	}
+}
+
+// saveReport persiste o relatório na collection content_gap_reports
+func (gs *ContentGapService) saveReport(ctx context.Context, report *models.ContentGapReport) error {
+	if err := gs.ensureCollection(ctx); err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar relatório: %w", err)
+	}
+
+	doc := map[string]interface{}{
+		"id":                  report.ID,
+		"generated_at":        report.GeneratedAt,
+		"generated_by":        report.GeneratedBy,
+		"zero_result_queries": report.ZeroResultQueries,
+		"clusters":            len(report.Clusters),
+		"report":              string(raw),
+	}
+	_, err = gs.client.Collection(ContentGapReportsCollection).Documents().Create(ctx, doc, &api.DocumentIndexParameters{})
+	return err
+}
+
+// ensureCollection garante que a collection content_gap_reports existe
+func (gs *ContentGapService) ensureCollection(ctx context.Context) error {
+	_, err := gs.client.Collection(ContentGapReportsCollection).Retrieve(ctx)
+	if err == nil {
+		return nil
+	}
+
+	schema := &api.CollectionSchema{
+		Name: ContentGapReportsCollection,
+		Fields: []api.Field{
+			{Name: "generated_at", Type: "int64", Facet: pointer.False()},
+			{Name: "generated_by", Type: "string", Facet: pointer.True()},
+			{Name: "zero_result_queries", Type: "int32", Facet: pointer.False()},
+			{Name: "clusters", Type: "int32", Facet: pointer.False()},
+			{Name: "report", Type: "string", Index: pointer.False(), Optional: pointer.True()},
+		},
+		DefaultSortingField: pointer.String("generated_at"),
+	}
+
+	if _, err := gs.client.Collections().Create(ctx, schema); err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %w", ContentGapReportsCollection, err)
+	}
+	return nil
+}
+
+// topQueries retorna as limit queries mais buscadas (empates em ordem alfabética)
+func topQueries(counts map[string]int, limit int) []string {
+	queries := make([]string, 0, len(counts))
+	for query := range counts {
+		queries = append(queries, query)
+	}
+	sort.Slice(queries, func(i, j int) bool {
+		if counts[queries[i]] != counts[queries[j]] {
+			return counts[queries[i]] > counts[queries[j]]
+		}
+		return queries[i] < queries[j]
+	})
+	if len(queries) > limit {
+		queries = queries[:limit]
+	}
+	return queries
+}
+
+// contentGapClusterCount escolhe a quantidade de temas pela regra sqrt(n/2), limitada a
+// contentGapMaxClusters
+func contentGapClusterCount(n int) int {
+	k := int(math.Round(math.Sqrt(float64(n) / 2)))
+	return max(1, min(k, contentGapMaxClusters, n))
+}
+
+// buildContentGapClusters monta os temas a partir da atribuição do k-means, dos mais aos menos
+// buscados. O rótulo inicial é a query mais buscada do tema.
+func buildContentGapClusters(queries []string, counts map[string]int, assignments []int) []models.ContentGapCluster {
+	byCluster := make(map[int][]models.ContentGapQuery)
+	for i, query := range queries {
+		byCluster[assignments[i]] = append(byCluster[assignments[i]], models.ContentGapQuery{Query: query, Count: counts[query]})
+	}
+
+	clusters := make([]models.ContentGapCluster, 0, len(byCluster))
+	for _, members := range byCluster {
+		sort.Slice(members, func(i, j int) bool {
+			if members[i].Count != members[j].Count {
+				return members[i].Count > members[j].Count
+			}
+			return members[i].Query < members[j].Query
+		})
+		cluster := models.ContentGapCluster{Label: members[0].Query}
+		for _, member := range members {
+			cluster.Searches += member.Count
+		}
+		if len(members) > contentGapClusterQueries {
+			members = members[:contentGapClusterQueries]
+		}
+		cluster.Queries = members
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Searches != clusters[j].Searches {
+			return clusters[i].Searches > clusters[j].Searches
+		}
+		return clusters[i].Label < clusters[j].Label
+	})
+	return clusters
+}
+
+// kMeans agrupa vetores unitários em k grupos por similaridade de cosseno, com centróides
+// ponderados pela frequência das queries. A inicialização k-means++ usa semente fixa, para que
+// o mesmo período gere os mesmos temas.
+func kMeans(vectors [][]float64, weights []float64, k int) []int {
+	n := len(vectors)
+	assignments := make([]int, n)
+	if n == 0 || k <= 1 {
+		return assignments
+	}
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	centroids := [][]float64{vectors[rng.IntN(n)]}
+	distances := make([]float64, n)
+	for len(centroids) < k {
+		total := 0.0
+		for i, vector := range vectors {
+			distances[i] = math.Inf(1)
+			for _, centroid := range centroids {
+				distances[i] = math.Min(distances[i], 1-dotProduct(vector, centroid))
+			}
+			distances[i] = math.Max(distances[i], 0) * weights[i]
+			total += distances[i]
+		}
+		if total == 0 {
+			break // menos vetores distintos que k
+		}
+		target := rng.Float64() * total
+		chosen := n - 1
+		for i, distance := range distances {
+			if target -= distance; target <= 0 {
+				chosen = i
+				break
+			}
+		}
+		centroids = append(centroids, vectors[chosen])
+	}
+
+	for iteration := 0; iteration < contentGapKMeansIterations; iteration++ {
+		changed := iteration == 0
+		for i, vector := range vectors {
+			best, bestSimilarity := 0, math.Inf(-1)
+			for c, centroid := range centroids {
+				if similarity := dotProduct(vector, centroid); similarity > bestSimilarity {
+					best, bestSimilarity = c, similarity
+				}
+			}
+			if assignments[i] != best {
+				assignments[i] = best
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+
+		sums := make([][]float64, len(centroids))
+		for i, vector := range vectors {
+			c := assignments[i]
+			if sums[c] == nil {
+				sums[c] = make([]float64, len(vector))
+			}
+			for d, value := range vector {
+				sums[c][d] += value * weights[i]
+			}
+		}
+		for c, sum := range sums {
+			if sum == nil {
+				continue // grupo vazio mantém o centróide
+			}
+			if unit := unitVector64(sum); unit != nil {
+				centroids[c] = unit
+			}
+		}
+	}
+	return assignments
+}
+
+func dotProduct(a, b []float64) float64 {
+	sum := 0.0
+	for i := range min(len(a), len(b)) {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+// unitVector converte o embedding para float64 com norma 1 (nil para o vetor nulo)
+func unitVector(embedding []float32) []float64 {
+	vector := make([]float64, len(embedding))
+	for i, value := range embedding {
+		vector[i] = float64(value)
+	}
+	return unitVector64(vector)
+}
+
+func unitVector64(vector []float64) []float64 {
+	norm := math.Sqrt(dotProduct(vector, vector))
+	if norm == 0 {
+		return nil
+	}
+	unit := make([]float64, len(vector))
+	for i, value := range vector {
+		unit[i] = value / norm
+	}
+	return unit
+}
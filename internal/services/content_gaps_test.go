@@ -0,0 +1,53 @@
+package services
+
+import (
+	"testing"
+)
+
+func TestKMeansSeparatesThemes(t *testing.T) {
+	counts := map[string]int{
+		"castração gato": 9, "castração cachorro": 5, "vacina pet": 3,
+		"poda de árvore": 7, "árvore caída": 4,
+	}
+	embeddings := map[string][]float32{
+		"castração gato":     {1, 0.1, 0},
+		"castração cachorro": {0.9, 0.2, 0},
+		"vacina pet":         {0.8, 0, 0.1},
+		"poda de árvore":     {0, 1, 0.1},
+		"árvore caída":       {0.1, 0.9, 0},
+	}
+
+	queries := topQueries(counts, 10)
+	if queries[0] != "castração gato" || queries[4] != "vacina pet" {
+		t.Fatalf("ordem inesperada: %v", queries)
+	}
+
+	vectors := make([][]float64, len(queries))
+	weights := make([]float64, len(queries))
+	for i, query := range queries {
+		vectors[i] = unitVector(embeddings[query])
+		weights[i] = float64(counts[query])
+	}
+
+	clusters := buildContentGapClusters(queries, counts, kMeans(vectors, weights, 2))
+	if len(clusters) != 2 {
+		t.Fatalf("esperados 2 temas, obtidos %d: %+v", len(clusters), clusters)
+	}
+	if clusters[0].Label != "castração gato" || clusters[0].Searches != 17 || len(clusters[0].Queries) != 3 {
+		t.Errorf("primeiro tema inesperado: %+v", clusters[0])
+	}
+	if clusters[1].Label != "poda de árvore" || clusters[1].Searches != 11 {
+		t.Errorf("segundo tema inesperado: %+v", clusters[1])
+	}
+}
+
+func TestContentGapClusterCount(t *testing.T) {
+	for n, expected := range map[int]int{1: 1, 2: 1, 8: 2, 50: 5, 500: 16, 5000: contentGapMaxClusters} {
+		if got := contentGapClusterCount(n); got != expected {
+			t.Errorf("n=%d: esperado %d, obtido %d", n, expected, got)
+		}
+	}
+	if unitVector([]float32{0, 0}) != nil {
+		t.Error("vetor nulo não deveria ser normalizado")
+	}
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+)
+
+// webDeepLinkChannel é o canal cujo link é embutido ({url}) nos templates dos demais canais
+const webDeepLinkChannel = "web"
+
+// DeepLinkBuilder gera as URLs canônicas de um serviço por canal (portal, app, WhatsApp...),
+// com os parâmetros UTM gerenciados em um único lugar
+type DeepLinkBuilder struct {
+	channels map[string]*config.DeepLinkChannel
+}
+
+// NewDeepLinkBuilder cria o builder com os canais configurados
+func NewDeepLinkBuilder(channels map[string]*config.DeepLinkChannel) *DeepLinkBuilder {
+	return &DeepLinkBuilder{channels: channels}
+}
+
+// Channels retorna os nomes dos canais configurados, em ordem alfabética
+func (b *DeepLinkBuilder) Channels() []string {
+	names := make([]string, 0, len(b.channels))
+	for name := range b.channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Build gera os deep links do serviço por canal. Serviços sem slug não têm URL canônica e
+// retornam nil.
+func (b *DeepLinkBuilder) Build(service *models.PrefRioService) map[string]string {
+	if b == nil || service == nil || service.Slug == "" || len(b.channels) == 0 {
+		return nil
+	}
+
+	links := make(map[string]string, len(b.channels))
+	for name, channel := range b.channels {
+		if link := b.buildChannel(service, channel); link != "" {
+			links[name] = link
+		}
+	}
+	return links
+}
+
+// buildChannel monta o link de um canal. Quando o template embute {url}, os UTMs do canal são
+// aplicados ao link web embutido (ex.: compartilhamento pelo WhatsApp); caso contrário, ao
+// próprio link gerado.
+func (b *DeepLinkBuilder) buildChannel(service *models.PrefRioService, channel *config.DeepLinkChannel) string {
+	if channel == nil || channel.URLTemplate == "" {
+		return ""
+	}
+
+	if strings.Contains(channel.URLTemplate, "{url}") {
+		embedded := b.embeddedWebURL(service, channel)
+		if embedded == "" {
+			return ""
+		}
+		return expandDeepLinkTemplate(channel.URLTemplate, service, embedded)
+	}
+
+	return withUTM(expandDeepLinkTemplate(channel.URLTemplate, service, ""), channel, service)
+}
+
+// embeddedWebURL monta o link web com os UTMs do canal, embutido ({url}) no template do canal
+func (b *DeepLinkBuilder) embeddedWebURL(service *models.PrefRioService, channel *config.DeepLinkChannel) string {
+	web, ok := b.channels[webDeepLinkChannel]
+	if !ok || web == nil || strings.Contains(web.URLTemplate, "{url}") {
+		return ""
+	}
+	return withUTM(expandDeepLinkTemplate(web.URLTemplate, service, ""), channel, service)
+}
+
+// PageLink retorna a URL da página do serviço atribuída ao canal: o link web com os UTMs do
+// canal quando o template dele embute {url} (ex.: o wa.me do WhatsApp, que não abre o serviço),
+// o próprio link do canal nos demais casos e o link web quando o canal não está configurado
+func (b *DeepLinkBuilder) PageLink(service *models.PrefRioService, channelName string) string {
+	if b == nil || service == nil || service.Slug == "" {
+		return ""
+	}
+	channel := b.channels[channelName]
+	if channel == nil || channel.URLTemplate == "" {
+		return b.buildChannel(service, b.channels[webDeepLinkChannel])
+	}
+	if strings.Contains(channel.URLTemplate, "{url}") {
+		return b.embeddedWebURL(service, channel)
+	}
+	return b.buildChannel(service, channel)
+}
+
+// expandDeepLinkTemplate substitui os placeholders do template, escapando os valores
+func expandDeepLinkTemplate(template string, service *models.PrefRioService, embeddedURL string) string {
+	replacer := strings.NewReplacer(
+		"{slug}", url.PathEscape(service.Slug),
+		"{id}", url.PathEscape(service.ID),
+		"{nome}", url.QueryEscape(service.NomeServico),
+		"{url}", url.QueryEscape(embeddedURL),
+	)
+	return replacer.Replace(template)
+}
+
+// withUTM acrescenta os parâmetros UTM do canal à URL, preservando a query existente
+func withUTM(rawURL string, channel *config.DeepLinkChannel, service *models.PrefRioService) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	query := parsed.Query()
+	if channel.UTMSource != "" {
+		query.Set("utm_source", channel.UTMSource)
+	}
+	if channel.UTMMedium != "" {
+		query.Set("utm_medium", channel.UTMMedium)
+	}
+	if channel.UTMCampaign != "" {
+		campaign := strings.NewReplacer(
+			"{slug}", service.Slug,
+			"{tema}", strings.ReplaceAll(utils.NormalizarCategoria(service.TemaGeral), " ", "-"),
+		).Replace(channel.UTMCampaign)
+		if campaign != "" {
+			query.Set("utm_campaign", campaign)
+		}
+	}
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String()
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestDeepLinkBuilderBuild(t *testing.T) {
+	builder := NewDeepLinkBuilder(config.DefaultDeepLinkChannels())
+	service := &models.PrefRioService{
+		ID:          "abc123",
+		NomeServico: "Matrícula Escolar",
+		TemaGeral:   "Educação",
+		Slug:        "matricula-escolar-abc123",
+	}
+
+	links := builder.Build(service)
+	if len(links) != 3 {
+		t.Fatalf("esperado 3 canais, obtido %v", links)
+	}
+
+	web := "https://prefeitura.rio/servicos/matricula-escolar-abc123?utm_campaign=educacao&utm_medium=web&utm_source=busca"
+	if links["web"] != web {
+		t.Errorf("link web = %s", links["web"])
+	}
+
+	// O link do WhatsApp embute o link do portal com os UTMs do próprio canal
+	whatsapp, err := url.Parse(links["whatsapp"])
+	if err != nil {
+		t.Fatalf("link whatsapp inválido: %v", err)
+	}
+	want := "Matrícula Escolar https://prefeitura.rio/servicos/matricula-escolar-abc123?utm_campaign=educacao&utm_medium=social&utm_source=whatsapp"
+	if got := whatsapp.Query().Get("text"); got != want {
+		t.Errorf("texto whatsapp = %q, esperado %q", got, want)
+	}
+	if whatsapp.Query().Get("utm_source") != "" {
+		t.Errorf("UTMs não deveriam ser aplicados ao link do wa.me: %s", links["whatsapp"])
+	}
+}
+
+func TestDeepLinkBuilderWithoutSlug(t *testing.T) {
+	builder := NewDeepLinkBuilder(config.DefaultDeepLinkChannels())
+	if links := builder.Build(&models.PrefRioService{ID: "x"}); links != nil {
+		t.Errorf("serviço sem slug não deveria ter deep links: %v", links)
+	}
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+// ErrDependencyUnavailable indica falha de dependência com a política "fail"
+var ErrDependencyUnavailable = errors.New("dependência indisponível")
+
+// DegradationMonitor aplica a política de degradação (DEGRADATION_POLICY) e registra as
+// dependências em falha. Uma degradação permanece ativa até a dependência voltar a responder
+// ou até passar window sem novas falhas.
+type DegradationMonitor struct {
+	policy map[string]string
+	window time.Duration
+	now    func() time.Time
+
+	mu     sync.Mutex
+	active map[string]*models.Degradation
+}
+
+// NewDegradationMonitor cria o monitor com a política por dependência (mesclada sobre a padrão)
+func NewDegradationMonitor(policy map[string]string, window time.Duration) *DegradationMonitor {
+	merged := config.DefaultDegradationPolicy()
+	for dependency, action := range policy {
+		merged[dependency] = action
+	}
+	return &DegradationMonitor{
+		policy: merged,
+		window: window,
+		now:    time.Now,
+		active: make(map[string]*models.Degradation),
+	}
+}
+
+// Action retorna o comportamento configurado para a falha da dependência. Sem monitor vale a
+// política padrão.
+func (m *DegradationMonitor) Action(dependency string) string {
+	if m == nil {
+		return config.DefaultDegradationPolicy()[dependency]
+	}
+	return m.policy[dependency]
+}
+
+// Report registra a falha da dependência, marca a requisição como degradada e retorna o
+// comportamento a aplicar
+func (m *DegradationMonitor) Report(ctx context.Context, dependency string, err error) string {
+	action := m.Action(dependency)
+	markDegraded(ctx, dependency+":"+action)
+	if m == nil {
+		return action
+	}
+
+	now := m.now().Unix()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	degradation, ok := m.active[dependency]
+	if !ok || m.expired(degradation) {
+		degradation = &models.Degradation{Dependency: dependency, Action: action, Since: now}
+		m.active[dependency] = degradation
+		log.Printf("[Degradação] %s em falha, aplicando %s: %v", dependency, action, err)
+	}
+	degradation.LastSeenAt = now
+	degradation.Occurrences++
+	if err != nil {
+		degradation.LastError = err.Error()
+	}
+	return action
+}
+
+// Recover encerra a degradação da dependência após uma resposta bem-sucedida
+func (m *DegradationMonitor) Recover(dependency string) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if degradation, ok := m.active[dependency]; ok {
+		delete(m.active, dependency)
+		log.Printf("[Degradação] %s recuperado após %d falhas", dependency, degradation.Occurrences)
+	}
+}
+
+// Active retorna as degradações ativas ordenadas por dependência
+func (m *DegradationMonitor) Active() []models.Degradation {
+	if m == nil {
+		return []models.Degradation{}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	active := make([]models.Degradation, 0, len(m.active))
+	for dependency, degradation := range m.active {
+		if m.expired(degradation) {
+			delete(m.active, dependency)
+			continue
+		}
+		active = append(active, *degradation)
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].Dependency < active[j].Dependency })
+	return active
+}
+
+// IsActive informa se a dependência está degradada
+func (m *DegradationMonitor) IsActive(dependency string) bool {
+	for _, degradation := range m.Active() {
+		if degradation.Dependency == dependency {
+			return true
+		}
+	}
+	return false
+}
+
+// Status retorna a política e as degradações ativas
+func (m *DegradationMonitor) Status() models.DegradationStatus {
+	active := m.Active()
+	policy := config.DefaultDegradationPolicy()
+	if m != nil {
+		policy = make(map[string]string, len(m.policy))
+		for dependency, action := range m.policy {
+			policy[dependency] = action
+		}
+	}
+	return models.DegradationStatus{
+		Degraded:  len(active) > 0,
+		Policy:    policy,
+		Active:    active,
+		Timestamp: time.Now().Unix(),
+	}
+}
+
+// StartNodeProbe verifica periodicamente o /health do nó principal do Typesense. As falhas
+// de nó são contornadas pelo cliente (retry_other_node); a sonda apenas as torna visíveis.
+func (m *DegradationMonitor) StartNodeProbe(nodeURL string, interval time.Duration) {
+	client := &http.Client{Timeout: 3 * time.Second}
+	probe := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := probeTypesenseNode(ctx, client, nodeURL); err != nil {
+			m.Report(ctx, config.DependencyTypesense, err)
+			return
+		}
+		m.Recover(config.DependencyTypesense)
+	}
+
+	go func() {
+		probe()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			probe()
+		}
+	}()
+}
+
+func probeTypesenseNode(ctx context.Context, client *http.Client, nodeURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, nodeURL+"/health", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("nó %s indisponível: %w", nodeURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("nó %s retornou status %d", nodeURL, resp.StatusCode)
+	}
+	return nil
+}
+
+func (m *DegradationMonitor) expired(degradation *models.Degradation) bool {
+	return m.now().Sub(time.Unix(degradation.LastSeenAt, 0)) > m.window
+}
+
+// degradedModesKey chave do contexto com as degradações aplicadas na requisição
+type degradedModesKey struct{}
+
+type degradedModes struct {
+	mu    sync.Mutex
+	modes []string
+}
+
+// withDegradedModes prepara o contexto para registrar as degradações aplicadas na requisição
+func withDegradedModes(ctx context.Context) context.Context {
+	return context.WithValue(ctx, degradedModesKey{}, &degradedModes{})
+}
+
+// markDegraded registra a degradação (dependência:comportamento) na requisição, sem repetições
+func markDegraded(ctx context.Context, mode string) {
+	collected, ok := ctx.Value(degradedModesKey{}).(*degradedModes)
+	if !ok {
+		return
+	}
+	collected.mu.Lock()
+	defer collected.mu.Unlock()
+	for _, existing := range collected.modes {
+		if existing == mode {
+			return
+		}
+	}
+	collected.modes = append(collected.modes, mode)
+}
+
+// degradedModesFrom retorna as degradações aplicadas na requisição
+func degradedModesFrom(ctx context.Context) []string {
+	collected, ok := ctx.Value(degradedModesKey{}).(*degradedModes)
+	if !ok {
+		return nil
+	}
+	collected.mu.Lock()
+	defer collected.mu.Unlock()
+	return append([]string(nil), collected.modes...)
+}
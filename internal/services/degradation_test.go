@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+)
+
+func TestDegradationMonitorReportAndRecover(t *testing.T) {
+	m := NewDegradationMonitor(map[string]string{config.DependencyGemini: config.DegradeFail}, time.Minute)
+	now := time.Unix(1_700_000_000, 0)
+	m.now = func() time.Time { return now }
+
+	ctx := withDegradedModes(context.Background())
+	if action := m.Report(ctx, config.DependencyGemini, errors.New("quota")); action != config.DegradeFail {
+		t.Fatalf("ação inesperada: %s", action)
+	}
+	m.Report(ctx, config.DependencyGemini, errors.New("timeout"))
+	m.Report(ctx, config.DependencyCache, errors.New("redis"))
+
+	if modes := degradedModesFrom(ctx); len(modes) != 2 || modes[0] != "gemini:fail" || modes[1] != "cache:bypass" {
+		t.Errorf("degradações da requisição inesperadas: %v", modes)
+	}
+
+	active := m.Active()
+	if len(active) != 2 || active[1].Dependency != config.DependencyGemini || active[1].Occurrences != 2 || active[1].LastError != "timeout" {
+		t.Fatalf("degradações ativas inesperadas: %+v", active)
+	}
+
+	m.Recover(config.DependencyGemini)
+	if m.IsActive(config.DependencyGemini) || !m.IsActive(config.DependencyCache) {
+		t.Errorf("recuperação inesperada: %+v", m.Active())
+	}
+
+	// Sem novas falhas dentro da janela a degradação expira
+	now = now.Add(2 * time.Minute)
+	if status := m.Status(); status.Degraded || len(status.Active) != 0 {
+		t.Errorf("degradação deveria expirar: %+v", status)
+	}
+}
+
+func TestDegradationMonitorNilUsesDefaultPolicy(t *testing.T) {
+	var m *DegradationMonitor
+	if action := m.Report(context.Background(), config.DependencyGemini, errors.New("x")); action != config.DegradeHybridToKeyword {
+		t.Errorf("ação padrão inesperada: %s", action)
+	}
+	if m.IsActive(config.DependencyGemini) {
+		t.Error("monitor nil não registra degradações")
+	}
+}
@@ -0,0 +1,24 @@
+package services
+
+import (
+	"sort"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+// isDeprecated indica se o documento foi marcado como descontinuado
+func isDeprecated(doc *models.ServiceDocument) bool {
+	if doc == nil || doc.Metadata == nil {
+		return false
+	}
+	deprecated, _ := doc.Metadata["deprecated"].(bool)
+	return deprecated
+}
+
+// demoteDeprecated move os serviços descontinuados para o fim dos resultados, preservando
+// a ordem relativa de relevância dentro de cada grupo
+func demoteDeprecated(results []*models.ServiceDocument) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return !isDeprecated(results[i]) && isDeprecated(results[j])
+	})
+}
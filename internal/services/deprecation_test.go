@@ -0,0 +1,26 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestDemoteDeprecated(t *testing.T) {
+	results := []*models.ServiceDocument{
+		{ID: "antigo", Metadata: map[string]interface{}{"deprecated": true}},
+		{ID: "a"},
+		{ID: "b", Metadata: map[string]interface{}{"deprecated": false}},
+		{ID: "legado", Metadata: map[string]interface{}{"deprecated": true}},
+		{ID: "c"},
+	}
+
+	demoteDeprecated(results)
+
+	expected := []string{"a", "b", "c", "antigo", "legado"}
+	for i, id := range expected {
+		if results[i].ID != id {
+			t.Fatalf("posição %d: esperado %s, obtido %s", i, id, results[i].ID)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+// destaqueActive indica se o serviço está em destaque: fixar_destaque marcado e destaque_until
+// (0 = sem limite) ainda não vencido
+func destaqueActive(doc *models.ServiceDocument, now time.Time) bool {
+	if doc.Metadata == nil {
+		return false
+	}
+	if featured, _ := doc.Metadata["fixar_destaque"].(bool); !featured {
+		return false
+	}
+	until := getInt64(doc.Metadata, "destaque_until")
+	return until == 0 || until > now.Unix()
+}
+
+// destaqueFactor retorna o fator do boost de destaque do serviço: boost se o destaque está
+// ativo, 1 caso contrário
+func destaqueFactor(doc *models.ServiceDocument, boost float64, now time.Time) float64 {
+	if boost <= 1 || !destaqueActive(doc, now) {
+		return 1
+	}
+	return boost
+}
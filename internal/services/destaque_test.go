@@ -0,0 +1,56 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestDestaqueFactor(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	doc := func(metadata map[string]interface{}) *models.ServiceDocument {
+		return &models.ServiceDocument{Metadata: metadata}
+	}
+
+	cases := []struct {
+		name  string
+		doc   *models.ServiceDocument
+		boost float64
+		want  float64
+	}{
+		{"sem destaque", doc(map[string]interface{}{"fixar_destaque": false}), 1.5, 1},
+		{"destaque sem limite", doc(map[string]interface{}{"fixar_destaque": true}), 1.5, 1.5},
+		{"destaque vigente", doc(map[string]interface{}{"fixar_destaque": true, "destaque_until": float64(now.Unix() + 60)}), 1.5, 1.5},
+		{"destaque vencido", doc(map[string]interface{}{"fixar_destaque": true, "destaque_until": float64(now.Unix())}), 1.5, 1},
+		{"boost desabilitado", doc(map[string]interface{}{"fixar_destaque": true}), 1, 1},
+		{"sem metadata", doc(nil), 1.5, 1},
+	}
+	for _, tc := range cases {
+		if got := destaqueFactor(tc.doc, tc.boost, now); got != tc.want {
+			t.Errorf("%s: fator %v, esperado %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestApplyScoreThresholdDestaqueBoost(t *testing.T) {
+	ss := &SearchService{destaqueBoost: 2}
+	docs := []*models.ServiceDocument{
+		{ID: "comum", Metadata: map[string]interface{}{"text_match": int64(1000)}},
+		{ID: "destaque", Metadata: map[string]interface{}{"text_match": int64(100), "fixar_destaque": true}},
+		{ID: "vencido", Metadata: map[string]interface{}{"text_match": int64(10), "fixar_destaque": true, "destaque_until": int64(1)}},
+	}
+
+	filtered, meta := ss.applyScoreThreshold(docs, &models.SearchRequest{}, models.SearchTypeKeyword)
+	if filtered[0].ID != "destaque" || meta["destaque_boost_applied"] != true {
+		t.Fatalf("serviço em destaque deveria subir para o topo: %s, meta %v", filtered[0].ID, meta)
+	}
+	info := filtered[0].Metadata["score_info"].(*models.ScoreInfo)
+	if *info.DestaqueBoost != 2 || *info.DestaqueContribution != *info.FinalScore/2 {
+		t.Errorf("contribuição do destaque inconsistente: %+v", info)
+	}
+	expired := filtered[2].Metadata["score_info"].(*models.ScoreInfo)
+	if filtered[2].ID != "vencido" || expired.DestaqueBoost != nil {
+		t.Errorf("destaque vencido não deveria receber boost: %s %+v", filtered[2].ID, expired)
+	}
+}
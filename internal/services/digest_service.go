@@ -0,0 +1,338 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/jobs"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense"
+)
+
+// OwnerDigestEvent evento enviado ao webhook com o resumo de cada órgão gestor
+const OwnerDigestEvent = "owner_digest"
+
+// Notas incluídas em todos os resumos sobre sinais ainda não disponíveis
+var digestUnavailableNotes = []string{
+	"Desempenho de busca por serviço indisponível: não há analytics de buscas por serviço",
+	"Score de qualidade indisponível: são usadas as pendências do último relatório de atualização",
+}
+
+// DigestService gera e envia resumos periódicos (diários/semanais) para os órgãos gestores
+type DigestService struct {
+	client           *typesense.Client
+	freshness        *FreshnessService
+	notifier         *WebhookNotifier
+	mailer           *Mailer
+	links            *linkChecker
+	subscriptions    map[string]*config.DigestSubscription
+	defaultFrequency models.DigestFrequency
+}
+
+// NewDigestService cria o serviço de resumos. Órgãos sem inscrição em subscriptions recebem
+// o resumo via webhook na defaultFrequency (vazia desabilita)
+func NewDigestService(client *typesense.Client, freshness *FreshnessService, notifier *WebhookNotifier, mailer *Mailer, subscriptions map[string]*config.DigestSubscription, defaultFrequency string) *DigestService {
+	return &DigestService{
+		client:           client,
+		freshness:        freshness,
+		notifier:         notifier,
+		mailer:           mailer,
+		links:            newLinkChecker(5*time.Second, 8),
+		subscriptions:    subscriptions,
+		defaultFrequency: models.DigestFrequency(defaultFrequency),
+	}
+}
+
+// GenerateDigests monta os resumos dos órgãos inscritos na frequência informada e, se
+// deliver for true, envia cada um por webhook e/ou e-mail
+func (ds *DigestService) GenerateDigests(ctx context.Context, frequency models.DigestFrequency, deliver bool) (*models.DigestRun, error) {
+	services, err := ds.fetchServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []models.FreshnessIssue
+	if report, err := ds.freshness.GetLatestReport(ctx); err != nil {
+		log.Printf("[Digest] Erro ao buscar relatório de atualização: %v", err)
+	} else if report != nil {
+		issues = report.Issues
+	}
+
+	now := time.Now()
+	digests := buildOwnerDigests(services, issues, frequency, ds.subscriptionFor, now)
+	ds.attachBrokenLinks(ctx, services, digests)
+
+	run := &models.DigestRun{
+		GeneratedAt: now.Unix(),
+		Frequency:   frequency,
+		Delivered:   deliver,
+		Digests:     digests,
+	}
+
+	if deliver {
+		for i := range run.Digests {
+			ds.deliver(ctx, &run.Digests[i])
+		}
+	}
+
+	return run, nil
+}
+
+// StartRoutine agenda o envio no horário informado (0-23): resumos diários todo dia e
+// semanais às segundas-feiras. Apenas uma réplica envia os resumos de cada dia (job owner-digests).
+func (ds *DigestService) StartRoutine(runner *jobs.Runner, hour int) error {
+	return runner.Register("owner-digests", fmt.Sprintf("0 %d * * *", hour), time.Hour, func(ctx context.Context) error {
+		var errs []error
+		for _, frequency := range dueFrequencies(time.Now()) {
+			if _, err := ds.GenerateDigests(ctx, frequency, true); err != nil {
+				errs = append(errs, fmt.Errorf("erro ao gerar resumos %s: %w", frequency, err))
+			}
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// subscriptionFor retorna a inscrição do órgão ou a padrão (somente webhook)
+func (ds *DigestService) subscriptionFor(orgao string) *config.DigestSubscription {
+	if sub, ok := ds.subscriptions[orgao]; ok && sub != nil {
+		return sub
+	}
+	if ds.defaultFrequency == "" {
+		return nil
+	}
+	return &config.DigestSubscription{Frequency: string(ds.defaultFrequency), Webhook: true}
+}
+
+// fetchServices busca os serviços publicados e os aguardando aprovação
+func (ds *DigestService) fetchServices(ctx context.Context) ([]models.PrefRioService, error) {
+	const perPage = 250
+	var all []models.PrefRioService
+	for page := 1; ; page++ {
+		services, found, err := fetchServicesPage(ctx, ds.client, "status:=1 || awaiting_approval:=true", page, perPage)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar serviços: %w", err)
+		}
+		all = append(all, services...)
+		if len(services) == 0 || page*perPage >= found {
+			break
+		}
+	}
+	return all, nil
+}
+
+// attachBrokenLinks verifica os links dos serviços publicados citados nos resumos
+func (ds *DigestService) attachBrokenLinks(ctx context.Context, services []models.PrefRioService, digests []models.OwnerDigest) {
+	if len(digests) == 0 {
+		return
+	}
+
+	byOrgao := make(map[string]int, len(digests))
+	for i, digest := range digests {
+		byOrgao[digest.OrgaoGestor] = i
+	}
+
+	var urls []string
+	for _, service := range services {
+		if service.Status == 1 && servesAny(service.OrgaoGestor, byOrgao) {
+			urls = append(urls, serviceLinks(&service)...)
+		}
+	}
+	results := ds.links.CheckAll(ctx, urls)
+
+	for _, service := range services {
+		if service.Status != 1 {
+			continue
+		}
+		for _, link := range serviceLinks(&service) {
+			result := results[link]
+			if !result.Broken() {
+				continue
+			}
+			broken := models.DigestBrokenLink{
+				ServiceID:   service.ID,
+				NomeServico: service.NomeServico,
+				URL:         link,
+				StatusCode:  result.StatusCode,
+			}
+			if result.Err != nil {
+				broken.Error = result.Err.Error()
+			}
+			for _, orgao := range service.OrgaoGestor {
+				if i, ok := byOrgao[orgao]; ok {
+					digests[i].BrokenLinks = append(digests[i].BrokenLinks, broken)
+				}
+			}
+		}
+	}
+}
+
+// deliver envia o resumo pelos canais da inscrição do órgão
+func (ds *DigestService) deliver(ctx context.Context, digest *models.OwnerDigest) {
+	sub := ds.subscriptionFor(digest.OrgaoGestor)
+	if sub == nil {
+		return
+	}
+
+	if sub.Webhook && ds.notifier.Enabled() {
+		if err := ds.notifier.Notify(ctx, OwnerDigestEvent, digest); err != nil {
+			log.Printf("[Digest] Erro ao notificar %s via webhook: %v", digest.OrgaoGestor, err)
+		}
+	}
+
+	if len(sub.Emails) > 0 && ds.mailer.Enabled() {
+		subject := fmt.Sprintf("Resumo %s dos serviços - %s", frequencyLabel(digest.Frequency), digest.OrgaoGestor)
+		if err := ds.mailer.Send(sub.Emails, subject, renderDigestText(digest)); err != nil {
+			log.Printf("[Digest] Erro ao enviar e-mail para %s: %v", digest.OrgaoGestor, err)
+		}
+	}
+}
+
+// buildOwnerDigests agrupa serviços e pendências por órgão gestor inscrito na frequência.
+// Links quebrados são preenchidos depois, pois exigem requisições externas.
+func buildOwnerDigests(services []models.PrefRioService, issues []models.FreshnessIssue, frequency models.DigestFrequency, subscriptionFor func(string) *config.DigestSubscription, now time.Time) []models.OwnerDigest {
+	digests := make(map[string]*models.OwnerDigest)
+	digestFor := func(orgao string) *models.OwnerDigest {
+		if digest, ok := digests[orgao]; ok {
+			return digest
+		}
+		sub := subscriptionFor(orgao)
+		if sub == nil || models.DigestFrequency(sub.Frequency) != frequency {
+			return nil
+		}
+		digest := &models.OwnerDigest{
+			OrgaoGestor:     orgao,
+			Frequency:       frequency,
+			GeneratedAt:     now.Unix(),
+			PendingApproval: []models.DigestServiceRef{},
+			BrokenLinks:     []models.DigestBrokenLink{},
+			QualityIssues:   []models.FreshnessIssue{},
+			Notes:           digestUnavailableNotes,
+			Recipients:      sub.Emails,
+		}
+		digests[orgao] = digest
+		return digest
+	}
+
+	for _, service := range services {
+		for _, orgao := range service.OrgaoGestor {
+			digest := digestFor(orgao)
+			if digest == nil {
+				continue
+			}
+			digest.TotalServices++
+			if service.AwaitingApproval {
+				digest.PendingApproval = append(digest.PendingApproval, models.DigestServiceRef{
+					ServiceID:   service.ID,
+					NomeServico: service.NomeServico,
+					LastUpdate:  service.LastUpdate,
+				})
+			}
+		}
+	}
+
+	for _, issue := range issues {
+		for _, orgao := range issue.OrgaoGestor {
+			if digest := digestFor(orgao); digest != nil {
+				digest.QualityIssues = append(digest.QualityIssues, issue)
+			}
+		}
+	}
+
+	orgaos := make([]string, 0, len(digests))
+	for orgao := range digests {
+		orgaos = append(orgaos, orgao)
+	}
+	sort.Strings(orgaos)
+
+	result := make([]models.OwnerDigest, 0, len(orgaos))
+	for _, orgao := range orgaos {
+		result = append(result, *digests[orgao])
+	}
+	return result
+}
+
+// dueFrequencies retorna as frequências a enviar no dia: diária sempre, semanal às segundas
+func dueFrequencies(day time.Time) []models.DigestFrequency {
+	frequencies := []models.DigestFrequency{models.DigestDaily}
+	if day.Weekday() == time.Monday {
+		frequencies = append(frequencies, models.DigestWeekly)
+	}
+	return frequencies
+}
+
+// serviceLinks retorna os links verificáveis do serviço (botões habilitados e canais digitais)
+func serviceLinks(service *models.PrefRioService) []string {
+	var links []string
+	for _, button := range service.Buttons {
+		if button.IsEnabled && isCheckableURL(button.URLService) {
+			links = append(links, strings.TrimSpace(button.URLService))
+		}
+	}
+	for _, canal := range service.CanaisDigitais {
+		if isCheckableURL(canal) {
+			links = append(links, strings.TrimSpace(canal))
+		}
+	}
+	return links
+}
+
+func servesAny(orgaos []string, set map[string]int) bool {
+	for _, orgao := range orgaos {
+		if _, ok := set[orgao]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func frequencyLabel(frequency models.DigestFrequency) string {
+	if frequency == models.DigestDaily {
+		return "diário"
+	}
+	return "semanal"
+}
+
+// renderDigestText formata o resumo em texto simples para e-mail
+func renderDigestText(digest *models.OwnerDigest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Resumo %s dos serviços de %s\n", frequencyLabel(digest.Frequency), digest.OrgaoGestor)
+	fmt.Fprintf(&b, "Gerado em %s - %d serviço(s)\n\n", time.Unix(digest.GeneratedAt, 0).Format("02/01/2006 15:04"), digest.TotalServices)
+
+	fmt.Fprintf(&b, "Aguardando aprovação (%d):\n", len(digest.PendingApproval))
+	for _, service := range digest.PendingApproval {
+		fmt.Fprintf(&b, "- %s (%s)\n", service.NomeServico, service.ServiceID)
+	}
+
+	fmt.Fprintf(&b, "\nLinks quebrados (%d):\n", len(digest.BrokenLinks))
+	for _, link := range digest.BrokenLinks {
+		reason := link.Error
+		if reason == "" {
+			reason = fmt.Sprintf("status %d", link.StatusCode)
+		}
+		fmt.Fprintf(&b, "- %s: %s (%s)\n", link.NomeServico, link.URL, reason)
+	}
+
+	fmt.Fprintf(&b, "\nPendências de qualidade (%d):\n", len(digest.QualityIssues))
+	for _, issue := range digest.QualityIssues {
+		detail := string(issue.Type)
+		if issue.Detail != "" {
+			detail += ": " + issue.Detail
+		}
+		fmt.Fprintf(&b, "- %s (%s)\n", issue.NomeServico, detail)
+	}
+
+	if len(digest.Notes) > 0 {
+		b.WriteString("\nObservações:\n")
+		for _, note := range digest.Notes {
+			fmt.Fprintf(&b, "- %s\n", note)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestBuildOwnerDigests(t *testing.T) {
+	subs := map[string]*config.DigestSubscription{
+		"SMS":        {Frequency: "weekly", Emails: []string{"sms@rio.rj.gov.br"}},
+		"SMF":        {Frequency: "daily"},
+		"SECONSERVA": {Frequency: "weekly"},
+	}
+	subscriptionFor := func(orgao string) *config.DigestSubscription { return subs[orgao] }
+
+	services := []models.PrefRioService{
+		{ID: "1", NomeServico: "Vacina", OrgaoGestor: []string{"SMS"}, AwaitingApproval: true},
+		{ID: "2", NomeServico: "Consulta", OrgaoGestor: []string{"SMS", "SMF"}},
+		{ID: "3", NomeServico: "IPTU", OrgaoGestor: []string{"SMF"}, AwaitingApproval: true},
+		{ID: "4", NomeServico: "Sem inscrição", OrgaoGestor: []string{"OUTRO"}},
+	}
+	issues := []models.FreshnessIssue{
+		{ServiceID: "2", OrgaoGestor: []string{"SMS", "SMF"}, Type: models.FreshnessIssueStale},
+	}
+
+	digests := buildOwnerDigests(services, issues, models.DigestWeekly, subscriptionFor, time.Unix(1700000000, 0))
+	if len(digests) != 1 {
+		t.Fatalf("expected only SMS digest, got %+v", digests)
+	}
+
+	sms := digests[0]
+	if sms.OrgaoGestor != "SMS" || sms.TotalServices != 2 {
+		t.Fatalf("unexpected digest: %+v", sms)
+	}
+	if len(sms.PendingApproval) != 1 || sms.PendingApproval[0].ServiceID != "1" {
+		t.Errorf("expected service 1 pending approval, got %+v", sms.PendingApproval)
+	}
+	if len(sms.QualityIssues) != 1 {
+		t.Errorf("expected 1 quality issue, got %d", len(sms.QualityIssues))
+	}
+	if len(sms.Recipients) != 1 {
+		t.Errorf("expected recipients from subscription, got %v", sms.Recipients)
+	}
+
+	text := renderDigestText(&sms)
+	if !strings.Contains(text, "Vacina") || !strings.Contains(text, "semanal") {
+		t.Errorf("unexpected digest text:\n%s", text)
+	}
+}
+
+func TestDueFrequencies(t *testing.T) {
+	monday := time.Date(2024, 6, 3, 8, 0, 0, 0, time.UTC)
+	if got := dueFrequencies(monday); len(got) != 2 {
+		t.Errorf("expected daily and weekly on monday, got %v", got)
+	}
+	if got := dueFrequencies(monday.AddDate(0, 0, 1)); len(got) != 1 || got[0] != models.DigestDaily {
+		t.Errorf("expected only daily on tuesday, got %v", got)
+	}
+}
+
+func TestLinkCheckerCheckAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/head-not-allowed":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	checker := newLinkChecker(2*time.Second, 2)
+	results := checker.CheckAll(context.Background(), []string{
+		server.URL + "/ok",
+		server.URL + "/head-not-allowed",
+		server.URL + "/missing",
+		server.URL + "/ok",
+	})
+
+	if results[server.URL+"/ok"].Broken() {
+		t.Error("expected /ok to be reachable")
+	}
+	if results[server.URL+"/head-not-allowed"].Broken() {
+		t.Error("expected GET fallback to succeed")
+	}
+	if !results[server.URL+"/missing"].Broken() {
+		t.Error("expected /missing to be broken")
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import "github.com/prefeitura-rio/app-busca-search/internal/models"
+
+// DiversityConfig limites de resultados do mesmo órgão gestor e do mesmo tema nas primeiras
+// Window posições da primeira página (0 desabilita o limite)
+type DiversityConfig struct {
+	MaxPerOrgao int
+	MaxPerTema  int
+	Window      int
+}
+
+func (dc DiversityConfig) enabled() bool {
+	return dc.Window > 0 && (dc.MaxPerOrgao > 0 || dc.MaxPerTema > 0)
+}
+
+// primaryOrgao retorna o primeiro órgão gestor do documento
+func primaryOrgao(doc *models.ServiceDocument) string {
+	if doc.Metadata == nil {
+		return ""
+	}
+	switch orgaos := doc.Metadata["orgao_gestor"].(type) {
+	case []interface{}:
+		if len(orgaos) > 0 {
+			orgao, _ := orgaos[0].(string)
+			return orgao
+		}
+	case []string:
+		if len(orgaos) > 0 {
+			return orgaos[0]
+		}
+	}
+	return ""
+}
+
+// diversify reordena os resultados para que as primeiras posições não excedam os limites por
+// órgão gestor e por tema. Cada posição recebe o resultado mais relevante que ainda cabe nos
+// limites, de modo que a ordem original muda o mínimo necessário; se nenhum cabe, o mais
+// relevante é mantido. Retorna quantos resultados foram rebaixados.
+func diversify(results []*models.ServiceDocument, cfg DiversityConfig) int {
+	if !cfg.enabled() || len(results) < 2 {
+		return 0
+	}
+
+	original := make(map[*models.ServiceDocument]int, len(results))
+	for i, doc := range results {
+		original[doc] = i
+	}
+
+	remaining := append([]*models.ServiceDocument(nil), results...)
+	orgaoCount := make(map[string]int)
+	temaCount := make(map[string]int)
+	fits := func(doc *models.ServiceDocument) bool {
+		if orgao := primaryOrgao(doc); cfg.MaxPerOrgao > 0 && orgao != "" && orgaoCount[orgao] >= cfg.MaxPerOrgao {
+			return false
+		}
+		if cfg.MaxPerTema > 0 && doc.Category != "" && temaCount[doc.Category] >= cfg.MaxPerTema {
+			return false
+		}
+		return true
+	}
+
+	window := min(cfg.Window, len(results))
+	for pos := 0; pos < window; pos++ {
+		pick := 0
+		for i, doc := range remaining {
+			if fits(doc) {
+				pick = i
+				break
+			}
+		}
+
+		doc := remaining[pick]
+		remaining = append(remaining[:pick], remaining[pick+1:]...)
+		results[pos] = doc
+		orgaoCount[primaryOrgao(doc)]++
+		temaCount[doc.Category]++
+	}
+	copy(results[window:], remaining)
+
+	demoted := 0
+	for i, doc := range results {
+		if i > original[doc] {
+			demoted++
+		}
+	}
+	return demoted
+}
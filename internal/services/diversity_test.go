@@ -0,0 +1,48 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestDiversifyLimitsSameOrgaoInWindow(t *testing.T) {
+	doc := func(id, orgao, tema string) *models.ServiceDocument {
+		return &models.ServiceDocument{ID: id, Category: tema, Metadata: map[string]interface{}{"orgao_gestor": []interface{}{orgao}}}
+	}
+	results := []*models.ServiceDocument{
+		doc("a1", "SMF", "Impostos"),
+		doc("a2", "SMF", "Impostos"),
+		doc("a3", "SMF", "Impostos"),
+		doc("b1", "SMS", "Saúde"),
+		doc("a4", "SMF", "Impostos"),
+		doc("c1", "SMTR", "Transporte"),
+	}
+
+	demoted := diversify(results, DiversityConfig{MaxPerOrgao: 2, Window: 4})
+
+	want := []string{"a1", "a2", "b1", "c1", "a3", "a4"}
+	for i, id := range want {
+		if results[i].ID != id {
+			t.Fatalf("posição %d = %s, ordem esperada %v", i, results[i].ID, want)
+		}
+	}
+	if demoted != 2 {
+		t.Errorf("rebaixados = %d, esperado 2", demoted)
+	}
+}
+
+func TestDiversifyKeepsOrderWhenNothingFits(t *testing.T) {
+	results := []*models.ServiceDocument{
+		{ID: "a", Category: "Impostos"},
+		{ID: "b", Category: "Impostos"},
+		{ID: "c", Category: "Impostos"},
+	}
+
+	if demoted := diversify(results, DiversityConfig{MaxPerTema: 1, Window: 3}); demoted != 0 {
+		t.Errorf("rebaixados = %d, esperado 0", demoted)
+	}
+	if results[0].ID != "a" || results[1].ID != "b" || results[2].ID != "c" {
+		t.Errorf("ordem alterada sem alternativa: %s %s %s", results[0].ID, results[1].ID, results[2].ID)
+	}
+}
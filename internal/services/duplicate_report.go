@@ -0,0 +1,311 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prefeitura-rio/app-busca-search/internal/jobs"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+const (
+	DuplicateReportsCollection = "duplicate_reports"
+
+	// duplicateNeighbors vizinhos mais próximos consultados por serviço
+	duplicateNeighbors = 10
+	// duplicateBatch buscas vetoriais por requisição multi-search
+	duplicateBatch = 50
+)
+
+// DuplicateReportService agrupa serviços publicados com embeddings quase idênticos, sugerindo o
+// serviço canônico de cada grupo e os candidatos a tombamento nele
+type DuplicateReportService struct {
+	client *typesense.Client
+}
+
+// NewDuplicateReportService cria um novo serviço de relatório de duplicatas
+func NewDuplicateReportService(client *typesense.Client) *DuplicateReportService {
+	return &DuplicateReportService{client: client}
+}
+
+// GenerateReport busca os vizinhos mais próximos de cada serviço publicado no índice vetorial
+// (ANN do Typesense), liga os pares com similaridade a partir de threshold e salva os grupos
+func (ds *DuplicateReportService) GenerateReport(ctx context.Context, threshold float64, generatedBy string) (*models.DuplicateReport, error) {
+	if threshold <= 0 || threshold >= 1 {
+		return nil, fmt.Errorf("threshold deve estar entre 0 e 1 (exclusivo)")
+	}
+
+	published := make(map[string]models.PrefRioService)
+	var ids []string
+	for page := 1; ; page++ {
+		services, _, err := fetchServicesPage(ctx, ds.client, "status:=1", page, 250)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar serviços (página %d): %w", page, err)
+		}
+		for _, service := range services {
+			if service.Deprecated {
+				continue
+			}
+			published[service.ID] = service
+			ids = append(ids, service.ID)
+		}
+		if len(services) < 250 {
+			break
+		}
+	}
+
+	report := &models.DuplicateReport{
+		ID:              uuid.New().String(),
+		GeneratedAt:     time.Now().Unix(),
+		GeneratedBy:     generatedBy,
+		Threshold:       threshold,
+		TotalServices:   len(ids),
+		SkippedServices: []string{},
+	}
+
+	pairs := make(map[[2]string]float64)
+	for start := 0; start < len(ids); start += duplicateBatch {
+		batch := ids[start:min(start+duplicateBatch, len(ids))]
+		skipped, err := ds.nearestNeighbors(ctx, batch, threshold, published, pairs)
+		if err != nil {
+			return nil, err
+		}
+		report.SkippedServices = append(report.SkippedServices, skipped...)
+	}
+	report.Compared = len(ids) - len(report.SkippedServices)
+	report.Clusters = buildDuplicateClusters(published, pairs)
+
+	if err := ds.saveReport(ctx, report); err != nil {
+		return nil, fmt.Errorf("erro ao salvar relatório: %w", err)
+	}
+
+	log.Printf("[DuplicateReport] Relatório %s gerado: %d serviços comparados, %d grupos de duplicatas",
+		report.ID, report.Compared, len(report.Clusters))
+	return report, nil
+}
+
+// nearestNeighbors registra em pairs os vizinhos de cada serviço do lote com similaridade a
+// partir de threshold. Retorna os serviços sem embedding comparável (a busca vetorial falha).
+func (ds *DuplicateReportService) nearestNeighbors(ctx context.Context, batch []string, threshold float64, published map[string]models.PrefRioService, pairs map[[2]string]float64) ([]string, error) {
+	searches := make([]api.MultiSearchCollectionParameters, len(batch))
+	for i, id := range batch {
+		searches[i] = api.MultiSearchCollectionParameters{
+			Collection:    pointer.String(CollectionName),
+			Q:             pointer.String("*"),
+			VectorQuery:   pointer.String(fmt.Sprintf("embedding:([], id: %s, k: %d, distance_threshold: %g)", id, duplicateNeighbors+1, 1-threshold)),
+			FilterBy:      pointer.String("status:=1"),
+			IncludeFields: pointer.String("id"),
+			PerPage:       pointer.Int(duplicateNeighbors + 1),
+		}
+	}
+
+	result, err := ds.client.MultiSearch.Perform(ctx, &api.MultiSearchParams{}, api.MultiSearchSearchesParameter{Searches: searches})
+	if err != nil {
+		return nil, fmt.Errorf("erro na busca vetorial: %w", err)
+	}
+
+	var skipped []string
+	for i, res := range result.Results {
+		if i >= len(batch) {
+			break
+		}
+		if res.Error != nil || res.Hits == nil {
+			skipped = append(skipped, batch[i])
+			continue
+		}
+		for _, hit := range *res.Hits {
+			if hit.Document == nil || hit.VectorDistance == nil {
+				continue
+			}
+			neighbor, _ := (*hit.Document)["id"].(string)
+			if _, ok := published[neighbor]; !ok || neighbor == batch[i] {
+				continue
+			}
+			pairs[duplicatePair(batch[i], neighbor)] = 1 - float64(*hit.VectorDistance)
+		}
+	}
+	return skipped, nil
+}
+
+// duplicatePair chave do par, independente da ordem
+func duplicatePair(a, b string) [2]string {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
+
+// buildDuplicateClusters agrupa os pares ligados (componentes conexos). O canônico sugerido é o
+// serviço mais antigo do grupo, do qual os demais provavelmente foram copiados; os candidatos
+// vêm do mais para o menos similar. Grupos maiores e mais similares vêm primeiro.
+func buildDuplicateClusters(services map[string]models.PrefRioService, pairs map[[2]string]float64) []models.DuplicateCluster {
+	parent := make(map[string]string)
+	var find func(id string) string
+	find = func(id string) string {
+		if parent[id] == "" || parent[id] == id {
+			parent[id] = id
+			return id
+		}
+		parent[id] = find(parent[id])
+		return parent[id]
+	}
+	for pair := range pairs {
+		parent[find(pair[0])] = find(pair[1])
+	}
+
+	groups := make(map[string][]string)
+	for id := range parent {
+		root := find(id)
+		groups[root] = append(groups[root], id)
+	}
+
+	clusters := []models.DuplicateCluster{}
+	for _, members := range groups {
+		if len(members) < 2 {
+			continue
+		}
+		sort.Slice(members, func(i, j int) bool {
+			a, b := services[members[i]], services[members[j]]
+			if a.CreatedAt != b.CreatedAt {
+				return a.CreatedAt < b.CreatedAt
+			}
+			return a.ID < b.ID
+		})
+
+		canonical := members[0]
+		cluster := models.DuplicateCluster{Canonical: duplicateService(services[canonical], 0)}
+		for _, id := range members[1:] {
+			similarity, ok := pairs[duplicatePair(canonical, id)]
+			if !ok {
+				for _, other := range members {
+					similarity = max(similarity, pairs[duplicatePair(id, other)])
+				}
+			}
+			cluster.Candidates = append(cluster.Candidates, duplicateService(services[id], similarity))
+		}
+		sort.SliceStable(cluster.Candidates, func(i, j int) bool {
+			return cluster.Candidates[i].Similarity > cluster.Candidates[j].Similarity
+		})
+		clusters = append(clusters, cluster)
+	}
+
+	// Candidatos já ordenados: o primeiro é o mais similar do grupo
+	sort.Slice(clusters, func(i, j int) bool {
+		a, b := clusters[i], clusters[j]
+		if len(a.Candidates) != len(b.Candidates) {
+			return len(a.Candidates) > len(b.Candidates)
+		}
+		if a.Candidates[0].Similarity != b.Candidates[0].Similarity {
+			return a.Candidates[0].Similarity > b.Candidates[0].Similarity
+		}
+		return a.Canonical.ID < b.Canonical.ID
+	})
+	return clusters
+}
+
+func duplicateService(service models.PrefRioService, similarity float64) models.DuplicateService {
+	return models.DuplicateService{
+		ID:          service.ID,
+		NomeServico: service.NomeServico,
+		TemaGeral:   service.TemaGeral,
+		OrgaoGestor: service.OrgaoGestor,
+		CreatedAt:   service.CreatedAt,
+		Similarity:  similarity,
+	}
+}
+
+// GetLatestReport retorna o relatório mais recente (nil se nenhum foi gerado)
+func (ds *DuplicateReportService) GetLatestReport(ctx context.Context) (*models.DuplicateReport, error) {
+	if err := ds.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := ds.client.Collection(DuplicateReportsCollection).Documents().Search(ctx, &api.SearchCollectionParams{
+		Q:       pointer.String("*"),
+		Page:    pointer.Int(1),
+		PerPage: pointer.Int(1),
+		SortBy:  pointer.String("generated_at:desc"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar relatório: %w", err)
+	}
+
+	if result.Hits == nil || len(*result.Hits) == 0 || (*result.Hits)[0].Document == nil {
+		return nil, nil
+	}
+
+	raw, _ := (*(*result.Hits)[0].Document)["report"].(string)
+	var report models.DuplicateReport
+	if err := json.Unmarshal([]byte(raw), &report); err != nil {
+		return nil, fmt.Errorf("erro ao deserializar relatório: %w", err)
+	}
+	return &report, nil
+}
+
+// StartNightlyRoutine agenda a geração diária do relatório no horário informado (0-23),
+// executada por uma única réplica (job duplicate-report)
+func (ds *DuplicateReportService) StartNightlyRoutine(runner *jobs.Runner, hour int, threshold float64) error {
+	return runner.Register("duplicate-report", fmt.Sprintf("0 %d * * *", hour), 30*time.Minute, func(ctx context.Context) error {
+		if _, err := ds.GenerateReport(ctx, threshold, "scheduler"); err != nil {
+			return fmt.Errorf("erro ao gerar relatório agendado: %w", err)
+		}
+		return nil
+	})
+}
+
+// saveReport persiste o relatório na collection duplicate_reports
+func (ds *DuplicateReportService) saveReport(ctx context.Context, report *models.DuplicateReport) error {
+	if err := ds.ensureCollection(ctx); err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar relatório: %w", err)
+	}
+
+	doc := map[string]interface{}{
+		"id":             report.ID,
+		"generated_at":   report.GeneratedAt,
+		"generated_by":   report.GeneratedBy,
+		"total_services": report.TotalServices,
+		"cluster_count":  len(report.Clusters),
+		"report":         string(raw),
+	}
+
+	_, err = ds.client.Collection(DuplicateReportsCollection).Documents().Create(ctx, doc, &api.DocumentIndexParameters{})
+	return err
+}
+
+// ensureCollection garante que a collection duplicate_reports existe
+func (ds *DuplicateReportService) ensureCollection(ctx context.Context) error {
+	_, err := ds.client.Collection(DuplicateReportsCollection).Retrieve(ctx)
+	if err == nil {
+		return nil
+	}
+
+	schema := &api.CollectionSchema{
+		Name: DuplicateReportsCollection,
+		Fields: []api.Field{
+			{Name: "generated_at", Type: "int64", Facet: pointer.False()},
+			{Name: "generated_by", Type: "string", Facet: pointer.True()},
+			{Name: "total_services", Type: "int32", Facet: pointer.False()},
+			{Name: "cluster_count", Type: "int32", Facet: pointer.False()},
+			{Name: "report", Type: "string", Index: pointer.False(), Optional: pointer.True()},
+		},
+		DefaultSortingField: pointer.String("generated_at"),
+	}
+
+	if _, err := ds.client.Collections().Create(ctx, schema); err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %w", DuplicateReportsCollection, err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestBuildDuplicateClusters(t *testing.T) {
+	services := map[string]models.PrefRioService{
+		"a": {ID: "a", NomeServico: "IPTU 2ª via", CreatedAt: 100},
+		"b": {ID: "b", NomeServico: "Segunda via do IPTU", CreatedAt: 50},
+		"c": {ID: "c", NomeServico: "Emitir 2ª via de IPTU", CreatedAt: 300},
+		"d": {ID: "d", NomeServico: "Matrícula escolar", CreatedAt: 10},
+		"e": {ID: "e", NomeServico: "Matrícula na rede municipal", CreatedAt: 20},
+		"f": {ID: "f", NomeServico: "Poda de árvore", CreatedAt: 30},
+	}
+	pairs := map[[2]string]float64{
+		duplicatePair("a", "b"): 0.97,
+		duplicatePair("c", "a"): 0.95, // c só é próximo de a, não do canônico b
+		duplicatePair("d", "e"): 0.99,
+	}
+
+	clusters := buildDuplicateClusters(services, pairs)
+	if len(clusters) != 2 {
+		t.Fatalf("esperados 2 grupos, obtidos %d", len(clusters))
+	}
+
+	iptu := clusters[0]
+	if iptu.Canonical.ID != "b" {
+		t.Errorf("canônico deveria ser o serviço mais antigo (b), obtido %s", iptu.Canonical.ID)
+	}
+	if len(iptu.Candidates) != 2 || iptu.Candidates[0].ID != "a" || iptu.Candidates[1].ID != "c" {
+		t.Fatalf("candidatos inesperados: %+v", iptu.Candidates)
+	}
+	if iptu.Candidates[1].Similarity != 0.95 {
+		t.Errorf("sem par com o canônico, vale a maior similaridade no grupo; obtida %v", iptu.Candidates[1].Similarity)
+	}
+
+	if matricula := clusters[1]; matricula.Canonical.ID != "d" || matricula.Candidates[0].ID != "e" {
+		t.Errorf("grupo de matrícula inesperado: %+v", matricula)
+	}
+}
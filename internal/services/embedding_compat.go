@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+// indexEmbeddingsCacheKey chave do cache com os modelos/dimensões declarados no índice
+const indexEmbeddingsCacheKey = "index:embedding_metadata"
+
+// EmbeddingModelMismatchError indica que o índice contém vetores de um modelo ou dimensão
+// diferente do configurado para as queries, o que tornaria a busca vetorial sem sentido
+type EmbeddingModelMismatchError struct {
+	IndexModels []string
+	IndexDims   []int
+	QueryModel  string
+	QueryDim    int
+}
+
+func (e *EmbeddingModelMismatchError) Error() string {
+	return fmt.Sprintf(
+		"embeddings incompatíveis: o índice %s contém vetores de modelo %v e dimensão %v, mas as queries usam %s (%d dimensões). "+
+			"Regere os embeddings dos documentos com o modelo atual (migração de schema) ou ajuste GEMINI_EMBEDDING_MODEL para o modelo do índice; "+
+			"enquanto isso, use type=keyword",
+		CollectionName, e.IndexModels, e.IndexDims, e.QueryModel, e.QueryDim,
+	)
+}
+
+// indexEmbeddingMetadata modelos e dimensões de embedding presentes no índice
+type indexEmbeddingMetadata struct {
+	models []string
+	dims   []int
+}
+
+// checkEmbeddingCompatibility recusa a busca vetorial se o índice declarar vetores de outro
+// modelo/dimensão. Índices sem metadados (anteriores ao schema v4) são aceitos.
+func (ss *SearchService) checkEmbeddingCompatibility(ctx context.Context) error {
+	metadata := ss.indexEmbeddingMetadata(ctx)
+	if metadata == nil {
+		return nil
+	}
+
+	queryModel := ss.embeddingService.GetModelName()
+	queryDim := ss.embeddingService.GetDimensions()
+
+	for _, model := range metadata.models {
+		if model != queryModel {
+			return &EmbeddingModelMismatchError{IndexModels: metadata.models, IndexDims: metadata.dims, QueryModel: queryModel, QueryDim: queryDim}
+		}
+	}
+	for _, dim := range metadata.dims {
+		if dim != queryDim {
+			return &EmbeddingModelMismatchError{IndexModels: metadata.models, IndexDims: metadata.dims, QueryModel: queryModel, QueryDim: queryDim}
+		}
+	}
+
+	return nil
+}
+
+// indexEmbeddingMetadata consulta (com cache de 5 minutos) os facets de embedding_model e
+// embedding_dim. Retorna nil se os campos ainda não existirem no schema.
+func (ss *SearchService) indexEmbeddingMetadata(ctx context.Context) *indexEmbeddingMetadata {
+	if cached := ss.cache.Get(indexEmbeddingsCacheKey); cached != nil {
+		return cached.(*indexEmbeddingMetadata)
+	}
+
+	result, err := ss.client.Collection(CollectionName).Documents().Search(ctx, &api.SearchCollectionParams{
+		Q:       stringPtr("*"),
+		FacetBy: stringPtr("embedding_model,embedding_dim"),
+		PerPage: intPtr(0),
+	})
+	if err != nil {
+		if !strings.Contains(err.Error(), "facet") {
+			log.Printf("Aviso: não foi possível verificar o modelo de embeddings do índice: %v", err)
+		}
+		return nil
+	}
+
+	metadata := &indexEmbeddingMetadata{}
+	if result.FacetCounts != nil {
+		for _, facet := range *result.FacetCounts {
+			if facet.FieldName == nil || facet.Counts == nil {
+				continue
+			}
+			for _, count := range *facet.Counts {
+				if count.Value == nil || *count.Value == "" {
+					continue
+				}
+				switch *facet.FieldName {
+				case "embedding_model":
+					metadata.models = append(metadata.models, *count.Value)
+				case "embedding_dim":
+					if dim, err := strconv.Atoi(*count.Value); err == nil {
+						metadata.dims = append(metadata.dims, dim)
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(metadata.models)
+	sort.Ints(metadata.dims)
+
+	ss.cache.Set(indexEmbeddingsCacheKey, metadata, 5*time.Minute)
+	return metadata
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckEmbeddingCompatibility(t *testing.T) {
+	cache := NewLRUCache(10)
+	ss := &SearchService{cache: cache, embeddingService: &fakeEmbeddingProvider{}}
+
+	// metadados compatíveis com o provider (modelo "fake", 1 dimensão)
+	cache.Set(indexEmbeddingsCacheKey, &indexEmbeddingMetadata{models: []string{"fake"}, dims: []int{1}}, time.Minute)
+	if err := ss.checkEmbeddingCompatibility(context.Background()); err != nil {
+		t.Errorf("erro inesperado: %v", err)
+	}
+
+	cache.Set(indexEmbeddingsCacheKey, &indexEmbeddingMetadata{models: []string{"fake", "text-embedding-004"}, dims: []int{1}}, time.Minute)
+	var mismatch *EmbeddingModelMismatchError
+	if err := ss.checkEmbeddingCompatibility(context.Background()); !errors.As(err, &mismatch) {
+		t.Errorf("esperado EmbeddingModelMismatchError, obtido %v", err)
+	}
+}
@@ -12,6 +12,9 @@ import (
 	"google.golang.org/genai"
 )
 
+// embeddingCachePrefix prefixo das chaves de embeddings no cache
+const embeddingCachePrefix = CacheEmbedding + ":"
+
 // EmbeddingProvider é a interface para geração de embeddings
 type EmbeddingProvider interface {
 	GenerateEmbedding(ctx context.Context, text string) ([]float32, error)
@@ -178,7 +181,7 @@ func (g *GeminiEmbeddingProvider) GetModelName() string {
 func (g *GeminiEmbeddingProvider) getCacheKey(text string) string {
 	// Usar hash SHA256 para gerar chave única
 	hash := sha256.Sum256([]byte(text))
-	return "embedding:" + hex.EncodeToString(hash[:])
+	return embeddingCachePrefix + hex.EncodeToString(hash[:])
 }
 
 // FormatEmbeddingForTypesense formata um embedding para uso no Typesense
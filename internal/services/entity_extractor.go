@@ -0,0 +1,347 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+	"google.golang.org/genai"
+)
+
+// Origem das entidades extraídas
+const (
+	EntitySourceGemini = "gemini"
+	EntitySourceLocal  = "local"
+)
+
+var (
+	// prazoPattern captura prazos como "5 dias úteis", "48 horas", "até 2 meses"
+	prazoPattern = regexp.MustCompile(`(?i)\b(\d{1,4})\s*(?:\([^)]{1,30}\)\s*)?(dias?\s+[úu]teis|dias?\s+corridos|dias?|horas?|semanas?|meses|m[êe]s)\b`)
+
+	// valorPattern captura valores em reais como "R$ 1.234,56" ou "R$25"
+	valorPattern = regexp.MustCompile(`R\$\s*(\d{1,3}(?:\.\d{3})+(?:,\d{1,2})?|\d+(?:,\d{1,2})?)`)
+
+	// siglaPattern captura siglas de órgãos (ex.: SMS, SMTR, CET-RIO)
+	siglaPattern = regexp.MustCompile(`\b[A-Z]{2,}(?:-[A-Z]{2,})?\b`)
+)
+
+// knownDocuments documentos comumente exigidos, procurados na descrição (forma canônica → variações)
+var knownDocuments = map[string][]string{
+	"CPF":                       {"cpf"},
+	"RG":                        {"rg", "carteira de identidade", "documento de identidade"},
+	"CNH":                       {"cnh", "carteira nacional de habilitação", "carteira de motorista"},
+	"CNPJ":                      {"cnpj"},
+	"Comprovante de residência": {"comprovante de residência", "comprovante de residencia", "comprovante de endereço"},
+	"Certidão de nascimento":    {"certidão de nascimento", "certidao de nascimento"},
+	"Certidão de casamento":     {"certidão de casamento", "certidao de casamento"},
+	"Título de eleitor":         {"título de eleitor", "titulo de eleitor"},
+	"Carteira de trabalho":      {"carteira de trabalho", "ctps"},
+	"Passaporte":                {"passaporte"},
+	"Cartão do SUS":             {"cartão do sus", "cartao do sus", "cartão nacional de saúde"},
+	"Inscrição imobiliária":     {"inscrição imobiliária", "inscricao imobiliaria"},
+}
+
+// nonOrgaoSiglas siglas que não são órgãos (documentos, tributos e termos comuns)
+var nonOrgaoSiglas = map[string]bool{
+	"CPF": true, "RG": true, "CNH": true, "CNPJ": true, "CTPS": true, "SUS": true, "IPTU": true,
+	"ISS": true, "ITBI": true, "CEP": true, "PDF": true, "LAI": true, "LGPD": true, "RJ": true,
+	"NIS": true, "PIS": true, "PASEP": true, "URL": true, "APP": true, "QR": true, "DOU": true,
+}
+
+const entityExtractionPrompt = `Extraia as entidades do texto de um serviço público da Prefeitura do Rio.
+
+Texto:
+%s
+
+Retorne APENAS o JSON:
+{
+  "orgaos": ["siglas ou nomes dos órgãos públicos citados"],
+  "documentos": ["documentos exigidos do cidadão, em forma curta (ex.: CPF, Comprovante de residência)"],
+  "prazos": [{"texto": "trecho original", "dias": 5}],
+  "valores": [{"texto": "trecho original", "valor": 25.50}]
+}
+
+Regras:
+- dias: prazo convertido em dias (horas arredondadas para cima, semanas x7, meses x30)
+- valor: valor em reais como número
+- use listas vazias quando não houver entidades`
+
+// EntityExtractor extrai entidades estruturadas dos serviços: com Gemini quando disponível,
+// caso contrário (ou em caso de falha) com regras locais
+type EntityExtractor struct {
+	geminiClient *genai.Client
+	model        string
+}
+
+// NewEntityExtractor cria o extrator. Sem cliente Gemini, apenas a extração local é usada.
+func NewEntityExtractor(geminiClient *genai.Client, model string) *EntityExtractor {
+	return &EntityExtractor{geminiClient: geminiClient, model: model}
+}
+
+// Extract extrai as entidades de descricao_completa (e campos relacionados) do serviço
+func (e *EntityExtractor) Extract(ctx context.Context, service *models.PrefRioService) *models.ServiceEntities {
+	text := entitySourceText(service)
+
+	var entities *models.ServiceEntities
+	if e != nil && e.geminiClient != nil {
+		extracted, err := e.extractWithGemini(ctx, text)
+		if err != nil {
+			log.Printf("[Entidades] Falha no Gemini para o serviço %s, usando extração local: %v", service.ID, err)
+		} else {
+			entities = extracted
+		}
+	}
+	if entities == nil {
+		entities = extractEntitiesLocal(text)
+	}
+
+	// Documentos e órgãos cadastrados explicitamente sempre entram
+	entities.Documentos = mergeUnique(entities.Documentos, utils.StripMarkdownArray(service.DocumentosNecessarios))
+	entities.Orgaos = mergeUnique(entities.Orgaos, service.OrgaoGestor)
+	entities.ExtractedAt = time.Now().Unix()
+
+	return entities
+}
+
+func (e *EntityExtractor) extractWithGemini(ctx context.Context, text string) (*models.ServiceEntities, error) {
+	prompt := fmt.Sprintf(entityExtractionPrompt, truncateText(text, 8000))
+	config := &genai.GenerateContentConfig{ResponseMIMEType: "application/json"}
+
+	resp, err := e.geminiClient.Models.GenerateContent(ctx, e.model, []*genai.Content{genai.NewContentFromText(prompt, genai.RoleUser)}, config)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao chamar Gemini: %w", err)
+	}
+
+	var answer struct {
+		Orgaos     []string `json:"orgaos"`
+		Documentos []string `json:"documentos"`
+		Prazos     []struct {
+			Texto string `json:"texto"`
+			Dias  int    `json:"dias"`
+		} `json:"prazos"`
+		Valores []struct {
+			Texto string  `json:"texto"`
+			Valor float64 `json:"valor"`
+		} `json:"valores"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(resp.Text())), &answer); err != nil {
+		return nil, fmt.Errorf("erro ao parsear JSON do Gemini: %w", err)
+	}
+
+	entities := &models.ServiceEntities{
+		Orgaos:     mergeUnique(nil, answer.Orgaos),
+		Documentos: mergeUnique(nil, answer.Documentos),
+		Prazos:     []string{},
+		Valores:    []float64{},
+		Source:     EntitySourceGemini,
+	}
+	for _, prazo := range answer.Prazos {
+		if prazo.Dias <= 0 {
+			continue
+		}
+		entities.Prazos = append(entities.Prazos, strings.TrimSpace(prazo.Texto))
+		entities.PrazoDiasMin = minIntPtr(entities.PrazoDiasMin, prazo.Dias)
+	}
+	for _, valor := range answer.Valores {
+		if valor.Valor < 0 {
+			continue
+		}
+		entities.Valores = append(entities.Valores, valor.Valor)
+		entities.ValorMin = minFloatPtr(entities.ValorMin, valor.Valor)
+	}
+
+	return entities, nil
+}
+
+// extractEntitiesLocal extrai entidades com expressões regulares e listas conhecidas
+func extractEntitiesLocal(text string) *models.ServiceEntities {
+	entities := &models.ServiceEntities{
+		Orgaos:     []string{},
+		Documentos: []string{},
+		Prazos:     []string{},
+		Valores:    []float64{},
+		Source:     EntitySourceLocal,
+	}
+
+	for _, match := range prazoPattern.FindAllStringSubmatch(text, -1) {
+		amount, err := strconv.Atoi(match[1])
+		if err != nil || amount <= 0 {
+			continue
+		}
+		entities.Prazos = mergeUnique(entities.Prazos, []string{strings.TrimSpace(match[0])})
+		entities.PrazoDiasMin = minIntPtr(entities.PrazoDiasMin, prazoToDays(amount, match[2]))
+	}
+
+	for _, match := range valorPattern.FindAllStringSubmatch(text, -1) {
+		raw := strings.ReplaceAll(strings.ReplaceAll(match[1], ".", ""), ",", ".")
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			continue
+		}
+		entities.Valores = append(entities.Valores, value)
+		entities.ValorMin = minFloatPtr(entities.ValorMin, value)
+	}
+
+	lower := strings.ToLower(text)
+	for canonical, variants := range knownDocuments {
+		for _, variant := range variants {
+			if containsWord(lower, variant) {
+				entities.Documentos = append(entities.Documentos, canonical)
+				break
+			}
+		}
+	}
+	sort.Strings(entities.Documentos)
+
+	for _, sigla := range siglaPattern.FindAllString(text, -1) {
+		if !nonOrgaoSiglas[sigla] {
+			entities.Orgaos = mergeUnique(entities.Orgaos, []string{sigla})
+		}
+	}
+
+	return entities
+}
+
+// prazoToDays converte o prazo para dias (horas arredondadas para cima)
+func prazoToDays(amount int, unit string) int {
+	unit = strings.ToLower(unit)
+	switch {
+	case strings.HasPrefix(unit, "hora"):
+		return int(math.Ceil(float64(amount) / 24))
+	case strings.HasPrefix(unit, "semana"):
+		return amount * 7
+	case strings.HasPrefix(unit, "mes"), strings.HasPrefix(unit, "mês"):
+		return amount * 30
+	default:
+		return amount
+	}
+}
+
+// entitySourceText reúne os campos textuais de onde as entidades são extraídas
+func entitySourceText(service *models.PrefRioService) string {
+	parts := []string{
+		utils.StripMarkdown(service.DescricaoCompleta),
+		utils.StripMarkdown(service.TempoAtendimento),
+		utils.StripMarkdown(service.CustoServico),
+		utils.StripMarkdown(service.InstrucoesSolicitante),
+	}
+	return strings.Join(parts, "\n")
+}
+
+// containsWord verifica se o termo aparece no texto delimitado por não-letras
+func containsWord(text, term string) bool {
+	for start := 0; ; {
+		idx := strings.Index(text[start:], term)
+		if idx < 0 {
+			return false
+		}
+		begin := start + idx
+		end := begin + len(term)
+		if (begin == 0 || !isLetterByte(text[begin-1])) && (end == len(text) || !isLetterByte(text[end])) {
+			return true
+		}
+		start = begin + 1
+	}
+}
+
+func isLetterByte(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || b >= 0x80
+}
+
+// mergeUnique acrescenta os itens não vazios que ainda não estão na lista (sem diferenciar caixa)
+func mergeUnique(list, items []string) []string {
+	if list == nil {
+		list = []string{}
+	}
+	seen := make(map[string]bool, len(list))
+	for _, item := range list {
+		seen[strings.ToLower(item)] = true
+	}
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" || seen[strings.ToLower(item)] {
+			continue
+		}
+		seen[strings.ToLower(item)] = true
+		list = append(list, item)
+	}
+	return list
+}
+
+func minIntPtr(current *int, value int) *int {
+	if current == nil || value < *current {
+		return &value
+	}
+	return current
+}
+
+func minFloatPtr(current *float64, value float64) *float64 {
+	if current == nil || value < *current {
+		return &value
+	}
+	return current
+}
+
+// EntityHighlights resume as entidades em frases curtas para snippets de resultado
+func EntityHighlights(entities map[string]interface{}) []string {
+	var highlights []string
+	if days, ok := toFloat(entities["prazo_dias_min"]); ok {
+		highlights = append(highlights, fmt.Sprintf("Prazo a partir de %d dia(s)", int(days)))
+	}
+	if value, ok := toFloat(entities["valor_min"]); ok {
+		if value == 0 {
+			highlights = append(highlights, "Gratuito")
+		} else {
+			highlights = append(highlights, "A partir de "+formatReais(value))
+		}
+	}
+	if docs, ok := entities["documentos"].([]interface{}); ok && len(docs) > 0 {
+		names := make([]string, 0, len(docs))
+		for _, doc := range docs {
+			if name, ok := doc.(string); ok {
+				names = append(names, name)
+			}
+		}
+		if len(names) > 3 {
+			names = append(names[:3], "...")
+		}
+		highlights = append(highlights, "Documentos: "+strings.Join(names, ", "))
+	}
+	return highlights
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// formatReais formata o valor no padrão brasileiro (R$ 1.234,56)
+func formatReais(value float64) string {
+	cents := int64(math.Round(value * 100))
+	integer := strconv.FormatInt(cents/100, 10)
+	for i := len(integer) - 3; i > 0; i -= 3 {
+		integer = integer[:i] + "." + integer[i:]
+	}
+	return fmt.Sprintf("R$ %s,%02d", integer, cents%100)
+}
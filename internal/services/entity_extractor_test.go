@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestExtractEntitiesLocal(t *testing.T) {
+	text := `O pedido é analisado pela SMF em até 5 dias úteis. Caso haja pendência, a CET-RIO
+responde em 48 horas. A taxa é de R$ 1.234,56 (ou R$25 para isentos).
+Apresente CPF, carteira de identidade e comprovante de residência.`
+
+	entities := extractEntitiesLocal(text)
+
+	if entities.PrazoDiasMin == nil || *entities.PrazoDiasMin != 2 {
+		t.Errorf("expected minimum prazo of 2 days (48 horas), got %v", entities.PrazoDiasMin)
+	}
+	if len(entities.Prazos) != 2 {
+		t.Errorf("expected 2 prazos, got %v", entities.Prazos)
+	}
+	if entities.ValorMin == nil || *entities.ValorMin != 25 {
+		t.Errorf("expected minimum valor 25, got %v", entities.ValorMin)
+	}
+	if len(entities.Valores) != 2 || entities.Valores[0] != 1234.56 {
+		t.Errorf("unexpected valores: %v", entities.Valores)
+	}
+
+	docs := strings.Join(entities.Documentos, ",")
+	for _, want := range []string{"CPF", "RG", "Comprovante de residência"} {
+		if !strings.Contains(docs, want) {
+			t.Errorf("expected documento %q in %v", want, entities.Documentos)
+		}
+	}
+
+	orgaos := strings.Join(entities.Orgaos, ",")
+	if !strings.Contains(orgaos, "SMF") || !strings.Contains(orgaos, "CET-RIO") || strings.Contains(orgaos, "CPF") {
+		t.Errorf("unexpected orgaos: %v", entities.Orgaos)
+	}
+}
+
+func TestPrazoToDays(t *testing.T) {
+	tests := []struct {
+		amount int
+		unit   string
+		want   int
+	}{
+		{10, "dias úteis", 10},
+		{25, "horas", 2},
+		{2, "semanas", 14},
+		{1, "mês", 30},
+		{3, "meses", 90},
+	}
+	for _, tt := range tests {
+		if got := prazoToDays(tt.amount, tt.unit); got != tt.want {
+			t.Errorf("prazoToDays(%d, %q) = %d, want %d", tt.amount, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestExtractWithoutGeminiMergesRegisteredFields(t *testing.T) {
+	extractor := NewEntityExtractor(nil, "")
+	service := &models.PrefRioService{
+		OrgaoGestor:           []string{"SMS"},
+		DescricaoCompleta:     "Atendimento em até **3 dias**.",
+		DocumentosNecessarios: []string{"**Cartão do SUS**", "CPF"},
+	}
+
+	entities := extractor.Extract(context.Background(), service)
+
+	if entities.Source != EntitySourceLocal || entities.ExtractedAt == 0 {
+		t.Errorf("unexpected source/extracted_at: %s %d", entities.Source, entities.ExtractedAt)
+	}
+	if entities.PrazoDiasMin == nil || *entities.PrazoDiasMin != 3 {
+		t.Errorf("expected prazo 3, got %v", entities.PrazoDiasMin)
+	}
+	if strings.Join(entities.Orgaos, ",") != "SMS" {
+		t.Errorf("expected orgao SMS, got %v", entities.Orgaos)
+	}
+	if !strings.Contains(strings.Join(entities.Documentos, ","), "Cartão do SUS") {
+		t.Errorf("expected registered documents to be merged, got %v", entities.Documentos)
+	}
+}
+
+func TestEntityHighlights(t *testing.T) {
+	highlights := EntityHighlights(map[string]interface{}{
+		"prazo_dias_min": float64(5),
+		"valor_min":      float64(1234.5),
+		"documentos":     []interface{}{"CPF", "RG", "CNH", "Passaporte"},
+	})
+
+	want := []string{"Prazo a partir de 5 dia(s)", "A partir de R$ 1.234,50", "Documentos: CPF, RG, CNH, ..."}
+	if strings.Join(highlights, "|") != strings.Join(want, "|") {
+		t.Errorf("got %v, want %v", highlights, want)
+	}
+}
+
+func TestBuildFilterByEntityFilters(t *testing.T) {
+	prazo := 5
+	valor := 0.0
+	req := &models.SearchRequest{PrazoMaxDias: &prazo, ValorMax: &valor, Documento: "CPF"}
+
+	filter := buildFilterBy(context.Background(), req)
+
+	for _, want := range []string{"entities.prazo_dias_min:<=5", "entities.valor_min:<=0", "entities.documentos:=`CPF`"} {
+		if !strings.Contains(filter, want) {
+			t.Errorf("filter %q missing %q", filter, want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import "github.com/prefeitura-rio/app-busca-search/internal/models"
+
+// ServiceDocumentType tipo dos documentos da collection de serviços na busca multi-collection
+const ServiceDocumentType = "service"
+
+// UnifiedFromSearchResponse adapta a resposta da busca de serviços (envelope 1) ao envelope da
+// busca multi-collection (envelope 2): cada resultado vira um UnifiedDocument da collection de
+// serviços, com os campos de volta aos nomes do Typesense em data. As degradações aplicadas vão
+// em metadata.degraded_mode, já que o envelope 2 não tem o campo.
+func UnifiedFromSearchResponse(response *models.SearchResponse) *models.UnifiedSearchResponse {
+	unified := &models.UnifiedSearchResponse{
+		Results:        make([]*models.UnifiedDocument, 0, len(response.Results)),
+		TotalCount:     response.TotalCount,
+		FilteredCount:  response.FilteredCount,
+		Page:           response.Page,
+		PerPage:        response.PerPage,
+		SearchType:     response.SearchType,
+		Collections:    []string{CollectionName},
+		TypeCounts:     map[string]int{ServiceDocumentType: response.TotalCount},
+		Safety:         response.Safety,
+		QueryMeta:      response.QueryMeta,
+		RelatedQueries: response.RelatedQueries,
+		Interventions:  response.Interventions,
+		PageInfo:       response.PageInfo,
+	}
+	for _, doc := range response.Results {
+		unified.Results = append(unified.Results, unifiedFromServiceDocument(doc))
+	}
+
+	if len(response.Metadata) > 0 || len(response.DegradedMode) > 0 {
+		unified.Metadata = make(map[string]interface{}, len(response.Metadata)+1)
+		for key, value := range response.Metadata {
+			unified.Metadata[key] = value
+		}
+		if len(response.DegradedMode) > 0 {
+			unified.Metadata["degraded_mode"] = response.DegradedMode
+		}
+	}
+	return unified
+}
+
+// unifiedFromServiceDocument desfaz a separação de transformDocument: os campos principais
+// voltam a data com os nomes do Typesense, ao lado do restante do metadata
+func unifiedFromServiceDocument(doc *models.ServiceDocument) *models.UnifiedDocument {
+	data := make(map[string]interface{}, len(doc.Metadata)+10)
+	for key, value := range doc.Metadata {
+		data[key] = value
+	}
+	scoreInfo, _ := data["score_info"].(*models.ScoreInfo)
+	delete(data, "score_info")
+
+	data["id"] = doc.ID
+	data["nome_servico"] = doc.Title
+	data["resumo"] = doc.Description
+	data["tema_geral"] = doc.Category
+	data["slug"] = doc.Slug
+	data["status"] = doc.Status
+	data["created_at"] = doc.CreatedAt
+	data["last_update"] = doc.UpdatedAt
+	if doc.Subcategory != nil {
+		data["sub_categoria"] = *doc.Subcategory
+	}
+	if len(doc.DeepLinks) > 0 {
+		data["deep_links"] = doc.DeepLinks
+	}
+
+	return &models.UnifiedDocument{
+		ID:          doc.ID,
+		Collection:  CollectionName,
+		Type:        ServiceDocumentType,
+		Data:        data,
+		ScoreInfo:   scoreInfo,
+		Title:       doc.Title,
+		Description: doc.Description,
+		Category:    doc.Category,
+		URL:         doc.DeepLinks[webDeepLinkChannel],
+		Annotations: doc.Annotations,
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestUnifiedFromSearchResponse(t *testing.T) {
+	subcategory := "Segunda via"
+	finalScore := 0.8
+	response := &models.SearchResponse{
+		Results: []*models.ServiceDocument{{
+			ID:          "abc",
+			Title:       "IPTU",
+			Description: "Imposto predial",
+			Category:    "Finanças",
+			Subcategory: &subcategory,
+			Slug:        "iptu-abc",
+			Status:      1,
+			Metadata: map[string]interface{}{
+				"orgao_gestor": []interface{}{"SMF"},
+				"score_info":   &models.ScoreInfo{FinalScore: &finalScore},
+			},
+		}},
+		TotalCount:     1,
+		FilteredCount:  1,
+		SearchType:     models.SearchTypeKeyword,
+		DegradedMode:   []string{"gemini:keyword_only"},
+		RelatedQueries: []string{"segunda via iptu"},
+	}
+
+	unified := UnifiedFromSearchResponse(response)
+	if len(unified.Results) != 1 || unified.TypeCounts[ServiceDocumentType] != 1 || unified.Collections[0] != CollectionName {
+		t.Fatalf("envelope inesperado: %+v", unified)
+	}
+	doc := unified.Results[0]
+	if doc.Data["nome_servico"] != "IPTU" || doc.Data["sub_categoria"] != "Segunda via" || doc.Data["orgao_gestor"] == nil {
+		t.Errorf("data deveria trazer os campos com os nomes do Typesense: %v", doc.Data)
+	}
+	if _, ok := doc.Data["score_info"]; ok || doc.ScoreInfo == nil || *doc.ScoreInfo.FinalScore != finalScore {
+		t.Errorf("score_info deveria sair de data para o campo próprio: %+v", doc)
+	}
+	if unified.Metadata["degraded_mode"] == nil || len(unified.RelatedQueries) != 1 {
+		t.Errorf("degradações e sugestões deveriam ser mantidas: %+v", unified)
+	}
+	if _, ok := response.Results[0].Metadata["score_info"]; !ok {
+		t.Error("a resposta original não deveria ser alterada")
+	}
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// DocumentEventType identifica o tipo de alteração em um documento
+type DocumentEventType string
+
+const (
+	DocumentCreated DocumentEventType = "document.created"
+	DocumentUpdated DocumentEventType = "document.updated"
+	DocumentDeleted DocumentEventType = "document.deleted"
+)
+
+// DocumentEvent descreve uma escrita em documentos indexados
+type DocumentEvent struct {
+	Type       DocumentEventType `json:"type"`
+	Collection string            `json:"collection"`
+	DocumentID string            `json:"document_id,omitempty"`
+	Timestamp  int64             `json:"timestamp"`
+	// Origin identifica a réplica de origem; vazio para eventos gerados localmente
+	Origin string `json:"origin,omitempty"`
+}
+
+// EventHandler é chamado de forma síncrona para cada evento publicado
+type EventHandler func(ctx context.Context, event DocumentEvent)
+
+// EventBus distribui eventos de escrita de documentos para os assinantes do processo
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers []EventHandler
+}
+
+// NewEventBus cria um novo barramento de eventos
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Subscribe registra um handler para todos os eventos publicados
+func (b *EventBus) Subscribe(handler EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish entrega o evento a todos os assinantes; falhas de um assinante não afetam os demais
+func (b *EventBus) Publish(ctx context.Context, event DocumentEvent) {
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().Unix()
+	}
+
+	b.mu.RLock()
+	handlers := make([]EventHandler, len(b.handlers))
+	copy(handlers, b.handlers)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("Erro em assinante do evento %s: %v", event.Type, r)
+				}
+			}()
+			handler(ctx, event)
+		}()
+	}
+}
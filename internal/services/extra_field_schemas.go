@@ -0,0 +1,396 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+// ExtraFieldSchemasCollection guarda os schemas de extra_fields por tema
+const ExtraFieldSchemasCollection = "extra_field_schemas"
+
+var (
+	// ErrExtraFieldSchemaNotFound indica tema sem schema cadastrado
+	ErrExtraFieldSchemaNotFound = errors.New("schema de extra_fields não encontrado")
+
+	// ErrInvalidExtraFieldSchema indica schema ou campo promovido inválido
+	ErrInvalidExtraFieldSchema = errors.New("schema de extra_fields inválido")
+)
+
+// promotableTypes tipos Typesense dos campos promovidos por tipo do JSON Schema
+var promotableTypes = map[string]string{
+	"string":  "string",
+	"integer": "int64",
+	"number":  "float",
+	"boolean": "bool",
+}
+
+type extraFieldSchemaEntry struct {
+	schema   models.ExtraFieldSchema
+	compiled *jsonSchema
+	promoted []api.Field
+}
+
+// ExtraFieldSchemas registro dos schemas de extra_fields por tema, geridos pelos
+// administradores. Serviços de temas com schema têm o extra_fields validado ao salvar; os
+// campos promovidos entram no schema da collection na próxima migração (ver
+// MigrationService.SetExtraFieldSchemas).
+type ExtraFieldSchemas struct {
+	client *typesense.Client
+
+	mu      sync.RWMutex
+	entries map[string]*extraFieldSchemaEntry // chave: tema normalizado
+}
+
+// NewExtraFieldSchemas cria o registro vazio; os schemas persistidos são carregados por Reload
+func NewExtraFieldSchemas(client *typesense.Client) *ExtraFieldSchemas {
+	return &ExtraFieldSchemas{client: client, entries: make(map[string]*extraFieldSchemaEntry)}
+}
+
+// List retorna os schemas cadastrados ordenados por tema
+func (s *ExtraFieldSchemas) List() *models.ExtraFieldSchemaList {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := &models.ExtraFieldSchemaList{Schemas: make([]models.ExtraFieldSchema, 0, len(s.entries))}
+	for _, entry := range s.entries {
+		list.Schemas = append(list.Schemas, entry.schema)
+	}
+	sort.Slice(list.Schemas, func(i, j int) bool { return list.Schemas[i].Tema < list.Schemas[j].Tema })
+	list.Total = len(list.Schemas)
+	return list
+}
+
+// Get retorna o schema do tema
+func (s *ExtraFieldSchemas) Get(tema string) (*models.ExtraFieldSchema, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.entries[extraFieldSchemaKey(tema)]
+	if !ok {
+		return nil, ErrExtraFieldSchemaNotFound
+	}
+	schema := entry.schema
+	return &schema, nil
+}
+
+// Validate valida o extra_fields de um serviço do tema. Temas sem schema aceitam qualquer valor.
+func (s *ExtraFieldSchemas) Validate(tema string, extraFields map[string]interface{}) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	entry, ok := s.entries[extraFieldSchemaKey(tema)]
+	s.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	if extraFields == nil {
+		extraFields = map[string]interface{}{}
+	}
+	return entry.compiled.validate(extraFields, "extra_fields")
+}
+
+// Save cria ou substitui o schema do tema e o persiste para as demais réplicas
+func (s *ExtraFieldSchemas) Save(ctx context.Context, tema string, request *models.ExtraFieldSchemaRequest, userName string) (*models.ExtraFieldSchema, error) {
+	tema = strings.TrimSpace(tema)
+	key := extraFieldSchemaKey(tema)
+	if key == "" {
+		return nil, fmt.Errorf("%w: tema não informado", ErrInvalidExtraFieldSchema)
+	}
+
+	schema := models.ExtraFieldSchema{
+		Tema:      tema,
+		Schema:    request.Schema,
+		Promoted:  request.Promoted,
+		UpdatedBy: userName,
+		UpdatedAt: time.Now().Unix(),
+	}
+	if schema.Promoted == nil {
+		schema.Promoted = []models.PromotedExtraField{}
+	}
+	entry, err := compileExtraFieldSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	others := make([]*extraFieldSchemaEntry, 0, len(s.entries))
+	for otherKey, other := range s.entries {
+		if otherKey != key {
+			others = append(others, other)
+		}
+	}
+	s.mu.RUnlock()
+	if err := checkPromotedConflicts(entry, others); err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+	schemaJSON, _ := json.Marshal(schema.Schema)
+	promotedJSON, _ := json.Marshal(schema.Promoted)
+	doc := map[string]interface{}{
+		"id":         key,
+		"tema":       schema.Tema,
+		"schema":     string(schemaJSON),
+		"promoted":   string(promotedJSON),
+		"updated_by": schema.UpdatedBy,
+		"updated_at": schema.UpdatedAt,
+	}
+	if _, err := s.client.Collection(ExtraFieldSchemasCollection).Documents().Upsert(ctx, doc, &api.DocumentIndexParameters{}); err != nil {
+		return nil, fmt.Errorf("erro ao salvar schema de extra_fields: %w", err)
+	}
+
+	s.mu.Lock()
+	s.entries[key] = entry
+	s.mu.Unlock()
+	return &schema, nil
+}
+
+// Delete remove o schema do tema. Campos promovidos deixam de ser indexados na próxima migração.
+func (s *ExtraFieldSchemas) Delete(ctx context.Context, tema string) error {
+	key := extraFieldSchemaKey(tema)
+	s.mu.RLock()
+	_, ok := s.entries[key]
+	s.mu.RUnlock()
+	if !ok {
+		return ErrExtraFieldSchemaNotFound
+	}
+
+	if _, err := s.client.Collection(ExtraFieldSchemasCollection).Document(key).Delete(ctx); err != nil && !IsNotFoundError(err) {
+		return fmt.Errorf("erro ao remover schema de extra_fields: %w", err)
+	}
+
+	s.mu.Lock()
+	delete(s.entries, key)
+	s.mu.Unlock()
+	return nil
+}
+
+// PromotedFields retorna os campos promovidos de todos os temas como campos do Typesense
+// (extra_fields.<name>), a incluir no schema da collection de serviços
+func (s *ExtraFieldSchemas) PromotedFields() []api.Field {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	byName := make(map[string]api.Field)
+	for _, entry := range s.entries {
+		for _, field := range entry.promoted {
+			if existing, ok := byName[field.Name]; ok && *existing.Facet {
+				continue
+			}
+			byName[field.Name] = field
+		}
+	}
+	s.mu.RUnlock()
+
+	fields := make([]api.Field, 0, len(byName))
+	for _, field := range byName {
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+	return fields
+}
+
+// Reload carrega os schemas persistidos, substituindo os em memória
+func (s *ExtraFieldSchemas) Reload(ctx context.Context) error {
+	entries := make(map[string]*extraFieldSchemaEntry)
+	for page := 1; ; page++ {
+		result, err := s.client.Collection(ExtraFieldSchemasCollection).Documents().Search(ctx, &api.SearchCollectionParams{
+			Q:       pointer.String("*"),
+			Page:    pointer.Int(page),
+			PerPage: pointer.Int(250),
+		})
+		if err != nil {
+			if IsNotFoundError(err) {
+				break
+			}
+			return fmt.Errorf("erro ao carregar schemas de extra_fields: %w", err)
+		}
+		if result.Hits == nil || len(*result.Hits) == 0 {
+			break
+		}
+
+		for _, hit := range *result.Hits {
+			if hit.Document == nil {
+				continue
+			}
+			entry, err := decodeExtraFieldSchema(*hit.Document)
+			if err != nil {
+				log.Printf("[ExtraFields] Schema ignorado: %v", err)
+				continue
+			}
+			entries[extraFieldSchemaKey(entry.schema.Tema)] = entry
+		}
+		if len(*result.Hits) < 250 {
+			break
+		}
+	}
+
+	s.mu.Lock()
+	s.entries = entries
+	s.mu.Unlock()
+	return nil
+}
+
+// StartRefreshRoutine recarrega periodicamente os schemas até o cancelamento de ctx,
+// propagando alterações feitas em outras réplicas
+func (s *ExtraFieldSchemas) StartRefreshRoutine(ctx context.Context, interval time.Duration) {
+	startReloadLoop(ctx, "ExtraFields", interval, s.Reload)
+}
+
+// ensureCollection garante que a collection extra_field_schemas existe
+func (s *ExtraFieldSchemas) ensureCollection(ctx context.Context) error {
+	_, err := s.client.Collection(ExtraFieldSchemasCollection).Retrieve(ctx)
+	if err == nil {
+		return nil
+	}
+
+	schema := &api.CollectionSchema{
+		Name: ExtraFieldSchemasCollection,
+		Fields: []api.Field{
+			{Name: "tema", Type: "string"},
+			{Name: "schema", Type: "string", Index: pointer.False(), Optional: pointer.True()},
+			{Name: "promoted", Type: "string", Index: pointer.False(), Optional: pointer.True()},
+			{Name: "updated_by", Type: "string", Index: pointer.False(), Optional: pointer.True()},
+			{Name: "updated_at", Type: "int64", Facet: pointer.False()},
+		},
+		DefaultSortingField: pointer.String("updated_at"),
+	}
+
+	if _, err := s.client.Collections().Create(ctx, schema); err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %w", ExtraFieldSchemasCollection, err)
+	}
+	return nil
+}
+
+// compileExtraFieldSchema valida o schema e os campos promovidos
+func compileExtraFieldSchema(schema models.ExtraFieldSchema) (*extraFieldSchemaEntry, error) {
+	compiled, err := compileJSONSchema(schema.Schema, "schema")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExtraFieldSchema, err)
+	}
+	if len(compiled.types) != 1 || compiled.types[0] != "object" {
+		return nil, fmt.Errorf("%w: a raiz do schema deve ter type object", ErrInvalidExtraFieldSchema)
+	}
+
+	entry := &extraFieldSchemaEntry{schema: schema, compiled: compiled}
+	seen := make(map[string]bool, len(schema.Promoted))
+	for _, promoted := range schema.Promoted {
+		if seen[promoted.Name] {
+			return nil, fmt.Errorf("%w: campo %s promovido mais de uma vez", ErrInvalidExtraFieldSchema, promoted.Name)
+		}
+		seen[promoted.Name] = true
+
+		prop, ok := compiled.properties[promoted.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: campo promovido %s não está em properties", ErrInvalidExtraFieldSchema, promoted.Name)
+		}
+		fieldType, ok := promotedFieldType(prop)
+		if !ok {
+			return nil, fmt.Errorf("%w: campo promovido %s deve ter um único tipo string, integer, number, boolean ou lista desses", ErrInvalidExtraFieldSchema, promoted.Name)
+		}
+		entry.promoted = append(entry.promoted, api.Field{
+			Name:     "extra_fields." + promoted.Name,
+			Type:     fieldType,
+			Facet:    boolPtr(promoted.Facet),
+			Optional: pointer.True(),
+		})
+	}
+	return entry, nil
+}
+
+// promotedFieldType mapeia o tipo do JSON Schema para o tipo do campo no Typesense
+func promotedFieldType(prop *jsonSchema) (string, bool) {
+	types := nonNullTypes(prop.types)
+	if len(types) != 1 {
+		return "", false
+	}
+	if types[0] == "array" {
+		if prop.items == nil {
+			return "", false
+		}
+		itemTypes := nonNullTypes(prop.items.types)
+		if len(itemTypes) != 1 {
+			return "", false
+		}
+		fieldType, ok := promotableTypes[itemTypes[0]]
+		return fieldType + "[]", ok
+	}
+	fieldType, ok := promotableTypes[types[0]]
+	return fieldType, ok
+}
+
+func nonNullTypes(types []string) []string {
+	var result []string
+	for _, t := range types {
+		if t != "null" {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// checkPromotedConflicts recusa promover com outro tipo um nome já promovido por outro tema:
+// o campo extra_fields.<name> é único na collection
+func checkPromotedConflicts(entry *extraFieldSchemaEntry, others []*extraFieldSchemaEntry) error {
+	for _, field := range entry.promoted {
+		for _, other := range others {
+			for _, existing := range other.promoted {
+				if existing.Name == field.Name && existing.Type != field.Type {
+					return fmt.Errorf("%w: %s já é promovido como %s pelo tema %s", ErrInvalidExtraFieldSchema,
+						strings.TrimPrefix(field.Name, "extra_fields."), existing.Type, other.schema.Tema)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// decodeExtraFieldSchema converte o documento persistido
+func decodeExtraFieldSchema(doc map[string]interface{}) (*extraFieldSchemaEntry, error) {
+	schema := models.ExtraFieldSchema{
+		Tema:      getString(doc, "tema"),
+		UpdatedBy: getString(doc, "updated_by"),
+		UpdatedAt: getInt64(doc, "updated_at"),
+		Promoted:  []models.PromotedExtraField{},
+	}
+	if err := json.Unmarshal([]byte(getString(doc, "schema")), &schema.Schema); err != nil {
+		return nil, fmt.Errorf("tema %s: %w", schema.Tema, err)
+	}
+	if raw := getString(doc, "promoted"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &schema.Promoted); err != nil {
+			return nil, fmt.Errorf("tema %s: %w", schema.Tema, err)
+		}
+	}
+	return compileExtraFieldSchema(schema)
+}
+
+// extraFieldSchemaKey identifica o tema ignorando acentos, caixa e pontuação
+func extraFieldSchemaKey(tema string) string {
+	return strings.Trim(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '-'
+	}, utils.NormalizarCategoria(strings.TrimSpace(tema))), "-")
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/migration/schemas"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+func eventosSchema() models.ExtraFieldSchema {
+	return models.ExtraFieldSchema{
+		Tema: "Cultura e Lazer",
+		Schema: map[string]interface{}{
+			"type":                 "object",
+			"required":             []interface{}{"capacidade"},
+			"additionalProperties": false,
+			"properties": map[string]interface{}{
+				"capacidade": map[string]interface{}{"type": "integer", "minimum": float64(1)},
+				"faixa":      map[string]interface{}{"type": "string", "enum": []interface{}{"livre", "18+"}},
+				"dias":       map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "maxItems": float64(7)},
+			},
+		},
+		Promoted: []models.PromotedExtraField{{Name: "capacidade"}, {Name: "dias", Facet: true}},
+	}
+}
+
+func TestExtraFieldSchemaValidate(t *testing.T) {
+	entry, err := compileExtraFieldSchema(eventosSchema())
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	registry := &ExtraFieldSchemas{entries: map[string]*extraFieldSchemaEntry{extraFieldSchemaKey("Cultura e Lazer"): entry}}
+
+	valid := map[string]interface{}{"capacidade": float64(300), "faixa": "livre", "dias": []interface{}{"sab", "dom"}}
+	if err := registry.Validate("cultura-e-lazer", valid); err != nil {
+		t.Errorf("extra_fields válido recusado: %v", err)
+	}
+
+	cases := map[string]map[string]interface{}{
+		"extra_fields.capacidade: campo obrigatório": nil,
+		"extra_fields.capacidade: esperado integer":  {"capacidade": 1.5},
+		"extra_fields.capacidade: mínimo 1":          {"capacidade": float64(0)},
+		"extra_fields.faixa: valor fora":             {"capacidade": float64(1), "faixa": "12+"},
+		"extra_fields.dias[0]: esperado string":      {"capacidade": float64(1), "dias": []interface{}{float64(1)}},
+		"extra_fields.outro: campo não previsto":     {"capacidade": float64(1), "outro": true},
+	}
+	for want, extra := range cases {
+		if err := registry.Validate("Cultura e Lazer", extra); err == nil || !strings.HasPrefix(err.Error(), want) {
+			t.Errorf("esperado %q, obtido %v", want, err)
+		}
+	}
+
+	if err := registry.Validate("Saúde", map[string]interface{}{"qualquer": 1}); err != nil {
+		t.Errorf("tema sem schema deveria aceitar qualquer valor: %v", err)
+	}
+}
+
+func TestCompileExtraFieldSchemaRejectsInvalid(t *testing.T) {
+	cases := []models.ExtraFieldSchema{
+		{Schema: map[string]interface{}{"type": "array"}},
+		{Schema: map[string]interface{}{"type": "object", "oneOf": []interface{}{}}},
+		{Schema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"x": map[string]interface{}{"pattern": "("}}}},
+		{Schema: map[string]interface{}{"type": "object"}, Promoted: []models.PromotedExtraField{{Name: "ausente"}}},
+		{Schema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{
+			"horarios": map[string]interface{}{"type": "object"},
+		}}, Promoted: []models.PromotedExtraField{{Name: "horarios"}}},
+	}
+	for i, schema := range cases {
+		if _, err := compileExtraFieldSchema(schema); !errors.Is(err, ErrInvalidExtraFieldSchema) {
+			t.Errorf("caso %d: esperado ErrInvalidExtraFieldSchema, obtido %v", i, err)
+		}
+	}
+}
+
+func TestPromotedExtraFields(t *testing.T) {
+	entry, err := compileExtraFieldSchema(eventosSchema())
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	conflicting := eventosSchema()
+	conflicting.Tema = "Esportes"
+	conflicting.Schema["properties"].(map[string]interface{})["capacidade"] = map[string]interface{}{"type": "string"}
+	other, err := compileExtraFieldSchema(conflicting)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if err := checkPromotedConflicts(other, []*extraFieldSchemaEntry{entry}); !errors.Is(err, ErrInvalidExtraFieldSchema) {
+		t.Errorf("promoção com tipo diferente deveria ser recusada: %v", err)
+	}
+
+	registry := &ExtraFieldSchemas{entries: map[string]*extraFieldSchemaEntry{"cultura-e-lazer": entry}}
+	schema := withPromotedFields(&schemas.SchemaDefinition{Version: "v12", Fields: []api.Field{{Name: "nome_servico", Type: "string"}}}, registry.PromotedFields())
+
+	var names []string
+	for _, field := range schema.Fields {
+		names = append(names, field.Name+":"+field.Type)
+	}
+	if got := strings.Join(names, ","); got != "nome_servico:string,extra_fields.capacidade:int64,extra_fields.dias:string[]" {
+		t.Errorf("campos inesperados: %s", got)
+	}
+	if !*schema.Fields[2].Facet || *schema.Fields[1].Facet {
+		t.Errorf("facet dos campos promovidos inesperado")
+	}
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+// fallbackIndexFields campos dos serviços guardados no mini-índice
+const fallbackIndexFields = "id,nome_servico,resumo,tema_geral,sub_categoria,slug,status,created_at,last_update,agents," +
+	"tenant,available_from,available_until,deprecated,replaced_by,sunset_at"
+
+// fallbackDegradedMode degradação informada nas buscas respondidas pelo mini-índice
+const fallbackDegradedMode = "typesense:local_index"
+
+// Parâmetros do BM25; os termos do nome do serviço contam fallbackTitleWeight vezes
+const (
+	bm25K1              = 1.2
+	bm25B               = 0.75
+	fallbackTitleWeight = 2
+)
+
+// FallbackIndex mini-índice em memória com o nome e o resumo dos serviços publicados. É o
+// último recurso quando o Typesense está inacessível: as buscas recebem resultados apenas
+// textuais (BM25), sinalizados como degradados, em vez de erro 5xx.
+type FallbackIndex struct {
+	export func(ctx context.Context) ([]map[string]interface{}, error)
+
+	mu        sync.RWMutex
+	docs      []fallbackDoc
+	postings  map[string][]int // termo -> posições em docs
+	avgLength float64
+	builtAt   int64
+}
+
+type fallbackDoc struct {
+	fields    map[string]interface{}
+	terms     map[string]int
+	length    int
+	agentOnly bool
+	tenant    string
+}
+
+type fallbackHit struct {
+	fields map[string]interface{}
+	score  float64
+}
+
+// NewFallbackIndex cria o mini-índice (vazio até o primeiro Rebuild)
+func NewFallbackIndex(client *typesense.Client) *FallbackIndex {
+	return &FallbackIndex{
+		export: func(ctx context.Context) ([]map[string]interface{}, error) {
+			body, err := client.Collection(CollectionName).Documents().Export(ctx, &api.ExportDocumentsParams{
+				FilterBy:      pointer.String("status:=1"),
+				IncludeFields: pointer.String(fallbackIndexFields),
+			})
+			if err != nil {
+				return nil, err
+			}
+			defer body.Close()
+			return decodeExportedDocuments(body)
+		},
+	}
+}
+
+// Rebuild reconstrói o mini-índice com os serviços publicados; em caso de erro o índice
+// anterior é mantido
+func (fi *FallbackIndex) Rebuild(ctx context.Context) error {
+	exported, err := fi.export(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao reconstruir índice de contingência: %w", err)
+	}
+
+	docs := make([]fallbackDoc, 0, len(exported))
+	postings := make(map[string][]int)
+	total := 0
+	for _, fields := range exported {
+		terms := make(map[string]int)
+		length := 0
+		for _, term := range fallbackTerms(getString(fields, "nome_servico")) {
+			terms[term] += fallbackTitleWeight
+			length += fallbackTitleWeight
+		}
+		for _, term := range fallbackTerms(getString(fields, "resumo")) {
+			terms[term]++
+			length++
+		}
+
+		agentOnly := false
+		if agents, ok := fields["agents"].(map[string]interface{}); ok {
+			agentOnly, _ = agents["exclusive_for_agents"].(bool)
+		}
+
+		for term := range terms {
+			postings[term] = append(postings[term], len(docs))
+		}
+		docs = append(docs, fallbackDoc{fields: fields, terms: terms, length: length, agentOnly: agentOnly, tenant: getString(fields, tenant.Field)})
+		total += length
+	}
+
+	avgLength := 0.0
+	if len(docs) > 0 {
+		avgLength = float64(total) / float64(len(docs))
+	}
+
+	fi.mu.Lock()
+	fi.docs, fi.postings, fi.avgLength = docs, postings, avgLength
+	fi.builtAt = time.Now().Unix()
+	fi.mu.Unlock()
+	return nil
+}
+
+// Ready indica se o mini-índice está habilitado e já tem serviços
+func (fi *FallbackIndex) Ready() bool {
+	if fi == nil {
+		return false
+	}
+	fi.mu.RLock()
+	defer fi.mu.RUnlock()
+	return len(fi.docs) > 0
+}
+
+// BuiltAt retorna quando o mini-índice foi reconstruído pela última vez (unix)
+func (fi *FallbackIndex) BuiltAt() int64 {
+	fi.mu.RLock()
+	defer fi.mu.RUnlock()
+	return fi.builtAt
+}
+
+// Search retorna os serviços do tenant da requisição com algum termo da query, ordenados pelo
+// BM25
+func (fi *FallbackIndex) Search(ctx context.Context, query string, excludeAgentOnly bool) []fallbackHit {
+	fi.mu.RLock()
+	defer fi.mu.RUnlock()
+
+	scores := make(map[int]float64)
+	n := float64(len(fi.docs))
+	for _, term := range uniqueSorted(fallbackTerms(query)) {
+		matches := fi.postings[term]
+		if len(matches) == 0 {
+			continue
+		}
+		df := float64(len(matches))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for _, i := range matches {
+			doc := fi.docs[i]
+			tf := float64(doc.terms[term])
+			norm := bm25K1 * (1 - bm25B + bm25B*float64(doc.length)/fi.avgLength)
+			scores[i] += idf * tf * (bm25K1 + 1) / (tf + norm)
+		}
+	}
+
+	hits := make([]fallbackHit, 0, len(scores))
+	for i, score := range scores {
+		if excludeAgentOnly && fi.docs[i].agentOnly {
+			continue
+		}
+		if !tenant.Allows(ctx, fi.docs[i].tenant) {
+			continue
+		}
+		hits = append(hits, fallbackHit{fields: fi.docs[i].fields, score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return getString(hits[i].fields, "nome_servico") < getString(hits[j].fields, "nome_servico")
+	})
+	return hits
+}
+
+// StartRefreshRoutine reconstrói o mini-índice agora e depois periodicamente, até o
+// cancelamento de ctx
+func (fi *FallbackIndex) StartRefreshRoutine(ctx context.Context, interval time.Duration) {
+	startReloadLoop(ctx, "FallbackIndex", interval, fi.Rebuild)
+}
+
+// fallbackTerms termos sem acentos e em minúsculas; termos de uma letra são ignorados
+func fallbackTerms(text string) []string {
+	fields := strings.FieldsFunc(utils.NormalizarCategoria(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := fields[:0]
+	for _, field := range fields {
+		if len(field) > 1 {
+			terms = append(terms, field)
+		}
+	}
+	return terms
+}
+
+// isTypesenseUnreachable indica falhas em que o Typesense não responde: erros de rede, 5xx e
+// circuit breaker aberto
+func isTypesenseUnreachable(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var httpErr *typesense.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Status >= 500
+	}
+	return strings.Contains(err.Error(), "circuit breaker is open")
+}
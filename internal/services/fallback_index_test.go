@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
+	"github.com/typesense/typesense-go/v3/typesense"
+)
+
+func newTestFallbackIndex(t *testing.T) *FallbackIndex {
+	t.Helper()
+	index := &FallbackIndex{export: func(ctx context.Context) ([]map[string]interface{}, error) {
+		return []map[string]interface{}{
+			{"id": "1", "nome_servico": "Segunda via do IPTU", "resumo": "Emita a guia do imposto predial", "status": 1},
+			{"id": "2", "nome_servico": "Parcelamento de dívidas", "resumo": "Parcele débitos de IPTU e taxas", "status": 1},
+			{"id": "3", "nome_servico": "Licença sanitária", "resumo": "Alvará da vigilância sanitária", "status": 1,
+				"agents": map[string]interface{}{"exclusive_for_agents": true}},
+			{"id": "4", "nome_servico": "Remoção de entulho", "resumo": "Coleta de entulho de obras", "status": 1, "tenant": "niteroi"},
+			{"id": "5", "nome_servico": "Colônia de férias", "resumo": "Inscrição na colônia de férias", "status": 1, "available_until": 100},
+			{"id": "6", "nome_servico": "Colônia de férias antiga", "resumo": "Inscrição encerrada", "status": 1, "deprecated": true},
+		}, nil
+	}}
+	if err := index.Rebuild(context.Background()); err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	return index
+}
+
+func TestFallbackIndexSearch(t *testing.T) {
+	index := newTestFallbackIndex(t)
+
+	// O nome do serviço pesa mais que o resumo
+	hits := index.Search(context.Background(), "iptu", false)
+	if len(hits) != 2 || hits[0].fields["id"] != "1" || hits[1].fields["id"] != "2" {
+		t.Fatalf("ordem inesperada: %+v", hits)
+	}
+
+	// Sem acentos e sem diferenciar maiúsculas
+	if hits := index.Search(context.Background(), "LICENCA", false); len(hits) != 1 || hits[0].fields["id"] != "3" {
+		t.Errorf("esperado o serviço 3, obtido %+v", hits)
+	}
+	if hits := index.Search(context.Background(), "licença", true); len(hits) != 0 {
+		t.Errorf("serviço exclusivo para agentes não deveria ser retornado: %+v", hits)
+	}
+	if hits := index.Search(context.Background(), "cemitério", false); len(hits) != 0 {
+		t.Errorf("esperado nenhum resultado, obtido %+v", hits)
+	}
+}
+
+func TestFallbackIndexSearchTenant(t *testing.T) {
+	index := newTestFallbackIndex(t)
+
+	if hits := index.Search(context.Background(), "entulho", false); len(hits) != 1 {
+		t.Errorf("sem particionamento todos os tenants são visíveis, obtido %+v", hits)
+	}
+	if hits := index.Search(tenant.WithTenant(context.Background(), "niteroi"), "entulho", false); len(hits) != 1 || hits[0].fields["id"] != "4" {
+		t.Errorf("esperado o serviço 4 para o próprio tenant, obtido %+v", hits)
+	}
+
+	// Documentos sem tenant pertencem ao tenant padrão
+	rio := tenant.WithTenant(context.Background(), tenant.DefaultID)
+	if hits := index.Search(rio, "entulho", false); len(hits) != 0 {
+		t.Errorf("serviço de outro tenant não deveria ser retornado: %+v", hits)
+	}
+	if hits := index.Search(rio, "iptu", false); len(hits) != 2 {
+		t.Errorf("esperados 2 serviços do tenant padrão, obtidos %+v", hits)
+	}
+}
+
+func TestFallbackSearchAvailabilityAndDeprecation(t *testing.T) {
+	ss := &SearchService{fallback: newTestFallbackIndex(t)}
+	ctx := withDegradedModes(context.Background())
+
+	// Fora da janela fica de fora; o descontinuado vai para o fim
+	response := ss.fallbackSearch(ctx, &models.SearchRequest{Query: "colônia férias antiga", Page: 1, PerPage: 10})
+	if len(response.Results) != 1 || response.Results[0].ID != "6" {
+		t.Fatalf("resultados inesperados: %+v", response.Results)
+	}
+
+	response = ss.fallbackSearch(ctx, &models.SearchRequest{Query: "colônia férias antiga", Page: 1, PerPage: 10, IncludeOutOfWindow: true})
+	if len(response.Results) != 2 || response.Results[0].ID != "5" || response.Results[0].Metadata["availability"] != AvailabilityClosed {
+		t.Errorf("esperado o serviço fora da janela sinalizado e à frente do descontinuado, obtido %+v", response.Results)
+	}
+}
+
+func TestSearchFallsBackToLocalIndex(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"message":"indisponível"}`))
+	}))
+	defer server.Close()
+
+	client := typesense.NewClient(typesense.WithServer(server.URL), typesense.WithNumRetries(0))
+	ss := NewSearchService(client, nil, "", nil, server.URL, "key")
+	ss.SetFallbackIndex(newTestFallbackIndex(t))
+
+	req := &models.SearchRequest{Query: "iptu", Type: models.SearchTypeKeyword, Page: 1, PerPage: 1}
+	response, err := ss.search(context.Background(), req)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if len(response.Results) != 1 || response.Results[0].ID != "1" || response.TotalCount != 2 {
+		t.Errorf("resultados inesperados: %+v", response)
+	}
+	if len(response.DegradedMode) != 1 || response.DegradedMode[0] != fallbackDegradedMode {
+		t.Errorf("esperada a degradação %s, obtido %v", fallbackDegradedMode, response.DegradedMode)
+	}
+
+	// Erros de requisição não são mascarados pelo mini-índice
+	status = http.StatusBadRequest
+	if _, err := ss.search(context.Background(), req); err == nil {
+		t.Error("esperado erro para 4xx do Typesense")
+	}
+}
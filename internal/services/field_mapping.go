@@ -0,0 +1,57 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+// applyFieldMapping preenche os campos comuns do documento (título, descrição, categoria e url)
+// a partir dos campos de origem declarados no field_mapping da collection. Os dados originais
+// continuam em Data.
+func applyFieldMapping(doc *models.UnifiedDocument, collConfig *config.CollectionConfig) {
+	if collConfig == nil {
+		return
+	}
+	fields := collConfig.Fields()
+	doc.Title = mappedString(doc.Data, fields.Title)
+	doc.Description = mappedString(doc.Data, fields.Description)
+	doc.Category = mappedString(doc.Data, fields.Category)
+	doc.URL = mappedString(doc.Data, fields.URL)
+}
+
+// mappedString lê o campo como texto; em listas (ex.: categorias) usa o primeiro item
+func mappedString(data map[string]interface{}, field string) string {
+	if field == "" {
+		return ""
+	}
+	switch value := data[field].(type) {
+	case string:
+		return value
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := item.(string); ok && s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// applyFieldProjection limita os campos devolvidos pela collection na multi-search: o embedding
+// nunca volta e, com include_fields configurado, apenas os campos do documento unificado e os
+// extras (da configuração e do parâmetro fields) são devolvidos
+func applyFieldProjection(params *api.MultiSearchCollectionParameters, collConfig *config.CollectionConfig, req *models.SearchRequest) {
+	params.ExcludeFields = pointer.String("embedding")
+
+	var extra []string
+	if req.Fields != "" {
+		extra = strings.Split(req.Fields, ",")
+	}
+	if include := collConfig.ProjectedFields(extra); include != "" {
+		params.IncludeFields = pointer.String(include)
+	}
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+func TestApplyFieldMapping(t *testing.T) {
+	legacy := &config.CollectionConfig{
+		Type:       "service",
+		TitleField: "titulo",
+		DescField:  "descricao",
+		FieldMapping: &config.FieldMapping{
+			Category: "categorias",
+			URL:      "link",
+		},
+	}
+	doc := &models.UnifiedDocument{Data: map[string]interface{}{
+		"titulo":     "Remoção de entulho",
+		"descricao":  "Solicite a remoção",
+		"categorias": []interface{}{"", "Limpeza urbana", "Obras"},
+		"link":       "https://1746.rio/entulho",
+	}}
+
+	applyFieldMapping(doc, legacy)
+
+	if doc.Title != "Remoção de entulho" || doc.Description != "Solicite a remoção" {
+		t.Errorf("title/description = %q/%q, want the title_field/desc_field fallbacks", doc.Title, doc.Description)
+	}
+	if doc.Category != "Limpeza urbana" {
+		t.Errorf("category = %q, want the first non-empty item of the list", doc.Category)
+	}
+	if doc.URL != "https://1746.rio/entulho" {
+		t.Errorf("url = %q", doc.URL)
+	}
+
+	override := &config.CollectionConfig{
+		TitleField:   "titulo",
+		FieldMapping: &config.FieldMapping{Title: "nome"},
+	}
+	doc = &models.UnifiedDocument{Data: map[string]interface{}{"titulo": "antigo", "nome": "novo", "link": 1}}
+	applyFieldMapping(doc, override)
+	if doc.Title != "novo" {
+		t.Errorf("title = %q, want the field_mapping title to take precedence", doc.Title)
+	}
+	if doc.Category != "" || doc.URL != "" {
+		t.Errorf("unmapped fields = %q/%q, want empty", doc.Category, doc.URL)
+	}
+}
+
+func TestApplyFieldProjection(t *testing.T) {
+	full := &config.CollectionConfig{TitleField: "nome_servico", DescField: "resumo"}
+	var params api.MultiSearchCollectionParameters
+	applyFieldProjection(&params, full, &models.SearchRequest{Fields: "custo"})
+	if params.IncludeFields != nil {
+		t.Errorf("include_fields = %q, want full documents without include_fields configured", *params.IncludeFields)
+	}
+	if params.ExcludeFields == nil || *params.ExcludeFields != "embedding" {
+		t.Errorf("exclude_fields = %v, want embedding", params.ExcludeFields)
+	}
+
+	projected := &config.CollectionConfig{
+		TitleField:    "titulo",
+		DescField:     "descricao",
+		FieldMapping:  &config.FieldMapping{URL: "link"},
+		IncludeFields: []string{"orgao", "titulo"},
+	}
+	params = api.MultiSearchCollectionParameters{}
+	applyFieldProjection(&params, projected, &models.SearchRequest{Fields: " custo,orgao"})
+
+	want := "id,titulo,descricao,link,last_update,orgao,custo"
+	if params.IncludeFields == nil || *params.IncludeFields != want {
+		t.Errorf("include_fields = %v, want %q", params.IncludeFields, want)
+	}
+}
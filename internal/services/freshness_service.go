@@ -0,0 +1,414 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prefeitura-rio/app-busca-search/internal/jobs"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+const (
+	FreshnessReportsCollection = "freshness_reports"
+
+	// FreshnessReportEvent evento enviado ao webhook para cada responsável com pendências
+	FreshnessReportEvent = "freshness_report.owner_digest"
+)
+
+// Variação de click-through por categoria: a última janela é comparada à anterior. As duas
+// janelas cabem na retenção padrão das jornadas (JOURNEY_RETENTION_DAYS).
+const (
+	clickThroughWindowDays = 14
+	// clickThroughMinClicks cliques na janela anterior para que a categoria seja avaliada
+	clickThroughMinClicks = 20
+	// clickThroughDropThreshold queda relativa a partir da qual a categoria é sinalizada
+	clickThroughDropThreshold = 0.25
+)
+
+// legislationExpiryPattern captura datas de vigência em textos como "vigente até 31/12/2023"
+var legislationExpiryPattern = regexp.MustCompile(`(?i)(?:até|ate|vig[eê]ncia|validade|v[aá]lid[oa])\D{0,20}(\d{2}/\d{2}/\d{4})`)
+
+// FreshnessService gera o relatório de atualização do conteúdo dos serviços
+type FreshnessService struct {
+	client   *typesense.Client
+	notifier *WebhookNotifier
+	journeys *JourneyAnalytics
+}
+
+// NewFreshnessService cria um novo serviço de relatório de atualização
+func NewFreshnessService(client *typesense.Client, notifier *WebhookNotifier) *FreshnessService {
+	return &FreshnessService{
+		client:   client,
+		notifier: notifier,
+	}
+}
+
+// SetJourneyAnalytics habilita a variação de click-through por categoria, calculada a partir
+// das buscas e cliques das jornadas
+func (fs *FreshnessService) SetJourneyAnalytics(journeys *JourneyAnalytics) {
+	fs.journeys = journeys
+}
+
+// GenerateReport varre os serviços publicados, salva o relatório e notifica os responsáveis
+func (fs *FreshnessService) GenerateReport(ctx context.Context, staleAfterMonths int, generatedBy string) (*models.FreshnessReport, error) {
+	if staleAfterMonths < 1 {
+		staleAfterMonths = 12
+	}
+
+	now := time.Now()
+	staleCutoff := now.AddDate(0, -staleAfterMonths, 0).Unix()
+
+	report := &models.FreshnessReport{
+		ID:               uuid.New().String(),
+		GeneratedAt:      now.Unix(),
+		GeneratedBy:      generatedBy,
+		StaleAfterMonths: staleAfterMonths,
+		Issues:           []models.FreshnessIssue{},
+	}
+	categories := make(map[string]string)
+
+	page := 1
+	perPage := 250
+	for {
+		services, found, err := fs.fetchPublishedServices(ctx, page, perPage)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar serviços (página %d): %w", page, err)
+		}
+		report.TotalServices = found
+
+		for _, service := range services {
+			categories[service.ID] = service.TemaGeral
+			if service.LastUpdate > 0 && service.LastUpdate < staleCutoff {
+				report.Issues = append(report.Issues, newFreshnessIssue(&service, models.FreshnessIssueStale,
+					int(now.Sub(time.Unix(service.LastUpdate, 0)).Hours()/24), ""))
+				report.StaleCount++
+			}
+
+			if detail := findExpiredLegislation(service.LegislacaoRelacionada, now); detail != "" {
+				report.Issues = append(report.Issues, newFreshnessIssue(&service, models.FreshnessIssueExpiredLegislation, 0, detail))
+				report.ExpiredLegislationCount++
+			}
+		}
+
+		if len(services) < perPage {
+			break
+		}
+		page++
+	}
+
+	if fs.journeys == nil {
+		report.Notes = append(report.Notes, "Variação de click-through por categoria desabilitada: as jornadas de busca não estão sendo coletadas (JOURNEY_ANALYTICS_ENABLED)")
+	} else {
+		events, err := fs.journeys.Events(ctx, 2*clickThroughWindowDays)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar jornadas de busca: %w", err)
+		}
+		report.ClickThroughWindowDays = clickThroughWindowDays
+		report.ClickThroughDrops = shrinkingClickThrough(events, categories, now, clickThroughWindowDays)
+	}
+
+	report.NotifiedOwners = fs.notifyOwners(ctx, report)
+
+	if err := fs.saveReport(ctx, report); err != nil {
+		return nil, fmt.Errorf("erro ao salvar relatório: %w", err)
+	}
+
+	log.Printf("[Freshness] Relatório %s gerado: %d serviços, %d desatualizados, %d com legislação vencida, %d categorias com queda de click-through",
+		report.ID, report.TotalServices, report.StaleCount, report.ExpiredLegislationCount, len(report.ClickThroughDrops))
+
+	return report, nil
+}
+
+// GetLatestReport retorna o relatório mais recente (nil se nenhum foi gerado)
+func (fs *FreshnessService) GetLatestReport(ctx context.Context) (*models.FreshnessReport, error) {
+	if err := fs.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+
+	searchParams := &api.SearchCollectionParams{
+		Q:       pointer.String("*"),
+		Page:    pointer.Int(1),
+		PerPage: pointer.Int(1),
+		SortBy:  pointer.String("generated_at:desc"),
+	}
+
+	result, err := fs.client.Collection(FreshnessReportsCollection).Documents().Search(ctx, searchParams)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar relatório: %w", err)
+	}
+
+	if result.Hits == nil || len(*result.Hits) == 0 || (*result.Hits)[0].Document == nil {
+		return nil, nil
+	}
+
+	doc := *(*result.Hits)[0].Document
+	raw, _ := doc["report"].(string)
+
+	var report models.FreshnessReport
+	if err := json.Unmarshal([]byte(raw), &report); err != nil {
+		return nil, fmt.Errorf("erro ao deserializar relatório: %w", err)
+	}
+
+	return &report, nil
+}
+
+// StartNightlyRoutine agenda a geração diária do relatório no horário informado (0-23),
+// executada por uma única réplica (job freshness-report)
+func (fs *FreshnessService) StartNightlyRoutine(runner *jobs.Runner, hour, staleAfterMonths int) error {
+	return runner.Register("freshness-report", fmt.Sprintf("0 %d * * *", hour), 30*time.Minute, func(ctx context.Context) error {
+		if _, err := fs.GenerateReport(ctx, staleAfterMonths, "scheduler"); err != nil {
+			return fmt.Errorf("erro ao gerar relatório agendado: %w", err)
+		}
+		return nil
+	})
+}
+
+// fetchPublishedServices busca uma página de serviços publicados sem embeddings
+func (fs *FreshnessService) fetchPublishedServices(ctx context.Context, page, perPage int) ([]models.PrefRioService, int, error) {
+	return fetchServicesPage(ctx, fs.client, "status:=1", page, perPage)
+}
+
+// fetchServicesPage busca uma página de serviços (sem embeddings) que atendem ao filtro
+func fetchServicesPage(ctx context.Context, client *typesense.Client, filterBy string, page, perPage int) ([]models.PrefRioService, int, error) {
+	searchParams := &api.SearchCollectionParams{
+		Q:             pointer.String("*"),
+		FilterBy:      pointer.String(filterBy),
+		Page:          pointer.Int(page),
+		PerPage:       pointer.Int(perPage),
+		ExcludeFields: pointer.String("embedding,search_content"),
+		SortBy:        pointer.String("last_update:asc"),
+	}
+
+	result, err := client.Collection(CollectionName).Documents().Search(ctx, searchParams)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	found := 0
+	if result.Found != nil {
+		found = int(*result.Found)
+	}
+
+	return servicesFromHits(result), found, nil
+}
+
+// servicesFromHits converte os hits da busca em serviços, ignorando documentos inválidos
+func servicesFromHits(result *api.SearchResult) []models.PrefRioService {
+	services := []models.PrefRioService{}
+	if result.Hits != nil {
+		for _, hit := range *result.Hits {
+			if hit.Document == nil {
+				continue
+			}
+			docBytes, err := json.Marshal(*hit.Document)
+			if err != nil {
+				continue
+			}
+			var service models.PrefRioService
+			if err := json.Unmarshal(docBytes, &service); err == nil {
+				services = append(services, service)
+			}
+		}
+	}
+	return services
+}
+
+// notifyOwners envia um resumo por responsável (autor) via webhook e retorna quantos foram notificados
+func (fs *FreshnessService) notifyOwners(ctx context.Context, report *models.FreshnessReport) int {
+	if !fs.notifier.Enabled() || len(report.Issues) == 0 {
+		return 0
+	}
+
+	byOwner := make(map[string][]models.FreshnessIssue)
+	for _, issue := range report.Issues {
+		owner := issue.Autor
+		if owner == "" {
+			owner = "sem_autor"
+		}
+		byOwner[owner] = append(byOwner[owner], issue)
+	}
+
+	owners := make([]string, 0, len(byOwner))
+	for owner := range byOwner {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+
+	notified := 0
+	for _, owner := range owners {
+		payload := map[string]interface{}{
+			"report_id": report.ID,
+			"owner":     owner,
+			"issues":    byOwner[owner],
+		}
+		if err := fs.notifier.Notify(ctx, FreshnessReportEvent, payload); err != nil {
+			log.Printf("[Freshness] Erro ao notificar %s: %v", owner, err)
+			continue
+		}
+		notified++
+	}
+
+	return notified
+}
+
+// saveReport persiste o relatório na collection freshness_reports
+func (fs *FreshnessService) saveReport(ctx context.Context, report *models.FreshnessReport) error {
+	if err := fs.ensureCollection(ctx); err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar relatório: %w", err)
+	}
+
+	doc := map[string]interface{}{
+		"id":                        report.ID,
+		"generated_at":              report.GeneratedAt,
+		"generated_by":              report.GeneratedBy,
+		"total_services":            report.TotalServices,
+		"stale_count":               report.StaleCount,
+		"expired_legislation_count": report.ExpiredLegislationCount,
+		"report":                    string(raw),
+	}
+
+	_, err = fs.client.Collection(FreshnessReportsCollection).Documents().Create(ctx, doc, &api.DocumentIndexParameters{})
+	return err
+}
+
+// ensureCollection garante que a collection freshness_reports existe
+func (fs *FreshnessService) ensureCollection(ctx context.Context) error {
+	_, err := fs.client.Collection(FreshnessReportsCollection).Retrieve(ctx)
+	if err == nil {
+		return nil
+	}
+
+	schema := &api.CollectionSchema{
+		Name: FreshnessReportsCollection,
+		Fields: []api.Field{
+			{Name: "generated_at", Type: "int64", Facet: pointer.False()},
+			{Name: "generated_by", Type: "string", Facet: pointer.True()},
+			{Name: "total_services", Type: "int32", Facet: pointer.False()},
+			{Name: "stale_count", Type: "int32", Facet: pointer.False()},
+			{Name: "expired_legislation_count", Type: "int32", Facet: pointer.False()},
+			{Name: "report", Type: "string", Index: pointer.False(), Optional: pointer.True()},
+		},
+		DefaultSortingField: pointer.String("generated_at"),
+	}
+
+	if _, err := fs.client.Collections().Create(ctx, schema); err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %w", FreshnessReportsCollection, err)
+	}
+
+	return nil
+}
+
+func newFreshnessIssue(service *models.PrefRioService, issueType models.FreshnessIssueType, daysStale int, detail string) models.FreshnessIssue {
+	return models.FreshnessIssue{
+		ServiceID:   service.ID,
+		NomeServico: service.NomeServico,
+		TemaGeral:   service.TemaGeral,
+		Autor:       service.Autor,
+		OrgaoGestor: service.OrgaoGestor,
+		Type:        issueType,
+		LastUpdate:  service.LastUpdate,
+		DaysStale:   daysStale,
+		Detail:      detail,
+	}
+}
+
+// findExpiredLegislation retorna a primeira legislação revogada ou com data de vigência vencida
+func findExpiredLegislation(legislacoes []string, now time.Time) string {
+	for _, leg := range legislacoes {
+		if strings.Contains(strings.ToLower(leg), "revogad") {
+			return leg
+		}
+
+		for _, match := range legislationExpiryPattern.FindAllStringSubmatch(leg, -1) {
+			expiry, err := time.Parse("02/01/2006", match[1])
+			if err != nil {
+				continue
+			}
+			if expiry.Before(now) {
+				return leg
+			}
+		}
+	}
+	return ""
+}
+
+// shrinkingClickThrough compara o click-through de cada categoria na última janela de
+// windowDays dias com o da janela anterior e retorna as categorias com queda relativa de ao
+// menos clickThroughDropThreshold, da maior queda para a menor. Cliques em serviços fora de
+// categories (não publicados) são ignorados.
+func shrinkingClickThrough(events []models.JourneyEvent, categories map[string]string, now time.Time, windowDays int) []models.CategoryClickThroughDrop {
+	currentStart := now.AddDate(0, 0, -windowDays).Unix()
+	previousStart := now.AddDate(0, 0, -2*windowDays).Unix()
+
+	var searches [2]int
+	clicks := make(map[string]*[2]int)
+	for _, event := range events {
+		if event.Timestamp < previousStart {
+			continue
+		}
+		window := 0
+		if event.Timestamp >= currentStart {
+			window = 1
+		}
+		switch event.Type {
+		case models.JourneyEventSearch:
+			searches[window]++
+		case models.JourneyEventClick:
+			category, ok := categories[event.ServiceID]
+			if !ok || category == "" {
+				continue
+			}
+			if clicks[category] == nil {
+				clicks[category] = &[2]int{}
+			}
+			clicks[category][window]++
+		}
+	}
+
+	drops := []models.CategoryClickThroughDrop{}
+	if searches[0] == 0 || searches[1] == 0 {
+		return drops
+	}
+	for category, counts := range clicks {
+		if counts[0] < clickThroughMinClicks {
+			continue
+		}
+		previous := float64(counts[0]) / float64(searches[0])
+		current := float64(counts[1]) / float64(searches[1])
+		change := (current - previous) / previous
+		if change > -clickThroughDropThreshold {
+			continue
+		}
+		drops = append(drops, models.CategoryClickThroughDrop{
+			TemaGeral:            category,
+			PreviousClicks:       counts[0],
+			CurrentClicks:        counts[1],
+			PreviousClickThrough: previous,
+			CurrentClickThrough:  current,
+			Change:               change,
+		})
+	}
+
+	sort.Slice(drops, func(i, j int) bool {
+		if drops[i].Change != drops[j].Change {
+			return drops[i].Change < drops[j].Change
+		}
+		return drops[i].TemaGeral < drops[j].TemaGeral
+	})
+	return drops
+}
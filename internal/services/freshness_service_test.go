@@ -0,0 +1,77 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestFindExpiredLegislation(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		legislacoes []string
+		expected    string
+	}{
+		{"sem legislação", nil, ""},
+		{"legislação sem data", []string{"Lei nº 1.234/2010"}, ""},
+		{"vigência futura", []string{"Decreto 50.000, vigente até 31/12/2030"}, ""},
+		{"vigência vencida", []string{"Lei 1", "Decreto 45.000, válido até 31/12/2023"}, "Decreto 45.000, válido até 31/12/2023"},
+		{"legislação revogada", []string{"Lei 2.000/2001 (revogada)"}, "Lei 2.000/2001 (revogada)"},
+		{"data de publicação não é vigência", []string{"Publicado em 01/01/2001"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findExpiredLegislation(tt.legislacoes, now); got != tt.expected {
+				t.Errorf("findExpiredLegislation() = %q, esperado %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestShrinkingClickThrough(t *testing.T) {
+	now := time.Date(2025, 6, 29, 0, 0, 0, 0, time.UTC)
+	previous := now.AddDate(0, 0, -20).Unix()
+	current := now.AddDate(0, 0, -3).Unix()
+	categories := map[string]string{"iptu": "Tributos", "matricula": "Educação", "vacina": "Saúde"}
+
+	var events []models.JourneyEvent
+	add := func(n int, event models.JourneyEvent) {
+		for i := 0; i < n; i++ {
+			events = append(events, event)
+		}
+	}
+	// 100 buscas em cada janela
+	add(100, models.JourneyEvent{Type: models.JourneyEventSearch, Timestamp: previous})
+	add(100, models.JourneyEvent{Type: models.JourneyEventSearch, Timestamp: current})
+	// Tributos cai de 40% para 20%; Educação oscila pouco; Saúde tem poucos cliques
+	add(40, models.JourneyEvent{Type: models.JourneyEventClick, ServiceID: "iptu", Timestamp: previous})
+	add(20, models.JourneyEvent{Type: models.JourneyEventClick, ServiceID: "iptu", Timestamp: current})
+	add(30, models.JourneyEvent{Type: models.JourneyEventClick, ServiceID: "matricula", Timestamp: previous})
+	add(27, models.JourneyEvent{Type: models.JourneyEventClick, ServiceID: "matricula", Timestamp: current})
+	add(5, models.JourneyEvent{Type: models.JourneyEventClick, ServiceID: "vacina", Timestamp: previous})
+	// Fora das janelas e serviços não publicados são ignorados
+	add(50, models.JourneyEvent{Type: models.JourneyEventClick, ServiceID: "iptu", Timestamp: now.AddDate(0, 0, -40).Unix()})
+	add(50, models.JourneyEvent{Type: models.JourneyEventClick, ServiceID: "despublicado", Timestamp: previous})
+
+	drops := shrinkingClickThrough(events, categories, now, 14)
+	expected := []models.CategoryClickThroughDrop{{
+		TemaGeral:            "Tributos",
+		PreviousClicks:       40,
+		CurrentClicks:        20,
+		PreviousClickThrough: 0.4,
+		CurrentClickThrough:  0.2,
+		Change:               -0.5,
+	}}
+	if !reflect.DeepEqual(drops, expected) {
+		t.Errorf("esperado %+v, obtido %+v", expected, drops)
+	}
+
+	if drops := shrinkingClickThrough(nil, categories, now, 14); len(drops) != 0 {
+		t.Errorf("sem eventos não deveria haver quedas, obtido %+v", drops)
+	}
+}
@@ -1,8 +1,30 @@
 package services
 
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/typesense/typesense-go/v3/typesense"
+)
+
 // CollectionName is the name of the Typesense collection for services
 const CollectionName = "prefrio_services_base"
 
+// IsNotFoundError indica se o Typesense respondeu 404 (documento ou collection inexistente).
+// Erros sem o HTTPError tipado (ex.: formatados com %v) são reconhecidos pela mensagem.
+func IsNotFoundError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var httpErr *typesense.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Status == http.StatusNotFound
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "404") || strings.Contains(msg, "Not found") || strings.Contains(msg, "Not Found")
+}
+
 // Helper functions for extracting values from Typesense documents
 func getString(m map[string]interface{}, key string) string {
 	if v, ok := m[key]; ok {
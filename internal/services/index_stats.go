@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+// IndexStatsService consolida as métricas do cluster (/metrics.json e /stats.json) e o tamanho
+// das collections. O resultado é reaproveitado por ttl para que painéis com atualização
+// frequente não sobrecarreguem o Typesense.
+type IndexStatsService struct {
+	client *typesense.Client
+	ttl    time.Duration
+
+	mu     sync.Mutex
+	cached *models.IndexStats
+}
+
+// NewIndexStatsService cria o serviço de métricas do índice
+func NewIndexStatsService(client *typesense.Client, ttl time.Duration) *IndexStatsService {
+	return &IndexStatsService{client: client, ttl: ttl}
+}
+
+// Stats retorna as métricas do cluster. Falhas em /metrics.json ou /stats.json são reportadas
+// em Errors; falha ao listar as collections é erro.
+func (s *IndexStatsService) Stats(ctx context.Context) (*models.IndexStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached != nil && time.Since(time.Unix(s.cached.CollectedAt, 0)) < s.ttl {
+		return s.cached, nil
+	}
+
+	collections, err := s.client.Collections().Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar collections: %w", err)
+	}
+
+	stats := &models.IndexStats{CollectedAt: time.Now().Unix(), Metrics: map[string]float64{}}
+
+	aliasesByCollection := make(map[string][]string)
+	if aliases, err := s.client.Aliases().Retrieve(ctx); err != nil {
+		stats.Errors = append(stats.Errors, fmt.Sprintf("aliases: %v", err))
+	} else {
+		for _, alias := range aliases {
+			if alias.Name != nil {
+				aliasesByCollection[alias.CollectionName] = append(aliasesByCollection[alias.CollectionName], *alias.Name)
+			}
+		}
+	}
+	stats.Collections = collectionStats(collections, aliasesByCollection)
+	for _, collection := range stats.Collections {
+		stats.TotalDocuments += collection.Documents
+	}
+
+	if metrics, err := s.client.Metrics().Retrieve(ctx); err != nil {
+		stats.Errors = append(stats.Errors, fmt.Sprintf("metrics.json: %v", err))
+	} else {
+		applyClusterMetrics(stats, metrics)
+	}
+
+	if apiStats, err := s.client.Stats().Retrieve(ctx); err != nil {
+		stats.Errors = append(stats.Errors, fmt.Sprintf("stats.json: %v", err))
+	} else {
+		stats.Latency = latencyStats(apiStats)
+	}
+
+	s.cached = stats
+	return stats, nil
+}
+
+// collectionStats ordena as collections pela quantidade de documentos
+func collectionStats(collections []*api.CollectionResponse, aliases map[string][]string) []models.IndexCollectionStats {
+	result := make([]models.IndexCollectionStats, 0, len(collections))
+	for _, collection := range collections {
+		if collection == nil {
+			continue
+		}
+		entry := models.IndexCollectionStats{
+			Name:    collection.Name,
+			Fields:  len(collection.Fields),
+			Aliases: aliases[collection.Name],
+		}
+		if collection.NumDocuments != nil {
+			entry.Documents = *collection.NumDocuments
+		}
+		if collection.CreatedAt != nil {
+			entry.CreatedAt = *collection.CreatedAt
+		}
+		sort.Strings(entry.Aliases)
+		result = append(result, entry)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Documents != result[j].Documents {
+			return result[i].Documents > result[j].Documents
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// applyClusterMetrics converte o /metrics.json, que traz os valores como texto
+func applyClusterMetrics(stats *models.IndexStats, metrics map[string]interface{}) {
+	for name, raw := range metrics {
+		switch v := raw.(type) {
+		case float64:
+			stats.Metrics[name] = v
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				stats.Metrics[name] = f
+			}
+		}
+	}
+
+	bytes := func(name string) int64 { return int64(stats.Metrics[name]) }
+	stats.Memory = models.IndexMemoryStats{
+		SystemTotalBytes:   bytes("system_memory_total_bytes"),
+		SystemUsedBytes:    bytes("system_memory_used_bytes"),
+		ActiveBytes:        bytes("typesense_memory_active_bytes"),
+		AllocatedBytes:     bytes("typesense_memory_allocated_bytes"),
+		ResidentBytes:      bytes("typesense_memory_resident_bytes"),
+		FragmentationRatio: stats.Metrics["typesense_memory_fragmentation_ratio"],
+	}
+	stats.Disk = models.IndexDiskStats{
+		TotalBytes: bytes("system_disk_total_bytes"),
+		UsedBytes:  bytes("system_disk_used_bytes"),
+	}
+	stats.CPUPercent = stats.Metrics["system_cpu_active_percentage"]
+}
+
+func latencyStats(apiStats *api.APIStatsResponse) models.IndexLatencyStats {
+	value := func(v *float64) float64 {
+		if v == nil {
+			return 0
+		}
+		return *v
+	}
+	return models.IndexLatencyStats{
+		SearchMs:            value(apiStats.SearchLatencyMs),
+		WriteMs:             value(apiStats.WriteLatencyMs),
+		ImportMs:            value(apiStats.ImportLatencyMs),
+		DeleteMs:            value(apiStats.DeleteLatencyMs),
+		SearchPerSecond:     value(apiStats.SearchRequestsPerSecond),
+		WritePerSecond:      value(apiStats.WriteRequestsPerSecond),
+		TotalPerSecond:      value(apiStats.TotalRequestsPerSecond),
+		OverloadedPerSecond: value(apiStats.OverloadedRequestsPerSecond),
+		PendingWriteBatches: value(apiStats.PendingWriteBatches),
+	}
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+func TestApplyClusterMetrics(t *testing.T) {
+	stats := &models.IndexStats{Metrics: map[string]float64{}}
+	applyClusterMetrics(stats, map[string]interface{}{
+		"system_memory_total_bytes":            "8589934592",
+		"system_disk_used_bytes":               "1048576",
+		"system_cpu_active_percentage":         "12.50",
+		"typesense_memory_fragmentation_ratio": "0.08",
+		"typesense_memory_resident_bytes":      float64(2048),
+		"invalid":                              "n/a",
+	})
+
+	if stats.Memory.SystemTotalBytes != 8589934592 || stats.Memory.ResidentBytes != 2048 {
+		t.Errorf("memória inesperada: %+v", stats.Memory)
+	}
+	if stats.Disk.UsedBytes != 1048576 || stats.CPUPercent != 12.5 || stats.Memory.FragmentationRatio != 0.08 {
+		t.Errorf("métricas inesperadas: %+v", stats)
+	}
+	if _, ok := stats.Metrics["invalid"]; ok {
+		t.Error("métricas não numéricas deveriam ser ignoradas")
+	}
+}
+
+func TestCollectionStatsSortedByDocuments(t *testing.T) {
+	collections := []*api.CollectionResponse{
+		{Name: "versions", NumDocuments: pointer.Int64(10)},
+		{Name: "prefrio_services_base", NumDocuments: pointer.Int64(500), Fields: []api.Field{{Name: "nome_servico"}}},
+		{Name: "_jobs"},
+	}
+	aliases := map[string][]string{"prefrio_services_base": {"services_b", "services_a"}}
+
+	result := collectionStats(collections, aliases)
+	if len(result) != 3 || result[0].Name != "prefrio_services_base" || result[2].Name != "_jobs" {
+		t.Fatalf("ordem inesperada: %+v", result)
+	}
+	if result[0].Fields != 1 || result[0].Aliases[0] != "services_a" {
+		t.Errorf("collection inesperada: %+v", result[0])
+	}
+}
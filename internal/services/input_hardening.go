@@ -0,0 +1,91 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+)
+
+// Limites do extra_fields: profundidade de objetos/listas aninhados e total de chaves e itens
+const (
+	MaxExtraFieldsDepth   = 3
+	MaxExtraFieldsEntries = 200
+)
+
+// ValidateServiceInput complementa as tags de validação do request: limita o extra_fields
+// (estrutura livre indexada como objeto), recusa botões com URLs de esquema executável e
+// valida e normaliza os botões conforme o action_type
+func ValidateServiceInput(request *models.PrefRioServiceRequest) error {
+	entries := 0
+	if err := validateExtraValue(request.ExtraFields, 0, &entries); err != nil {
+		return err
+	}
+
+	for i := range request.Buttons {
+		if !utils.IsSafeURL(request.Buttons[i].URLService) {
+			return fmt.Errorf("buttons[%d].url_service: esquema de URL não permitido", i)
+		}
+		if err := request.Buttons[i].Normalize(); err != nil {
+			return fmt.Errorf("buttons[%d].%w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateExtraValue percorre o valor contando profundidade e quantidade de entradas
+func validateExtraValue(value interface{}, depth int, entries *int) error {
+	var children []interface{}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return nil
+		}
+		for _, child := range v {
+			children = append(children, child)
+		}
+	case []interface{}:
+		children = v
+	default:
+		return nil
+	}
+
+	if depth+1 > MaxExtraFieldsDepth {
+		return fmt.Errorf("extra_fields excede a profundidade máxima de %d níveis", MaxExtraFieldsDepth)
+	}
+	*entries += len(children)
+	if *entries > MaxExtraFieldsEntries {
+		return fmt.Errorf("extra_fields excede o máximo de %d entradas", MaxExtraFieldsEntries)
+	}
+
+	for _, child := range children {
+		if err := validateExtraValue(child, depth+1, entries); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SanitizeService remove HTML e links executáveis dos campos em markdown antes da indexação,
+// protegendo os portais e apps que renderizam o conteúdo
+func SanitizeService(service *models.PrefRioService) {
+	service.NomeServico = utils.SanitizeMarkdown(service.NomeServico)
+	service.Resumo = utils.SanitizeMarkdown(service.Resumo)
+	service.TempoAtendimento = utils.SanitizeMarkdown(service.TempoAtendimento)
+	service.CustoServico = utils.SanitizeMarkdown(service.CustoServico)
+	service.ResultadoSolicitacao = utils.SanitizeMarkdown(service.ResultadoSolicitacao)
+	service.DescricaoCompleta = utils.SanitizeMarkdown(service.DescricaoCompleta)
+	service.InstrucoesSolicitante = utils.SanitizeMarkdown(service.InstrucoesSolicitante)
+	service.ServicoNaoCobre = utils.SanitizeMarkdown(service.ServicoNaoCobre)
+	service.DocumentosNecessarios = utils.SanitizeMarkdownArray(service.DocumentosNecessarios)
+	service.CanaisPresenciais = utils.SanitizeMarkdownArray(service.CanaisPresenciais)
+	service.LegislacaoRelacionada = utils.SanitizeMarkdownArray(service.LegislacaoRelacionada)
+
+	for i := range service.Buttons {
+		service.Buttons[i].Titulo = utils.SanitizeMarkdown(service.Buttons[i].Titulo)
+		service.Buttons[i].Descricao = utils.SanitizeMarkdown(service.Buttons[i].Descricao)
+		if !utils.IsSafeURL(service.Buttons[i].URLService) {
+			service.Buttons[i].URLService = ""
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestValidateServiceInputExtraFields(t *testing.T) {
+	ok := &models.PrefRioServiceRequest{ExtraFields: map[string]interface{}{
+		"horarios": map[string]interface{}{"seg": []interface{}{"08:00", "17:00"}},
+	}}
+	if err := ValidateServiceInput(ok); err != nil {
+		t.Errorf("extra_fields com 3 níveis deveria ser aceito: %v", err)
+	}
+
+	deep := &models.PrefRioServiceRequest{ExtraFields: map[string]interface{}{
+		"a": map[string]interface{}{"b": map[string]interface{}{"c": map[string]interface{}{"d": 1}}},
+	}}
+	if err := ValidateServiceInput(deep); err == nil || !strings.Contains(err.Error(), "profundidade") {
+		t.Errorf("extra_fields aninhado demais deveria ser recusado: %v", err)
+	}
+
+	items := make([]interface{}, MaxExtraFieldsEntries)
+	large := &models.PrefRioServiceRequest{ExtraFields: map[string]interface{}{"lista": items}}
+	if err := ValidateServiceInput(large); err == nil {
+		t.Error("extra_fields com entradas demais deveria ser recusado")
+	}
+}
+
+func TestValidateServiceInputButtonURL(t *testing.T) {
+	request := &models.PrefRioServiceRequest{Buttons: []models.Button{
+		{Titulo: "Solicitar", URLService: "https://1746.rio"},
+		{Titulo: "Golpe", URLService: "javascript:alert(1)"},
+	}}
+	if err := ValidateServiceInput(request); err == nil || !strings.Contains(err.Error(), "buttons[1]") {
+		t.Errorf("URL executável deveria ser recusada: %v", err)
+	}
+}
+
+func TestSanitizeService(t *testing.T) {
+	service := &models.PrefRioService{
+		Resumo:                "Emissão de **IPTU**<script>steal()</script>",
+		DocumentosNecessarios: []string{"<b>RG</b>", "CPF"},
+		Buttons:               []models.Button{{Titulo: "<i>Pagar</i>", URLService: "data:text/html,x"}},
+	}
+	SanitizeService(service)
+
+	if service.Resumo != "Emissão de **IPTU**" {
+		t.Errorf("resumo não sanitizado: %q", service.Resumo)
+	}
+	if service.DocumentosNecessarios[0] != "RG" {
+		t.Errorf("documentos não sanitizados: %v", service.DocumentosNecessarios)
+	}
+	if service.Buttons[0].Titulo != "Pagar" || service.Buttons[0].URLService != "" {
+		t.Errorf("botão não sanitizado: %+v", service.Buttons[0])
+	}
+}
+
+func TestValidateServiceInputButtonActions(t *testing.T) {
+	request := &models.PrefRioServiceRequest{Buttons: []models.Button{
+		{Titulo: "Portal", URLService: "http://carioca.rio/servico"},
+		{Titulo: "Atendimento", ActionType: models.ButtonActionWhatsApp, Phone: "(21) 98888-7777", Message: "Olá, IPTU"},
+		{Titulo: "Ligar", ActionType: models.ButtonActionPhone, Phone: "1746"},
+		{Titulo: "Entrar", ActionType: models.ButtonActionPrefLoginFlow, URLService: "carioca.rio/minha-conta"},
+	}}
+	if err := ValidateServiceInput(request); err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	want := []models.Button{
+		{ActionType: models.ButtonActionExternalLink, URLService: "https://carioca.rio/servico"},
+		{ActionType: models.ButtonActionWhatsApp, Phone: "+5521988887777", Message: "Olá, IPTU", URLService: "https://wa.me/5521988887777?text=Ol%C3%A1%2C+IPTU"},
+		{ActionType: models.ButtonActionPhone, Phone: "1746", URLService: "tel:1746"},
+		{ActionType: models.ButtonActionPrefLoginFlow, URLService: "https://carioca.rio/minha-conta"},
+	}
+	for i, button := range request.Buttons {
+		if button.ActionType != want[i].ActionType || button.URLService != want[i].URLService || button.Phone != want[i].Phone || button.Message != want[i].Message {
+			t.Errorf("buttons[%d] = %+v, esperado %+v", i, button, want[i])
+		}
+	}
+
+	invalid := &models.PrefRioServiceRequest{Buttons: []models.Button{
+		{Titulo: "WhatsApp", ActionType: models.ButtonActionWhatsApp},
+	}}
+	if err := ValidateServiceInput(invalid); err == nil || !strings.Contains(err.Error(), "buttons[0].phone") {
+		t.Errorf("whatsapp sem telefone deveria ser recusado: %v", err)
+	}
+}
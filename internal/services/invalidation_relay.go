@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// invalidationChannel canal Redis usado para retransmitir eventos entre réplicas
+const invalidationChannel = "busca:events"
+
+// NewRedisClient conecta ao Redis informado. Retorna nil se a URL for vazia, inválida
+// ou o servidor estiver inacessível, permitindo que os chamadores operem sem Redis.
+func NewRedisClient(redisURL string) *redis.Client {
+	if redisURL == "" {
+		return nil
+	}
+
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		log.Printf("Aviso: REDIS_URL inválida, recursos distribuídos desabilitados: %v", err)
+		return nil
+	}
+
+	client := redis.NewClient(opts)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		log.Printf("Aviso: Redis indisponível, recursos distribuídos desabilitados: %v", err)
+		client.Close()
+		return nil
+	}
+
+	return client
+}
+
+// InvalidationRelay retransmite os eventos do EventBus local para as demais réplicas
+// via Redis pub/sub e publica localmente os eventos recebidos delas
+type InvalidationRelay struct {
+	bus    *EventBus
+	redis  *redis.Client
+	origin string
+}
+
+// NewInvalidationRelay cria o relay entre o EventBus local e o Redis
+func NewInvalidationRelay(bus *EventBus, client *redis.Client) *InvalidationRelay {
+	return &InvalidationRelay{
+		bus:    bus,
+		redis:  client,
+		origin: uuid.New().String(),
+	}
+}
+
+// Start assina o EventBus local e inicia a escuta dos eventos das demais réplicas
+func (r *InvalidationRelay) Start() {
+	r.bus.Subscribe(r.forward)
+	go r.listen()
+	log.Printf("Relay de invalidação entre réplicas iniciado (origem %s)", r.origin)
+}
+
+// forward envia ao Redis os eventos gerados nesta réplica
+func (r *InvalidationRelay) forward(ctx context.Context, event DocumentEvent) {
+	if event.Origin != "" {
+		// evento recebido de outra réplica: não retransmitir
+		return
+	}
+
+	event.Origin = r.origin
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	if err := r.redis.Publish(ctx, invalidationChannel, data).Err(); err != nil {
+		log.Printf("Erro ao retransmitir evento %s para réplicas: %v", event.Type, err)
+	}
+}
+
+// listen publica localmente os eventos recebidos das demais réplicas
+func (r *InvalidationRelay) listen() {
+	sub := r.redis.Subscribe(context.Background(), invalidationChannel)
+	defer sub.Close()
+
+	for msg := range sub.Channel() {
+		var event DocumentEvent
+		if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+			log.Printf("Evento inválido recebido de réplica: %v", err)
+			continue
+		}
+		if event.Origin == r.origin || event.Origin == "" {
+			continue
+		}
+		r.bus.Publish(context.Background(), event)
+	}
+}
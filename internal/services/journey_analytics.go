@@ -0,0 +1,407 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/jobs"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/pii"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+const (
+	JourneyEventsCollection = "search_journeys"
+
+	// journeyMaxPending limita os eventos acumulados entre flushes
+	journeyMaxPending = 20000
+	// journeyMaxEvents limita os eventos lidos para montar o relatório
+	journeyMaxEvents = 200000
+	// journeyGap inatividade que encerra uma jornada na mesma sessão
+	journeyGap = 30 * time.Minute
+	// journeyMaxPathQueries últimas queries mantidas em cada caminho de refinamento
+	journeyMaxPathQueries = 5
+)
+
+// sessionIDPattern IDs de sessão aceitos: identificadores opacos gerados pelo cliente (UUID,
+// nanoid etc.)
+var sessionIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{8,64}$`)
+
+// JourneyAnalytics registra buscas e cliques por sessão anônima, para reconstruir as jornadas
+// (refinamentos de query até o clique). Os eventos são acumulados em memória e persistidos
+// periodicamente na collection search_journeys.
+type JourneyAnalytics struct {
+	client *typesense.Client
+
+	mu      sync.Mutex
+	pending []models.JourneyEvent
+}
+
+// NewJourneyAnalytics cria o registro de jornadas
+func NewJourneyAnalytics(client *typesense.Client) *JourneyAnalytics {
+	return &JourneyAnalytics{client: client}
+}
+
+// ValidSessionID indica se o ID de sessão pode ser registrado: identificador opaco, sem
+// dados pessoais (ex.: CPF ou telefone usados como ID)
+func ValidSessionID(sessionID string) bool {
+	return sessionIDPattern.MatchString(sessionID) && pii.Scrub(sessionID) == sessionID
+}
+
+// RecordSearch registra a busca da sessão, com o tipo, a duração e as intervenções aplicadas aos
+// resultados. Sessões inválidas e buscas sensíveis são ignoradas; dados pessoais da query são
+// mascarados.
+func (ja *JourneyAnalytics) RecordSearch(ctx context.Context, sessionID, query string, searchType models.SearchType, resultCount int, latency time.Duration, interventions []string) {
+	if ja == nil || !ValidSessionID(sessionID) || SensitiveTopicFromContext(ctx) != "" {
+		return
+	}
+	query = normalizeQueryKey(pii.Scrub(query))
+	if query == "" {
+		return
+	}
+
+	ja.record(models.JourneyEvent{
+		SessionID:     sessionID,
+		Type:          models.JourneyEventSearch,
+		Query:         query,
+		ResultCount:   resultCount,
+		SearchType:    string(searchType),
+		LatencyMs:     latency.Milliseconds(),
+		Timestamp:     time.Now().Unix(),
+		Interventions: interventions,
+	})
+}
+
+// RecordClick registra o clique da sessão em um resultado
+func (ja *JourneyAnalytics) RecordClick(req models.JourneyClickRequest) error {
+	if ja == nil {
+		return nil
+	}
+	if !ValidSessionID(req.SessionID) {
+		return fmt.Errorf("session_id inválido: use um identificador opaco de 8 a 64 caracteres (letras, números, - ou _)")
+	}
+
+	ja.record(models.JourneyEvent{
+		SessionID: req.SessionID,
+		Type:      models.JourneyEventClick,
+		Query:     normalizeQueryKey(pii.Scrub(req.Query)),
+		ServiceID: req.ServiceID,
+		Position:  req.Position,
+		Timestamp: time.Now().Unix(),
+	})
+	return nil
+}
+
+func (ja *JourneyAnalytics) record(event models.JourneyEvent) {
+	ja.mu.Lock()
+	defer ja.mu.Unlock()
+	if len(ja.pending) >= journeyMaxPending {
+		return
+	}
+	ja.pending = append(ja.pending, event)
+}
+
+// Flush persiste os eventos acumulados desde o último flush
+func (ja *JourneyAnalytics) Flush(ctx context.Context) error {
+	ja.mu.Lock()
+	pending := ja.pending
+	ja.pending = nil
+	ja.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if err := ja.ensureCollection(ctx); err != nil {
+		return err
+	}
+
+	for start := 0; start < len(pending); start += 500 {
+		end := min(start+500, len(pending))
+
+		docs := make([]interface{}, 0, end-start)
+		for _, event := range pending[start:end] {
+			docs = append(docs, event)
+		}
+		if _, err := ja.client.Collection(JourneyEventsCollection).Documents().Import(ctx, docs, &api.ImportDocumentsParams{
+			Action: pointer.Any(api.Create),
+		}); err != nil {
+			return fmt.Errorf("erro ao persistir eventos de jornada: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// StartRoutines inicia o flush periódico dos eventos e agenda a remoção diária dos eventos
+// mais antigos que retentionDays (job journeys-cleanup)
+func (ja *JourneyAnalytics) StartRoutines(runner *jobs.Runner, flushInterval time.Duration, retentionDays int) error {
+	go func() {
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			if err := ja.Flush(ctx); err != nil {
+				log.Printf("[Journeys] Erro ao persistir eventos: %v", err)
+			}
+			cancel()
+		}
+	}()
+
+	return runner.Register("journeys-cleanup", "30 4 * * *", 10*time.Minute, func(ctx context.Context) error {
+		if err := ja.ensureCollection(ctx); err != nil {
+			return err
+		}
+		before := time.Now().AddDate(0, 0, -retentionDays).Unix()
+		_, err := ja.client.Collection(JourneyEventsCollection).Documents().Delete(ctx, &api.DeleteDocumentsParams{
+			FilterBy: pointer.String(fmt.Sprintf("timestamp:<%d", before)),
+		})
+		return err
+	})
+}
+
+// Journeys reconstrói as jornadas dos últimos days dias e retorna os limit caminhos de
+// refinamento mais comuns
+func (ja *JourneyAnalytics) Journeys(ctx context.Context, days, limit int) (*models.JourneyReport, error) {
+	if err := ja.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	events, err := ja.fetchEvents(ctx, now.AddDate(0, 0, -days).Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	report := buildJourneyReport(events, limit)
+	report.Days = days
+	report.GeneratedAt = now.Unix()
+	return report, nil
+}
+
+// Events retorna as buscas e os cliques dos últimos days dias, em ordem cronológica. Sem
+// jornadas habilitadas, retorna nil.
+func (ja *JourneyAnalytics) Events(ctx context.Context, days int) ([]models.JourneyEvent, error) {
+	if ja == nil {
+		return nil, nil
+	}
+	if err := ja.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+	return ja.fetchEvents(ctx, time.Now().AddDate(0, 0, -days).Unix())
+}
+
+// ClickCounts conta os cliques dos últimos days dias por service_id e retorna também o total
+// de cliques do período
+func (ja *JourneyAnalytics) ClickCounts(ctx context.Context, days int) (map[string]int, int, error) {
+	counts := make(map[string]int)
+	if ja == nil {
+		return counts, 0, nil
+	}
+	if err := ja.ensureCollection(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	events, err := ja.fetchEvents(ctx, time.Now().AddDate(0, 0, -days).Unix())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := 0
+	for _, event := range events {
+		if event.Type != models.JourneyEventClick || event.ServiceID == "" {
+			continue
+		}
+		counts[event.ServiceID]++
+		total++
+	}
+	return counts, total, nil
+}
+
+// fetchEvents busca os eventos a partir do timestamp, em ordem cronológica
+func (ja *JourneyAnalytics) fetchEvents(ctx context.Context, since int64) ([]models.JourneyEvent, error) {
+	var events []models.JourneyEvent
+	perPage := 250
+
+	for page := 1; len(events) < journeyMaxEvents; page++ {
+		result, err := ja.client.Collection(JourneyEventsCollection).Documents().Search(ctx, &api.SearchCollectionParams{
+			Q:        pointer.String("*"),
+			FilterBy: pointer.String(fmt.Sprintf("timestamp:>=%d", since)),
+			SortBy:   pointer.String("timestamp:asc"),
+			Page:     pointer.Int(page),
+			PerPage:  pointer.Int(perPage),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar eventos de jornada: %w", err)
+		}
+		if result.Hits == nil || len(*result.Hits) == 0 {
+			break
+		}
+
+		for _, hit := range *result.Hits {
+			if hit.Document == nil {
+				continue
+			}
+			doc := *hit.Document
+			events = append(events, models.JourneyEvent{
+				SessionID:   getString(doc, "session_id"),
+				Type:        getString(doc, "type"),
+				Query:       getString(doc, "query"),
+				ServiceID:   getString(doc, "service_id"),
+				Position:    int(getInt64(doc, "position")),
+				ResultCount: int(getInt64(doc, "result_count")),
+				SearchType:  getString(doc, "search_type"),
+				LatencyMs:   getInt64(doc, "latency_ms"),
+				Timestamp:   getInt64(doc, "timestamp"),
+			})
+		}
+		if len(*result.Hits) < perPage {
+			break
+		}
+	}
+
+	return events, nil
+}
+
+// journey buscas consecutivas de uma sessão até o primeiro clique
+type journey struct {
+	queries   []string
+	serviceID string
+	clicked   bool
+}
+
+// buildJourneyReport agrupa os eventos (em ordem cronológica) por sessão e os divide em
+// jornadas: cada jornada termina no primeiro clique ou após journeyGap sem eventos. Cliques
+// seguintes na mesma jornada são ignorados e queries repetidas em sequência (paginação) contam
+// uma vez.
+func buildJourneyReport(events []models.JourneyEvent, limit int) *models.JourneyReport {
+	bySession := make(map[string][]models.JourneyEvent)
+	for _, event := range events {
+		bySession[event.SessionID] = append(bySession[event.SessionID], event)
+	}
+
+	report := &models.JourneyReport{Sessions: len(bySession), Paths: []models.JourneyPath{}}
+	paths := make(map[string]*models.JourneyPath)
+	totalQueriesToClick := 0
+
+	for _, sessionEvents := range bySession {
+		for _, j := range splitJourneys(sessionEvents) {
+			report.Journeys++
+			if j.clicked {
+				report.JourneysWithClick++
+				totalQueriesToClick += len(j.queries)
+			}
+			if len(j.queries) < 2 {
+				continue
+			}
+			report.RefinedJourneys++
+
+			queries := j.queries
+			if len(queries) > journeyMaxPathQueries {
+				queries = queries[len(queries)-journeyMaxPathQueries:]
+			}
+			key := strings.Join(queries, "\x00") + "\x01" + j.serviceID
+			path, ok := paths[key]
+			if !ok {
+				path = &models.JourneyPath{Queries: queries, ServiceID: j.serviceID, Clicked: j.clicked}
+				paths[key] = path
+			}
+			path.Count++
+		}
+	}
+
+	if report.JourneysWithClick > 0 {
+		avg := float64(totalQueriesToClick) / float64(report.JourneysWithClick)
+		report.AvgQueriesToClick = math.Round(avg*100) / 100
+	}
+
+	for _, path := range paths {
+		report.Paths = append(report.Paths, *path)
+	}
+	sort.Slice(report.Paths, func(a, b int) bool {
+		pa, pb := report.Paths[a], report.Paths[b]
+		if pa.Count != pb.Count {
+			return pa.Count > pb.Count
+		}
+		return strings.Join(pa.Queries, " → ")+pa.ServiceID < strings.Join(pb.Queries, " → ")+pb.ServiceID
+	})
+	if len(report.Paths) > limit {
+		report.Paths = report.Paths[:limit]
+	}
+
+	return report
+}
+
+// splitJourneys divide os eventos de uma sessão em jornadas
+func splitJourneys(events []models.JourneyEvent) []journey {
+	var journeys []journey
+	current := -1
+	var last int64
+
+	for _, event := range events {
+		if current >= 0 && event.Timestamp-last > int64(journeyGap.Seconds()) {
+			current = -1
+		}
+		last = event.Timestamp
+
+		switch event.Type {
+		case models.JourneyEventSearch:
+			if current < 0 || journeys[current].clicked {
+				journeys = append(journeys, journey{})
+				current = len(journeys) - 1
+			}
+			j := &journeys[current]
+			if n := len(j.queries); n == 0 || j.queries[n-1] != event.Query {
+				j.queries = append(j.queries, event.Query)
+			}
+		case models.JourneyEventClick:
+			// Cliques sem busca anterior na jornada (ex.: link direto) não formam jornada
+			if current < 0 || journeys[current].clicked {
+				continue
+			}
+			journeys[current].clicked = true
+			journeys[current].serviceID = event.ServiceID
+		}
+	}
+
+	return journeys
+}
+
+func (ja *JourneyAnalytics) ensureCollection(ctx context.Context) error {
+	_, err := ja.client.Collection(JourneyEventsCollection).Retrieve(ctx)
+	if err == nil {
+		return nil
+	}
+
+	schema := &api.CollectionSchema{
+		Name: JourneyEventsCollection,
+		Fields: []api.Field{
+			{Name: "session_id", Type: "string", Facet: pointer.True()},
+			{Name: "type", Type: "string", Facet: pointer.True()},
+			{Name: "query", Type: "string", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "service_id", Type: "string", Facet: pointer.True(), Optional: pointer.True()},
+			{Name: "position", Type: "int32", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "result_count", Type: "int32", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "search_type", Type: "string", Facet: pointer.True(), Optional: pointer.True()},
+			{Name: "latency_ms", Type: "int64", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "interventions", Type: "string[]", Facet: pointer.True(), Optional: pointer.True()},
+			{Name: "timestamp", Type: "int64", Facet: pointer.False()},
+		},
+		DefaultSortingField: pointer.String("timestamp"),
+	}
+
+	if _, err := ja.client.Collections().Create(ctx, schema); err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %w", JourneyEventsCollection, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestBuildJourneyReportRefinementPaths(t *testing.T) {
+	search := func(session, query string, ts int64) models.JourneyEvent {
+		return models.JourneyEvent{SessionID: session, Type: models.JourneyEventSearch, Query: query, Timestamp: ts}
+	}
+	click := func(session, serviceID string, ts int64) models.JourneyEvent {
+		return models.JourneyEvent{SessionID: session, Type: models.JourneyEventClick, ServiceID: serviceID, Timestamp: ts}
+	}
+
+	events := []models.JourneyEvent{
+		// Dois refinamentos iguais até o clique no mesmo serviço (a página 2 repete a query)
+		search("sessao-aaaa", "iptu", 0), search("sessao-aaaa", "iptu", 5), search("sessao-aaaa", "segunda via iptu", 20), click("sessao-aaaa", "svc-1", 30),
+		search("sessao-bbbb", "iptu", 100), search("sessao-bbbb", "segunda via iptu", 110), click("sessao-bbbb", "svc-1", 120), click("sessao-bbbb", "svc-2", 125),
+		// Jornada sem clique, depois uma nova jornada após a inatividade
+		search("sessao-cccc", "alvara", 0), search("sessao-cccc", "licenca", 10),
+		search("sessao-cccc", "poda de arvore", 4000), click("sessao-cccc", "svc-3", 4010),
+	}
+
+	report := buildJourneyReport(events, 10)
+	if report.Sessions != 3 || report.Journeys != 4 || report.JourneysWithClick != 3 || report.RefinedJourneys != 3 {
+		t.Fatalf("contagens inesperadas: %+v", report)
+	}
+	if report.AvgQueriesToClick != 1.67 {
+		t.Errorf("avg_queries_to_click = %v", report.AvgQueriesToClick)
+	}
+	if len(report.Paths) != 2 {
+		t.Fatalf("caminhos = %+v", report.Paths)
+	}
+	top := report.Paths[0]
+	if top.Count != 2 || !top.Clicked || top.ServiceID != "svc-1" || len(top.Queries) != 2 || top.Queries[1] != "segunda via iptu" {
+		t.Errorf("caminho mais comum = %+v", top)
+	}
+	if report.Paths[1].Clicked || report.Paths[1].Queries[0] != "alvara" {
+		t.Errorf("caminho sem clique = %+v", report.Paths[1])
+	}
+}
+
+func TestValidSessionID(t *testing.T) {
+	valid := []string{"3f1c2a9e-8b7d-4c55-9a10-2b6e4f0d1c3a", "V1StGXR8_Z5jdHi6B-myT"}
+	invalid := []string{"", "curto", "fulano@exemplo.com", "123.456.789-09", "12345678909", "sessão com espaço"}
+
+	for _, id := range valid {
+		if !ValidSessionID(id) {
+			t.Errorf("%q deveria ser aceito", id)
+		}
+	}
+	for _, id := range invalid {
+		if ValidSessionID(id) {
+			t.Errorf("%q deveria ser recusado", id)
+		}
+	}
+}
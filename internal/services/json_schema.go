@@ -0,0 +1,305 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// jsonSchema é o subconjunto do JSON Schema aceito para os extra_fields: type, properties,
+// required, additionalProperties (booleano), enum, minLength, maxLength, pattern, minimum,
+// maximum, items, minItems e maxItems. title, description, default e $schema são aceitos e
+// ignorados; outras palavras-chave são recusadas para não dar a falsa impressão de validação.
+type jsonSchema struct {
+	types                []string
+	properties           map[string]*jsonSchema
+	required             []string
+	additionalProperties *bool
+	enum                 []interface{}
+	minLength, maxLength *int
+	pattern              *regexp.Regexp
+	minimum, maximum     *float64
+	items                *jsonSchema
+	minItems, maxItems   *int
+}
+
+var jsonSchemaTypes = map[string]bool{
+	"object": true, "array": true, "string": true, "integer": true, "number": true, "boolean": true, "null": true,
+}
+
+var jsonSchemaIgnored = map[string]bool{
+	"title": true, "description": true, "default": true, "$schema": true, "examples": true,
+}
+
+// compileJSONSchema valida o documento de schema e o converte para validação
+func compileJSONSchema(raw map[string]interface{}, path string) (*jsonSchema, error) {
+	schema := &jsonSchema{}
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := raw[key]
+		var err error
+		switch key {
+		case "type":
+			schema.types, err = schemaTypes(value)
+		case "properties":
+			props, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s.properties deve ser um objeto", path)
+			}
+			schema.properties = make(map[string]*jsonSchema, len(props))
+			for name, prop := range props {
+				propRaw, ok := prop.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("%s.properties.%s deve ser um objeto", path, name)
+				}
+				if schema.properties[name], err = compileJSONSchema(propRaw, path+".properties."+name); err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			list, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s.required deve ser uma lista de nomes", path)
+			}
+			for _, item := range list {
+				name, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("%s.required deve ser uma lista de nomes", path)
+				}
+				schema.required = append(schema.required, name)
+			}
+		case "additionalProperties":
+			allowed, ok := value.(bool)
+			if !ok {
+				return nil, fmt.Errorf("%s.additionalProperties deve ser booleano", path)
+			}
+			schema.additionalProperties = &allowed
+		case "enum":
+			list, ok := value.([]interface{})
+			if !ok || len(list) == 0 {
+				return nil, fmt.Errorf("%s.enum deve ser uma lista não vazia", path)
+			}
+			schema.enum = list
+		case "minLength":
+			schema.minLength, err = schemaInt(value, path+".minLength")
+		case "maxLength":
+			schema.maxLength, err = schemaInt(value, path+".maxLength")
+		case "minItems":
+			schema.minItems, err = schemaInt(value, path+".minItems")
+		case "maxItems":
+			schema.maxItems, err = schemaInt(value, path+".maxItems")
+		case "minimum":
+			schema.minimum, err = schemaNumber(value, path+".minimum")
+		case "maximum":
+			schema.maximum, err = schemaNumber(value, path+".maximum")
+		case "pattern":
+			expr, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s.pattern deve ser uma expressão regular", path)
+			}
+			if schema.pattern, err = regexp.Compile(expr); err != nil {
+				return nil, fmt.Errorf("%s.pattern inválido: %w", path, err)
+			}
+		case "items":
+			itemsRaw, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s.items deve ser um objeto", path)
+			}
+			schema.items, err = compileJSONSchema(itemsRaw, path+".items")
+		default:
+			if !jsonSchemaIgnored[key] {
+				return nil, fmt.Errorf("%s: palavra-chave %q não suportada", path, key)
+			}
+		}
+		if err != nil {
+			if strings.HasPrefix(err.Error(), path) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%s.%s: %w", path, key, err)
+		}
+	}
+	return schema, nil
+}
+
+// validate verifica o valor contra o schema; path identifica o valor na mensagem de erro
+func (s *jsonSchema) validate(value interface{}, path string) error {
+	if len(s.types) > 0 && !s.matchesType(value) {
+		return fmt.Errorf("%s: esperado %s", path, strings.Join(s.types, " ou "))
+	}
+
+	if len(s.enum) > 0 {
+		found := false
+		for _, option := range s.enum {
+			if jsonEqual(option, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: valor fora das opções permitidas", path)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return s.validateObject(v, path)
+	case []interface{}:
+		if s.minItems != nil && len(v) < *s.minItems {
+			return fmt.Errorf("%s: mínimo de %d itens", path, *s.minItems)
+		}
+		if s.maxItems != nil && len(v) > *s.maxItems {
+			return fmt.Errorf("%s: máximo de %d itens", path, *s.maxItems)
+		}
+		if s.items != nil {
+			for i, item := range v {
+				if err := s.items.validate(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.minLength != nil && length < *s.minLength {
+			return fmt.Errorf("%s: mínimo de %d caracteres", path, *s.minLength)
+		}
+		if s.maxLength != nil && length > *s.maxLength {
+			return fmt.Errorf("%s: máximo de %d caracteres", path, *s.maxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			return fmt.Errorf("%s: formato inválido", path)
+		}
+	default:
+		if number, ok := toFloat(value); ok {
+			if s.minimum != nil && number < *s.minimum {
+				return fmt.Errorf("%s: mínimo %v", path, *s.minimum)
+			}
+			if s.maximum != nil && number > *s.maximum {
+				return fmt.Errorf("%s: máximo %v", path, *s.maximum)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *jsonSchema) validateObject(object map[string]interface{}, path string) error {
+	for _, name := range s.required {
+		if _, ok := object[name]; !ok {
+			return fmt.Errorf("%s.%s: campo obrigatório", path, name)
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, ok := s.properties[name]
+		if !ok {
+			if s.additionalProperties != nil && !*s.additionalProperties {
+				return fmt.Errorf("%s.%s: campo não previsto no schema", path, name)
+			}
+			continue
+		}
+		if err := prop.validate(object[name], path+"."+name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *jsonSchema) matchesType(value interface{}) bool {
+	for _, t := range s.types {
+		if jsonValueType(value, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonValueType indica se o valor decodificado de JSON é do tipo do schema
+func jsonValueType(value interface{}, schemaType string) bool {
+	switch schemaType {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	case "number":
+		_, ok := toFloat(value)
+		return ok
+	case "integer":
+		number, ok := toFloat(value)
+		return ok && number == math.Trunc(number)
+	}
+	return false
+}
+
+func jsonEqual(a, b interface{}) bool {
+	if na, ok := toFloat(a); ok {
+		nb, ok := toFloat(b)
+		return ok && na == nb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func schemaTypes(value interface{}) ([]string, error) {
+	var types []string
+	switch v := value.(type) {
+	case string:
+		types = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			t, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("tipos devem ser textos")
+			}
+			types = append(types, t)
+		}
+	default:
+		return nil, fmt.Errorf("deve ser um tipo ou lista de tipos")
+	}
+
+	for _, t := range types {
+		if !jsonSchemaTypes[t] {
+			return nil, fmt.Errorf("tipo %q desconhecido", t)
+		}
+	}
+	return types, nil
+}
+
+func schemaInt(value interface{}, path string) (*int, error) {
+	number, ok := toFloat(value)
+	if !ok || number < 0 || number != math.Trunc(number) {
+		return nil, fmt.Errorf("%s deve ser um inteiro não negativo", path)
+	}
+	n := int(number)
+	return &n, nil
+}
+
+func schemaNumber(value interface{}, path string) (*float64, error) {
+	number, ok := toFloat(value)
+	if !ok {
+		return nil, fmt.Errorf("%s deve ser um número", path)
+	}
+	return &number, nil
+}
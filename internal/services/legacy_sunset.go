@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+// LegacyCollections collections legadas substituídas por prefrio_services_base via tombamentos
+var LegacyCollections = []string{"1746_v2_llm", "carioca-digital_v2_llm"}
+
+// LegacySunsetService mede o que falta para desativar as collections legadas: documentos ainda
+// não tombados e o tráfego (cliques das jornadas de busca) que eles recebem
+type LegacySunsetService struct {
+	client   *typesense.Client
+	journeys *JourneyAnalytics
+	cfg      *config.Config
+}
+
+// NewLegacySunsetService cria o serviço. Sem jornadas, o relatório sai sem tráfego e a lista
+// segue a ordem dos documentos.
+func NewLegacySunsetService(client *typesense.Client, journeys *JourneyAnalytics, cfg *config.Config) *LegacySunsetService {
+	return &LegacySunsetService{
+		client:   client,
+		journeys: journeys,
+		cfg:      cfg,
+	}
+}
+
+// legacyDocument documento de uma collection legada
+type legacyDocument struct {
+	collection string
+	id         string
+	title      string
+}
+
+// Report gera o relatório com os cliques dos últimos days dias e os limit primeiros itens da
+// lista de tombamento (0 = todos)
+func (ls *LegacySunsetService) Report(ctx context.Context, days, limit int) (*models.LegacySunsetReport, error) {
+	start := time.Now()
+
+	tombados, err := ls.tombados(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []legacyDocument
+	for _, collection := range LegacyCollections {
+		collectionDocs, err := ls.documents(ctx, collection)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, collectionDocs...)
+	}
+
+	clicks, totalClicks, err := ls.journeys.ClickCounts(ctx, days)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao contar cliques: %w", err)
+	}
+
+	report := buildLegacySunsetReport(docs, tombados, clicks, totalClicks, limit)
+	report.GeneratedAt = start.Unix()
+	report.Days = days
+	report.Frozen = ls.cfg.LegacyCollectionsFrozen
+	report.DurationMs = time.Since(start).Milliseconds()
+	return report, nil
+}
+
+// tombados retorna os IDs antigos já tombados por collection legada
+func (ls *LegacySunsetService) tombados(ctx context.Context) (map[string]map[string]bool, error) {
+	body, err := ls.client.Collection(TombamentosCollection).Documents().Export(ctx, &api.ExportDocumentsParams{
+		IncludeFields: pointer.String("origem,id_servico_antigo"),
+	})
+	if err != nil {
+		if IsNotFoundError(err) {
+			return map[string]map[string]bool{}, nil
+		}
+		return nil, fmt.Errorf("erro ao exportar tombamentos: %w", err)
+	}
+	defer body.Close()
+
+	rows, err := decodeExportedDocuments(body)
+	if err != nil {
+		return nil, err
+	}
+
+	tombados := make(map[string]map[string]bool)
+	for _, row := range rows {
+		origem, idAntigo := getString(row, "origem"), getString(row, "id_servico_antigo")
+		if origem == "" || idAntigo == "" {
+			continue
+		}
+		if tombados[origem] == nil {
+			tombados[origem] = make(map[string]bool)
+		}
+		tombados[origem][idAntigo] = true
+	}
+	return tombados, nil
+}
+
+// documents lista os documentos da collection legada com o título do field_mapping, se houver
+func (ls *LegacySunsetService) documents(ctx context.Context, collection string) ([]legacyDocument, error) {
+	titleField := ""
+	if collConfig := ls.cfg.GetCollectionConfig(collection); collConfig != nil {
+		titleField = collConfig.Fields().Title
+	}
+	includeFields := "id"
+	if titleField != "" {
+		includeFields += "," + titleField
+	}
+
+	body, err := ls.client.Collection(collection).Documents().Export(ctx, &api.ExportDocumentsParams{
+		IncludeFields: pointer.String(includeFields),
+	})
+	if err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao exportar %s: %w", collection, err)
+	}
+	defer body.Close()
+
+	rows, err := decodeExportedDocuments(body)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]legacyDocument, 0, len(rows))
+	for _, row := range rows {
+		docs = append(docs, legacyDocument{
+			collection: collection,
+			id:         getString(row, "id"),
+			title:      mappedString(row, titleField),
+		})
+	}
+	return docs, nil
+}
+
+// buildLegacySunsetReport soma os documentos restantes e o tráfego de cada collection e ordena
+// os não tombados pelos cliques (mais acessados primeiro)
+func buildLegacySunsetReport(docs []legacyDocument, tombados map[string]map[string]bool, clicks map[string]int, totalClicks, limit int) *models.LegacySunsetReport {
+	share := func(n int) float64 {
+		if totalClicks == 0 {
+			return 0
+		}
+		return float64(n) / float64(totalClicks)
+	}
+
+	byCollection := make(map[string]*models.LegacyCollectionSunset, len(LegacyCollections))
+	for _, collection := range LegacyCollections {
+		byCollection[collection] = &models.LegacyCollectionSunset{Collection: collection}
+	}
+
+	var remaining []legacyDocument
+	for _, doc := range docs {
+		status := byCollection[doc.collection]
+		status.TotalDocuments++
+		if tombados[doc.collection][doc.id] {
+			status.Tombados++
+			continue
+		}
+		status.Remaining++
+		status.Clicks += clicks[doc.id]
+		remaining = append(remaining, doc)
+	}
+
+	sort.SliceStable(remaining, func(i, j int) bool {
+		ci, cj := clicks[remaining[i].id], clicks[remaining[j].id]
+		if ci != cj {
+			return ci > cj
+		}
+		if remaining[i].collection != remaining[j].collection {
+			return remaining[i].collection < remaining[j].collection
+		}
+		return remaining[i].id < remaining[j].id
+	})
+	if limit > 0 && len(remaining) > limit {
+		remaining = remaining[:limit]
+	}
+
+	report := &models.LegacySunsetReport{
+		TotalClicks: totalClicks,
+		Collections: make([]models.LegacyCollectionSunset, 0, len(LegacyCollections)),
+		Worklist:    make([]models.LegacySunsetItem, 0, len(remaining)),
+	}
+	for _, collection := range LegacyCollections {
+		status := byCollection[collection]
+		status.TrafficShare = share(status.Clicks)
+		report.Collections = append(report.Collections, *status)
+	}
+	for i, doc := range remaining {
+		report.Worklist = append(report.Worklist, models.LegacySunsetItem{
+			Priority:     i + 1,
+			Collection:   doc.collection,
+			ID:           doc.id,
+			Title:        doc.title,
+			Clicks:       clicks[doc.id],
+			TrafficShare: share(clicks[doc.id]),
+		})
+	}
+	return report
+}
@@ -0,0 +1,45 @@
+package services
+
+import "testing"
+
+func TestBuildLegacySunsetReport(t *testing.T) {
+	docs := []legacyDocument{
+		{collection: "1746_v2_llm", id: "a", title: "Remoção de entulho"},
+		{collection: "1746_v2_llm", id: "b"},
+		{collection: "1746_v2_llm", id: "c"},
+		{collection: "carioca-digital_v2_llm", id: "d"},
+		{collection: "carioca-digital_v2_llm", id: "e"},
+	}
+	tombados := map[string]map[string]bool{"1746_v2_llm": {"b": true}}
+	clicks := map[string]int{"a": 2, "b": 10, "d": 5}
+
+	report := buildLegacySunsetReport(docs, tombados, clicks, 40, 0)
+
+	status := report.Collections[0]
+	if status.Collection != "1746_v2_llm" || status.TotalDocuments != 3 || status.Tombados != 1 || status.Remaining != 2 {
+		t.Errorf("1746 status = %+v", status)
+	}
+	if status.Clicks != 2 || status.TrafficShare != 0.05 {
+		t.Errorf("1746 traffic = %d (%.3f), want only the clicks on untombado documents", status.Clicks, status.TrafficShare)
+	}
+	if got := report.Collections[1]; got.Remaining != 2 || got.Clicks != 5 {
+		t.Errorf("carioca-digital status = %+v", got)
+	}
+
+	want := []string{"d", "a", "c", "e"}
+	if len(report.Worklist) != len(want) {
+		t.Fatalf("worklist = %+v, want %v", report.Worklist, want)
+	}
+	for i, id := range want {
+		if item := report.Worklist[i]; item.ID != id || item.Priority != i+1 {
+			t.Errorf("worklist[%d] = %+v, want %s with priority %d", i, item, id, i+1)
+		}
+	}
+	if report.Worklist[1].Title != "Remoção de entulho" {
+		t.Errorf("title = %q", report.Worklist[1].Title)
+	}
+
+	if limited := buildLegacySunsetReport(docs, tombados, clicks, 0, 1); len(limited.Worklist) != 1 || limited.Worklist[0].TrafficShare != 0 {
+		t.Errorf("limited worklist = %+v, want one item and no share without clicks", limited.Worklist)
+	}
+}
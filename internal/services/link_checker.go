@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// linkCheckResult resultado da verificação de um link
+type linkCheckResult struct {
+	StatusCode int
+	Err        error
+}
+
+// Broken indica se o link deve ser reportado como quebrado
+func (r linkCheckResult) Broken() bool {
+	return r.Err != nil || r.StatusCode >= 400
+}
+
+// linkChecker verifica a disponibilidade de links externos com concorrência limitada
+type linkChecker struct {
+	httpClient  *http.Client
+	concurrency int
+}
+
+func newLinkChecker(timeout time.Duration, concurrency int) *linkChecker {
+	return &linkChecker{
+		httpClient:  &http.Client{Timeout: timeout},
+		concurrency: concurrency,
+	}
+}
+
+// CheckAll verifica cada URL uma única vez e retorna o resultado por URL
+func (lc *linkChecker) CheckAll(ctx context.Context, urls []string) map[string]linkCheckResult {
+	results := make(map[string]linkCheckResult, len(urls))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, lc.concurrency)
+
+	for _, u := range urls {
+		mu.Lock()
+		_, seen := results[u]
+		if !seen {
+			results[u] = linkCheckResult{}
+		}
+		mu.Unlock()
+		if seen {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(u string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := lc.check(ctx, u)
+			mu.Lock()
+			results[u] = result
+			mu.Unlock()
+		}(u)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// check faz HEAD e, se o servidor não suportar, repete com GET
+func (lc *linkChecker) check(ctx context.Context, u string) linkCheckResult {
+	status, err := lc.request(ctx, http.MethodHead, u)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented || status == http.StatusForbidden) {
+		status, err = lc.request(ctx, http.MethodGet, u)
+	}
+	return linkCheckResult{StatusCode: status, Err: err}
+}
+
+func (lc *linkChecker) request(ctx context.Context, method, u string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "app-busca-search-linkchecker/1.0")
+
+	resp, err := lc.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// isCheckableURL aceita apenas links http(s) absolutos
+func isCheckableURL(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+// Mailer envia e-mails de texto simples via SMTP
+type Mailer struct {
+	host     string
+	port     string
+	user     string
+	password string
+	from     string
+}
+
+// NewMailer cria o cliente SMTP. Com host vazio o envio de e-mails fica desabilitado
+func NewMailer(host, port, user, password, from string) *Mailer {
+	return &Mailer{host: host, port: port, user: user, password: password, from: from}
+}
+
+// Enabled indica se há um servidor SMTP configurado
+func (m *Mailer) Enabled() bool {
+	return m != nil && m.host != "" && m.from != ""
+}
+
+// Send envia um e-mail em texto simples (UTF-8)
+func (m *Mailer) Send(to []string, subject, body string) error {
+	if !m.Enabled() || len(to) == 0 {
+		return nil
+	}
+
+	var auth smtp.Auth
+	if m.user != "" {
+		auth = smtp.PlainAuth("", m.user, m.password, m.host)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + m.from,
+		"To: " + strings.Join(to, ", "),
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	if err := smtp.SendMail(m.host+":"+m.port, auth, m.from, to, []byte(msg)); err != nil {
+		return fmt.Errorf("erro ao enviar e-mail: %w", err)
+	}
+	return nil
+}
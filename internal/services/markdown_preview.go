@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+)
+
+// MarkdownFields campos do serviço editados em markdown
+var MarkdownFields = []string{
+	"resumo",
+	"tempo_atendimento",
+	"custo_servico",
+	"resultado_solicitacao",
+	"descricao_completa",
+	"instrucoes_solicitante",
+	"servico_nao_cobre",
+	"documentos_necessarios",
+}
+
+// ErrUnknownMarkdownField indica campo que não é editado em markdown
+var ErrUnknownMarkdownField = errors.New("campo não é editado em markdown")
+
+// unclosedMarkers marcações que, sobrando no texto após o parse, indicam formatação sem fechamento
+var unclosedMarkers = []string{"**", "__", "~~", "`"}
+
+// MarkdownPreviewer renderiza os campos em markdown com o mesmo sanitizador aplicado antes da
+// indexação e aponta problemas de renderização antes da publicação
+type MarkdownPreviewer struct {
+	links *linkChecker
+}
+
+// NewMarkdownPreviewer cria o serviço de pré-visualização
+func NewMarkdownPreviewer() *MarkdownPreviewer {
+	return &MarkdownPreviewer{links: newLinkChecker(5*time.Second, 8)}
+}
+
+// Preview renderiza cada campo e retorna o HTML com os avisos encontrados
+func (mp *MarkdownPreviewer) Preview(ctx context.Context, request *models.MarkdownPreviewRequest) (*models.MarkdownPreviewResponse, error) {
+	names := make([]string, 0, len(request.Fields))
+	for name := range request.Fields {
+		if !isMarkdownField(name) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownMarkdownField, name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	response := &models.MarkdownPreviewResponse{Fields: make(map[string]models.MarkdownPreviewField, len(names))}
+	externalLinks := make(map[string][]string) // campo -> links externos
+	for _, name := range names {
+		text := request.Fields[name]
+		doc := utils.ParseMarkdown(text)
+
+		var warnings []models.MarkdownWarning
+		if utils.SanitizeMarkdown(text) != strings.TrimSpace(text) {
+			warnings = append(warnings, models.MarkdownWarning{
+				Type:    models.MarkdownWarningSanitized,
+				Message: "HTML ou links com javascript:/data: serão removidos na publicação",
+			})
+		}
+		fieldWarnings, links := inspectMarkdown(doc)
+		warnings = append(warnings, fieldWarnings...)
+		externalLinks[name] = links
+
+		response.Fields[name] = models.MarkdownPreviewField{
+			HTML:     utils.RenderMarkdownHTML(doc),
+			Warnings: warnings,
+		}
+	}
+
+	if request.CheckLinks {
+		mp.checkLinks(ctx, response, externalLinks)
+	}
+
+	for name, field := range response.Fields {
+		if field.Warnings == nil {
+			field.Warnings = []models.MarkdownWarning{}
+			response.Fields[name] = field
+		}
+		response.TotalWarnings += len(field.Warnings)
+	}
+	return response, nil
+}
+
+// checkLinks verifica os links externos de todos os campos de uma vez
+func (mp *MarkdownPreviewer) checkLinks(ctx context.Context, response *models.MarkdownPreviewResponse, links map[string][]string) {
+	var all []string
+	for _, fieldLinks := range links {
+		all = append(all, fieldLinks...)
+	}
+	if len(all) == 0 {
+		return
+	}
+
+	results := mp.links.CheckAll(ctx, all)
+	for name, fieldLinks := range links {
+		field := response.Fields[name]
+		for _, link := range fieldLinks {
+			result := results[link]
+			if !result.Broken() {
+				continue
+			}
+			message := fmt.Sprintf("Link %s retornou status %d", link, result.StatusCode)
+			if result.Err != nil {
+				message = fmt.Sprintf("Link %s inacessível: %v", link, result.Err)
+			}
+			field.Warnings = append(field.Warnings, models.MarkdownWarning{Type: models.MarkdownWarningBrokenLink, Message: message})
+		}
+		response.Fields[name] = field
+	}
+}
+
+// inspectMarkdown percorre o documento procurando títulos mal usados, formatação sem
+// fechamento e links inválidos; retorna também os links externos a verificar
+func inspectMarkdown(doc ast.Node) ([]models.MarkdownWarning, []string) {
+	var warnings []models.MarkdownWarning
+	var links []string
+	seenLinks := make(map[string]bool)
+	previousLevel := 0
+
+	ast.WalkFunc(doc, func(node ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.GoToNext
+		}
+
+		switch n := node.(type) {
+		case *ast.Heading:
+			warnings = append(warnings, headingWarnings(n, previousLevel)...)
+			previousLevel = n.Level
+		case *ast.Link:
+			if warning, external := linkWarning(string(n.Destination)); warning != nil {
+				warnings = append(warnings, *warning)
+			} else if external != "" && !seenLinks[external] {
+				seenLinks[external] = true
+				links = append(links, external)
+			}
+		case *ast.Image:
+			if warning, _ := linkWarning(string(n.Destination)); warning != nil {
+				warnings = append(warnings, *warning)
+			}
+		case *ast.Text:
+			warnings = append(warnings, textWarnings(string(n.Literal))...)
+		}
+		return ast.GoToNext
+	})
+
+	return warnings, links
+}
+
+func headingWarnings(heading *ast.Heading, previousLevel int) []models.MarkdownWarning {
+	var warnings []models.MarkdownWarning
+	text := strings.TrimSpace(utils.StripMarkdown(markdownNodeText(heading)))
+
+	switch {
+	case heading.Level == 1:
+		warnings = append(warnings, models.MarkdownWarning{
+			Type:    models.MarkdownWarningHeading,
+			Message: fmt.Sprintf("Título de nível 1 (%q) é reservado ao nome do serviço; use ## ou inferior", text),
+		})
+	case previousLevel > 0 && heading.Level > previousLevel+1:
+		warnings = append(warnings, models.MarkdownWarning{
+			Type:    models.MarkdownWarningHeading,
+			Message: fmt.Sprintf("Título %q salta do nível %d para o %d", text, previousLevel, heading.Level),
+		})
+	}
+	if text == "" {
+		warnings = append(warnings, models.MarkdownWarning{
+			Type:    models.MarkdownWarningHeading,
+			Message: "Título vazio",
+		})
+	}
+	return warnings
+}
+
+// linkWarning valida o destino do link; links http(s) válidos são retornados para verificação
+func linkWarning(destination string) (*models.MarkdownWarning, string) {
+	destination = strings.TrimSpace(destination)
+	if destination == "" || destination == "#" {
+		return &models.MarkdownWarning{Type: models.MarkdownWarningBrokenLink, Message: "Link sem destino"}, ""
+	}
+
+	parsed, err := url.Parse(destination)
+	if err != nil || (isCheckableURL(destination) && parsed.Host == "") {
+		return &models.MarkdownWarning{Type: models.MarkdownWarningBrokenLink, Message: fmt.Sprintf("Link inválido: %s", destination)}, ""
+	}
+	if isCheckableURL(destination) {
+		return nil, destination
+	}
+	return nil, ""
+}
+
+func textWarnings(text string) []models.MarkdownWarning {
+	var warnings []models.MarkdownWarning
+	for _, marker := range unclosedMarkers {
+		if strings.Contains(text, marker) {
+			warnings = append(warnings, models.MarkdownWarning{
+				Type:    models.MarkdownWarningUnclosed,
+				Message: fmt.Sprintf("Marcação %q sem fechamento em %q", marker, truncateText(strings.TrimSpace(text), 60)),
+			})
+		}
+	}
+	if strings.Contains(text, "](") || strings.Contains(text, "] (") {
+		warnings = append(warnings, models.MarkdownWarning{
+			Type:    models.MarkdownWarningUnclosed,
+			Message: fmt.Sprintf("Link malformado em %q", truncateText(strings.TrimSpace(text), 60)),
+		})
+	}
+	return warnings
+}
+
+// markdownNodeText concatena o texto dos nós filhos
+func markdownNodeText(node ast.Node) string {
+	var builder strings.Builder
+	ast.WalkFunc(node, func(child ast.Node, entering bool) ast.WalkStatus {
+		if leaf := child.AsLeaf(); entering && leaf != nil {
+			builder.Write(leaf.Literal)
+		}
+		return ast.GoToNext
+	})
+	return builder.String()
+}
+
+func isMarkdownField(name string) bool {
+	for _, field := range MarkdownFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
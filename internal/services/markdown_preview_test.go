@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func warningTypes(field models.MarkdownPreviewField) []string {
+	var types []string
+	for _, warning := range field.Warnings {
+		types = append(types, warning.Type)
+	}
+	return types
+}
+
+func TestMarkdownPreview(t *testing.T) {
+	previewer := NewMarkdownPreviewer()
+	response, err := previewer.Preview(context.Background(), &models.MarkdownPreviewRequest{Fields: map[string]string{
+		"resumo":                 "Emissão da **2ª via** do [IPTU](https://www.rio.rj.gov.br/iptu)",
+		"descricao_completa":     "# Como solicitar\n\n#### Documentos\n\nLeve o **RG<script>x()</script>",
+		"instrucoes_solicitante": "Acesse [o portal]() e [pague](javascript:pay())",
+	}})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	resumo := response.Fields["resumo"]
+	if len(resumo.Warnings) != 0 {
+		t.Errorf("resumo não deveria ter avisos: %v", resumo.Warnings)
+	}
+	if !strings.Contains(resumo.HTML, "<strong>2ª via</strong>") || !strings.Contains(resumo.HTML, `target="_blank"`) {
+		t.Errorf("HTML inesperado: %s", resumo.HTML)
+	}
+
+	descricao := response.Fields["descricao_completa"]
+	if strings.Contains(descricao.HTML, "script") {
+		t.Errorf("script deveria ser removido: %s", descricao.HTML)
+	}
+	types := strings.Join(warningTypes(descricao), ",")
+	for _, want := range []string{models.MarkdownWarningSanitized, models.MarkdownWarningHeading, models.MarkdownWarningUnclosed} {
+		if !strings.Contains(types, want) {
+			t.Errorf("aviso %s esperado em descricao_completa: %s", want, types)
+		}
+	}
+	if strings.Count(types, models.MarkdownWarningHeading) != 2 {
+		t.Errorf("esperados avisos de nível 1 e de salto de nível: %v", descricao.Warnings)
+	}
+
+	instrucoes := warningTypes(response.Fields["instrucoes_solicitante"])
+	if strings.Count(strings.Join(instrucoes, ","), models.MarkdownWarningBrokenLink) != 2 {
+		t.Errorf("esperados dois links quebrados: %v", response.Fields["instrucoes_solicitante"].Warnings)
+	}
+
+	if response.TotalWarnings == 0 {
+		t.Error("total de avisos não contabilizado")
+	}
+}
+
+func TestMarkdownPreviewUnknownField(t *testing.T) {
+	_, err := NewMarkdownPreviewer().Preview(context.Background(), &models.MarkdownPreviewRequest{Fields: map[string]string{"nome_servico": "IPTU"}})
+	if !errors.Is(err, ErrUnknownMarkdownField) {
+		t.Errorf("esperado ErrUnknownMarkdownField, obtido %v", err)
+	}
+}
@@ -24,6 +24,7 @@ const (
 type MigrationService struct {
 	client         *typesense.Client
 	schemaRegistry *schemas.Registry
+	extraFields    *ExtraFieldSchemas
 }
 
 // NewMigrationService cria um novo serviço de migração
@@ -34,6 +35,12 @@ func NewMigrationService(client *typesense.Client, registry *schemas.Registry) *
 	}
 }
 
+// SetExtraFieldSchemas inclui os campos promovidos dos schemas de extra_fields nas collections
+// criadas pelas migrações. Para indexar um campo recém-promovido, migre para a versão atual.
+func (ms *MigrationService) SetExtraFieldSchemas(extraFields *ExtraFieldSchemas) {
+	ms.extraFields = extraFields
+}
+
 // GetStatus retorna o status atual da migração
 func (ms *MigrationService) GetStatus(ctx context.Context) (*models.MigrationStatusResponse, error) {
 	migration, err := ms.getActiveMigration(ctx)
@@ -84,6 +91,7 @@ func (ms *MigrationService) StartMigration(ctx context.Context, req *models.Migr
 	if err != nil {
 		return nil, fmt.Errorf("schema versão '%s' não encontrado: %v", req.SchemaVersion, err)
 	}
+	schema = withPromotedFields(schema, ms.extraFields.PromotedFields())
 
 	timestamp := time.Now().Format("20060102_150405")
 	backupCollectionName := fmt.Sprintf("%s%s", BackupCollectionPrefix, timestamp)
@@ -184,6 +192,28 @@ func (ms *MigrationService) StartMigration(ctx context.Context, req *models.Migr
 	}, nil
 }
 
+// withPromotedFields retorna uma cópia do schema com os campos promovidos do extra_fields que
+// ainda não fazem parte da versão
+func withPromotedFields(schema *schemas.SchemaDefinition, promoted []api.Field) *schemas.SchemaDefinition {
+	if len(promoted) == 0 {
+		return schema
+	}
+
+	existing := make(map[string]bool, len(schema.Fields))
+	for _, field := range schema.Fields {
+		existing[field.Name] = true
+	}
+
+	extended := *schema
+	extended.Fields = append(make([]api.Field, 0, len(schema.Fields)+len(promoted)), schema.Fields...)
+	for _, field := range promoted {
+		if !existing[field.Name] {
+			extended.Fields = append(extended.Fields, field)
+		}
+	}
+	return &extended
+}
+
 // executeMigration executa o processo completo de migração em background
 func (ms *MigrationService) executeMigration(ctx context.Context, migration *models.MigrationControl, schema *schemas.SchemaDefinition) {
 	defer func() {
@@ -284,14 +314,7 @@ func (ms *MigrationService) createBackup(ctx context.Context, migration *models.
 
 // createNewCollection cria a nova collection com o novo schema
 func (ms *MigrationService) createNewCollection(ctx context.Context, migration *models.MigrationControl, schema *schemas.SchemaDefinition) error {
-	newSchema := &api.CollectionSchema{
-		Name:                migration.TargetCollection,
-		Fields:              schema.Fields,
-		DefaultSortingField: stringPtr(schema.SortingField),
-		EnableNestedFields:  boolPtr(schema.NestedFields),
-	}
-
-	_, err := ms.client.Collections().Create(ctx, newSchema)
+	_, err := ms.client.Collections().Create(ctx, collectionFromSchema(migration.TargetCollection, schema))
 	if err != nil {
 		return fmt.Errorf("erro ao criar nova collection: %v", err)
 	}
@@ -299,6 +322,16 @@ func (ms *MigrationService) createNewCollection(ctx context.Context, migration *
 	return nil
 }
 
+// collectionFromSchema monta a collection com o nome informado a partir do schema registrado
+func collectionFromSchema(name string, schema *schemas.SchemaDefinition) *api.CollectionSchema {
+	return &api.CollectionSchema{
+		Name:                name,
+		Fields:              schema.Fields,
+		DefaultSortingField: stringPtr(schema.SortingField),
+		EnableNestedFields:  boolPtr(schema.NestedFields),
+	}
+}
+
 // migrateDocuments migra todos os documentos aplicando transformações se necessário
 func (ms *MigrationService) migrateDocuments(ctx context.Context, migration *models.MigrationControl, schema *schemas.SchemaDefinition) error {
 	page := 1
@@ -520,6 +553,16 @@ func (ms *MigrationService) GetCurrentSchemaVersion(ctx context.Context) string
 	return migration.SchemaVersion
 }
 
+// CurrentSchema retorna o schema registrado da versão atual da collection de serviços, com os
+// campos promovidos de extra_fields
+func (ms *MigrationService) CurrentSchema(ctx context.Context) (*schemas.SchemaDefinition, error) {
+	schema, err := ms.schemaRegistry.GetSchema(ms.GetCurrentSchemaVersion(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return withPromotedFields(schema, ms.extraFields.PromotedFields()), nil
+}
+
 // ========== Métodos auxiliares ==========
 
 func (ms *MigrationService) countDocuments(ctx context.Context, collection string) (int, error) {
@@ -812,7 +855,9 @@ func (ms *MigrationService) listMigrationHistory(ctx context.Context, page, perP
 		Found:      found,
 		OutOf:      found,
 		Page:       page,
+		PerPage:    perPage,
 		Migrations: migrations,
+		PageInfo:   models.NewPageInfo(page, perPage, found),
 	}, nil
 }
 
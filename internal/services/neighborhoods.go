@@ -0,0 +1,321 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+// NeighborhoodsCollection guarda a lista canônica de bairros
+const NeighborhoodsCollection = "bairros"
+
+var (
+	// ErrUnknownNeighborhood indica termo que não corresponde a bairro, região ou zona da lista
+	ErrUnknownNeighborhood = errors.New("bairro, região administrativa ou zona não encontrado")
+
+	// ErrInvalidNeighborhood indica bairro inválido na importação da lista
+	ErrInvalidNeighborhood = errors.New("lista de bairros inválida")
+)
+
+// Neighborhoods lista canônica de bairros da cidade, gerida pelos administradores. Resolve
+// termos livres — nome do bairro, alias, região administrativa ("RA Tijuca") ou zona ("zona
+// norte") — na lista de bairros usada para filtrar serviços com atendimento localizado.
+type Neighborhoods struct {
+	client *typesense.Client
+
+	mu      sync.RWMutex
+	bairros []models.Bairro
+	index   map[string]*models.BairroResolution // chave: termo normalizado
+}
+
+// NewNeighborhoods cria a lista vazia; os bairros persistidos são carregados por Reload
+func NewNeighborhoods(client *typesense.Client) *Neighborhoods {
+	return &Neighborhoods{client: client, index: map[string]*models.BairroResolution{}}
+}
+
+// List retorna os bairros ordenados por nome
+func (n *Neighborhoods) List() *models.BairroList {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	list := &models.BairroList{Total: len(n.bairros), Bairros: make([]models.Bairro, len(n.bairros))}
+	copy(list.Bairros, n.bairros)
+	return list
+}
+
+// Resolve retorna os bairros correspondentes ao termo. Nomes de bairro e aliases têm prioridade
+// sobre regiões administrativas, que têm prioridade sobre zonas. Sem lista carregada o termo é
+// usado como nome de bairro.
+func (n *Neighborhoods) Resolve(term string) (*models.BairroResolution, error) {
+	term = strings.TrimSpace(term)
+	if n == nil || n.empty() {
+		return &models.BairroResolution{Termo: term, Tipo: models.BairroMatchBairro, Nome: term, Bairros: []string{term}}, nil
+	}
+
+	n.mu.RLock()
+	match, ok := n.index[neighborhoodKey(term)]
+	n.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownNeighborhood, term)
+	}
+
+	resolution := *match
+	resolution.Termo = term
+	resolution.Bairros = append([]string(nil), match.Bairros...)
+	return &resolution, nil
+}
+
+// Canonicalize resolve os bairros informados em um serviço, expandindo regiões e zonas, e retorna
+// os nomes canônicos e as regiões administrativas correspondentes, ordenados e sem repetição
+func (n *Neighborhoods) Canonicalize(terms []string) (bairros []string, regioes []string, err error) {
+	if len(terms) == 0 {
+		return nil, nil, nil
+	}
+	if n == nil || n.empty() {
+		return uniqueSorted(terms), nil, nil
+	}
+
+	n.mu.RLock()
+	regiaoByBairro := make(map[string]string, len(n.bairros))
+	for _, bairro := range n.bairros {
+		regiaoByBairro[bairro.Nome] = bairro.RegiaoAdministrativa
+	}
+	n.mu.RUnlock()
+
+	for _, term := range terms {
+		resolution, err := n.Resolve(term)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, bairro := range resolution.Bairros {
+			bairros = append(bairros, bairro)
+			regioes = append(regioes, regiaoByBairro[bairro])
+		}
+	}
+	return uniqueSorted(bairros), uniqueSorted(regioes), nil
+}
+
+// Import substitui a lista canônica de bairros e a persiste para as demais réplicas
+func (n *Neighborhoods) Import(ctx context.Context, bairros []models.Bairro) (*models.BairroList, error) {
+	now := time.Now().Unix()
+	normalized := make([]models.Bairro, 0, len(bairros))
+	for _, bairro := range bairros {
+		bairro.Nome = strings.TrimSpace(bairro.Nome)
+		bairro.RegiaoAdministrativa = strings.TrimSpace(bairro.RegiaoAdministrativa)
+		bairro.Zona = strings.TrimSpace(bairro.Zona)
+		bairro.ID = strings.ReplaceAll(neighborhoodKey(bairro.Nome), " ", "-")
+		bairro.UpdatedAt = now
+		normalized = append(normalized, bairro)
+	}
+	if _, err := buildNeighborhoodIndex(normalized); err != nil {
+		return nil, err
+	}
+
+	if err := n.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+	docs := make([]interface{}, 0, len(normalized))
+	for _, bairro := range normalized {
+		docs = append(docs, bairro)
+	}
+	action := api.Upsert
+	if _, err := n.client.Collection(NeighborhoodsCollection).Documents().Import(ctx, docs, &api.ImportDocumentsParams{Action: &action}); err != nil {
+		return nil, fmt.Errorf("erro ao salvar bairros: %w", err)
+	}
+	// Bairros fora da nova lista são removidos
+	filter := fmt.Sprintf("updated_at:<%d", now)
+	if _, err := n.client.Collection(NeighborhoodsCollection).Documents().Delete(ctx, &api.DeleteDocumentsParams{FilterBy: pointer.String(filter)}); err != nil {
+		return nil, fmt.Errorf("erro ao remover bairros antigos: %w", err)
+	}
+
+	if err := n.set(normalized); err != nil {
+		return nil, err
+	}
+	return n.List(), nil
+}
+
+// Reload carrega a lista persistida, substituindo a em memória
+func (n *Neighborhoods) Reload(ctx context.Context) error {
+	var bairros []models.Bairro
+	for page := 1; ; page++ {
+		result, err := n.client.Collection(NeighborhoodsCollection).Documents().Search(ctx, &api.SearchCollectionParams{
+			Q:       pointer.String("*"),
+			Page:    pointer.Int(page),
+			PerPage: pointer.Int(250),
+		})
+		if err != nil {
+			if IsNotFoundError(err) {
+				break
+			}
+			return fmt.Errorf("erro ao carregar bairros: %w", err)
+		}
+		if result.Hits == nil || len(*result.Hits) == 0 {
+			break
+		}
+
+		for _, hit := range *result.Hits {
+			if hit.Document == nil {
+				continue
+			}
+			data, _ := json.Marshal(*hit.Document)
+			var bairro models.Bairro
+			if err := json.Unmarshal(data, &bairro); err != nil {
+				log.Printf("[Bairros] Bairro ignorado: %v", err)
+				continue
+			}
+			bairros = append(bairros, bairro)
+		}
+		if len(*result.Hits) < 250 {
+			break
+		}
+	}
+
+	return n.set(bairros)
+}
+
+// StartRefreshRoutine recarrega periodicamente a lista até o cancelamento de ctx, propagando
+// importações feitas em outras réplicas
+func (n *Neighborhoods) StartRefreshRoutine(ctx context.Context, interval time.Duration) {
+	startReloadLoop(ctx, "Bairros", interval, n.Reload)
+}
+
+func (n *Neighborhoods) set(bairros []models.Bairro) error {
+	index, err := buildNeighborhoodIndex(bairros)
+	if err != nil {
+		return err
+	}
+	sort.Slice(bairros, func(i, j int) bool { return bairros[i].Nome < bairros[j].Nome })
+
+	n.mu.Lock()
+	n.bairros = bairros
+	n.index = index
+	n.mu.Unlock()
+	return nil
+}
+
+func (n *Neighborhoods) empty() bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return len(n.bairros) == 0
+}
+
+// ensureCollection garante que a collection bairros existe
+func (n *Neighborhoods) ensureCollection(ctx context.Context) error {
+	_, err := n.client.Collection(NeighborhoodsCollection).Retrieve(ctx)
+	if err == nil {
+		return nil
+	}
+
+	schema := &api.CollectionSchema{
+		Name: NeighborhoodsCollection,
+		Fields: []api.Field{
+			{Name: "nome", Type: "string"},
+			{Name: "regiao_administrativa", Type: "string", Facet: pointer.True()},
+			{Name: "zona", Type: "string", Facet: pointer.True()},
+			{Name: "aliases", Type: "string[]", Optional: pointer.True()},
+			{Name: "updated_at", Type: "int64", Facet: pointer.False()},
+		},
+		DefaultSortingField: pointer.String("updated_at"),
+	}
+
+	if _, err := n.client.Collections().Create(ctx, schema); err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %w", NeighborhoodsCollection, err)
+	}
+	return nil
+}
+
+// buildNeighborhoodIndex indexa os bairros por nome e aliases, as regiões administrativas por
+// nome (também como "RA <nome>" e "região administrativa <nome>") e as zonas como "zona <nome>"
+func buildNeighborhoodIndex(bairros []models.Bairro) (map[string]*models.BairroResolution, error) {
+	index := make(map[string]*models.BairroResolution)
+	regioes := make(map[string]*models.BairroResolution)
+	zonas := make(map[string]*models.BairroResolution)
+
+	for _, bairro := range bairros {
+		if bairro.Nome == "" || bairro.RegiaoAdministrativa == "" || bairro.Zona == "" {
+			return nil, fmt.Errorf("%w: bairro %q sem nome, região administrativa ou zona", ErrInvalidNeighborhood, bairro.Nome)
+		}
+
+		for _, name := range append([]string{bairro.Nome}, bairro.Aliases...) {
+			key := neighborhoodKey(name)
+			if key == "" {
+				continue
+			}
+			if existing, ok := index[key]; ok && existing.Nome != bairro.Nome {
+				return nil, fmt.Errorf("%w: %q corresponde aos bairros %s e %s", ErrInvalidNeighborhood, name, existing.Nome, bairro.Nome)
+			}
+			index[key] = &models.BairroResolution{Tipo: models.BairroMatchBairro, Nome: bairro.Nome, Bairros: []string{bairro.Nome}}
+		}
+
+		regiao := regioes[neighborhoodKey(bairro.RegiaoAdministrativa)]
+		if regiao == nil {
+			regiao = &models.BairroResolution{Tipo: models.BairroMatchRegiao, Nome: bairro.RegiaoAdministrativa}
+			regioes[neighborhoodKey(bairro.RegiaoAdministrativa)] = regiao
+		}
+		regiao.Bairros = append(regiao.Bairros, bairro.Nome)
+
+		zonaKey := neighborhoodKey(strings.TrimPrefix(neighborhoodKey(bairro.Zona), "zona "))
+		zona := zonas[zonaKey]
+		if zona == nil {
+			zona = &models.BairroResolution{Tipo: models.BairroMatchZona, Nome: bairro.Zona}
+			zonas[zonaKey] = zona
+		}
+		zona.Bairros = append(zona.Bairros, bairro.Nome)
+	}
+
+	for key, regiao := range regioes {
+		sort.Strings(regiao.Bairros)
+		for _, alias := range []string{key, "ra " + key, "regiao administrativa " + key} {
+			if _, ok := index[alias]; !ok {
+				index[alias] = regiao
+			}
+		}
+	}
+	for key, zona := range zonas {
+		sort.Strings(zona.Bairros)
+		if _, ok := index["zona "+key]; !ok {
+			index["zona "+key] = zona
+		}
+	}
+	return index, nil
+}
+
+// neighborhoodKey normaliza o termo: sem acentos, minúsculo e com espaços simples
+func neighborhoodKey(term string) string {
+	return strings.Join(strings.Fields(utils.NormalizarCategoria(term)), " ")
+}
+
+// bairroFilter restringe a busca aos serviços atendidos nos bairros: os com atendimento em toda a
+// cidade (restrito_bairros false) e os localizados em algum dos bairros
+func bairroFilter(bairros []string) string {
+	quoted := make([]string, 0, len(bairros))
+	for _, bairro := range bairros {
+		quoted = append(quoted, "`"+strings.ReplaceAll(bairro, "`", "")+"`")
+	}
+	return fmt.Sprintf("(restrito_bairros:=false || bairros:=[%s])", strings.Join(quoted, ","))
+}
+
+func uniqueSorted(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" && !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
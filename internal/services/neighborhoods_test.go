@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func testNeighborhoods(t *testing.T) *Neighborhoods {
+	t.Helper()
+	n := NewNeighborhoods(nil)
+	err := n.set([]models.Bairro{
+		{Nome: "Tijuca", RegiaoAdministrativa: "Tijuca", Zona: "Norte"},
+		{Nome: "Praça da Bandeira", RegiaoAdministrativa: "Tijuca", Zona: "Norte"},
+		{Nome: "Méier", RegiaoAdministrativa: "Méier", Zona: "Norte"},
+		{Nome: "Jacarepaguá", RegiaoAdministrativa: "Jacarepaguá", Zona: "Zona Oeste", Aliases: []string{"JPA"}},
+	})
+	if err != nil {
+		t.Fatalf("erro ao carregar bairros: %v", err)
+	}
+	return n
+}
+
+func TestNeighborhoodsResolve(t *testing.T) {
+	n := testNeighborhoods(t)
+
+	tests := []struct {
+		term    string
+		tipo    string
+		bairros []string
+	}{
+		{"tijuca", models.BairroMatchBairro, []string{"Tijuca"}},
+		{"RA Tijuca", models.BairroMatchRegiao, []string{"Praça da Bandeira", "Tijuca"}},
+		{"  Zona   NORTE ", models.BairroMatchZona, []string{"Méier", "Praça da Bandeira", "Tijuca"}},
+		{"zona oeste", models.BairroMatchZona, []string{"Jacarepaguá"}},
+		{"jpa", models.BairroMatchBairro, []string{"Jacarepaguá"}},
+		{"meier", models.BairroMatchBairro, []string{"Méier"}},
+	}
+	for _, tt := range tests {
+		got, err := n.Resolve(tt.term)
+		if err != nil {
+			t.Errorf("Resolve(%q): %v", tt.term, err)
+			continue
+		}
+		if got.Tipo != tt.tipo || !reflect.DeepEqual(got.Bairros, tt.bairros) {
+			t.Errorf("Resolve(%q) = %s %v, esperado %s %v", tt.term, got.Tipo, got.Bairros, tt.tipo, tt.bairros)
+		}
+	}
+
+	if _, err := n.Resolve("Atlântida"); !errors.Is(err, ErrUnknownNeighborhood) {
+		t.Errorf("termo desconhecido deveria retornar ErrUnknownNeighborhood, retornou %v", err)
+	}
+}
+
+func TestNeighborhoodsCanonicalize(t *testing.T) {
+	n := testNeighborhoods(t)
+
+	bairros, regioes, err := n.Canonicalize([]string{"tijuca", "RA Tijuca", "JPA"})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if !reflect.DeepEqual(bairros, []string{"Jacarepaguá", "Praça da Bandeira", "Tijuca"}) {
+		t.Errorf("bairros = %v", bairros)
+	}
+	if !reflect.DeepEqual(regioes, []string{"Jacarepaguá", "Tijuca"}) {
+		t.Errorf("regiões = %v", regioes)
+	}
+
+	// Sem lista carregada os bairros são gravados como informados
+	var empty *Neighborhoods
+	if bairros, _, _ := empty.Canonicalize([]string{"Tijuca"}); !reflect.DeepEqual(bairros, []string{"Tijuca"}) {
+		t.Errorf("bairros sem lista = %v", bairros)
+	}
+}
+
+func TestBuildNeighborhoodIndexRejectsDuplicateAliases(t *testing.T) {
+	_, err := buildNeighborhoodIndex([]models.Bairro{
+		{Nome: "Tijuca", RegiaoAdministrativa: "Tijuca", Zona: "Norte", Aliases: []string{"Grande Tijuca"}},
+		{Nome: "Alto da Boa Vista", RegiaoAdministrativa: "Tijuca", Zona: "Norte", Aliases: []string{"grande tijuca"}},
+	})
+	if !errors.Is(err, ErrInvalidNeighborhood) {
+		t.Errorf("alias repetido deveria ser rejeitado, retornou %v", err)
+	}
+}
+
+func TestBairroFilter(t *testing.T) {
+	got := bairroFilter([]string{"Tijuca", "Praça da Bandeira"})
+	want := "(restrito_bairros:=false || bairros:=[`Tijuca`,`Praça da Bandeira`])"
+	if got != want {
+		t.Errorf("bairroFilter = %s", got)
+	}
+}
@@ -0,0 +1,412 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prefeitura-rio/app-busca-search/internal/jobs"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+// OpsAuditCollection registra as ações de recuperação executadas pelo plantão
+const OpsAuditCollection = "_ops_audit"
+
+// opsJobRestartGrace quanto restart-job aguarda a execução cancelada terminar
+const opsJobRestartGrace = 30 * time.Second
+
+var (
+	// ErrOpsUnknownAction indica ação de recuperação inexistente
+	ErrOpsUnknownAction = errors.New("ação de operação desconhecida")
+
+	// ErrOpsInvalidTarget indica alvo ausente ou não aceito pela ação
+	ErrOpsInvalidTarget = errors.New("alvo inválido para a ação")
+
+	// ErrOpsNotNeeded indica que o estado atual dispensa a ação (ex.: a collection existe)
+	ErrOpsNotNeeded = errors.New("ação desnecessária no estado atual")
+
+	// ErrOpsMigrationActive indica ação sobre a collection de serviços durante uma migração
+	ErrOpsMigrationActive = errors.New("migração em andamento; aguarde sua conclusão")
+
+	// ErrOpsConfirmationRequired indica ação sem token de confirmação válido
+	ErrOpsConfirmationRequired = errors.New("ação de operação exige confirmação")
+)
+
+// OpsConfirmationError carrega o plano da ação e o token que a confirma. Assim como nos aliases,
+// o token não é segredo: identifica a ação sobre o estado atual e evita execuções acidentais.
+type OpsConfirmationError struct {
+	Plan models.OpsPlan
+}
+
+func (e *OpsConfirmationError) Error() string {
+	return fmt.Sprintf("%s: %s; reenvie com confirmation_token", ErrOpsConfirmationRequired, e.Plan.Description)
+}
+
+func (e *OpsConfirmationError) Unwrap() error {
+	return ErrOpsConfirmationRequired
+}
+
+// OpsService reúne as ações de recuperação mais comuns do plantão (recriar a collection de
+// serviços, voltar o alias para o backup, limpar caches e reiniciar jobs travados), cada uma
+// confirmada por token e registrada na auditoria
+type OpsService struct {
+	client     *typesense.Client
+	migrations *MigrationService
+	runner     *jobs.Runner
+	bus        *EventBus
+}
+
+// NewOpsService cria o serviço de operações
+func NewOpsService(client *typesense.Client, migrations *MigrationService, runner *jobs.Runner, bus *EventBus) *OpsService {
+	return &OpsService{client: client, migrations: migrations, runner: runner, bus: bus}
+}
+
+// opsPlan plano da ação com o estado que o token confirma e a execução
+type opsPlan struct {
+	models.OpsPlan
+	run func(ctx context.Context, userName string) (*models.OpsResult, error)
+}
+
+// Execute executa a ação se o token confirmar o plano atual; sem token (ou com token de um
+// estado anterior) retorna OpsConfirmationError com o plano. Execuções são auditadas.
+func (o *OpsService) Execute(ctx context.Context, action models.OpsAction, request *models.OpsRequest, userName string) (*models.OpsResult, error) {
+	plan, err := o.plan(ctx, action, strings.TrimSpace(request.Target))
+	if err != nil {
+		return nil, err
+	}
+	if request.ConfirmationToken != plan.ConfirmationToken {
+		return nil, &OpsConfirmationError{Plan: plan.OpsPlan}
+	}
+
+	result, err := plan.run(ctx, userName)
+	o.audit(ctx, plan.OpsPlan, userName, result, err)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Action = plan.Action
+	result.Target = plan.Target
+	result.ExecutedBy = userName
+	result.ExecutedAt = time.Now().Unix()
+	return result, nil
+}
+
+func (o *OpsService) plan(ctx context.Context, action models.OpsAction, target string) (*opsPlan, error) {
+	var plan *opsPlan
+	var state string
+	var err error
+	switch action {
+	case models.OpsRecreateCollection:
+		plan, state, err = o.planRecreateCollection(ctx, target)
+	case models.OpsRestoreBackupAlias:
+		plan, state, err = o.planRestoreBackupAlias(ctx, target)
+	case models.OpsClearCaches:
+		plan, state, err = o.planClearCaches()
+	case models.OpsRestartJob:
+		plan, state, err = o.planRestartJob(ctx, target)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrOpsUnknownAction, action)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Action = action
+	plan.ConfirmationToken = opsConfirmationToken(action, plan.Target, state)
+	return plan, nil
+}
+
+// planRecreateCollection recria, vazia, a collection de serviços ausente (o alias de serviços
+// aponta para ela, ou ela própria) com o schema registrado da versão atual
+func (o *OpsService) planRecreateCollection(ctx context.Context, target string) (*opsPlan, string, error) {
+	if target == "" {
+		target = PrefRioServicesCollection
+	}
+	if err := o.checkNoMigration(ctx); err != nil {
+		return nil, "", err
+	}
+
+	name := target
+	alias, err := o.client.Alias(target).Retrieve(ctx)
+	switch {
+	case err == nil:
+		name = alias.CollectionName
+	case !IsNotFoundError(err):
+		return nil, "", fmt.Errorf("erro ao consultar alias: %w", err)
+	}
+	if !strings.HasPrefix(name, PrefRioServicesCollection) {
+		return nil, "", fmt.Errorf("%w: apenas a collection de serviços tem schema registrado (%s)", ErrOpsInvalidTarget, name)
+	}
+
+	if _, err := o.client.Collection(name).Retrieve(ctx); err == nil {
+		return nil, "", fmt.Errorf("%w: collection %s existe", ErrOpsNotNeeded, name)
+	} else if !IsNotFoundError(err) {
+		return nil, "", fmt.Errorf("erro ao consultar collection: %w", err)
+	}
+
+	schema, err := o.migrations.CurrentSchema(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao carregar schema atual: %w", err)
+	}
+
+	plan := &opsPlan{
+		OpsPlan: models.OpsPlan{
+			Target:      target,
+			Description: fmt.Sprintf("cria a collection %s vazia com o schema %s (%d campos); os documentos precisam ser restaurados depois", name, schema.Version, len(schema.Fields)),
+		},
+		run: func(ctx context.Context, _ string) (*models.OpsResult, error) {
+			if _, err := o.client.Collections().Create(ctx, collectionFromSchema(name, schema)); err != nil {
+				return nil, fmt.Errorf("erro ao criar collection %s: %w", name, err)
+			}
+			return &models.OpsResult{Detail: fmt.Sprintf("collection %s criada com o schema %s", name, schema.Version)}, nil
+		},
+	}
+	return plan, name + "@" + schema.Version, nil
+}
+
+// planRestoreBackupAlias aponta o alias de serviços para o backup mais recente
+func (o *OpsService) planRestoreBackupAlias(ctx context.Context, target string) (*opsPlan, string, error) {
+	if target != "" && target != PrefRioServicesCollection {
+		return nil, "", fmt.Errorf("%w: backups existem apenas para o alias %s", ErrOpsInvalidTarget, PrefRioServicesCollection)
+	}
+	if err := o.checkNoMigration(ctx); err != nil {
+		return nil, "", err
+	}
+
+	collections, err := o.client.Collections().Retrieve(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao listar collections: %w", err)
+	}
+	// O sufixo dos backups é o horário (20060102_150405): o maior nome é o mais recente
+	var latest string
+	for _, coll := range collections {
+		if strings.HasPrefix(coll.Name, BackupCollectionPrefix) && coll.Name > latest {
+			latest = coll.Name
+		}
+	}
+	if latest == "" {
+		return nil, "", fmt.Errorf("%w: nenhum backup encontrado", ErrOpsNotNeeded)
+	}
+
+	var current string
+	alias, err := o.client.Alias(PrefRioServicesCollection).Retrieve(ctx)
+	switch {
+	case err == nil:
+		current = alias.CollectionName
+	case !IsNotFoundError(err):
+		return nil, "", fmt.Errorf("erro ao consultar alias: %w", err)
+	}
+	if current == latest {
+		return nil, "", fmt.Errorf("%w: o alias já aponta para %s", ErrOpsNotNeeded, latest)
+	}
+
+	plan := &opsPlan{
+		OpsPlan: models.OpsPlan{
+			Target:      PrefRioServicesCollection,
+			Description: fmt.Sprintf("aponta o alias %s de %q para o backup %s e limpa os caches", PrefRioServicesCollection, current, latest),
+		},
+		run: func(ctx context.Context, _ string) (*models.OpsResult, error) {
+			if _, err := o.client.Aliases().Upsert(ctx, PrefRioServicesCollection, &api.CollectionAliasSchema{CollectionName: latest}); err != nil {
+				return nil, fmt.Errorf("erro ao reapontar alias: %w", err)
+			}
+			o.invalidateCaches(ctx)
+			return &models.OpsResult{Detail: fmt.Sprintf("alias %s aponta para %s (antes: %q)", PrefRioServicesCollection, latest, current)}, nil
+		},
+	}
+	return plan, current + "->" + latest, nil
+}
+
+// planClearCaches descarta os caches de busca, de respostas e de análise em todas as réplicas
+func (o *OpsService) planClearCaches() (*opsPlan, string, error) {
+	plan := &opsPlan{
+		OpsPlan: models.OpsPlan{
+			Description: "descarta os caches de busca, de respostas e o catálogo em todas as réplicas",
+		},
+		run: func(ctx context.Context, _ string) (*models.OpsResult, error) {
+			o.invalidateCaches(ctx)
+			return &models.OpsResult{Detail: "caches invalidados"}, nil
+		},
+	}
+	return plan, "", nil
+}
+
+// planRestartJob cancela a execução do job em andamento nesta réplica e o executa novamente
+func (o *OpsService) planRestartJob(ctx context.Context, target string) (*opsPlan, string, error) {
+	if target == "" {
+		return nil, "", fmt.Errorf("%w: informe o nome do job", ErrOpsInvalidTarget)
+	}
+	runs, err := o.runner.Runs(ctx, target, 1)
+	if err != nil {
+		if errors.Is(err, jobs.ErrJobNotFound) {
+			return nil, "", fmt.Errorf("%w: %v", ErrOpsInvalidTarget, err)
+		}
+		return nil, "", err
+	}
+
+	description := fmt.Sprintf("cancela a execução de %s em andamento nesta réplica e o executa novamente", target)
+	var state string
+	if len(runs.Runs) > 0 {
+		last := runs.Runs[0]
+		state = last.ID
+		description += fmt.Sprintf(" (última execução: %s, %s, iniciada em %s)", last.ID, last.Status, time.Unix(last.StartedAt, 0).Format(time.RFC3339))
+	}
+
+	plan := &opsPlan{
+		OpsPlan: models.OpsPlan{Target: target, Description: description},
+		run: func(ctx context.Context, userName string) (*models.OpsResult, error) {
+			run, err := o.runner.Restart(ctx, target, userName, opsJobRestartGrace)
+			if err != nil {
+				return nil, err
+			}
+			return &models.OpsResult{Detail: fmt.Sprintf("job %s reiniciado", target), Job: run}, nil
+		},
+	}
+	return plan, state, nil
+}
+
+// checkNoMigration recusa alterar a collection ou o alias de serviços durante uma migração
+func (o *OpsService) checkNoMigration(ctx context.Context) error {
+	status, err := o.migrations.GetStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao consultar migração: %w", err)
+	}
+	if status.IsLocked {
+		return ErrOpsMigrationActive
+	}
+	return nil
+}
+
+// invalidateCaches publica uma escrita na collection de serviços: os caches assinantes do
+// EventBus são descartados e o relay propaga a invalidação às demais réplicas
+func (o *OpsService) invalidateCaches(ctx context.Context) {
+	if o.bus == nil {
+		return
+	}
+	o.bus.Publish(ctx, DocumentEvent{Type: DocumentUpdated, Collection: PrefRioServicesCollection})
+}
+
+// Audit retorna as últimas ações registradas
+func (o *OpsService) Audit(ctx context.Context, limit int) (*models.OpsAuditList, error) {
+	if err := o.ensureAuditCollection(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := o.client.Collection(OpsAuditCollection).Documents().Search(ctx, &api.SearchCollectionParams{
+		Q:       pointer.String("*"),
+		SortBy:  pointer.String("created_at:desc"),
+		PerPage: pointer.Int(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar auditoria de operações: %w", err)
+	}
+
+	list := &models.OpsAuditList{Entries: []models.OpsAuditEntry{}}
+	if result.Hits != nil {
+		for _, hit := range *result.Hits {
+			if hit.Document != nil {
+				list.Entries = append(list.Entries, opsAuditFromDocument(*hit.Document))
+			}
+		}
+	}
+	list.Total = len(list.Entries)
+	return list, nil
+}
+
+// audit registra a ação no log e na collection de auditoria. Falhas ao gravar são apenas
+// logadas: a ação já foi executada.
+func (o *OpsService) audit(ctx context.Context, plan models.OpsPlan, userName string, result *models.OpsResult, runErr error) {
+	entry := models.OpsAuditEntry{
+		ID:        uuid.New().String(),
+		Action:    plan.Action,
+		Target:    plan.Target,
+		User:      userName,
+		Status:    models.OpsStatusSuccess,
+		CreatedAt: time.Now().Unix(),
+	}
+	if result != nil {
+		entry.Detail = result.Detail
+	}
+	if runErr != nil {
+		entry.Status = models.OpsStatusFailed
+		entry.Error = runErr.Error()
+	}
+	log.Printf("[Ops] %s executou %s (alvo %q): %s %s%s", userName, plan.Action, plan.Target, entry.Status, entry.Detail, entry.Error)
+
+	// A gravação não depende da requisição que disparou a ação
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	if err := o.ensureAuditCollection(ctx); err != nil {
+		log.Printf("[Ops] %v", err)
+		return
+	}
+	doc := map[string]interface{}{
+		"id":         entry.ID,
+		"action":     string(entry.Action),
+		"target":     entry.Target,
+		"user":       entry.User,
+		"status":     entry.Status,
+		"detail":     entry.Detail,
+		"error":      entry.Error,
+		"created_at": entry.CreatedAt,
+	}
+	if _, err := o.client.Collection(OpsAuditCollection).Documents().Create(ctx, doc, &api.DocumentIndexParameters{}); err != nil {
+		log.Printf("[Ops] erro ao registrar auditoria: %v", err)
+	}
+}
+
+// ensureAuditCollection garante que a collection _ops_audit existe
+func (o *OpsService) ensureAuditCollection(ctx context.Context) error {
+	_, err := o.client.Collection(OpsAuditCollection).Retrieve(ctx)
+	if err == nil {
+		return nil
+	}
+
+	schema := &api.CollectionSchema{
+		Name: OpsAuditCollection,
+		Fields: []api.Field{
+			{Name: "action", Type: "string", Facet: pointer.True()},
+			{Name: "target", Type: "string", Facet: pointer.True(), Optional: pointer.True()},
+			{Name: "user", Type: "string", Facet: pointer.True()},
+			{Name: "status", Type: "string", Facet: pointer.True()},
+			{Name: "detail", Type: "string", Optional: pointer.True()},
+			{Name: "error", Type: "string", Optional: pointer.True()},
+			{Name: "created_at", Type: "int64", Facet: pointer.False()},
+		},
+		DefaultSortingField: pointer.String("created_at"),
+	}
+	if _, err := o.client.Collections().Create(ctx, schema); err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %w", OpsAuditCollection, err)
+	}
+	return nil
+}
+
+func opsAuditFromDocument(doc map[string]interface{}) models.OpsAuditEntry {
+	entry := models.OpsAuditEntry{
+		ID:     getString(doc, "id"),
+		Action: models.OpsAction(getString(doc, "action")),
+		Target: getString(doc, "target"),
+		User:   getString(doc, "user"),
+		Status: getString(doc, "status"),
+		Detail: getString(doc, "detail"),
+		Error:  getString(doc, "error"),
+	}
+	if value, ok := doc["created_at"].(float64); ok {
+		entry.CreatedAt = int64(value)
+	}
+	return entry
+}
+
+// opsConfirmationToken identifica a ação sobre o alvo no estado atual
+func opsConfirmationToken(action models.OpsAction, target, state string) string {
+	sum := sha256.Sum256([]byte(string(action) + "\x00" + target + "\x00" + state))
+	return hex.EncodeToString(sum[:6])
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestOpsExecuteRequiresConfirmation(t *testing.T) {
+	ops := NewOpsService(nil, nil, nil, nil)
+
+	_, err := ops.Execute(context.Background(), "drop-everything", &models.OpsRequest{}, "plantao")
+	if !errors.Is(err, ErrOpsUnknownAction) {
+		t.Fatalf("esperado ErrOpsUnknownAction, obtido %v", err)
+	}
+
+	_, err = ops.Execute(context.Background(), models.OpsClearCaches, &models.OpsRequest{ConfirmationToken: "errado"}, "plantao")
+	var confirmation *OpsConfirmationError
+	if !errors.As(err, &confirmation) || !errors.Is(err, ErrOpsConfirmationRequired) {
+		t.Fatalf("esperado OpsConfirmationError, obtido %v", err)
+	}
+	if confirmation.Plan.Action != models.OpsClearCaches || confirmation.Plan.ConfirmationToken == "" {
+		t.Errorf("plano incompleto: %+v", confirmation.Plan)
+	}
+}
+
+func TestOpsConfirmationTokenDependsOnState(t *testing.T) {
+	token := opsConfirmationToken(models.OpsRestoreBackupAlias, PrefRioServicesCollection, "backup_1")
+	if token != opsConfirmationToken(models.OpsRestoreBackupAlias, PrefRioServicesCollection, "backup_1") {
+		t.Error("token deve ser determinístico para o mesmo estado")
+	}
+	if token == opsConfirmationToken(models.OpsRestoreBackupAlias, PrefRioServicesCollection, "backup_2") {
+		t.Error("token deve mudar quando o estado muda")
+	}
+	if token == opsConfirmationToken(models.OpsRecreateCollection, PrefRioServicesCollection, "backup_1") {
+		t.Error("token de uma ação não deve confirmar outra")
+	}
+}
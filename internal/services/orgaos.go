@@ -0,0 +1,323 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+// OrgaosCollection guarda o diretório de órgãos
+const OrgaosCollection = "orgaos"
+
+// orgaoSiglaPattern siglas como SMS, SME, COMLURB ou SMTR/CET-RIO
+var orgaoSiglaPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9/-]{0,29}$`)
+
+var (
+	// ErrOrgaoNotFound indica sigla sem órgão cadastrado
+	ErrOrgaoNotFound = errors.New("órgão não encontrado")
+
+	// ErrInvalidOrgao indica órgão inválido no cadastro
+	ErrInvalidOrgao = errors.New("órgão inválido")
+
+	// ErrUnknownOrgaoGestor indica orgao_gestor de serviço fora do diretório de órgãos
+	ErrUnknownOrgaoGestor = errors.New("orgao_gestor não cadastrado no diretório de órgãos")
+)
+
+// Orgaos diretório de órgãos da prefeitura, gerido pelos administradores. Com o diretório
+// preenchido, o orgao_gestor dos serviços é validado ao salvar contra a sigla ou o nome dos
+// órgãos cadastrados.
+type Orgaos struct {
+	client *typesense.Client
+
+	mu     sync.RWMutex
+	orgaos map[string]models.Orgao // chave: sigla
+	index  map[string]string       // sigla ou nome normalizado → sigla
+}
+
+// NewOrgaos cria o diretório vazio; os órgãos persistidos são carregados por Reload
+func NewOrgaos(client *typesense.Client) *Orgaos {
+	return &Orgaos{client: client, orgaos: map[string]models.Orgao{}, index: map[string]string{}}
+}
+
+// List retorna os órgãos ordenados por sigla
+func (o *Orgaos) List() *models.OrgaoList {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	list := &models.OrgaoList{Orgaos: make([]models.Orgao, 0, len(o.orgaos))}
+	for _, orgao := range o.orgaos {
+		list.Orgaos = append(list.Orgaos, orgao)
+	}
+	sort.Slice(list.Orgaos, func(i, j int) bool { return list.Orgaos[i].Sigla < list.Orgaos[j].Sigla })
+	list.Total = len(list.Orgaos)
+	return list
+}
+
+// Get retorna o órgão da sigla (sem diferenciar maiúsculas)
+func (o *Orgaos) Get(sigla string) (*models.Orgao, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	orgao, ok := o.orgaos[normalizeSigla(sigla)]
+	if !ok {
+		return nil, ErrOrgaoNotFound
+	}
+	return &orgao, nil
+}
+
+// Validate verifica se cada orgao_gestor corresponde à sigla ou ao nome de um órgão
+// cadastrado, sem considerar acentos nem maiúsculas. Sem diretório carregado aceita qualquer
+// valor.
+func (o *Orgaos) Validate(orgaoGestor []string) error {
+	if o == nil {
+		return nil
+	}
+
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if len(o.orgaos) == 0 {
+		return nil
+	}
+
+	var unknown []string
+	for _, value := range orgaoGestor {
+		if _, ok := o.index[orgaoKey(value)]; !ok {
+			unknown = append(unknown, value)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownOrgaoGestor, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// Save cria ou substitui o órgão da sigla e o persiste para as demais réplicas
+func (o *Orgaos) Save(ctx context.Context, sigla string, request *models.OrgaoRequest, userName string) (*models.Orgao, error) {
+	orgao := models.Orgao{
+		Sigla:     normalizeSigla(sigla),
+		Nome:      strings.TrimSpace(request.Nome),
+		Site:      strings.TrimSpace(request.Site),
+		Contato:   strings.TrimSpace(request.Contato),
+		UpdatedBy: userName,
+		UpdatedAt: time.Now().Unix(),
+	}
+	if err := validateOrgao(orgao); err != nil {
+		return nil, err
+	}
+
+	// Sigla e nome identificam o órgão em orgao_gestor e não podem coincidir com os de outro
+	o.mu.RLock()
+	for _, key := range []string{orgaoKey(orgao.Sigla), orgaoKey(orgao.Nome)} {
+		if sigla, ok := o.index[key]; ok && sigla != orgao.Sigla {
+			o.mu.RUnlock()
+			return nil, fmt.Errorf("%w: %s já identifica o órgão %s", ErrInvalidOrgao, key, sigla)
+		}
+	}
+	o.mu.RUnlock()
+
+	if err := o.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+	doc := map[string]interface{}{
+		"id":         orgaoDocumentID(orgao.Sigla),
+		"sigla":      orgao.Sigla,
+		"nome":       orgao.Nome,
+		"site":       orgao.Site,
+		"contato":    orgao.Contato,
+		"updated_by": orgao.UpdatedBy,
+		"updated_at": orgao.UpdatedAt,
+	}
+	if _, err := o.client.Collection(OrgaosCollection).Documents().Upsert(ctx, doc, &api.DocumentIndexParameters{}); err != nil {
+		return nil, fmt.Errorf("erro ao salvar órgão: %w", err)
+	}
+
+	o.mu.Lock()
+	o.orgaos[orgao.Sigla] = orgao
+	o.index = buildOrgaoIndex(o.orgaos)
+	o.mu.Unlock()
+	return &orgao, nil
+}
+
+// Delete remove o órgão. Serviços que o citam em orgao_gestor não são alterados, mas passam a
+// falhar na validação ao serem salvos.
+func (o *Orgaos) Delete(ctx context.Context, sigla string) error {
+	sigla = normalizeSigla(sigla)
+	o.mu.RLock()
+	_, ok := o.orgaos[sigla]
+	o.mu.RUnlock()
+	if !ok {
+		return ErrOrgaoNotFound
+	}
+
+	if _, err := o.client.Collection(OrgaosCollection).Document(orgaoDocumentID(sigla)).Delete(ctx); err != nil && !IsNotFoundError(err) {
+		return fmt.Errorf("erro ao remover órgão: %w", err)
+	}
+
+	o.mu.Lock()
+	delete(o.orgaos, sigla)
+	o.index = buildOrgaoIndex(o.orgaos)
+	o.mu.Unlock()
+	return nil
+}
+
+// Services lista os serviços publicados do órgão: os que citam sua sigla ou seu nome em
+// orgao_gestor, dos mais recentemente atualizados aos mais antigos
+func (o *Orgaos) Services(ctx context.Context, sigla string, page, perPage int) (*models.OrgaoServicesResponse, error) {
+	orgao, err := o.Get(sigla)
+	if err != nil {
+		return nil, err
+	}
+
+	filterBy := fmt.Sprintf("status:=1 && %s && orgao_gestor:=[`%s`,`%s`]", availabilityFilter(time.Now().Unix()), orgao.Sigla, orgao.Nome)
+	result, err := o.client.Collection(CollectionName).Documents().Search(ctx, &api.SearchCollectionParams{
+		Q:             pointer.String("*"),
+		FilterBy:      pointer.String(tenant.ScopeFilter(ctx, filterBy)),
+		SortBy:        pointer.String("last_update:desc"),
+		Page:          pointer.Int(page),
+		PerPage:       pointer.Int(perPage),
+		ExcludeFields: pointer.String("embedding,search_content"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar serviços do órgão: %w", err)
+	}
+
+	response := &models.OrgaoServicesResponse{
+		Orgao:    *orgao,
+		Services: servicesFromHits(result),
+		Page:     page,
+		PerPage:  perPage,
+	}
+	if result.Found != nil {
+		response.Found = *result.Found
+	}
+	response.PageInfo = models.NewPageInfo(page, perPage, response.Found)
+	return response, nil
+}
+
+// Reload carrega os órgãos persistidos, substituindo os em memória
+func (o *Orgaos) Reload(ctx context.Context) error {
+	orgaos := make(map[string]models.Orgao)
+	for page := 1; ; page++ {
+		result, err := o.client.Collection(OrgaosCollection).Documents().Search(ctx, &api.SearchCollectionParams{
+			Q:       pointer.String("*"),
+			Page:    pointer.Int(page),
+			PerPage: pointer.Int(250),
+		})
+		if err != nil {
+			if IsNotFoundError(err) {
+				break
+			}
+			return fmt.Errorf("erro ao carregar órgãos: %w", err)
+		}
+		if result.Hits == nil || len(*result.Hits) == 0 {
+			break
+		}
+
+		for _, hit := range *result.Hits {
+			if hit.Document == nil {
+				continue
+			}
+			doc := *hit.Document
+			orgao := models.Orgao{
+				Sigla:     getString(doc, "sigla"),
+				Nome:      getString(doc, "nome"),
+				Site:      getString(doc, "site"),
+				Contato:   getString(doc, "contato"),
+				UpdatedBy: getString(doc, "updated_by"),
+				UpdatedAt: getInt64(doc, "updated_at"),
+			}
+			orgaos[orgao.Sigla] = orgao
+		}
+		if len(*result.Hits) < 250 {
+			break
+		}
+	}
+
+	o.mu.Lock()
+	o.orgaos = orgaos
+	o.index = buildOrgaoIndex(orgaos)
+	o.mu.Unlock()
+	return nil
+}
+
+// StartRefreshRoutine recarrega periodicamente os órgãos até o cancelamento de ctx,
+// propagando alterações feitas em outras réplicas
+func (o *Orgaos) StartRefreshRoutine(ctx context.Context, interval time.Duration) {
+	startReloadLoop(ctx, "Orgaos", interval, o.Reload)
+}
+
+// ensureCollection garante que a collection orgaos existe
+func (o *Orgaos) ensureCollection(ctx context.Context) error {
+	_, err := o.client.Collection(OrgaosCollection).Retrieve(ctx)
+	if err == nil {
+		return nil
+	}
+
+	schema := &api.CollectionSchema{
+		Name: OrgaosCollection,
+		Fields: []api.Field{
+			{Name: "sigla", Type: "string"},
+			{Name: "nome", Type: "string"},
+			{Name: "site", Type: "string", Index: pointer.False(), Optional: pointer.True()},
+			{Name: "contato", Type: "string", Index: pointer.False(), Optional: pointer.True()},
+			{Name: "updated_by", Type: "string", Index: pointer.False(), Optional: pointer.True()},
+			{Name: "updated_at", Type: "int64", Facet: pointer.False()},
+		},
+		DefaultSortingField: pointer.String("updated_at"),
+	}
+
+	if _, err := o.client.Collections().Create(ctx, schema); err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %w", OrgaosCollection, err)
+	}
+	return nil
+}
+
+// validateOrgao valida a sigla, o nome e o site do órgão
+func validateOrgao(orgao models.Orgao) error {
+	if !orgaoSiglaPattern.MatchString(orgao.Sigla) {
+		return fmt.Errorf("%w: sigla %q deve ter até 30 letras, dígitos, / ou -", ErrInvalidOrgao, orgao.Sigla)
+	}
+	if orgao.Nome == "" || strings.Contains(orgao.Nome, "`") {
+		return fmt.Errorf("%w: nome obrigatório e sem crase", ErrInvalidOrgao)
+	}
+	if orgao.Site != "" && !utils.IsSafeURL(orgao.Site) {
+		return fmt.Errorf("%w: esquema do site não permitido", ErrInvalidOrgao)
+	}
+	return nil
+}
+
+// buildOrgaoIndex indexa os órgãos pela sigla e pelo nome normalizados
+func buildOrgaoIndex(orgaos map[string]models.Orgao) map[string]string {
+	index := make(map[string]string, 2*len(orgaos))
+	for sigla, orgao := range orgaos {
+		index[orgaoKey(orgao.Sigla)] = sigla
+		index[orgaoKey(orgao.Nome)] = sigla
+	}
+	return index
+}
+
+func normalizeSigla(sigla string) string {
+	return strings.ToUpper(strings.TrimSpace(sigla))
+}
+
+// orgaoKey compara siglas e nomes sem acentos, maiúsculas nem espaços repetidos
+func orgaoKey(value string) string {
+	return strings.Join(strings.Fields(utils.NormalizarCategoria(value)), " ")
+}
+
+// orgaoDocumentID ID do documento do órgão: a sigla em minúsculas, com / trocada por _
+func orgaoDocumentID(sigla string) string {
+	return strings.ReplaceAll(strings.ToLower(sigla), "/", "_")
+}
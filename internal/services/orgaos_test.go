@@ -0,0 +1,59 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestOrgaosValidate(t *testing.T) {
+	o := NewOrgaos(nil)
+	if err := o.Validate([]string{"Qualquer órgão"}); err != nil {
+		t.Fatalf("diretório vazio deve aceitar qualquer valor: %v", err)
+	}
+
+	o.orgaos = map[string]models.Orgao{
+		"SMS":          {Sigla: "SMS", Nome: "Secretaria Municipal de Saúde"},
+		"SMTR/CET-RIO": {Sigla: "SMTR/CET-RIO", Nome: "Companhia de Engenharia de Tráfego"},
+	}
+	o.index = buildOrgaoIndex(o.orgaos)
+
+	if err := o.Validate([]string{"sms", "secretaria municipal de  saude", "SMTR/CET-RIO"}); err != nil {
+		t.Errorf("erro inesperado: %v", err)
+	}
+	err := o.Validate([]string{"SMS", "SME", "Comlurb"})
+	if !errors.Is(err, ErrUnknownOrgaoGestor) || err.Error() != ErrUnknownOrgaoGestor.Error()+": SME, Comlurb" {
+		t.Errorf("erro inesperado: %v", err)
+	}
+
+	if orgao, err := o.Get(" sms "); err != nil || orgao.Nome != "Secretaria Municipal de Saúde" {
+		t.Errorf("Get: %+v, %v", orgao, err)
+	}
+	if _, err := o.Get("SME"); !errors.Is(err, ErrOrgaoNotFound) {
+		t.Errorf("esperado ErrOrgaoNotFound, obtido %v", err)
+	}
+}
+
+func TestValidateOrgao(t *testing.T) {
+	tests := []struct {
+		orgao models.Orgao
+		valid bool
+	}{
+		{models.Orgao{Sigla: "SMS", Nome: "Secretaria Municipal de Saúde", Site: "https://saude.prefeitura.rio"}, true},
+		{models.Orgao{Sigla: "SMTR/CET-RIO", Nome: "CET-Rio"}, true},
+		{models.Orgao{Sigla: "SM S", Nome: "Saúde"}, false},
+		{models.Orgao{Sigla: "", Nome: "Saúde"}, false},
+		{models.Orgao{Sigla: "SMS", Nome: "Sa`úde"}, false},
+		{models.Orgao{Sigla: "SMS", Nome: "Saúde", Site: "javascript:alert(1)"}, false},
+	}
+	for _, tt := range tests {
+		err := validateOrgao(tt.orgao)
+		if tt.valid && err != nil {
+			t.Errorf("%+v: erro inesperado %v", tt.orgao, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidOrgao) {
+			t.Errorf("%+v: esperado ErrInvalidOrgao, obtido %v", tt.orgao, err)
+		}
+	}
+}
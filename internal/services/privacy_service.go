@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+const PrivacyReceiptsCollection = "privacy_receipts"
+
+// ErrInvalidCPF indica CPF ausente ou com quantidade de dígitos inválida
+var ErrInvalidCPF = errors.New("CPF inválido")
+
+// PrivacySource é uma collection com registros vinculados a um CPF. Na eliminação os
+// registros são removidos, ou anonimizados quando precisam ser mantidos como histórico.
+type PrivacySource struct {
+	Collection string
+	Field      string
+	Action     models.PrivacyAction
+	Anonymize  map[string]interface{} // Campos sobrescritos na anonimização
+}
+
+// anonymizedSubject substitui nome e CPF do titular nos registros anonimizados
+const anonymizedSubject = "titular anonimizado"
+
+// DefaultPrivacySources lista as collections com dados vinculados a CPF. As collections de
+// buscas salvas, cliques de feedback e sessões são ignoradas enquanto não existirem.
+func DefaultPrivacySources() []PrivacySource {
+	return []PrivacySource{
+		{Collection: "saved_searches", Field: "cpf", Action: models.PrivacyActionDelete},
+		{Collection: "search_feedback", Field: "cpf", Action: models.PrivacyActionDelete},
+		{Collection: "search_sessions", Field: "cpf", Action: models.PrivacyActionDelete},
+		{
+			Collection: ServiceVersionsCollection,
+			Field:      "created_by_cpf",
+			Action:     models.PrivacyActionAnonymize,
+			Anonymize:  map[string]interface{}{"created_by": anonymizedSubject, "created_by_cpf": anonymizedSubject},
+		},
+		{
+			Collection: ServiceCommentsCollection,
+			Field:      "author_cpf",
+			Action:     models.PrivacyActionAnonymize,
+			Anonymize:  map[string]interface{}{"author_name": anonymizedSubject, "author_cpf": anonymizedSubject},
+		},
+		{
+			Collection: MigrationControlCollection,
+			Field:      "started_by_cpf",
+			Action:     models.PrivacyActionAnonymize,
+			Anonymize:  map[string]interface{}{"started_by": anonymizedSubject, "started_by_cpf": anonymizedSubject},
+		},
+	}
+}
+
+// PrivacyService atende às solicitações de exportação e eliminação de dados do titular
+type PrivacyService struct {
+	client        *typesense.Client
+	sources       []PrivacySource
+	receiptSecret []byte
+}
+
+// NewPrivacyService cria o serviço. receiptSecret é a chave do HMAC usado para identificar o
+// titular nos comprovantes (vazio usa SHA-256 simples).
+func NewPrivacyService(client *typesense.Client, sources []PrivacySource, receiptSecret string) *PrivacyService {
+	return &PrivacyService{
+		client:        client,
+		sources:       sources,
+		receiptSecret: []byte(receiptSecret),
+	}
+}
+
+// NormalizeCPF retorna apenas os dígitos do CPF ou ErrInvalidCPF
+func NormalizeCPF(cpf string) (string, error) {
+	var digits strings.Builder
+	for _, r := range cpf {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	if digits.Len() != 11 {
+		return "", ErrInvalidCPF
+	}
+	return digits.String(), nil
+}
+
+// SubjectHash identifica o titular nos comprovantes sem armazenar o CPF
+func (ps *PrivacyService) SubjectHash(cpf string) string {
+	if len(ps.receiptSecret) == 0 {
+		sum := sha256.Sum256([]byte(cpf))
+		return hex.EncodeToString(sum[:])
+	}
+	mac := hmac.New(sha256.New, ps.receiptSecret)
+	mac.Write([]byte(cpf))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Export reúne todos os registros vinculados ao CPF e registra o comprovante
+func (ps *PrivacyService) Export(ctx context.Context, cpf, requestedBy string) (*models.PrivacyExport, error) {
+	cpf, err := NormalizeCPF(cpf)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := ps.newReceipt(models.PrivacyRequestExport, cpf, requestedBy)
+	records := make(map[string][]map[string]interface{})
+
+	for _, source := range ps.sources {
+		result := models.PrivacyCollectionResult{Collection: source.Collection, Field: source.Field, Action: models.PrivacyActionExport}
+
+		docs, err := ps.fetchAll(ctx, source, cpf)
+		switch {
+		case err != nil && IsNotFoundError(err):
+			result.Skipped = "collection inexistente"
+		case err != nil:
+			result.Error = err.Error()
+		default:
+			result.Records = len(docs)
+			if len(docs) > 0 {
+				records[source.Collection] = docs
+			}
+		}
+
+		receipt.Collections = append(receipt.Collections, result)
+	}
+
+	if err := ps.finishReceipt(ctx, receipt); err != nil {
+		return nil, err
+	}
+
+	return &models.PrivacyExport{Receipt: *receipt, Records: records}, nil
+}
+
+// Delete elimina (ou anonimiza) todos os registros vinculados ao CPF e registra o comprovante
+func (ps *PrivacyService) Delete(ctx context.Context, cpf, requestedBy string) (*models.PrivacyReceipt, error) {
+	cpf, err := NormalizeCPF(cpf)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := ps.newReceipt(models.PrivacyRequestDelete, cpf, requestedBy)
+	filterBy := cpfFilter(cpf)
+
+	for _, source := range ps.sources {
+		result := models.PrivacyCollectionResult{Collection: source.Collection, Field: source.Field, Action: source.Action}
+		filter := pointer.String(source.Field + filterBy)
+
+		var affected int
+		if source.Action == models.PrivacyActionAnonymize {
+			affected, err = ps.client.Collection(source.Collection).Documents().Update(ctx, source.Anonymize, &api.UpdateDocumentsParams{FilterBy: filter})
+		} else {
+			affected, err = ps.client.Collection(source.Collection).Documents().Delete(ctx, &api.DeleteDocumentsParams{FilterBy: filter})
+		}
+
+		switch {
+		case err != nil && IsNotFoundError(err):
+			result.Skipped = "collection inexistente"
+		case err != nil:
+			result.Error = err.Error()
+		default:
+			result.Records = affected
+		}
+
+		receipt.Collections = append(receipt.Collections, result)
+	}
+
+	if err := ps.finishReceipt(ctx, receipt); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[Privacy] Eliminação %s concluída: %d registros (completa: %t)", receipt.ID, receipt.TotalRecords, receipt.Complete)
+	return receipt, nil
+}
+
+// GetReceipt retorna um comprovante pelo ID (nil se não existir)
+func (ps *PrivacyService) GetReceipt(ctx context.Context, id string) (*models.PrivacyReceipt, error) {
+	doc, err := ps.client.Collection(PrivacyReceiptsCollection).Document(id).Retrieve(ctx)
+	if err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar comprovante: %w", err)
+	}
+
+	raw, _ := doc["receipt"].(string)
+	var receipt models.PrivacyReceipt
+	if err := json.Unmarshal([]byte(raw), &receipt); err != nil {
+		return nil, fmt.Errorf("erro ao deserializar comprovante: %w", err)
+	}
+
+	return &receipt, nil
+}
+
+func (ps *PrivacyService) newReceipt(requestType models.PrivacyRequestType, cpf, requestedBy string) *models.PrivacyReceipt {
+	return &models.PrivacyReceipt{
+		ID:          uuid.New().String(),
+		Type:        requestType,
+		SubjectHash: ps.SubjectHash(cpf),
+		RequestedBy: requestedBy,
+		RequestedAt: time.Now().Unix(),
+		Collections: []models.PrivacyCollectionResult{},
+	}
+}
+
+// finishReceipt totaliza, calcula o digest e persiste o comprovante
+func (ps *PrivacyService) finishReceipt(ctx context.Context, receipt *models.PrivacyReceipt) error {
+	receipt.Complete = true
+	for _, result := range receipt.Collections {
+		receipt.TotalRecords += result.Records
+		if result.Error != "" {
+			receipt.Complete = false
+		}
+	}
+	receipt.Digest = receiptDigest(receipt)
+
+	if err := ps.ensureCollection(ctx); err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(receipt)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar comprovante: %w", err)
+	}
+
+	doc := map[string]interface{}{
+		"id":            receipt.ID,
+		"type":          string(receipt.Type),
+		"subject_hash":  receipt.SubjectHash,
+		"requested_by":  receipt.RequestedBy,
+		"requested_at":  receipt.RequestedAt,
+		"total_records": receipt.TotalRecords,
+		"receipt":       string(raw),
+	}
+
+	if _, err := ps.client.Collection(PrivacyReceiptsCollection).Documents().Create(ctx, doc, &api.DocumentIndexParameters{}); err != nil {
+		return fmt.Errorf("erro ao salvar comprovante: %w", err)
+	}
+	return nil
+}
+
+// fetchAll busca todos os registros da collection vinculados ao CPF
+func (ps *PrivacyService) fetchAll(ctx context.Context, source PrivacySource, cpf string) ([]map[string]interface{}, error) {
+	filterBy := source.Field + cpfFilter(cpf)
+	docs := []map[string]interface{}{}
+
+	perPage := 250
+	for page := 1; ; page++ {
+		searchParams := &api.SearchCollectionParams{
+			Q:             pointer.String("*"),
+			FilterBy:      pointer.String(filterBy),
+			Page:          pointer.Int(page),
+			PerPage:       pointer.Int(perPage),
+			ExcludeFields: pointer.String("embedding"),
+		}
+
+		result, err := ps.client.Collection(source.Collection).Documents().Search(ctx, searchParams)
+		if err != nil {
+			return nil, err
+		}
+		if result.Hits == nil {
+			break
+		}
+
+		for _, hit := range *result.Hits {
+			if hit.Document != nil {
+				docs = append(docs, *hit.Document)
+			}
+		}
+		if len(*result.Hits) < perPage {
+			break
+		}
+	}
+
+	return docs, nil
+}
+
+// ensureCollection garante que a collection privacy_receipts existe
+func (ps *PrivacyService) ensureCollection(ctx context.Context) error {
+	_, err := ps.client.Collection(PrivacyReceiptsCollection).Retrieve(ctx)
+	if err == nil {
+		return nil
+	}
+
+	schema := &api.CollectionSchema{
+		Name: PrivacyReceiptsCollection,
+		Fields: []api.Field{
+			{Name: "type", Type: "string", Facet: pointer.True()},
+			{Name: "subject_hash", Type: "string", Facet: pointer.False()},
+			{Name: "requested_by", Type: "string", Facet: pointer.True()},
+			{Name: "requested_at", Type: "int64", Facet: pointer.False()},
+			{Name: "total_records", Type: "int32", Facet: pointer.False()},
+			{Name: "receipt", Type: "string", Index: pointer.False(), Optional: pointer.True()},
+		},
+		DefaultSortingField: pointer.String("requested_at"),
+	}
+
+	if _, err := ps.client.Collections().Create(ctx, schema); err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %w", PrivacyReceiptsCollection, err)
+	}
+
+	return nil
+}
+
+// cpfFilter retorna o sufixo do filter_by que casa o CPF com e sem pontuação
+func cpfFilter(cpf string) string {
+	formatted := cpf[:3] + "." + cpf[3:6] + "." + cpf[6:9] + "-" + cpf[9:]
+	return fmt.Sprintf(":=[`%s`,`%s`]", cpf, formatted)
+}
+
+// receiptDigest calcula o SHA-256 do comprovante (sem o próprio digest)
+func receiptDigest(receipt *models.PrivacyReceipt) string {
+	unsigned := *receipt
+	unsigned.Digest = ""
+	raw, _ := json.Marshal(unsigned)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestNormalizeCPF(t *testing.T) {
+	for _, input := range []string{"123.456.789-09", "12345678909", " 123 456 789 09 "} {
+		if cpf, err := NormalizeCPF(input); err != nil || cpf != "12345678909" {
+			t.Errorf("NormalizeCPF(%q) = %q, %v", input, cpf, err)
+		}
+	}
+	for _, input := range []string{"", "1234567890", "123.456.789-091"} {
+		if _, err := NormalizeCPF(input); err != ErrInvalidCPF {
+			t.Errorf("NormalizeCPF(%q) deveria falhar", input)
+		}
+	}
+}
+
+func TestCPFFilterMatchesBothFormats(t *testing.T) {
+	if got := cpfFilter("12345678909"); got != ":=[`12345678909`,`123.456.789-09`]" {
+		t.Errorf("cpfFilter = %q", got)
+	}
+}
+
+func TestSubjectHashUsesSecret(t *testing.T) {
+	plain := NewPrivacyService(nil, nil, "")
+	keyed := NewPrivacyService(nil, nil, "segredo")
+
+	if plain.SubjectHash("12345678909") == keyed.SubjectHash("12345678909") {
+		t.Error("hash com segredo deveria diferir do SHA-256 simples")
+	}
+	if keyed.SubjectHash("12345678909") != keyed.SubjectHash("12345678909") {
+		t.Error("hash do titular deveria ser determinístico")
+	}
+}
+
+func TestReceiptDigestCoversContent(t *testing.T) {
+	receipt := &models.PrivacyReceipt{
+		ID:   "r1",
+		Type: models.PrivacyRequestDelete,
+		Collections: []models.PrivacyCollectionResult{
+			{Collection: "saved_searches", Action: models.PrivacyActionDelete, Records: 3},
+		},
+	}
+	receipt.Digest = receiptDigest(receipt)
+
+	if receiptDigest(receipt) != receipt.Digest {
+		t.Error("digest não deveria depender do próprio campo digest")
+	}
+
+	receipt.Collections[0].Records = 2
+	if receiptDigest(receipt) == receipt.Digest {
+		t.Error("alteração no comprovante deveria mudar o digest")
+	}
+}
@@ -0,0 +1,375 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/pii"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+const (
+	QueryEmbeddingsCollection = "query_embeddings"
+
+	// queryEmbeddingsMaxPending limita as queries distintas acumuladas entre flushes
+	queryEmbeddingsMaxPending = 10000
+	// queryEmbeddingsMaxAge idade máxima de um embedding pré-computado antes de ser regerado
+	queryEmbeddingsMaxAge = 7 * 24 * time.Hour
+)
+
+// QueryEmbeddingStore mantém embeddings pré-gerados das queries historicamente mais
+// frequentes. A frequência é acumulada em memória e persistida periodicamente na collection
+// query_embeddings; o refresh semanal gera embeddings para o top N e os carrega em memória.
+type QueryEmbeddingStore struct {
+	client   *typesense.Client
+	provider EmbeddingProvider
+	topN     int
+
+	mu      sync.RWMutex
+	vectors map[string][]float32
+	pending map[string]int64
+}
+
+// NewQueryEmbeddingStore cria o store de embeddings pré-computados para as topN queries
+func NewQueryEmbeddingStore(client *typesense.Client, provider EmbeddingProvider, topN int) *QueryEmbeddingStore {
+	return &QueryEmbeddingStore{
+		client:   client,
+		provider: provider,
+		topN:     topN,
+		vectors:  make(map[string][]float32),
+		pending:  make(map[string]int64),
+	}
+}
+
+// Lookup retorna o embedding pré-computado da query, se existir
+func (s *QueryEmbeddingStore) Lookup(query string) ([]float32, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	vector, ok := s.vectors[normalizeQueryKey(query)]
+	return vector, ok
+}
+
+// RecordQuery contabiliza uma ocorrência da query para o ranking de frequência. Dados
+// pessoais são mascarados antes da contagem, já que as queries mais frequentes são persistidas.
+func (s *QueryEmbeddingStore) RecordQuery(query string) {
+	key := normalizeQueryKey(pii.Scrub(query))
+	if key == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.pending[key]; !exists && len(s.pending) >= queryEmbeddingsMaxPending {
+		return
+	}
+	s.pending[key]++
+}
+
+// Size retorna a quantidade de embeddings carregados em memória
+func (s *QueryEmbeddingStore) Size() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.vectors)
+}
+
+// Flush persiste as contagens acumuladas desde o último flush
+func (s *QueryEmbeddingStore) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[string]int64)
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if err := s.ensureCollection(ctx); err != nil {
+		return err
+	}
+
+	queries := sortedKeys(pending)
+	now := time.Now().Unix()
+
+	for start := 0; start < len(queries); start += 100 {
+		end := min(start+100, len(queries))
+		batch := queries[start:end]
+
+		current, err := s.fetchCounts(ctx, batch)
+		if err != nil {
+			return err
+		}
+
+		docs := make([]interface{}, 0, len(batch))
+		for _, query := range batch {
+			docs = append(docs, map[string]interface{}{
+				"id":        queryKeyID(query),
+				"query":     query,
+				"count":     current[queryKeyID(query)] + pending[query],
+				"last_seen": now,
+			})
+		}
+
+		// emplace atualiza apenas os campos enviados, preservando o embedding existente
+		if _, err := s.client.Collection(QueryEmbeddingsCollection).Documents().Import(ctx, docs, &api.ImportDocumentsParams{
+			Action: pointer.Any(api.Emplace),
+		}); err != nil {
+			return fmt.Errorf("erro ao persistir frequência de queries: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Refresh gera embeddings ausentes ou antigos para as topN queries mais frequentes
+// e recarrega o mapa em memória
+func (s *QueryEmbeddingStore) Refresh(ctx context.Context) error {
+	if err := s.ensureCollection(ctx); err != nil {
+		return err
+	}
+
+	top, err := s.fetchTop(ctx)
+	if err != nil {
+		return err
+	}
+
+	model := s.provider.GetModelName()
+	staleBefore := time.Now().Add(-queryEmbeddingsMaxAge).Unix()
+	vectors := make(map[string][]float32, len(top))
+
+	var missing []string
+	for _, doc := range top {
+		query := getString(doc, "query")
+		vector := toFloat32Slice(doc["embedding"])
+		if len(vector) > 0 && getString(doc, "model") == model && getInt64(doc, "embedded_at") >= staleBefore {
+			vectors[query] = vector
+			continue
+		}
+		missing = append(missing, query)
+	}
+
+	generated := 0
+	for start := 0; start < len(missing); start += 50 {
+		end := min(start+50, len(missing))
+		batch := missing[start:end]
+
+		embeddings, err := s.provider.GenerateBatch(ctx, batch)
+		if err != nil {
+			log.Printf("[QueryEmbeddings] Erro ao gerar embeddings: %v", err)
+			break
+		}
+
+		now := time.Now().Unix()
+		docs := make([]interface{}, 0, len(batch))
+		for i, query := range batch {
+			if i >= len(embeddings) || len(embeddings[i]) == 0 {
+				continue
+			}
+			vectors[query] = embeddings[i]
+			docs = append(docs, map[string]interface{}{
+				"id":          queryKeyID(query),
+				"embedding":   embeddings[i],
+				"model":       model,
+				"embedded_at": now,
+			})
+		}
+		if len(docs) == 0 {
+			continue
+		}
+
+		if _, err := s.client.Collection(QueryEmbeddingsCollection).Documents().Import(ctx, docs, &api.ImportDocumentsParams{
+			Action: pointer.Any(api.Emplace),
+		}); err != nil {
+			log.Printf("[QueryEmbeddings] Erro ao salvar embeddings: %v", err)
+			continue
+		}
+		generated += len(docs)
+	}
+
+	s.mu.Lock()
+	s.vectors = vectors
+	s.mu.Unlock()
+
+	log.Printf("[QueryEmbeddings] %d embeddings pré-computados carregados (%d gerados)", len(vectors), generated)
+	return nil
+}
+
+// StartRoutines carrega os embeddings existentes e inicia o flush periódico das
+// contagens e o refresh semanal dos embeddings
+func (s *QueryEmbeddingStore) StartRoutines(flushInterval, refreshInterval time.Duration) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		if err := s.Refresh(ctx); err != nil {
+			log.Printf("[QueryEmbeddings] Erro no carregamento inicial: %v", err)
+		}
+		cancel()
+
+		flush := time.NewTicker(flushInterval)
+		refresh := time.NewTicker(refreshInterval)
+		defer flush.Stop()
+		defer refresh.Stop()
+
+		for {
+			select {
+			case <-flush.C:
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				if err := s.Flush(ctx); err != nil {
+					log.Printf("[QueryEmbeddings] Erro ao persistir frequências: %v", err)
+				}
+				cancel()
+			case <-refresh.C:
+				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+				if err := s.Refresh(ctx); err != nil {
+					log.Printf("[QueryEmbeddings] Erro no refresh: %v", err)
+				}
+				cancel()
+			}
+		}
+	}()
+}
+
+// fetchCounts retorna as contagens atuais dos IDs informados
+func (s *QueryEmbeddingStore) fetchCounts(ctx context.Context, queries []string) (map[string]int64, error) {
+	ids := make([]string, len(queries))
+	for i, query := range queries {
+		ids[i] = queryKeyID(query)
+	}
+
+	result, err := s.client.Collection(QueryEmbeddingsCollection).Documents().Search(ctx, &api.SearchCollectionParams{
+		Q:             pointer.String("*"),
+		FilterBy:      pointer.String(fmt.Sprintf("id:[%s]", strings.Join(ids, ","))),
+		IncludeFields: pointer.String("id,count"),
+		PerPage:       pointer.Int(len(ids)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar frequência de queries: %w", err)
+	}
+
+	counts := make(map[string]int64, len(ids))
+	if result.Hits != nil {
+		for _, hit := range *result.Hits {
+			if hit.Document != nil {
+				counts[getString(*hit.Document, "id")] = getInt64(*hit.Document, "count")
+			}
+		}
+	}
+	return counts, nil
+}
+
+// fetchTop retorna os documentos das topN queries mais frequentes
+func (s *QueryEmbeddingStore) fetchTop(ctx context.Context) ([]map[string]interface{}, error) {
+	var docs []map[string]interface{}
+	perPage := 250
+
+	for page := 1; len(docs) < s.topN; page++ {
+		result, err := s.client.Collection(QueryEmbeddingsCollection).Documents().Search(ctx, &api.SearchCollectionParams{
+			Q:       pointer.String("*"),
+			SortBy:  pointer.String("count:desc"),
+			Page:    pointer.Int(page),
+			PerPage: pointer.Int(perPage),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar queries frequentes: %w", err)
+		}
+		if result.Hits == nil || len(*result.Hits) == 0 {
+			break
+		}
+
+		for _, hit := range *result.Hits {
+			if hit.Document != nil && len(docs) < s.topN {
+				docs = append(docs, *hit.Document)
+			}
+		}
+		if len(*result.Hits) < perPage {
+			break
+		}
+	}
+
+	return docs, nil
+}
+
+func (s *QueryEmbeddingStore) ensureCollection(ctx context.Context) error {
+	_, err := s.client.Collection(QueryEmbeddingsCollection).Retrieve(ctx)
+	if err == nil {
+		return nil
+	}
+
+	schema := &api.CollectionSchema{
+		Name: QueryEmbeddingsCollection,
+		Fields: []api.Field{
+			{Name: "query", Type: "string", Facet: pointer.False()},
+			{Name: "count", Type: "int64", Facet: pointer.False()},
+			{Name: "last_seen", Type: "int64", Facet: pointer.False()},
+			{Name: "embedding", Type: "float[]", Optional: pointer.True(), NumDim: pointer.Int(s.provider.GetDimensions())},
+			{Name: "model", Type: "string", Facet: pointer.True(), Optional: pointer.True()},
+			{Name: "embedded_at", Type: "int64", Facet: pointer.False(), Optional: pointer.True()},
+		},
+		DefaultSortingField: pointer.String("count"),
+	}
+
+	if _, err := s.client.Collections().Create(ctx, schema); err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %w", QueryEmbeddingsCollection, err)
+	}
+
+	return nil
+}
+
+// PrecomputedEmbeddingProvider consulta os embeddings pré-computados antes de chamar o provider
+type PrecomputedEmbeddingProvider struct {
+	EmbeddingProvider
+	store *QueryEmbeddingStore
+}
+
+// NewPrecomputedEmbeddingProvider envolve o provider com a camada de embeddings pré-computados
+func NewPrecomputedEmbeddingProvider(provider EmbeddingProvider, store *QueryEmbeddingStore) *PrecomputedEmbeddingProvider {
+	return &PrecomputedEmbeddingProvider{
+		EmbeddingProvider: provider,
+		store:             store,
+	}
+}
+
+// GenerateEmbedding retorna o embedding pré-computado da query quando disponível
+func (p *PrecomputedEmbeddingProvider) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	if SensitiveTopicFromContext(ctx) == "" {
+		p.store.RecordQuery(text)
+	}
+	if vector, ok := p.store.Lookup(text); ok {
+		return vector, nil
+	}
+	return p.EmbeddingProvider.GenerateEmbedding(ctx, text)
+}
+
+// normalizeQueryKey normaliza a query para contagem e lookup (minúsculas, espaços simples)
+func normalizeQueryKey(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+// queryKeyID gera o ID do documento da query normalizada
+func queryKeyID(query string) string {
+	hash := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(hash[:16])
+}
+
+// toFloat32Slice converte o vetor decodificado do JSON do Typesense
+func toFloat32Slice(v interface{}) []float32 {
+	values, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	vector := make([]float32, 0, len(values))
+	for _, value := range values {
+		f, ok := value.(float64)
+		if !ok {
+			return nil
+		}
+		vector = append(vector, float32(f))
+	}
+	return vector
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"testing"
+)
+
+type fakeEmbeddingProvider struct {
+	calls int
+}
+
+func (f *fakeEmbeddingProvider) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	f.calls++
+	return []float32{0.5}, nil
+}
+
+func (f *fakeEmbeddingProvider) GenerateBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	return nil, nil
+}
+
+func (f *fakeEmbeddingProvider) GetDimensions() int   { return 1 }
+func (f *fakeEmbeddingProvider) GetModelName() string { return "fake" }
+
+func TestPrecomputedEmbeddingProvider(t *testing.T) {
+	base := &fakeEmbeddingProvider{}
+	store := NewQueryEmbeddingStore(nil, base, 10)
+	store.vectors["segunda via iptu"] = []float32{0.1}
+	provider := NewPrecomputedEmbeddingProvider(base, store)
+
+	vector, err := provider.GenerateEmbedding(context.Background(), "  Segunda  via IPTU ")
+	if err != nil || len(vector) != 1 || vector[0] != 0.1 {
+		t.Fatalf("esperado embedding pré-computado, obtido %v (%v)", vector, err)
+	}
+	if base.calls != 0 {
+		t.Errorf("provider não deveria ser chamado para query pré-computada")
+	}
+
+	if _, err := provider.GenerateEmbedding(context.Background(), "multa de trânsito"); err != nil || base.calls != 1 {
+		t.Errorf("query desconhecida deveria chamar o provider (chamadas: %d, erro: %v)", base.calls, err)
+	}
+
+	if store.pending["segunda via iptu"] != 1 || store.pending["multa de trânsito"] != 1 {
+		t.Errorf("frequências não registradas: %v", store.pending)
+	}
+}
+
+func TestRecordQueryScrubsPII(t *testing.T) {
+	store := NewQueryEmbeddingStore(nil, &fakeEmbeddingProvider{}, 10)
+
+	store.RecordQuery("segunda via iptu cpf 123.456.789-09")
+	store.RecordQuery("Segunda via IPTU cpf 98765432100")
+
+	if len(store.pending) != 1 || store.pending["segunda via iptu cpf [cpf]"] != 2 {
+		t.Errorf("CPF não mascarado antes da contagem: %v", store.pending)
+	}
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"google.golang.org/genai"
+)
+
+// queryTranslationCachePrefix prefixo das chaves de tradução de query no cache
+const queryTranslationCachePrefix = CacheQueryTranslation + ":"
+
+const queryTranslationPrompt = `Traduza para o português do Brasil a busca abaixo, feita em %s por um usuário do portal de serviços públicos da Prefeitura do Rio de Janeiro.
+
+Busca: %q
+
+Regras:
+- Use os termos que a Prefeitura usa para nomear serviços (ex.: "birth certificate" → "certidão de nascimento")
+- Mantenha nomes próprios, siglas e números
+- Não acrescente palavras que não estejam na busca
+
+Retorne APENAS o JSON: {"query": "busca traduzida"}`
+
+// Palavras frequentes em buscas em cada idioma. Termos comuns ao português e ao espanhol
+// (para, como, una) ficam apenas no português: na dúvida a query não é traduzida.
+var queryLanguageWords = map[string]map[string]bool{
+	"pt": wordSet("de do da dos das no na nos nas um uma para pra como onde quero preciso meu minha não com ao em é são está fazer tirar segunda via quando qual quais posso"),
+	"en": wordSet("the of and to how where what when which my i can do does get for is are an with need want apply renew renewal license permit tax card birth certificate school doctor appointment pay bill city hall help lost report request schedule"),
+	"es": wordSet("el los las del y es cómo dónde qué cuándo cuál quiero necesito mi mis puedo hacer sacar cita licencia nacimiento impuesto ayuda trámite solicitar perdí ciudad alcaldía"),
+}
+
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+// DetectQueryLanguage identifica o idioma da query (pt, en ou es) pelas palavras frequentes
+// e pelos caracteres próprios de cada idioma. Sem indícios de outro idioma, a query é
+// considerada português.
+func DetectQueryLanguage(query string) string {
+	lower := strings.ToLower(query)
+	scores := map[string]int{}
+
+	if strings.ContainsAny(lower, "ãõç") {
+		scores["pt"] += 2
+	}
+	if strings.ContainsAny(lower, "ñ¿¡") {
+		scores["es"] += 2
+	}
+
+	words := strings.FieldsFunc(lower, func(r rune) bool { return !unicode.IsLetter(r) && r != '\'' })
+	for _, word := range words {
+		for lang, set := range queryLanguageWords {
+			if set[word] {
+				scores[lang]++
+			}
+		}
+	}
+
+	detected := "pt"
+	for _, lang := range []string{"en", "es"} {
+		if scores[lang] > scores[detected] {
+			detected = lang
+		}
+	}
+	return detected
+}
+
+// QueryTranslator traduz para português as buscas feitas em outros idiomas, para que
+// encontrem os serviços do índice pt-BR. As traduções ficam em cache.
+type QueryTranslator struct {
+	cache     Cache
+	translate func(ctx context.Context, query, lang string) (string, error)
+}
+
+// NewQueryTranslator cria o tradutor de queries com o Gemini
+func NewQueryTranslator(geminiClient *genai.Client, model string, cache Cache) *QueryTranslator {
+	return &QueryTranslator{
+		cache: cache,
+		translate: func(ctx context.Context, query, lang string) (string, error) {
+			return translateQueryWithGemini(ctx, geminiClient, model, query, lang)
+		},
+	}
+}
+
+// Prepare identifica o idioma da busca (ou usa req.Lang) e, se não for português, substitui
+// req.Query pela tradução. Falhas na tradução mantêm a query original. Retorna nil sem
+// tradutor configurado.
+func (qt *QueryTranslator) Prepare(ctx context.Context, req *models.SearchRequest) *models.QueryMeta {
+	if qt == nil {
+		return nil
+	}
+
+	lang := strings.ToLower(strings.TrimSpace(req.Lang))
+	if lang == "" {
+		lang = DetectQueryLanguage(req.Query)
+	}
+	// A query passa a ser tratada como português (inclusive nas chaves de cache)
+	req.Lang = ""
+
+	meta := &models.QueryMeta{OriginalQuery: req.Query, Language: lang}
+	if lang == "pt" || strings.TrimSpace(req.Query) == "" || req.Query == "*" {
+		return meta
+	}
+
+	cacheKey := queryTranslationCachePrefix + lang + ":" + req.Query
+	translated, ok := qt.cache.Get(cacheKey).(string)
+	if !ok {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		var err error
+		translated, err = qt.translate(ctx, req.Query, lang)
+		if err != nil {
+			log.Printf("Aviso: erro ao traduzir query (%s), buscando a original: %v", lang, err)
+			return meta
+		}
+		qt.cache.Set(cacheKey, translated, 24*time.Hour)
+	}
+
+	meta.TranslatedQuery = translated
+	meta.Translated = true
+	req.Query = translated
+	return meta
+}
+
+func translateQueryWithGemini(ctx context.Context, geminiClient *genai.Client, model, query, lang string) (string, error) {
+	if geminiClient == nil {
+		return "", fmt.Errorf("cliente Gemini não configurado")
+	}
+
+	languageName := languageNames[lang]
+	if languageName == "" {
+		languageName = lang
+	}
+
+	prompt := fmt.Sprintf(queryTranslationPrompt, languageName, query)
+	config := &genai.GenerateContentConfig{ResponseMIMEType: "application/json"}
+
+	resp, err := geminiClient.Models.GenerateContent(ctx, model, []*genai.Content{genai.NewContentFromText(prompt, genai.RoleUser)}, config)
+	if err != nil {
+		return "", fmt.Errorf("erro ao chamar Gemini: %w", err)
+	}
+
+	var result struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(resp.Text())), &result); err != nil {
+		return "", fmt.Errorf("erro ao parsear JSON do Gemini: %w", err)
+	}
+	if strings.TrimSpace(result.Query) == "" {
+		return "", fmt.Errorf("tradução vazia")
+	}
+	return strings.TrimSpace(result.Query), nil
+}
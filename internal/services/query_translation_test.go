@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestDetectQueryLanguage(t *testing.T) {
+	tests := map[string]string{
+		"segunda via do IPTU":                  "pt",
+		"como tirar carteira de trabalho":      "pt",
+		"matrícula escolar":                    "pt",
+		"how to get a birth certificate":       "en",
+		"pay property tax":                     "en",
+		"¿cómo sacar la licencia de conducir?": "es",
+		"quiero una cita en el hospital":       "es",
+		"IPTU":                                 "pt",
+		"renovar a CNH":                        "pt",
+	}
+	for query, want := range tests {
+		if got := DetectQueryLanguage(query); got != want {
+			t.Errorf("DetectQueryLanguage(%q) = %s, want %s", query, got, want)
+		}
+	}
+}
+
+func TestQueryTranslatorPrepare(t *testing.T) {
+	calls := 0
+	qt := &QueryTranslator{
+		cache: NewLRUCache(10),
+		translate: func(_ context.Context, query, lang string) (string, error) {
+			calls++
+			if lang == "es" {
+				return "", errors.New("indisponível")
+			}
+			return "certidão de nascimento", nil
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		req := &models.SearchRequest{Query: "how to get a birth certificate"}
+		meta := qt.Prepare(context.Background(), req)
+		if !meta.Translated || meta.Language != "en" || req.Query != "certidão de nascimento" || meta.OriginalQuery != "how to get a birth certificate" {
+			t.Fatalf("tradução inesperada: %+v (query %q)", meta, req.Query)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("tradução deveria vir do cache, chamadas: %d", calls)
+	}
+
+	// Falha na tradução mantém a query original
+	req := &models.SearchRequest{Query: "licencia", Lang: "ES"}
+	if meta := qt.Prepare(context.Background(), req); meta.Translated || meta.Language != "es" || req.Query != "licencia" || req.Lang != "" {
+		t.Errorf("falha deveria manter a query: %+v (query %q)", meta, req.Query)
+	}
+
+	// Português não é traduzido
+	req = &models.SearchRequest{Query: "segunda via do IPTU"}
+	if meta := qt.Prepare(context.Background(), req); meta.Translated || calls != 2 {
+		t.Errorf("query em português não deveria ser traduzida: %+v", meta)
+	}
+
+	var disabled *QueryTranslator
+	if disabled.Prepare(context.Background(), req) != nil {
+		t.Error("tradutor nil deveria retornar nil")
+	}
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+)
+
+// recencyFactorAt calcula o fator de recência de um documento com o timestamp informado
+// (unix, 0 se ausente) segundo a curva configurada:
+//   - até grace_days: 1.0
+//   - exponential: 0.5^(dias após a carência / half_life_days)
+//   - step: fator do primeiro degrau cujo max_days cobre a idade
+//
+// O resultado nunca fica abaixo de floor; documentos sem data recebem o floor.
+func recencyFactorAt(cfg config.RecencyConfig, timestamp int64, now time.Time) float64 {
+	if timestamp <= 0 {
+		return cfg.Floor
+	}
+
+	ageDays := float64(now.Unix()-timestamp) / 86400.0
+	if ageDays <= cfg.GraceDays {
+		return 1.0
+	}
+
+	factor := cfg.Floor
+	switch cfg.Curve {
+	case config.RecencyCurveStep:
+		for _, step := range cfg.Steps {
+			if ageDays <= step.MaxDays {
+				factor = step.Factor
+				break
+			}
+		}
+	default:
+		if cfg.HalfLifeDays > 0 {
+			factor = math.Pow(0.5, (ageDays-cfg.GraceDays)/cfg.HalfLifeDays)
+		}
+	}
+
+	return math.Max(cfg.Floor, factor)
+}
+
+// recencyTimestamp retorna o timestamp mais recente entre os campos configurados
+func recencyTimestamp(fields []string, lookup func(field string) int64) int64 {
+	var latest int64
+	for _, field := range fields {
+		latest = max(latest, lookup(field))
+	}
+	return latest
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestRecencyFactorCurves(t *testing.T) {
+	now := time.Now()
+	daysAgo := func(days int) int64 { return now.Add(-time.Duration(days) * 24 * time.Hour).Unix() }
+
+	exponential := config.RecencyConfig{Curve: config.RecencyCurveExponential, GraceDays: 10, HalfLifeDays: 20, Floor: 0.2}
+	if got := recencyFactorAt(exponential, daysAgo(30), now); math.Abs(got-0.5) > 0.001 {
+		t.Errorf("exponential após uma meia-vida = %.3f, esperado 0.5", got)
+	}
+	if got := recencyFactorAt(exponential, daysAgo(500), now); got != 0.2 {
+		t.Errorf("exponential abaixo do floor = %.3f, esperado 0.2", got)
+	}
+
+	step := config.RecencyConfig{Curve: config.RecencyCurveStep, Floor: 0.3, Steps: []config.RecencyStep{
+		{MaxDays: 90, Factor: 0.9},
+		{MaxDays: 365, Factor: 0.6},
+	}}
+	tests := map[int]float64{0: 1.0, 60: 0.9, 200: 0.6, 400: 0.3}
+	for days, want := range tests {
+		if got := recencyFactorAt(step, daysAgo(days), now); got != want {
+			t.Errorf("step com %d dias = %.2f, esperado %.2f", days, got, want)
+		}
+	}
+}
+
+func TestRecencyTimestampUsesMostRecentField(t *testing.T) {
+	doc := map[string]interface{}{"last_update": float64(100), "published_at": float64(300)}
+	got := recencyTimestamp([]string{"last_update", "published_at", "missing"}, func(field string) int64 {
+		return getInt64(doc, field)
+	})
+	if got != 300 {
+		t.Errorf("timestamp = %d, esperado 300", got)
+	}
+}
+
+func TestApplyRecencyBoostAlwaysCollections(t *testing.T) {
+	cfg := &config.Config{CollectionConfigs: map[string]*config.CollectionConfig{
+		PrefRioServicesCollection:     {Type: "service"},
+		config.NoticiasCollectionName: config.DefaultNoticiasCollectionConfig(),
+	}}
+	ss := &SearchServiceV2{config: cfg}
+	score := func(v float64) *models.ScoreInfo { return &models.ScoreInfo{TextMatchNormalized: &v} }
+
+	old := time.Now().Add(-120 * 24 * time.Hour).Unix()
+	docs := []*models.UnifiedDocument{
+		{ID: "noticia", Collection: config.NoticiasCollectionName, Data: map[string]interface{}{"published_at": float64(old)}, ScoreInfo: score(0.9)},
+		{ID: "servico", Collection: PrefRioServicesCollection, Data: map[string]interface{}{"last_update": float64(old)}, ScoreInfo: score(0.5)},
+	}
+
+	// Sem recency_boost só as notícias perdem peso; o serviço mantém o score base
+	ss.applyRecencyBoost(docs, false, 0.3)
+	if docs[0].ID != "servico" || *docs[0].ScoreInfo.FinalScore != 0.5 {
+		t.Errorf("serviço deveria manter o score base e passar a notícia antiga: %s %.3f", docs[0].ID, *docs[0].ScoreInfo.FinalScore)
+	}
+	if *docs[1].ScoreInfo.RecencyFactor > 0.2 {
+		t.Errorf("notícia de 120 dias com fator %.3f, esperado próximo do floor", *docs[1].ScoreInfo.RecencyFactor)
+	}
+
+	// Sem collections com recência permanente a ordem original é mantida
+	serviceDocs := []*models.UnifiedDocument{{ID: "a", Collection: PrefRioServicesCollection, ScoreInfo: score(0.1)}}
+	ss.applyRecencyBoost(serviceDocs, false, 0.3)
+	if serviceDocs[0].ScoreInfo.FinalScore != nil {
+		t.Error("boost não deveria ser aplicado sem recency_boost")
+	}
+}
+
+func TestFilterCollectionsByType(t *testing.T) {
+	ss := &SearchServiceV2{config: &config.Config{CollectionConfigs: map[string]*config.CollectionConfig{
+		PrefRioServicesCollection:     {Type: "service"},
+		config.NoticiasCollectionName: {Type: "news"},
+	}}}
+	collections := []string{PrefRioServicesCollection, config.NoticiasCollectionName}
+
+	got, err := ss.filterCollectionsByType(collections, " news ")
+	if err != nil || len(got) != 1 || got[0] != config.NoticiasCollectionName {
+		t.Errorf("doc_types=news retornou %v, %v", got, err)
+	}
+	if _, err := ss.filterCollectionsByType(collections, "job"); err == nil {
+		t.Error("tipo sem collections deveria retornar erro")
+	}
+}
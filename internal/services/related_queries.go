@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/jobs"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+const (
+	RelatedQueriesCollection = "related_queries"
+
+	// relatedQueriesDays período de jornadas analisado em cada cálculo
+	relatedQueriesDays = 30
+	// relatedQueriesStored relacionadas gravadas por query
+	relatedQueriesStored = 10
+	// RelatedQueriesShown relacionadas incluídas na resposta da busca
+	RelatedQueriesShown = 3
+	// relatedQueriesMaxPerSession queries distintas consideradas por sessão, para que sessões
+	// automatizadas não dominem a coocorrência
+	relatedQueriesMaxPerSession = 20
+)
+
+// RelatedQueries sugere queries buscadas nas mesmas sessões ("quem buscou isto também
+// buscou"). A coocorrência é calculada diariamente das jornadas (job related-queries) e gravada
+// na collection related_queries; cada réplica mantém a tabela em memória, recarregada
+// periodicamente, de modo que a busca não faz consultas adicionais.
+type RelatedQueries struct {
+	client      *typesense.Client
+	journeys    *JourneyAnalytics
+	minSessions int
+
+	mu      sync.RWMutex
+	related map[string][]string
+}
+
+// NewRelatedQueries cria as sugestões. Um par de queries é sugerido a partir de minSessions
+// sessões distintas em que ambas foram buscadas.
+func NewRelatedQueries(client *typesense.Client, journeys *JourneyAnalytics, minSessions int) *RelatedQueries {
+	return &RelatedQueries{
+		client:      client,
+		journeys:    journeys,
+		minSessions: max(minSessions, 1),
+		related:     make(map[string][]string),
+	}
+}
+
+// Lookup retorna até limit queries relacionadas à query (nil sem sugestões)
+func (rq *RelatedQueries) Lookup(query string, limit int) []string {
+	if rq == nil {
+		return nil
+	}
+	rq.mu.RLock()
+	related := rq.related[normalizeQueryKey(query)]
+	rq.mu.RUnlock()
+
+	if len(related) > limit {
+		related = related[:limit]
+	}
+	return related
+}
+
+// Compute recalcula a coocorrência das jornadas recentes e regrava a collection: queries sem
+// relacionadas no novo cálculo são removidas
+func (rq *RelatedQueries) Compute(ctx context.Context) (*models.RelatedQueriesResult, error) {
+	if err := rq.journeys.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+	events, err := rq.journeys.fetchEvents(ctx, time.Now().AddDate(0, 0, -relatedQueriesDays).Unix())
+	if err != nil {
+		return nil, err
+	}
+	if err := rq.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+
+	related, sessions := coOccurringQueries(events, rq.minSessions, relatedQueriesStored)
+	result := &models.RelatedQueriesResult{Sessions: sessions, Queries: len(related)}
+
+	computedAt := time.Now().Unix()
+	queries := sortedKeys(related)
+	for start := 0; start < len(queries); start += 100 {
+		end := min(start+100, len(queries))
+		docs := make([]interface{}, 0, end-start)
+		for _, query := range queries[start:end] {
+			docs = append(docs, map[string]interface{}{
+				"id":         queryKeyID(query),
+				"query":      query,
+				"related":    related[query],
+				"updated_at": computedAt,
+			})
+		}
+		if _, err := rq.client.Collection(RelatedQueriesCollection).Documents().Import(ctx, docs, &api.ImportDocumentsParams{
+			Action: pointer.Any(api.Upsert),
+		}); err != nil {
+			return nil, fmt.Errorf("erro ao gravar queries relacionadas: %w", err)
+		}
+	}
+
+	removed, err := rq.client.Collection(RelatedQueriesCollection).Documents().Delete(ctx, &api.DeleteDocumentsParams{
+		FilterBy: pointer.String(fmt.Sprintf("updated_at:<%d", computedAt)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao remover queries relacionadas antigas: %w", err)
+	}
+	result.Removed = removed
+
+	rq.mu.Lock()
+	rq.related = related
+	rq.mu.Unlock()
+
+	log.Printf("[RelatedQueries] Cálculo concluído: %d sessões, %d queries com relacionadas, %d removidas",
+		result.Sessions, result.Queries, result.Removed)
+	return result, nil
+}
+
+// Load recarrega a tabela em memória a partir da collection
+func (rq *RelatedQueries) Load(ctx context.Context) error {
+	if err := rq.ensureCollection(ctx); err != nil {
+		return err
+	}
+
+	related := make(map[string][]string)
+	perPage := 250
+	for page := 1; ; page++ {
+		result, err := rq.client.Collection(RelatedQueriesCollection).Documents().Search(ctx, &api.SearchCollectionParams{
+			Q:       pointer.String("*"),
+			Page:    pointer.Int(page),
+			PerPage: pointer.Int(perPage),
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao carregar queries relacionadas: %w", err)
+		}
+		if result.Hits == nil || len(*result.Hits) == 0 {
+			break
+		}
+		for _, hit := range *result.Hits {
+			if hit.Document == nil {
+				continue
+			}
+			if values, ok := (*hit.Document)["related"].([]interface{}); ok {
+				queries := make([]string, 0, len(values))
+				for _, value := range values {
+					if query, ok := value.(string); ok {
+						queries = append(queries, query)
+					}
+				}
+				related[getString(*hit.Document, "query")] = queries
+			}
+		}
+		if len(*result.Hits) < perPage {
+			break
+		}
+	}
+
+	rq.mu.Lock()
+	rq.related = related
+	rq.mu.Unlock()
+	return nil
+}
+
+// StartRoutines agenda o cálculo diário (4h45, job related-queries, executado por uma única
+// réplica), carrega a tabela e a recarrega a cada reloadInterval em todas as réplicas
+func (rq *RelatedQueries) StartRoutines(runner *jobs.Runner, reloadInterval time.Duration) error {
+	if err := runner.Register("related-queries", "45 4 * * *", 15*time.Minute, func(ctx context.Context) error {
+		_, err := rq.Compute(ctx)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(reloadInterval)
+		defer ticker.Stop()
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			if err := rq.Load(ctx); err != nil {
+				log.Printf("[RelatedQueries] Erro ao carregar: %v", err)
+			}
+			cancel()
+			<-ticker.C
+		}
+	}()
+	return nil
+}
+
+// ensureCollection garante que a collection related_queries existe
+func (rq *RelatedQueries) ensureCollection(ctx context.Context) error {
+	_, err := rq.client.Collection(RelatedQueriesCollection).Retrieve(ctx)
+	if err == nil {
+		return nil
+	}
+
+	schema := &api.CollectionSchema{
+		Name: RelatedQueriesCollection,
+		Fields: []api.Field{
+			{Name: "query", Type: "string", Facet: pointer.False()},
+			{Name: "related", Type: "string[]", Index: pointer.False(), Optional: pointer.True()},
+			{Name: "updated_at", Type: "int64", Facet: pointer.False()},
+		},
+		DefaultSortingField: pointer.String("updated_at"),
+	}
+
+	if _, err := rq.client.Collections().Create(ctx, schema); err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %w", RelatedQueriesCollection, err)
+	}
+	return nil
+}
+
+// coOccurringQueries conta, para cada par de queries distintas, as sessões em que ambas foram
+// buscadas e retorna para cada query até limit relacionadas vistas em ao menos minSessions
+// sessões (empates em ordem alfabética), além de quantas sessões tinham mais de uma query
+func coOccurringQueries(events []models.JourneyEvent, minSessions, limit int) (map[string][]string, int) {
+	bySession := make(map[string][]string)
+	seen := make(map[string]map[string]bool)
+	for _, event := range events {
+		if event.Type != models.JourneyEventSearch || event.Query == "" {
+			continue
+		}
+		if seen[event.SessionID] == nil {
+			seen[event.SessionID] = make(map[string]bool)
+		}
+		if seen[event.SessionID][event.Query] || len(bySession[event.SessionID]) >= relatedQueriesMaxPerSession {
+			continue
+		}
+		seen[event.SessionID][event.Query] = true
+		bySession[event.SessionID] = append(bySession[event.SessionID], event.Query)
+	}
+
+	counts := make(map[string]map[string]int)
+	sessions := 0
+	for _, queries := range bySession {
+		if len(queries) < 2 {
+			continue
+		}
+		sessions++
+		for _, query := range queries {
+			for _, other := range queries {
+				if other == query {
+					continue
+				}
+				if counts[query] == nil {
+					counts[query] = make(map[string]int)
+				}
+				counts[query][other]++
+			}
+		}
+	}
+
+	related := make(map[string][]string)
+	for query, others := range counts {
+		candidates := make([]string, 0, len(others))
+		for other, count := range others {
+			if count >= minSessions {
+				candidates = append(candidates, other)
+			}
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+		sort.Slice(candidates, func(i, j int) bool {
+			if others[candidates[i]] != others[candidates[j]] {
+				return others[candidates[i]] > others[candidates[j]]
+			}
+			return candidates[i] < candidates[j]
+		})
+		if len(candidates) > limit {
+			candidates = candidates[:limit]
+		}
+		related[query] = candidates
+	}
+	return related, sessions
+}
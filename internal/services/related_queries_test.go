@@ -0,0 +1,54 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestCoOccurringQueries(t *testing.T) {
+	search := func(session, query string) models.JourneyEvent {
+		return models.JourneyEvent{SessionID: session, Type: models.JourneyEventSearch, Query: query}
+	}
+	events := []models.JourneyEvent{
+		search("s1", "iptu"), search("s1", "segunda via iptu"), search("s1", "iptu"),
+		search("s2", "iptu"), search("s2", "segunda via iptu"), search("s2", "isencao iptu"),
+		search("s3", "iptu"), search("s3", "isencao iptu"),
+		search("s4", "iptu"), search("s4", "segunda via iptu"),
+		search("s5", "iptu"),
+		{SessionID: "s5", Type: models.JourneyEventClick, Query: "nota fiscal"},
+	}
+
+	related, sessions := coOccurringQueries(events, 2, 10)
+	if sessions != 4 {
+		t.Errorf("esperadas 4 sessões com mais de uma query, obtidas %d", sessions)
+	}
+	if want := []string{"segunda via iptu", "isencao iptu"}; !reflect.DeepEqual(related["iptu"], want) {
+		t.Errorf("relacionadas de iptu: esperado %v, obtido %v", want, related["iptu"])
+	}
+	if want := []string{"iptu"}; !reflect.DeepEqual(related["segunda via iptu"], want) {
+		t.Errorf("relacionadas de segunda via iptu: esperado %v, obtido %v", want, related["segunda via iptu"])
+	}
+	if _, ok := related["nota fiscal"]; ok {
+		t.Error("cliques não deveriam contar como buscas")
+	}
+
+	// Pares abaixo do mínimo de sessões não são sugeridos
+	related, _ = coOccurringQueries(events, 3, 10)
+	if want := []string{"segunda via iptu"}; !reflect.DeepEqual(related["iptu"], want) {
+		t.Errorf("com mínimo de 3 sessões: esperado %v, obtido %v", want, related["iptu"])
+	}
+}
+
+func TestRelatedQueriesLookup(t *testing.T) {
+	rq := &RelatedQueries{related: map[string][]string{"segunda via iptu": {"iptu", "isencao iptu", "iss", "itbi"}}}
+	if got := rq.Lookup("  Segunda  Via IPTU ", 3); !reflect.DeepEqual(got, []string{"iptu", "isencao iptu", "iss"}) {
+		t.Errorf("lookup normalizado e limitado: obtido %v", got)
+	}
+
+	var disabled *RelatedQueries
+	if got := disabled.Lookup("iptu", 3); got != nil {
+		t.Errorf("sem sugestões configuradas deveria retornar nil, obtido %v", got)
+	}
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// startReloadLoop executa reload imediatamente e depois a cada interval, em segundo plano, até o
+// cancelamento de ctx. As falhas são registradas com o prefixo name e não interrompem o ciclo.
+func startReloadLoop(ctx context.Context, name string, interval time.Duration, reload func(context.Context) error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := reload(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("[%s] %v", name, err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStartReloadLoopStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	startReloadLoop(ctx, "Teste", time.Millisecond, func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+
+	deadline := time.Now().Add(time.Second)
+	for calls.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if calls.Load() < 3 {
+		t.Fatalf("esperadas ao menos 3 recargas, obtidas %d", calls.Load())
+	}
+
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	stopped := calls.Load()
+	time.Sleep(20 * time.Millisecond)
+	if calls.Load() != stopped {
+		t.Errorf("recargas continuaram após o cancelamento: %d -> %d", stopped, calls.Load())
+	}
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense"
+)
+
+const (
+	// replicaFailAfter probes consecutivos com falha para tirar a réplica de uso
+	replicaFailAfter = 2
+	// replicaRecoverAfter probes consecutivos bem-sucedidos para voltar a usar a réplica
+	replicaRecoverAfter = 3
+)
+
+// ReplicaRouter direciona as buscas públicas a uma réplica somente leitura do Typesense, isolando
+// a latência dos cidadãos da carga de escrita, reindexação e migração no primário. Escritas e
+// leituras administrativas continuam no primário. Com a réplica fora do ar (probe do /health ou
+// falha de conexão/5xx numa busca) as buscas vão ao primário e voltam à réplica depois de
+// replicaRecoverAfter probes bem-sucedidos.
+type ReplicaRouter struct {
+	primary    *typesense.Client
+	replica    *typesense.Client
+	replicaURL string
+	replicaKey string
+
+	mu        sync.RWMutex
+	healthy   bool
+	failures  int // probes consecutivos com falha
+	successes int // probes consecutivos bem-sucedidos desde a última falha
+	since     int64
+	lastError string
+
+	probe func(ctx context.Context) error
+}
+
+// NewReplicaRouter cria o roteador para a réplica em replicaURL. Sem replicaKey, a réplica usa a
+// mesma API key do primário.
+func NewReplicaRouter(primary *typesense.Client, replicaURL, replicaKey string) *ReplicaRouter {
+	replicaURL = strings.TrimSuffix(replicaURL, "/")
+	r := &ReplicaRouter{
+		primary:    primary,
+		replica:    typesense.NewClient(typesense.WithServer(replicaURL), typesense.WithAPIKey(replicaKey)),
+		replicaURL: replicaURL,
+		replicaKey: replicaKey,
+		healthy:    true,
+		since:      time.Now().Unix(),
+	}
+	httpClient := &http.Client{Timeout: 3 * time.Second}
+	r.probe = func(ctx context.Context) error {
+		return probeTypesenseNode(ctx, httpClient, replicaURL)
+	}
+	return r
+}
+
+// Client retorna o cliente das buscas públicas: a réplica quando saudável, senão o primário
+func (r *ReplicaRouter) Client() *typesense.Client {
+	if r == nil {
+		return nil
+	}
+	if r.Healthy() {
+		return r.replica
+	}
+	return r.primary
+}
+
+// Healthy indica se as buscas estão sendo direcionadas à réplica
+func (r *ReplicaRouter) Healthy() bool {
+	if r == nil {
+		return false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.healthy
+}
+
+// Node retorna a URL e a API key da réplica, para as buscas por HTTP direto
+func (r *ReplicaRouter) Node() (string, string) {
+	return r.replicaURL, r.replicaKey
+}
+
+// Do executa fn com o cliente das buscas públicas. Se a réplica falha com erro de conexão ou
+// 5xx, ela sai de uso e fn é repetida no primário.
+func (r *ReplicaRouter) Do(ctx context.Context, primary *typesense.Client, fn func(client *typesense.Client) error) error {
+	if !r.Healthy() {
+		return fn(primary)
+	}
+
+	err := fn(r.replica)
+	if err == nil || ctx.Err() != nil || !isReplicaFailure(err) {
+		return err
+	}
+	r.ReportFailure(err)
+	return fn(primary)
+}
+
+// ReportFailure tira a réplica de uso após uma falha de busca; ela volta depois de
+// replicaRecoverAfter probes bem-sucedidos
+func (r *ReplicaRouter) ReportFailure(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.successes = 0
+	r.lastError = err.Error()
+	if r.healthy {
+		log.Printf("[ReplicaRouter] réplica %s fora de uso, buscas no primário: %v", r.replicaURL, err)
+		r.healthy = false
+		r.since = time.Now().Unix()
+	}
+}
+
+// check aplica o resultado de um probe do /health da réplica
+func (r *ReplicaRouter) check(ctx context.Context) {
+	err := r.probe(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.successes = 0
+		r.failures++
+		r.lastError = err.Error()
+		if r.healthy && r.failures >= replicaFailAfter {
+			log.Printf("[ReplicaRouter] réplica %s fora de uso, buscas no primário: %v", r.replicaURL, err)
+			r.healthy = false
+			r.since = time.Now().Unix()
+		}
+		return
+	}
+
+	r.failures = 0
+	r.successes++
+	if !r.healthy && r.successes >= replicaRecoverAfter {
+		log.Printf("[ReplicaRouter] réplica %s recuperada, buscas de volta à réplica", r.replicaURL)
+		r.healthy = true
+		r.since = time.Now().Unix()
+		r.lastError = ""
+	}
+}
+
+// StartHealthCheck verifica o /health da réplica periodicamente
+func (r *ReplicaRouter) StartHealthCheck(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			r.check(ctx)
+			cancel()
+		}
+	}()
+}
+
+// Status retorna o estado do roteamento para o health check
+func (r *ReplicaRouter) Status() *models.ReplicaStatus {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return &models.ReplicaStatus{
+		URL:       r.replicaURL,
+		Healthy:   r.healthy,
+		Since:     r.since,
+		LastError: r.lastError,
+	}
+}
+
+// isReplicaFailure indica erros da réplica que justificam repetir a busca no primário: falhas
+// de conexão e 5xx (erros 4xx se repetiriam no primário)
+func isReplicaFailure(err error) bool {
+	var httpErr *typesense.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Status >= http.StatusInternalServerError
+	}
+	return true
+}
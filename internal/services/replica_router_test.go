@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/typesense/typesense-go/v3/typesense"
+)
+
+func TestReplicaRouterHealthCheck(t *testing.T) {
+	router := NewReplicaRouter(typesense.NewClient(typesense.WithServer("http://primary:8108")), "http://replica:8108/", "key")
+	var probeErr error
+	router.probe = func(ctx context.Context) error { return probeErr }
+	ctx := context.Background()
+
+	if url, _ := router.Node(); url != "http://replica:8108" {
+		t.Errorf("URL da réplica: esperado sem barra final, obtido %q", url)
+	}
+
+	// Uma falha isolada não tira a réplica de uso
+	probeErr = errors.New("connection refused")
+	router.check(ctx)
+	if !router.Healthy() {
+		t.Fatal("réplica fora de uso após uma única falha")
+	}
+	router.check(ctx)
+	if router.Healthy() || router.Client() != router.primary {
+		t.Fatal("réplica deveria estar fora de uso após falhas consecutivas")
+	}
+	if status := router.Status(); status.Healthy || status.LastError != "connection refused" {
+		t.Errorf("status inesperado: %+v", status)
+	}
+
+	// Volta à réplica após probes consecutivos bem-sucedidos
+	probeErr = nil
+	for i := 0; i < replicaRecoverAfter-1; i++ {
+		router.check(ctx)
+	}
+	if router.Healthy() {
+		t.Fatal("réplica voltou antes do número de probes bem-sucedidos")
+	}
+	router.check(ctx)
+	if !router.Healthy() || router.Client() != router.replica || router.Status().LastError != "" {
+		t.Errorf("réplica deveria ter voltado: %+v", router.Status())
+	}
+}
+
+func TestReplicaRouterDo(t *testing.T) {
+	primary := typesense.NewClient(typesense.WithServer("http://primary:8108"))
+	ctx := context.Background()
+
+	t.Run("5xx na réplica repete no primário", func(t *testing.T) {
+		router := NewReplicaRouter(primary, "http://replica:8108", "key")
+		var calls []*typesense.Client
+		err := router.Do(ctx, primary, func(client *typesense.Client) error {
+			calls = append(calls, client)
+			if client == router.replica {
+				return &typesense.HTTPError{Status: http.StatusServiceUnavailable}
+			}
+			return nil
+		})
+		if err != nil || len(calls) != 2 || calls[1] != primary {
+			t.Fatalf("esperada repetição no primário, obtido err=%v chamadas=%d", err, len(calls))
+		}
+		if router.Healthy() {
+			t.Error("réplica deveria sair de uso após a falha")
+		}
+	})
+
+	t.Run("4xx não repete", func(t *testing.T) {
+		router := NewReplicaRouter(primary, "http://replica:8108", "key")
+		calls := 0
+		err := router.Do(ctx, primary, func(client *typesense.Client) error {
+			calls++
+			return &typesense.HTTPError{Status: http.StatusNotFound}
+		})
+		if err == nil || calls != 1 || !router.Healthy() {
+			t.Errorf("esperado erro sem repetição, obtido err=%v chamadas=%d", err, calls)
+		}
+	})
+
+	t.Run("sem réplica usa o primário", func(t *testing.T) {
+		var router *ReplicaRouter
+		var used *typesense.Client
+		router.Do(ctx, primary, func(client *typesense.Client) error {
+			used = client
+			return nil
+		})
+		if used != primary || router.Healthy() || router.Status() != nil {
+			t.Error("roteador nil deveria usar o primário")
+		}
+	})
+}
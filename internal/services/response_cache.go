@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	responseCacheKeyPrefix     = "busca:response:"
+	responseCacheGenerationKey = "busca:response:generation"
+)
+
+// ResponseCache é o cache de respostas dos endpoints públicos (memória local + Redis opcional).
+// A invalidação é global: qualquer escrita em documentos incrementa a geração do cache,
+// o que descarta todas as entradas anteriores. As demais réplicas são avisadas pelo
+// InvalidationRelay e sincronizam a geração a partir do Redis.
+type ResponseCache struct {
+	local      *LRUCache
+	redis      *redis.Client
+	ttl        time.Duration
+	generation atomic.Int64
+
+	hits      atomic.Int64 // respostas servidas da memória
+	redisHits atomic.Int64 // respostas servidas do Redis
+	misses    atomic.Int64
+
+	// Falhas do Redis são contornadas lendo e gravando apenas em memória (bypass)
+	degradations *DegradationMonitor
+}
+
+// NewResponseCache cria o cache de respostas. Com redisClient nil, opera apenas em memória.
+func NewResponseCache(capacity int, ttl time.Duration, redisClient *redis.Client) *ResponseCache {
+	rc := &ResponseCache{
+		local: NewLRUCache(capacity),
+		redis: redisClient,
+		ttl:   ttl,
+	}
+	rc.local.StartCleanupRoutine(5 * time.Minute)
+
+	if redisClient != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		rc.syncGeneration(ctx)
+	}
+
+	return rc
+}
+
+// SetDegradationMonitor define o monitor que registra as falhas do Redis
+func (rc *ResponseCache) SetDegradationMonitor(monitor *DegradationMonitor) {
+	rc.degradations = monitor
+}
+
+// Generation retorna a geração atual do cache
+func (rc *ResponseCache) Generation() int64 {
+	return rc.generation.Load()
+}
+
+// Get busca uma resposta no cache local e, em caso de falta, no Redis
+func (rc *ResponseCache) Get(ctx context.Context, key string) ([]byte, bool) {
+	fullKey := rc.fullKey(rc.Generation(), key)
+
+	if cached := rc.local.Get(fullKey); cached != nil {
+		rc.hits.Add(1)
+		return cached.([]byte), true
+	}
+
+	if rc.redis == nil {
+		rc.misses.Add(1)
+		return nil, false
+	}
+
+	data, err := rc.redis.Get(ctx, fullKey).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			log.Printf("Erro ao ler cache de respostas no Redis: %v", err)
+			rc.degradations.Report(ctx, config.DependencyCache, err)
+		} else {
+			rc.degradations.Recover(config.DependencyCache)
+		}
+		rc.misses.Add(1)
+		return nil, false
+	}
+	rc.degradations.Recover(config.DependencyCache)
+	rc.redisHits.Add(1)
+
+	rc.local.Set(fullKey, data, rc.ttl)
+	return data, true
+}
+
+// Set armazena uma resposta calculada na geração informada. Se o cache foi invalidado
+// enquanto a resposta era calculada, ela é descartada para não reintroduzir dados antigos.
+func (rc *ResponseCache) Set(ctx context.Context, key string, generation int64, data []byte) {
+	if generation != rc.Generation() {
+		return
+	}
+
+	fullKey := rc.fullKey(generation, key)
+	rc.local.Set(fullKey, data, rc.ttl)
+
+	if rc.redis != nil {
+		if err := rc.redis.Set(ctx, fullKey, data, rc.ttl).Err(); err != nil {
+			log.Printf("Erro ao gravar cache de respostas no Redis: %v", err)
+			rc.degradations.Report(ctx, config.DependencyCache, err)
+		}
+	}
+}
+
+// Invalidate descarta todas as respostas em cache desta réplica e as armazenadas no Redis
+func (rc *ResponseCache) Invalidate(ctx context.Context) {
+	if rc.redis == nil {
+		rc.generation.Add(1)
+		rc.local.Clear()
+		return
+	}
+
+	generation, err := rc.redis.Incr(ctx, responseCacheGenerationKey).Result()
+	if err != nil {
+		log.Printf("Erro ao invalidar cache de respostas no Redis: %v", err)
+		rc.generation.Add(1)
+		rc.local.Clear()
+		return
+	}
+
+	rc.advance(generation)
+}
+
+// DeletePrefix descarta as respostas da geração atual cuja chave ([tenant:]path?query) começa
+// com o prefixo: as desta réplica e as armazenadas no Redis. As cópias em memória das demais
+// réplicas expiram com o TTL. Retorna quantas entradas foram removidas.
+func (rc *ResponseCache) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	fullPrefix := rc.fullKey(rc.Generation(), prefix)
+	removed := rc.local.DeletePrefix(fullPrefix)
+	if rc.redis == nil {
+		return removed, nil
+	}
+
+	var keys []string
+	iter := rc.redis.Scan(ctx, 0, redisGlobEscaper.Replace(fullPrefix)+"*", 500).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return removed, err
+	}
+	if len(keys) == 0 {
+		return removed, nil
+	}
+
+	deleted, err := rc.redis.Del(ctx, keys...).Result()
+	if err != nil {
+		return removed, err
+	}
+	// As entradas locais costumam ser cópias das do Redis
+	return max(removed, int(deleted)), nil
+}
+
+// redisGlobEscaper escapa os caracteres especiais dos padrões do SCAN
+var redisGlobEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// ResponseCacheStats contadores do cache de respostas desta réplica
+type ResponseCacheStats struct {
+	Hits      int64 `json:"hits"`       // respostas servidas da memória
+	RedisHits int64 `json:"redis_hits"` // respostas servidas do Redis
+	Misses    int64 `json:"misses"`
+	Entries   int   `json:"entries"` // entradas em memória
+}
+
+// Stats retorna os contadores do cache
+func (rc *ResponseCache) Stats() ResponseCacheStats {
+	return ResponseCacheStats{
+		Hits:      rc.hits.Load(),
+		RedisHits: rc.redisHits.Load(),
+		Misses:    rc.misses.Load(),
+		Entries:   rc.local.Size(),
+	}
+}
+
+// Capacity retorna a quantidade máxima de respostas em memória
+func (rc *ResponseCache) Capacity() int {
+	return rc.local.Capacity()
+}
+
+// HandleDocumentEvent invalida o cache a cada escrita publicada no EventBus. Eventos vindos
+// de outras réplicas já incrementaram a geração no Redis; basta sincronizá-la.
+func (rc *ResponseCache) HandleDocumentEvent(ctx context.Context, event DocumentEvent) {
+	if event.Origin != "" && rc.redis != nil {
+		rc.syncGeneration(ctx)
+		return
+	}
+	rc.Invalidate(ctx)
+}
+
+// syncGeneration lê a geração atual do Redis
+func (rc *ResponseCache) syncGeneration(ctx context.Context) {
+	generation, err := rc.redis.Get(ctx, responseCacheGenerationKey).Int64()
+	if err != nil {
+		if err != redis.Nil {
+			log.Printf("Erro ao ler geração do cache de respostas: %v", err)
+		}
+		return
+	}
+	rc.advance(generation)
+}
+
+// advance avança a geração local (nunca retrocede) e limpa o cache em memória
+func (rc *ResponseCache) advance(generation int64) {
+	for {
+		current := rc.generation.Load()
+		if generation <= current {
+			return
+		}
+		if rc.generation.CompareAndSwap(current, generation) {
+			rc.local.Clear()
+			return
+		}
+	}
+}
+
+func (rc *ResponseCache) fullKey(generation int64, key string) string {
+	return responseCacheKeyPrefix + strconv.FormatInt(generation, 10) + ":" + key
+}
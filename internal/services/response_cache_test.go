@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestResponseCacheInvalidatedByDocumentEvent(t *testing.T) {
+	ctx := context.Background()
+	cache := NewResponseCache(10, time.Minute, nil)
+	bus := NewEventBus()
+	bus.Subscribe(cache.HandleDocumentEvent)
+
+	cache.Set(ctx, "/api/v1/categories?", cache.Generation(), []byte(`{"categorias":[]}`))
+	if _, ok := cache.Get(ctx, "/api/v1/categories?"); !ok {
+		t.Fatal("esperado hit após Set")
+	}
+
+	bus.Publish(ctx, DocumentEvent{Type: DocumentUpdated, Collection: PrefRioServicesCollection, DocumentID: "1"})
+
+	if _, ok := cache.Get(ctx, "/api/v1/categories?"); ok {
+		t.Error("esperado miss após evento de escrita")
+	}
+}
+
+func TestResponseCacheDiscardsStaleGeneration(t *testing.T) {
+	ctx := context.Background()
+	cache := NewResponseCache(10, time.Minute, nil)
+
+	// resposta calculada antes de uma invalidação não deve ser armazenada
+	generation := cache.Generation()
+	cache.Invalidate(ctx)
+	cache.Set(ctx, "/api/v1/search/1?", generation, []byte(`{}`))
+
+	if _, ok := cache.Get(ctx, "/api/v1/search/1?"); ok {
+		t.Error("resposta de geração antiga não deveria ser armazenada")
+	}
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+// ErrSearchTimeout is returned when every collection exceeded its search budget
+var ErrSearchTimeout = errors.New("tempo limite da busca excedido em todas as collections")
+
+// ErrSearchFanOut is returned when a search would exceed the fan-out limits
+// (SEARCH_MAX_COLLECTIONS, SEARCH_MAX_FETCHED_DOCS)
+var ErrSearchFanOut = errors.New("busca excede os limites de fan-out")
+
+// SearchFanOutError tells which fan-out limit the search exceeds and how to fit it
+type SearchFanOutError struct {
+	Reason   string
+	Guidance string
+}
+
+func (e *SearchFanOutError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrSearchFanOut, e.Reason)
+}
+
+func (e *SearchFanOutError) Unwrap() error {
+	return ErrSearchFanOut
+}
+
+// fitFanOut checks the search against the fan-out limits and sets the per-collection window:
+// the fetched-documents budget is split across the collections, up to searchWindow each. A
+// search is rejected when it spans too many collections, when the split leaves each collection
+// fewer hits than a page, or when the requested page lies beyond the merged windows (deeper
+// pages are reached through the next_cursor).
+func (ss *SearchServiceV2) fitFanOut(req *models.SearchRequest, collections []string) error {
+	n := max(len(collections), 1)
+	if limit := ss.config.SearchMaxCollections; limit > 0 && n > limit {
+		return &SearchFanOutError{
+			Reason:   fmt.Sprintf("a busca consulta %d collections, acima do limite de %d", n, limit),
+			Guidance: fmt.Sprintf("informe no máximo %d collections em collections ou restrinja a busca com doc_types", limit),
+		}
+	}
+
+	req.Window = searchWindow
+	budget := ss.config.SearchMaxFetchedDocs
+	if budget <= 0 {
+		return nil
+	}
+	req.Window = min(searchWindow, budget/n)
+
+	if req.Window < req.PerPage {
+		return &SearchFanOutError{
+			Reason:   fmt.Sprintf("per_page=%d em %d collections excede o limite de %d documentos por busca", req.PerPage, n, budget),
+			Guidance: fmt.Sprintf("use per_page de no máximo %d ou consulte menos collections", max(req.Window, 1)),
+		}
+	}
+	if req.Cursor == "" && req.Page*req.PerPage > req.Window*n {
+		return &SearchFanOutError{
+			Reason:   fmt.Sprintf("a página %d com per_page=%d exige %d documentos, acima do limite de %d por busca", req.Page, req.PerPage, req.Page*req.PerPage, req.Window*n),
+			Guidance: "continue a paginação pelo next_cursor da página anterior em vez de pedir a página pelo número",
+		}
+	}
+	return nil
+}
+
+// performSearches searches each collection concurrently within its budget
+// (config.CollectionTimeout) instead of one multi-search that waits for the slowest
+// collection. Collections exceeding their budget are returned as dropped, with an empty
+// result in their position; any other failure fails the search.
+func (ss *SearchServiceV2) performSearches(ctx context.Context, collections []string, searches []api.MultiSearchCollectionParameters) (*api.MultiSearchResult, []string, error) {
+	result := &api.MultiSearchResult{Results: make([]api.MultiSearchResultItem, len(searches))}
+	timedOut := make([]bool, len(searches))
+	errs := make([]error, len(searches))
+
+	var wg sync.WaitGroup
+	for i := range searches {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			searchCtx := ctx
+			if budget := ss.config.CollectionTimeout(collections[i]); budget > 0 {
+				var cancel context.CancelFunc
+				searchCtx, cancel = context.WithTimeout(ctx, budget)
+				defer cancel()
+			}
+
+			res, err := ss.multiSearch(searchCtx, api.MultiSearchSearchesParameter{
+				Searches: []api.MultiSearchCollectionParameters{searches[i]},
+			})
+			switch {
+			case err == nil && len(res.Results) > 0:
+				result.Results[i] = res.Results[0]
+			case err == nil:
+			case ctx.Err() == nil && errors.Is(searchCtx.Err(), context.DeadlineExceeded):
+				timedOut[i] = true
+			default:
+				errs[i] = err
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, nil, err
+	}
+
+	var dropped []string
+	for i, coll := range collections {
+		if timedOut[i] {
+			dropped = append(dropped, coll)
+		}
+	}
+	if len(dropped) > 0 {
+		log.Printf("[SearchV2] collections dropped after exceeding their search budget: %v", dropped)
+		if len(dropped) == len(collections) {
+			return nil, dropped, fmt.Errorf("%w: %v", ErrSearchTimeout, dropped)
+		}
+	}
+	return result, dropped, nil
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+func TestPerformSearchesDropsSlowCollections(t *testing.T) {
+	delays := map[string]time.Duration{"fast": 0, "slow": time.Second, "tuned": 30 * time.Millisecond}
+	ss := &SearchServiceV2{
+		config: &config.Config{
+			SearchCollectionTimeoutMs: 20,
+			CollectionConfigs: map[string]*config.CollectionConfig{
+				"tuned": {TimeoutMs: 500},
+			},
+		},
+		multiSearch: func(ctx context.Context, searches api.MultiSearchSearchesParameter) (*api.MultiSearchResult, error) {
+			coll := searches.Searches[0].Collection
+			select {
+			case <-time.After(delays[*coll]):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			return &api.MultiSearchResult{Results: []api.MultiSearchResultItem{{Found: pointer.Int(len(*coll))}}}, nil
+		},
+	}
+
+	collections := []string{"fast", "slow", "tuned"}
+	searches := make([]api.MultiSearchCollectionParameters, len(collections))
+	for i, coll := range collections {
+		searches[i].Collection = pointer.String(coll)
+	}
+
+	start := time.Now()
+	result, dropped, err := ss.performSearches(context.Background(), collections, searches)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("a busca esperou a collection lenta: %v", elapsed)
+	}
+	if len(dropped) != 1 || dropped[0] != "slow" {
+		t.Errorf("esperada apenas slow descartada, obtido %v", dropped)
+	}
+	if found := result.Results[0].Found; found == nil || *found != 4 {
+		t.Errorf("resultado de fast fora da posição: %+v", result.Results[0])
+	}
+	if result.Results[1].Found != nil || result.Results[1].Hits != nil {
+		t.Errorf("collection descartada deveria ter resultado vazio: %+v", result.Results[1])
+	}
+	if found := result.Results[2].Found; found == nil || *found != 5 {
+		t.Errorf("collection com tempo limite próprio deveria ser aguardada: %+v", result.Results[2])
+	}
+
+	// Todas as collections descartadas: erro de tempo limite
+	if _, _, err := ss.performSearches(context.Background(), collections[1:2], searches[1:2]); !errors.Is(err, ErrSearchTimeout) {
+		t.Errorf("esperado ErrSearchTimeout, obtido %v", err)
+	}
+
+	// Falhas que não são de tempo limite continuam falhando a busca
+	ss.multiSearch = func(ctx context.Context, searches api.MultiSearchSearchesParameter) (*api.MultiSearchResult, error) {
+		return nil, errors.New("503 Service Unavailable")
+	}
+	if _, _, err := ss.performSearches(context.Background(), collections, searches); err == nil || errors.Is(err, ErrSearchTimeout) {
+		t.Errorf("esperado erro da busca, obtido %v", err)
+	}
+}
+
+func TestFitFanOut(t *testing.T) {
+	ss := &SearchServiceV2{config: &config.Config{SearchMaxFetchedDocs: 1000, SearchMaxCollections: 12}}
+	collections := func(n int) []string { return make([]string, n) }
+
+	tests := []struct {
+		name       string
+		req        models.SearchRequest
+		n          int
+		wantWindow int
+		wantErr    bool
+	}{
+		{"poucas collections mantêm a janela", models.SearchRequest{Page: 1, PerPage: 10}, 2, searchWindow, false},
+		{"muitas collections reduzem a janela", models.SearchRequest{Page: 1, PerPage: 10}, 5, 200, false},
+		{"collections acima do limite", models.SearchRequest{Page: 1, PerPage: 10}, 13, 0, true},
+		{"per_page acima da parte de cada collection", models.SearchRequest{Page: 1, PerPage: 100}, 11, 0, true},
+		{"página além do orçamento", models.SearchRequest{Page: 11, PerPage: 100}, 4, 0, true},
+		{"cursor dispensa o limite de página", models.SearchRequest{Page: 11, PerPage: 100, Cursor: "c"}, 4, searchWindow, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			err := ss.fitFanOut(&req, collections(tt.n))
+			if tt.wantErr {
+				var fanOut *SearchFanOutError
+				if !errors.As(err, &fanOut) || !errors.Is(err, ErrSearchFanOut) || fanOut.Guidance == "" {
+					t.Fatalf("esperado SearchFanOutError com orientação, obtido %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			if req.Window != tt.wantWindow {
+				t.Errorf("esperada janela %d, obtida %d", tt.wantWindow, req.Window)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+	"golang.org/x/sync/singleflight"
+)
+
+// SearchCacheTTL validade das respostas em cache de um tipo de busca: até Fresh a resposta é
+// servida diretamente; até Fresh+Stale é servida imediatamente enquanto é recalculada em
+// segundo plano (stale-while-revalidate), se a query for quente
+type SearchCacheTTL struct {
+	Fresh time.Duration
+	Stale time.Duration
+}
+
+// DefaultSearchCacheTTLs validades padrão por tipo de busca. Buscas com LLM/embeddings são mais
+// caras e ficam mais tempo em cache.
+func DefaultSearchCacheTTLs() map[models.SearchType]SearchCacheTTL {
+	return map[models.SearchType]SearchCacheTTL{
+		models.SearchTypeKeyword:  {Fresh: time.Minute, Stale: 5 * time.Minute},
+		models.SearchTypeSemantic: {Fresh: 5 * time.Minute, Stale: 30 * time.Minute},
+		models.SearchTypeHybrid:   {Fresh: 5 * time.Minute, Stale: 30 * time.Minute},
+		models.SearchTypeAI:       {Fresh: 10 * time.Minute, Stale: time.Hour},
+	}
+}
+
+// LoadSearchCacheTTLs combina as validades padrão com as configuradas (SEARCH_CACHE_TTLS).
+// Fresh 0 desabilita o cache do tipo de busca.
+func LoadSearchCacheTTLs(overrides map[string]*config.SearchCacheTTL) map[models.SearchType]SearchCacheTTL {
+	ttls := DefaultSearchCacheTTLs()
+	for searchType, override := range overrides {
+		if override == nil {
+			continue
+		}
+		ttls[models.SearchType(searchType)] = SearchCacheTTL{
+			Fresh: time.Duration(override.Fresh) * time.Second,
+			Stale: time.Duration(override.Stale) * time.Second,
+		}
+	}
+	return ttls
+}
+
+// SearchCacheStats contadores do cache de resultados de busca
+type SearchCacheStats struct {
+	Hits        int64 `json:"hits"`        // respostas dentro da validade
+	StaleHits   int64 `json:"stale_hits"`  // respostas vencidas servidas durante a revalidação
+	Misses      int64 `json:"misses"`      // buscas executadas na hora
+	Revalidated int64 `json:"revalidated"` // revalidações em segundo plano concluídas
+	Bypassed    int64 `json:"bypassed"`    // buscas com no_cache, recalculadas sem consultar o cache
+	Entries     int   `json:"entries"`
+}
+
+type searchCacheEntry struct {
+	response   *models.SearchResponse
+	freshUntil time.Time
+	staleUntil time.Time
+	hits       atomic.Int64
+}
+
+// SearchCache guarda os resultados de busca por query normalizada + filtros. Queries quentes
+// (com ao menos hotHits acessos) continuam sendo servidas após vencer, enquanto uma única
+// revalidação por chave roda em segundo plano; as demais voltam a ser executadas. Escritas em
+// documentos descartam todo o cache.
+type SearchCache struct {
+	entries    *LRUCache
+	ttls       map[models.SearchType]SearchCacheTTL
+	hotHits    int64
+	generation atomic.Int64
+	refreshing singleflight.Group
+
+	mu    sync.Mutex
+	stats SearchCacheStats
+}
+
+// NewSearchCache cria o cache com a capacidade e as validades por tipo de busca informadas.
+// Tipos sem validade configurada não são armazenados.
+func NewSearchCache(capacity int, ttls map[models.SearchType]SearchCacheTTL, hotHits int) *SearchCache {
+	sc := &SearchCache{
+		entries: NewLRUCache(capacity),
+		ttls:    ttls,
+		hotHits: int64(hotHits),
+	}
+	sc.entries.StartCleanupRoutine(5 * time.Minute)
+	return sc
+}
+
+// Get retorna a resposta em cache ou executa fetch. fetch deve ser seguro para execução em
+// segundo plano (o contexto da revalidação não é cancelado com a requisição).
+func (sc *SearchCache) Get(ctx context.Context, req *models.SearchRequest, fetch func(context.Context) (*models.SearchResponse, error)) (*models.SearchResponse, error) {
+	if sc == nil {
+		return fetch(ctx)
+	}
+	ttl, ok := sc.ttls[req.Type]
+	if !ok || ttl.Fresh <= 0 {
+		return fetch(ctx)
+	}
+
+	key, err := searchCacheKey(ctx, req)
+	if err != nil {
+		return fetch(ctx)
+	}
+
+	// Com no_cache a busca é recalculada e a resposta nova substitui a armazenada
+	if CacheBypassed(ctx) {
+		sc.count(func(s *SearchCacheStats) { s.Bypassed++ })
+		generation := sc.generation.Load()
+		response, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sc.store(key, generation, ttl, response, 1)
+		return response, nil
+	}
+
+	now := time.Now()
+	if cached, ok := sc.entries.Get(key).(*searchCacheEntry); ok {
+		hits := cached.hits.Add(1)
+		switch {
+		case now.Before(cached.freshUntil):
+			sc.count(func(s *SearchCacheStats) { s.Hits++ })
+			return cached.response, nil
+		case now.Before(cached.staleUntil) && hits >= sc.hotHits:
+			sc.count(func(s *SearchCacheStats) { s.StaleHits++ })
+			sc.revalidate(ctx, key, ttl, hits, fetch)
+			return cached.response, nil
+		}
+	}
+
+	sc.count(func(s *SearchCacheStats) { s.Misses++ })
+	generation := sc.generation.Load()
+	response, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sc.store(key, generation, ttl, response, 1)
+	return response, nil
+}
+
+// revalidate recalcula a resposta em segundo plano, no máximo uma vez por chave ao mesmo tempo
+func (sc *SearchCache) revalidate(ctx context.Context, key string, ttl SearchCacheTTL, hits int64, fetch func(context.Context) (*models.SearchResponse, error)) {
+	generation := sc.generation.Load()
+	bg := context.WithoutCancel(ctx)
+
+	go sc.refreshing.Do(key, func() (interface{}, error) {
+		response, err := fetch(bg)
+		if err != nil {
+			log.Printf("[SearchCache] Erro ao revalidar busca em cache: %v", err)
+			return nil, err
+		}
+		sc.store(key, generation, ttl, response, hits)
+		sc.count(func(s *SearchCacheStats) { s.Revalidated++ })
+		return nil, nil
+	})
+}
+
+// store grava a resposta se o cache não foi invalidado enquanto ela era calculada
+func (sc *SearchCache) store(key string, generation int64, ttl SearchCacheTTL, response *models.SearchResponse, hits int64) {
+	// Respostas degradadas não são reaproveitadas após a dependência se recuperar
+	if generation != sc.generation.Load() || len(response.DegradedMode) > 0 {
+		return
+	}
+
+	now := time.Now()
+	entry := &searchCacheEntry{
+		response:   response,
+		freshUntil: now.Add(ttl.Fresh),
+		staleUntil: now.Add(ttl.Fresh + ttl.Stale),
+	}
+	entry.hits.Store(hits)
+	sc.entries.Set(key, entry, ttl.Fresh+ttl.Stale)
+}
+
+// HandleDocumentEvent descarta todas as respostas após escritas em documentos
+func (sc *SearchCache) HandleDocumentEvent(ctx context.Context, event DocumentEvent) {
+	sc.generation.Add(1)
+	sc.entries.Clear()
+}
+
+// DeleteQueryPrefix descarta as respostas das buscas cuja query normalizada começa com o
+// prefixo (normalizado da mesma forma), em todos os tenants, retornando quantas foram removidas
+func (sc *SearchCache) DeleteQueryPrefix(prefix string) int {
+	return sc.entries.DeletePrefix(normalizeCacheQuery(prefix))
+}
+
+// Capacity retorna a quantidade máxima de respostas em cache
+func (sc *SearchCache) Capacity() int {
+	return sc.entries.Capacity()
+}
+
+// Stats retorna os contadores do cache
+func (sc *SearchCache) Stats() SearchCacheStats {
+	sc.mu.Lock()
+	stats := sc.stats
+	sc.mu.Unlock()
+	stats.Entries = sc.entries.Size()
+	return stats
+}
+
+func (sc *SearchCache) count(update func(*SearchCacheStats)) {
+	sc.mu.Lock()
+	update(&sc.stats)
+	sc.mu.Unlock()
+}
+
+// searchCacheKey combina a query normalizada (sem acentos, caixa e espaços extras), o tenant
+// e os demais parâmetros que influenciam o resultado. A query vem primeiro para permitir
+// descartar as respostas por prefixo da query (DeleteQueryPrefix).
+func searchCacheKey(ctx context.Context, req *models.SearchRequest) (string, error) {
+	normalized := *req
+	normalized.Query = normalizeCacheQuery(req.Query)
+
+	key, err := coalescingKey(&normalized)
+	if err != nil {
+		return "", err
+	}
+	if tenantID, ok := tenant.FromContext(ctx); ok {
+		key = tenantID + "|" + key
+	}
+	return normalized.Query + "\x00" + key, nil
+}
+
+func normalizeCacheQuery(query string) string {
+	return strings.Join(strings.Fields(utils.NormalizarCategoria(query)), " ")
+}
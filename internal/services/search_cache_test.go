@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestSearchCacheNormalizesQuery(t *testing.T) {
+	ttls := map[models.SearchType]SearchCacheTTL{models.SearchTypeKeyword: {Fresh: time.Minute}}
+	sc := NewSearchCache(10, ttls, 1)
+
+	var calls atomic.Int64
+	fetch := func(context.Context) (*models.SearchResponse, error) {
+		calls.Add(1)
+		return &models.SearchResponse{TotalCount: 1}, nil
+	}
+
+	for _, query := range []string{"Matrícula Escolar", "  matricula   escolar "} {
+		if _, err := sc.Get(context.Background(), &models.SearchRequest{Query: query, Type: models.SearchTypeKeyword}, fetch); err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("queries equivalentes deveriam compartilhar a entrada, fetch chamado %d vezes", calls.Load())
+	}
+
+	// Filtros diferentes não compartilham a entrada
+	sc.Get(context.Background(), &models.SearchRequest{Query: "matricula escolar", Type: models.SearchTypeKeyword, Page: 2}, fetch)
+	if calls.Load() != 2 {
+		t.Errorf("filtros diferentes deveriam gerar nova busca, fetch chamado %d vezes", calls.Load())
+	}
+
+	// Escritas em documentos descartam o cache
+	sc.HandleDocumentEvent(context.Background(), DocumentEvent{})
+	sc.Get(context.Background(), &models.SearchRequest{Query: "matricula escolar", Type: models.SearchTypeKeyword}, fetch)
+	if calls.Load() != 3 {
+		t.Errorf("cache deveria ser descartado após escrita, fetch chamado %d vezes", calls.Load())
+	}
+}
+
+func TestSearchCacheStaleWhileRevalidate(t *testing.T) {
+	ttls := map[models.SearchType]SearchCacheTTL{models.SearchTypeHybrid: {Fresh: time.Millisecond, Stale: time.Minute}}
+	sc := NewSearchCache(10, ttls, 2)
+	req := &models.SearchRequest{Query: "iptu", Type: models.SearchTypeHybrid}
+
+	var version atomic.Int64
+	refreshed := make(chan struct{}, 10)
+	fetch := func(context.Context) (*models.SearchResponse, error) {
+		v := version.Add(1)
+		if v > 1 {
+			refreshed <- struct{}{}
+		}
+		return &models.SearchResponse{TotalCount: int(v)}, nil
+	}
+
+	sc.Get(context.Background(), req, fetch)
+	time.Sleep(5 * time.Millisecond)
+
+	// Segundo acesso torna a query quente: a resposta vencida é servida e revalidada em segundo plano
+	response, _ := sc.Get(context.Background(), req, fetch)
+	if response.TotalCount != 1 {
+		t.Fatalf("esperada a resposta vencida, obtido %d", response.TotalCount)
+	}
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("revalidação em segundo plano não executada")
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	stats := sc.Stats()
+	if stats.StaleHits != 1 || stats.Misses != 1 {
+		t.Errorf("contadores inesperados: %+v", stats)
+	}
+}
+
+func TestSearchCacheColdQueryNotServedStale(t *testing.T) {
+	ttls := map[models.SearchType]SearchCacheTTL{models.SearchTypeKeyword: {Fresh: time.Millisecond, Stale: time.Minute}}
+	sc := NewSearchCache(10, ttls, 5)
+	req := &models.SearchRequest{Query: "iptu", Type: models.SearchTypeKeyword}
+
+	var calls atomic.Int64
+	fetch := func(context.Context) (*models.SearchResponse, error) {
+		return &models.SearchResponse{TotalCount: int(calls.Add(1))}, nil
+	}
+
+	sc.Get(context.Background(), req, fetch)
+	time.Sleep(5 * time.Millisecond)
+	response, _ := sc.Get(context.Background(), req, fetch)
+	if response.TotalCount != 2 {
+		t.Errorf("query fria vencida deveria ser executada novamente, obtido %d", response.TotalCount)
+	}
+}
+
+func TestSearchCacheBypassAndDeleteQueryPrefix(t *testing.T) {
+	ttls := map[models.SearchType]SearchCacheTTL{models.SearchTypeKeyword: {Fresh: time.Minute}}
+	sc := NewSearchCache(10, ttls, 1)
+
+	var calls atomic.Int64
+	fetch := func(context.Context) (*models.SearchResponse, error) {
+		return &models.SearchResponse{TotalCount: int(calls.Add(1))}, nil
+	}
+	iptu := &models.SearchRequest{Query: "IPTU 2025", Type: models.SearchTypeKeyword}
+	multa := &models.SearchRequest{Query: "multa", Type: models.SearchTypeKeyword}
+	sc.Get(context.Background(), iptu, fetch)
+	sc.Get(context.Background(), multa, fetch)
+
+	// no_cache recalcula e substitui a entrada
+	response, _ := sc.Get(WithCacheBypass(context.Background()), iptu, fetch)
+	if response.TotalCount != 3 {
+		t.Fatalf("busca com bypass deveria ser recalculada, obtido %d", response.TotalCount)
+	}
+	if response, _ = sc.Get(context.Background(), iptu, fetch); response.TotalCount != 3 {
+		t.Errorf("resposta recalculada deveria substituir a armazenada, obtido %d", response.TotalCount)
+	}
+	if stats := sc.Stats(); stats.Bypassed != 1 {
+		t.Errorf("esperado 1 bypass, obtidos %d", stats.Bypassed)
+	}
+
+	// O prefixo é normalizado como as queries
+	if removed := sc.DeleteQueryPrefix("Iptu"); removed != 1 {
+		t.Errorf("esperada 1 remoção, obtidas %d", removed)
+	}
+	sc.Get(context.Background(), multa, fetch)
+	if calls.Load() != 3 {
+		t.Errorf("buscas fora do prefixo deveriam continuar em cache, fetch chamado %d vezes", calls.Load())
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+)
+
+// CoalescingStats contadores de coalescência de buscas idênticas concorrentes
+type CoalescingStats struct {
+	Executed int64 `json:"executed"` // buscas efetivamente executadas
+	Shared   int64 `json:"shared"`   // requisições que participaram de uma execução compartilhada (inclui a que a iniciou)
+}
+
+// searchCoalescer agrupa buscas idênticas simultâneas (ex: picos de um link de campanha)
+// para que compartilhem uma única geração de embedding e uma única ida ao Typesense
+type searchCoalescer struct {
+	group    singleflight.Group
+	executed atomic.Int64
+	shared   atomic.Int64
+	counter  metric.Int64Counter
+}
+
+func newSearchCoalescer() *searchCoalescer {
+	counter, _ := otel.Meter("search").Int64Counter(
+		"search.coalesced_requests",
+		metric.WithDescription("Requisições de busca por resultado da coalescência (shared=true quando reaproveitaram outra execução)"),
+	)
+	return &searchCoalescer{counter: counter}
+}
+
+// do executa fn uma única vez por chave entre chamadas concorrentes. A execução compartilhada
+// não é cancelada se a requisição que a iniciou desistir; cada chamador apenas para de esperar.
+func (sc *searchCoalescer) do(ctx context.Context, req *models.SearchRequest, fn func(context.Context) (*models.SearchResponse, error)) (*models.SearchResponse, error) {
+	key, err := coalescingKey(req)
+	if err != nil {
+		return fn(ctx)
+	}
+	if tenantID, ok := tenant.FromContext(ctx); ok {
+		// buscas de tenants diferentes nunca compartilham resultado
+		key = tenantID + "|" + key
+	}
+
+	ch := sc.group.DoChan(key, func() (interface{}, error) {
+		sc.executed.Add(1)
+		return fn(context.WithoutCancel(ctx))
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ErrSearchCanceled
+	case res := <-ch:
+		if res.Shared {
+			sc.shared.Add(1)
+		}
+		if sc.counter != nil {
+			sc.counter.Add(ctx, 1, metric.WithAttributes(attribute.Bool("shared", res.Shared)))
+		}
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("search.coalesced", res.Shared))
+
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*models.SearchResponse), nil
+	}
+}
+
+func (sc *searchCoalescer) stats() CoalescingStats {
+	return CoalescingStats{Executed: sc.executed.Load(), Shared: sc.shared.Load()}
+}
+
+// coalescingKey serializa todos os parâmetros que influenciam o resultado da busca
+func coalescingKey(req *models.SearchRequest) (string, error) {
+	data, err := json.Marshal(struct {
+		*models.SearchRequest
+		ParsedCollections []string
+	}{req, req.ParsedCollections})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
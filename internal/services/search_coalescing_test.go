@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestSearchCoalescerSharesIdenticalRequests(t *testing.T) {
+	sc := newSearchCoalescer()
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	fn := func(ctx context.Context) (*models.SearchResponse, error) {
+		calls.Add(1)
+		<-release
+		return &models.SearchResponse{TotalCount: 1}, nil
+	}
+
+	const n = 5
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := &models.SearchRequest{Query: "iptu", Type: models.SearchTypeHybrid, Page: 1, PerPage: 10}
+			if resp, err := sc.do(context.Background(), req, fn); err != nil || resp.TotalCount != 1 {
+				t.Errorf("resposta inesperada: %v, %v", resp, err)
+			}
+		}()
+	}
+
+	// aguarda todas as requisições chegarem antes de liberar a execução
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("esperada 1 execução, obtidas %d", calls.Load())
+	}
+	if stats := sc.stats(); stats.Executed != 1 || stats.Shared != n {
+		t.Errorf("estatísticas inesperadas: %+v", stats)
+	}
+}
+
+func TestSearchCoalescerKeyDependsOnParameters(t *testing.T) {
+	a, _ := coalescingKey(&models.SearchRequest{Query: "iptu", Type: models.SearchTypeHybrid, Page: 1})
+	b, _ := coalescingKey(&models.SearchRequest{Query: "iptu", Type: models.SearchTypeHybrid, Page: 2})
+	if a == b {
+		t.Error("páginas diferentes não devem compartilhar execução")
+	}
+}
+
+func TestSearchCoalescerStopsWaitingOnCancel(t *testing.T) {
+	sc := newSearchCoalescer()
+	release := make(chan struct{})
+	defer close(release)
+
+	fn := func(ctx context.Context) (*models.SearchResponse, error) {
+		<-release
+		return &models.SearchResponse{TotalCount: 1}, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := &models.SearchRequest{Query: "iptu", Type: models.SearchTypeHybrid, Page: 1, PerPage: 10}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := sc.do(ctx, req, fn)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != ErrSearchCanceled {
+			t.Errorf("esperado ErrSearchCanceled, obtido %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("a requisição cancelada continuou aguardando a busca")
+	}
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+// searchWindow is how many hits are fetched from each collection per v2 search. The merged
+// windows are paginated in memory; pages beyond them are reached through the next_cursor.
+const searchWindow = 250
+
+// ErrInvalidSearchCursor is returned for malformed cursors and cursors issued for another search
+var ErrInvalidSearchCursor = errors.New("cursor de busca inválido")
+
+// setSearchWindow fetches the collection's window (req.Window hits, searchWindow when unset):
+// its first hits or, when continuing from a cursor, the hits after the collection's offset
+func setSearchWindow(params *api.MultiSearchCollectionParameters, collName string, req *models.SearchRequest) {
+	window := req.Window
+	if window <= 0 {
+		window = searchWindow
+	}
+	if req.Cursor != "" && req.ParsedCursor != nil {
+		params.Offset = pointer.Int(req.ParsedCursor.Offsets[collName])
+		params.Limit = pointer.Int(window)
+		return
+	}
+	params.Page = pointer.Int(1)
+	params.PerPage = pointer.Int(window)
+}
+
+// windowPositions maps each merged hit to its position in its collection's window, before
+// thresholds and the recency boost change the merged order
+func windowPositions(docs []*models.UnifiedDocument) map[*models.UnifiedDocument]int {
+	positions := make(map[*models.UnifiedDocument]int, len(docs))
+	next := make(map[string]int)
+	for _, doc := range docs {
+		positions[doc] = next[doc.Collection]
+		next[doc.Collection]++
+	}
+	return positions
+}
+
+// pageResults returns the requested page of the merged results and its page info. When more
+// hits remain in any collection, the page info carries the cursor continuing right after the
+// page; a cursor page is always the first page of the windows fetched from its offsets.
+func (ss *SearchServiceV2) pageResults(req *models.SearchRequest, result *api.MultiSearchResult, collections []string, positions map[*models.UnifiedDocument]int, filtered []*models.UnifiedDocument) ([]*models.UnifiedDocument, models.PageInfo) {
+	found := foundByCollection(result, collections)
+
+	if req.Cursor == "" || req.ParsedCursor == nil {
+		paged := ss.paginateDocuments(filtered, req.Page, req.PerPage)
+		info := models.NewPageInfo(req.Page, req.PerPage, len(filtered))
+		if req.ParsedCursor == nil || len(paged) == 0 {
+			return paged, info
+		}
+		consumed := filtered[:(req.Page-1)*req.PerPage+len(paged)]
+		info.HasNext = false
+		if next := nextSearchCursor(req.ParsedCursor, collections, found, positions, consumed, filtered); next != nil {
+			info.HasNext = true
+			info.NextCursor = encodeSearchCursor(next)
+		}
+		return paged, info
+	}
+
+	paged := ss.paginateDocuments(filtered, 1, req.PerPage)
+
+	// The total counts the hits left after the cursor, before thresholds
+	remaining := 0
+	for _, coll := range collections {
+		remaining += max(found[coll]-req.ParsedCursor.Offsets[coll], 0)
+	}
+	info := models.PageInfo{
+		TotalPages: req.Page - 1 + (remaining+req.PerPage-1)/req.PerPage,
+		HasPrev:    true,
+	}
+	if len(paged) == 0 {
+		return paged, info
+	}
+	if next := nextSearchCursor(req.ParsedCursor, collections, found, positions, paged, filtered); next != nil {
+		info.HasNext = true
+		info.NextCursor = encodeSearchCursor(next)
+	}
+	return paged, info
+}
+
+// foundByCollection returns how many hits each collection found
+func foundByCollection(result *api.MultiSearchResult, collections []string) map[string]int {
+	found := make(map[string]int, len(collections))
+	for i, res := range result.Results {
+		if i < len(collections) && res.Found != nil {
+			found[collections[i]] = int(*res.Found)
+		}
+	}
+	return found
+}
+
+// nextSearchCursor returns the cursor after the consumed results, or nil when every collection
+// is exhausted. Each collection advances past its last consumed hit. Hits come sorted by the
+// score the thresholds apply to, so a collection whose window had hits dropped by a threshold
+// is exhausted once its kept hits are consumed.
+func nextSearchCursor(current *models.SearchCursor, collections []string, found map[string]int, positions map[*models.UnifiedDocument]int, consumed, filtered []*models.UnifiedDocument) *models.SearchCursor {
+	fetched := make(map[string]int)
+	for doc := range positions {
+		fetched[doc.Collection]++
+	}
+	kept := make(map[string]int)
+	for _, doc := range filtered {
+		kept[doc.Collection]++
+	}
+
+	next := &models.SearchCursor{
+		Page:        current.Page + 1,
+		Offsets:     make(map[string]int, len(collections)),
+		Fingerprint: current.Fingerprint,
+	}
+	more := false
+	for _, coll := range collections {
+		base := current.Offsets[coll]
+		offset, used := base, 0
+		for _, doc := range consumed {
+			if doc.Collection == coll {
+				offset = max(offset, base+positions[doc]+1)
+				used++
+			}
+		}
+		if kept[coll] < fetched[coll] && used == kept[coll] {
+			offset = found[coll]
+		}
+
+		next.Offsets[coll] = offset
+		if offset < found[coll] {
+			more = true
+		}
+	}
+	if !more {
+		return nil
+	}
+	return next
+}
+
+// searchFingerprint identifies the search parameters a cursor may continue: everything but the
+// pagination itself
+func searchFingerprint(req *models.SearchRequest) string {
+	key := *req
+	key.Page, key.PerPage, key.Cursor = 0, 0, ""
+	data, _ := json.Marshal(key)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+func encodeSearchCursor(cursor *models.SearchCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeSearchCursor decodes the cursor and checks that it was issued for the same search
+func decodeSearchCursor(raw, fingerprint string) (*models.SearchCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidSearchCursor
+	}
+	var cursor models.SearchCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Page < 1 {
+		return nil, ErrInvalidSearchCursor
+	}
+	for _, offset := range cursor.Offsets {
+		if offset < 0 {
+			return nil, ErrInvalidSearchCursor
+		}
+	}
+	if cursor.Fingerprint != fingerprint {
+		return nil, fmt.Errorf("%w: os parâmetros da busca mudaram", ErrInvalidSearchCursor)
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestSearchCursorRoundTrip(t *testing.T) {
+	req := &models.SearchRequest{Query: "iptu", Type: models.SearchTypeKeyword, Page: 3}
+	fingerprint := searchFingerprint(req)
+
+	cursor := &models.SearchCursor{Page: 4, Offsets: map[string]int{"a": 260, "b": 12}, Fingerprint: fingerprint}
+	decoded, err := decodeSearchCursor(encodeSearchCursor(cursor), fingerprint)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, cursor) {
+		t.Errorf("esperado %+v, obtido %+v", cursor, decoded)
+	}
+
+	// A paginação não altera a impressão digital; os demais parâmetros sim
+	paged := *req
+	paged.Page, paged.PerPage, paged.Cursor = 9, 50, "x"
+	if searchFingerprint(&paged) != fingerprint {
+		t.Error("paginação alterou a impressão digital")
+	}
+	other := *req
+	other.Query = "itbi"
+	if _, err := decodeSearchCursor(encodeSearchCursor(cursor), searchFingerprint(&other)); !errors.Is(err, ErrInvalidSearchCursor) {
+		t.Errorf("esperado ErrInvalidSearchCursor para outra busca, obtido %v", err)
+	}
+
+	for _, raw := range []string{"!!", encodeSearchCursor(&models.SearchCursor{Page: 0, Fingerprint: fingerprint}),
+		encodeSearchCursor(&models.SearchCursor{Page: 2, Offsets: map[string]int{"a": -1}, Fingerprint: fingerprint})} {
+		if _, err := decodeSearchCursor(raw, fingerprint); !errors.Is(err, ErrInvalidSearchCursor) {
+			t.Errorf("cursor %q: esperado ErrInvalidSearchCursor, obtido %v", raw, err)
+		}
+	}
+}
+
+func TestNextSearchCursor(t *testing.T) {
+	docs := []*models.UnifiedDocument{
+		{ID: "a1", Collection: "a"}, {ID: "a2", Collection: "a"}, {ID: "a3", Collection: "a"},
+		{ID: "b1", Collection: "b"}, {ID: "b2", Collection: "b"},
+	}
+	positions := windowPositions(docs)
+	collections := []string{"a", "b"}
+	current := &models.SearchCursor{Page: 2, Offsets: map[string]int{"a": 250, "b": 250}, Fingerprint: "f"}
+
+	t.Run("avança após o último consumido", func(t *testing.T) {
+		consumed := []*models.UnifiedDocument{docs[0], docs[3], docs[1]}
+		next := nextSearchCursor(current, collections, map[string]int{"a": 600, "b": 300}, positions, consumed, docs)
+		expected := &models.SearchCursor{Page: 3, Offsets: map[string]int{"a": 252, "b": 251}, Fingerprint: "f"}
+		if !reflect.DeepEqual(next, expected) {
+			t.Errorf("esperado %+v, obtido %+v", expected, next)
+		}
+	})
+
+	t.Run("collection sem resultados consumidos mantém o offset", func(t *testing.T) {
+		next := nextSearchCursor(current, collections, map[string]int{"a": 600, "b": 300}, positions, docs[:1], docs)
+		if next.Offsets["b"] != 250 {
+			t.Errorf("offset de b: esperado 250, obtido %d", next.Offsets["b"])
+		}
+	})
+
+	t.Run("threshold esgota a collection", func(t *testing.T) {
+		kept := []*models.UnifiedDocument{docs[0], docs[3], docs[4]}
+		next := nextSearchCursor(current, collections, map[string]int{"a": 600, "b": 300}, positions, kept[:2], kept)
+		if next.Offsets["a"] != 600 || next.Offsets["b"] != 251 {
+			t.Errorf("offsets inesperados: %+v", next.Offsets)
+		}
+	})
+
+	t.Run("fim dos resultados", func(t *testing.T) {
+		if next := nextSearchCursor(current, collections, map[string]int{"a": 253, "b": 252}, positions, docs, docs); next != nil {
+			t.Errorf("esperado nil, obtido %+v", next)
+		}
+	})
+}
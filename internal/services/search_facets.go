@@ -0,0 +1,134 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+// SearchFacetFields are the fields v2 search can count results by (facet_by)
+var SearchFacetFields = []string{"tema_geral", "orgao_gestor", "publico_especifico", "is_free"}
+
+// ErrInvalidFacetField is returned when facet_by names a field outside SearchFacetFields
+var ErrInvalidFacetField = errors.New("campo de faceta inválido")
+
+// searchFacetValues caps the values returned per facet, in each collection and after merging
+const searchFacetValues = 50
+
+// ParseFacetBy splits the facet_by parameter, dropping blanks and repeated fields
+func ParseFacetBy(facetBy string) ([]string, error) {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(facetBy, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || seen[field] {
+			continue
+		}
+		if !isSearchFacetField(field) {
+			return nil, fmt.Errorf("%w: %s (válidos: %s)", ErrInvalidFacetField, field, strings.Join(SearchFacetFields, ", "))
+		}
+		seen[field] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func isSearchFacetField(field string) bool {
+	for _, allowed := range SearchFacetFields {
+		if field == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// collectionFacetFields returns the requested fields the collection declares in facet_fields.
+// The services collection, whose schema is defined here, facets on every SearchFacetFields
+// field unless configured otherwise.
+func collectionFacetFields(collName string, collConfig *config.CollectionConfig, requested []string) []string {
+	available := SearchFacetFields
+	if collConfig != nil && len(collConfig.FacetFields) > 0 {
+		available = collConfig.FacetFields
+	} else if collName != PrefRioServicesCollection {
+		return nil
+	}
+
+	var fields []string
+	for _, field := range requested {
+		for _, candidate := range available {
+			if field == candidate {
+				fields = append(fields, field)
+				break
+			}
+		}
+	}
+	return fields
+}
+
+// applyFacets asks the collection for the counts of the requested fields it can facet on
+func applyFacets(params *api.MultiSearchCollectionParameters, collName string, collConfig *config.CollectionConfig, req *models.SearchRequest) {
+	fields := collectionFacetFields(collName, collConfig, req.ParsedFacetBy)
+	if len(fields) == 0 {
+		return
+	}
+	params.FacetBy = pointer.String(strings.Join(fields, ","))
+	params.MaxFacetValues = pointer.Int(searchFacetValues)
+}
+
+// mergeFacetCounts sums the facet counts of every collection by field and value. Every requested
+// field is returned, in the requested order, even when no collection had counts for it.
+func mergeFacetCounts(result *api.MultiSearchResult, requested []string) []models.SearchFacet {
+	if len(requested) == 0 {
+		return nil
+	}
+
+	counts := make(map[string]map[string]int, len(requested))
+	for _, field := range requested {
+		counts[field] = make(map[string]int)
+	}
+	for _, res := range result.Results {
+		if res.FacetCounts == nil {
+			continue
+		}
+		for _, facet := range *res.FacetCounts {
+			if facet.FieldName == nil || facet.Counts == nil {
+				continue
+			}
+			values, ok := counts[*facet.FieldName]
+			if !ok {
+				continue
+			}
+			for _, count := range *facet.Counts {
+				if count.Value == nil || count.Count == nil {
+					continue
+				}
+				values[*count.Value] += *count.Count
+			}
+		}
+	}
+
+	facets := make([]models.SearchFacet, 0, len(requested))
+	for _, field := range requested {
+		values := make([]models.FacetValueCount, 0, len(counts[field]))
+		for value, count := range counts[field] {
+			values = append(values, models.FacetValueCount{Value: value, Count: count})
+		}
+		sort.Slice(values, func(i, j int) bool {
+			if values[i].Count != values[j].Count {
+				return values[i].Count > values[j].Count
+			}
+			return values[i].Value < values[j].Value
+		})
+		if len(values) > searchFacetValues {
+			values = values[:searchFacetValues]
+		}
+		facets = append(facets, models.SearchFacet{Field: field, Counts: values})
+	}
+	return facets
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+func TestParseFacetBy(t *testing.T) {
+	fields, err := ParseFacetBy(" tema_geral,is_free,,tema_geral ")
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if expected := []string{"tema_geral", "is_free"}; !reflect.DeepEqual(fields, expected) {
+		t.Errorf("esperado %v, obtido %v", expected, fields)
+	}
+
+	if fields, err := ParseFacetBy(""); err != nil || fields != nil {
+		t.Errorf("facet_by vazio: esperado nil, obtido %v (%v)", fields, err)
+	}
+	if _, err := ParseFacetBy("tema_geral,embedding"); !errors.Is(err, ErrInvalidFacetField) {
+		t.Errorf("esperado ErrInvalidFacetField, obtido %v", err)
+	}
+}
+
+func TestCollectionFacetFields(t *testing.T) {
+	requested := []string{"tema_geral", "is_free"}
+
+	if got := collectionFacetFields(PrefRioServicesCollection, &config.CollectionConfig{}, requested); !reflect.DeepEqual(got, requested) {
+		t.Errorf("serviços: esperado %v, obtido %v", requested, got)
+	}
+	if got := collectionFacetFields(HubSearchCollection, &config.CollectionConfig{}, requested); got != nil {
+		t.Errorf("collection sem facet_fields não deve ter facetas, obtido %v", got)
+	}
+	configured := &config.CollectionConfig{FacetFields: []string{"is_free"}}
+	if got := collectionFacetFields(HubSearchCollection, configured, requested); !reflect.DeepEqual(got, []string{"is_free"}) {
+		t.Errorf("esperado [is_free], obtido %v", got)
+	}
+}
+
+func TestMergeFacetCounts(t *testing.T) {
+	var result api.MultiSearchResult
+	raw := `{"results": [
+		{"facet_counts": [
+			{"field_name": "tema_geral", "counts": [{"value": "saude", "count": 3}, {"value": "educacao", "count": 5}]},
+			{"field_name": "is_free", "counts": [{"value": "true", "count": 6}]}
+		]},
+		{},
+		{"facet_counts": [
+			{"field_name": "tema_geral", "counts": [{"value": "saude", "count": 4}, {"value": "transporte", "count": 2}]}
+		]}
+	]}`
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	facets := mergeFacetCounts(&result, []string{"tema_geral", "is_free", "orgao_gestor"})
+	expected := []models.SearchFacet{
+		{Field: "tema_geral", Counts: []models.FacetValueCount{{Value: "saude", Count: 7}, {Value: "educacao", Count: 5}, {Value: "transporte", Count: 2}}},
+		{Field: "is_free", Counts: []models.FacetValueCount{{Value: "true", Count: 6}}},
+		{Field: "orgao_gestor", Counts: []models.FacetValueCount{}},
+	}
+	if !reflect.DeepEqual(facets, expected) {
+		t.Errorf("esperado %+v, obtido %+v", expected, facets)
+	}
+
+	if facets := mergeFacetCounts(&result, nil); facets != nil {
+		t.Errorf("sem facet_by não deve haver facetas, obtido %+v", facets)
+	}
+}
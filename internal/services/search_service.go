@@ -2,6 +2,7 @@ package services
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
@@ -14,7 +15,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
 	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
 	"github.com/typesense/typesense-go/v3/typesense"
 	"github.com/typesense/typesense-go/v3/typesense/api"
 	"go.opentelemetry.io/otel"
@@ -27,6 +31,9 @@ var (
 	ErrSearchCanceled = errors.New("busca cancelada")
 )
 
+// analysisCachePrefix prefixo das chaves de análise de query no cache
+const analysisCachePrefix = CacheAnalysis + ":"
+
 // SearchService fornece busca unificada de alta qualidade
 type SearchService struct {
 	client           *typesense.Client
@@ -38,6 +45,28 @@ type SearchService struct {
 	typesenseURL string
 	typesenseKey string
 	httpClient   *http.Client
+	coalescer    *searchCoalescer
+	sensitive    *SensitiveQueryClassifier
+	resultCache  *SearchCache
+	translator   *QueryTranslator
+	// Nós adicionais do Typesense (retry_other_node) e política de degradação
+	typesenseNodes []string
+	degradations   *DegradationMonitor
+	// Réplica somente leitura das buscas (opcional)
+	replicas *ReplicaRouter
+	// Mini-índice em memória para quando o Typesense está inacessível (opcional)
+	fallback *FallbackIndex
+	// Limites por órgão gestor e tema no topo da primeira página
+	diversity DiversityConfig
+	// Curva do recency boost (padrão: config.DefaultRecencyConfig)
+	recency *config.RecencyConfig
+	// Fator aplicado ao score dos serviços em destaque (≤ 1 desabilita)
+	destaqueBoost float64
+	// Alpha padrão da busca híbrida (0 = 0.3) e thresholds padrão das requisições que os omitem
+	hybridAlpha       float64
+	defaultThresholds config.ScoreThresholds
+	// Configuração recarregável; cada busca usa um único snapshot (opcional)
+	configs *config.Store
 }
 
 // NewSearchService cria um novo serviço de busca
@@ -63,11 +92,14 @@ func NewSearchService(
 		typesenseURL:     typesenseURL,
 		typesenseKey:     typesenseKey,
 		httpClient:       &http.Client{Timeout: 60 * time.Second},
+		coalescer:        newSearchCoalescer(),
 	}
 }
 
 // Search executa busca baseada no tipo especificado
 func (ss *SearchService) Search(ctx context.Context, req *models.SearchRequest) (*models.SearchResponse, error) {
+	ss = ss.withCurrentConfig()
+
 	// Validações
 	if req.Page < 1 {
 		req.Page = 1
@@ -75,20 +107,279 @@ func (ss *SearchService) Search(ctx context.Context, req *models.SearchRequest)
 	if req.PerPage < 1 || req.PerPage > 100 {
 		req.PerPage = 10
 	}
+	applyDefaultThresholds(req, ss.defaultThresholds)
+
+	// Buscas em outros idiomas são traduzidas para o português (ofensivas não passam por LLM)
+	var queryMeta *models.QueryMeta
+	if !IsAbusiveQuery(ctx) {
+		queryMeta = ss.translator.Prepare(ctx, req)
+	}
+
+	// Buscas sensíveis recebem contatos de emergência e não têm a query registrada
+	safety := ss.sensitive.Classify(req.Query)
+	if safety != nil {
+		ctx = WithSensitiveTopic(ctx, safety.Topic)
+	}
+
+	// Buscas ofensivas não passam por LLM nem embeddings: apenas busca textual
+	if IsAbusiveQuery(ctx) {
+		req.Type = models.SearchTypeKeyword
+		req.GenerateScores = false
+	}
+
+	// Buscas idênticas simultâneas compartilham a mesma execução
+	execute := func(ctx context.Context) (*models.SearchResponse, error) {
+		return ss.coalescer.do(ctx, req, func(ctx context.Context) (*models.SearchResponse, error) {
+			response, err := ss.search(ctx, req)
+			if err == nil {
+				response.Safety = safety
+				response.PageInfo = models.NewPageInfo(response.Page, response.PerPage, response.TotalCount)
+			}
+			return response, err
+		})
+	}
+
+	// Buscas sensíveis não são mantidas em cache
+	var response *models.SearchResponse
+	var err error
+	if safety != nil {
+		response, err = execute(ctx)
+	} else {
+		response, err = ss.resultCache.Get(ctx, req, execute)
+	}
+	if err != nil || queryMeta == nil {
+		return response, err
+	}
+
+	// A resposta pode ser compartilhada (cache, coalescência): o idioma e a anotação da
+	// tradução vão numa cópia
+	withMeta := *response
+	withMeta.QueryMeta = queryMeta
+	if rewrite := translationAnnotation(queryMeta); rewrite != nil {
+		withMeta.Interventions = append([]string{rewrite.Key("")}, response.Interventions...)
+		if req.DebugAnnotations {
+			withMeta.Results = make([]*models.ServiceDocument, len(response.Results))
+			for i, doc := range response.Results {
+				annotated := *doc
+				annotated.Annotations = append([]models.ResultAnnotation{*rewrite}, doc.Annotations...)
+				withMeta.Results[i] = &annotated
+			}
+		}
+	}
+	return &withMeta, nil
+}
+
+// SetConfigStore faz as buscas usarem a configuração recarregável: diversidade, recency,
+// alpha e thresholds padrão são lidos do snapshot atual a cada busca
+func (ss *SearchService) SetConfigStore(store *config.Store) {
+	ss.configs = store
+}
+
+// withCurrentConfig retorna uma cópia do serviço com os parâmetros do snapshot atual da
+// configuração, usada do início ao fim da busca
+func (ss *SearchService) withCurrentConfig() *SearchService {
+	cfg := ss.configs.Current()
+	if cfg == nil {
+		return ss
+	}
+
+	bound := *ss
+	bound.diversity = DiversityConfig{
+		MaxPerOrgao: cfg.DiversityMaxPerOrgao,
+		MaxPerTema:  cfg.DiversityMaxPerTema,
+		Window:      cfg.DiversityWindow,
+	}
+	recency := cfg.GetCollectionConfig(CollectionName).GetRecency()
+	bound.recency = &recency
+	bound.hybridAlpha = cfg.HybridAlpha()
+	bound.destaqueBoost = cfg.DestaqueBoost()
+	bound.defaultThresholds = cfg.SearchDefaultThresholds
+	return &bound
+}
+
+// defaultAlpha retorna o alpha padrão da busca híbrida
+func (ss *SearchService) defaultAlpha() float64 {
+	if ss.hybridAlpha > 0 {
+		return ss.hybridAlpha
+	}
+	return 0.3
+}
+
+// SetResultCache define o cache de resultados de busca (nil desabilita)
+func (ss *SearchService) SetResultCache(cache *SearchCache) {
+	ss.resultCache = cache
+}
+
+// ResultCacheStats retorna os contadores do cache de resultados (nil se desabilitado)
+func (ss *SearchService) ResultCacheStats() *SearchCacheStats {
+	if ss.resultCache == nil {
+		return nil
+	}
+	stats := ss.resultCache.Stats()
+	return &stats
+}
+
+// SetEmbeddingProvider substitui o provider de embeddings (ex: camada de embeddings pré-computados)
+func (ss *SearchService) SetEmbeddingProvider(provider EmbeddingProvider) {
+	ss.embeddingService = provider
+}
+
+// SetQueryTranslator define o tradutor das buscas feitas em outros idiomas (nil desabilita)
+func (ss *SearchService) SetQueryTranslator(translator *QueryTranslator) {
+	ss.translator = translator
+}
+
+// SetDegradationMonitor define a política de degradação aplicada às falhas de dependências
+func (ss *SearchService) SetDegradationMonitor(monitor *DegradationMonitor) {
+	ss.degradations = monitor
+}
+
+// SetTypesenseNodes define os nós adicionais do Typesense usados quando o principal falha
+func (ss *SearchService) SetTypesenseNodes(nodes []string) {
+	ss.typesenseNodes = nodes
+}
+
+// SetReplicaRouter direciona as buscas à réplica somente leitura enquanto ela está saudável
+func (ss *SearchService) SetReplicaRouter(router *ReplicaRouter) {
+	ss.replicas = router
+}
+
+// ReplicaStatus retorna o estado da réplica das buscas (nil se não configurada)
+func (ss *SearchService) ReplicaStatus() *models.ReplicaStatus {
+	return ss.replicas.Status()
+}
+
+// SetFallbackIndex define o mini-índice em memória que responde às buscas quando o Typesense
+// está inacessível (nil desabilita)
+func (ss *SearchService) SetFallbackIndex(index *FallbackIndex) {
+	ss.fallback = index
+}
+
+// SetDiversity define os limites de resultados do mesmo órgão gestor e tema no topo da
+// primeira página
+func (ss *SearchService) SetDiversity(diversity DiversityConfig) {
+	ss.diversity = diversity
+}
+
+// SetRecency define a curva de decaimento do recency boost da collection de serviços
+func (ss *SearchService) SetRecency(recency config.RecencyConfig) {
+	ss.recency = &recency
+}
+
+// SetDestaqueBoost define o fator aplicado ao score dos serviços com fixar_destaque vigente
+// (≤ 1 desabilita)
+func (ss *SearchService) SetDestaqueBoost(boost float64) {
+	ss.destaqueBoost = boost
+}
+
+// SetSensitiveQueryClassifier define o classificador de buscas sensíveis
+func (ss *SearchService) SetSensitiveQueryClassifier(classifier *SensitiveQueryClassifier) {
+	ss.sensitive = classifier
+}
+
+// CoalescingStats retorna os contadores de coalescência de buscas
+func (ss *SearchService) CoalescingStats() CoalescingStats {
+	return ss.coalescer.stats()
+}
+
+// search executa a busca baseada no tipo especificado
+func (ss *SearchService) search(ctx context.Context, req *models.SearchRequest) (*models.SearchResponse, error) {
+	var response *models.SearchResponse
+	var err error
+
+	ctx = withDegradedModes(ctx)
 
-	// Executa busca baseada no tipo
 	switch req.Type {
 	case models.SearchTypeKeyword:
-		return ss.KeywordSearch(ctx, req)
+		response, err = ss.KeywordSearch(ctx, req)
 	case models.SearchTypeSemantic:
-		return ss.SemanticSearch(ctx, req)
+		response, err = ss.SemanticSearch(ctx, req)
 	case models.SearchTypeHybrid:
-		return ss.HybridSearch(ctx, req)
+		response, err = ss.HybridSearch(ctx, req)
 	case models.SearchTypeAI:
-		return ss.AIAgentSearch(ctx, req)
+		response, err = ss.AIAgentSearch(ctx, req)
 	default:
 		return nil, fmt.Errorf("tipo de busca inválido: %s", req.Type)
 	}
+	if err != nil {
+		// Com o Typesense inacessível, o mini-índice responde em vez do erro
+		if !ss.fallback.Ready() || ctx.Err() != nil || !isTypesenseUnreachable(err) {
+			return nil, err
+		}
+		response = ss.fallbackSearch(ctx, req)
+	}
+
+	// Na primeira página, evita que um único órgão ou tema domine os primeiros resultados
+	annotations := resultAnnotations{}
+	if req.Page <= 1 {
+		before := resultPositions(response.Results)
+		if demoted := diversify(response.Results, ss.diversity); demoted > 0 {
+			if response.Metadata == nil {
+				response.Metadata = map[string]interface{}{}
+			}
+			response.Metadata["diversity_demoted"] = demoted
+			annotations.demoted(response.Results, before, models.AnnotationRuleDiversity, nil)
+		}
+	}
+
+	// Serviços fora da janela de disponibilidade (quando incluídos) e descontinuados vão para o fim da página
+	before := resultPositions(response.Results)
+	flagAvailability(response.Results, time.Now().Unix())
+	annotations.demoted(response.Results, before, models.AnnotationRuleAvailability, func(doc *models.ServiceDocument) bool {
+		return doc.Metadata != nil && doc.Metadata["availability"] != nil
+	})
+	before = resultPositions(response.Results)
+	demoteDeprecated(response.Results)
+	annotations.demoted(response.Results, before, models.AnnotationRuleDeprecated, isDeprecated)
+	response.Interventions = annotations.apply(response.Results, req.DebugAnnotations)
+
+	// Falha de nó do Typesense é contornada pelo cliente; a resposta informa a degradação
+	if ss.degradations.IsActive(config.DependencyTypesense) {
+		markDegraded(ctx, config.DependencyTypesense+":"+ss.degradations.Action(config.DependencyTypesense))
+	}
+	response.DegradedMode = degradedModesFrom(ctx)
+	return response, nil
+}
+
+// fallbackSearch responde pelo mini-índice em memória: busca apenas textual no nome e no resumo
+// dos serviços publicados do tenant, sem os filtros de entidades e bairros da requisição. A
+// janela de disponibilidade e os descontinuados seguem as regras da busca normal.
+func (ss *SearchService) fallbackSearch(ctx context.Context, req *models.SearchRequest) *models.SearchResponse {
+	excludeAgentOnly := req.ExcludeAgentExclusive != nil && *req.ExcludeAgentExclusive
+	hits := ss.fallback.Search(ctx, req.Query, excludeAgentOnly)
+
+	now := time.Now().Unix()
+	if !req.IncludeInactive && !req.IncludeOutOfWindow {
+		inWindow := hits[:0]
+		for _, hit := range hits {
+			if AvailabilityState(getInt64(hit.fields, "available_from"), getInt64(hit.fields, "available_until"), now) == AvailabilityOpen {
+				inWindow = append(inWindow, hit)
+			}
+		}
+		hits = inWindow
+	}
+
+	start := min((req.Page-1)*req.PerPage, len(hits))
+	end := min(start+req.PerPage, len(hits))
+	results := make([]*models.ServiceDocument, 0, end-start)
+	for _, hit := range hits[start:end] {
+		doc := ss.transformDocument(hit.fields)
+		doc.Metadata["fallback_score"] = hit.score
+		results = append(results, doc)
+	}
+	flagAvailability(results, now)
+	demoteDeprecated(results)
+
+	markDegraded(ctx, fallbackDegradedMode)
+	return &models.SearchResponse{
+		Results:       results,
+		TotalCount:    len(hits),
+		FilteredCount: len(hits),
+		Page:          req.Page,
+		PerPage:       req.PerPage,
+		SearchType:    models.SearchTypeKeyword,
+		Metadata:      map[string]interface{}{"fallback_index_built_at": ss.fallback.BuiltAt()},
+	}
 }
 
 // ============================================================================
@@ -100,7 +391,7 @@ func (ss *SearchService) KeywordSearch(ctx context.Context, req *models.SearchRe
 	defer span.End()
 
 	span.SetAttributes(
-		attribute.String("search.query", req.Query),
+		queryAttribute(ctx, req.Query),
 		attribute.Int("search.page", req.Page),
 		attribute.Int("search.per_page", req.PerPage),
 	)
@@ -108,12 +399,16 @@ func (ss *SearchService) KeywordSearch(ctx context.Context, req *models.SearchRe
 	prioritizeExact := true
 	prioritizePos := true
 
+	// Referências legais e protocolos são normalizados como no search_content
+	query := utils.NormalizeQueryTokens(req.Query)
+
 	searchParams := &api.SearchCollectionParams{
-		Q: &req.Query,
-		// Campos ordenados por relevância
-		QueryBy: stringPtr("nome_servico,resumo,descricao_completa,documentos_necessarios,instrucoes_solicitante"),
+		Q: &query,
+		// Campos ordenados por relevância; o resumo sem markdown evita casar sintaxe e URLs de
+		// links, e search_content traz os tokens normalizados
+		QueryBy: stringPtr("nome_servico,resumo_plaintext,descricao_completa,documentos_necessarios,instrucoes_solicitante,search_content"),
 		// Pesos: nome do serviço é mais importante
-		QueryByWeights:          stringPtr("4,3,2,1,1"),
+		QueryByWeights:          stringPtr("4,3,2,1,1,1"),
 		PerPage:                 intPtr(req.PerPage),
 		Page:                    intPtr(req.Page),
 		PrioritizeExactMatch:    &prioritizeExact,
@@ -124,13 +419,18 @@ func (ss *SearchService) KeywordSearch(ctx context.Context, req *models.SearchRe
 	}
 
 	// Aplicar filtros (status, exclusive_for_agents)
-	if filterBy := buildFilterBy(req); filterBy != "" {
+	if filterBy := buildFilterBy(ctx, req); filterBy != "" {
 		searchParams.FilterBy = stringPtr(filterBy)
 	}
 
 	// Executar busca
 	_, typesenseSpan := otel.Tracer("search").Start(ctx, "Typesense.KeywordSearch")
-	result, err := ss.client.Collection(CollectionName).Documents().Search(ctx, searchParams)
+	var result *api.SearchResult
+	err := ss.replicas.Do(ctx, ss.client, func(client *typesense.Client) error {
+		var err error
+		result, err = client.Collection(CollectionName).Documents().Search(ctx, searchParams)
+		return err
+	})
 	typesenseSpan.End()
 
 	if err != nil {
@@ -139,6 +439,15 @@ func (ss *SearchService) KeywordSearch(ctx context.Context, req *models.SearchRe
 		return nil, fmt.Errorf("erro ao executar busca keyword: %w", err)
 	}
 
+	// Sem resultados, tenta pela chave fonética do nome (grafias como "auvara" para "alvará")
+	var phoneticKey string
+	if result.Found == nil || *result.Found == 0 {
+		if phonetic, key := ss.phoneticSearch(ctx, searchParams, req.Query); phonetic != nil {
+			span.AddEvent("Phonetic fallback")
+			result, phoneticKey = phonetic, key
+		}
+	}
+
 	// Transformar resultados
 	docs, err := ss.transformResults(result)
 	if err != nil {
@@ -175,10 +484,47 @@ func (ss *SearchService) KeywordSearch(ctx context.Context, req *models.SearchRe
 	if filterMeta != nil {
 		response.Metadata = filterMeta
 	}
+	if phoneticKey != "" {
+		if response.Metadata == nil {
+			response.Metadata = map[string]interface{}{}
+		}
+		response.Metadata["phonetic_fallback"] = true
+		response.Metadata["phonetic_query"] = phoneticKey
+	}
 
 	return response, nil
 }
 
+// phoneticSearch repete a busca keyword pela chave fonética da query no campo
+// nome_servico_fonetico. Retorna nil sem resultados ou se o índice ainda não tem o campo.
+func (ss *SearchService) phoneticSearch(ctx context.Context, params *api.SearchCollectionParams, query string) (*api.SearchResult, string) {
+	key := utils.PhoneticKey(query)
+	if key == "" {
+		return nil, ""
+	}
+
+	phonetic := *params
+	phonetic.Q = stringPtr(key)
+	phonetic.QueryBy = stringPtr("nome_servico_fonetico")
+	phonetic.QueryByWeights = nil
+	phonetic.NumTypos = stringPtr("1")
+
+	var result *api.SearchResult
+	err := ss.replicas.Do(ctx, ss.client, func(client *typesense.Client) error {
+		var err error
+		result, err = client.Collection(CollectionName).Documents().Search(ctx, &phonetic)
+		return err
+	})
+	if err != nil {
+		log.Printf("Aviso: busca fonética falhou: %v", err)
+		return nil, ""
+	}
+	if result.Found == nil || *result.Found == 0 {
+		return nil, ""
+	}
+	return result, key
+}
+
 // ============================================================================
 // SEMANTIC SEARCH - Busca vetorial pura
 // ============================================================================
@@ -188,7 +534,7 @@ func (ss *SearchService) SemanticSearch(ctx context.Context, req *models.SearchR
 	defer span.End()
 
 	span.SetAttributes(
-		attribute.String("search.query", req.Query),
+		queryAttribute(ctx, req.Query),
 		attribute.Int("search.page", req.Page),
 		attribute.Int("search.per_page", req.PerPage),
 	)
@@ -198,6 +544,11 @@ func (ss *SearchService) SemanticSearch(ctx context.Context, req *models.SearchR
 		return nil, fmt.Errorf("busca semântica requer serviço de embeddings configurado")
 	}
 
+	if err := ss.checkEmbeddingCompatibility(ctx); err != nil {
+		span.SetStatus(codes.Error, "Embedding model mismatch")
+		return nil, err
+	}
+
 	// Gerar embedding da query com timeout
 	ctxEmbed, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
@@ -210,7 +561,7 @@ func (ss *SearchService) SemanticSearch(ctx context.Context, req *models.SearchR
 		span.RecordError(err)
 		if errors.Is(err, context.Canceled) || errors.Is(ctxEmbed.Err(), context.Canceled) {
 			span.SetStatus(codes.Error, "Embedding generation canceled")
-			log.Printf("Semantic search canceled for query: %s", req.Query)
+			log.Printf("Semantic search canceled for query: %s", loggableQuery(ctx, req.Query))
 			return nil, ErrSearchCanceled
 		}
 		span.SetStatus(codes.Error, "Embedding generation failed")
@@ -232,7 +583,7 @@ func (ss *SearchService) HybridSearch(ctx context.Context, req *models.SearchReq
 	defer span.End()
 
 	span.SetAttributes(
-		attribute.String("search.query", req.Query),
+		queryAttribute(ctx, req.Query),
 		attribute.Int("search.page", req.Page),
 		attribute.Int("search.per_page", req.PerPage),
 	)
@@ -242,6 +593,12 @@ func (ss *SearchService) HybridSearch(ctx context.Context, req *models.SearchReq
 	var err error
 
 	if ss.embeddingService != nil {
+		// Não misturar vetores de modelos diferentes: recusar em vez de degradar silenciosamente
+		if err := ss.checkEmbeddingCompatibility(ctx); err != nil {
+			span.SetStatus(codes.Error, "Embedding model mismatch")
+			return nil, err
+		}
+
 		ctxEmbed, cancel := context.WithTimeout(ctx, 15*time.Second)
 		defer cancel()
 
@@ -250,10 +607,15 @@ func (ss *SearchService) HybridSearch(ctx context.Context, req *models.SearchReq
 		embeddingSpan.End()
 
 		if err != nil {
+			if ctx.Err() == nil && ss.degradations.Report(ctx, config.DependencyGemini, err) == config.DegradeFail {
+				span.SetStatus(codes.Error, "Embedding generation failed")
+				return nil, fmt.Errorf("%w: erro ao gerar embedding: %v", ErrDependencyUnavailable, err)
+			}
 			span.AddEvent("Fallback to KeywordSearch due to embedding failure")
 			log.Printf("Hybrid search fallback to keyword: %v", err)
 			return ss.KeywordSearch(ctx, req)
 		}
+		ss.degradations.Recover(config.DependencyGemini)
 
 		span.SetAttributes(attribute.Int("search.embedding.dimensions", len(embedding)))
 	} else {
@@ -263,7 +625,7 @@ func (ss *SearchService) HybridSearch(ctx context.Context, req *models.SearchReq
 	}
 
 	// Alpha configurável (default 0.3 = 70% texto + 30% vetor)
-	alpha := 0.3
+	alpha := ss.defaultAlpha()
 	if req.Alpha > 0 && req.Alpha <= 1.0 {
 		alpha = req.Alpha
 	}
@@ -273,6 +635,62 @@ func (ss *SearchService) HybridSearch(ctx context.Context, req *models.SearchReq
 	return ss.executeVectorSearch(ctx, req, embedding, alpha)
 }
 
+// searchNodes retorna o nó principal do Typesense seguido dos nós adicionais, quando a
+// política para falhas do Typesense é retry_other_node. Com a réplica saudável, ela vem antes
+// do primário.
+func (ss *SearchService) searchNodes() []string {
+	nodes := []string{ss.typesenseURL}
+	if ss.replicas.Healthy() {
+		replicaURL, _ := ss.replicas.Node()
+		nodes = []string{replicaURL, ss.typesenseURL}
+	}
+	if ss.degradations.Action(config.DependencyTypesense) == config.DegradeRetryOtherNode {
+		nodes = append(nodes, ss.typesenseNodes...)
+	}
+	return nodes
+}
+
+// isReplicaNode indica se o nó é a réplica das buscas
+func (ss *SearchService) isReplicaNode(node string) bool {
+	if ss.replicas == nil {
+		return false
+	}
+	replicaURL, _ := ss.replicas.Node()
+	return node == replicaURL
+}
+
+// nodeKey retorna a API key do nó: a da réplica ou a do primário (compartilhada pelos demais nós)
+func (ss *SearchService) nodeKey(node string) string {
+	if ss.isReplicaNode(node) {
+		if _, replicaKey := ss.replicas.Node(); replicaKey != "" {
+			return replicaKey
+		}
+	}
+	return ss.typesenseKey
+}
+
+// postMultiSearch envia o body ao endpoint multi_search do nó
+func (ss *SearchService) postMultiSearch(ctx context.Context, node string, jsonBody []byte) (*http.Response, error) {
+	url := fmt.Sprintf("%s/multi_search", node)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição: %w", err)
+	}
+
+	// Headers
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-TYPESENSE-API-KEY", ss.nodeKey(node))
+
+	_, httpSpan := otel.Tracer("search").Start(ctx, "HTTP.POST.MultiSearch")
+	defer httpSpan.End()
+	httpSpan.SetAttributes(
+		attribute.String("http.method", "POST"),
+		attribute.String("http.url", url),
+	)
+	return ss.httpClient.Do(httpReq)
+}
+
 // executeVectorSearch executa busca com vector query usando HTTP POST direto
 func (ss *SearchService) executeVectorSearch(
 	ctx context.Context,
@@ -305,15 +723,15 @@ func (ss *SearchService) executeVectorSearch(
 	}
 
 	// Aplicar filtros (status, exclusive_for_agents)
-	if filterBy := buildFilterBy(req); filterBy != "" {
+	if filterBy := buildFilterBy(ctx, req); filterBy != "" {
 		search["filter_by"] = filterBy
 	}
 
 	// Se alpha < 1.0, incluir busca textual híbrida
 	if alpha < 1.0 {
-		search["q"] = req.Query
-		search["query_by"] = "nome_servico,resumo,descricao_completa"
-		search["query_by_weights"] = "4,3,2"
+		search["q"] = utils.NormalizeQueryTokens(req.Query)
+		search["query_by"] = "nome_servico,resumo,descricao_completa,search_content"
+		search["query_by_weights"] = "4,3,2,1"
 	}
 
 	// Montar multi_search body
@@ -327,28 +745,27 @@ func (ss *SearchService) executeVectorSearch(
 		return nil, fmt.Errorf("erro ao serializar body: %w", err)
 	}
 
-	// Montar URL do endpoint multi_search
-	url := fmt.Sprintf("%s/multi_search", ss.typesenseURL)
-
-	// Criar requisição POST
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonBody))
-	if err != nil {
-		return nil, fmt.Errorf("erro ao criar requisição: %w", err)
+	// Executar requisição no nó principal e, com retry_other_node, nos demais nós em caso de
+	// erro de conexão ou 5xx
+	var resp *http.Response
+	nodes := ss.searchNodes()
+	for i, node := range nodes {
+		resp, err = ss.postMultiSearch(ctx, node, jsonBody)
+		if i == len(nodes)-1 || ctx.Err() != nil || (err == nil && resp.StatusCode < http.StatusInternalServerError) {
+			break
+		}
+		if err == nil {
+			resp.Body.Close()
+			err = fmt.Errorf("status %d", resp.StatusCode)
+		}
+		span.AddEvent("Retry MultiSearch on next Typesense node")
+		if ss.isReplicaNode(node) {
+			ss.replicas.ReportFailure(err)
+			continue
+		}
+		ss.degradations.Report(ctx, config.DependencyTypesense, fmt.Errorf("nó %s: %w", node, err))
 	}
 
-	// Headers
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("X-TYPESENSE-API-KEY", ss.typesenseKey)
-
-	// Executar requisição
-	_, httpSpan := otel.Tracer("search").Start(ctx, "HTTP.POST.MultiSearch")
-	httpSpan.SetAttributes(
-		attribute.String("http.method", "POST"),
-		attribute.String("http.url", url),
-	)
-	resp, err := ss.httpClient.Do(httpReq)
-	httpSpan.End()
-
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "HTTP request failed")
@@ -369,7 +786,7 @@ func (ss *SearchService) executeVectorSearch(
 	// Verificar status
 	if resp.StatusCode != http.StatusOK {
 		span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", resp.StatusCode))
-		return nil, fmt.Errorf("busca vetorial falhou (status %d): %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("busca vetorial falhou: %w", &typesense.HTTPError{Status: resp.StatusCode, Body: body})
 	}
 
 	// Parse resposta do multi_search
@@ -449,7 +866,7 @@ func (ss *SearchService) AIAgentSearch(ctx context.Context, req *models.SearchRe
 	defer span.End()
 
 	span.SetAttributes(
-		attribute.String("search.query", req.Query),
+		queryAttribute(ctx, req.Query),
 		attribute.Int("search.page", req.Page),
 		attribute.Int("search.per_page", req.PerPage),
 	)
@@ -470,8 +887,12 @@ func (ss *SearchService) AIAgentSearch(ctx context.Context, req *models.SearchRe
 	analysisSpan.End()
 
 	if err != nil {
-		span.AddEvent("Fallback to HybridSearch - analysis failed")
 		span.RecordError(err)
+		if ctx.Err() == nil && ss.degradations.Report(ctx, config.DependencyGemini, err) == config.DegradeFail {
+			span.SetStatus(codes.Error, "Query analysis failed")
+			return nil, fmt.Errorf("%w: erro na análise da query: %v", ErrDependencyUnavailable, err)
+		}
+		span.AddEvent("Fallback to HybridSearch - analysis failed")
 		log.Printf("AI analysis failed, fallback to hybrid: %v", err)
 		return ss.HybridSearch(ctx, req)
 	}
@@ -594,12 +1015,22 @@ func (ss *SearchService) AIAgentSearch(ctx context.Context, req *models.SearchRe
 	return results, nil
 }
 
+// HandleDocumentEvent descarta as análises de query em cache após escritas em documentos,
+// já que elas refletem categorias e serviços existentes. Embeddings não dependem do acervo e são mantidos.
+func (ss *SearchService) HandleDocumentEvent(ctx context.Context, event DocumentEvent) {
+	ss.cache.DeletePrefix(analysisCachePrefix)
+	ss.cache.Delete(indexEmbeddingsCacheKey)
+}
+
 // analyzeQuery analisa a query com LLM usando structured outputs
 func (ss *SearchService) analyzeQuery(ctx context.Context, query string) (*models.QueryAnalysis, error) {
 	// Verificar cache
-	cacheKey := "analysis:" + query
-	if cached := ss.cache.Get(cacheKey); cached != nil {
-		return cached.(*models.QueryAnalysis), nil
+	// Com no_cache a análise é refeita e substitui a armazenada
+	cacheKey := analysisCachePrefix + query
+	if !CacheBypassed(ctx) {
+		if cached := ss.cache.Get(cacheKey); cached != nil {
+			return cached.(*models.QueryAnalysis), nil
+		}
 	}
 
 	// Timeout de 60s para análise
@@ -1086,7 +1517,8 @@ func (ss *SearchService) transformDocument(tsDoc map[string]interface{}) *models
 		"tema_geral": true, "sub_categoria": true, "slug": true, "status": true, "created_at": true,
 		"last_update": true, "embedding": true, // não retornar embedding
 		"search_content": true, // não retornar search_content bagunçado
-		"slug_history": true,   // não retornar histórico de slugs
+		"slug_history":   true, // não retornar histórico de slugs
+		"deep_links":     true, // retornado no campo deep_links
 	}
 
 	for key, value := range tsDoc {
@@ -1095,6 +1527,24 @@ func (ss *SearchService) transformDocument(tsDoc map[string]interface{}) *models
 		}
 	}
 
+	// Deep links por canal
+	var deepLinks map[string]string
+	if links, ok := tsDoc["deep_links"].(map[string]interface{}); ok {
+		deepLinks = make(map[string]string, len(links))
+		for channel, link := range links {
+			if s, ok := link.(string); ok {
+				deepLinks[channel] = s
+			}
+		}
+	}
+
+	// Destaques das entidades extraídas para snippets mais ricos
+	if entities, ok := tsDoc["entities"].(map[string]interface{}); ok {
+		if highlights := EntityHighlights(entities); len(highlights) > 0 {
+			metadata["entity_highlights"] = highlights
+		}
+	}
+
 	return &models.ServiceDocument{
 		ID:          id,
 		Title:       title,
@@ -1102,6 +1552,7 @@ func (ss *SearchService) transformDocument(tsDoc map[string]interface{}) *models
 		Category:    category,
 		Subcategory: subcategory,
 		Slug:        slug,
+		DeepLinks:   deepLinks,
 		Status:      status,
 		CreatedAt:   createdAt,
 		UpdatedAt:   updatedAt,
@@ -1125,32 +1576,15 @@ func normalizeTextMatch(score float64) float64 {
 	return math.Min(1.0, normalized)
 }
 
-// calculateRecencyFactor calcula o fator de recência baseado em last_update
+// calculateRecencyFactor calcula o fator de recência baseado em last_update com a curva padrão
 // Docs atualizados nos últimos 30 dias: fator = 1.0
 // Docs mais antigos: decaimento exponencial até 0.5 em ~1 ano
 func calculateRecencyFactor(lastUpdateTimestamp int64) float64 {
-	if lastUpdateTimestamp <= 0 {
-		return 0.5 // Docs sem data recebem fator mínimo
-	}
-
-	now := time.Now().Unix()
-	daysSinceUpdate := float64(now-lastUpdateTimestamp) / 86400.0 // segundos para dias
-
-	const gracePeriodDays = 30.0 // período sem penalidade
-	const lambda = 0.00207       // decay rate: ~0.5 em 365 dias após período de graça
-
-	if daysSinceUpdate <= gracePeriodDays {
-		return 1.0
-	}
-
-	daysAfterGrace := daysSinceUpdate - gracePeriodDays
-	factor := math.Exp(-lambda * daysAfterGrace)
-
-	return math.Max(0.5, factor) // mínimo de 0.5
+	return recencyFactorAt(config.DefaultRecencyConfig(), lastUpdateTimestamp, time.Now())
 }
 
-// buildFilterBy constrói a expressão de filtro baseada no SearchRequest
-func buildFilterBy(req *models.SearchRequest) string {
+// buildFilterBy constrói a expressão de filtro baseada no SearchRequest e no tenant da requisição
+func buildFilterBy(ctx context.Context, req *models.SearchRequest) string {
 	var filters []string
 
 	// Filtro de status (apenas publicados, a menos que include_inactive)
@@ -1164,11 +1598,44 @@ func buildFilterBy(req *models.SearchRequest) string {
 		filters = append(filters, "agents.exclusive_for_agents:=false")
 	}
 
-	if len(filters) == 0 {
-		return ""
+	// Filtros por entidades extraídas (serviços ainda não enriquecidos não atendem)
+	if req.PrazoMaxDias != nil {
+		filters = append(filters, fmt.Sprintf("entities.prazo_dias_min:<=%d", *req.PrazoMaxDias))
+	}
+	if req.ValorMax != nil {
+		filters = append(filters, fmt.Sprintf("entities.valor_min:<=%g", *req.ValorMax))
+	}
+	if req.Documento != "" {
+		filters = append(filters, fmt.Sprintf("entities.documentos:=`%s`", strings.ReplaceAll(req.Documento, "`", "")))
+	}
+
+	if len(req.ParsedBairros) > 0 {
+		filters = append(filters, bairroFilter(req.ParsedBairros))
 	}
 
-	return strings.Join(filters, " && ")
+	// Janela de disponibilidade dos serviços sazonais
+	if !req.IncludeInactive && !req.IncludeOutOfWindow {
+		filters = append(filters, availabilityFilter(time.Now().Unix()))
+	}
+
+	return tenant.ScopeFilter(ctx, strings.Join(filters, " && "))
+}
+
+// applyDefaultThresholds completa os thresholds omitidos pela requisição com os padrões da
+// configuração (SEARCH_DEFAULT_THRESHOLDS)
+func applyDefaultThresholds(req *models.SearchRequest, defaults config.ScoreThresholds) {
+	if defaults == (config.ScoreThresholds{}) {
+		return
+	}
+	threshold := models.ScoreThreshold{}
+	if req.ScoreThreshold != nil {
+		threshold = *req.ScoreThreshold
+	}
+	threshold.Keyword = cmp.Or(threshold.Keyword, defaults.Keyword)
+	threshold.Semantic = cmp.Or(threshold.Semantic, defaults.Semantic)
+	threshold.Hybrid = cmp.Or(threshold.Hybrid, defaults.Hybrid)
+	threshold.AI = cmp.Or(threshold.AI, defaults.AI)
+	req.ScoreThreshold = &threshold
 }
 
 // applyScoreThreshold filtra resultados baseado nos thresholds configurados
@@ -1206,8 +1673,14 @@ func (ss *SearchService) applyScoreThreshold(
 		thresholdType = "none"
 	}
 
+	recency := config.DefaultRecencyConfig()
+	if ss.recency != nil {
+		recency = *ss.recency
+	}
+	now := time.Now()
+
 	// Calcular alpha para hybrid
-	alpha := 0.3
+	alpha := ss.defaultAlpha()
 	if searchType == models.SearchTypeHybrid && req.Alpha > 0 && req.Alpha <= 1.0 {
 		alpha = req.Alpha
 	}
@@ -1238,6 +1711,13 @@ func (ss *SearchService) applyScoreThreshold(
 		maxSimilarity = 1.0 - (minVectorDist / 2.0)
 	}
 
+	// Boost de destaque: apenas nos tipos com score de relevância (a busca AI reordena pelo ai_score)
+	destaqueBoost := 1.0
+	if searchType != models.SearchTypeAI {
+		destaqueBoost = ss.destaqueBoost
+	}
+	destaqueApplied := false
+
 	// Processar cada documento, calcular scores e aplicar threshold
 	originalCount := len(docs)
 	filtered := make([]*models.ServiceDocument, 0, len(docs))
@@ -1348,10 +1828,34 @@ func (ss *SearchService) applyScoreThreshold(
 		// Aplicar recency boost se habilitado
 		finalScore := normalizedScore
 		if req.RecencyBoost {
-			recencyFactor := calculateRecencyFactor(doc.UpdatedAt)
+			recencyFactor := recencyFactorAt(recency, recencyTimestamp(recency.Fields, func(field string) int64 {
+				switch field {
+				case "last_update":
+					return doc.UpdatedAt
+				case "created_at":
+					return doc.CreatedAt
+				}
+				return getInt64(doc.Metadata, field)
+			}), now)
 			scoreInfo.RecencyFactor = &recencyFactor
 			finalScore = normalizedScore * recencyFactor
 			scoreInfo.FinalScore = &finalScore
+			contribution := finalScore - normalizedScore
+			scoreInfo.RecencyContribution = &contribution
+		}
+
+		// Aplicar boost de destaque (fixar_destaque até destaque_until)
+		if destaqueBoost > 1 {
+			boosted := finalScore
+			if factor := destaqueFactor(doc, destaqueBoost, now); factor > 1 {
+				boosted = finalScore * factor
+				contribution := boosted - finalScore
+				scoreInfo.DestaqueBoost = &factor
+				scoreInfo.DestaqueContribution = &contribution
+				destaqueApplied = true
+			}
+			finalScore = boosted
+			scoreInfo.FinalScore = &finalScore
 		}
 
 		// Adicionar ScoreInfo ao metadata do documento
@@ -1370,9 +1874,9 @@ func (ss *SearchService) applyScoreThreshold(
 		}
 	}
 
-	// Se recency boost está habilitado, reordenar por final_score
-	if req.RecencyBoost && len(filtered) > 1 {
-		sort.Slice(filtered, func(i, j int) bool {
+	// Se recency boost está habilitado ou algum destaque foi aplicado, reordenar por final_score
+	if (req.RecencyBoost || destaqueApplied) && len(filtered) > 1 {
+		sort.SliceStable(filtered, func(i, j int) bool {
 			scoreI := getFinalScoreFromMetadata(filtered[i])
 			scoreJ := getFinalScoreFromMetadata(filtered[j])
 			return scoreI > scoreJ
@@ -1397,6 +1901,12 @@ func (ss *SearchService) applyScoreThreshold(
 		}
 		filterMeta["recency_boost_applied"] = true
 	}
+	if destaqueApplied {
+		if filterMeta == nil {
+			filterMeta = make(map[string]interface{})
+		}
+		filterMeta["destaque_boost_applied"] = true
+	}
 
 	return filtered, filterMeta
 }
@@ -5,13 +5,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/prefeitura-rio/app-busca-search/internal/config"
 	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
 	"github.com/typesense/typesense-go/v3/typesense"
 	"github.com/typesense/typesense-go/v3/typesense/api"
-	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
 )
 
 // SearchServiceV2 provides multi-collection search (v2 API)
@@ -19,6 +22,13 @@ type SearchServiceV2 struct {
 	client           *typesense.Client
 	embeddingService EmbeddingProvider
 	config           *config.Config
+	sensitive        *SensitiveQueryClassifier
+	translator       *QueryTranslator
+	replicas         *ReplicaRouter
+	configs          *config.Store
+
+	// multiSearch runs a multi-search request (replaced in tests)
+	multiSearch func(ctx context.Context, searches api.MultiSearchSearchesParameter) (*api.MultiSearchResult, error)
 }
 
 // NewSearchServiceV2 creates a new v2 search service
@@ -27,15 +37,51 @@ func NewSearchServiceV2(
 	embeddingService EmbeddingProvider,
 	cfg *config.Config,
 ) *SearchServiceV2 {
-	return &SearchServiceV2{
+	ss := &SearchServiceV2{
 		client:           client,
 		embeddingService: embeddingService,
 		config:           cfg,
 	}
+	ss.multiSearch = func(ctx context.Context, searches api.MultiSearchSearchesParameter) (*api.MultiSearchResult, error) {
+		var result *api.MultiSearchResult
+		err := ss.replicas.Do(ctx, client, func(c *typesense.Client) error {
+			var err error
+			result, err = c.MultiSearch.Perform(ctx, &api.MultiSearchParams{}, searches)
+			return err
+		})
+		return result, err
+	}
+	return ss
+}
+
+// SetReplicaRouter sends searches to the read-only replica while it is healthy. Document
+// lookups by ID stay on the primary so freshly written services are found before replication.
+func (ss *SearchServiceV2) SetReplicaRouter(router *ReplicaRouter) {
+	ss.replicas = router
+}
+
+// SetConfigStore makes searches read the reloadable configuration: collections, collection
+// configs, budgets and defaults come from the snapshot current when the request starts
+func (ss *SearchServiceV2) SetConfigStore(store *config.Store) {
+	ss.configs = store
+}
+
+// withCurrentConfig returns a copy of the service bound to the current config snapshot, so a
+// request sees a single version of the settings
+func (ss *SearchServiceV2) withCurrentConfig() *SearchServiceV2 {
+	cfg := ss.configs.Current()
+	if cfg == nil || cfg == ss.config {
+		return ss
+	}
+	bound := *ss
+	bound.config = cfg
+	return &bound
 }
 
 // Search routes to specific search type
 func (ss *SearchServiceV2) Search(ctx context.Context, req *models.SearchRequest) (*models.UnifiedSearchResponse, error) {
+	ss = ss.withCurrentConfig()
+
 	// Validations
 	if req.Page < 1 {
 		req.Page = 1
@@ -44,24 +90,85 @@ func (ss *SearchServiceV2) Search(ctx context.Context, req *models.SearchRequest
 		req.PerPage = 10
 	}
 
+	// The cursor is bound to the search parameters as sent, before translation
+	fingerprint := searchFingerprint(req)
+	if req.Cursor != "" {
+		cursor, err := decodeSearchCursor(req.Cursor, fingerprint)
+		if err != nil {
+			return nil, err
+		}
+		req.Page = cursor.Page
+		req.ParsedCursor = cursor
+	} else {
+		req.ParsedCursor = &models.SearchCursor{Page: req.Page, Fingerprint: fingerprint}
+	}
+	applyDefaultThresholds(req, ss.config.SearchDefaultThresholds)
+
+	// Queries in other languages are translated to Portuguese (abusive ones skip the LLM)
+	var queryMeta *models.QueryMeta
+	if !IsAbusiveQuery(ctx) {
+		queryMeta = ss.translator.Prepare(ctx, req)
+	}
+
+	// Sensitive queries get emergency contacts and are not recorded
+	safety := ss.sensitive.Classify(req.Query)
+	if safety != nil {
+		ctx = WithSensitiveTopic(ctx, safety.Topic)
+	}
+
+	// Abusive queries skip embeddings and are served by keyword search only
+	if IsAbusiveQuery(ctx) {
+		req.Type = models.SearchTypeKeyword
+	}
+
+	var response *models.UnifiedSearchResponse
+	var err error
 	switch req.Type {
 	case models.SearchTypeKeyword:
-		return ss.KeywordSearch(ctx, req)
+		response, err = ss.KeywordSearch(ctx, req)
 	case models.SearchTypeSemantic:
-		return ss.SemanticSearch(ctx, req)
+		response, err = ss.SemanticSearch(ctx, req)
 	case models.SearchTypeHybrid:
-		return ss.HybridSearch(ctx, req)
+		response, err = ss.HybridSearch(ctx, req)
 	default:
 		return nil, fmt.Errorf("tipo de busca inválido: %s (AI search not yet implemented for v2)", req.Type)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	response.Safety = safety
+	response.QueryMeta = queryMeta
+	if rewrite := translationAnnotation(queryMeta); rewrite != nil {
+		response.Interventions = []string{rewrite.Key("")}
+		if req.DebugAnnotations {
+			for _, doc := range response.Results {
+				doc.Annotations = append(doc.Annotations, *rewrite)
+			}
+		}
+	}
+	return response, nil
+}
+
+// SetSensitiveQueryClassifier sets the sensitive query classifier
+func (ss *SearchServiceV2) SetSensitiveQueryClassifier(classifier *SensitiveQueryClassifier) {
+	ss.sensitive = classifier
+}
+
+// SetQueryTranslator sets the translator for queries in other languages (nil disables)
+func (ss *SearchServiceV2) SetQueryTranslator(translator *QueryTranslator) {
+	ss.translator = translator
 }
 
 // KeywordSearch executes text-based search across multiple collections
 func (ss *SearchServiceV2) KeywordSearch(ctx context.Context, req *models.SearchRequest) (*models.UnifiedSearchResponse, error) {
-	collections, err := ss.getCollections(req.ParsedCollections)
+	collections, err := ss.getCollections(ctx, req.ParsedCollections, req.DocTypes)
 	if err != nil {
 		return nil, err
 	}
+	if err := ss.fitFanOut(req, collections); err != nil {
+		return nil, err
+	}
 
 	// Build search parameters for each collection
 	searches := make([]api.MultiSearchCollectionParameters, 0, len(collections))
@@ -71,18 +178,16 @@ func (ss *SearchServiceV2) KeywordSearch(ctx context.Context, req *models.Search
 		searches = append(searches, params)
 	}
 
-	// Execute MultiSearch
-	searchParams := api.MultiSearchSearchesParameter{
-		Searches: searches,
-	}
-
-	result, err := ss.client.MultiSearch.Perform(ctx, &api.MultiSearchParams{}, searchParams)
+	// Execute the searches concurrently, dropping collections that exceed their budget
+	result, dropped, err := ss.performSearches(ctx, collections, searches)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao executar MultiSearch: %w", err)
 	}
 
 	// Transform results to UnifiedDocuments
 	docs, totalCount := ss.transformMultiSearchResults(result, collections)
+	typeCounts := ss.countByType(result, collections)
+	positions := windowPositions(docs)
 
 	// Apply thresholds if specified
 	filtered := docs
@@ -90,17 +195,23 @@ func (ss *SearchServiceV2) KeywordSearch(ctx context.Context, req *models.Search
 		filtered = ss.applyKeywordThreshold(docs, *req.ScoreThreshold.Keyword)
 	}
 
-	// Manual pagination
-	paged := ss.paginateDocuments(filtered, req.Page, req.PerPage)
+	ss.applyRecencyBoost(filtered, req.RecencyBoost, req.Alpha)
+
+	// Manual pagination over the merged windows, continued by cursor beyond them
+	paged, pageInfo := ss.pageResults(req, result, collections, positions, filtered)
 
 	return &models.UnifiedSearchResponse{
-		Results:       paged,
-		TotalCount:    totalCount,
-		FilteredCount: len(filtered),
-		Page:          req.Page,
-		PerPage:       req.PerPage,
-		SearchType:    models.SearchTypeKeyword,
-		Collections:   collections,
+		Results:            paged,
+		TotalCount:         totalCount,
+		FilteredCount:      len(filtered),
+		Page:               req.Page,
+		PerPage:            req.PerPage,
+		SearchType:         models.SearchTypeKeyword,
+		Collections:        collections,
+		TypeCounts:         typeCounts,
+		Facets:             mergeFacetCounts(result, req.ParsedFacetBy),
+		DroppedCollections: dropped,
+		PageInfo:           pageInfo,
 	}, nil
 }
 
@@ -110,15 +221,18 @@ func (ss *SearchServiceV2) SemanticSearch(ctx context.Context, req *models.Searc
 		return nil, fmt.Errorf("serviço de embedding não disponível")
 	}
 
-	// Generate embedding for query
-	embedding, err := ss.embeddingService.GenerateEmbedding(ctx, req.Query)
+	collections, err := ss.getCollections(ctx, req.ParsedCollections, req.DocTypes)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao gerar embedding: %w", err)
+		return nil, err
+	}
+	if err := ss.fitFanOut(req, collections); err != nil {
+		return nil, err
 	}
 
-	collections, err := ss.getCollections(req.ParsedCollections)
+	// Generate embedding for query
+	embedding, err := ss.embeddingService.GenerateEmbedding(ctx, req.Query)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("erro ao gerar embedding: %w", err)
 	}
 
 	// Build vector query string
@@ -132,18 +246,16 @@ func (ss *SearchServiceV2) SemanticSearch(ctx context.Context, req *models.Searc
 		searches = append(searches, params)
 	}
 
-	// Execute MultiSearch
-	searchParams := api.MultiSearchSearchesParameter{
-		Searches: searches,
-	}
-
-	result, err := ss.client.MultiSearch.Perform(ctx, &api.MultiSearchParams{}, searchParams)
+	// Execute the searches concurrently, dropping collections that exceed their budget
+	result, dropped, err := ss.performSearches(ctx, collections, searches)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao executar MultiSearch: %w", err)
 	}
 
 	// Transform results
 	docs, totalCount := ss.transformMultiSearchResults(result, collections)
+	typeCounts := ss.countByType(result, collections)
+	positions := windowPositions(docs)
 
 	// Apply thresholds if specified
 	filtered := docs
@@ -151,17 +263,23 @@ func (ss *SearchServiceV2) SemanticSearch(ctx context.Context, req *models.Searc
 		filtered = ss.applySemanticThreshold(docs, *req.ScoreThreshold.Semantic)
 	}
 
-	// Manual pagination
-	paged := ss.paginateDocuments(filtered, req.Page, req.PerPage)
+	ss.applyRecencyBoost(filtered, req.RecencyBoost, req.Alpha)
+
+	// Manual pagination over the merged windows, continued by cursor beyond them
+	paged, pageInfo := ss.pageResults(req, result, collections, positions, filtered)
 
 	return &models.UnifiedSearchResponse{
-		Results:       paged,
-		TotalCount:    totalCount,
-		FilteredCount: len(filtered),
-		Page:          req.Page,
-		PerPage:       req.PerPage,
-		SearchType:    models.SearchTypeSemantic,
-		Collections:   collections,
+		Results:            paged,
+		TotalCount:         totalCount,
+		FilteredCount:      len(filtered),
+		Page:               req.Page,
+		PerPage:            req.PerPage,
+		SearchType:         models.SearchTypeSemantic,
+		Collections:        collections,
+		TypeCounts:         typeCounts,
+		Facets:             mergeFacetCounts(result, req.ParsedFacetBy),
+		DroppedCollections: dropped,
+		PageInfo:           pageInfo,
 	}, nil
 }
 
@@ -172,6 +290,14 @@ func (ss *SearchServiceV2) HybridSearch(ctx context.Context, req *models.SearchR
 		return ss.KeywordSearch(ctx, req)
 	}
 
+	collections, err := ss.getCollections(ctx, req.ParsedCollections, req.DocTypes)
+	if err != nil {
+		return nil, err
+	}
+	if err := ss.fitFanOut(req, collections); err != nil {
+		return nil, err
+	}
+
 	// Generate embedding for query
 	embedding, err := ss.embeddingService.GenerateEmbedding(ctx, req.Query)
 	if err != nil {
@@ -179,15 +305,10 @@ func (ss *SearchServiceV2) HybridSearch(ctx context.Context, req *models.SearchR
 		return ss.KeywordSearch(ctx, req)
 	}
 
-	collections, err := ss.getCollections(req.ParsedCollections)
-	if err != nil {
-		return nil, err
-	}
-
-	// Use provided alpha or default to 0.3
+	// Use provided alpha or the configured default (0.3 unless SEARCH_HYBRID_ALPHA)
 	alpha := req.Alpha
 	if alpha == 0 {
-		alpha = 0.3
+		alpha = ss.config.HybridAlpha()
 	}
 
 	// Build vector query string
@@ -201,18 +322,16 @@ func (ss *SearchServiceV2) HybridSearch(ctx context.Context, req *models.SearchR
 		searches = append(searches, params)
 	}
 
-	// Execute MultiSearch
-	searchParams := api.MultiSearchSearchesParameter{
-		Searches: searches,
-	}
-
-	result, err := ss.client.MultiSearch.Perform(ctx, &api.MultiSearchParams{}, searchParams)
+	// Execute the searches concurrently, dropping collections that exceed their budget
+	result, dropped, err := ss.performSearches(ctx, collections, searches)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao executar MultiSearch: %w", err)
 	}
 
 	// Transform results
 	docs, totalCount := ss.transformMultiSearchResults(result, collections)
+	typeCounts := ss.countByType(result, collections)
+	positions := windowPositions(docs)
 
 	// Apply thresholds if specified
 	filtered := docs
@@ -220,28 +339,35 @@ func (ss *SearchServiceV2) HybridSearch(ctx context.Context, req *models.SearchR
 		filtered = ss.applyHybridThreshold(docs, *req.ScoreThreshold.Hybrid)
 	}
 
-	// Manual pagination
-	paged := ss.paginateDocuments(filtered, req.Page, req.PerPage)
+	ss.applyRecencyBoost(filtered, req.RecencyBoost, alpha)
+
+	// Manual pagination over the merged windows, continued by cursor beyond them
+	paged, pageInfo := ss.pageResults(req, result, collections, positions, filtered)
 
 	return &models.UnifiedSearchResponse{
-		Results:       paged,
-		TotalCount:    totalCount,
-		FilteredCount: len(filtered),
-		Page:          req.Page,
-		PerPage:       req.PerPage,
-		SearchType:    models.SearchTypeHybrid,
-		Collections:   collections,
+		Results:            paged,
+		TotalCount:         totalCount,
+		FilteredCount:      len(filtered),
+		Page:               req.Page,
+		PerPage:            req.PerPage,
+		SearchType:         models.SearchTypeHybrid,
+		Collections:        collections,
+		TypeCounts:         typeCounts,
+		Facets:             mergeFacetCounts(result, req.ParsedFacetBy),
+		DroppedCollections: dropped,
+		PageInfo:           pageInfo,
 	}, nil
 }
 
 // GetDocumentByID retrieves a document by ID with optional collection hint
 func (ss *SearchServiceV2) GetDocumentByID(ctx context.Context, id string, collectionHint string) (*models.UnifiedDocument, error) {
-	collections := ss.config.SearchableCollections
+	ss = ss.withCurrentConfig()
+	collections := ss.searchableCollections(ctx)
 
-	// If hint provided and valid, try it first
-	if collectionHint != "" {
+	// If hint provided and valid for the tenant, try it first
+	if collectionHint != "" && slices.Contains(collections, collectionHint) {
 		if collConfig := ss.config.GetCollectionConfig(collectionHint); collConfig != nil {
-			doc, err := ss.tryGetFromCollection(ctx, id, collectionHint, collConfig.Type)
+			doc, err := ss.tryGetFromCollection(ctx, id, collectionHint, collConfig)
 			if err == nil {
 				return doc, nil
 			}
@@ -251,7 +377,7 @@ func (ss *SearchServiceV2) GetDocumentByID(ctx context.Context, id string, colle
 	// Search all searchable collections
 	for _, collName := range collections {
 		collConfig := ss.config.GetCollectionConfig(collName)
-		doc, err := ss.tryGetFromCollection(ctx, id, collName, collConfig.Type)
+		doc, err := ss.tryGetFromCollection(ctx, id, collName, collConfig)
 		if err == nil {
 			return doc, nil
 		}
@@ -264,28 +390,65 @@ func (ss *SearchServiceV2) GetDocumentByID(ctx context.Context, id string, colle
 // Helper Methods
 // ============================================================================
 
-// getCollections returns the collections to search based on request or defaults to all configured collections.
+// searchableCollections returns the collections configured for the request's tenant
+func (ss *SearchServiceV2) searchableCollections(ctx context.Context) []string {
+	if tenantID, ok := tenant.FromContext(ctx); ok {
+		return ss.config.SearchableCollectionsFor(tenantID)
+	}
+	return ss.config.SearchableCollections
+}
+
+// getCollections returns the collections to search based on request or defaults to all configured collections,
+// restricted to the collections whose type is in docTypes (comma-separated, e.g. "news") when set.
 // Returns an error if any requested collection is not valid.
-func (ss *SearchServiceV2) getCollections(requestedCollections []string) ([]string, error) {
+func (ss *SearchServiceV2) getCollections(ctx context.Context, requestedCollections []string, docTypes string) ([]string, error) {
+	searchable := ss.searchableCollections(ctx)
+
 	// If no collections specified, use all configured collections
 	if len(requestedCollections) == 0 {
-		return ss.config.SearchableCollections, nil
+		return ss.filterCollectionsByType(searchable, docTypes)
 	}
 
 	// Validate that all requested collections are valid
 	validCollections := make(map[string]bool)
-	for _, c := range ss.config.SearchableCollections {
+	for _, c := range searchable {
 		validCollections[c] = true
 	}
 
 	for _, c := range requestedCollections {
 		if !validCollections[c] {
 			return nil, fmt.Errorf("collection '%s' não está configurada. Collections válidas: %s",
-				c, strings.Join(ss.config.SearchableCollections, ", "))
+				c, strings.Join(searchable, ", "))
+		}
+	}
+
+	return ss.filterCollectionsByType(requestedCollections, docTypes)
+}
+
+// filterCollectionsByType keeps the collections whose configured type is in docTypes
+// (comma-separated). Returns an error if no collection has one of the types.
+func (ss *SearchServiceV2) filterCollectionsByType(collections []string, docTypes string) ([]string, error) {
+	if strings.TrimSpace(docTypes) == "" {
+		return collections, nil
+	}
+
+	types := make(map[string]bool)
+	for _, t := range strings.Split(docTypes, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types[t] = true
 		}
 	}
 
-	return requestedCollections, nil
+	filtered := make([]string, 0, len(collections))
+	for _, c := range collections {
+		if collConfig := ss.config.GetCollectionConfig(c); collConfig != nil && types[collConfig.Type] {
+			filtered = append(filtered, c)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("nenhuma collection do tipo '%s' disponível para busca", docTypes)
+	}
+	return filtered, nil
 }
 
 func (ss *SearchServiceV2) buildKeywordSearchParams(collName string, collConfig *config.CollectionConfig, req *models.SearchRequest) api.MultiSearchCollectionParameters {
@@ -307,12 +470,12 @@ func (ss *SearchServiceV2) buildKeywordSearchParams(collName string, collConfig
 		Q:              &queryStr,
 		QueryBy:        &queryBy,
 		QueryByWeights: &queryByWeights,
-		Page:           pointer.Int(1),
-		PerPage:        pointer.Int(250),
 	}
+	setSearchWindow(&params, collName, req)
+	applyFieldProjection(&params, collConfig, req)
+	applyFacets(&params, collName, collConfig, req)
 
-	if collConfig.FilterField != "" && !req.IncludeInactive {
-		filterBy := fmt.Sprintf("%s:=%s", collConfig.FilterField, collConfig.FilterValue)
+	if filterBy := collectionFilterBy(collName, collConfig, req); filterBy != "" {
 		params.FilterBy = &filterBy
 	}
 
@@ -326,13 +489,13 @@ func (ss *SearchServiceV2) buildSemanticSearchParams(collName string, collConfig
 		Collection:  &collName,
 		Q:           &queryStr,
 		VectorQuery: &vectorQuery,
-		Page:        pointer.Int(1),
-		PerPage:     pointer.Int(250),
 	}
+	setSearchWindow(&params, collName, req)
+	applyFieldProjection(&params, collConfig, req)
+	applyFacets(&params, collName, collConfig, req)
 
 	// Add filter if collection requires it
-	if collConfig.FilterField != "" && !req.IncludeInactive {
-		filterBy := fmt.Sprintf("%s:=%s", collConfig.FilterField, collConfig.FilterValue)
+	if filterBy := collectionFilterBy(collName, collConfig, req); filterBy != "" {
 		params.FilterBy = &filterBy
 	}
 
@@ -359,18 +522,39 @@ func (ss *SearchServiceV2) buildHybridSearchParams(collName string, collConfig *
 		QueryBy:        &queryBy,
 		QueryByWeights: &queryByWeights,
 		VectorQuery:    &vectorQuery,
-		Page:           pointer.Int(1),
-		PerPage:        pointer.Int(250),
 	}
+	setSearchWindow(&params, collName, req)
+	applyFieldProjection(&params, collConfig, req)
+	applyFacets(&params, collName, collConfig, req)
 
-	if collConfig.FilterField != "" && !req.IncludeInactive {
-		filterBy := fmt.Sprintf("%s:=%s", collConfig.FilterField, collConfig.FilterValue)
+	if filterBy := collectionFilterBy(collName, collConfig, req); filterBy != "" {
 		params.FilterBy = &filterBy
 	}
 
 	return params
 }
 
+// collectionFilterBy builds the filter of a collection: the configured status filter plus, for
+// the services collection, the availability window of seasonal services
+func collectionFilterBy(collName string, collConfig *config.CollectionConfig, req *models.SearchRequest) string {
+	if req.IncludeInactive {
+		return ""
+	}
+
+	var filters []string
+	if collConfig.FilterField != "" {
+		filters = append(filters, fmt.Sprintf("%s:=%s", collConfig.FilterField, collConfig.FilterValue))
+	}
+	if collName == PrefRioServicesCollection && !req.IncludeOutOfWindow {
+		filters = append(filters, availabilityFilter(time.Now().Unix()))
+	}
+	if len(req.ParsedBairros) > 0 && (collName == PrefRioServicesCollection || collName == HubSearchCollection) {
+		filters = append(filters, bairroFilter(req.ParsedBairros))
+	}
+
+	return strings.Join(filters, " && ")
+}
+
 func (ss *SearchServiceV2) transformMultiSearchResults(result *api.MultiSearchResult, collections []string) ([]*models.UnifiedDocument, int) {
 	var docs []*models.UnifiedDocument
 	totalCount := 0
@@ -407,6 +591,7 @@ func (ss *SearchServiceV2) transformMultiSearchResults(result *api.MultiSearchRe
 				Data:       tsDoc,
 				ScoreInfo:  ss.extractScoreInfo(&hit),
 			}
+			applyFieldMapping(doc, collConfig)
 
 			docs = append(docs, doc)
 		}
@@ -415,6 +600,23 @@ func (ss *SearchServiceV2) transformMultiSearchResults(result *api.MultiSearchRe
 	return docs, totalCount
 }
 
+// countByType sums the results found in each collection by the collection's type (e.g. service,
+// news), so clients can show type facets for the whole result set, not only the current page
+func (ss *SearchServiceV2) countByType(result *api.MultiSearchResult, collections []string) map[string]int {
+	counts := make(map[string]int)
+	for i, res := range result.Results {
+		if i >= len(collections) || res.Found == nil {
+			continue
+		}
+		collConfig := ss.config.GetCollectionConfig(collections[i])
+		if collConfig == nil || collConfig.Type == "" {
+			continue
+		}
+		counts[collConfig.Type] += int(*res.Found)
+	}
+	return counts
+}
+
 func (ss *SearchServiceV2) extractScoreInfo(hit *api.SearchResultHit) *models.ScoreInfo {
 	scoreInfo := &models.ScoreInfo{}
 
@@ -441,7 +643,7 @@ func (ss *SearchServiceV2) extractScoreInfo(hit *api.SearchResultHit) *models.Sc
 	return scoreInfo
 }
 
-func (ss *SearchServiceV2) tryGetFromCollection(ctx context.Context, id string, collName string, collType string) (*models.UnifiedDocument, error) {
+func (ss *SearchServiceV2) tryGetFromCollection(ctx context.Context, id string, collName string, collConfig *config.CollectionConfig) (*models.UnifiedDocument, error) {
 	result, err := ss.client.Collection(collName).Document(id).Retrieve(ctx)
 	if err != nil {
 		return nil, err
@@ -451,12 +653,14 @@ func (ss *SearchServiceV2) tryGetFromCollection(ctx context.Context, id string,
 	var tsDoc map[string]interface{}
 	json.Unmarshal(resultBytes, &tsDoc)
 
-	return &models.UnifiedDocument{
+	doc := &models.UnifiedDocument{
 		ID:         id,
 		Collection: collName,
-		Type:       collType,
+		Type:       collConfig.Type,
 		Data:       tsDoc,
-	}, nil
+	}
+	applyFieldMapping(doc, collConfig)
+	return doc, nil
 }
 
 func (ss *SearchServiceV2) applyKeywordThreshold(docs []*models.UnifiedDocument, threshold float64) []*models.UnifiedDocument {
@@ -504,6 +708,68 @@ func (ss *SearchServiceV2) applyHybridThreshold(docs []*models.UnifiedDocument,
 	return filtered
 }
 
+// applyRecencyBoost multiplies each document's score by the recency factor of its collection's
+// recency config and re-sorts the merged results by the final score. Without requested, only
+// collections whose recency config is marked always (e.g. noticias) are boosted; the others keep
+// their base score. The factor and its contribution are exposed in score_info for tuning.
+func (ss *SearchServiceV2) applyRecencyBoost(docs []*models.UnifiedDocument, requested bool, alpha float64) {
+	if !requested && !ss.hasAlwaysRecency(docs) {
+		return
+	}
+
+	now := time.Now()
+	for _, doc := range docs {
+		if doc.ScoreInfo == nil {
+			doc.ScoreInfo = &models.ScoreInfo{}
+		}
+		recency := ss.config.GetCollectionConfig(doc.Collection).GetRecency()
+		timestamp := recencyTimestamp(recency.Fields, func(field string) int64 {
+			return getInt64(doc.Data, field)
+		})
+
+		base := unifiedBaseScore(doc.ScoreInfo, alpha)
+		factor := 1.0
+		if requested || recency.Always {
+			factor = recencyFactorAt(recency, timestamp, now)
+		}
+		final := base * factor
+		contribution := final - base
+		doc.ScoreInfo.RecencyFactor = &factor
+		doc.ScoreInfo.FinalScore = &final
+		doc.ScoreInfo.RecencyContribution = &contribution
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		return *docs[i].ScoreInfo.FinalScore > *docs[j].ScoreInfo.FinalScore
+	})
+}
+
+// hasAlwaysRecency reports whether any document comes from a collection boosted on every search
+func (ss *SearchServiceV2) hasAlwaysRecency(docs []*models.UnifiedDocument) bool {
+	for _, doc := range docs {
+		if collConfig := ss.config.GetCollectionConfig(doc.Collection); collConfig != nil && collConfig.Recency != nil && collConfig.Recency.Always {
+			return true
+		}
+	}
+	return false
+}
+
+// unifiedBaseScore returns the relevance score before the recency boost: the hybrid score, or
+// alpha*text + (1-alpha)*vector when both are present, or whichever one is present
+func unifiedBaseScore(info *models.ScoreInfo, alpha float64) float64 {
+	switch {
+	case info.HybridScore != nil:
+		return *info.HybridScore
+	case info.TextMatchNormalized != nil && info.VectorSimilarity != nil:
+		return alpha*(*info.TextMatchNormalized) + (1-alpha)*(*info.VectorSimilarity)
+	case info.VectorSimilarity != nil:
+		return *info.VectorSimilarity
+	case info.TextMatchNormalized != nil:
+		return *info.TextMatchNormalized
+	}
+	return 0
+}
+
 func (ss *SearchServiceV2) paginateDocuments(docs []*models.UnifiedDocument, page, perPage int) []*models.UnifiedDocument {
 	startIdx := (page - 1) * perPage
 	if startIdx < 0 {
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/pii"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type sensitiveTopicKey struct{}
+
+// WithSensitiveTopic marca o contexto da busca como sensível. Buscas marcadas não têm a query
+// registrada em traces, logs nem na contagem de frequência de queries.
+func WithSensitiveTopic(ctx context.Context, topicID string) context.Context {
+	return context.WithValue(ctx, sensitiveTopicKey{}, topicID)
+}
+
+// SensitiveTopicFromContext retorna o tema sensível da busca ("" se não for sensível)
+func SensitiveTopicFromContext(ctx context.Context) string {
+	topicID, _ := ctx.Value(sensitiveTopicKey{}).(string)
+	return topicID
+}
+
+// queryAttribute retorna o atributo search.query do span, omitindo a query de buscas sensíveis
+// e mascarando dados pessoais das demais
+func queryAttribute(ctx context.Context, query string) attribute.KeyValue {
+	return attribute.String("search.query", loggableQuery(ctx, query))
+}
+
+// loggableQuery retorna a query que pode ser registrada em logs e traces
+func loggableQuery(ctx context.Context, query string) string {
+	if topicID := SensitiveTopicFromContext(ctx); topicID != "" {
+		return "[sensível:" + topicID + "]"
+	}
+	return pii.Scrub(query)
+}
+
+// SensitiveQueryClassifier identifica buscas sobre temas sensíveis por palavras-chave e conta
+// as ocorrências por tema (única informação mantida sobre essas buscas)
+type SensitiveQueryClassifier struct {
+	topics   []models.SensitiveTopic
+	keywords [][]string // palavras-chave normalizadas, por tema
+
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+// NewSensitiveQueryClassifier cria o classificador com os temas informados
+func NewSensitiveQueryClassifier(topics []models.SensitiveTopic) *SensitiveQueryClassifier {
+	sc := &SensitiveQueryClassifier{counts: make(map[string]int64)}
+	for _, topic := range topics {
+		if topic.Disabled || len(topic.Keywords) == 0 {
+			continue
+		}
+		normalized := make([]string, 0, len(topic.Keywords))
+		for _, keyword := range topic.Keywords {
+			if keyword = normalizeSensitiveText(keyword); keyword != "" {
+				normalized = append(normalized, keyword)
+			}
+		}
+		sc.topics = append(sc.topics, topic)
+		sc.keywords = append(sc.keywords, normalized)
+		sc.counts[topic.ID] = 0
+	}
+	return sc
+}
+
+// Classify retorna o bloco de segurança do primeiro tema cuja palavra-chave aparece na query
+// (nil se a busca não for sensível) e contabiliza a ocorrência
+func (sc *SensitiveQueryClassifier) Classify(query string) *models.SafetyBlock {
+	if sc == nil {
+		return nil
+	}
+
+	normalized := normalizeSensitiveText(query)
+	for i, topic := range sc.topics {
+		for _, keyword := range sc.keywords[i] {
+			if containsWord(normalized, keyword) {
+				sc.mu.Lock()
+				sc.counts[topic.ID]++
+				sc.mu.Unlock()
+
+				return &models.SafetyBlock{
+					Topic:    topic.ID,
+					Label:    topic.Label,
+					Message:  topic.Message,
+					Contacts: topic.Contacts,
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Counts retorna quantas buscas sensíveis foram recebidas por tema desde o início do processo
+func (sc *SensitiveQueryClassifier) Counts() map[string]int64 {
+	counts := make(map[string]int64)
+	if sc == nil {
+		return counts
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for id, count := range sc.counts {
+		counts[id] = count
+	}
+	return counts
+}
+
+// normalizeSensitiveText remove acentos, caixa e pontuação para a comparação de palavras-chave
+func normalizeSensitiveText(text string) string {
+	text = utils.NormalizarCategoria(text)
+	return strings.Join(strings.FieldsFunc(text, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9')
+	}), " ")
+}
+
+// LoadSensitiveTopics combina os temas padrão com os configurados em JSON (SENSITIVE_TOPICS):
+// temas com o mesmo ID substituem o padrão, "disabled": true o remove e IDs novos são adicionados
+func LoadSensitiveTopics(raw string) ([]models.SensitiveTopic, error) {
+	topics := DefaultSensitiveTopics()
+	if strings.TrimSpace(raw) == "" {
+		return topics, nil
+	}
+
+	var overrides []models.SensitiveTopic
+	if err := json.Unmarshal([]byte(raw), &overrides); err != nil {
+		return nil, fmt.Errorf("SENSITIVE_TOPICS inválido: %w", err)
+	}
+
+	for _, override := range overrides {
+		if override.ID == "" {
+			return nil, fmt.Errorf("SENSITIVE_TOPICS inválido: tema sem id")
+		}
+		replaced := false
+		for i := range topics {
+			if topics[i].ID == override.ID {
+				topics[i] = override
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			topics = append(topics, override)
+		}
+	}
+
+	return topics, nil
+}
+
+// DefaultSensitiveTopics temas sensíveis padrão com os canais oficiais de ajuda
+func DefaultSensitiveTopics() []models.SensitiveTopic {
+	return []models.SensitiveTopic{
+		{
+			ID:    "suicidio",
+			Label: "Apoio emocional",
+			Keywords: []string{
+				"suicidio", "suicida", "me matar", "quero morrer", "vontade de morrer",
+				"tirar minha vida", "tirar a propria vida", "nao quero mais viver", "automutilacao", "me cortar",
+			},
+			Message: "Você não está sozinho. Se estiver pensando em tirar a própria vida, converse agora com alguém: o CVV atende 24 horas, gratuitamente e com sigilo.",
+			Contacts: []models.EmergencyContact{
+				{Name: "CVV - Centro de Valorização da Vida", Phone: "188", URL: "https://cvv.org.br", Description: "Apoio emocional 24h, ligação gratuita"},
+				{Name: "SAMU", Phone: "192", Description: "Em caso de risco imediato à vida"},
+			},
+		},
+		{
+			ID:    "violencia",
+			Label: "Violência",
+			Keywords: []string{
+				"violencia domestica", "violencia contra a mulher", "violencia sexual", "estupro", "abuso sexual",
+				"abuso infantil", "agressao", "espancamento", "maria da penha", "ameaca de morte", "sequestro",
+			},
+			Message: "Se você ou alguém está em perigo, procure ajuda imediatamente. As denúncias podem ser anônimas.",
+			Contacts: []models.EmergencyContact{
+				{Name: "Polícia Militar", Phone: "190", Description: "Emergências e situações de risco imediato"},
+				{Name: "Central de Atendimento à Mulher", Phone: "180", Description: "Denúncias de violência contra a mulher, 24h"},
+				{Name: "Disque Direitos Humanos", Phone: "100", Description: "Violência contra crianças, idosos e outros grupos vulneráveis"},
+			},
+		},
+		{
+			ID:    "emergencia_saude",
+			Label: "Emergência de saúde",
+			Keywords: []string{
+				"infarto", "avc", "derrame", "parada cardiaca", "parada respiratoria", "overdose", "envenenamento",
+				"intoxicacao", "convulsao", "hemorragia", "falta de ar", "engasgo", "emergencia medica", "ambulancia",
+			},
+			Message: "Em uma emergência de saúde, ligue imediatamente para o SAMU ou procure a unidade de urgência mais próxima.",
+			Contacts: []models.EmergencyContact{
+				{Name: "SAMU", Phone: "192", Description: "Atendimento móvel de urgência, 24h"},
+				{Name: "Corpo de Bombeiros", Phone: "193", Description: "Resgates e emergências"},
+			},
+		},
+	}
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSensitiveQueryClassifierClassify(t *testing.T) {
+	classifier := NewSensitiveQueryClassifier(DefaultSensitiveTopics())
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"Quero MORRER", "suicidio"},
+		{"como denunciar violência doméstica?", "violencia"},
+		{"sintomas de AVC", "emergencia_saude"},
+		{"segunda via do IPTU", ""},
+		{"avcb bombeiros", ""}, // palavra-chave só casa com palavra inteira
+	}
+
+	for _, tt := range tests {
+		block := classifier.Classify(tt.query)
+		got := ""
+		if block != nil {
+			got = block.Topic
+			if len(block.Contacts) == 0 {
+				t.Errorf("Classify(%q) returned block without contacts", tt.query)
+			}
+		}
+		if got != tt.want {
+			t.Errorf("Classify(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+
+	counts := classifier.Counts()
+	if counts["suicidio"] != 1 || counts["violencia"] != 1 || counts["emergencia_saude"] != 1 {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+}
+
+func TestLoadSensitiveTopics(t *testing.T) {
+	topics, err := LoadSensitiveTopics(`[
+		{"id": "emergencia_saude", "disabled": true},
+		{"id": "desastres", "label": "Desastres", "keywords": ["enchente", "deslizamento"], "contacts": [{"name": "Defesa Civil", "phone": "199"}]}
+	]`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	classifier := NewSensitiveQueryClassifier(topics)
+	if block := classifier.Classify("infarto"); block != nil {
+		t.Errorf("disabled topic should not match, got %q", block.Topic)
+	}
+	if block := classifier.Classify("enchente na minha rua"); block == nil || block.Topic != "desastres" {
+		t.Errorf("expected custom topic to match, got %+v", block)
+	}
+
+	if _, err := LoadSensitiveTopics(`[{"label": "sem id"}]`); err == nil {
+		t.Error("expected error for topic without id")
+	}
+}
+
+func TestLoggableQueryOmitsSensitiveQueries(t *testing.T) {
+	ctx := context.Background()
+	if got := loggableQuery(ctx, "iptu"); got != "iptu" {
+		t.Errorf("expected query unchanged, got %q", got)
+	}
+
+	ctx = WithSensitiveTopic(ctx, "suicidio")
+	if got := loggableQuery(ctx, "quero morrer"); got != "[sensível:suicidio]" {
+		t.Errorf("expected redacted query, got %q", got)
+	}
+	if attr := queryAttribute(ctx, "quero morrer"); attr.Value.AsString() != "[sensível:suicidio]" {
+		t.Errorf("expected redacted span attribute, got %q", attr.Value.AsString())
+	}
+}
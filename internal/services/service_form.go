@@ -0,0 +1,206 @@
+package services
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+// serviceFormLabels rótulos dos campos do formulário, pelo caminho no JSON
+var serviceFormLabels = map[string]string{
+	"nome_servico":                "Nome do serviço",
+	"orgao_gestor":                "Órgão gestor",
+	"resumo":                      "Resumo",
+	"tempo_atendimento":           "Tempo de atendimento",
+	"custo_servico":               "Custo do serviço",
+	"resultado_solicitacao":       "Resultado da solicitação",
+	"descricao_completa":          "Descrição completa",
+	"documentos_necessarios":      "Documentos necessários",
+	"instrucoes_solicitante":      "Instruções ao solicitante",
+	"canais_digitais":             "Canais digitais",
+	"canais_presenciais":          "Canais presenciais",
+	"servico_nao_cobre":           "O que o serviço não cobre",
+	"legislacao_relacionada":      "Legislação relacionada",
+	"tema_geral":                  "Tema",
+	"sub_categoria":               "Subcategoria",
+	"publico_especifico":          "Público específico",
+	"fixar_destaque":              "Fixar em destaque",
+	"awaiting_approval":           "Aguardando aprovação",
+	"published_at":                "Data de publicação",
+	"is_free":                     "Gratuito",
+	"agents":                      "Agentes de IA",
+	"agents.tool_hint":            "Dica de uso para agentes",
+	"agents.exclusive_for_agents": "Exclusivo para agentes",
+	"extra_fields":                "Campos extras",
+	"status":                      "Status",
+	"buttons":                     "Botões",
+	"buttons.titulo":              "Título",
+	"buttons.descricao":           "Descrição",
+	"buttons.is_enabled":          "Habilitado",
+	"buttons.ordem":               "Ordem",
+	"buttons.action_type":         "Tipo de ação",
+	"buttons.url_service":         "URL",
+	"buttons.phone":               "Telefone",
+	"buttons.message":             "Mensagem",
+	"available_from":              "Disponível a partir de",
+	"available_until":             "Disponível até",
+	"destaque_until":              "Destaque até",
+	"bairros":                     "Bairros atendidos",
+}
+
+// serviceFormDescriptions orientações exibidas junto aos campos
+var serviceFormDescriptions = map[string]string{
+	"tema_geral":          "Vazio ou fora da lista: uma categoria é sugerida automaticamente",
+	"status":              "0 = rascunho, 1 = publicado",
+	"extra_fields":        "Campos definidos pelo schema do tema (GET /api/v1/admin/extra-fields/schemas/{tema})",
+	"bairros":             "Bairros, regiões administrativas ou zonas; vazio = toda a cidade",
+	"destaque_until":      "Fim do destaque na busca (fixar_destaque); vazio = sem limite",
+	"buttons.action_type": "Define os campos exigidos: url_service para external_link e pref_login_flow, phone para whatsapp e phone",
+}
+
+// serviceFormTextFields campos de texto longo com markdown
+var serviceFormTextFields = map[string]bool{
+	"resumo":                 true,
+	"resultado_solicitacao":  true,
+	"descricao_completa":     true,
+	"documentos_necessarios": true,
+	"instrucoes_solicitante": true,
+	"servico_nao_cobre":      true,
+}
+
+// serviceFormDateFields campos com timestamp unix
+var serviceFormDateFields = map[string]bool{
+	"published_at":    true,
+	"available_from":  true,
+	"available_until": true,
+	"destaque_until":  true,
+}
+
+// BuildServiceForm descreve os campos de PrefRioServiceRequest a partir dos tipos e das tags
+// validate do modelo, para que o back-office siga a validação do servidor. As opções de tema e
+// subcategoria vêm da taxonomia e as de bairros da lista canônica.
+func BuildServiceForm(taxonomy map[string][]string, bairros []string) *models.ServiceForm {
+	fields := formFields(reflect.TypeOf(models.PrefRioServiceRequest{}), "")
+
+	for i := range fields {
+		field := &fields[i]
+		switch field.Name {
+		case "tema_geral":
+			field.Options = make([]string, 0, len(taxonomy))
+			for tema := range taxonomy {
+				field.Options = append(field.Options, tema)
+			}
+			sort.Strings(field.Options)
+		case "sub_categoria":
+			field.DependsOn = "tema_geral"
+			field.OptionsBy = taxonomy
+		case "bairros":
+			field.Options = bairros
+		}
+	}
+
+	return &models.ServiceForm{Fields: fields, GeneratedAt: time.Now().Unix()}
+}
+
+// formFields descreve os campos exportados da struct; prefix é o caminho do objeto pai
+func formFields(t reflect.Type, prefix string) []models.FormField {
+	fields := make([]models.FormField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" || !sf.IsExported() {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		field := models.FormField{
+			Name:        name,
+			Label:       serviceFormLabels[path],
+			Type:        formFieldType(sf.Type, path),
+			Description: serviceFormDescriptions[path],
+		}
+		if field.Label == "" {
+			field.Label = name
+		}
+		applyValidateTag(&field, sf.Tag.Get("validate"))
+
+		elem := sf.Type
+		for elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Slice {
+			elem = elem.Elem()
+		}
+		if elem.Kind() == reflect.Struct {
+			field.Fields = formFields(elem, path)
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// formFieldType tipo do campo no formulário
+func formFieldType(t reflect.Type, path string) string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	name := path[strings.LastIndex(path, ".")+1:]
+
+	switch t.Kind() {
+	case reflect.String:
+		if serviceFormTextFields[name] {
+			return models.FormFieldText
+		}
+		return models.FormFieldString
+	case reflect.Bool:
+		return models.FormFieldBoolean
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		if serviceFormDateFields[name] {
+			return models.FormFieldDateTime
+		}
+		return models.FormFieldInteger
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Struct {
+			return models.FormFieldObjects
+		}
+		return models.FormFieldList
+	}
+	return models.FormFieldObject
+}
+
+// applyValidateTag traduz as regras do validator: antes de dive, valem para o campo (em listas,
+// min e max são quantidades de itens); depois de dive, para cada item
+func applyValidateTag(field *models.FormField, tag string) {
+	isList := field.Type == models.FormFieldList || field.Type == models.FormFieldObjects
+	isNumber := field.Type == models.FormFieldInteger || field.Type == models.FormFieldDateTime
+	items := false
+
+	for _, rule := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(rule, "=")
+		n, _ := strconv.ParseInt(value, 10, 64)
+
+		switch {
+		case key == "dive":
+			items = true
+		case key == "required" && !items:
+			field.Required = true
+		case key == "oneof":
+			field.Options = strings.Fields(value)
+			field.OptionsStrict = true
+		case key == "min" && isNumber:
+			field.Min = &n
+		case key == "max" && isNumber:
+			field.Max = &n
+		case key == "min" && isList && !items:
+			field.MinItems = int(n)
+		case key == "max" && isList && !items:
+			field.MaxItems = int(n)
+		case key == "max":
+			field.MaxLength = int(n)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func formField(fields []models.FormField, name string) *models.FormField {
+	for i := range fields {
+		if fields[i].Name == name {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+func TestBuildServiceForm(t *testing.T) {
+	taxonomy := map[string][]string{"Saúde": {"Vacinação"}, "Educação": {}}
+	form := BuildServiceForm(taxonomy, []string{"Tijuca"})
+
+	nome := formField(form.Fields, "nome_servico")
+	if nome == nil || !nome.Required || nome.MaxLength != 20000 || nome.Type != models.FormFieldString || nome.Label != "Nome do serviço" {
+		t.Errorf("nome_servico = %+v", nome)
+	}
+
+	orgao := formField(form.Fields, "orgao_gestor")
+	if orgao == nil || orgao.Type != models.FormFieldList || !orgao.Required || orgao.MinItems != 1 || orgao.MaxItems != 50 || orgao.MaxLength != 500 {
+		t.Errorf("orgao_gestor = %+v", orgao)
+	}
+
+	if resumo := formField(form.Fields, "resumo"); resumo == nil || resumo.Type != models.FormFieldText {
+		t.Errorf("resumo = %+v", resumo)
+	}
+
+	status := formField(form.Fields, "status")
+	if status == nil || status.Type != models.FormFieldInteger || status.Min == nil || *status.Min != 0 || status.Max == nil || *status.Max != 1 {
+		t.Errorf("status = %+v", status)
+	}
+
+	if published := formField(form.Fields, "published_at"); published == nil || published.Type != models.FormFieldDateTime || published.Required {
+		t.Errorf("published_at = %+v", published)
+	}
+
+	tema := formField(form.Fields, "tema_geral")
+	if tema == nil || len(tema.Options) != 2 || tema.Options[0] != "Educação" || tema.OptionsStrict {
+		t.Errorf("tema_geral = %+v", tema)
+	}
+	if sub := formField(form.Fields, "sub_categoria"); sub == nil || sub.DependsOn != "tema_geral" || len(sub.OptionsBy["Saúde"]) != 1 {
+		t.Errorf("sub_categoria = %+v", sub)
+	}
+	if bairros := formField(form.Fields, "bairros"); bairros == nil || len(bairros.Options) != 1 || bairros.MaxItems != 200 {
+		t.Errorf("bairros = %+v", bairros)
+	}
+
+	buttons := formField(form.Fields, "buttons")
+	if buttons == nil || buttons.Type != models.FormFieldObjects || buttons.MaxItems != 20 {
+		t.Fatalf("buttons = %+v", buttons)
+	}
+	action := formField(buttons.Fields, "action_type")
+	if action == nil || !action.OptionsStrict || len(action.Options) != 4 || action.Label != "Tipo de ação" {
+		t.Errorf("buttons.action_type = %+v", action)
+	}
+	if agents := formField(form.Fields, "agents"); agents == nil || agents.Type != models.FormFieldObject || len(agents.Fields) != 2 {
+		t.Errorf("agents = %+v", agents)
+	}
+
+	// Todo campo tem rótulo próprio
+	var check func(fields []models.FormField)
+	check = func(fields []models.FormField) {
+		for _, f := range fields {
+			if f.Label == f.Name {
+				t.Errorf("campo %s sem rótulo", f.Name)
+			}
+			check(f.Fields)
+		}
+	}
+	check(form.Fields)
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+// ErrNotPublishedAsOf indica serviço sem versão publicada no instante consultado: ainda não
+// criado, em rascunho, despublicado ou removido
+var ErrNotPublishedAsOf = errors.New("serviço não estava publicado na data informada")
+
+// brasilia fuso das datas sem horário (sem horário de verão desde 2019)
+var brasilia = time.FixedZone("BRT", -3*60*60)
+
+// ParseAsOf interpreta o parâmetro as_of: timestamp unix em segundos, RFC3339 ou data
+// (AAAA-MM-DD, considerada ao fim do dia no horário de Brasília)
+func ParseAsOf(raw string) (int64, error) {
+	if ts, err := strconv.ParseInt(raw, 10, 64); err == nil && ts > 0 {
+		return ts, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t.Unix(), nil
+	}
+	if day, err := time.ParseInLocation("2006-01-02", raw, brasilia); err == nil {
+		return day.Add(24*time.Hour - time.Second).Unix(), nil
+	}
+	return 0, fmt.Errorf("as_of inválido: use timestamp unix, RFC3339 ou AAAA-MM-DD")
+}
+
+// ServiceStateAsOf reconstrói o serviço como estava publicado a partir da versão vigente no
+// instante. Versões de remoção ou com status diferente de publicado resultam em
+// ErrNotPublishedAsOf, para não expor rascunhos.
+func ServiceStateAsOf(serviceID string, version *models.ServiceVersion, validUntil, asOf int64) (*models.ServiceAsOf, error) {
+	if version == nil || version.ChangeType == "delete" || version.Status != 1 {
+		return nil, ErrNotPublishedAsOf
+	}
+
+	return &models.ServiceAsOf{
+		AsOf:          asOf,
+		VersionNumber: version.VersionNumber,
+		ValidFrom:     version.CreatedAt,
+		ValidUntil:    validUntil,
+		Service:       ServiceFromVersion(serviceID, version),
+	}, nil
+}
+
+// ServiceFromVersion reconstrói o serviço a partir do snapshot da versão. Os snapshots guardam
+// apenas os campos de conteúdo: botões, extra_fields, anexos, bairros, tenant, embedding e
+// deep links não são recuperados.
+func ServiceFromVersion(serviceID string, version *models.ServiceVersion) *models.PrefRioService {
+	service := &models.PrefRioService{
+		ID:                    serviceID,
+		NomeServico:           version.NomeServico,
+		OrgaoGestor:           version.OrgaoGestor,
+		Resumo:                version.Resumo,
+		TempoAtendimento:      version.TempoAtendimento,
+		CustoServico:          version.CustoServico,
+		ResultadoSolicitacao:  version.ResultadoSolicitacao,
+		DescricaoCompleta:     version.DescricaoCompleta,
+		Autor:                 version.Autor,
+		DocumentosNecessarios: version.DocumentosNecessarios,
+		InstrucoesSolicitante: version.InstrucoesSolicitante,
+		CanaisDigitais:        version.CanaisDigitais,
+		CanaisPresenciais:     version.CanaisPresenciais,
+		ServicoNaoCobre:       version.ServicoNaoCobre,
+		LegislacaoRelacionada: version.LegislacaoRelacionada,
+		TemaGeral:             version.TemaGeral,
+		PublicoEspecifico:     version.PublicoEspecifico,
+		FixarDestaque:         version.FixarDestaque,
+		AwaitingApproval:      version.AwaitingApproval,
+		PublishedAt:           version.PublishedAt,
+		IsFree:                version.IsFree,
+		Status:                version.Status,
+		SearchContent:         version.SearchContent,
+		LastUpdate:            version.CreatedAt,
+	}
+	service.SetPlaintextFields()
+	return service
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestParseAsOf(t *testing.T) {
+	tests := map[string]int64{
+		"1700000000":                1700000000,
+		"2024-03-01T12:00:00-03:00": 1709305200,
+		"2024-03-01":                1709348399, // 23:59:59 em Brasília
+	}
+	for raw, want := range tests {
+		got, err := ParseAsOf(raw)
+		if err != nil || got != want {
+			t.Errorf("ParseAsOf(%q) = %d, %v; esperado %d", raw, got, err, want)
+		}
+	}
+
+	for _, raw := range []string{"ontem", "-5", "01/03/2024"} {
+		if _, err := ParseAsOf(raw); err == nil {
+			t.Errorf("ParseAsOf(%q) deveria falhar", raw)
+		}
+	}
+}
+
+func TestServiceStateAsOf(t *testing.T) {
+	version := &models.ServiceVersion{
+		ServiceID:     "svc",
+		VersionNumber: 3,
+		CreatedAt:     100,
+		ChangeType:    "update",
+		NomeServico:   "IPTU",
+		CustoServico:  "R$ 10,00",
+		Status:        1,
+	}
+
+	state, err := ServiceStateAsOf("svc", version, 200, 150)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if state.VersionNumber != 3 || state.ValidFrom != 100 || state.ValidUntil != 200 || state.AsOf != 150 {
+		t.Errorf("vigência inesperada: %+v", state)
+	}
+	if state.Service.ID != "svc" || state.Service.CustoServico != "R$ 10,00" {
+		t.Errorf("serviço não reconstruído: %+v", state.Service)
+	}
+
+	draft := *version
+	draft.Status = 0
+	removed := *version
+	removed.ChangeType = "delete"
+	for _, v := range []*models.ServiceVersion{nil, &draft, &removed} {
+		if _, err := ServiceStateAsOf("svc", v, 0, 150); !errors.Is(err, ErrNotPublishedAsOf) {
+			t.Errorf("esperado ErrNotPublishedAsOf, obtido %v", err)
+		}
+	}
+}
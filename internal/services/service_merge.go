@@ -0,0 +1,56 @@
+package services
+
+import "github.com/prefeitura-rio/app-busca-search/internal/models"
+
+// MergeMissingFields copia para o destino os campos de conteúdo que estão vazios nele e
+// preenchidos na origem, devolvendo os nomes (json) dos campos copiados. Campos com significado
+// próprio quando vazios (bairros = toda a cidade) e os derivados da indexação não são copiados.
+func MergeMissingFields(target, source *models.PrefRioService) []string {
+	copied := []string{}
+	copyString := func(name string, dst *string, src string) {
+		if *dst == "" && src != "" {
+			*dst = src
+			copied = append(copied, name)
+		}
+	}
+	copyStrings := func(name string, dst *[]string, src []string) {
+		if len(*dst) == 0 && len(src) > 0 {
+			*dst = append([]string(nil), src...)
+			copied = append(copied, name)
+		}
+	}
+
+	copyString("resumo", &target.Resumo, source.Resumo)
+	copyString("tempo_atendimento", &target.TempoAtendimento, source.TempoAtendimento)
+	copyString("custo_servico", &target.CustoServico, source.CustoServico)
+	copyString("resultado_solicitacao", &target.ResultadoSolicitacao, source.ResultadoSolicitacao)
+	copyString("descricao_completa", &target.DescricaoCompleta, source.DescricaoCompleta)
+	copyStrings("documentos_necessarios", &target.DocumentosNecessarios, source.DocumentosNecessarios)
+	copyString("instrucoes_solicitante", &target.InstrucoesSolicitante, source.InstrucoesSolicitante)
+	copyStrings("canais_digitais", &target.CanaisDigitais, source.CanaisDigitais)
+	copyStrings("canais_presenciais", &target.CanaisPresenciais, source.CanaisPresenciais)
+	copyString("servico_nao_cobre", &target.ServicoNaoCobre, source.ServicoNaoCobre)
+	copyStrings("legislacao_relacionada", &target.LegislacaoRelacionada, source.LegislacaoRelacionada)
+	copyStrings("publico_especifico", &target.PublicoEspecifico, source.PublicoEspecifico)
+
+	if (target.SubCategoria == nil || *target.SubCategoria == "") && source.SubCategoria != nil && *source.SubCategoria != "" {
+		subCategoria := *source.SubCategoria
+		target.SubCategoria = &subCategoria
+		copied = append(copied, "sub_categoria")
+	}
+	if target.IsFree == nil && source.IsFree != nil {
+		isFree := *source.IsFree
+		target.IsFree = &isFree
+		copied = append(copied, "is_free")
+	}
+	if len(target.Buttons) == 0 && len(source.Buttons) > 0 {
+		target.Buttons = append([]models.Button(nil), source.Buttons...)
+		copied = append(copied, "buttons")
+	}
+	if len(target.Attachments) == 0 && len(source.Attachments) > 0 {
+		target.Attachments = append([]models.Attachment(nil), source.Attachments...)
+		copied = append(copied, "attachments")
+	}
+
+	return copied
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestMergeMissingFields(t *testing.T) {
+	subCategoria := "vacinação"
+	isFree := true
+	target := &models.PrefRioService{
+		Resumo:         "Resumo do destino",
+		CanaisDigitais: []string{"https://destino"},
+	}
+	source := &models.PrefRioService{
+		Resumo:                "Resumo da origem",
+		CustoServico:          "Gratuito",
+		CanaisDigitais:        []string{"https://origem"},
+		DocumentosNecessarios: []string{"CPF"},
+		SubCategoria:          &subCategoria,
+		IsFree:                &isFree,
+		Bairros:               []string{"Tijuca"},
+	}
+
+	copied := MergeMissingFields(target, source)
+
+	expected := []string{"custo_servico", "documentos_necessarios", "sub_categoria", "is_free"}
+	if !reflect.DeepEqual(copied, expected) {
+		t.Errorf("esperado %v, obtido %v", expected, copied)
+	}
+	if target.Resumo != "Resumo do destino" || target.CanaisDigitais[0] != "https://destino" {
+		t.Error("campos preenchidos do destino não devem ser sobrescritos")
+	}
+	if target.CustoServico != "Gratuito" || target.IsFree == nil || !*target.IsFree || *target.SubCategoria != subCategoria {
+		t.Errorf("campos vazios do destino não foram copiados: %+v", target)
+	}
+	if len(target.Bairros) != 0 {
+		t.Error("bairros não devem ser copiados (vazio = toda a cidade)")
+	}
+
+	source.DocumentosNecessarios[0] = "RG"
+	if target.DocumentosNecessarios[0] != "CPF" {
+		t.Error("listas copiadas não devem compartilhar o array da origem")
+	}
+
+	if copied := MergeMissingFields(target, source); len(copied) != 0 {
+		t.Errorf("segunda mesclagem não deve copiar nada, obtido %v", copied)
+	}
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/pdf"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+)
+
+// servicePDFTTL tempo de vida da ficha renderizada no cache; a chave muda a cada nova versão
+const servicePDFTTL = 24 * time.Hour
+
+// ServicePDFRenderer monta a ficha em PDF de um serviço para o atendimento presencial: nome,
+// órgão, textos com o markdown convertido, documentos, canais e legislação. As fichas ficam em
+// cache por versão do serviço.
+type ServicePDFRenderer struct {
+	cache *LRUCache
+}
+
+// NewServicePDFRenderer cria o renderizador com um cache de até capacity fichas
+func NewServicePDFRenderer(capacity int) *ServicePDFRenderer {
+	return &ServicePDFRenderer{cache: NewLRUCache(capacity)}
+}
+
+// Render devolve a ficha do serviço na versão informada. A data de atualização também compõe a
+// chave, para que serviços sem histórico de versões não recebam uma ficha desatualizada.
+func (r *ServicePDFRenderer) Render(service *models.PrefRioService, version int64) ([]byte, error) {
+	key := fmt.Sprintf("pdf:%s:%d:%d", service.ID, version, service.LastUpdate)
+	if cached, ok := r.cache.Get(key).([]byte); ok {
+		return cached, nil
+	}
+
+	data, err := buildServicePDF(service, version).Render()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao renderizar PDF do serviço: %w", err)
+	}
+	r.cache.Set(key, data, servicePDFTTL)
+	return data, nil
+}
+
+// buildServicePDF dispõe as seções da ficha; seções vazias são omitidas
+func buildServicePDF(service *models.PrefRioService, version int64) *pdf.Document {
+	doc := pdf.New(service.NomeServico, "pt-BR")
+
+	footer := "Atualizado em " + time.Unix(service.LastUpdate, 0).In(brasilia).Format("02/01/2006")
+	if version > 0 {
+		footer = fmt.Sprintf("Versão %d · %s", version, footer)
+	}
+	doc.SetFooter(footer)
+
+	doc.Heading(1, service.NomeServico)
+	if len(service.OrgaoGestor) > 0 {
+		doc.Paragraph("Órgão gestor: " + strings.Join(service.OrgaoGestor, ", "))
+	}
+	if service.TemaGeral != "" {
+		doc.Paragraph("Categoria: " + service.TemaGeral)
+	}
+
+	markdownSection(doc, "Resumo", service.Resumo)
+	markdownSection(doc, "Descrição", service.DescricaoCompleta)
+	listSection(doc, "Documentos necessários", service.DocumentosNecessarios)
+	markdownSection(doc, "Como solicitar", service.InstrucoesSolicitante)
+	markdownSection(doc, "Tempo de atendimento", service.TempoAtendimento)
+	markdownSection(doc, "Custo", service.CustoServico)
+	markdownSection(doc, "Resultado da solicitação", service.ResultadoSolicitacao)
+	markdownSection(doc, "O que o serviço não cobre", service.ServicoNaoCobre)
+	listSection(doc, "Canais digitais", service.CanaisDigitais)
+	listSection(doc, "Canais presenciais", service.CanaisPresenciais)
+	listSection(doc, "Legislação relacionada", service.LegislacaoRelacionada)
+
+	return doc
+}
+
+func markdownSection(doc *pdf.Document, title, text string) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	doc.Heading(2, title)
+	appendMarkdown(doc, text)
+}
+
+func listSection(doc *pdf.Document, title string, items []string) {
+	var texts []string
+	for _, item := range items {
+		if text := markdownInlineText(utils.ParseMarkdown(item)); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	if len(texts) == 0 {
+		return
+	}
+	doc.Heading(2, title)
+	doc.List(texts)
+}
+
+// appendMarkdown converte os blocos do markdown em títulos, parágrafos e listas do PDF
+func appendMarkdown(doc *pdf.Document, text string) {
+	for _, node := range utils.ParseMarkdown(text).GetChildren() {
+		switch n := node.(type) {
+		case *ast.Heading:
+			doc.Heading(3, markdownInlineText(n))
+		case *ast.List:
+			var items []string
+			for _, item := range n.Children {
+				items = append(items, markdownInlineText(item))
+			}
+			doc.List(items)
+		default:
+			doc.Paragraph(markdownInlineText(n))
+		}
+	}
+}
+
+// markdownInlineText extrai o texto do nó. Links impressos trazem o endereço entre parênteses,
+// já que não podem ser clicados no papel.
+func markdownInlineText(node ast.Node) string {
+	var b strings.Builder
+	ast.WalkFunc(node, func(n ast.Node, entering bool) ast.WalkStatus {
+		switch n := n.(type) {
+		case *ast.Text:
+			if entering {
+				b.Write(n.Literal)
+			}
+		case *ast.Code:
+			if entering {
+				b.Write(n.Literal)
+			}
+		case *ast.CodeBlock:
+			if entering {
+				b.Write(n.Literal)
+			}
+		case *ast.Softbreak, *ast.Hardbreak:
+			if entering {
+				b.WriteString(" ")
+			}
+		case *ast.HTMLSpan, *ast.HTMLBlock:
+			return ast.SkipChildren
+		case *ast.Link:
+			if !entering {
+				if destination := string(n.Destination); destination != "" && !strings.Contains(b.String(), destination) {
+					b.WriteString(" (" + destination + ")")
+				}
+			}
+		case *ast.Paragraph, *ast.ListItem:
+			if !entering {
+				b.WriteString(" ")
+			}
+		}
+		return ast.GoToNext
+	})
+	return strings.Join(strings.Fields(b.String()), " ")
+}
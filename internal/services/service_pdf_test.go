@@ -0,0 +1,51 @@
+package services
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+)
+
+func TestMarkdownInlineText(t *testing.T) {
+	cases := map[string]string{
+		"Acesse o **portal** [Carioca Digital](https://carioca.rio) e\nsolicite": "Acesse o portal Carioca Digital (https://carioca.rio) e solicite",
+		"<https://carioca.rio>":          "https://carioca.rio",
+		"Texto com <b>html</b> `código`": "Texto com html código",
+	}
+	for input, expected := range cases {
+		if got := markdownInlineText(utils.ParseMarkdown(input)); got != expected {
+			t.Errorf("%q: esperado %q, obtido %q", input, expected, got)
+		}
+	}
+}
+
+func TestServicePDFRendererCachesByVersion(t *testing.T) {
+	renderer := NewServicePDFRenderer(10)
+	service := &models.PrefRioService{
+		ID:                    "abc",
+		NomeServico:           "Matrícula escolar",
+		OrgaoGestor:           []string{"SME"},
+		Resumo:                "Matrícula na **rede municipal**.\n\n- Ensino fundamental\n- Creche",
+		DocumentosNecessarios: []string{"CPF", "Comprovante de residência"},
+		LastUpdate:            1700000000,
+	}
+
+	first, err := renderer.Render(service, 3)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if !bytes.HasPrefix(first, []byte("%PDF-")) {
+		t.Fatal("resposta não é um PDF")
+	}
+
+	// A mesma versão vem do cache, mesmo que o documento em memória tenha mudado
+	service.NomeServico = "Outro nome"
+	if cached, _ := renderer.Render(service, 3); !bytes.Equal(cached, first) {
+		t.Error("esperada a ficha em cache para a mesma versão")
+	}
+	if updated, _ := renderer.Render(service, 4); bytes.Equal(updated, first) {
+		t.Error("nova versão deve gerar uma nova ficha")
+	}
+}
@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+// ServiceValidator valida lotes de serviços sem gravar, para sistemas externos (CMS) checarem
+// o conteúdo antes de enviar: aplica a mesma validação do cadastro e aponta, como avisos, tema
+// fora da taxonomia, links inválidos ou inacessíveis e serviços com o mesmo nome
+type ServiceValidator struct {
+	validate          *validator.Validate
+	classifier        *CategoryClassifier
+	extraFieldSchemas *ExtraFieldSchemas
+	neighborhoods     *Neighborhoods
+	orgaos            *Orgaos
+	links             *linkChecker
+
+	// listNames retorna os IDs dos serviços do tenant do contexto pelo nome normalizado
+	listNames func(ctx context.Context) (map[string][]string, error)
+}
+
+// NewServiceValidator cria o validador com os registros usados no cadastro; registros nil
+// não são aplicados
+func NewServiceValidator(client *typesense.Client, classifier *CategoryClassifier, extraFieldSchemas *ExtraFieldSchemas, neighborhoods *Neighborhoods, orgaos *Orgaos) *ServiceValidator {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	return &ServiceValidator{
+		validate:          validate,
+		classifier:        classifier,
+		extraFieldSchemas: extraFieldSchemas,
+		neighborhoods:     neighborhoods,
+		orgaos:            orgaos,
+		links:             newLinkChecker(5*time.Second, 8),
+		listNames: func(ctx context.Context) (map[string][]string, error) {
+			return listServiceNames(ctx, client)
+		},
+	}
+}
+
+// Validate valida cada serviço do lote e retorna o relatório
+func (sv *ServiceValidator) Validate(ctx context.Context, request *models.ServiceValidationRequest) (*models.ServiceValidationReport, error) {
+	existing, err := sv.listNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar serviços para detectar duplicatas: %w", err)
+	}
+
+	var taxonomy map[string][]string
+	if sv.classifier != nil {
+		taxonomy = sv.classifier.Taxonomy(ctx)
+	}
+
+	report := &models.ServiceValidationReport{Results: make([]models.ServiceValidationResult, len(request.Services))}
+	batchNames := make(map[string]int) // nome normalizado → índice do primeiro do lote
+	batchIDs := make(map[string]int)
+	links := make(map[int]map[string][]string) // índice → URL → campos
+	for i := range request.Services {
+		item := &request.Services[i]
+		result := &report.Results[i]
+		*result = models.ServiceValidationResult{
+			Index:       i,
+			ID:          item.ID,
+			NomeServico: item.NomeServico,
+			Errors:      []models.ServiceValidationIssue{},
+			Warnings:    []models.ServiceValidationIssue{},
+		}
+
+		result.Errors = append(result.Errors, sv.requestErrors(&item.PrefRioServiceRequest)...)
+		result.Warnings = append(result.Warnings, taxonomyWarnings(taxonomy, item.TemaGeral, item.SubCategoria)...)
+
+		linkWarnings, itemLinks := requestLinks(&item.PrefRioServiceRequest)
+		result.Warnings = append(result.Warnings, linkWarnings...)
+		links[i] = itemLinks
+
+		if item.ID != "" {
+			if first, ok := batchIDs[item.ID]; ok {
+				result.Errors = append(result.Errors, models.ServiceValidationIssue{
+					Code: models.ServiceIssueDuplicate, Field: "id",
+					Message: fmt.Sprintf("ID repetido no lote (serviço %d)", first),
+				})
+			} else {
+				batchIDs[item.ID] = i
+			}
+		}
+		result.Warnings = append(result.Warnings, duplicateNameWarnings(item, i, existing, batchNames)...)
+	}
+
+	if request.CheckLinks {
+		sv.checkLinks(ctx, report, links)
+	}
+
+	for i := range report.Results {
+		result := &report.Results[i]
+		result.Valid = len(result.Errors) == 0
+		if result.Valid {
+			report.Valid++
+		} else {
+			report.Invalid++
+		}
+		report.Warnings += len(result.Warnings)
+	}
+	report.Total = len(report.Results)
+	return report, nil
+}
+
+// requestErrors aplica as validações do cadastro de serviços
+func (sv *ServiceValidator) requestErrors(request *models.PrefRioServiceRequest) []models.ServiceValidationIssue {
+	var issues []models.ServiceValidationIssue
+	if err := sv.validate.Struct(request); err != nil {
+		var fieldErrors validator.ValidationErrors
+		if !errors.As(err, &fieldErrors) {
+			return append(issues, models.ServiceValidationIssue{Code: models.ServiceIssueSchema, Message: err.Error()})
+		}
+		for _, fe := range fieldErrors {
+			field := fe.Namespace()
+			if dot := strings.Index(field, "."); dot >= 0 {
+				field = field[dot+1:]
+			}
+			rule := fe.Tag()
+			if fe.Param() != "" {
+				rule += "=" + fe.Param()
+			}
+			issues = append(issues, models.ServiceValidationIssue{
+				Code:    models.ServiceIssueSchema,
+				Field:   field,
+				Message: fmt.Sprintf("%s não atende à regra %s", field, rule),
+			})
+		}
+	}
+
+	checks := []struct {
+		code  string
+		field string
+		err   error
+	}{
+		{models.ServiceIssueAvailability, "available_until", ValidateAvailabilityWindow(request.AvailableFrom, request.AvailableUntil)},
+		{models.ServiceIssueInput, "", ValidateServiceInput(request)},
+		{models.ServiceIssueExtraFields, "extra_fields", sv.extraFieldSchemas.Validate(request.TemaGeral, request.ExtraFields)},
+		{models.ServiceIssueOrgao, "orgao_gestor", sv.orgaos.Validate(request.OrgaoGestor)},
+	}
+	for _, check := range checks {
+		if check.err != nil {
+			issues = append(issues, models.ServiceValidationIssue{Code: check.code, Field: check.field, Message: check.err.Error()})
+		}
+	}
+	if _, _, err := sv.neighborhoods.Canonicalize(request.Bairros); err != nil {
+		issues = append(issues, models.ServiceValidationIssue{Code: models.ServiceIssueBairros, Field: "bairros", Message: err.Error()})
+	}
+	return issues
+}
+
+// taxonomyWarnings aponta tema vazio ou fora da taxonomia (o cadastro aceita e gera sugestão de
+// categoria) e subcategoria desconhecida (descartada pela classificação)
+func taxonomyWarnings(taxonomy map[string][]string, tema string, subCategoria *string) []models.ServiceValidationIssue {
+	if taxonomy == nil {
+		return nil
+	}
+	if strings.TrimSpace(tema) == "" {
+		return []models.ServiceValidationIssue{{
+			Code: models.ServiceIssueTaxonomy, Field: "tema_geral",
+			Message: "tema_geral vazio: uma categoria será sugerida automaticamente",
+		}}
+	}
+
+	sub := ""
+	if subCategoria != nil {
+		sub = strings.TrimSpace(*subCategoria)
+	}
+	_, canonicalSub, ok := matchTaxonomy(taxonomy, tema, sub)
+	switch {
+	case !ok:
+		return []models.ServiceValidationIssue{{
+			Code: models.ServiceIssueTaxonomy, Field: "tema_geral",
+			Message: fmt.Sprintf("tema_geral %q fora da taxonomia: uma categoria será sugerida automaticamente", tema),
+		}}
+	case sub != "" && canonicalSub == "":
+		return []models.ServiceValidationIssue{{
+			Code: models.ServiceIssueTaxonomy, Field: "sub_categoria",
+			Message: fmt.Sprintf("sub_categoria %q não existe no tema %s", sub, tema),
+		}}
+	}
+	return nil
+}
+
+// requestLinks aponta links inválidos nos campos em markdown e retorna os links http(s) do
+// serviço (markdown, botões e canais digitais) a verificar, com os campos em que aparecem
+func requestLinks(request *models.PrefRioServiceRequest) ([]models.ServiceValidationIssue, map[string][]string) {
+	var warnings []models.ServiceValidationIssue
+	links := make(map[string][]string)
+	add := func(link, field string) {
+		links[link] = append(links[link], field)
+	}
+
+	markdown := map[string][]string{
+		"resumo":                 {request.Resumo},
+		"tempo_atendimento":      {request.TempoAtendimento},
+		"custo_servico":          {request.CustoServico},
+		"resultado_solicitacao":  {request.ResultadoSolicitacao},
+		"descricao_completa":     {request.DescricaoCompleta},
+		"instrucoes_solicitante": {request.InstrucoesSolicitante},
+		"servico_nao_cobre":      {request.ServicoNaoCobre},
+		"documentos_necessarios": request.DocumentosNecessarios,
+	}
+	for _, field := range MarkdownFields {
+		for _, text := range markdown[field] {
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+			fieldWarnings, fieldLinks := inspectMarkdown(utils.ParseMarkdown(text))
+			for _, warning := range fieldWarnings {
+				if warning.Type == models.MarkdownWarningBrokenLink {
+					warnings = append(warnings, models.ServiceValidationIssue{Code: models.ServiceIssueURL, Field: field, Message: warning.Message})
+				}
+			}
+			for _, link := range fieldLinks {
+				add(link, field)
+			}
+		}
+	}
+
+	for i, button := range request.Buttons {
+		if isCheckableURL(button.URLService) {
+			add(strings.TrimSpace(button.URLService), fmt.Sprintf("buttons[%d].url_service", i))
+		}
+	}
+	for i, canal := range request.CanaisDigitais {
+		if isCheckableURL(canal) {
+			add(strings.TrimSpace(canal), fmt.Sprintf("canais_digitais[%d]", i))
+		}
+	}
+	return warnings, links
+}
+
+// checkLinks verifica os links de todo o lote de uma vez e aponta os inacessíveis
+func (sv *ServiceValidator) checkLinks(ctx context.Context, report *models.ServiceValidationReport, links map[int]map[string][]string) {
+	var all []string
+	for _, itemLinks := range links {
+		for link := range itemLinks {
+			all = append(all, link)
+		}
+	}
+	if len(all) == 0 {
+		return
+	}
+
+	results := sv.links.CheckAll(ctx, all)
+	for i, itemLinks := range links {
+		for link, fields := range itemLinks {
+			result := results[link]
+			if !result.Broken() {
+				continue
+			}
+			message := fmt.Sprintf("Link %s retornou status %d", link, result.StatusCode)
+			if result.Err != nil {
+				message = fmt.Sprintf("Link %s inacessível: %v", link, result.Err)
+			}
+			for _, field := range fields {
+				report.Results[i].Warnings = append(report.Results[i].Warnings, models.ServiceValidationIssue{
+					Code: models.ServiceIssueURL, Field: field, Message: message,
+				})
+			}
+		}
+	}
+}
+
+// duplicateNameWarnings aponta serviços já cadastrados ou anteriores no lote com o mesmo nome
+// (sem considerar acentos, maiúsculas nem espaços repetidos)
+func duplicateNameWarnings(item *models.ServiceValidationItem, index int, existing map[string][]string, batch map[string]int) []models.ServiceValidationIssue {
+	name := serviceNameKey(item.NomeServico)
+	if name == "" {
+		return nil
+	}
+
+	var warnings []models.ServiceValidationIssue
+	for _, id := range existing[name] {
+		if id != item.ID {
+			warnings = append(warnings, models.ServiceValidationIssue{
+				Code: models.ServiceIssueDuplicate, Field: "nome_servico",
+				Message: fmt.Sprintf("Já existe o serviço %s com o mesmo nome", id),
+			})
+		}
+	}
+	if first, ok := batch[name]; ok {
+		warnings = append(warnings, models.ServiceValidationIssue{
+			Code: models.ServiceIssueDuplicate, Field: "nome_servico",
+			Message: fmt.Sprintf("Mesmo nome do serviço %d do lote", first),
+		})
+	} else {
+		batch[name] = index
+	}
+	return warnings
+}
+
+func serviceNameKey(name string) string {
+	return strings.Join(strings.Fields(utils.NormalizarCategoria(name)), " ")
+}
+
+// listServiceNames lista os IDs dos serviços do tenant pelo nome normalizado
+func listServiceNames(ctx context.Context, client *typesense.Client) (map[string][]string, error) {
+	names := make(map[string][]string)
+	filterBy := tenant.ScopeFilter(ctx, "")
+	for page := 1; ; page++ {
+		params := &api.SearchCollectionParams{
+			Q:             pointer.String("*"),
+			IncludeFields: pointer.String("id,nome_servico"),
+			Page:          pointer.Int(page),
+			PerPage:       pointer.Int(250),
+		}
+		if filterBy != "" {
+			params.FilterBy = pointer.String(filterBy)
+		}
+		result, err := client.Collection(CollectionName).Documents().Search(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		if result.Hits == nil || len(*result.Hits) == 0 {
+			return names, nil
+		}
+
+		for _, hit := range *result.Hits {
+			if hit.Document == nil {
+				continue
+			}
+			doc := *hit.Document
+			if name := serviceNameKey(getString(doc, "nome_servico")); name != "" {
+				names[name] = append(names[name], getString(doc, "id"))
+			}
+		}
+		if result.Found == nil || page*250 >= *result.Found {
+			return names, nil
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestServiceValidatorValidate(t *testing.T) {
+	sv := NewServiceValidator(nil, nil, nil, nil, nil)
+	sv.listNames = func(ctx context.Context) (map[string][]string, error) {
+		return map[string][]string{"segunda via do iptu": {"iptu-1"}}, nil
+	}
+
+	valid := models.PrefRioServiceRequest{
+		NomeServico:       "Alvará de funcionamento",
+		OrgaoGestor:       []string{"SMDEIS"},
+		Resumo:            "Emissão do [alvará](https://carioca.rio/alvara)",
+		PublicoEspecifico: []string{"Empresas"},
+	}
+	invalid := valid
+	invalid.NomeServico = "Segunda via do  IPTU"
+	invalid.Resumo = ""
+	invalid.InstrucoesSolicitante = "Acesse [o portal]()"
+	invalid.AvailableFrom, invalid.AvailableUntil = 200, 100
+	repeated := valid
+	repeated.NomeServico = "alvara de FUNCIONAMENTO"
+
+	report, err := sv.Validate(context.Background(), &models.ServiceValidationRequest{Services: []models.ServiceValidationItem{
+		{ID: "alvara", PrefRioServiceRequest: valid},
+		{ID: "iptu-2", PrefRioServiceRequest: invalid},
+		{ID: "alvara", PrefRioServiceRequest: repeated},
+	}})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if report.Total != 3 || report.Valid != 1 || report.Invalid != 2 || report.Warnings != 3 {
+		t.Errorf("totais inesperados: %+v", report)
+	}
+
+	first := report.Results[0]
+	if !first.Valid || len(first.Errors) != 0 || len(first.Warnings) != 0 {
+		t.Errorf("serviço válido com problemas: %+v", first)
+	}
+
+	second := report.Results[1]
+	if second.Valid || !hasIssue(second.Errors, models.ServiceIssueSchema, "resumo") ||
+		!hasIssue(second.Errors, models.ServiceIssueAvailability, "available_until") {
+		t.Errorf("erros inesperados: %+v", second.Errors)
+	}
+	if !hasIssue(second.Warnings, models.ServiceIssueURL, "instrucoes_solicitante") ||
+		!hasIssue(second.Warnings, models.ServiceIssueDuplicate, "nome_servico") {
+		t.Errorf("avisos inesperados: %+v", second.Warnings)
+	}
+
+	third := report.Results[2]
+	if third.Valid || !hasIssue(third.Errors, models.ServiceIssueDuplicate, "id") ||
+		!hasIssue(third.Warnings, models.ServiceIssueDuplicate, "nome_servico") {
+		t.Errorf("duplicatas no lote não apontadas: %+v", third)
+	}
+}
+
+func TestTaxonomyWarnings(t *testing.T) {
+	taxonomy := map[string][]string{"Saúde": {"Vacinação"}}
+	sub := func(s string) *string { return &s }
+
+	tests := []struct {
+		tema  string
+		sub   *string
+		field string
+	}{
+		{"saude", sub("vacinacao"), ""},
+		{"Saúde", nil, ""},
+		{"", nil, "tema_geral"},
+		{"Esportes", nil, "tema_geral"},
+		{"Saúde", sub("Odontologia"), "sub_categoria"},
+	}
+	for _, tt := range tests {
+		warnings := taxonomyWarnings(taxonomy, tt.tema, tt.sub)
+		if tt.field == "" && len(warnings) != 0 || tt.field != "" && !hasIssue(warnings, models.ServiceIssueTaxonomy, tt.field) {
+			t.Errorf("tema %q: avisos inesperados: %+v", tt.tema, warnings)
+		}
+	}
+}
+
+func hasIssue(issues []models.ServiceValidationIssue, code, field string) bool {
+	for _, issue := range issues {
+		if issue.Code == code && issue.Field == field {
+			return true
+		}
+	}
+	return false
+}
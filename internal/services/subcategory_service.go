@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
 	"github.com/typesense/typesense-go/v3/typesense"
 	"github.com/typesense/typesense-go/v3/typesense/api"
 	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
@@ -99,6 +100,7 @@ func (scs *SubcategoryService) GetServicesBySubcategory(ctx context.Context, req
 			filterBy = fmt.Sprintf("sub_categoria:=`%s` && status:=1", req.Subcategory)
 		}
 	}
+	filterBy = tenant.ScopeFilter(ctx, filterBy)
 
 	searchParams := &api.SearchCollectionParams{
 		Q:        pointer.String("*"),
@@ -153,6 +155,7 @@ func (scs *SubcategoryService) fetchSubcategoriesWithFacets(ctx context.Context,
 		// Filtrar por categoria E status publicado
 		filterBy = fmt.Sprintf("tema_geral:=`%s` && status:=1", category)
 	}
+	filterBy = tenant.ScopeFilter(ctx, filterBy)
 
 	// Query com facet em sub_categoria
 	searchParams := &api.SearchCollectionParams{
@@ -0,0 +1,380 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/jobs"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+const (
+	SynonymCandidatesCollection = "synonym_candidates"
+
+	// synonymMiningDays período de jornadas analisado em cada execução
+	synonymMiningDays = 30
+	// synonymMaxServiceIDs serviços clicados mantidos como evidência de cada candidato
+	synonymMaxServiceIDs = 5
+)
+
+var (
+	// ErrSynonymCandidateNotFound indica candidato inexistente
+	ErrSynonymCandidateNotFound = errors.New("candidato a sinônimo não encontrado")
+
+	// ErrSynonymCandidateResolved indica candidato já aprovado ou rejeitado
+	ErrSynonymCandidateResolved = errors.New("candidato a sinônimo já revisado")
+)
+
+// synonymStopwords palavras ignoradas ao comparar os termos das queries
+var synonymStopwords = map[string]bool{"das": true, "dos": true, "para": true, "com": true, "por": true, "uma": true, "como": true}
+
+// reformulation evidências de um par de queries: a query abandonada e a reformulação clicada
+type reformulation struct {
+	from     string
+	to       string
+	journeys int
+	sessions map[string]bool
+	services map[string]int
+}
+
+// SynonymMiner propõe sinônimos a partir das jornadas de busca: quando usuários trocam uma
+// query por outra sem termos em comum e clicam em um resultado da nova (ex.: "xerox" →
+// "cópia autenticada"), o par entra na fila de candidatos para aprovação humana. A aprovação
+// cria o sinônimo na collection de serviços.
+type SynonymMiner struct {
+	client      *typesense.Client
+	journeys    *JourneyAnalytics
+	minSessions int
+}
+
+// NewSynonymMiner cria o minerador. Pares são propostos a partir de minSessions sessões
+// distintas com a reformulação seguida de clique.
+func NewSynonymMiner(client *typesense.Client, journeys *JourneyAnalytics, minSessions int) *SynonymMiner {
+	return &SynonymMiner{client: client, journeys: journeys, minSessions: max(minSessions, 1)}
+}
+
+// Mine analisa as jornadas recentes e atualiza a fila: pares novos com evidência suficiente
+// entram como pendentes, pendentes têm a evidência atualizada e revisados não são alterados
+func (sm *SynonymMiner) Mine(ctx context.Context) (*models.SynonymMiningResult, error) {
+	if err := sm.journeys.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+	events, err := sm.journeys.fetchEvents(ctx, time.Now().AddDate(0, 0, -synonymMiningDays).Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	pairs, journeys := mineReformulations(events)
+	result := &models.SynonymMiningResult{Journeys: journeys, Pairs: len(pairs)}
+
+	now := time.Now().Unix()
+	for _, pair := range pairs {
+		if len(pair.sessions) < sm.minSessions {
+			continue
+		}
+
+		candidate, err := sm.get(ctx, synonymCandidateID(pair.from, pair.to))
+		if err != nil && !errors.Is(err, ErrSynonymCandidateNotFound) {
+			return nil, err
+		}
+		if candidate == nil {
+			candidate = &models.SynonymCandidate{
+				ID:        synonymCandidateID(pair.from, pair.to),
+				From:      pair.from,
+				To:        pair.to,
+				Status:    models.SynonymCandidatePending,
+				CreatedAt: now,
+			}
+			result.Proposed++
+		} else if candidate.Status == models.SynonymCandidatePending {
+			result.Updated++
+		} else {
+			continue
+		}
+
+		candidate.Sessions = len(pair.sessions)
+		candidate.Journeys = pair.journeys
+		candidate.ServiceIDs = topServiceIDs(pair.services, synonymMaxServiceIDs)
+		candidate.UpdatedAt = now
+		if err := sm.save(ctx, candidate); err != nil {
+			return nil, fmt.Errorf("erro ao salvar candidato a sinônimo: %w", err)
+		}
+	}
+
+	log.Printf("[Synonyms] Mineração concluída: %d jornadas com reformulação, %d pares, %d novos candidatos",
+		result.Journeys, result.Pairs, result.Proposed)
+	return result, nil
+}
+
+// StartRoutine agenda a mineração diária (5h15), executada por uma única réplica (job
+// synonym-mining)
+func (sm *SynonymMiner) StartRoutine(runner *jobs.Runner) error {
+	return runner.Register("synonym-mining", "15 5 * * *", 15*time.Minute, func(ctx context.Context) error {
+		_, err := sm.Mine(ctx)
+		return err
+	})
+}
+
+// List retorna os candidatos com o status informado (vazio = todos), dos com mais sessões aos
+// com menos
+func (sm *SynonymMiner) List(ctx context.Context, status models.SynonymCandidateStatus, page, perPage int) (*models.SynonymCandidateList, error) {
+	if err := sm.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+
+	params := &api.SearchCollectionParams{
+		Q:       pointer.String("*"),
+		Page:    pointer.Int(page),
+		PerPage: pointer.Int(perPage),
+		SortBy:  pointer.String("sessions:desc,updated_at:desc"),
+	}
+	if status != "" {
+		params.FilterBy = pointer.String("status:=" + string(status))
+	}
+
+	result, err := sm.client.Collection(SynonymCandidatesCollection).Documents().Search(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar candidatos a sinônimo: %w", err)
+	}
+
+	list := &models.SynonymCandidateList{Candidates: []models.SynonymCandidate{}}
+	if result.Found != nil {
+		list.Total = *result.Found
+	}
+	if result.Hits != nil {
+		for _, hit := range *result.Hits {
+			if hit.Document == nil {
+				continue
+			}
+			if candidate, err := decodeSynonymCandidate(*hit.Document); err == nil {
+				list.Candidates = append(list.Candidates, *candidate)
+			}
+		}
+	}
+	return list, nil
+}
+
+// Approve cria o sinônimo na collection de serviços e marca o candidato como aprovado
+func (sm *SynonymMiner) Approve(ctx context.Context, id string, request *models.ApproveSynonymRequest, userName string) (*models.SynonymCandidate, error) {
+	candidate, err := sm.pending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	synonymID := "mined-" + candidate.ID
+	schema := &api.SearchSynonymSchema{Root: pointer.String(candidate.From), Synonyms: []string{candidate.To}}
+	if request.MultiWay {
+		schema = &api.SearchSynonymSchema{Synonyms: []string{candidate.From, candidate.To}}
+	}
+	if _, err := sm.client.Collection(CollectionName).Synonyms().Upsert(ctx, synonymID, schema); err != nil {
+		return nil, fmt.Errorf("erro ao criar sinônimo: %w", err)
+	}
+
+	candidate.SynonymID = synonymID
+	return candidate, sm.resolve(ctx, candidate, models.SynonymCandidateApproved, userName)
+}
+
+// Reject descarta o candidato; o par não volta a ser proposto
+func (sm *SynonymMiner) Reject(ctx context.Context, id, userName string) (*models.SynonymCandidate, error) {
+	candidate, err := sm.pending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return candidate, sm.resolve(ctx, candidate, models.SynonymCandidateRejected, userName)
+}
+
+func (sm *SynonymMiner) pending(ctx context.Context, id string) (*models.SynonymCandidate, error) {
+	candidate, err := sm.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if candidate.Status != models.SynonymCandidatePending {
+		return nil, fmt.Errorf("%w: %s", ErrSynonymCandidateResolved, candidate.Status)
+	}
+	return candidate, nil
+}
+
+func (sm *SynonymMiner) resolve(ctx context.Context, candidate *models.SynonymCandidate, status models.SynonymCandidateStatus, userName string) error {
+	candidate.Status = status
+	candidate.ResolvedAt = time.Now().Unix()
+	candidate.ResolvedBy = userName
+	if err := sm.save(ctx, candidate); err != nil {
+		return fmt.Errorf("erro ao salvar candidato a sinônimo: %w", err)
+	}
+	return nil
+}
+
+func (sm *SynonymMiner) get(ctx context.Context, id string) (*models.SynonymCandidate, error) {
+	if err := sm.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+	doc, err := sm.client.Collection(SynonymCandidatesCollection).Document(id).Retrieve(ctx)
+	if err != nil {
+		if IsNotFoundError(err) {
+			return nil, ErrSynonymCandidateNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar candidato a sinônimo: %w", err)
+	}
+	return decodeSynonymCandidate(doc)
+}
+
+func decodeSynonymCandidate(doc map[string]interface{}) (*models.SynonymCandidate, error) {
+	raw, _ := doc["candidate"].(string)
+	var candidate models.SynonymCandidate
+	if err := json.Unmarshal([]byte(raw), &candidate); err != nil {
+		return nil, fmt.Errorf("erro ao deserializar candidato a sinônimo: %w", err)
+	}
+	return &candidate, nil
+}
+
+// save grava (ou atualiza) o candidato na collection synonym_candidates
+func (sm *SynonymMiner) save(ctx context.Context, candidate *models.SynonymCandidate) error {
+	if err := sm.ensureCollection(ctx); err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(candidate)
+	if err != nil {
+		return err
+	}
+	doc := map[string]interface{}{
+		"id":         candidate.ID,
+		"status":     string(candidate.Status),
+		"sessions":   candidate.Sessions,
+		"updated_at": candidate.UpdatedAt,
+		"candidate":  string(raw),
+	}
+	_, err = sm.client.Collection(SynonymCandidatesCollection).Documents().Upsert(ctx, doc, &api.DocumentIndexParameters{})
+	return err
+}
+
+// ensureCollection garante que a collection synonym_candidates existe
+func (sm *SynonymMiner) ensureCollection(ctx context.Context) error {
+	_, err := sm.client.Collection(SynonymCandidatesCollection).Retrieve(ctx)
+	if err == nil {
+		return nil
+	}
+
+	schema := &api.CollectionSchema{
+		Name: SynonymCandidatesCollection,
+		Fields: []api.Field{
+			{Name: "status", Type: "string", Facet: pointer.True()},
+			{Name: "sessions", Type: "int32", Facet: pointer.False()},
+			{Name: "updated_at", Type: "int64", Facet: pointer.False()},
+			{Name: "candidate", Type: "string", Index: pointer.False(), Optional: pointer.True()},
+		},
+		DefaultSortingField: pointer.String("updated_at"),
+	}
+
+	if _, err := sm.client.Collections().Create(ctx, schema); err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %w", SynonymCandidatesCollection, err)
+	}
+	return nil
+}
+
+// mineReformulations extrai das jornadas com clique (eventos em ordem cronológica) os pares
+// query abandonada → query clicada sem termos em comum. Retorna os pares e quantas jornadas
+// tiveram ao menos uma reformulação.
+func mineReformulations(events []models.JourneyEvent) (map[string]*reformulation, int) {
+	bySession := make(map[string][]models.JourneyEvent)
+	for _, event := range events {
+		bySession[event.SessionID] = append(bySession[event.SessionID], event)
+	}
+
+	pairs := make(map[string]*reformulation)
+	journeys := 0
+	for sessionID, sessionEvents := range bySession {
+		for _, j := range splitJourneys(sessionEvents) {
+			if !j.clicked || len(j.queries) < 2 {
+				continue
+			}
+
+			final := j.queries[len(j.queries)-1]
+			seen := make(map[string]bool)
+			found := false
+			for _, query := range j.queries[:len(j.queries)-1] {
+				if seen[query] || !isReformulation(query, final) {
+					continue
+				}
+				seen[query] = true
+				found = true
+
+				key := query + "\x00" + final
+				pair, ok := pairs[key]
+				if !ok {
+					pair = &reformulation{from: query, to: final, sessions: map[string]bool{}, services: map[string]int{}}
+					pairs[key] = pair
+				}
+				pair.journeys++
+				pair.sessions[sessionID] = true
+				pair.services[j.serviceID]++
+			}
+			if found {
+				journeys++
+			}
+		}
+	}
+	return pairs, journeys
+}
+
+// isReformulation indica se to substitui from em vez de refiná-la: as queries não têm termos
+// significativos em comum (ex.: "xerox" → "cópia autenticada", mas não "iptu" → "iptu 2025")
+func isReformulation(from, to string) bool {
+	fromTerms := synonymTerms(from)
+	toTerms := synonymTerms(to)
+	if len(fromTerms) == 0 || len(toTerms) == 0 {
+		return false
+	}
+	for term := range fromTerms {
+		if toTerms[term] {
+			return false
+		}
+	}
+	return true
+}
+
+// synonymTerms termos significativos da query, sem acentos
+func synonymTerms(query string) map[string]bool {
+	terms := make(map[string]bool)
+	for _, term := range strings.Fields(utils.NormalizarCategoria(query)) {
+		if len([]rune(term)) >= 3 && !synonymStopwords[term] {
+			terms[term] = true
+		}
+	}
+	return terms
+}
+
+// topServiceIDs serviços mais clicados (empates em ordem alfabética)
+func topServiceIDs(counts map[string]int, limit int) []string {
+	ids := make([]string, 0, len(counts))
+	for id := range counts {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if counts[ids[i]] != counts[ids[j]] {
+			return counts[ids[i]] > counts[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids
+}
+
+// synonymCandidateID identifica o par de queries
+func synonymCandidateID(from, to string) string {
+	return queryKeyID(from + "\x00" + to)
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestMineReformulations(t *testing.T) {
+	search := func(session, query string, ts int64) models.JourneyEvent {
+		return models.JourneyEvent{SessionID: session, Type: models.JourneyEventSearch, Query: query, Timestamp: ts}
+	}
+	click := func(session, serviceID string, ts int64) models.JourneyEvent {
+		return models.JourneyEvent{SessionID: session, Type: models.JourneyEventClick, ServiceID: serviceID, Timestamp: ts}
+	}
+
+	events := []models.JourneyEvent{
+		search("sessao-aaaa", "xerox", 0), search("sessao-aaaa", "cópia autenticada", 10), click("sessao-aaaa", "svc-1", 20),
+		// Mesma sessão repete a reformulação em outra jornada
+		search("sessao-aaaa", "xerox", 5000), search("sessao-aaaa", "copia autenticada", 5010), search("sessao-aaaa", "cópia autenticada", 5020), click("sessao-aaaa", "svc-2", 5030),
+		search("sessao-bbbb", "xerox", 0), search("sessao-bbbb", "cópia autenticada", 10), click("sessao-bbbb", "svc-1", 20),
+		// Refinamento (termo em comum) não é reformulação
+		search("sessao-cccc", "iptu", 0), search("sessao-cccc", "segunda via iptu", 10), click("sessao-cccc", "svc-3", 20),
+		// Reformulação sem clique não conta
+		search("sessao-dddd", "xerox", 0), search("sessao-dddd", "cópia autenticada", 10),
+	}
+
+	pairs, journeys := mineReformulations(events)
+	if journeys != 3 {
+		t.Errorf("jornadas com reformulação: esperado 3, obtido %d", journeys)
+	}
+
+	pair := pairs["xerox\x00cópia autenticada"]
+	if pair == nil {
+		t.Fatalf("par xerox → cópia autenticada não encontrado: %v", pairs)
+	}
+	if pair.journeys != 3 || len(pair.sessions) != 2 {
+		t.Errorf("evidência inesperada: %d jornadas, %d sessões", pair.journeys, len(pair.sessions))
+	}
+	if ids := topServiceIDs(pair.services, 5); !reflect.DeepEqual(ids, []string{"svc-1", "svc-2"}) {
+		t.Errorf("serviços inesperados: %v", ids)
+	}
+	// "copia autenticada" (sem acento) compartilha termos com a query clicada
+	if _, ok := pairs["copia autenticada\x00cópia autenticada"]; ok {
+		t.Error("variação de acentuação não deveria ser proposta")
+	}
+	if _, ok := pairs["iptu\x00segunda via iptu"]; ok {
+		t.Error("refinamento não deveria ser proposto")
+	}
+}
+
+func TestIsReformulation(t *testing.T) {
+	cases := map[[2]string]bool{
+		{"xerox", "cópia autenticada"}:          true,
+		{"iptu", "segunda via iptu"}:            false,
+		{"certidão de casamento", "Certidao"}:   false,
+		{"de", "cópia"}:                         false, // sem termos significativos
+		{"carteira para idosos", "riocard"}:     true,
+		{"vacina dos cães", "vacinação animal"}: true,
+	}
+	for pair, expected := range cases {
+		if got := isReformulation(pair[0], pair[1]); got != expected {
+			t.Errorf("%q → %q: esperado %v, obtido %v", pair[0], pair[1], expected, got)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+	"google.golang.org/genai"
+)
+
+const ServiceTranslationsCollection = "service_translations"
+
+var (
+	// ErrUnsupportedLanguage indica idioma fora de TRANSLATION_LANGUAGES
+	ErrUnsupportedLanguage = errors.New("idioma não configurado para tradução")
+
+	// ErrTranslationUnavailable indica que a tradução automática não está disponível (sem Gemini)
+	ErrTranslationUnavailable = errors.New("tradução automática indisponível")
+
+	// ErrTranslationNotFound indica revisão sem conteúdo e sem tradução automática prévia
+	ErrTranslationNotFound = errors.New("não há tradução automática para aprovar; envie os campos revisados")
+
+	// ErrTranslationReviewed indica tentativa de sobrescrever uma tradução revisada e atual
+	ErrTranslationReviewed = errors.New("tradução já revisada e atualizada; use force para traduzir novamente")
+)
+
+// languageNames nomes dos idiomas usados no prompt de tradução
+var languageNames = map[string]string{
+	"en": "inglês",
+	"es": "espanhol",
+	"fr": "francês",
+	"de": "alemão",
+	"it": "italiano",
+	"zh": "chinês simplificado",
+}
+
+const translationPrompt = `Traduza o conteúdo de um serviço público da Prefeitura do Rio de Janeiro do português para %s.
+
+Conteúdo (JSON):
+%s
+
+Regras:
+- Retorne APENAS um JSON com exatamente as mesmas chaves
+- Preserve a formatação markdown, URLs, telefones, siglas de órgãos e nomes próprios
+- Use linguagem simples, voltada ao cidadão`
+
+// TranslationService gerencia as traduções dos serviços: tradução automática (Gemini),
+// revisão humana e a situação por idioma
+type TranslationService struct {
+	client       *typesense.Client
+	geminiClient *genai.Client
+	model        string
+	languages    []string
+}
+
+// NewTranslationService cria o serviço de traduções para os idiomas informados (ex.: en, es)
+func NewTranslationService(client *typesense.Client, geminiClient *genai.Client, model string, languages []string) *TranslationService {
+	return &TranslationService{
+		client:       client,
+		geminiClient: geminiClient,
+		model:        model,
+		languages:    languages,
+	}
+}
+
+// Languages retorna os idiomas configurados
+func (ts *TranslationService) Languages() []string {
+	return ts.languages
+}
+
+// SupportsLanguage indica se o idioma está configurado
+func (ts *TranslationService) SupportsLanguage(lang string) bool {
+	for _, language := range ts.languages {
+		if language == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// List retorna a situação da tradução do serviço em cada idioma configurado
+func (ts *TranslationService) List(ctx context.Context, service *models.PrefRioService) (*models.ServiceTranslationList, error) {
+	if err := ts.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+
+	searchParams := &api.SearchCollectionParams{
+		Q:        pointer.String("*"),
+		FilterBy: pointer.String(fmt.Sprintf("service_id:=`%s`", service.ID)),
+		PerPage:  pointer.Int(100),
+	}
+	result, err := ts.client.Collection(ServiceTranslationsCollection).Documents().Search(ctx, searchParams)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar traduções: %w", err)
+	}
+
+	stored := make(map[string]*models.ServiceTranslation)
+	if result.Hits != nil {
+		for _, hit := range *result.Hits {
+			if hit.Document == nil {
+				continue
+			}
+			if translation, err := translationFromDocument(*hit.Document); err == nil {
+				stored[translation.Language] = translation
+			}
+		}
+	}
+
+	sourceHash := translationSourceHash(service)
+	list := &models.ServiceTranslationList{ServiceID: service.ID, Translations: []models.ServiceTranslation{}}
+	for _, lang := range ts.languages {
+		translation, ok := stored[lang]
+		if !ok {
+			list.Translations = append(list.Translations, models.ServiceTranslation{
+				ServiceID: service.ID,
+				Language:  lang,
+				Status:    models.TranslationMissing,
+			})
+			continue
+		}
+		translation.Outdated = translation.SourceHash != sourceHash
+		list.Translations = append(list.Translations, *translation)
+	}
+
+	return list, nil
+}
+
+// MachineTranslate traduz o serviço com o Gemini e registra a tradução como "machine". Uma
+// tradução revisada e atual só é sobrescrita com force.
+func (ts *TranslationService) MachineTranslate(ctx context.Context, service *models.PrefRioService, lang string, force bool) (*models.ServiceTranslation, error) {
+	if !ts.SupportsLanguage(lang) {
+		return nil, ErrUnsupportedLanguage
+	}
+	if ts.geminiClient == nil {
+		return nil, ErrTranslationUnavailable
+	}
+
+	sourceHash := translationSourceHash(service)
+	existing, err := ts.get(ctx, service.ID, lang)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Status == models.TranslationReviewed && existing.SourceHash == sourceHash && !force {
+		return nil, ErrTranslationReviewed
+	}
+
+	fields, err := ts.translateWithGemini(ctx, translationSource(service), lang)
+	if err != nil {
+		return nil, err
+	}
+
+	translation := &models.ServiceTranslation{
+		ServiceID:    service.ID,
+		Language:     lang,
+		Status:       models.TranslationMachine,
+		Fields:       fields,
+		SourceHash:   sourceHash,
+		Model:        ts.model,
+		TranslatedAt: time.Now().Unix(),
+	}
+	if err := ts.save(ctx, translation); err != nil {
+		return nil, err
+	}
+
+	return translation, nil
+}
+
+// Review registra a revisão humana. Sem fields, aprova a tradução automática existente.
+// A tradução revisada passa a corresponder ao conteúdo atual do serviço.
+func (ts *TranslationService) Review(ctx context.Context, service *models.PrefRioService, lang string, fields *models.ServiceTranslationFields, reviewer string) (*models.ServiceTranslation, error) {
+	if !ts.SupportsLanguage(lang) {
+		return nil, ErrUnsupportedLanguage
+	}
+
+	existing, err := ts.get(ctx, service.ID, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	translation := &models.ServiceTranslation{ServiceID: service.ID, Language: lang}
+	if existing != nil {
+		translation = existing
+	}
+	if fields == nil {
+		if existing == nil || existing.Fields == nil {
+			return nil, ErrTranslationNotFound
+		}
+		fields = existing.Fields
+	}
+	if strings.TrimSpace(fields.NomeServico) == "" || strings.TrimSpace(fields.Resumo) == "" {
+		return nil, fmt.Errorf("nome_servico e resumo traduzidos são obrigatórios")
+	}
+
+	now := time.Now().Unix()
+	translation.Status = models.TranslationReviewed
+	translation.Fields = fields
+	translation.SourceHash = translationSourceHash(service)
+	translation.Outdated = false
+	translation.ReviewedBy = reviewer
+	translation.ReviewedAt = now
+	if translation.TranslatedAt == 0 {
+		translation.TranslatedAt = now
+	}
+
+	if err := ts.save(ctx, translation); err != nil {
+		return nil, err
+	}
+
+	return translation, nil
+}
+
+func (ts *TranslationService) translateWithGemini(ctx context.Context, source *models.ServiceTranslationFields, lang string) (*models.ServiceTranslationFields, error) {
+	sourceJSON, err := json.Marshal(source)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar conteúdo: %w", err)
+	}
+
+	languageName := languageNames[lang]
+	if languageName == "" {
+		languageName = lang
+	}
+
+	prompt := fmt.Sprintf(translationPrompt, languageName, truncateText(string(sourceJSON), 30000))
+	config := &genai.GenerateContentConfig{ResponseMIMEType: "application/json"}
+
+	resp, err := ts.geminiClient.Models.GenerateContent(ctx, ts.model, []*genai.Content{genai.NewContentFromText(prompt, genai.RoleUser)}, config)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao chamar Gemini: %w", err)
+	}
+
+	var fields models.ServiceTranslationFields
+	if err := json.Unmarshal([]byte(strings.TrimSpace(resp.Text())), &fields); err != nil {
+		return nil, fmt.Errorf("erro ao parsear JSON do Gemini: %w", err)
+	}
+	if fields.NomeServico == "" {
+		return nil, fmt.Errorf("tradução sem nome_servico")
+	}
+
+	return &fields, nil
+}
+
+// get busca a tradução do serviço no idioma (nil se não existir)
+func (ts *TranslationService) get(ctx context.Context, serviceID, lang string) (*models.ServiceTranslation, error) {
+	if err := ts.ensureCollection(ctx); err != nil {
+		return nil, err
+	}
+
+	doc, err := ts.client.Collection(ServiceTranslationsCollection).Document(translationDocumentID(serviceID, lang)).Retrieve(ctx)
+	if err != nil {
+		if IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao buscar tradução: %w", err)
+	}
+
+	return translationFromDocument(doc)
+}
+
+func (ts *TranslationService) save(ctx context.Context, translation *models.ServiceTranslation) error {
+	fieldsJSON, err := json.Marshal(translation.Fields)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar tradução: %w", err)
+	}
+
+	doc := map[string]interface{}{
+		"id":            translationDocumentID(translation.ServiceID, translation.Language),
+		"service_id":    translation.ServiceID,
+		"language":      translation.Language,
+		"status":        string(translation.Status),
+		"source_hash":   translation.SourceHash,
+		"model":         translation.Model,
+		"translated_at": translation.TranslatedAt,
+		"reviewed_by":   translation.ReviewedBy,
+		"reviewed_at":   translation.ReviewedAt,
+		"fields":        string(fieldsJSON),
+	}
+
+	if _, err := ts.client.Collection(ServiceTranslationsCollection).Documents().Upsert(ctx, doc, &api.DocumentIndexParameters{}); err != nil {
+		return fmt.Errorf("erro ao salvar tradução: %w", err)
+	}
+	return nil
+}
+
+// ensureCollection garante que a collection service_translations existe
+func (ts *TranslationService) ensureCollection(ctx context.Context) error {
+	_, err := ts.client.Collection(ServiceTranslationsCollection).Retrieve(ctx)
+	if err == nil {
+		return nil
+	}
+
+	schema := &api.CollectionSchema{
+		Name: ServiceTranslationsCollection,
+		Fields: []api.Field{
+			{Name: "service_id", Type: "string", Facet: pointer.True()},
+			{Name: "language", Type: "string", Facet: pointer.True()},
+			{Name: "status", Type: "string", Facet: pointer.True()},
+			{Name: "source_hash", Type: "string", Index: pointer.False(), Optional: pointer.True()},
+			{Name: "model", Type: "string", Index: pointer.False(), Optional: pointer.True()},
+			{Name: "translated_at", Type: "int64", Facet: pointer.False()},
+			{Name: "reviewed_by", Type: "string", Facet: pointer.True(), Optional: pointer.True()},
+			{Name: "reviewed_at", Type: "int64", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "fields", Type: "string", Index: pointer.False(), Optional: pointer.True()},
+		},
+		DefaultSortingField: pointer.String("translated_at"),
+	}
+
+	if _, err := ts.client.Collections().Create(ctx, schema); err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %w", ServiceTranslationsCollection, err)
+	}
+
+	return nil
+}
+
+func translationFromDocument(doc map[string]interface{}) (*models.ServiceTranslation, error) {
+	translation := &models.ServiceTranslation{}
+	translation.ServiceID, _ = doc["service_id"].(string)
+	translation.Language, _ = doc["language"].(string)
+	status, _ := doc["status"].(string)
+	translation.Status = models.TranslationStatus(status)
+	translation.SourceHash, _ = doc["source_hash"].(string)
+	translation.Model, _ = doc["model"].(string)
+	translation.ReviewedBy, _ = doc["reviewed_by"].(string)
+	if value, ok := doc["translated_at"].(float64); ok {
+		translation.TranslatedAt = int64(value)
+	}
+	if value, ok := doc["reviewed_at"].(float64); ok {
+		translation.ReviewedAt = int64(value)
+	}
+
+	if raw, _ := doc["fields"].(string); raw != "" && raw != "null" {
+		var fields models.ServiceTranslationFields
+		if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+			return nil, fmt.Errorf("erro ao deserializar tradução: %w", err)
+		}
+		translation.Fields = &fields
+	}
+
+	return translation, nil
+}
+
+// translationSource extrai do serviço os campos traduzíveis
+func translationSource(service *models.PrefRioService) *models.ServiceTranslationFields {
+	return &models.ServiceTranslationFields{
+		NomeServico:           service.NomeServico,
+		Resumo:                service.Resumo,
+		ResultadoSolicitacao:  service.ResultadoSolicitacao,
+		DescricaoCompleta:     service.DescricaoCompleta,
+		InstrucoesSolicitante: service.InstrucoesSolicitante,
+		DocumentosNecessarios: service.DocumentosNecessarios,
+	}
+}
+
+// translationSourceHash identifica a versão do conteúdo original traduzido, para detectar
+// traduções desatualizadas
+func translationSourceHash(service *models.PrefRioService) string {
+	raw, _ := json.Marshal(translationSource(service))
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+func translationDocumentID(serviceID, lang string) string {
+	return serviceID + "_" + lang
+}
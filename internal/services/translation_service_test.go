@@ -0,0 +1,52 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestTranslationSourceHash(t *testing.T) {
+	service := &models.PrefRioService{NomeServico: "Segunda via do IPTU", Resumo: "Emita a guia", Status: 1}
+	original := translationSourceHash(service)
+
+	// Campos não traduzidos não tornam a tradução desatualizada
+	service.Status = 0
+	service.FixarDestaque = true
+	if translationSourceHash(service) != original {
+		t.Error("hash não deveria mudar com campos não traduzidos")
+	}
+
+	service.Resumo = "Emita a guia pela internet"
+	if translationSourceHash(service) == original {
+		t.Error("hash deveria mudar quando o conteúdo traduzível muda")
+	}
+}
+
+func TestTranslationFromDocument(t *testing.T) {
+	doc := map[string]interface{}{
+		"service_id":    "s1",
+		"language":      "en",
+		"status":        "reviewed",
+		"source_hash":   "abc",
+		"translated_at": float64(10),
+		"reviewed_at":   float64(20),
+		"reviewed_by":   "Ana",
+		"fields":        `{"nome_servico":"Property tax duplicate","resumo":"Issue the bill"}`,
+	}
+
+	translation, err := translationFromDocument(doc)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if translation.Status != models.TranslationReviewed || translation.ReviewedAt != 20 || translation.Fields == nil || translation.Fields.NomeServico != "Property tax duplicate" {
+		t.Errorf("tradução inesperada: %+v", translation)
+	}
+}
+
+func TestTranslationSupportsLanguage(t *testing.T) {
+	ts := NewTranslationService(nil, nil, "", []string{"en", "es"})
+	if !ts.SupportsLanguage("es") || ts.SupportsLanguage("fr") {
+		t.Error("idiomas suportados inesperados")
+	}
+}
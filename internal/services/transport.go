@@ -0,0 +1,361 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+)
+
+// Geocoder converte endereços em coordenadas; found false indica endereço não localizado
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) (lat, lng float64, found bool, err error)
+}
+
+// NominatimGeocoder geocodifica pela API de busca do Nominatim (OpenStreetMap), respeitando o
+// limite de uma requisição por segundo da política de uso
+type NominatimGeocoder struct {
+	baseURL    string
+	userAgent  string
+	httpClient *http.Client
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// NewNominatimGeocoder cria o geocodificador para a instância do Nominatim em baseURL
+func NewNominatimGeocoder(baseURL, userAgent string) *NominatimGeocoder {
+	return &NominatimGeocoder{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		userAgent:  userAgent,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Geocode retorna as coordenadas do primeiro resultado para o endereço
+func (g *NominatimGeocoder) Geocode(ctx context.Context, address string) (float64, float64, bool, error) {
+	if err := g.wait(ctx); err != nil {
+		return 0, 0, false, err
+	}
+
+	params := url.Values{}
+	params.Set("q", address)
+	params.Set("format", "jsonv2")
+	params.Set("limit", "1")
+	params.Set("countrycodes", "br")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.baseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	req.Header.Set("User-Agent", g.userAgent)
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("erro ao consultar geocodificador: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, false, fmt.Errorf("geocodificador retornou status %d", resp.StatusCode)
+	}
+
+	var results []struct {
+		Lat string `json:"lat"`
+		Lon string `json:"lon"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return 0, 0, false, fmt.Errorf("resposta inválida do geocodificador: %w", err)
+	}
+	if len(results) == 0 {
+		return 0, 0, false, nil
+	}
+
+	lat, errLat := strconv.ParseFloat(results[0].Lat, 64)
+	lng, errLng := strconv.ParseFloat(results[0].Lon, 64)
+	if errLat != nil || errLng != nil {
+		return 0, 0, false, fmt.Errorf("coordenadas inválidas do geocodificador: %s,%s", results[0].Lat, results[0].Lon)
+	}
+	return lat, lng, true, nil
+}
+
+// wait espaça as requisições em pelo menos um segundo
+func (g *NominatimGeocoder) wait(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if delay := time.Second - time.Since(g.last); delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	g.last = time.Now()
+	return nil
+}
+
+// TransportStopPoint estação de transporte com coordenadas
+type TransportStopPoint struct {
+	Nome  string
+	Modal string
+	Lat   float64
+	Lng   float64
+}
+
+// LoadTransportStops carrega as estações de um CSV com cabeçalho. Aceita as colunas nome, modal,
+// lat e lng ou as do stops.txt do GTFS (stop_name, stop_lat, stop_lon); sem coluna modal, usa
+// defaultModal (ex.: um arquivo por modal).
+func LoadTransportStops(path, defaultModal string) ([]TransportStopPoint, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir estações de transporte: %w", err)
+	}
+	defer file.Close()
+	return parseTransportStops(file, defaultModal)
+}
+
+func parseTransportStops(r io.Reader, defaultModal string) ([]TransportStopPoint, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("cabeçalho das estações de transporte: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	column := func(names ...string) int {
+		for _, name := range names {
+			if i, ok := columns[name]; ok {
+				return i
+			}
+		}
+		return -1
+	}
+	nomeCol, modalCol := column("nome", "stop_name"), column("modal")
+	latCol, lngCol := column("lat", "stop_lat"), column("lng", "lon", "stop_lon")
+	if nomeCol < 0 || latCol < 0 || lngCol < 0 {
+		return nil, fmt.Errorf("estações de transporte sem as colunas nome, lat e lng")
+	}
+
+	var stops []TransportStopPoint
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("estações de transporte, linha %d: %w", line, err)
+		}
+
+		field := func(i int) string {
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		lat, errLat := strconv.ParseFloat(field(latCol), 64)
+		lng, errLng := strconv.ParseFloat(field(lngCol), 64)
+		if errLat != nil || errLng != nil || field(nomeCol) == "" {
+			return nil, fmt.Errorf("estações de transporte, linha %d: nome ou coordenadas inválidos", line)
+		}
+		modal := strings.ToLower(field(modalCol))
+		if modal == "" {
+			modal = defaultModal
+		}
+		stops = append(stops, TransportStopPoint{Nome: field(nomeCol), Modal: modal, Lat: lat, Lng: lng})
+	}
+	return stops, nil
+}
+
+type geoPoint struct {
+	lat, lng float64
+	found    bool
+}
+
+// TransportEnricher geocodifica os canais presenciais dos serviços e associa as estações de
+// transporte público mais próximas, exibidas nos detalhes do serviço ("próximo ao BRT X")
+type TransportEnricher struct {
+	geocoder    Geocoder
+	stops       []TransportStopPoint
+	maxDistance float64 // metros
+	maxStops    int
+
+	mu    sync.Mutex
+	cache map[string]geoPoint // endereços já geocodificados
+}
+
+// NewTransportEnricher cria o enriquecedor com as estações carregadas; cada canal recebe até
+// maxStops estações a no máximo maxDistance metros
+func NewTransportEnricher(geocoder Geocoder, stops []TransportStopPoint, maxDistance float64, maxStops int) *TransportEnricher {
+	return &TransportEnricher{
+		geocoder:    geocoder,
+		stops:       stops,
+		maxDistance: maxDistance,
+		maxStops:    maxStops,
+		cache:       make(map[string]geoPoint),
+	}
+}
+
+// NewTransportEnricherFromConfig cria o enriquecedor com o Nominatim e as estações de
+// TRANSPORT_STOPS_FILE; retorna nil quando o arquivo não está configurado
+func NewTransportEnricherFromConfig(cfg *config.Config) (*TransportEnricher, error) {
+	if cfg.TransportStopsFile == "" {
+		return nil, nil
+	}
+
+	stops, err := LoadTransportStops(cfg.TransportStopsFile, "")
+	if err != nil {
+		return nil, err
+	}
+	geocoder := NewNominatimGeocoder(cfg.GeocoderURL, cfg.GeocoderUserAgent)
+	return NewTransportEnricher(geocoder, stops, float64(cfg.TransportMaxDistance), cfg.TransportMaxStops), nil
+}
+
+// Enabled indica se há geocodificador e estações para o enriquecimento
+func (e *TransportEnricher) Enabled() bool {
+	return e != nil && e.geocoder != nil && len(e.stops) > 0
+}
+
+// Enrich geocodifica os canais presenciais e retorna as estações próximas de cada um, além dos
+// canais que não puderam ser localizados
+func (e *TransportEnricher) Enrich(ctx context.Context, canais []string) (*models.ServiceTransport, []string, error) {
+	transport := &models.ServiceTransport{Locations: []models.PresencialLocation{}, EnrichedAt: time.Now().Unix()}
+	var unresolved []string
+
+	for _, canal := range canais {
+		address := presencialAddress(canal)
+		if address == "" {
+			continue
+		}
+
+		point, err := e.geocode(ctx, address)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !point.found {
+			unresolved = append(unresolved, canal)
+			continue
+		}
+
+		transport.Locations = append(transport.Locations, models.PresencialLocation{
+			Canal:      canal,
+			Lat:        point.lat,
+			Lng:        point.lng,
+			Transporte: nearestStops(e.stops, point.lat, point.lng, e.maxDistance, e.maxStops),
+		})
+	}
+	return transport, unresolved, nil
+}
+
+func (e *TransportEnricher) geocode(ctx context.Context, address string) (geoPoint, error) {
+	e.mu.Lock()
+	point, ok := e.cache[address]
+	e.mu.Unlock()
+	if ok {
+		return point, nil
+	}
+
+	query := address
+	if !strings.Contains(utils.NormalizarCategoria(address), "rio de janeiro") {
+		query += ", Rio de Janeiro - RJ"
+	}
+	lat, lng, found, err := e.geocoder.Geocode(ctx, query)
+	if err != nil {
+		return geoPoint{}, err
+	}
+
+	point = geoPoint{lat: lat, lng: lng, found: found}
+	e.mu.Lock()
+	e.cache[address] = point
+	e.mu.Unlock()
+	return point, nil
+}
+
+// presencialAddress extrai o texto do canal presencial, sem markdown; canais que são apenas
+// links não têm endereço
+func presencialAddress(canal string) string {
+	text := strings.Join(strings.Fields(utils.StripMarkdown(canal)), " ")
+	if strings.HasPrefix(text, "http://") || strings.HasPrefix(text, "https://") {
+		return ""
+	}
+	return text
+}
+
+// nearestStops retorna as estações mais próximas dentro do raio, uma por nome e modal
+func nearestStops(stops []TransportStopPoint, lat, lng, maxDistance float64, limit int) []models.TransportStop {
+	nearest := []models.TransportStop{}
+	seen := make(map[string]bool)
+	for _, stop := range stops {
+		distance := haversineMeters(lat, lng, stop.Lat, stop.Lng)
+		if distance > maxDistance {
+			continue
+		}
+		nearest = append(nearest, models.TransportStop{
+			Nome:            stop.Nome,
+			Modal:           stop.Modal,
+			DistanciaMetros: int(math.Round(distance)),
+			Descricao:       describeTransportStop(stop.Modal, stop.Nome),
+		})
+	}
+	sort.SliceStable(nearest, func(i, j int) bool { return nearest[i].DistanciaMetros < nearest[j].DistanciaMetros })
+
+	unique := nearest[:0]
+	for _, stop := range nearest {
+		key := stop.Modal + "|" + strings.ToLower(stop.Nome)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, stop)
+		if limit > 0 && len(unique) == limit {
+			break
+		}
+	}
+	return unique
+}
+
+// describeTransportStop texto exibido nos resultados (ex.: "próximo ao BRT Alvorada")
+func describeTransportStop(modal, nome string) string {
+	switch modal {
+	case models.ModalBRT:
+		return "próximo ao BRT " + nome
+	case models.ModalMetro:
+		return "próximo à estação " + nome + " do metrô"
+	case models.ModalVLT:
+		return "próximo ao VLT " + nome
+	case models.ModalTrem:
+		return "próximo à estação " + nome + " do trem"
+	case models.ModalBarcas:
+		return "próximo às barcas " + nome
+	}
+	return "próximo a " + nome
+}
+
+// haversineMeters distância em metros entre duas coordenadas
+func haversineMeters(lat1, lng1, lat2, lng2 float64) float64 {
+	const earthRadius = 6371000.0
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
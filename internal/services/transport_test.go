@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+type fakeGeocoder struct {
+	points  map[string][2]float64
+	queries []string
+}
+
+func (g *fakeGeocoder) Geocode(_ context.Context, address string) (float64, float64, bool, error) {
+	g.queries = append(g.queries, address)
+	if address == "erro, Rio de Janeiro - RJ" {
+		return 0, 0, false, errors.New("geocodificador indisponível")
+	}
+	point, ok := g.points[address]
+	return point[0], point[1], ok, nil
+}
+
+func TestParseTransportStops(t *testing.T) {
+	stops, err := parseTransportStops(strings.NewReader("\ufeffnome,modal,lat,lng\nAlvorada,BRT,-23.0006,-43.3657\nCarioca,metro,-22.9068,-43.1769\n"), "")
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if len(stops) != 2 || stops[0].Nome != "Alvorada" || stops[0].Modal != "brt" || stops[1].Lng != -43.1769 {
+		t.Errorf("estações = %+v", stops)
+	}
+
+	gtfs, err := parseTransportStops(strings.NewReader("stop_id,stop_name,stop_lat,stop_lon\n1,Central,-22.9035,-43.1911\n"), "trem")
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if len(gtfs) != 1 || gtfs[0].Nome != "Central" || gtfs[0].Modal != "trem" {
+		t.Errorf("estações GTFS = %+v", gtfs)
+	}
+
+	if _, err := parseTransportStops(strings.NewReader("nome,lat\nX,1\n"), ""); err == nil {
+		t.Error("esperado erro sem coluna lng")
+	}
+	if _, err := parseTransportStops(strings.NewReader("nome,lat,lng\nX,abc,1\n"), ""); err == nil {
+		t.Error("esperado erro com coordenada inválida")
+	}
+}
+
+func TestHaversineMeters(t *testing.T) {
+	// Central do Brasil -> Carioca: cerca de 1,5 km
+	d := haversineMeters(-22.9035, -43.1911, -22.9068, -43.1769)
+	if math.Abs(d-1500) > 200 {
+		t.Errorf("distância = %.0f, esperado ~1500", d)
+	}
+	if haversineMeters(-22.9, -43.1, -22.9, -43.1) != 0 {
+		t.Error("distância entre o mesmo ponto deve ser zero")
+	}
+}
+
+func TestTransportEnricherEnrich(t *testing.T) {
+	stops := []TransportStopPoint{
+		{Nome: "Alvorada", Modal: "brt", Lat: -23.0006, Lng: -43.3657},
+		{Nome: "Alvorada", Modal: "brt", Lat: -23.0008, Lng: -43.3659}, // plataforma duplicada
+		{Nome: "Jardim Oceânico", Modal: "metro", Lat: -23.0065, Lng: -43.3110},
+		{Nome: "Centro", Modal: "vlt", Lat: -22.90, Lng: -43.17},
+	}
+	geocoder := &fakeGeocoder{points: map[string][2]float64{
+		"Av. das Américas, 5000, Rio de Janeiro - RJ": {-23.0010, -43.3660},
+	}}
+	enricher := NewTransportEnricher(geocoder, stops, 800, 3)
+
+	canais := []string{
+		"**Av. das Américas, 5000**",
+		"https://carioca.rio",
+		"Rua Inexistente, 0",
+		"Av. das Américas, 5000",
+	}
+	transport, unresolved, err := enricher.Enrich(context.Background(), canais)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	if len(transport.Locations) != 2 {
+		t.Fatalf("locais = %+v", transport.Locations)
+	}
+	nearby := transport.Locations[0].Transporte
+	if len(nearby) != 1 || nearby[0].Nome != "Alvorada" || nearby[0].Descricao != "próximo ao BRT Alvorada" {
+		t.Errorf("estações próximas = %+v", nearby)
+	}
+	if len(unresolved) != 1 || unresolved[0] != "Rua Inexistente, 0" {
+		t.Errorf("não localizados = %v", unresolved)
+	}
+	// Endereço repetido usa o cache; link não é geocodificado
+	if len(geocoder.queries) != 2 {
+		t.Errorf("consultas = %v", geocoder.queries)
+	}
+
+	if _, _, err := enricher.Enrich(context.Background(), []string{"erro"}); err == nil {
+		t.Error("esperado erro do geocodificador")
+	}
+}
+
+func TestDescribeTransportStop(t *testing.T) {
+	cases := map[string]string{
+		"metro":  "próximo à estação Carioca do metrô",
+		"trem":   "próximo à estação Carioca do trem",
+		"barcas": "próximo às barcas Carioca",
+		"":       "próximo a Carioca",
+	}
+	for modal, want := range cases {
+		if got := describeTransportStop(modal, "Carioca"); got != want {
+			t.Errorf("describeTransportStop(%q) = %q, esperado %q", modal, got, want)
+		}
+	}
+}
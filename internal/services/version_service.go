@@ -2,7 +2,6 @@ package services
 
 import (
 	"context"
-	"crypto/md5"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -11,11 +10,21 @@ import (
 	"time"
 
 	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/pii"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
 	"github.com/typesense/typesense-go/v3/typesense"
 	api "github.com/typesense/typesense-go/v3/typesense/api"
 	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
 )
 
+// VersionPendingGrace tempo após o qual uma versão pendente é considerada abandonada por uma
+// criação interrompida
+const VersionPendingGrace = 10 * time.Minute
+
+// confirmedVersionsFilter exclui das consultas públicas as versões pendentes, gravadas antes do
+// documento do serviço e ainda não confirmadas
+const confirmedVersionsFilter = "pending:!=true"
+
 // VersionService gerencia o histórico de versões dos serviços
 type VersionService struct {
 	typesenseClient *typesense.Client
@@ -38,8 +47,53 @@ func (vs *VersionService) CaptureVersion(
 	changeReason string,
 	previousVersion *models.ServiceVersion,
 ) (*models.ServiceVersion, error) {
+	version := vs.newVersion(service, changeType, createdBy, createdByCPF, changeReason, previousVersion)
+	return vs.saveCapturedVersion(ctx, version)
+}
+
+// CapturePendingVersion grava a versão 1 de um serviço que ainda será criado, marcada como
+// pendente. Após gravar o documento, a criação confirma a versão (ConfirmVersion) ou a descarta
+// em caso de falha (DiscardVersion); pendências que sobrarem são resolvidas pela reconciliação.
+func (vs *VersionService) CapturePendingVersion(
+	ctx context.Context,
+	service *models.PrefRioService,
+	createdBy string,
+	createdByCPF string,
+	changeReason string,
+) (*models.ServiceVersion, error) {
+	version := vs.newVersion(service, "create", createdBy, createdByCPF, changeReason, nil)
+	version.Pending = true
+	return vs.saveCapturedVersion(ctx, version)
+}
+
+// ConfirmVersion remove a marcação de pendente da versão
+func (vs *VersionService) ConfirmVersion(ctx context.Context, versionID string) error {
+	update := map[string]interface{}{"pending": false}
+	if _, err := vs.typesenseClient.Collection(ServiceVersionsCollection).Document(versionID).Update(ctx, update, &api.DocumentIndexParameters{}); err != nil {
+		return fmt.Errorf("erro ao confirmar versão %s: %v", versionID, err)
+	}
+	return nil
+}
+
+// DiscardVersion remove a versão pendente de um serviço que não chegou a ser criado
+func (vs *VersionService) DiscardVersion(ctx context.Context, versionID string) error {
+	if _, err := vs.typesenseClient.Collection(ServiceVersionsCollection).Document(versionID).Delete(ctx); err != nil {
+		return fmt.Errorf("erro ao descartar versão %s: %v", versionID, err)
+	}
+	return nil
+}
+
+// newVersion monta o snapshot da versão do serviço, com o diff em relação à versão anterior
+func (vs *VersionService) newVersion(
+	service *models.PrefRioService,
+	changeType string,
+	createdBy string,
+	createdByCPF string,
+	changeReason string,
+	previousVersion *models.ServiceVersion,
+) *models.ServiceVersion {
 	log.Printf("[CaptureVersion] Iniciando para serviceID=%s, changeType=%s, createdBy='%s', createdByCPF='%s'",
-		service.ID, changeType, createdBy, createdByCPF)
+		service.ID, changeType, createdBy, pii.Scrub(createdByCPF))
 
 	// Determina o número da versão
 	versionNumber := int64(1)
@@ -52,23 +106,63 @@ func (vs *VersionService) CaptureVersion(
 		log.Printf("[CaptureVersion] Nenhuma versão anterior, criando versão 1")
 	}
 
-	// Calcula hash do embedding se existir
-	embeddingHash := ""
-	if len(service.Embedding) > 0 {
-		embeddingHash = vs.calculateEmbeddingHash(service.Embedding)
+	// Cria o snapshot da versão
+	version := versionSnapshot(service)
+	version.VersionNumber = versionNumber
+	version.CreatedAt = time.Now().Unix()
+	version.CreatedBy = createdBy
+	version.CreatedByCPF = createdByCPF
+	version.ChangeType = changeType
+	version.ChangeReason = changeReason
+	version.PreviousVersion = previousVersionNumber
+	version.IsRollback = false
+
+	// Calcula diff se houver versão anterior
+	if previousVersion != nil {
+		changes := vs.ComputeDiff(previousVersion, version)
+		if len(changes) > 0 {
+			changesJSON, err := json.Marshal(changes)
+			if err != nil {
+				log.Printf("Erro ao serializar mudanças: %v", err)
+			} else {
+				version.ChangedFieldsJSON = string(changesJSON)
+			}
+		}
+	} else {
+		// Para a primeira versão, todas as mudanças são "create"
+		changes := vs.GetAllFieldsAsChanges(version)
+		if len(changes) > 0 {
+			changesJSON, err := json.Marshal(changes)
+			if err != nil {
+				log.Printf("Erro ao serializar mudanças: %v", err)
+			} else {
+				version.ChangedFieldsJSON = string(changesJSON)
+			}
+		}
+	}
+
+	return version
+}
+
+// PreviewChanges calcula, sem gravar nada, as mudanças que a escrita de service registraria na
+// versão: o diff em relação a existing ou, sem ele (criação), os campos da versão 1
+func (vs *VersionService) PreviewChanges(existing, service *models.PrefRioService) []models.FieldChange {
+	if existing == nil {
+		return vs.GetAllFieldsAsChanges(versionSnapshot(service))
+	}
+	return vs.ComputeDiff(versionSnapshot(existing), versionSnapshot(service))
+}
+
+// versionSnapshot copia os campos versionados do serviço
+func versionSnapshot(service *models.PrefRioService) *models.ServiceVersion {
+	// Hash do embedding: o gravado no serviço ou, na falta dele, calculado do vetor
+	embeddingHash := service.EmbeddingHash
+	if embeddingHash == "" {
+		embeddingHash = utils.EmbeddingHash(service.EmbeddingModel, service.Embedding)
 	}
 
-	// Cria o snapshot da versão
-	version := &models.ServiceVersion{
+	return &models.ServiceVersion{
 		ServiceID:             service.ID,
-		VersionNumber:         versionNumber,
-		CreatedAt:             time.Now().Unix(),
-		CreatedBy:             createdBy,
-		CreatedByCPF:          createdByCPF,
-		ChangeType:            changeType,
-		ChangeReason:          changeReason,
-		PreviousVersion:       previousVersionNumber,
-		IsRollback:            false,
 		NomeServico:           service.NomeServico,
 		OrgaoGestor:           service.OrgaoGestor,
 		Resumo:                service.Resumo,
@@ -93,33 +187,12 @@ func (vs *VersionService) CaptureVersion(
 		SearchContent:         service.SearchContent,
 		EmbeddingHash:         embeddingHash,
 	}
+}
 
-	// Calcula diff se houver versão anterior
-	if previousVersion != nil {
-		changes := vs.ComputeDiff(previousVersion, version)
-		if len(changes) > 0 {
-			changesJSON, err := json.Marshal(changes)
-			if err != nil {
-				log.Printf("Erro ao serializar mudanças: %v", err)
-			} else {
-				version.ChangedFieldsJSON = string(changesJSON)
-			}
-		}
-	} else {
-		// Para a primeira versão, todas as mudanças são "create"
-		changes := vs.GetAllFieldsAsChanges(version)
-		if len(changes) > 0 {
-			changesJSON, err := json.Marshal(changes)
-			if err != nil {
-				log.Printf("Erro ao serializar mudanças: %v", err)
-			} else {
-				version.ChangedFieldsJSON = string(changesJSON)
-			}
-		}
-	}
-
+// saveCapturedVersion salva a versão montada por newVersion
+func (vs *VersionService) saveCapturedVersion(ctx context.Context, version *models.ServiceVersion) (*models.ServiceVersion, error) {
 	log.Printf("[CaptureVersion] Prestes a salvar versão: ServiceID=%s, VersionNumber=%d, CreatedBy='%s', CreatedByCPF='%s'",
-		version.ServiceID, version.VersionNumber, version.CreatedBy, version.CreatedByCPF)
+		version.ServiceID, version.VersionNumber, version.CreatedBy, pii.Scrub(version.CreatedByCPF))
 
 	// Salva a versão no Typesense
 	savedVersion, err := vs.SaveVersion(ctx, version)
@@ -228,17 +301,6 @@ func (vs *VersionService) GetAllFieldsAsChanges(version *models.ServiceVersion)
 	return changes
 }
 
-// calculateEmbeddingHash calcula MD5 hash do embedding
-func (vs *VersionService) calculateEmbeddingHash(embedding []float64) string {
-	// Serializa o embedding para bytes
-	data, err := json.Marshal(embedding)
-	if err != nil {
-		return ""
-	}
-	hash := md5.Sum(data)
-	return fmt.Sprintf("%x", hash)
-}
-
 // SaveVersion salva uma versão no Typesense
 func (vs *VersionService) SaveVersion(ctx context.Context, version *models.ServiceVersion) (*models.ServiceVersion, error) {
 	log.Printf("[SaveVersion] Iniciando para ServiceID=%s, VersionNumber=%d", version.ServiceID, version.VersionNumber)
@@ -289,7 +351,15 @@ func (vs *VersionService) SaveVersion(ctx context.Context, version *models.Servi
 
 // GetLatestVersion busca a última versão de um serviço
 func (vs *VersionService) GetLatestVersion(ctx context.Context, serviceID string) (*models.ServiceVersion, error) {
-	filterBy := fmt.Sprintf("service_id:=%s", serviceID)
+	return vs.getLatestVersion(ctx, fmt.Sprintf("service_id:=%s", serviceID))
+}
+
+// GetLatestConfirmedVersion busca a última versão confirmada de um serviço, ignorando as pendentes
+func (vs *VersionService) GetLatestConfirmedVersion(ctx context.Context, serviceID string) (*models.ServiceVersion, error) {
+	return vs.getLatestVersion(ctx, fmt.Sprintf("service_id:=%s && %s", serviceID, confirmedVersionsFilter))
+}
+
+func (vs *VersionService) getLatestVersion(ctx context.Context, filterBy string) (*models.ServiceVersion, error) {
 	sortBy := "version_number:desc"
 
 	searchParams := &api.SearchCollectionParams{
@@ -367,7 +437,16 @@ func (vs *VersionService) GetVersionByNumber(ctx context.Context, serviceID stri
 
 // ListVersions lista todas as versões de um serviço com paginação
 func (vs *VersionService) ListVersions(ctx context.Context, serviceID string, page, perPage int) (*models.VersionHistory, error) {
-	filterBy := fmt.Sprintf("service_id:=%s", serviceID)
+	return vs.listVersions(ctx, fmt.Sprintf("service_id:=%s", serviceID), page, perPage)
+}
+
+// ListConfirmedVersions lista as versões de um serviço sem as pendentes, cuja criação ainda não
+// foi confirmada (usada no histórico público)
+func (vs *VersionService) ListConfirmedVersions(ctx context.Context, serviceID string, page, perPage int) (*models.VersionHistory, error) {
+	return vs.listVersions(ctx, fmt.Sprintf("service_id:=%s && %s", serviceID, confirmedVersionsFilter), page, perPage)
+}
+
+func (vs *VersionService) listVersions(ctx context.Context, filterBy string, page, perPage int) (*models.VersionHistory, error) {
 	sortBy := "version_number:desc"
 
 	if page < 1 {
@@ -417,7 +496,9 @@ func (vs *VersionService) ListVersions(ctx context.Context, serviceID string, pa
 		Found:    searchResult.Found,
 		OutOf:    searchResult.OutOf,
 		Page:     page,
+		PerPage:  perPage,
 		Versions: versions,
+		PageInfo: models.NewPageInfo(page, perPage, searchResult.Found),
 	}, nil
 }
 
@@ -447,6 +528,123 @@ func (vs *VersionService) CompareVersions(ctx context.Context, serviceID string,
 	}, nil
 }
 
+// GetVersionAsOf busca a versão vigente do serviço no instante asOf (a última criada até ele) e
+// o início da versão seguinte, 0 se a versão ainda é a atual. Retorna nil se o serviço ainda
+// não tinha versões no instante.
+func (vs *VersionService) GetVersionAsOf(ctx context.Context, serviceID string, asOf int64) (*models.ServiceVersion, int64, error) {
+	// O ID vem da URL pública: crases delimitam o valor no filtro
+	serviceID = "`" + strings.ReplaceAll(serviceID, "`", "") + "`"
+
+	versions, err := vs.searchVersions(ctx, &api.SearchCollectionParams{
+		Q:        pointer.String("*"),
+		FilterBy: pointer.String(fmt.Sprintf("service_id:=%s && created_at:<=%d && %s", serviceID, asOf, confirmedVersionsFilter)),
+		SortBy:   pointer.String("version_number:desc"),
+		PerPage:  pointer.Int(1),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao buscar versão vigente: %v", err)
+	}
+	if len(versions) == 0 {
+		return nil, 0, nil
+	}
+
+	next, err := vs.searchVersions(ctx, &api.SearchCollectionParams{
+		Q:        pointer.String("*"),
+		FilterBy: pointer.String(fmt.Sprintf("service_id:=%s && version_number:>%d && %s", serviceID, versions[0].VersionNumber, confirmedVersionsFilter)),
+		SortBy:   pointer.String("version_number:asc"),
+		PerPage:  pointer.Int(1),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao buscar versão seguinte: %v", err)
+	}
+
+	var validUntil int64
+	if len(next) > 0 {
+		validUntil = next[0].CreatedAt
+	}
+	return &versions[0], validUntil, nil
+}
+
+// searchVersions executa a busca na collection service_versions
+func (vs *VersionService) searchVersions(ctx context.Context, params *api.SearchCollectionParams) ([]models.ServiceVersion, error) {
+	result, err := vs.typesenseClient.Collection("service_versions").Documents().Search(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar resultado: %v", err)
+	}
+
+	var searchResult struct {
+		Hits []struct {
+			Document models.ServiceVersion `json:"document"`
+		} `json:"hits"`
+	}
+	if err := json.Unmarshal(resultBytes, &searchResult); err != nil {
+		return nil, fmt.Errorf("erro ao deserializar resultado: %v", err)
+	}
+
+	versions := make([]models.ServiceVersion, len(searchResult.Hits))
+	for i, hit := range searchResult.Hits {
+		versions[i] = hit.Document
+	}
+	return versions, nil
+}
+
+// ServiceVersionsSchema schema da collection service_versions, usado na criação (pelo cliente e
+// na primeira versão gravada) e na sincronização de campos da collection existente
+func ServiceVersionsSchema(collectionName string) *api.CollectionSchema {
+	return &api.CollectionSchema{
+		Name: collectionName,
+		Fields: []api.Field{
+			{Name: "id", Type: "string", Optional: pointer.True()},
+			{Name: "service_id", Type: "string", Facet: pointer.True()},
+			{Name: "version_number", Type: "int64", Facet: pointer.True()},
+			{Name: "created_at", Type: "int64", Facet: pointer.False()},
+			{Name: "created_by", Type: "string", Facet: pointer.True()},
+			{Name: "created_by_cpf", Type: "string", Facet: pointer.True()},
+			{Name: "change_type", Type: "string", Facet: pointer.True()},
+			{Name: "change_reason", Type: "string", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "previous_version", Type: "int64", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "is_rollback", Type: "bool", Facet: pointer.True()},
+			{Name: "rollback_to_version", Type: "int64", Facet: pointer.False(), Optional: pointer.True()},
+
+			// Snapshot do serviço (campos principais)
+			{Name: "nome_servico", Type: "string", Facet: pointer.False()},
+			{Name: "orgao_gestor", Type: "string[]", Facet: pointer.False()},
+			{Name: "resumo", Type: "string", Facet: pointer.False()},
+			{Name: "tempo_atendimento", Type: "string", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "custo_servico", Type: "string", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "resultado_solicitacao", Type: "string", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "descricao_completa", Type: "string", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "autor", Type: "string", Facet: pointer.False()},
+			{Name: "documentos_necessarios", Type: "string[]", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "instrucoes_solicitante", Type: "string", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "canais_digitais", Type: "string[]", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "canais_presenciais", Type: "string[]", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "servico_nao_cobre", Type: "string", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "legislacao_relacionada", Type: "string[]", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "tema_geral", Type: "string", Facet: pointer.False()},
+			{Name: "publico_especifico", Type: "string[]", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "fixar_destaque", Type: "bool", Facet: pointer.False()},
+			{Name: "awaiting_approval", Type: "bool", Facet: pointer.False()},
+			{Name: "published_at", Type: "int64", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "is_free", Type: "bool", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "status", Type: "int32", Facet: pointer.True()},
+			{Name: "search_content", Type: "string", Facet: pointer.False()},
+
+			// Campos de controle de versão
+			{Name: "embedding_hash", Type: "string", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "changed_fields_json", Type: "string", Facet: pointer.False(), Optional: pointer.True()},
+			{Name: "pending", Type: "bool", Facet: pointer.True(), Optional: pointer.True()},
+		},
+		DefaultSortingField: pointer.String("created_at"),
+		EnableNestedFields:  pointer.True(),
+	}
+}
+
 // ensureCollectionExists garante que a collection service_versions existe
 func (vs *VersionService) ensureCollectionExists(ctx context.Context) error {
 	// Verifica se a collection já existe
@@ -466,48 +664,7 @@ func (vs *VersionService) ensureCollectionExists(ctx context.Context) error {
 	log.Printf("[ensureCollectionExists] Collection service_versions não existe, criando...")
 
 	// Cria a collection
-	schema := &api.CollectionSchema{
-		Name: "service_versions",
-		Fields: []api.Field{
-			{Name: "service_id", Type: "string", Facet: pointer.True()},
-			{Name: "version_number", Type: "int64"},
-			{Name: "created_at", Type: "int64", Sort: pointer.True()},
-			{Name: "created_by", Type: "string"},
-			{Name: "created_by_cpf", Type: "string", Facet: pointer.True()},
-			{Name: "change_type", Type: "string", Facet: pointer.True()},
-			{Name: "change_reason", Type: "string", Optional: pointer.True()},
-			{Name: "previous_version", Type: "int64", Optional: pointer.True()},
-			{Name: "is_rollback", Type: "bool", Facet: pointer.True()},
-			{Name: "rollback_to_version", Type: "int64", Optional: pointer.True()},
-			{Name: "nome_servico", Type: "string"},
-			{Name: "orgao_gestor", Type: "string[]", Facet: pointer.True()},
-			{Name: "resumo", Type: "string"},
-			{Name: "tempo_atendimento", Type: "string", Optional: pointer.True()},
-			{Name: "custo_servico", Type: "string", Optional: pointer.True()},
-			{Name: "resultado_solicitacao", Type: "string", Optional: pointer.True()},
-			{Name: "descricao_completa", Type: "string", Optional: pointer.True()},
-			{Name: "autor", Type: "string"},
-			{Name: "documentos_necessarios", Type: "string[]", Optional: pointer.True()},
-			{Name: "instrucoes_solicitante", Type: "string", Optional: pointer.True()},
-			{Name: "canais_digitais", Type: "string[]", Optional: pointer.True()},
-			{Name: "canais_presenciais", Type: "string[]", Optional: pointer.True()},
-			{Name: "servico_nao_cobre", Type: "string", Optional: pointer.True()},
-			{Name: "legislacao_relacionada", Type: "string[]", Optional: pointer.True()},
-			{Name: "tema_geral", Type: "string", Facet: pointer.True()},
-			{Name: "publico_especifico", Type: "string[]", Optional: pointer.True(), Facet: pointer.True()},
-			{Name: "fixar_destaque", Type: "bool", Facet: pointer.True()},
-			{Name: "awaiting_approval", Type: "bool", Facet: pointer.True()},
-			{Name: "published_at", Type: "int64", Optional: pointer.True()},
-			{Name: "is_free", Type: "bool", Optional: pointer.True(), Facet: pointer.True()},
-			{Name: "status", Type: "int32", Facet: pointer.True()},
-			{Name: "search_content", Type: "string"},
-			{Name: "embedding_hash", Type: "string", Optional: pointer.True()},
-			{Name: "changed_fields_json", Type: "string", Optional: pointer.True()},
-		},
-		DefaultSortingField: pointer.String("created_at"),
-	}
-
-	_, err = vs.typesenseClient.Collections().Create(ctx, schema)
+	_, err = vs.typesenseClient.Collections().Create(ctx, ServiceVersionsSchema(ServiceVersionsCollection))
 	if err != nil {
 		log.Printf("[ensureCollectionExists] Erro ao criar collection: %v", err)
 		return fmt.Errorf("erro ao criar collection service_versions: %v", err)
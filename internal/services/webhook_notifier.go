@@ -0,0 +1,70 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookEvent representa o envelope enviado para o webhook de notificações
+type WebhookEvent struct {
+	Event     string      `json:"event"`
+	Timestamp int64       `json:"timestamp"`
+	Payload   interface{} `json:"payload"`
+}
+
+// WebhookNotifier envia eventos do sistema para um webhook HTTP externo
+type WebhookNotifier struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhookNotifier cria um novo notificador. Com url vazia as notificações ficam desabilitadas
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:        url,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Enabled indica se há um webhook configurado
+func (wn *WebhookNotifier) Enabled() bool {
+	return wn != nil && wn.url != ""
+}
+
+// Notify envia um evento para o webhook configurado
+func (wn *WebhookNotifier) Notify(ctx context.Context, event string, payload interface{}) error {
+	if !wn.Enabled() {
+		return nil
+	}
+
+	body, err := json.Marshal(&WebhookEvent{
+		Event:     event,
+		Timestamp: time.Now().Unix(),
+		Payload:   payload,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao serializar evento: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wn.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição do webhook: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := wn.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao chamar webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook retornou status %d", resp.StatusCode)
+	}
+
+	return nil
+}
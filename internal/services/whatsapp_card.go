@@ -0,0 +1,129 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+)
+
+// Limites das mensagens interativas do WhatsApp
+const (
+	whatsAppTitleLimit      = 60
+	whatsAppBodyLimit       = 1024
+	whatsAppReplyTitleLimit = 20
+	whatsAppMaxReplies      = 3
+	// whatsAppMaxDocuments documentos listados no card; os demais ficam na página do serviço
+	whatsAppMaxDocuments = 5
+)
+
+// whatsAppDeepLinkChannel canal de deep link cujos UTMs identificam o tráfego do chatbot
+const whatsAppDeepLinkChannel = "whatsapp"
+
+// WhatsAppCardBuilder gera os cards de serviço para o chatbot do WhatsApp
+type WhatsAppCardBuilder struct {
+	links *DeepLinkBuilder
+}
+
+// NewWhatsAppCardBuilder cria o builder com os canais de deep link configurados
+func NewWhatsAppCardBuilder(links *DeepLinkBuilder) *WhatsAppCardBuilder {
+	return &WhatsAppCardBuilder{links: links}
+}
+
+// Build monta o card a partir do documento, com os textos convertidos para texto simples. O
+// corpo é cortado no limite do WhatsApp preservando o link, que fica sempre na última linha.
+func (b *WhatsAppCardBuilder) Build(service *models.PrefRioService) *models.WhatsAppCard {
+	card := &models.WhatsAppCard{
+		ServiceID:    service.ID,
+		Title:        truncateWithEllipsis(service.NomeServico, whatsAppTitleLimit),
+		DeepLink:     b.links.PageLink(service, whatsAppDeepLinkChannel),
+		QuickReplies: whatsAppQuickReplies(service),
+	}
+
+	content := whatsAppContent(service)
+	linkLine := ""
+	if card.DeepLink != "" {
+		linkLine = "\n\n🔗 " + card.DeepLink
+	}
+	card.Body = truncateWithEllipsis(content, whatsAppBodyLimit-utf8.RuneCountInString(linkLine)) + linkLine
+	card.Text = "*" + service.NomeServico + "*\n\n" + card.Body
+	return card
+}
+
+// whatsAppContent monta as seções do card, marcadas com emojis
+func whatsAppContent(service *models.PrefRioService) string {
+	resumo := service.ResumoPlaintext
+	if resumo == "" {
+		resumo = utils.StripMarkdown(service.Resumo)
+	}
+	sections := []string{"📝 " + resumo}
+
+	if tempo := strings.TrimSpace(service.TempoAtendimento); tempo != "" {
+		sections = append(sections, "⏱️ *Tempo de atendimento:* "+tempo)
+	}
+	switch custo := strings.TrimSpace(service.CustoServico); {
+	case service.IsFree != nil && *service.IsFree:
+		sections = append(sections, "💰 *Custo:* Gratuito")
+	case custo != "":
+		sections = append(sections, "💰 *Custo:* "+custo)
+	}
+
+	documentos := service.DocumentosNecessariosPlaintext
+	if len(documentos) == 0 {
+		documentos = utils.StripMarkdownArray(service.DocumentosNecessarios)
+	}
+	if len(documentos) > 0 {
+		lines := []string{"📄 *Documentos necessários:*"}
+		for i, doc := range documentos {
+			if i == whatsAppMaxDocuments {
+				lines = append(lines, fmt.Sprintf("• e mais %d", len(documentos)-whatsAppMaxDocuments))
+				break
+			}
+			lines = append(lines, "• "+doc)
+		}
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+
+	if len(service.OrgaoGestor) > 0 {
+		sections = append(sections, "🏛️ *Órgão responsável:* "+strings.Join(service.OrgaoGestor, ", "))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// whatsAppQuickReplies converte os botões ativos, na ordem de exibição, em respostas rápidas
+func whatsAppQuickReplies(service *models.PrefRioService) []models.WhatsAppQuickReply {
+	buttons := make([]models.Button, 0, len(service.Buttons))
+	for _, button := range service.Buttons {
+		if button.IsEnabled && strings.TrimSpace(button.Titulo) != "" {
+			buttons = append(buttons, button)
+		}
+	}
+	sort.SliceStable(buttons, func(i, j int) bool { return buttons[i].Ordem < buttons[j].Ordem })
+
+	replies := []models.WhatsAppQuickReply{}
+	for i, button := range buttons {
+		if i == whatsAppMaxReplies {
+			break
+		}
+		replies = append(replies, models.WhatsAppQuickReply{
+			ID:         fmt.Sprintf("%s:button:%d", service.ID, i),
+			Title:      truncateWithEllipsis(button.Titulo, whatsAppReplyTitleLimit),
+			ActionType: button.ActionType,
+			URL:        button.URLService,
+		})
+	}
+	return replies
+}
+
+// truncateWithEllipsis corta o texto em no máximo limit caracteres, terminando em reticências
+// quando cortado
+func truncateWithEllipsis(text string, limit int) string {
+	text = strings.TrimSpace(text)
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	return strings.TrimSpace(truncateText(text, limit-1)) + "…"
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestWhatsAppCardBuilderBuild(t *testing.T) {
+	free := true
+	service := &models.PrefRioService{
+		ID:                    "abc123",
+		NomeServico:           "Matrícula Escolar",
+		TemaGeral:             "Educação",
+		Slug:                  "matricula-escolar-abc123",
+		Resumo:                "Matrícula na **rede municipal** de ensino.",
+		TempoAtendimento:      "5 dias úteis",
+		IsFree:                &free,
+		DocumentosNecessarios: []string{"RG", "CPF", "Comprovante de residência", "Certidão", "Foto", "Histórico"},
+		OrgaoGestor:           []string{"SME"},
+		Buttons: []models.Button{
+			{Titulo: "Desativado", IsEnabled: false, Ordem: 0},
+			{Titulo: "Consultar resultado da matrícula", IsEnabled: true, Ordem: 2, ActionType: models.ButtonActionExternalLink, URLService: "https://exemplo.rio/resultado"},
+			{Titulo: "Fazer matrícula", IsEnabled: true, Ordem: 1, ActionType: models.ButtonActionPrefLoginFlow, URLService: "https://exemplo.rio/matricula"},
+			{Titulo: "Ligar", IsEnabled: true, Ordem: 3},
+			{Titulo: "WhatsApp", IsEnabled: true, Ordem: 4},
+		},
+	}
+
+	card := NewWhatsAppCardBuilder(NewDeepLinkBuilder(config.DefaultDeepLinkChannels())).Build(service)
+
+	link := "https://prefeitura.rio/servicos/matricula-escolar-abc123?utm_campaign=educacao&utm_medium=social&utm_source=whatsapp"
+	if card.DeepLink != link {
+		t.Errorf("deep link = %s, esperado a página com os UTMs do WhatsApp", card.DeepLink)
+	}
+	if !strings.Contains(card.Body, "📝 Matrícula na rede municipal de ensino.") {
+		t.Errorf("resumo deveria estar em texto simples: %q", card.Body)
+	}
+	if !strings.Contains(card.Body, "💰 *Custo:* Gratuito") || !strings.Contains(card.Body, "• e mais 1") {
+		t.Errorf("corpo inesperado: %q", card.Body)
+	}
+	if !strings.HasSuffix(card.Body, "🔗 "+link) {
+		t.Errorf("o link deveria encerrar o corpo: %q", card.Body)
+	}
+
+	if len(card.QuickReplies) != 3 {
+		t.Fatalf("esperadas 3 respostas rápidas, obtidas %d", len(card.QuickReplies))
+	}
+	if card.QuickReplies[0].Title != "Fazer matrícula" || card.QuickReplies[0].URL != "https://exemplo.rio/matricula" {
+		t.Errorf("respostas deveriam seguir a ordem dos botões: %+v", card.QuickReplies[0])
+	}
+	if title := card.QuickReplies[1].Title; utf8.RuneCountInString(title) != whatsAppReplyTitleLimit || !strings.HasSuffix(title, "…") {
+		t.Errorf("título da resposta deveria ser cortado em %d caracteres: %q", whatsAppReplyTitleLimit, title)
+	}
+
+	// Resumos longos são cortados sem perder o link
+	service.ResumoPlaintext = strings.Repeat("texto ", 400)
+	card = NewWhatsAppCardBuilder(NewDeepLinkBuilder(config.DefaultDeepLinkChannels())).Build(service)
+	if n := utf8.RuneCountInString(card.Body); n > whatsAppBodyLimit {
+		t.Errorf("corpo com %d caracteres excede o limite", n)
+	}
+	if !strings.HasSuffix(card.Body, "🔗 "+link) {
+		t.Error("o corte não deveria remover o link")
+	}
+}
@@ -0,0 +1,65 @@
+// Package tenant propaga o município (tenant) da requisição até as consultas ao Typesense.
+package tenant
+
+import (
+	"context"
+	"fmt"
+)
+
+// DefaultID tenant dos documentos indexados antes do particionamento (Prefeitura do Rio)
+const DefaultID = "rio"
+
+// Field nome do campo que identifica o tenant nos documentos
+const Field = "tenant"
+
+type contextKey struct{}
+
+// WithTenant retorna um contexto associado ao tenant informado
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext retorna o tenant da requisição. ok é false quando o particionamento não está
+// habilitado (nenhum tenant foi associado ao contexto).
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok && id != ""
+}
+
+// OwnerID retorna o tenant dos documentos criados no contexto: o da requisição ou, sem
+// particionamento, o tenant padrão. Assim os documentos continuam visíveis quando o
+// particionamento é habilitado.
+func OwnerID(ctx context.Context) string {
+	if id, ok := FromContext(ctx); ok {
+		return id
+	}
+	return DefaultID
+}
+
+// ScopeFilter restringe a expressão filter_by ao tenant do contexto. Sem tenant no contexto,
+// retorna o filtro inalterado.
+func ScopeFilter(ctx context.Context, filter string) string {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return filter
+	}
+
+	scope := fmt.Sprintf("%s:=`%s`", Field, id)
+	if filter == "" {
+		return scope
+	}
+	return filter + " && " + scope
+}
+
+// Allows indica se um documento do tenant docTenant pode ser acessado no contexto.
+// Documentos sem tenant pertencem ao tenant padrão.
+func Allows(ctx context.Context, docTenant string) bool {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return true
+	}
+	if docTenant == "" {
+		docTenant = DefaultID
+	}
+	return docTenant == id
+}
@@ -0,0 +1,44 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+)
+
+func TestScopeFilter(t *testing.T) {
+	ctx := context.Background()
+	if got := ScopeFilter(ctx, "status:=1"); got != "status:=1" {
+		t.Errorf("sem tenant o filtro não deveria mudar: %q", got)
+	}
+
+	ctx = WithTenant(ctx, "niteroi")
+	if got := ScopeFilter(ctx, "status:=1"); got != "status:=1 && tenant:=`niteroi`" {
+		t.Errorf("filtro inesperado: %q", got)
+	}
+	if got := ScopeFilter(ctx, ""); got != "tenant:=`niteroi`" {
+		t.Errorf("filtro inesperado: %q", got)
+	}
+}
+
+func TestAllows(t *testing.T) {
+	if !Allows(context.Background(), "niteroi") {
+		t.Error("sem tenant no contexto todos os documentos são acessíveis")
+	}
+
+	ctx := WithTenant(context.Background(), DefaultID)
+	if !Allows(ctx, "") {
+		t.Error("documento sem tenant pertence ao tenant padrão")
+	}
+	if Allows(ctx, "niteroi") {
+		t.Error("documento de outro tenant não deveria ser acessível")
+	}
+}
+
+func TestOwnerID(t *testing.T) {
+	if got := OwnerID(context.Background()); got != DefaultID {
+		t.Errorf("sem particionamento o dono é o tenant padrão, obtido %q", got)
+	}
+	if got := OwnerID(WithTenant(context.Background(), "niteroi")); got != "niteroi" {
+		t.Errorf("esperado niteroi, obtido %q", got)
+	}
+}
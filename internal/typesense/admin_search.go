@@ -0,0 +1,37 @@
+package typesense
+
+import (
+	"context"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+// Campos da busca do admin e seus pesos: nome, resumo e órgão gestor pesam mais que o conteúdo
+const (
+	adminSearchQueryBy = "nome_servico,resumo,orgao_gestor,search_content"
+	adminSearchWeights = "4,2,2,1"
+)
+
+// SearchPrefRioServicesAdmin busca serviços de todos os status (rascunhos inclusive) para o
+// admin, com os filtros da listagem
+func (c *Client) SearchPrefRioServicesAdmin(ctx context.Context, query string, page, perPage int, filters map[string]interface{}) (*models.PrefRioServiceResponse, error) {
+	return c.listPrefRioServices(ctx, page, perPage, filters, nil, query)
+}
+
+// applyAdminSearch configura a busca textual do admin, isolada dos ajustes da busca pública:
+// tolera até dois erros de digitação e prefixos, ordena pela relevância e, no empate, pela
+// atualização mais recente, e ignora as curadorias (overrides) e os sinônimos mantidos para o
+// público. Não há busca vetorial nem cache, e a consulta vai ao primário, de modo que um
+// rascunho recém-gravado já aparece.
+func applyAdminSearch(params *api.SearchCollectionParams, query string) {
+	params.Q = stringPtr(query)
+	params.QueryBy = stringPtr(adminSearchQueryBy)
+	params.QueryByWeights = stringPtr(adminSearchWeights)
+	params.NumTypos = stringPtr("2")
+	params.Prefix = stringPtr("true")
+	params.PrioritizeExactMatch = boolPtr(true)
+	params.EnableOverrides = boolPtr(false)
+	params.EnableSynonyms = boolPtr(false)
+	params.SortBy = stringPtr("_text_match:desc,last_update:desc")
+}
@@ -0,0 +1,28 @@
+package typesense
+
+import (
+	"testing"
+
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+func TestApplyAdminSearch(t *testing.T) {
+	params := &api.SearchCollectionParams{Q: stringPtr("*"), SortBy: stringPtr("last_update:desc")}
+	applyAdminSearch(params, "iptu segunda via")
+
+	if *params.Q != "iptu segunda via" || *params.QueryBy != adminSearchQueryBy {
+		t.Errorf("q/query_by = %q/%q", *params.Q, *params.QueryBy)
+	}
+	if *params.SortBy != "_text_match:desc,last_update:desc" {
+		t.Errorf("sort_by = %q, want relevance first", *params.SortBy)
+	}
+	if *params.EnableOverrides || *params.EnableSynonyms {
+		t.Error("admin search must not use the public overrides and synonyms")
+	}
+	if *params.NumTypos != "2" {
+		t.Errorf("num_typos = %q, want 2", *params.NumTypos)
+	}
+	if params.FilterBy != nil {
+		t.Error("admin search must not restrict the statuses")
+	}
+}
@@ -0,0 +1,170 @@
+package typesense
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/jobs"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+// Identificação do usuário registrada nas versões geradas pela janela de disponibilidade
+const (
+	availabilityUserName = "Sistema (janela de disponibilidade)"
+	availabilityUserCPF  = "00000000000"
+)
+
+// ApplyAvailabilityWindows despublica os serviços cuja janela encerrou, marcando-os como pausados,
+// e republica os pausados cuja nova janela já abriu. Retorna os IDs alterados.
+func (c *Client) ApplyAvailabilityWindows(ctx context.Context) (paused, republished []string, err error) {
+	now := time.Now().Unix()
+
+	closed, err := c.searchServicesByFilter(ctx, fmt.Sprintf("status:=1 && available_until:>0 && available_until:<=%d", now))
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao buscar serviços com janela encerrada: %v", err)
+	}
+	for i := range closed {
+		service := &closed[i]
+		service.Status = 0
+		service.SeasonalPaused = true
+		if _, err := c.UpdatePrefRioServiceWithVersion(ctx, service.ID, service, availabilityUserName, availabilityUserCPF, "Despublicação automática (fim da janela de disponibilidade)"); err != nil {
+			log.Printf("[Disponibilidade] Erro ao despublicar serviço %s: %v", service.ID, err)
+			continue
+		}
+		paused = append(paused, service.ID)
+	}
+
+	opened, err := c.searchServicesByFilter(ctx, fmt.Sprintf(
+		"status:=0 && seasonal_paused:=true && available_from:<=%d && (available_until:=0 || available_until:>%d)", now, now))
+	if err != nil {
+		return paused, nil, fmt.Errorf("erro ao buscar serviços com janela aberta: %v", err)
+	}
+	for i := range opened {
+		service := &opened[i]
+		service.Status = 1
+		service.SeasonalPaused = false
+		if _, err := c.UpdatePrefRioServiceWithVersion(ctx, service.ID, service, availabilityUserName, availabilityUserCPF, "Republicação automática (abertura da janela de disponibilidade)"); err != nil {
+			log.Printf("[Disponibilidade] Erro ao republicar serviço %s: %v", service.ID, err)
+			continue
+		}
+		republished = append(republished, service.ID)
+	}
+
+	return paused, republished, nil
+}
+
+// ListClosingAvailabilityWindows lista os serviços publicados cuja janela encerra dentro do prazo
+func (c *Client) ListClosingAvailabilityWindows(ctx context.Context, within time.Duration) ([]models.AvailabilityWarning, error) {
+	now := time.Now()
+	filterBy := fmt.Sprintf("status:=1 && available_until:>%d && available_until:<=%d", now.Unix(), now.Add(within).Unix())
+
+	closing, err := c.searchServicesByFilter(ctx, filterBy)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar janelas prestes a encerrar: %v", err)
+	}
+
+	warnings := make([]models.AvailabilityWarning, 0, len(closing))
+	for _, service := range closing {
+		warnings = append(warnings, models.AvailabilityWarning{
+			ID:             service.ID,
+			NomeServico:    service.NomeServico,
+			OrgaoGestor:    service.OrgaoGestor,
+			AvailableUntil: service.AvailableUntil,
+			HoursLeft:      int(time.Unix(service.AvailableUntil, 0).Sub(now).Hours()),
+		})
+	}
+	return warnings, nil
+}
+
+// StartAvailabilityRoutine aplica periodicamente, em uma única réplica, as janelas de
+// disponibilidade e avisa pelo webhook, uma vez por janela, os serviços cuja janela encerra
+// dentro de warnWithin. onChange é chamado para cada serviço despublicado ou republicado
+// (ex.: para invalidar caches). Job availability-windows.
+func (c *Client) StartAvailabilityRoutine(runner *jobs.Runner, interval, warnWithin time.Duration, notifier *services.WebhookNotifier, onChange func(ctx context.Context, serviceID string)) error {
+	warned := make(map[string]int64) // ID do serviço -> available_until já avisado
+
+	return runner.Register("availability-windows", fmt.Sprintf("@every %dm", int(interval/time.Minute)), 5*time.Minute, func(ctx context.Context) error {
+		paused, republished, err := c.ApplyAvailabilityWindows(ctx)
+		if err != nil {
+			log.Printf("[Disponibilidade] %v", err)
+		}
+		for _, id := range append(paused, republished...) {
+			if onChange != nil {
+				onChange(ctx, id)
+			}
+		}
+		if len(paused) > 0 || len(republished) > 0 {
+			log.Printf("[Disponibilidade] %d serviço(s) despublicado(s), %d republicado(s)", len(paused), len(republished))
+		}
+
+		if !notifier.Enabled() || warnWithin <= 0 {
+			return nil
+		}
+		warnings, err := c.ListClosingAvailabilityWindows(ctx, warnWithin)
+		if err != nil {
+			return err
+		}
+		for _, warning := range warnings {
+			if warned[warning.ID] == warning.AvailableUntil {
+				continue
+			}
+			if err := notifier.Notify(ctx, services.AvailabilityClosingEvent, warning); err != nil {
+				log.Printf("[Disponibilidade] Erro ao avisar encerramento da janela do serviço %s: %v", warning.ID, err)
+				continue
+			}
+			warned[warning.ID] = warning.AvailableUntil
+		}
+		return nil
+	})
+}
+
+// searchServicesByFilter carrega todos os serviços que atendem ao filtro antes de qualquer
+// alteração, para que as atualizações não desloquem a paginação
+func (c *Client) searchServicesByFilter(ctx context.Context, filterBy string) ([]models.PrefRioService, error) {
+	collectionName := "prefrio_services_base"
+
+	var found []models.PrefRioService
+	for page := 1; ; page++ {
+		searchParams := &api.SearchCollectionParams{
+			Q:             stringPtr("*"),
+			FilterBy:      &filterBy,
+			Page:          intPtr(page),
+			PerPage:       intPtr(250),
+			ExcludeFields: stringPtr("embedding"),
+		}
+
+		result, err := c.client.Collection(collectionName).Documents().Search(ctx, searchParams)
+		if err != nil {
+			return nil, err
+		}
+		if result.Hits == nil || len(*result.Hits) == 0 {
+			break
+		}
+
+		for _, hit := range *result.Hits {
+			if hit.Document == nil {
+				continue
+			}
+			docBytes, err := json.Marshal(*hit.Document)
+			if err != nil {
+				continue
+			}
+			var service models.PrefRioService
+			if err := json.Unmarshal(docBytes, &service); err != nil {
+				continue
+			}
+			found = append(found, service)
+		}
+
+		if len(*result.Hits) < 250 {
+			break
+		}
+	}
+
+	return found, nil
+}
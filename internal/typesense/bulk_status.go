@@ -0,0 +1,129 @@
+package typesense
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+// Justificativas registradas nas versões geradas pelas transições em lote
+var bulkStatusReasons = map[models.BulkStatusAction]string{
+	models.BulkStatusPublish:   "Publicação em lote",
+	models.BulkStatusUnpublish: "Despublicação em lote",
+	models.BulkStatusArchive:   "Arquivamento em lote",
+}
+
+// BulkUpdateStatus aplica a transição de status a cada serviço, validando item a item e
+// registrando uma versão por serviço alterado. Falhas em um item não interrompem os demais.
+// Em dry-run apenas a validação é executada.
+func (c *Client) BulkUpdateStatus(ctx context.Context, req *models.BulkStatusRequest, userName, userCPF string) *models.BulkStatusResult {
+	result := &models.BulkStatusResult{
+		Action: req.Action,
+		DryRun: req.DryRun,
+		Total:  len(req.IDs),
+		Items:  make([]models.BulkStatusItem, 0, len(req.IDs)),
+	}
+
+	reason := bulkStatusReasons[req.Action]
+	if req.Reason != "" {
+		reason += ": " + req.Reason
+	}
+
+	seen := make(map[string]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		item := models.BulkStatusItem{ID: id}
+
+		switch {
+		case id == "":
+			item.Status, item.Message = models.BulkItemFailed, "ID vazio"
+		case seen[id]:
+			item.Status, item.Message = models.BulkItemFailed, "ID repetido na requisição"
+		default:
+			item = c.transitionService(ctx, id, req, reason, userName, userCPF)
+		}
+		seen[id] = true
+
+		switch item.Status {
+		case models.BulkItemApplied, models.BulkItemWouldApply:
+			result.Applied++
+		case models.BulkItemSkipped:
+			result.Skipped++
+		default:
+			result.Failed++
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	return result
+}
+
+func (c *Client) transitionService(ctx context.Context, id string, req *models.BulkStatusRequest, reason, userName, userCPF string) models.BulkStatusItem {
+	item := models.BulkStatusItem{ID: id}
+
+	service, err := c.GetPrefRioService(ctx, id)
+	if err != nil {
+		item.Status, item.Message = models.BulkItemFailed, "Serviço não encontrado"
+		return item
+	}
+
+	changed, err := applyStatusAction(service, req.Action, time.Now())
+	switch {
+	case err != nil:
+		item.Status, item.Message = models.BulkItemFailed, err.Error()
+		return item
+	case !changed:
+		item.Status, item.Message = models.BulkItemSkipped, "Serviço já está no estado desejado"
+		return item
+	case req.DryRun:
+		item.Status = models.BulkItemWouldApply
+		return item
+	}
+
+	if _, err := c.UpdatePrefRioServiceWithVersion(ctx, id, service, userName, userCPF, reason); err != nil {
+		item.Status, item.Message = models.BulkItemFailed, fmt.Sprintf("Erro ao salvar: %v", err)
+		return item
+	}
+
+	item.Status = models.BulkItemApplied
+	return item
+}
+
+// applyStatusAction altera o serviço conforme a ação e indica se houve mudança. Retorna erro
+// quando a transição não é permitida para o serviço.
+func applyStatusAction(service *models.PrefRioService, action models.BulkStatusAction, now time.Time) (bool, error) {
+	switch action {
+	case models.BulkStatusPublish:
+		if service.Deprecated && service.SunsetAt != nil && *service.SunsetAt <= now.Unix() {
+			return false, fmt.Errorf("serviço descontinuado com sunset vencido não pode ser publicado")
+		}
+		if service.Status == 1 && !service.AwaitingApproval {
+			return false, nil
+		}
+		service.Status = 1
+		service.AwaitingApproval = false
+
+	case models.BulkStatusUnpublish:
+		if service.Status == 0 {
+			return false, nil
+		}
+		service.Status = 0
+		service.AwaitingApproval = true
+
+	case models.BulkStatusArchive:
+		if service.Status == 0 && service.Deprecated && !service.AwaitingApproval {
+			return false, nil
+		}
+		service.Status = 0
+		service.AwaitingApproval = false
+		service.Deprecated = true
+
+	default:
+		return false, fmt.Errorf("ação inválida: %s", action)
+	}
+
+	// Transições manuais encerram a pausa sazonal (sem republicação automática)
+	service.SeasonalPaused = false
+	return true, nil
+}
@@ -0,0 +1,52 @@
+package typesense
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestApplyStatusAction(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0)
+	past := now.Add(-time.Hour).Unix()
+
+	tests := []struct {
+		name    string
+		service models.PrefRioService
+		action  models.BulkStatusAction
+		changed bool
+		wantErr bool
+		want    models.PrefRioService
+	}{
+		{"publica rascunho", models.PrefRioService{Status: 0, AwaitingApproval: true}, models.BulkStatusPublish, true, false,
+			models.PrefRioService{Status: 1}},
+		{"publicado é ignorado", models.PrefRioService{Status: 1}, models.BulkStatusPublish, false, false,
+			models.PrefRioService{Status: 1}},
+		{"sunset vencido não publica", models.PrefRioService{Deprecated: true, SunsetAt: &past}, models.BulkStatusPublish, false, true,
+			models.PrefRioService{Deprecated: true, SunsetAt: &past}},
+		{"despublica", models.PrefRioService{Status: 1}, models.BulkStatusUnpublish, true, false,
+			models.PrefRioService{Status: 0, AwaitingApproval: true}},
+		{"rascunho não é despublicado", models.PrefRioService{Status: 0}, models.BulkStatusUnpublish, false, false,
+			models.PrefRioService{Status: 0}},
+		{"arquiva", models.PrefRioService{Status: 1}, models.BulkStatusArchive, true, false,
+			models.PrefRioService{Status: 0, Deprecated: true}},
+		{"arquivado é ignorado", models.PrefRioService{Status: 0, Deprecated: true}, models.BulkStatusArchive, false, false,
+			models.PrefRioService{Status: 0, Deprecated: true}},
+		{"ação inválida", models.PrefRioService{Status: 1}, "delete", false, true,
+			models.PrefRioService{Status: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := tt.service
+			changed, err := applyStatusAction(&service, tt.action, now)
+			if changed != tt.changed || (err != nil) != tt.wantErr {
+				t.Fatalf("changed=%v err=%v, esperado changed=%v erro=%v", changed, err, tt.changed, tt.wantErr)
+			}
+			if service.Status != tt.want.Status || service.AwaitingApproval != tt.want.AwaitingApproval || service.Deprecated != tt.want.Deprecated {
+				t.Errorf("serviço resultante %+v, esperado %+v", service, tt.want)
+			}
+		})
+	}
+}
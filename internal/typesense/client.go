@@ -3,16 +3,21 @@ package typesense
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/prefeitura-rio/app-busca-search/internal/config"
 	"github.com/prefeitura-rio/app-busca-search/internal/constants"
 	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/pii"
 	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
 	"github.com/prefeitura-rio/app-busca-search/internal/utils"
 	"github.com/typesense/typesense-go/v3/typesense"
 	"github.com/typesense/typesense-go/v3/typesense/api"
@@ -25,6 +30,9 @@ type Client struct {
 	embeddingModel string
 	versionService *services.VersionService
 	gatewayBaseURL string
+	deepLinks      *services.DeepLinkBuilder
+	cfg            *config.Config
+	dims           schemaDimsCache
 	// relevanciaService and filterService REMOVED - no longer used
 }
 
@@ -34,9 +42,18 @@ func NewClient(cfg *config.Config) *Client {
 		log.Fatal("GATEWAY_BASE_URL environment variable is required but not set")
 	}
 
+	primaryNode := fmt.Sprintf("%s://%s:%s", cfg.TypesenseProtocol, cfg.TypesenseHost, cfg.TypesensePort)
+	nodeOptions := []typesense.ClientOption{typesense.WithServer(primaryNode)}
+	if len(cfg.TypesenseNodes) > 0 && cfg.DegradationPolicy[config.DependencyTypesense] == config.DegradeRetryOtherNode {
+		// O nó principal é preferido; com falha (erro ou 5xx) a requisição é repetida nos demais
+		nodeOptions = []typesense.ClientOption{
+			typesense.WithNearestNode(primaryNode),
+			typesense.WithNodes(cfg.TypesenseNodes),
+		}
+	}
+
 	typesenseClient := typesense.NewClient(
-		typesense.WithServer(fmt.Sprintf("%s://%s:%s", cfg.TypesenseProtocol, cfg.TypesenseHost, cfg.TypesensePort)),
-		typesense.WithAPIKey(cfg.TypesenseAPIKey),
+		append(nodeOptions, typesense.WithAPIKey(cfg.TypesenseAPIKey))...,
 	)
 
 	ctx := context.Background()
@@ -61,6 +78,8 @@ func NewClient(cfg *config.Config) *Client {
 		embeddingModel: cfg.GeminiEmbeddingModel,
 		versionService: versionService,
 		gatewayBaseURL: cfg.GatewayBaseURL,
+		deepLinks:      services.NewDeepLinkBuilder(cfg.DeepLinkChannels),
+		cfg:            cfg,
 	}
 
 	// Garante que a collection de tombamentos existe
@@ -68,13 +87,17 @@ func NewClient(cfg *config.Config) *Client {
 		log.Printf("Aviso: não foi possível criar/verificar collection tombamentos_overlay: %v", err)
 	} else {
 		log.Println("Collection tombamentos_overlay verificada/criada com sucesso")
+		client.syncCollectionFields(ctx, "tombamentos_overlay")
 	}
 
-	// Garante que a collection prefrio_services_base existe
+	// Garante que a collection prefrio_services_base existe. Nesta e nas demais, campos
+	// adicionados ao schema depois da criação são acrescentados à collection existente
 	if err := client.EnsureCollectionExists("prefrio_services_base"); err != nil {
 		log.Printf("Aviso: não foi possível criar/verificar collection prefrio_services_base: %v", err)
 	} else {
 		log.Println("Collection prefrio_services_base verificada/criada com sucesso")
+		client.syncCollectionFields(ctx, "prefrio_services_base")
+		client.checkEmbeddingSchema(ctx, "prefrio_services_base")
 	}
 
 	// Garante que a collection service_versions existe
@@ -82,6 +105,7 @@ func NewClient(cfg *config.Config) *Client {
 		log.Printf("Aviso: não foi possível criar/verificar collection service_versions: %v", err)
 	} else {
 		log.Println("Collection service_versions verificada/criada com sucesso")
+		client.syncCollectionFields(ctx, "service_versions")
 	}
 
 	// Garante que a collection hub_search existe
@@ -89,6 +113,17 @@ func NewClient(cfg *config.Config) *Client {
 		log.Printf("Aviso: não foi possível criar/verificar collection hub_search: %v", err)
 	} else {
 		log.Println("Collection hub_search verificada/criada com sucesso")
+		client.syncCollectionFields(ctx, "hub_search")
+	}
+
+	// Garante que a collection noticias existe quando as notícias estão habilitadas
+	if cfg.NewsroomCMSURL != "" || slices.Contains(cfg.SearchableCollections, NoticiasCollection) {
+		if err := client.EnsureCollectionExists(NoticiasCollection); err != nil {
+			log.Printf("Aviso: não foi possível criar/verificar collection noticias: %v", err)
+		} else {
+			log.Println("Collection noticias verificada/criada com sucesso")
+			client.syncCollectionFields(ctx, NoticiasCollection)
+		}
 	}
 
 	return client
@@ -100,55 +135,68 @@ func (c *Client) GetClient() *typesense.Client {
 }
 
 func (c *Client) GerarEmbedding(ctx context.Context, texto string) ([]float32, error) {
+	embeddings, err := c.GerarEmbeddings(ctx, []string{texto})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// GerarEmbeddings gera os embeddings de vários textos numa única chamada ao Gemini, na ordem
+// dos textos (no máximo maxEmbedBatch por chamada)
+func (c *Client) GerarEmbeddings(ctx context.Context, textos []string) ([][]float32, error) {
 	if c.geminiClient == nil {
 		return nil, fmt.Errorf("cliente Gemini não inicializado")
 	}
 
-	// Trunca texto muito longo
+	// Trunca textos muito longos
 	maxLength := 10000
-	if len(texto) > maxLength {
-		texto = texto[:maxLength]
+	contents := make([]*genai.Content, len(textos))
+	for i, texto := range textos {
+		if len(texto) > maxLength {
+			texto = texto[:maxLength]
+		}
+		contents[i] = genai.NewContentFromText(texto, genai.RoleUser)
 	}
 
-	content := genai.NewContentFromText(texto, genai.RoleUser)
-
 	// Configurar para gerar embeddings com 768 dimensões
-	outputDim := int32(768)
+	outputDim := int32(embeddingDimensions)
 	config := &genai.EmbedContentConfig{
 		OutputDimensionality: &outputDim,
 	}
 
-	resp, err := c.geminiClient.Models.EmbedContent(ctx, c.embeddingModel, []*genai.Content{content}, config)
+	resp, err := c.geminiClient.Models.EmbedContent(ctx, c.embeddingModel, contents, config)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao gerar embedding: %v", err)
+		return nil, fmt.Errorf("erro ao gerar embedding: %w", err)
 	}
 
-	if len(resp.Embeddings) == 0 {
-		return nil, fmt.Errorf("nenhum embedding foi gerado")
+	if len(resp.Embeddings) != len(textos) {
+		return nil, fmt.Errorf("%d embeddings gerados para %d textos", len(resp.Embeddings), len(textos))
 	}
 
-	embedding := resp.Embeddings[0].Values
-
-	// Valida dimensões (sempre 768)
-	if len(embedding) != 768 {
-		log.Printf("AVISO: Embedding de query tem %d dimensões (esperado: 768)", len(embedding))
-		return nil, fmt.Errorf("embedding com dimensões incorretas: %d", len(embedding))
+	embeddings := make([][]float32, len(resp.Embeddings))
+	for i, embedding := range resp.Embeddings {
+		// Valida dimensões (sempre 768)
+		if len(embedding.Values) != embeddingDimensions {
+			log.Printf("AVISO: Embedding tem %d dimensões (esperado: %d)", len(embedding.Values), embeddingDimensions)
+			return nil, fmt.Errorf("embedding com dimensões incorretas: %d", len(embedding.Values))
+		}
+		embeddings[i] = embedding.Values
 	}
 
-	return embedding, nil
+	return embeddings, nil
 }
 
 func (c *Client) BuscaMultiColecaoComTexto(ctx context.Context, colecoes []string, query string, pagina int, porPagina int) (map[string]interface{}, error) {
 	vetor, err := c.GerarEmbedding(ctx, query)
 	if err != nil {
-		return c.BuscaMultiColecao(colecoes, query, pagina, porPagina, nil)
+		return c.BuscaMultiColecao(ctx, colecoes, query, pagina, porPagina, nil)
 	}
 
-	return c.BuscaMultiColecao(colecoes, query, pagina, porPagina, vetor)
+	return c.BuscaMultiColecao(ctx, colecoes, query, pagina, porPagina, vetor)
 }
 
-func (c *Client) BuscaMultiColecao(colecoes []string, query string, pagina int, porPagina int, vetor []float32) (map[string]interface{}, error) {
-	ctx := context.Background()
+func (c *Client) BuscaMultiColecao(ctx context.Context, colecoes []string, query string, pagina int, porPagina int, vetor []float32) (map[string]interface{}, error) {
 	queryStr := query
 	queryByStr := "search_content,titulo,descricao"
 	includeFields := "*"
@@ -321,287 +369,240 @@ func (c *Client) BuscaMultiColecao(colecoes []string, query string, pagina int,
 	return resp, nil
 }
 
-// BuscaPorCategoriaMultiColecao busca documentos por categoria em múltiplas coleções retornando informações completas
-func (c *Client) BuscaPorCategoriaMultiColecao(colecoes []string, categoria string, pagina int, porPagina int) (map[string]interface{}, error) {
-	ctx := context.Background()
-	filterBy := fmt.Sprintf("category:=%s", categoria)
-	includeFields := "*"
-	excludeFields := "embedding"
-
-	// Wrapper para hits com relevância
-	type hitWithRelevance struct {
-		relevancia int
-		hit        map[string]interface{}
-	}
+// categoriaJanela trecho de uma coleção que compõe a página solicitada
+type categoriaJanela struct {
+	indice int // posição da coleção na lista
+	offset int
+	limit  int
+}
 
-	// Combina todos os resultados das coleções e adiciona relevância
-	var allHitsWithRelevance []hitWithRelevance
-	totalFound := 0
+// calcularJanelasCategoria distribui a página [inicio, inicio+tamanho) da lista concatenada
+// das coleções (na ordem recebida) em offsets/limits por coleção, a partir dos totais de cada uma
+func calcularJanelasCategoria(totais []int, inicio, tamanho int) []categoriaJanela {
+	var janelas []categoriaJanela
+	fim := inicio + tamanho
+	acumulado := 0
 
-	// Para cada coleção, busca todos os resultados com paginação
-	for _, colecao := range colecoes {
-		page := 1
-		perPageLimit := 250 // Máximo permitido pelo Typesense
+	for i, total := range totais {
+		colecaoInicio, colecaoFim := acumulado, acumulado+total
+		acumulado = colecaoFim
 
-		// Prepara o filtro para esta coleção específica
-		collectionFilterBy := filterBy
-		if colecao == "prefrio_services_base" {
-			// Adiciona filtro status:=1 (publicado) para prefrio_services_base
-			collectionFilterBy = fmt.Sprintf("%s && status:=1", filterBy)
+		if total == 0 || colecaoFim <= inicio || colecaoInicio >= fim {
+			continue
 		}
 
-		for {
-			searchParams := &api.SearchCollectionParams{
-				Q:             stringPtr("*"),
-				FilterBy:      &collectionFilterBy,
-				Page:          intPtr(page),
-				PerPage:       intPtr(perPageLimit),
-				IncludeFields: &includeFields,
-				ExcludeFields: &excludeFields,
-			}
-
-			searchResult, err := c.client.Collection(colecao).Documents().Search(ctx, searchParams)
-			if err != nil {
-				// Se é erro 404 (coleção não encontrada), pula para próxima coleção
-				if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "Not found") {
-					log.Printf("Coleção %s não encontrada, pulando para próxima coleção", colecao)
-					break // Sai do loop interno para ir para próxima coleção
-				}
-				// Log do erro mas continua com próxima coleção
-				log.Printf("Erro ao buscar na coleção %s: %v", colecao, err)
-				break // Sai do loop interno para ir para próxima coleção
-			}
-
-			var resultMap map[string]interface{}
-			jsonData, err := json.Marshal(searchResult)
-			if err != nil {
-				log.Printf("Erro ao serializar resultado da coleção %s: %v", colecao, err)
-				break // Sai do loop interno para ir para próxima coleção
-			}
-
-			if err := json.Unmarshal(jsonData, &resultMap); err != nil {
-				log.Printf("Erro ao deserializar resultado da coleção %s: %v", colecao, err)
-				break // Sai do loop interno para ir para próxima coleção
-			}
-
-			// Captura o total encontrado na primeira página
-			if page == 1 {
-				if found, ok := resultMap["found"].(float64); ok {
-					totalFound += int(found)
-				}
-			}
-
-			hitsCount := 0
-			if hits, ok := resultMap["hits"].([]interface{}); ok {
-				hitsCount = len(hits)
-				for _, h := range hits {
-					if hitMap, ok := h.(map[string]interface{}); ok {
-						// Verifica se documento legado foi tombado
-						shouldKeep := true
-						if document, ok := hitMap["document"].(map[string]interface{}); ok {
-							if id, ok := document["id"].(string); ok {
-								if c.isLegacyCollectionTombado(ctx, colecao, id) {
-									shouldKeep = false
-									log.Printf("Removendo serviço tombado da categoria: collection=%s, id=%s", colecao, id)
-								}
-							}
-						}
-
-						if !shouldKeep {
-							continue // Pula este documento
-						}
-
-						// REMOVED: relevanciaService - volumetry-based relevance no longer used
-						// Legacy code that calculated relevance based on CSV volumetry data
-						relevancia := 0
-
-						allHitsWithRelevance = append(allHitsWithRelevance, hitWithRelevance{
-							relevancia: relevancia,
-							hit:        hitMap,
-						})
-					}
-				}
-			}
-
-			// Se retornou menos que perPageLimit, chegamos ao fim desta coleção
-			if hitsCount < perPageLimit {
-				break
-			}
-
-			page++
-		}
+		offset := max(inicio, colecaoInicio) - colecaoInicio
+		limit := min(fim, colecaoFim) - colecaoInicio - offset
+		janelas = append(janelas, categoriaJanela{indice: i, offset: offset, limit: limit})
 	}
 
-	// Ordena por relevância (maior relevância primeiro)
-	sort.Slice(allHitsWithRelevance, func(i, j int) bool {
-		return allHitsWithRelevance[i].relevancia > allHitsWithRelevance[j].relevancia
-	})
-
-	// REMOVED: filterService - CSV-based filtering no longer used
-	// Legacy code that filtered documents from carioca-digital based on CSV
+	return janelas
+}
 
-	// Paginação manual dos resultados combinados
-	startIdx := (pagina - 1) * porPagina
-	if startIdx < 0 {
-		startIdx = 0
+// filtroCategoria monta o filtro de uma coleção: categoria, apenas publicados em
+// prefrio_services_base e exclusão dos documentos legados já tombados
+func filtroCategoria(colecao, categoria string, tombados []string) string {
+	filtros := make([]string, 0, 3)
+	if categoria != "" {
+		filtros = append(filtros, fmt.Sprintf("category:=`%s`", categoria))
 	}
-	endIdx := startIdx + porPagina
-	if endIdx > len(allHitsWithRelevance) {
-		endIdx = len(allHitsWithRelevance)
+	if colecao == "prefrio_services_base" {
+		filtros = append(filtros, "status:=1")
 	}
-
-	if startIdx > len(allHitsWithRelevance) {
-		startIdx = len(allHitsWithRelevance)
-	}
-
-	count := 0
-	if endIdx > startIdx {
-		count = endIdx - startIdx
+	if len(tombados) > 0 {
+		filtros = append(filtros, fmt.Sprintf("id:!=[%s]", strings.Join(tombados, ",")))
 	}
+	return strings.Join(filtros, " && ")
+}
 
-	pagedHits := make([]map[string]interface{}, 0, count)
-	if count > 0 {
-		for _, hitWithRel := range allHitsWithRelevance[startIdx:endIdx] {
-			pagedHits = append(pagedHits, hitWithRel.hit)
-		}
+// filtroPtr retorna nil para filtro vazio (sem restrição)
+func filtroPtr(filtro string) *string {
+	if filtro == "" {
+		return nil
 	}
+	return &filtro
+}
 
-	resp := map[string]interface{}{
-		"found":  totalFound,
-		"out_of": totalFound,
-		"page":   pagina,
-		"hits":   pagedHits,
+// ordenacaoCategoria retorna a ordenação aplicada no Typesense para a coleção.
+// Apenas prefrio_services_base possui os campos de destaque e atualização.
+func ordenacaoCategoria(colecao string) *string {
+	if colecao == "prefrio_services_base" {
+		return stringPtr("fixar_destaque:desc,last_update:desc")
 	}
-
-	return resp, nil
+	return nil
 }
 
-// BuscaPorCategoria busca documentos por categoria retornando informações completas
-func (c *Client) BuscaPorCategoria(colecao string, categoria string, pagina int, porPagina int) (map[string]interface{}, error) {
-	ctx := context.Background()
-	filterBy := fmt.Sprintf("category:=%s", categoria)
-	includeFields := "*"
-	excludeFields := "embedding"
+// buscarTombadosPorColecao retorna, em uma única consulta paginada, os IDs antigos já
+// tombados de cada coleção legada presente na lista
+func (c *Client) buscarTombadosPorColecao(ctx context.Context, colecoes []string) map[string][]string {
+	tombados := make(map[string][]string)
 
-	// Wrapper para hits com relevância
-	type hitWithRelevance struct {
-		relevancia int
-		hit        map[string]interface{}
+	var legadas []string
+	for _, colecao := range colecoes {
+		if colecao == "1746_v2_llm" || colecao == "carioca-digital_v2_llm" {
+			legadas = append(legadas, colecao)
+		}
+	}
+	if len(legadas) == 0 {
+		return tombados
 	}
 
-	// Extrai hits e adiciona relevância
-	var allHitsWithRelevance []hitWithRelevance
-	totalFound := 0
-
-	// Busca todos os resultados com paginação para não ultrapassar limite do Typesense
-	page := 1
-	perPageLimit := 250 // Máximo permitido pelo Typesense
+	filterBy := fmt.Sprintf("origem:=[%s]", strings.Join(legadas, ","))
+	perPage := 250
 
-	for {
+	for page := 1; ; page++ {
 		searchParams := &api.SearchCollectionParams{
 			Q:             stringPtr("*"),
 			FilterBy:      &filterBy,
+			IncludeFields: stringPtr("origem,id_servico_antigo"),
 			Page:          intPtr(page),
-			PerPage:       intPtr(perPageLimit),
-			IncludeFields: &includeFields,
-			ExcludeFields: &excludeFields,
-		}
-
-		searchResult, err := c.client.Collection(colecao).Documents().Search(ctx, searchParams)
-		if err != nil {
-			return nil, err
+			PerPage:       intPtr(perPage),
 		}
 
-		var resultMap map[string]interface{}
-		jsonData, err := json.Marshal(searchResult)
+		result, err := c.client.Collection("tombamentos_overlay").Documents().Search(ctx, searchParams)
 		if err != nil {
-			return nil, fmt.Errorf("erro ao serializar resultado: %v", err)
+			if !strings.Contains(err.Error(), "404") && !strings.Contains(err.Error(), "Not found") {
+				log.Printf("Erro ao buscar tombamentos: %v", err)
+			}
+			return tombados
 		}
-
-		if err := json.Unmarshal(jsonData, &resultMap); err != nil {
-			return nil, fmt.Errorf("erro ao deserializar resultado: %v", err)
+		if result.Hits == nil {
+			return tombados
 		}
 
-		// Captura o total encontrado na primeira página
-		if page == 1 {
-			if found, ok := resultMap["found"].(float64); ok {
-				totalFound = int(found)
+		for _, hit := range *result.Hits {
+			if hit.Document == nil {
+				continue
 			}
-		}
-
-		hitsCount := 0
-		if hits, ok := resultMap["hits"].([]interface{}); ok {
-			hitsCount = len(hits)
-			for _, h := range hits {
-				if hitMap, ok := h.(map[string]interface{}); ok {
-					// REMOVED: relevanciaService - volumetry-based relevance no longer used
-					// Legacy code that calculated relevance based on CSV volumetry data
-					relevancia := 0
-
-					allHitsWithRelevance = append(allHitsWithRelevance, hitWithRelevance{
-						relevancia: relevancia,
-						hit:        hitMap,
-					})
-				}
+			origem, _ := (*hit.Document)["origem"].(string)
+			idAntigo, _ := (*hit.Document)["id_servico_antigo"].(string)
+			if origem != "" && idAntigo != "" {
+				tombados[origem] = append(tombados[origem], idAntigo)
 			}
 		}
 
-		// Se retornou menos que perPageLimit, chegamos ao fim
-		if hitsCount < perPageLimit {
-			break
+		if len(*result.Hits) < perPage {
+			return tombados
 		}
-
-		page++
 	}
+}
 
-	// Ordena por relevância (maior relevância primeiro)
-	sort.Slice(allHitsWithRelevance, func(i, j int) bool {
-		return allHitsWithRelevance[i].relevancia > allHitsWithRelevance[j].relevancia
-	})
+// BuscaPorCategoriaMultiColecao busca documentos por categoria em múltiplas coleções retornando informações completas.
+// A lista resultante é a concatenação das coleções na ordem recebida; ordenação, filtro de tombados e
+// paginação são executados no Typesense (uma consulta de totais e uma de documentos, ambas via multi_search).
+func (c *Client) BuscaPorCategoriaMultiColecao(ctx context.Context, colecoes []string, categoria string, pagina int, porPagina int) (map[string]interface{}, error) {
+	if pagina < 1 {
+		pagina = 1
+	}
+	if porPagina < 0 {
+		porPagina = 0
+	}
 
-	// REMOVED: filterService - CSV-based filtering no longer used
-	// Legacy code that filtered documents from carioca-digital based on CSV
+	tombados := c.buscarTombadosPorColecao(ctx, colecoes)
 
-	// Paginação manual dos resultados ordenados
-	startIdx := (pagina - 1) * porPagina
-	if startIdx < 0 {
-		startIdx = 0
+	filtros := make([]string, len(colecoes))
+	for i, colecao := range colecoes {
+		filtros[i] = filtroCategoria(colecao, categoria, tombados[colecao])
 	}
-	endIdx := startIdx + porPagina
-	if endIdx > len(allHitsWithRelevance) {
-		endIdx = len(allHitsWithRelevance)
+
+	// 1ª consulta: apenas os totais de cada coleção
+	contagens := make([]api.MultiSearchCollectionParameters, 0, len(colecoes))
+	for i, colecao := range colecoes {
+		contagens = append(contagens, api.MultiSearchCollectionParameters{
+			Collection: stringPtr(colecao),
+			Q:          stringPtr("*"),
+			FilterBy:   filtroPtr(filtros[i]),
+			PerPage:    intPtr(0),
+		})
 	}
 
-	if startIdx > len(allHitsWithRelevance) {
-		startIdx = len(allHitsWithRelevance)
+	contagemResult, err := c.client.MultiSearch.Perform(ctx, &api.MultiSearchParams{}, api.MultiSearchSearchesParameter{Searches: contagens})
+	if err != nil {
+		return nil, err
 	}
 
-	count := 0
-	if endIdx > startIdx {
-		count = endIdx - startIdx
+	totais := make([]int, len(colecoes))
+	totalFound := 0
+	for i, res := range contagemResult.Results {
+		if i >= len(colecoes) {
+			break
+		}
+		if res.Error != nil {
+			// Coleção inexistente ou com erro: pula para a próxima coleção
+			log.Printf("Erro ao buscar na coleção %s: %s", colecoes[i], *res.Error)
+			continue
+		}
+		if res.Found != nil {
+			totais[i] = *res.Found
+			totalFound += *res.Found
+		}
 	}
 
-	pagedHits := make([]interface{}, 0, count)
-	if count > 0 {
-		for _, hitWithRel := range allHitsWithRelevance[startIdx:endIdx] {
-			pagedHits = append(pagedHits, hitWithRel.hit)
+	// 2ª consulta: somente os trechos de cada coleção que compõem a página
+	janelas := calcularJanelasCategoria(totais, (pagina-1)*porPagina, porPagina)
+	pagedHits := make([]map[string]interface{}, 0, porPagina)
+
+	if len(janelas) > 0 {
+		buscas := make([]api.MultiSearchCollectionParameters, 0, len(janelas))
+		for _, janela := range janelas {
+			colecao := colecoes[janela.indice]
+			buscas = append(buscas, api.MultiSearchCollectionParameters{
+				Collection:    stringPtr(colecao),
+				Q:             stringPtr("*"),
+				FilterBy:      filtroPtr(filtros[janela.indice]),
+				SortBy:        ordenacaoCategoria(colecao),
+				Offset:        intPtr(janela.offset),
+				Limit:         intPtr(janela.limit),
+				IncludeFields: stringPtr("*"),
+				ExcludeFields: stringPtr("embedding"),
+			})
+		}
+
+		result, err := c.client.MultiSearch.Perform(ctx, &api.MultiSearchParams{}, api.MultiSearchSearchesParameter{Searches: buscas})
+		if err != nil {
+			return nil, err
+		}
+
+		for i, res := range result.Results {
+			if res.Error != nil {
+				log.Printf("Erro ao buscar na coleção %s: %s", colecoes[janelas[i].indice], *res.Error)
+				continue
+			}
+			if res.Hits == nil {
+				continue
+			}
+			for _, hit := range *res.Hits {
+				var hitMap map[string]interface{}
+				jsonData, err := json.Marshal(hit)
+				if err != nil {
+					continue
+				}
+				if err := json.Unmarshal(jsonData, &hitMap); err != nil {
+					continue
+				}
+				pagedHits = append(pagedHits, hitMap)
+			}
 		}
 	}
 
-	// Reconstrói o resultado com paginação ordenada
-	finalResultMap := map[string]interface{}{
-		"hits":  pagedHits,
-		"found": totalFound,
-		"page":  pagina,
+	resp := map[string]interface{}{
+		"found":  totalFound,
+		"out_of": totalFound,
+		"page":   pagina,
+		"hits":   pagedHits,
 	}
 
-	return finalResultMap, nil
+	return resp, nil
+}
+
+// BuscaPorCategoria busca documentos por categoria em uma única coleção retornando informações completas
+func (c *Client) BuscaPorCategoria(ctx context.Context, colecao string, categoria string, pagina int, porPagina int) (map[string]interface{}, error) {
+	return c.BuscaPorCategoriaMultiColecao(ctx, []string{colecao}, categoria, pagina, porPagina)
 }
 
 // BuscaPorID busca um documento específico por ID retornando todos os campos exceto embedding e normalizados
 // Se o documento for de collection legada e foi tombado, retorna o documento novo
-func (c *Client) BuscaPorID(colecao string, documentoID string) (map[string]interface{}, error) {
-	ctx := context.Background()
+func (c *Client) BuscaPorID(ctx context.Context, colecao string, documentoID string) (map[string]interface{}, error) {
 
 	// Verifica se documento legado foi tombado
 	if c.isLegacyCollectionTombado(ctx, colecao, documentoID) {
@@ -612,7 +613,7 @@ func (c *Client) BuscaPorID(colecao string, documentoID string) (map[string]inte
 				documentoID, colecao, tombamento.IDServicoNovo)
 
 			// Retorna o documento novo da prefrio_services_base
-			return c.BuscaPorID("prefrio_services_base", tombamento.IDServicoNovo)
+			return c.BuscaPorID(ctx, "prefrio_services_base", tombamento.IDServicoNovo)
 		}
 	}
 
@@ -637,9 +638,9 @@ func (c *Client) BuscaPorID(colecao string, documentoID string) (map[string]inte
 	return resultMap, nil
 }
 
-// BuscarCategoriasRelevancia busca todas as categorias e calcula sua relevância baseada na volumetria dos serviços
-func (c *Client) BuscarCategoriasRelevancia(colecoes []string) (*models.CategoriasRelevanciaResponse, error) {
-	ctx := context.Background()
+// BuscarCategoriasRelevancia busca todas as categorias e calcula sua relevância baseada na volumetria dos serviços.
+// A quantidade de serviços por categoria vem dos facets de uma única consulta multi_search.
+func (c *Client) BuscarCategoriasRelevancia(ctx context.Context, colecoes []string) (*models.CategoriasRelevanciaResponse, error) {
 
 	// Mapa para acumular relevância por categoria
 	categoriasMap := make(map[string]*models.CategoriaRelevancia)
@@ -654,56 +655,55 @@ func (c *Client) BuscarCategoriasRelevancia(colecoes []string) (*models.Categori
 		}
 	}
 
-	// Para cada coleção, busca todas as categorias
+	tombados := c.buscarTombadosPorColecao(ctx, colecoes)
+
+	buscas := make([]api.MultiSearchCollectionParameters, 0, len(colecoes))
 	for _, colecao := range colecoes {
-		// Busca usando facet para obter categorias únicas
-		searchParams := &api.SearchCollectionParams{
-			Q:       stringPtr("*"),
-			FacetBy: stringPtr("category"),
-			Page:    intPtr(1),
-			PerPage: intPtr(0), // Só queremos os facets, não os documentos
-		}
+		buscas = append(buscas, api.MultiSearchCollectionParameters{
+			Collection:     stringPtr(colecao),
+			Q:              stringPtr("*"),
+			FilterBy:       filtroPtr(filtroCategoria(colecao, "", tombados[colecao])),
+			FacetBy:        stringPtr("category"),
+			MaxFacetValues: intPtr(250),
+			PerPage:        intPtr(0), // Só queremos os facets, não os documentos
+		})
+	}
 
-		// Adiciona filtro status:=1 (publicado) para prefrio_services_base
-		if colecao == "prefrio_services_base" {
-			filterBy := "status:=1"
-			searchParams.FilterBy = &filterBy
-		}
+	result, err := c.client.MultiSearch.Perform(ctx, &api.MultiSearchParams{}, api.MultiSearchSearchesParameter{Searches: buscas})
+	if err != nil {
+		return nil, err
+	}
 
-		searchResult, err := c.client.Collection(colecao).Documents().Search(ctx, searchParams)
-		if err != nil {
-			// Se é erro 404 (coleção não encontrada), pula para próxima coleção
-			if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "Not found") {
-				log.Printf("Coleção %s não encontrada, pulando para próxima coleção", colecao)
-				continue
+	for i, res := range result.Results {
+		if res.Error != nil {
+			// Coleção inexistente ou com erro: pula para a próxima coleção
+			if i < len(colecoes) {
+				log.Printf("Erro ao buscar categorias na coleção %s: %s", colecoes[i], *res.Error)
 			}
-			log.Printf("Erro ao buscar categorias na coleção %s: %v", colecao, err)
+			continue
+		}
+		if res.FacetCounts == nil {
 			continue
 		}
 
-		// Processa os facets para obter categorias
-		var resultMap map[string]interface{}
-		jsonData, _ := json.Marshal(searchResult)
-		json.Unmarshal(jsonData, &resultMap)
+		for _, facet := range *res.FacetCounts {
+			if facet.FieldName == nil || *facet.FieldName != "category" || facet.Counts == nil {
+				continue
+			}
+			for _, count := range *facet.Counts {
+				if count.Value == nil || *count.Value == "" || count.Count == nil {
+					continue
+				}
 
-		if facetCounts, ok := resultMap["facet_counts"].([]interface{}); ok {
-			for _, facet := range facetCounts {
-				if facetMap, ok := facet.(map[string]interface{}); ok {
-					if fieldName, ok := facetMap["field_name"].(string); ok && fieldName == "category" {
-						if counts, ok := facetMap["counts"].([]interface{}); ok {
-							// Para cada categoria encontrada nos dados, calcula a relevância dos seus serviços
-							for _, count := range counts {
-								if countMap, ok := count.(map[string]interface{}); ok {
-									if categoria, ok := countMap["value"].(string); ok {
-										if categoria != "" {
-											if err := c.calcularRelevanciaCategoria(colecao, categoria, categoriasMap); err != nil {
-												log.Printf("Erro ao calcular relevância da categoria %s: %v", categoria, err)
-											}
-										}
-									}
-								}
-							}
-						}
+				// REMOVED: relevanciaService - volumetry-based relevance no longer used
+				// A relevância permanece zerada; apenas a quantidade de serviços é acumulada
+				categoria := *count.Value
+				if existente, exists := categoriasMap[categoria]; exists {
+					existente.QuantidadeServicos += *count.Count
+				} else {
+					categoriasMap[categoria] = &models.CategoriaRelevancia{
+						Nome:               categoria,
+						QuantidadeServicos: *count.Count,
 					}
 				}
 			}
@@ -724,9 +724,12 @@ func (c *Client) BuscarCategoriasRelevancia(colecoes []string) (*models.Categori
 		categorias = append(categorias, *categoria)
 	}
 
-	// Ordena por relevância total (maior primeiro)
+	// Ordena por relevância total (maior primeiro), desempatando pela quantidade de serviços
 	sort.Slice(categorias, func(i, j int) bool {
-		return categorias[i].RelevanciaTotal > categorias[j].RelevanciaTotal
+		if categorias[i].RelevanciaTotal != categorias[j].RelevanciaTotal {
+			return categorias[i].RelevanciaTotal > categorias[j].RelevanciaTotal
+		}
+		return categorias[i].QuantidadeServicos > categorias[j].QuantidadeServicos
 	})
 
 	response := &models.CategoriasRelevanciaResponse{
@@ -738,89 +741,8 @@ func (c *Client) BuscarCategoriasRelevancia(colecoes []string) (*models.Categori
 	return response, nil
 }
 
-// calcularRelevanciaCategoria calcula a relevância de uma categoria específica
-func (c *Client) calcularRelevanciaCategoria(colecao string, categoria string, categoriasMap map[string]*models.CategoriaRelevancia) error {
-	ctx := context.Background()
-	filterBy := fmt.Sprintf("category:=%s", categoria)
-
-	// Adiciona filtro status:=1 (publicado) para prefrio_services_base
-	if colecao == "prefrio_services_base" {
-		filterBy = fmt.Sprintf("%s && status:=1", filterBy)
-	}
-
-	relevanciaTotal := 0
-	quantidadeServicos := 0
-	page := 1
-	perPage := 250 // Máximo permitido pelo Typesense
-
-	for {
-		searchParams := &api.SearchCollectionParams{
-			Q:             stringPtr("*"),
-			FilterBy:      &filterBy,
-			Page:          intPtr(page),
-			PerPage:       intPtr(perPage),
-			IncludeFields: stringPtr("titulo"),
-			ExcludeFields: stringPtr("embedding"),
-		}
-
-		searchResult, err := c.client.Collection(colecao).Documents().Search(ctx, searchParams)
-		if err != nil {
-			// Se é erro 404 (coleção não encontrada), pula esta coleção
-			if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "Not found") {
-				log.Printf("Coleção %s não encontrada para categoria %s, pulando", colecao, categoria)
-				return nil
-			}
-			return err
-		}
-
-		var resultMap map[string]interface{}
-		jsonData, _ := json.Marshal(searchResult)
-		json.Unmarshal(jsonData, &resultMap)
-
-		hitsCount := 0
-		if hits, ok := resultMap["hits"].([]interface{}); ok {
-			hitsCount = len(hits)
-			for _, h := range hits {
-				if hitMap, ok := h.(map[string]interface{}); ok {
-					if document, ok := hitMap["document"].(map[string]interface{}); ok {
-						if _, ok := document["titulo"].(string); ok {
-							// REMOVED: relevanciaService - volumetry-based relevance no longer used
-							// Legacy code that calculated relevance based on CSV volumetry data
-							relevancia := 0
-							relevanciaTotal += relevancia
-							quantidadeServicos++
-						}
-					}
-				}
-			}
-		}
-
-		// Se retornou menos que perPage, chegamos ao fim
-		if hitsCount < perPage {
-			break
-		}
-
-		page++
-	}
-
-	// Acumula no mapa de categorias (pode existir em múltiplas coleções)
-	if existente, exists := categoriasMap[categoria]; exists {
-		existente.RelevanciaTotal += relevanciaTotal
-		existente.QuantidadeServicos += quantidadeServicos
-	} else {
-		categoriasMap[categoria] = &models.CategoriaRelevancia{
-			Nome:               categoria,
-			RelevanciaTotal:    relevanciaTotal,
-			QuantidadeServicos: quantidadeServicos,
-		}
-	}
-
-	return nil
-}
-
 // DiagnosticarCategoriasExistentes lista todas as categorias que existem nos dados das coleções
-func (c *Client) DiagnosticarCategoriasExistentes(colecoes []string) (map[string]int, error) {
-	ctx := context.Background()
+func (c *Client) DiagnosticarCategoriasExistentes(ctx context.Context, colecoes []string) (map[string]int, error) {
 	categoriasEncontradas := make(map[string]int)
 
 	// Para cada coleção, busca todas as categorias
@@ -895,27 +817,37 @@ func (c *Client) EnsureCollectionExists(collectionName string) error {
 
 	// Se não existe, cria a collection baseado no nome
 	if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "Not found") {
-		switch collectionName {
-		case "service_versions":
-			return c.createServiceVersionsCollection(collectionName)
-		case "prefrio_services_base":
-			return c.createPrefRioServicesCollection(collectionName)
-		case "hub_search":
-			return c.createHubSearchCollection(collectionName)
-		default:
-			// Para outras collections, assume schema de prefrio_services_base
-			return c.createPrefRioServicesCollection(collectionName)
+		schema := collectionSchema(collectionName)
+		if _, err := c.client.Collections().Create(ctx, schema); err != nil {
+			return fmt.Errorf("erro ao criar collection %s: %v", collectionName, err)
 		}
+		log.Printf("Collection %s criada com sucesso", collectionName)
+		return nil
 	}
 
 	return err
 }
 
-// createPrefRioServicesCollection cria a collection prefrio_services_base com o schema apropriado
-func (c *Client) createPrefRioServicesCollection(collectionName string) error {
-	ctx := context.Background()
+// collectionSchema retorna o schema da collection pelo nome
+func collectionSchema(collectionName string) *api.CollectionSchema {
+	switch collectionName {
+	case "service_versions":
+		return services.ServiceVersionsSchema(collectionName)
+	case "hub_search":
+		return hubSearchSchema(collectionName)
+	case "tombamentos_overlay":
+		return tombamentosSchema(collectionName)
+	case NoticiasCollection:
+		return noticiasSchema(collectionName)
+	default:
+		// prefrio_services_base e outras collections usam o schema de serviços
+		return prefRioServicesSchema(collectionName)
+	}
+}
 
-	schema := &api.CollectionSchema{
+// prefRioServicesSchema schema da collection prefrio_services_base (e das collections de serviços)
+func prefRioServicesSchema(collectionName string) *api.CollectionSchema {
+	return &api.CollectionSchema{
 		Name: collectionName,
 		Fields: []api.Field{
 			{Name: "id", Type: "string", Optional: boolPtr(true)},
@@ -945,87 +877,48 @@ func (c *Client) createPrefRioServicesCollection(collectionName string) error {
 			{Name: "created_at", Type: "int64", Facet: boolPtr(false)},
 			{Name: "last_update", Type: "int64", Facet: boolPtr(false)},
 			{Name: "search_content", Type: "string", Facet: boolPtr(false)},
+			{Name: "nome_servico_fonetico", Type: "string", Facet: boolPtr(false), Optional: boolPtr(true)},
 			{Name: "buttons", Type: "object[]", Facet: boolPtr(false), Optional: boolPtr(true)},
 			{Name: "embedding", Type: "float[]", Facet: boolPtr(false), Optional: boolPtr(true), NumDim: intPtr(768)},
+			{Name: "embedding_model", Type: "string", Facet: boolPtr(true), Optional: boolPtr(true)},
+			{Name: "embedding_dim", Type: "int32", Facet: boolPtr(true), Optional: boolPtr(true)},
+			{Name: "embedding_hash", Type: "string", Facet: boolPtr(false), Optional: boolPtr(true), Index: boolPtr(false)},
+			{Name: "deprecated", Type: "bool", Facet: boolPtr(true), Optional: boolPtr(true)},
+			{Name: "replaced_by", Type: "string", Facet: boolPtr(false), Optional: boolPtr(true)},
+			{Name: "sunset_at", Type: "int64", Facet: boolPtr(false), Optional: boolPtr(true)},
+			{Name: "tenant", Type: "string", Facet: boolPtr(true), Optional: boolPtr(true)},
+			{Name: "attachments", Type: "object[]", Facet: boolPtr(false), Optional: boolPtr(true)},
+			{Name: "attachments.filename", Type: "string[]", Facet: boolPtr(false), Optional: boolPtr(true)},
+			{Name: "attachments.mime_type", Type: "string[]", Facet: boolPtr(true), Optional: boolPtr(true)},
+			{Name: "entities", Type: "object", Facet: boolPtr(false), Optional: boolPtr(true)},
+			{Name: "entities.orgaos", Type: "string[]", Facet: boolPtr(true), Optional: boolPtr(true)},
+			{Name: "entities.documentos", Type: "string[]", Facet: boolPtr(true), Optional: boolPtr(true)},
+			{Name: "entities.prazo_dias_min", Type: "int32", Facet: boolPtr(false), Optional: boolPtr(true)},
+			{Name: "entities.valor_min", Type: "float", Facet: boolPtr(false), Optional: boolPtr(true)},
+			{Name: "translations", Type: "object", Facet: boolPtr(false), Optional: boolPtr(true)},
+			{Name: "deep_links", Type: "object", Facet: boolPtr(false), Optional: boolPtr(true), Index: boolPtr(false)},
+			{Name: "available_from", Type: "int64", Facet: boolPtr(false), Optional: boolPtr(true)},
+			{Name: "available_until", Type: "int64", Facet: boolPtr(false), Optional: boolPtr(true)},
+			{Name: "seasonal_paused", Type: "bool", Facet: boolPtr(true), Optional: boolPtr(true)},
+			{Name: "destaque_until", Type: "int64", Facet: boolPtr(false), Optional: boolPtr(true)},
+			{Name: "bairros", Type: "string[]", Facet: boolPtr(true), Optional: boolPtr(true)},
+			{Name: "regioes_administrativas", Type: "string[]", Facet: boolPtr(true), Optional: boolPtr(true)},
+			{Name: "restrito_bairros", Type: "bool", Facet: boolPtr(true), Optional: boolPtr(true)},
+			{Name: "transporte", Type: "object", Facet: boolPtr(false), Optional: boolPtr(true), Index: boolPtr(false)},
+			{Name: "resumo_plaintext", Type: "string", Facet: boolPtr(false), Optional: boolPtr(true)},
+			{Name: "resultado_solicitacao_plaintext", Type: "string", Facet: boolPtr(false), Optional: boolPtr(true), Index: boolPtr(false)},
+			{Name: "descricao_completa_plaintext", Type: "string", Facet: boolPtr(false), Optional: boolPtr(true), Index: boolPtr(false)},
+			{Name: "documentos_necessarios_plaintext", Type: "string[]", Facet: boolPtr(false), Optional: boolPtr(true), Index: boolPtr(false)},
+			{Name: "instrucoes_solicitante_plaintext", Type: "string", Facet: boolPtr(false), Optional: boolPtr(true), Index: boolPtr(false)},
 		},
 		DefaultSortingField: stringPtr("last_update"),
 		EnableNestedFields:  boolPtr(true),
 	}
-
-	_, err := c.client.Collections().Create(ctx, schema)
-	if err != nil {
-		return fmt.Errorf("erro ao criar collection %s: %v", collectionName, err)
-	}
-
-	log.Printf("Collection %s criada com sucesso", collectionName)
-	return nil
-}
-
-// createServiceVersionsCollection cria a collection service_versions com o schema apropriado
-func (c *Client) createServiceVersionsCollection(collectionName string) error {
-	ctx := context.Background()
-
-	schema := &api.CollectionSchema{
-		Name: collectionName,
-		Fields: []api.Field{
-			{Name: "id", Type: "string", Optional: boolPtr(true)},
-			{Name: "service_id", Type: "string", Facet: boolPtr(true)},
-			{Name: "version_number", Type: "int64", Facet: boolPtr(true)},
-			{Name: "created_at", Type: "int64", Facet: boolPtr(false)},
-			{Name: "created_by", Type: "string", Facet: boolPtr(true)},
-			{Name: "created_by_cpf", Type: "string", Facet: boolPtr(true)},
-			{Name: "change_type", Type: "string", Facet: boolPtr(true)},
-			{Name: "change_reason", Type: "string", Facet: boolPtr(false), Optional: boolPtr(true)},
-			{Name: "previous_version", Type: "int64", Facet: boolPtr(false), Optional: boolPtr(true)},
-			{Name: "is_rollback", Type: "bool", Facet: boolPtr(true)},
-			{Name: "rollback_to_version", Type: "int64", Facet: boolPtr(false), Optional: boolPtr(true)},
-
-			// Snapshot do serviço (campos principais)
-			{Name: "nome_servico", Type: "string", Facet: boolPtr(false)},
-			{Name: "orgao_gestor", Type: "string[]", Facet: boolPtr(false)},
-			{Name: "resumo", Type: "string", Facet: boolPtr(false)},
-			{Name: "tempo_atendimento", Type: "string", Facet: boolPtr(false), Optional: boolPtr(true)},
-			{Name: "custo_servico", Type: "string", Facet: boolPtr(false), Optional: boolPtr(true)},
-			{Name: "resultado_solicitacao", Type: "string", Facet: boolPtr(false), Optional: boolPtr(true)},
-			{Name: "descricao_completa", Type: "string", Facet: boolPtr(false), Optional: boolPtr(true)},
-			{Name: "autor", Type: "string", Facet: boolPtr(false)},
-			{Name: "documentos_necessarios", Type: "string[]", Facet: boolPtr(false), Optional: boolPtr(true)},
-			{Name: "instrucoes_solicitante", Type: "string", Facet: boolPtr(false), Optional: boolPtr(true)},
-			{Name: "canais_digitais", Type: "string[]", Facet: boolPtr(false), Optional: boolPtr(true)},
-			{Name: "canais_presenciais", Type: "string[]", Facet: boolPtr(false), Optional: boolPtr(true)},
-			{Name: "servico_nao_cobre", Type: "string", Facet: boolPtr(false), Optional: boolPtr(true)},
-			{Name: "legislacao_relacionada", Type: "string[]", Facet: boolPtr(false), Optional: boolPtr(true)},
-			{Name: "tema_geral", Type: "string", Facet: boolPtr(false)},
-			{Name: "publico_especifico", Type: "string[]", Facet: boolPtr(false), Optional: boolPtr(true)},
-			{Name: "fixar_destaque", Type: "bool", Facet: boolPtr(false)},
-			{Name: "awaiting_approval", Type: "bool", Facet: boolPtr(false)},
-			{Name: "published_at", Type: "int64", Facet: boolPtr(false), Optional: boolPtr(true)},
-			{Name: "is_free", Type: "bool", Facet: boolPtr(false), Optional: boolPtr(true)},
-			{Name: "status", Type: "int32", Facet: boolPtr(true)},
-			{Name: "search_content", Type: "string", Facet: boolPtr(false)},
-
-			// Campos de controle de versão
-			{Name: "embedding_hash", Type: "string", Facet: boolPtr(false), Optional: boolPtr(true)},
-			{Name: "changed_fields_json", Type: "string", Facet: boolPtr(false), Optional: boolPtr(true)},
-		},
-		DefaultSortingField: stringPtr("created_at"),
-		EnableNestedFields:  boolPtr(true),
-	}
-
-	_, err := c.client.Collections().Create(ctx, schema)
-	if err != nil {
-		return fmt.Errorf("erro ao criar collection %s: %v", collectionName, err)
-	}
-
-	log.Printf("Collection %s criada com sucesso", collectionName)
-	return nil
 }
 
-// createHubSearchCollection cria a collection hub_search com o schema apropriado
-func (c *Client) createHubSearchCollection(collectionName string) error {
-	ctx := context.Background()
-
-	schema := &api.CollectionSchema{
+// hubSearchSchema schema da collection hub_search
+func hubSearchSchema(collectionName string) *api.CollectionSchema {
+	return &api.CollectionSchema{
 		Name: collectionName,
 		Fields: []api.Field{
 			// Identity
@@ -1050,6 +943,11 @@ func (c *Client) createHubSearchCollection(collectionName string) error {
 			{Name: "subcategories", Type: "string[]", Facet: boolPtr(true), Optional: boolPtr(true)},
 			{Name: "tags", Type: "string[]", Facet: boolPtr(true), Optional: boolPtr(true)},
 
+			// Neighborhoods
+			{Name: "bairros", Type: "string[]", Facet: boolPtr(true), Optional: boolPtr(true)},
+			{Name: "regioes_administrativas", Type: "string[]", Facet: boolPtr(true), Optional: boolPtr(true)},
+			{Name: "restrito_bairros", Type: "bool", Facet: boolPtr(true), Optional: boolPtr(true)},
+
 			// Metadata
 			{Name: "status", Type: "int32", Facet: boolPtr(true)},
 			{Name: "priority", Type: "int32", Facet: boolPtr(false), Optional: boolPtr(true)},
@@ -1063,14 +961,6 @@ func (c *Client) createHubSearchCollection(collectionName string) error {
 		DefaultSortingField: stringPtr("updated_at"),
 		EnableNestedFields:  boolPtr(true),
 	}
-
-	_, err := c.client.Collections().Create(ctx, schema)
-	if err != nil {
-		return fmt.Errorf("erro ao criar collection %s: %v", collectionName, err)
-	}
-
-	log.Printf("Collection %s criada com sucesso", collectionName)
-	return nil
 }
 
 // CreatePrefRioService cria um novo serviço na collection prefrio_services_base
@@ -1078,7 +968,10 @@ func (c *Client) CreatePrefRioService(ctx context.Context, service *models.PrefR
 	return c.CreatePrefRioServiceWithVersion(ctx, service, "", "")
 }
 
-// CreatePrefRioServiceWithVersion cria um novo serviço e captura a primeira versão
+// CreatePrefRioServiceWithVersion cria um novo serviço e captura a primeira versão. A versão é
+// gravada antes do documento, marcada como pendente, e confirmada depois dele: se a versão
+// falha, o serviço não é criado; se o documento falha, a versão é descartada. Pendências que
+// sobrarem de uma falha no meio do caminho são resolvidas pela reconciliação (ReconcileVersions).
 func (c *Client) CreatePrefRioServiceWithVersion(ctx context.Context, service *models.PrefRioService, userName, userCPF string) (*models.PrefRioService, error) {
 	collectionName := "prefrio_services_base"
 
@@ -1092,15 +985,22 @@ func (c *Client) CreatePrefRioServiceWithVersion(ctx context.Context, service *m
 	service.CreatedAt = now
 	service.LastUpdate = now
 
-	// Wrap service URLs through gateway
-	c.wrapServiceURLs(service)
+	// O ID é gerado antes da inserção para que a versão 1 possa ser gravada antes do documento
+	if service.ID == "" {
+		service.ID = uuid.New().String()
+	}
 
-	// Gera o search_content combinando campos relevantes
-	service.SearchContent = c.generateSearchContent(service)
+	// Associa o serviço ao tenant da requisição
+	if service.Tenant == "" {
+		service.Tenant = tenant.OwnerID(ctx)
+	}
+
+	// Sanitiza, aplica o gateway nas URLs e gera os campos derivados
+	c.prepareServiceDocument(ctx, service)
 
 	// Gera embedding se o cliente Gemini estiver disponível
 	if c.geminiClient != nil {
-		embedding, err := c.GerarEmbedding(ctx, service.SearchContent)
+		embedding, err := c.gerarEmbeddingServico(ctx, service.SearchContent)
 		if err != nil {
 			log.Printf("Aviso: erro ao gerar embedding: %v", err)
 		} else {
@@ -1109,8 +1009,14 @@ func (c *Client) CreatePrefRioServiceWithVersion(ctx context.Context, service *m
 			for i, v := range embedding {
 				service.Embedding[i] = float64(v)
 			}
+			service.EmbeddingModel = c.embeddingModel
+			service.EmbeddingDim = len(embedding)
+			service.EmbeddingHash = utils.EmbeddingHash(service.EmbeddingModel, service.Embedding)
 		}
 	}
+	if err := c.checkEmbeddingDim(ctx, collectionName, "embedding", len(service.Embedding)); err != nil {
+		return nil, err
+	}
 
 	// Converte para map[string]interface{} para inserção
 	serviceMap, err := c.structToMap(service)
@@ -1118,17 +1024,29 @@ func (c *Client) CreatePrefRioServiceWithVersion(ctx context.Context, service *m
 		return nil, fmt.Errorf("erro ao converter service para map: %v", err)
 	}
 
-	// Remove o ID se estiver vazio para auto-geração
-	if service.ID == "" {
-		delete(serviceMap, "id")
+	// Grava a versão 1 pendente antes do documento
+	if userName == "" {
+		userName = service.Autor
+	}
+	version, err := c.versionService.CapturePendingVersion(ctx, service, userName, userCPF, "Criação inicial do serviço")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao capturar versão inicial: %v", err)
 	}
 
 	// Insere o documento
 	result, err := c.client.Collection(collectionName).Documents().Create(ctx, serviceMap, &api.DocumentIndexParameters{})
 	if err != nil {
+		if discardErr := c.versionService.DiscardVersion(ctx, version.ID); discardErr != nil {
+			log.Printf("Aviso: %v", discardErr)
+		}
 		return nil, fmt.Errorf("erro ao criar serviço: %v", err)
 	}
 
+	// Confirma a versão; se falhar, a reconciliação confirma depois
+	if err := c.versionService.ConfirmVersion(ctx, version.ID); err != nil {
+		log.Printf("Aviso: %v", err)
+	}
+
 	// Converte o resultado de volta para o struct
 	resultBytes, err := json.Marshal(result)
 	if err != nil {
@@ -1140,23 +1058,6 @@ func (c *Client) CreatePrefRioServiceWithVersion(ctx context.Context, service *m
 		return nil, fmt.Errorf("erro ao deserializar resultado: %v", err)
 	}
 
-	// Captura versão 1 se informações do usuário forem fornecidas
-	if userName != "" && userCPF != "" {
-		_, err = c.versionService.CaptureVersion(
-			ctx,
-			&createdService,
-			"create",
-			userName,
-			userCPF,
-			"Criação inicial do serviço",
-			nil, // Não há versão anterior
-		)
-		if err != nil {
-			log.Printf("Aviso: erro ao capturar versão inicial: %v", err)
-			// Não falha a criação do serviço se a versão falhar
-		}
-	}
-
 	return &createdService, nil
 }
 
@@ -1169,10 +1070,13 @@ func (c *Client) UpdatePrefRioService(ctx context.Context, id string, service *m
 func (c *Client) UpdatePrefRioServiceWithVersion(ctx context.Context, id string, service *models.PrefRioService, userName, userCPF, changeReason string) (*models.PrefRioService, error) {
 	collectionName := "prefrio_services_base"
 
-	// Verifica se o documento existe
-	_, err := c.client.Collection(collectionName).Document(id).Retrieve(ctx)
+	// Verifica se o documento existe e pertence ao tenant da requisição
+	existing, err := c.GetPrefRioService(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("serviço não encontrado: %v", err)
+		return nil, err
+	}
+	if service.Tenant == "" {
+		service.Tenant = existing.Tenant
 	}
 
 	// Busca a versão anterior (sempre, para rastrear mudanças)
@@ -1187,15 +1091,18 @@ func (c *Client) UpdatePrefRioServiceWithVersion(ctx context.Context, id string,
 	service.ID = id
 	service.LastUpdate = time.Now().Unix()
 
-	// Wrap service URLs through gateway
-	c.wrapServiceURLs(service)
-
-	// Gera o search_content combinando campos relevantes
-	service.SearchContent = c.generateSearchContent(service)
-
-	// Gera embedding se o cliente Gemini estiver disponível
-	if c.geminiClient != nil {
-		embedding, err := c.GerarEmbedding(ctx, service.SearchContent)
+	// Sanitiza, aplica o gateway nas URLs e gera os campos derivados
+	c.prepareServiceDocument(ctx, service)
+
+	// Com o search_content e o modelo inalterados, o embedding gravado (identificado pelo hash) é
+	// mantido: a atualização parcial não envia o vetor. Caso contrário, gera o embedding se o
+	// cliente Gemini estiver disponível
+	if reuseEmbedding(existing, service.SearchContent, c.embeddingModel) {
+		service.EmbeddingModel = existing.EmbeddingModel
+		service.EmbeddingDim = existing.EmbeddingDim
+		service.EmbeddingHash = existing.EmbeddingHash
+	} else if c.geminiClient != nil {
+		embedding, err := c.gerarEmbeddingServico(ctx, service.SearchContent)
 		if err != nil {
 			log.Printf("Aviso: erro ao gerar embedding: %v", err)
 		} else {
@@ -1204,8 +1111,14 @@ func (c *Client) UpdatePrefRioServiceWithVersion(ctx context.Context, id string,
 			for i, v := range embedding {
 				service.Embedding[i] = float64(v)
 			}
+			service.EmbeddingModel = c.embeddingModel
+			service.EmbeddingDim = len(embedding)
+			service.EmbeddingHash = utils.EmbeddingHash(service.EmbeddingModel, service.Embedding)
 		}
 	}
+	if err := c.checkEmbeddingDim(ctx, collectionName, "embedding", len(service.Embedding)); err != nil {
+		return nil, err
+	}
 
 	// Converte para map[string]interface{} para atualização
 	serviceMap, err := c.structToMap(service)
@@ -1232,7 +1145,7 @@ func (c *Client) UpdatePrefRioServiceWithVersion(ctx context.Context, id string,
 
 	// Valida que temos informações do usuário
 	if userName == "" || userCPF == "" {
-		log.Printf("ERRO: Tentativa de atualizar serviço sem informações do usuário! userName='%s' userCPF='%s'", userName, userCPF)
+		log.Printf("ERRO: Tentativa de atualizar serviço sem informações do usuário! userName='%s' userCPF='%s'", userName, pii.Scrub(userCPF))
 		return nil, fmt.Errorf("informações do usuário não fornecidas - userName ou userCPF vazios")
 	}
 
@@ -1307,114 +1220,20 @@ func (c *Client) DeletePrefRioServiceWithVersion(ctx context.Context, id string,
 	return nil
 }
 
-// ListServiceVersions lista todas as versões de um serviço
-// Se o serviço não tiver histórico de versões (serviços criados antes do sistema de versionamento),
-// cria automaticamente a versão 1 a partir do estado atual
+// ListServiceVersions lista todas as versões de um serviço. Serviços sem histórico recebem a
+// versão 1 pela reconciliação (ReconcileVersions).
 func (c *Client) ListServiceVersions(ctx context.Context, serviceID string, page, perPage int) (*models.VersionHistory, error) {
-	log.Printf("[ListServiceVersions] Iniciando para serviceID=%s, page=%d, perPage=%d", serviceID, page, perPage)
-
-	history, err := c.versionService.ListVersions(ctx, serviceID, page, perPage)
-
-	log.Printf("[ListServiceVersions] ListVersions retornou: err=%v, history.Found=%d (se history != nil)",
-		err, func() int {
-			if history != nil {
-				return history.Found
-			}
-			return -1
-		}())
-
-	// Se houve erro OU se não há versões registradas, tenta criar a versão 1 automaticamente (lazy migration)
-	shouldCreateInitialVersion := (err != nil) || (history != nil && history.Found == 0)
-
-	log.Printf("[ListServiceVersions] shouldCreateInitialVersion=%v", shouldCreateInitialVersion)
-
-	if shouldCreateInitialVersion {
-		log.Printf("[ListServiceVersions] Tentando criar versão inicial para serviceID=%s", serviceID)
-
-		// Busca o serviço atual
-		service, getErr := c.GetPrefRioService(ctx, serviceID)
-		if getErr != nil {
-			log.Printf("[ListServiceVersions] Erro ao buscar serviço: %v", getErr)
-			// Se o serviço não existe, retorna o erro original (se houver) ou erro de serviço não encontrado
-			if err != nil {
-				return nil, err
-			}
-			return nil, fmt.Errorf("serviço não encontrado: %v", getErr)
-		}
-
-		log.Printf("[ListServiceVersions] Serviço encontrado: ID=%s, NomeServico=%s, Autor=%s",
-			service.ID, service.NomeServico, service.Autor)
-
-		// Cria a versão 1 inicial
-		initialVersion, createErr := c.versionService.CaptureVersion(
-			ctx,
-			service,
-			"create",
-			service.Autor,
-			"", // CPF não disponível para serviços legados
-			"Versão inicial (criada automaticamente para serviço pré-existente)",
-			nil, // Sem versão anterior
-		)
-		if createErr != nil {
-			log.Printf("[ListServiceVersions] ERRO ao criar versão inicial para serviço %s: %v", serviceID, createErr)
-			// Retorna histórico vazio ao invés de erro
-			return &models.VersionHistory{
-				Found:    0,
-				OutOf:    0,
-				Page:     page,
-				Versions: []models.ServiceVersion{},
-			}, nil
-		}
-
-		log.Printf("[ListServiceVersions] Versão inicial criada com sucesso: ID=%s, VersionNumber=%d",
-			initialVersion.ID, initialVersion.VersionNumber)
-
-		// Retorna a versão recém-criada como histórico
-		return &models.VersionHistory{
-			Found:    1,
-			OutOf:    1,
-			Page:     1,
-			Versions: []models.ServiceVersion{*initialVersion},
-		}, nil
-	}
+	return c.versionService.ListVersions(ctx, serviceID, page, perPage)
+}
 
-	log.Printf("[ListServiceVersions] Retornando histórico existente com %d versões", history.Found)
-	return history, nil
+// ListConfirmedServiceVersions lista as versões de um serviço sem as pendentes
+func (c *Client) ListConfirmedServiceVersions(ctx context.Context, serviceID string, page, perPage int) (*models.VersionHistory, error) {
+	return c.versionService.ListConfirmedVersions(ctx, serviceID, page, perPage)
 }
 
 // GetServiceVersionByNumber busca uma versão específica de um serviço
-// Se for versão 1 e não existir, tenta criar automaticamente para serviços legados
 func (c *Client) GetServiceVersionByNumber(ctx context.Context, serviceID string, versionNumber int64) (*models.ServiceVersion, error) {
-	version, err := c.versionService.GetVersionByNumber(ctx, serviceID, versionNumber)
-
-	// Se não encontrou e é versão 1, tenta criar automaticamente (lazy migration)
-	if err != nil && versionNumber == 1 && strings.Contains(err.Error(), "não encontrada") {
-		// Busca o serviço atual
-		service, getErr := c.GetPrefRioService(ctx, serviceID)
-		if getErr != nil {
-			// Se o serviço não existe, retorna erro original
-			return nil, err
-		}
-
-		// Cria a versão 1 inicial
-		initialVersion, createErr := c.versionService.CaptureVersion(
-			ctx,
-			service,
-			"create",
-			service.Autor,
-			"", // CPF não disponível para serviços legados
-			"Versão inicial (criada automaticamente para serviço pré-existente)",
-			nil, // Sem versão anterior
-		)
-		if createErr != nil {
-			log.Printf("Aviso: não foi possível criar versão inicial para serviço %s: %v", serviceID, createErr)
-			return nil, err // Retorna erro original
-		}
-
-		return initialVersion, nil
-	}
-
-	return version, err
+	return c.versionService.GetVersionByNumber(ctx, serviceID, versionNumber)
 }
 
 // GetLatestServiceVersion busca a última versão de um serviço
@@ -1422,18 +1241,35 @@ func (c *Client) GetLatestServiceVersion(ctx context.Context, serviceID string)
 	return c.versionService.GetLatestVersion(ctx, serviceID)
 }
 
+// GetLatestConfirmedServiceVersion busca a última versão confirmada de um serviço
+func (c *Client) GetLatestConfirmedServiceVersion(ctx context.Context, serviceID string) (*models.ServiceVersion, error) {
+	return c.versionService.GetLatestConfirmedVersion(ctx, serviceID)
+}
+
+// GetServiceVersionAsOf busca a versão do serviço vigente no instante asOf e o fim da sua vigência
+func (c *Client) GetServiceVersionAsOf(ctx context.Context, serviceID string, asOf int64) (*models.ServiceVersion, int64, error) {
+	return c.versionService.GetVersionAsOf(ctx, serviceID, asOf)
+}
+
 // CompareServiceVersions compara duas versões de um serviço
 func (c *Client) CompareServiceVersions(ctx context.Context, serviceID string, fromVersion, toVersion int64) (*models.VersionDiff, error) {
 	return c.versionService.CompareVersions(ctx, serviceID, fromVersion, toVersion)
 }
 
-// GetPrefRioService busca um serviço específico por ID
+// ErrServiceNotFound indica que o serviço não existe ou pertence a outro tenant
+var ErrServiceNotFound = errors.New("serviço não encontrado")
+
+// GetPrefRioService busca um serviço específico por ID. Retorna ErrServiceNotFound quando o
+// serviço não existe; as demais falhas (rede, 5xx) são devolvidas como estão.
 func (c *Client) GetPrefRioService(ctx context.Context, id string) (*models.PrefRioService, error) {
 	collectionName := "prefrio_services_base"
 
 	result, err := c.client.Collection(collectionName).Document(id).Retrieve(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("serviço não encontrado: %v", err)
+		if services.IsNotFoundError(err) {
+			return nil, fmt.Errorf("%w: %v", ErrServiceNotFound, err)
+		}
+		return nil, fmt.Errorf("erro ao buscar serviço: %w", err)
 	}
 
 	// Converte o resultado para o struct
@@ -1447,6 +1283,10 @@ func (c *Client) GetPrefRioService(ctx context.Context, id string) (*models.Pref
 		return nil, fmt.Errorf("erro ao deserializar resultado: %v", err)
 	}
 
+	if !tenant.Allows(ctx, service.Tenant) {
+		return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, id)
+	}
+
 	return &service, nil
 }
 
@@ -1454,7 +1294,7 @@ func (c *Client) GetPrefRioService(ctx context.Context, id string) (*models.Pref
 func (c *Client) GetPrefRioServiceBySlug(ctx context.Context, slug string) (*models.PrefRioService, error) {
 	collectionName := "prefrio_services_base"
 
-	filterBy := fmt.Sprintf("slug:=%s", slug)
+	filterBy := tenant.ScopeFilter(ctx, fmt.Sprintf("slug:=%s", slug))
 	searchParams := &api.SearchCollectionParams{
 		Q:             stringPtr("*"),
 		FilterBy:      &filterBy,
@@ -1515,7 +1355,7 @@ func (c *Client) GetPrefRioServiceBySlug(ctx context.Context, slug string) (*mod
 func (c *Client) GetPrefRioServiceByHistoricalSlug(ctx context.Context, slug string) (*models.PrefRioService, error) {
 	collectionName := "prefrio_services_base"
 
-	filterBy := fmt.Sprintf("slug_history:=%s", slug)
+	filterBy := tenant.ScopeFilter(ctx, fmt.Sprintf("slug_history:=%s", slug))
 	searchParams := &api.SearchCollectionParams{
 		Q:             stringPtr("*"),
 		FilterBy:      &filterBy,
@@ -1574,6 +1414,12 @@ func (c *Client) GetPrefRioServiceByHistoricalSlug(ctx context.Context, slug str
 
 // ListPrefRioServices lista serviços com paginação e filtros
 func (c *Client) ListPrefRioServices(ctx context.Context, page, perPage int, filters map[string]interface{}) (*models.PrefRioServiceResponse, error) {
+	return c.listPrefRioServices(ctx, page, perPage, filters, nil, "")
+}
+
+// listPrefRioServices lista os serviços; com after, continua a listagem a partir do cursor e,
+// com query, busca com a configuração da busca do admin (ver applyAdminSearch)
+func (c *Client) listPrefRioServices(ctx context.Context, page, perPage int, filters map[string]interface{}, after *serviceListCursor, query string) (*models.PrefRioServiceResponse, error) {
 	collectionName := "prefrio_services_base"
 
 	// Extrai nome_servico para busca textual
@@ -1610,6 +1456,13 @@ func (c *Client) ListPrefRioServices(ctx context.Context, page, perPage int, fil
 			filterBy = strings.Join(filterParts, " && ")
 		}
 	}
+	if after != nil {
+		if filterBy != "" {
+			filterBy += " && "
+		}
+		filterBy += after.filter()
+	}
+	filterBy = tenant.ScopeFilter(ctx, filterBy)
 
 	// Parâmetros de busca
 	searchParams := &api.SearchCollectionParams{
@@ -1629,6 +1482,10 @@ func (c *Client) ListPrefRioServices(ctx context.Context, page, perPage int, fil
 		searchParams.Q = stringPtr("*")
 	}
 
+	if query != "" {
+		applyAdminSearch(searchParams, query)
+	}
+
 	if filterBy != "" {
 		searchParams.FilterBy = &filterBy
 	}
@@ -1678,24 +1535,52 @@ func (c *Client) ListPrefRioServices(ctx context.Context, page, perPage int, fil
 		Found:    found,
 		OutOf:    outOf,
 		Page:     page,
+		PerPage:  perPage,
 		Services: services,
+		PageInfo: models.NewPageInfo(page, perPage, found),
+	}
+	// O cursor segue a ordem por last_update; a busca do admin é ordenada por relevância
+	if response.HasNext && len(services) > 0 && query == "" {
+		response.NextCursor = nextServiceListCursor(services, after).encode()
 	}
 
 	return response, nil
 }
 
-// generateSearchContent gera o conteúdo de busca combinando campos relevantes
-// wrapServiceURLs aplica o gateway wrapper em todas as URLs do serviço
-func (c *Client) wrapServiceURLs(service *models.PrefRioService) {
+// prepareServiceDocument aplica ao serviço as transformações feitas antes de indexar: remove HTML
+// e links executáveis dos textos, aplica o gateway nas URLs e gera os campos derivados (deep
+// links, search_content, nome fonético, restrição por bairro e plaintext)
+func (c *Client) prepareServiceDocument(ctx context.Context, service *models.PrefRioService) {
+	services.SanitizeService(service)
+	c.wrapServiceURLs(ctx, service)
+
+	// Gera os deep links por canal a partir do slug
+	service.DeepLinks = c.deepLinks.Build(service)
+
+	// Gera o search_content combinando campos relevantes
+	service.SearchContent = c.generateSearchContent(service)
+	service.NomeServicoFonetico = utils.PhoneticKey(service.NomeServico)
+	service.RestritoBairros = len(service.Bairros) > 0
+	service.SetPlaintextFields()
+}
+
+// wrapServiceURLs aplica o gateway wrapper (do tenant da requisição) em todas as URLs do serviço
+func (c *Client) wrapServiceURLs(ctx context.Context, service *models.PrefRioService) {
+	gatewayBaseURL := c.gatewayBaseURL
+	if tenantID, ok := tenant.FromContext(ctx); ok {
+		gatewayBaseURL = c.cfg.GatewayBaseURLFor(tenantID)
+	}
+
 	// Wrap URLs in buttons
 	for i := range service.Buttons {
-		service.Buttons[i].URLService = utils.WrapURLIfNeeded(service.Buttons[i].URLService, c.gatewayBaseURL)
+		service.Buttons[i].URLService = utils.WrapURLIfNeeded(service.Buttons[i].URLService, gatewayBaseURL)
 	}
 
 	// Wrap URLs in CanaisDigitais
-	service.CanaisDigitais = utils.WrapURLsInArray(service.CanaisDigitais, c.gatewayBaseURL)
+	service.CanaisDigitais = utils.WrapURLsInArray(service.CanaisDigitais, gatewayBaseURL)
 }
 
+// generateSearchContent gera o conteúdo de busca combinando campos relevantes
 func (c *Client) generateSearchContent(service *models.PrefRioService) string {
 	var content []string
 
@@ -1721,7 +1606,21 @@ func (c *Client) generateSearchContent(service *models.PrefRioService) string {
 	// Adiciona documentos necessários
 	content = append(content, service.DocumentosNecessarios...)
 
-	return strings.Join(content, " ")
+	// Adiciona nomes e texto extraído dos anexos
+	for _, attachment := range service.Attachments {
+		content = append(content, attachment.Filename)
+		if attachment.ExtractedText != "" {
+			content = append(content, attachment.ExtractedText)
+		}
+	}
+
+	// Formas canônicas de referências legais, protocolos e termos com hífen, para que a
+	// busca encontre o serviço independentemente do separador usado
+	joined := strings.Join(content, " ")
+	if tokens := utils.IndexTokens(joined); tokens != "" {
+		joined += " " + tokens
+	}
+	return joined
 }
 
 // structToMap converte um struct para map[string]interface{}
@@ -1752,7 +1651,18 @@ func (c *Client) createTombamentosCollection() error {
 	ctx := context.Background()
 	collectionName := "tombamentos_overlay"
 
-	schema := &api.CollectionSchema{
+	_, err := c.client.Collections().Create(ctx, tombamentosSchema(collectionName))
+	if err != nil {
+		return fmt.Errorf("erro ao criar collection %s: %v", collectionName, err)
+	}
+
+	log.Printf("Collection %s criada com sucesso", collectionName)
+	return nil
+}
+
+// tombamentosSchema schema da collection tombamentos_overlay
+func tombamentosSchema(collectionName string) *api.CollectionSchema {
+	return &api.CollectionSchema{
 		Name: collectionName,
 		Fields: []api.Field{
 			{Name: "id", Type: "string", Optional: boolPtr(true)},
@@ -1762,17 +1672,10 @@ func (c *Client) createTombamentosCollection() error {
 			{Name: "criado_em", Type: "int64", Facet: boolPtr(false)},
 			{Name: "criado_por", Type: "string", Facet: boolPtr(true)},
 			{Name: "observacoes", Type: "string", Facet: boolPtr(false), Optional: boolPtr(true)},
+			{Name: "tenant", Type: "string", Facet: boolPtr(true), Optional: boolPtr(true)},
 		},
 		DefaultSortingField: stringPtr("criado_em"),
 	}
-
-	_, err := c.client.Collections().Create(ctx, schema)
-	if err != nil {
-		return fmt.Errorf("erro ao criar collection %s: %v", collectionName, err)
-	}
-
-	log.Printf("Collection %s criada com sucesso", collectionName)
-	return nil
 }
 
 // EnsureTombamentosCollectionExists verifica se a collection tombamentos_overlay existe e a cria se necessário
@@ -1804,8 +1707,11 @@ func (c *Client) CreateTombamento(ctx context.Context, tombamento *models.Tombam
 		return nil, fmt.Errorf("erro ao verificar/criar collection: %v", err)
 	}
 
-	// Define timestamp
+	// Define timestamp e tenant
 	tombamento.CriadoEm = time.Now().Unix()
+	if tombamento.Tenant == "" {
+		tombamento.Tenant = tenant.OwnerID(ctx)
+	}
 
 	// Converte para map[string]interface{} para inserção
 	tombamentoMap, err := c.structToMap(tombamento)
@@ -1863,6 +1769,10 @@ func (c *Client) GetTombamento(ctx context.Context, id string) (*models.Tombamen
 		return nil, fmt.Errorf("erro ao deserializar resultado: %v", err)
 	}
 
+	if !tenant.Allows(ctx, tombamento.Tenant) {
+		return nil, fmt.Errorf("tombamento não encontrado: %s", id)
+	}
+
 	return &tombamento, nil
 }
 
@@ -1870,14 +1780,17 @@ func (c *Client) GetTombamento(ctx context.Context, id string) (*models.Tombamen
 func (c *Client) UpdateTombamento(ctx context.Context, id string, tombamento *models.Tombamento) (*models.Tombamento, error) {
 	collectionName := "tombamentos_overlay"
 
-	// Verifica se o documento existe
-	_, err := c.client.Collection(collectionName).Document(id).Retrieve(ctx)
+	// Verifica se o documento existe e pertence ao tenant da requisição
+	existing, err := c.GetTombamento(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("tombamento não encontrado: %v", err)
+		return nil, err
 	}
 
 	// Define o ID
 	tombamento.ID = id
+	if tombamento.Tenant == "" {
+		tombamento.Tenant = existing.Tenant
+	}
 
 	// Converte para map[string]interface{} para atualização
 	tombamentoMap, err := c.structToMap(tombamento)
@@ -1909,14 +1822,13 @@ func (c *Client) UpdateTombamento(ctx context.Context, id string, tombamento *mo
 func (c *Client) DeleteTombamento(ctx context.Context, id string) error {
 	collectionName := "tombamentos_overlay"
 
-	// Verifica se o documento existe
-	_, err := c.client.Collection(collectionName).Document(id).Retrieve(ctx)
-	if err != nil {
-		return fmt.Errorf("tombamento não encontrado: %v", err)
+	// Verifica se o documento existe e pertence ao tenant da requisição
+	if _, err := c.GetTombamento(ctx, id); err != nil {
+		return err
 	}
 
 	// Deleta o documento
-	_, err = c.client.Collection(collectionName).Document(id).Delete(ctx)
+	_, err := c.client.Collection(collectionName).Document(id).Delete(ctx)
 	if err != nil {
 		return fmt.Errorf("erro ao deletar tombamento: %v", err)
 	}
@@ -1951,6 +1863,7 @@ func (c *Client) ListTombamentos(ctx context.Context, page, perPage int, filters
 			filterBy = strings.Join(filterParts, " && ")
 		}
 	}
+	filterBy = tenant.ScopeFilter(ctx, filterBy)
 
 	// Parâmetros de busca
 	searchParams := &api.SearchCollectionParams{
@@ -2009,7 +1922,9 @@ func (c *Client) ListTombamentos(ctx context.Context, page, perPage int, filters
 		Found:       found,
 		OutOf:       outOf,
 		Page:        page,
+		PerPage:     perPage,
 		Tombamentos: tombamentos,
+		PageInfo:    models.NewPageInfo(page, perPage, found),
 	}
 
 	return response, nil
@@ -2342,7 +2257,9 @@ func (c *Client) ListMigrationHistory(ctx context.Context, page, perPage int) (*
 		Found:      found,
 		OutOf:      outOf,
 		Page:       page,
+		PerPage:    perPage,
 		Migrations: migrations,
+		PageInfo:   models.NewPageInfo(page, perPage, found),
 	}, nil
 }
 
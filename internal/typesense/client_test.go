@@ -0,0 +1,43 @@
+package typesense
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCalcularJanelasCategoria(t *testing.T) {
+	totais := []int{3, 0, 5}
+
+	tests := []struct {
+		name     string
+		inicio   int
+		tamanho  int
+		esperado []categoriaJanela
+	}{
+		{"primeira página dentro da primeira coleção", 0, 2, []categoriaJanela{{indice: 0, offset: 0, limit: 2}}},
+		{"página atravessando coleções", 2, 3, []categoriaJanela{{indice: 0, offset: 2, limit: 1}, {indice: 2, offset: 0, limit: 2}}},
+		{"última página parcial", 6, 5, []categoriaJanela{{indice: 2, offset: 3, limit: 2}}},
+		{"além do total", 10, 5, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			janelas := calcularJanelasCategoria(totais, tt.inicio, tt.tamanho)
+			if !reflect.DeepEqual(janelas, tt.esperado) {
+				t.Errorf("esperado %+v, obtido %+v", tt.esperado, janelas)
+			}
+		})
+	}
+}
+
+func TestFiltroCategoria(t *testing.T) {
+	filtro := filtroCategoria("prefrio_services_base", "Saúde", nil)
+	if filtro != "category:=`Saúde` && status:=1" {
+		t.Errorf("filtro inesperado: %s", filtro)
+	}
+
+	filtro = filtroCategoria("1746_v2_llm", "Saúde", []string{"a", "b"})
+	if filtro != "category:=`Saúde` && id:!=[a,b]" {
+		t.Errorf("filtro inesperado: %s", filtro)
+	}
+}
@@ -0,0 +1,263 @@
+package typesense
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+// ErrInvalidContentReplace indica expressão regular ou campo inválido na substituição
+var ErrInvalidContentReplace = errors.New("substituição inválida")
+
+// Tamanho do contexto exibido em volta da ocorrência na pré-visualização
+const replaceExcerptContext = 40
+
+// replaceableStringFields campos de texto simples em que a substituição pode ser aplicada
+var replaceableStringFields = map[string]func(*models.PrefRioService) *string{
+	"nome_servico":           func(s *models.PrefRioService) *string { return &s.NomeServico },
+	"resumo":                 func(s *models.PrefRioService) *string { return &s.Resumo },
+	"tempo_atendimento":      func(s *models.PrefRioService) *string { return &s.TempoAtendimento },
+	"custo_servico":          func(s *models.PrefRioService) *string { return &s.CustoServico },
+	"resultado_solicitacao":  func(s *models.PrefRioService) *string { return &s.ResultadoSolicitacao },
+	"descricao_completa":     func(s *models.PrefRioService) *string { return &s.DescricaoCompleta },
+	"instrucoes_solicitante": func(s *models.PrefRioService) *string { return &s.InstrucoesSolicitante },
+	"servico_nao_cobre":      func(s *models.PrefRioService) *string { return &s.ServicoNaoCobre },
+}
+
+// replaceableListFields campos de lista em que a substituição é aplicada item a item
+var replaceableListFields = map[string]func(*models.PrefRioService) []string{
+	"documentos_necessarios": func(s *models.PrefRioService) []string { return s.DocumentosNecessarios },
+	"canais_digitais":        func(s *models.PrefRioService) []string { return s.CanaisDigitais },
+	"canais_presenciais":     func(s *models.PrefRioService) []string { return s.CanaisPresenciais },
+	"legislacao_relacionada": func(s *models.PrefRioService) []string { return s.LegislacaoRelacionada },
+}
+
+// ReplaceableFields retorna os campos aceitos pela substituição (inclui "buttons": título,
+// descrição e URL dos botões)
+func ReplaceableFields() []string {
+	fields := []string{"buttons"}
+	for field := range replaceableStringFields {
+		fields = append(fields, field)
+	}
+	for field := range replaceableListFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// ReplaceContent localiza e (se req.Apply) substitui o texto nos campos e serviços
+// selecionados. Apenas os serviços afetados são gravados, gerando nova versão e novo embedding.
+func (c *Client) ReplaceContent(ctx context.Context, req *models.ContentReplaceRequest, userName, userCPF string) (*models.ContentReplaceResult, error) {
+	pattern, err := compileReplacePattern(req)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := req.Fields
+	if len(fields) == 0 {
+		fields = ReplaceableFields()
+	}
+	for _, field := range fields {
+		if !isReplaceableField(field) {
+			return nil, fmt.Errorf("%w: campo não suportado: %s", ErrInvalidContentReplace, field)
+		}
+	}
+
+	// Carrega todos os candidatos antes de gravar: as atualizações mudam a ordenação por last_update
+	candidates, err := c.loadReplaceCandidates(ctx, req.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ContentReplaceResult{
+		Applied: req.Apply,
+		Fields:  fields,
+		Scanned: len(candidates),
+		Items:   []models.ContentReplaceItem{},
+	}
+
+	reason := "Substituição em lote de conteúdo"
+	if req.Reason != "" {
+		reason += ": " + req.Reason
+	}
+
+	for i := range candidates {
+		service := &candidates[i]
+		matches := replaceInService(service, fields, pattern, req.Replace)
+		if len(matches) == 0 {
+			continue
+		}
+
+		item := models.ContentReplaceItem{
+			ServiceID:   service.ID,
+			NomeServico: service.NomeServico,
+			Matches:     matches,
+			Status:      models.BulkItemWouldApply,
+		}
+		for _, match := range matches {
+			result.Occurrences += match.Occurrences
+		}
+
+		if req.Apply {
+			if _, err := c.UpdatePrefRioServiceWithVersion(ctx, service.ID, service, userName, userCPF, reason); err != nil {
+				item.Status, item.Message = models.BulkItemFailed, fmt.Sprintf("Erro ao salvar: %v", err)
+				result.Failed++
+			} else {
+				item.Status = models.BulkItemApplied
+			}
+		}
+
+		result.Affected++
+		result.Items = append(result.Items, item)
+	}
+
+	return result, nil
+}
+
+// loadReplaceCandidates busca os serviços que atendem aos filtros
+func (c *Client) loadReplaceCandidates(ctx context.Context, filters models.ContentReplaceFilters) ([]models.PrefRioService, error) {
+	if len(filters.IDs) > 0 {
+		services := make([]models.PrefRioService, 0, len(filters.IDs))
+		for _, id := range filters.IDs {
+			service, err := c.GetPrefRioService(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("%w: serviço não encontrado: %s", ErrInvalidContentReplace, id)
+			}
+			services = append(services, *service)
+		}
+		return services, nil
+	}
+
+	listFilters := map[string]interface{}{}
+	if filters.Status != nil {
+		listFilters["status"] = *filters.Status
+	}
+	if filters.TemaGeral != "" {
+		listFilters["tema_geral"] = filters.TemaGeral
+	}
+	if filters.SubCategoria != "" {
+		listFilters["sub_categoria"] = filters.SubCategoria
+	}
+	if filters.Autor != "" {
+		listFilters["autor"] = filters.Autor
+	}
+
+	const perPage = 100
+	services := []models.PrefRioService{}
+	for page := 1; ; page++ {
+		resp, err := c.ListPrefRioServices(ctx, page, perPage, listFilters)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar serviços (página %d): %v", page, err)
+		}
+		services = append(services, resp.Services...)
+		if len(resp.Services) < perPage {
+			break
+		}
+	}
+
+	return services, nil
+}
+
+// compileReplacePattern converte find em expressão regular (escapando o texto literal)
+func compileReplacePattern(req *models.ContentReplaceRequest) (*regexp.Regexp, error) {
+	if req.Find == "" {
+		return nil, fmt.Errorf("%w: find é obrigatório", ErrInvalidContentReplace)
+	}
+
+	expr := req.Find
+	if !req.Regex {
+		expr = regexp.QuoteMeta(expr)
+	}
+	if req.IgnoreCase {
+		expr = "(?i)" + expr
+	}
+
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: expressão regular: %v", ErrInvalidContentReplace, err)
+	}
+	return pattern, nil
+}
+
+// replaceInService aplica a substituição nos campos do serviço e retorna as ocorrências
+func replaceInService(service *models.PrefRioService, fields []string, pattern *regexp.Regexp, replacement string) []models.ContentReplaceMatch {
+	var matches []models.ContentReplaceMatch
+
+	apply := func(field string, value *string) {
+		if match, ok := replaceValue(field, value, pattern, replacement); ok {
+			matches = append(matches, match)
+		}
+	}
+
+	for _, field := range fields {
+		if accessor, ok := replaceableStringFields[field]; ok {
+			apply(field, accessor(service))
+			continue
+		}
+		if accessor, ok := replaceableListFields[field]; ok {
+			items := accessor(service)
+			for i := range items {
+				apply(fmt.Sprintf("%s[%d]", field, i), &items[i])
+			}
+			continue
+		}
+		if field == "buttons" {
+			for i := range service.Buttons {
+				button := &service.Buttons[i]
+				apply(fmt.Sprintf("buttons[%d].titulo", i), &button.Titulo)
+				apply(fmt.Sprintf("buttons[%d].descricao", i), &button.Descricao)
+				apply(fmt.Sprintf("buttons[%d].url_service", i), &button.URLService)
+			}
+		}
+	}
+
+	return matches
+}
+
+// replaceValue substitui no valor e retorna o trecho da primeira ocorrência antes e depois
+func replaceValue(field string, value *string, pattern *regexp.Regexp, replacement string) (models.ContentReplaceMatch, bool) {
+	locations := pattern.FindAllStringIndex(*value, -1)
+	if len(locations) == 0 {
+		return models.ContentReplaceMatch{}, false
+	}
+
+	replaced := pattern.ReplaceAllString(*value, replacement)
+	if replaced == *value {
+		return models.ContentReplaceMatch{}, false
+	}
+
+	// Ajusta o trecho para não cortar caracteres multibyte
+	start := max(locations[0][0]-replaceExcerptContext, 0)
+	for start > 0 && !utf8.RuneStart((*value)[start]) {
+		start--
+	}
+	end := min(locations[0][1]+replaceExcerptContext, len(*value))
+	for end < len(*value) && !utf8.RuneStart((*value)[end]) {
+		end++
+	}
+	excerpt := (*value)[start:end]
+
+	*value = replaced
+
+	return models.ContentReplaceMatch{
+		Field:       field,
+		Occurrences: len(locations),
+		Before:      excerpt,
+		After:       pattern.ReplaceAllString(excerpt, replacement),
+	}, true
+}
+
+func isReplaceableField(field string) bool {
+	if field == "buttons" {
+		return true
+	}
+	_, isString := replaceableStringFields[field]
+	_, isList := replaceableListFields[field]
+	return isString || isList
+}
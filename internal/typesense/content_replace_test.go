@@ -0,0 +1,58 @@
+package typesense
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestReplaceInServiceLiteral(t *testing.T) {
+	service := &models.PrefRioService{
+		Resumo:         "Ligue (21) 2222-3333 ou (21) 2222-3333.",
+		CanaisDigitais: []string{"https://antigo.rio/servico", "https://outro.rio"},
+		Buttons:        []models.Button{{Titulo: "Acessar", URLService: "https://antigo.rio/form"}},
+	}
+
+	pattern, err := compileReplacePattern(&models.ContentReplaceRequest{Find: "(21) 2222-3333"})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	matches := replaceInService(service, []string{"resumo", "custo_servico"}, pattern, "1746")
+	if len(matches) != 1 || matches[0].Occurrences != 2 || service.Resumo != "Ligue 1746 ou 1746." {
+		t.Fatalf("substituição literal inesperada: %+v (%q)", matches, service.Resumo)
+	}
+
+	pattern, _ = compileReplacePattern(&models.ContentReplaceRequest{Find: `https://antigo\.rio/(\w+)`, Regex: true})
+	matches = replaceInService(service, []string{"canais_digitais", "buttons"}, pattern, "https://novo.rio/$1")
+	if len(matches) != 2 || matches[0].Field != "canais_digitais[0]" || matches[1].Field != "buttons[0].url_service" {
+		t.Fatalf("ocorrências inesperadas: %+v", matches)
+	}
+	if service.CanaisDigitais[0] != "https://novo.rio/servico" || service.Buttons[0].URLService != "https://novo.rio/form" {
+		t.Errorf("substituição por regex inesperada: %v %v", service.CanaisDigitais, service.Buttons)
+	}
+}
+
+func TestReplaceValueExcerpt(t *testing.T) {
+	value := "Atendimento na Secretaria de Saúde de segunda a sexta, telefone antigo 2222-3333, com agendamento prévio."
+	pattern, _ := compileReplacePattern(&models.ContentReplaceRequest{Find: "TELEFONE ANTIGO", IgnoreCase: true})
+
+	match, ok := replaceValue("resumo", &value, pattern, "telefone")
+	if !ok || match.After == match.Before || len(match.Before) > len("telefone antigo")+2*replaceExcerptContext {
+		t.Fatalf("trecho inesperado: %+v", match)
+	}
+
+	// Substituição que não altera o texto não conta como ocorrência
+	unchanged := "telefone antigo"
+	if _, ok := replaceValue("resumo", &unchanged, pattern, "telefone antigo"); ok {
+		t.Error("substituição idêntica não deveria afetar o serviço")
+	}
+}
+
+func TestCompileReplacePatternInvalid(t *testing.T) {
+	for _, req := range []models.ContentReplaceRequest{{Find: ""}, {Find: "(", Regex: true}} {
+		if _, err := compileReplacePattern(&req); !errors.Is(err, ErrInvalidContentReplace) {
+			t.Errorf("esperado ErrInvalidContentReplace para %+v, obtido %v", req, err)
+		}
+	}
+}
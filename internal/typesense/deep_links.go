@@ -0,0 +1,55 @@
+package typesense
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+// RefreshDeepLinks regenera os deep links de todos os serviços com a configuração atual de canais
+// (ex.: após mudar um template ou parâmetro UTM). Como os links são derivados, a atualização é
+// parcial e não gera nova versão nem altera last_update.
+func (c *Client) RefreshDeepLinks(ctx context.Context) (*models.DeepLinkBackfillResult, error) {
+	collectionName := "prefrio_services_base"
+	result := &models.DeepLinkBackfillResult{Channels: c.deepLinks.Channels()}
+
+	// Carrega todos os serviços antes de atualizar, para não deslocar a paginação
+	const perPage = 100
+	var candidates []models.PrefRioService
+	for page := 1; ; page++ {
+		resp, err := c.ListPrefRioServices(ctx, page, perPage, map[string]interface{}{})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar serviços (página %d): %v", page, err)
+		}
+		candidates = append(candidates, resp.Services...)
+		if len(resp.Services) < perPage {
+			break
+		}
+	}
+
+	for i := range candidates {
+		service := &candidates[i]
+		result.Scanned++
+
+		links := c.deepLinks.Build(service)
+		if maps.Equal(links, service.DeepLinks) {
+			continue
+		}
+
+		update := map[string]interface{}{"deep_links": links}
+		if links == nil {
+			update["deep_links"] = map[string]string{}
+		}
+		if _, err := c.client.Collection(collectionName).Document(service.ID).Update(ctx, update, &api.DocumentIndexParameters{}); err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", service.ID, err))
+			continue
+		}
+		result.Updated++
+	}
+
+	return result, nil
+}
@@ -0,0 +1,246 @@
+package typesense
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/concurrency"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+// AuditEmbeddings sorteia documentos, regenera o embedding do search_content atual e compara
+// com o vetor armazenado, reportando os documentos editados sem reindexação do vetor. Com Fix,
+// os vetores reportados são regravados (atualização parcial, sem nova versão); com Force, todos
+// os vetores sorteados são regravados.
+func (c *Client) AuditEmbeddings(ctx context.Context, opts models.EmbeddingAuditOptions) (*models.EmbeddingAuditReport, error) {
+	if c.geminiClient == nil {
+		return nil, errors.New("cliente Gemini indisponível: configure GEMINI_API_KEY")
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	if opts.Force {
+		opts.Fix = true
+	}
+
+	start := time.Now()
+	ids, err := c.listServiceIDs(ctx, opts.FilterBy)
+	if err != nil {
+		return nil, err
+	}
+	sample := sampleIDs(ids, opts.SampleSize, opts.Seed)
+
+	report := &models.EmbeddingAuditReport{
+		CheckedAt:      start.Unix(),
+		Model:          c.embeddingModel,
+		Threshold:      opts.Threshold,
+		Filter:         opts.FilterBy,
+		TotalDocuments: len(ids),
+		Sampled:        len(sample),
+		Drifted:        []models.EmbeddingDrift{},
+		FixApplied:     opts.Fix,
+	}
+
+	var (
+		mu        sync.Mutex
+		distances float64
+		compared  int
+	)
+	err = concurrency.Run(ctx, sample, concurrency.Options{Concurrency: opts.Concurrency}, func(ctx context.Context, id string) error {
+		drift, distance, err := c.auditServiceEmbedding(ctx, id, opts)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", id, err))
+			return nil
+		}
+		report.Checked++
+		if distance >= 0 {
+			compared++
+			distances += distance
+			report.MaxDistance = math.Max(report.MaxDistance, distance)
+		}
+		if drift != nil {
+			report.Drifted = append(report.Drifted, *drift)
+			if drift.Fixed {
+				report.TotalFixed++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("auditoria interrompida: %v", err))
+	}
+
+	if compared > 0 {
+		report.MeanDistance = distances / float64(compared)
+	}
+	sort.Slice(report.Drifted, func(i, j int) bool { return report.Drifted[i].Distance > report.Drifted[j].Distance })
+	sort.Strings(report.Errors)
+	report.DurationMs = time.Since(start).Milliseconds()
+	return report, nil
+}
+
+// auditServiceEmbedding compara o vetor do documento com o regenerado. distance é -1 quando
+// não há vetor comparável; drift é nil quando o vetor está em dia (exceto com Force).
+func (c *Client) auditServiceEmbedding(ctx context.Context, id string, opts models.EmbeddingAuditOptions) (*models.EmbeddingDrift, float64, error) {
+	doc, err := c.client.Collection("prefrio_services_base").Document(id).Retrieve(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao buscar documento: %v", err)
+	}
+
+	content, _ := doc["search_content"].(string)
+	if content == "" {
+		return nil, -1, nil
+	}
+
+	current, err := c.gerarEmbeddingServico(ctx, content)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	drift := &models.EmbeddingDrift{ID: id}
+	drift.NomeServico, _ = doc["nome_servico"].(string)
+	drift.StoredModel, _ = doc["embedding_model"].(string)
+	if lastUpdate, ok := doc["last_update"].(float64); ok {
+		drift.LastUpdate = int64(lastUpdate)
+	}
+
+	stored := parseStoredEmbedding(doc["embedding"])
+	distance := -1.0
+	switch {
+	case len(stored) == 0:
+		drift.Reason = models.EmbeddingMissing
+	case len(stored) != len(current) || (drift.StoredModel != "" && drift.StoredModel != c.embeddingModel):
+		drift.Reason = models.EmbeddingModelMismatch
+	default:
+		distance = cosineDistance(stored, current)
+		drift.Distance = distance
+		switch {
+		case distance >= opts.Threshold:
+			drift.Reason = models.EmbeddingDrifted
+		case opts.Force:
+			drift.Reason = models.EmbeddingForced
+		default:
+			return nil, distance, nil
+		}
+	}
+
+	if opts.Fix {
+		if err := c.updateServiceEmbedding(ctx, id, current); err != nil {
+			drift.FixError = err.Error()
+		} else {
+			drift.Fixed = true
+		}
+	}
+	return drift, distance, nil
+}
+
+// updateServiceEmbedding regrava o vetor do documento. Por ser dado derivado do conteúdo, a
+// atualização é parcial e não gera nova versão nem altera last_update.
+func (c *Client) updateServiceEmbedding(ctx context.Context, id string, embedding []float32) error {
+	if err := c.checkEmbeddingDim(ctx, "prefrio_services_base", "embedding", len(embedding)); err != nil {
+		return err
+	}
+
+	values := make([]float64, len(embedding))
+	for i, v := range embedding {
+		values[i] = float64(v)
+	}
+
+	update := map[string]interface{}{
+		"embedding":       values,
+		"embedding_model": c.embeddingModel,
+		"embedding_dim":   len(embedding),
+		"embedding_hash":  utils.EmbeddingHash(c.embeddingModel, values),
+	}
+	if _, err := c.client.Collection("prefrio_services_base").Document(id).Update(ctx, update, &api.DocumentIndexParameters{}); err != nil {
+		return fmt.Errorf("erro ao regravar embedding: %v", err)
+	}
+	return nil
+}
+
+// listServiceIDs lista os IDs dos serviços, todos ou apenas os que atendem ao filter_by
+func (c *Client) listServiceIDs(ctx context.Context, filterBy string) ([]string, error) {
+	var ids []string
+	for page := 1; ; page++ {
+		params := &api.SearchCollectionParams{
+			Q:             stringPtr("*"),
+			IncludeFields: stringPtr("id"),
+			Page:          intPtr(page),
+			PerPage:       intPtr(250),
+		}
+		if filterBy != "" {
+			params.FilterBy = stringPtr(filterBy)
+		}
+		result, err := c.client.Collection("prefrio_services_base").Documents().Search(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar serviços: %v", err)
+		}
+		if result.Hits == nil || len(*result.Hits) == 0 {
+			return ids, nil
+		}
+		for _, hit := range *result.Hits {
+			if hit.Document == nil {
+				continue
+			}
+			if id, ok := (*hit.Document)["id"].(string); ok {
+				ids = append(ids, id)
+			}
+		}
+		if len(*result.Hits) < 250 {
+			return ids, nil
+		}
+	}
+}
+
+// sampleIDs sorteia até size IDs (todos, se size <= 0) com a semente informada
+func sampleIDs(ids []string, size int, seed int64) []string {
+	sample := append([]string(nil), ids...)
+	if size <= 0 || size >= len(sample) {
+		return sample
+	}
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(sample), func(i, j int) { sample[i], sample[j] = sample[j], sample[i] })
+	return sample[:size]
+}
+
+func parseStoredEmbedding(raw interface{}) []float64 {
+	values, ok := raw.([]interface{})
+	if !ok {
+		return nil
+	}
+	embedding := make([]float64, 0, len(values))
+	for _, v := range values {
+		f, ok := v.(float64)
+		if !ok {
+			return nil
+		}
+		embedding = append(embedding, f)
+	}
+	return embedding
+}
+
+// cosineDistance retorna 1 - similaridade de cosseno (0 = mesma direção, 2 = opostos)
+func cosineDistance(stored []float64, current []float32) float64 {
+	var dot, normA, normB float64
+	for i := range stored {
+		b := float64(current[i])
+		dot += stored[i] * b
+		normA += stored[i] * stored[i]
+		normB += b * b
+	}
+	if normA == 0 || normB == 0 {
+		return 1
+	}
+	return 1 - dot/(math.Sqrt(normA)*math.Sqrt(normB))
+}
@@ -0,0 +1,44 @@
+package typesense
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCosineDistance(t *testing.T) {
+	if d := cosineDistance([]float64{1, 0}, []float32{2, 0}); math.Abs(d) > 1e-9 {
+		t.Errorf("vetores na mesma direção deveriam ter distância 0, obtido %f", d)
+	}
+	if d := cosineDistance([]float64{1, 0}, []float32{0, 1}); math.Abs(d-1) > 1e-9 {
+		t.Errorf("vetores ortogonais deveriam ter distância 1, obtido %f", d)
+	}
+	if d := cosineDistance([]float64{0, 0}, []float32{1, 1}); d != 1 {
+		t.Errorf("vetor nulo deveria ser tratado como distante, obtido %f", d)
+	}
+}
+
+func TestSampleIDs(t *testing.T) {
+	ids := []string{"a", "b", "c", "d", "e"}
+
+	if sample := sampleIDs(ids, 0, 1); len(sample) != 5 {
+		t.Errorf("sem limite deveria retornar todos, obtido %v", sample)
+	}
+
+	first := sampleIDs(ids, 2, 42)
+	second := sampleIDs(ids, 2, 42)
+	if len(first) != 2 || first[0] != second[0] || first[1] != second[1] {
+		t.Errorf("mesma semente deveria gerar a mesma amostra: %v %v", first, second)
+	}
+	if ids[0] != "a" || ids[4] != "e" {
+		t.Errorf("lista original não deveria ser alterada: %v", ids)
+	}
+}
+
+func TestParseStoredEmbedding(t *testing.T) {
+	if got := parseStoredEmbedding([]interface{}{0.5, -0.25}); len(got) != 2 || got[1] != -0.25 {
+		t.Errorf("vetor inesperado: %v", got)
+	}
+	if got := parseStoredEmbedding(nil); got != nil {
+		t.Errorf("documento sem vetor deveria retornar nil: %v", got)
+	}
+}
@@ -0,0 +1,111 @@
+package typesense
+
+import (
+	"context"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// embeddingChunkSize tamanho (caracteres) de cada trecho embedado; GerarEmbedding trunca em 10000
+	embeddingChunkSize = 8000
+	// maxEmbeddingChunks limita as chamadas de embedding por serviço
+	maxEmbeddingChunks = 8
+	// leadingChunkWeight peso do primeiro trecho (nome, resumo e descrição do serviço)
+	leadingChunkWeight = 0.5
+)
+
+// gerarEmbeddingServico gera o embedding do search_content. Conteúdos longos (ex: texto
+// extraído de anexos) são divididos em trechos embedados separadamente e combinados: o
+// primeiro trecho, com os campos principais do serviço, mantém metade do peso.
+func (c *Client) gerarEmbeddingServico(ctx context.Context, content string) ([]float32, error) {
+	return embedServiceContent(ctx, content, c.GerarEmbedding)
+}
+
+// embedServiceContent divide e combina o conteúdo como gerarEmbeddingServico, gerando cada
+// trecho com embed (ex.: GerarEmbedding com limite de chamadas por segundo)
+func embedServiceContent(ctx context.Context, content string, embed func(context.Context, string) ([]float32, error)) ([]float32, error) {
+	chunks := chunkText(content, embeddingChunkSize, maxEmbeddingChunks)
+	if len(chunks) <= 1 {
+		return embed(ctx, content)
+	}
+
+	embeddings := make([][]float32, 0, len(chunks))
+	for i, chunk := range chunks {
+		embedding, err := embed(ctx, chunk)
+		if err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			// trechos de anexos são complementares; segue com os já gerados
+			continue
+		}
+		embeddings = append(embeddings, embedding)
+	}
+
+	return combineChunkEmbeddings(embeddings), nil
+}
+
+// chunkText divide o texto em até maxChunks trechos de no máximo size caracteres,
+// preferindo quebrar em espaços
+func chunkText(text string, size, maxChunks int) []string {
+	var chunks []string
+	for text != "" && len(chunks) < maxChunks {
+		if utf8.RuneCountInString(text) <= size {
+			chunks = append(chunks, text)
+			break
+		}
+
+		runes := []rune(text)
+		cut := size
+		if space := strings.LastIndexFunc(string(runes[:size]), isSpace); space > 0 {
+			cut = utf8.RuneCountInString(string(runes[:size])[:space])
+		}
+
+		chunks = append(chunks, strings.TrimSpace(string(runes[:cut])))
+		text = strings.TrimSpace(string(runes[cut:]))
+	}
+	return chunks
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\n' || r == '\t'
+}
+
+// combineChunkEmbeddings faz a média ponderada (primeiro trecho com leadingChunkWeight,
+// os demais dividindo o restante) e normaliza o vetor resultante
+func combineChunkEmbeddings(embeddings [][]float32) []float32 {
+	if len(embeddings) == 1 {
+		return embeddings[0]
+	}
+
+	combined := make([]float64, len(embeddings[0]))
+	restWeight := (1 - leadingChunkWeight) / float64(len(embeddings)-1)
+	for i, embedding := range embeddings {
+		weight := restWeight
+		if i == 0 {
+			weight = leadingChunkWeight
+		}
+		for j, v := range embedding {
+			if j < len(combined) {
+				combined[j] += weight * float64(v)
+			}
+		}
+	}
+
+	var norm float64
+	for _, v := range combined {
+		norm += v * v
+	}
+	norm = math.Sqrt(norm)
+
+	result := make([]float32, len(combined))
+	for i, v := range combined {
+		if norm > 0 {
+			v /= norm
+		}
+		result[i] = float32(v)
+	}
+	return result
+}
@@ -0,0 +1,38 @@
+package typesense
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestChunkText(t *testing.T) {
+	if chunks := chunkText("IPTU 2025", 100, 8); len(chunks) != 1 {
+		t.Fatalf("texto curto deveria gerar um trecho, obtido %d", len(chunks))
+	}
+
+	text := strings.Repeat("formulário ", 30) // 330 caracteres
+	chunks := chunkText(text, 100, 8)
+	if len(chunks) != 4 {
+		t.Fatalf("esperado 4 trechos, obtido %d", len(chunks))
+	}
+	for _, chunk := range chunks {
+		if strings.HasPrefix(chunk, " ") || strings.Contains(chunk, "formulári ") {
+			t.Errorf("trecho quebrado no meio de palavra: %q", chunk)
+		}
+	}
+
+	if chunks := chunkText(text, 100, 2); len(chunks) != 2 {
+		t.Errorf("limite de trechos não respeitado: %d", len(chunks))
+	}
+}
+
+func TestCombineChunkEmbeddings(t *testing.T) {
+	combined := combineChunkEmbeddings([][]float32{{1, 0}, {0, 1}, {0, 1}})
+
+	// primeiro trecho com peso 0.5, demais 0.25 cada: (0.5, 0.5) normalizado
+	expected := float32(1 / math.Sqrt2)
+	if math.Abs(float64(combined[0]-expected)) > 1e-6 || math.Abs(float64(combined[1]-expected)) > 1e-6 {
+		t.Errorf("vetor combinado inesperado: %v", combined)
+	}
+}
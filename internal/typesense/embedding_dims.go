@@ -0,0 +1,109 @@
+package typesense
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// embeddingDimensions dimensão dos embeddings gerados por GerarEmbedding
+	embeddingDimensions = 768
+	// maxEmbedBatch textos por chamada de embedding aceitos pelo Gemini
+	maxEmbedBatch = 100
+	// schemaDimsTTL validade das dimensões lidas do schema (o schema só muda em migrações)
+	schemaDimsTTL = 5 * time.Minute
+)
+
+// EmbeddingDimensionError indica vetor cuja dimensão difere do num_dim do campo no schema
+type EmbeddingDimensionError struct {
+	Collection string
+	Field      string
+	Expected   int // num_dim do schema
+	Got        int
+	Model      string
+}
+
+func (e *EmbeddingDimensionError) Error() string {
+	return fmt.Sprintf(
+		"embedding com %d dimensões incompatível com %s.%s (num_dim %d) gerado pelo modelo %s. "+
+			"Ajuste GEMINI_EMBEDDING_MODEL para um modelo de %d dimensões ou migre o schema para num_dim %d "+
+			"e regenere os vetores (cmd/embedding-audit --sample 0 --fix)",
+		e.Got, e.Collection, e.Field, e.Expected, e.Model, e.Expected, e.Got,
+	)
+}
+
+// schemaDims dimensões dos campos vetoriais de uma collection
+type schemaDims struct {
+	dims     map[string]int
+	loadedAt time.Time
+}
+
+// schemaDimsCache dimensões lidas do schema por collection (ou alias)
+type schemaDimsCache struct {
+	mu      sync.Mutex
+	entries map[string]schemaDims
+}
+
+// vectorDims retorna o num_dim de cada campo vetorial da collection
+func (c *Client) vectorDims(ctx context.Context, collection string) (map[string]int, error) {
+	c.dims.mu.Lock()
+	cached, ok := c.dims.entries[collection]
+	c.dims.mu.Unlock()
+	if ok && time.Since(cached.loadedAt) < schemaDimsTTL {
+		return cached.dims, nil
+	}
+
+	schema, err := c.client.Collection(collection).Retrieve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dims := make(map[string]int)
+	for _, field := range schema.Fields {
+		if field.NumDim != nil && *field.NumDim > 0 {
+			dims[field.Name] = *field.NumDim
+		}
+	}
+
+	c.dims.mu.Lock()
+	if c.dims.entries == nil {
+		c.dims.entries = make(map[string]schemaDims)
+	}
+	c.dims.entries[collection] = schemaDims{dims: dims, loadedAt: time.Now()}
+	c.dims.mu.Unlock()
+	return dims, nil
+}
+
+// checkEmbeddingDim recusa gravar um vetor com dimensão diferente do num_dim do campo no
+// schema, com um erro que indica o reparo (em vez do erro genérico do Typesense). Se o schema
+// não puder ser lido ou o campo não for vetorial, a validação fica a cargo do Typesense.
+func (c *Client) checkEmbeddingDim(ctx context.Context, collection, field string, dim int) error {
+	if dim == 0 {
+		return nil
+	}
+	dims, err := c.vectorDims(ctx, collection)
+	if err != nil {
+		log.Printf("Aviso: não foi possível ler o schema de %s para validar o embedding: %v", collection, err)
+		return nil
+	}
+	if expected, ok := dims[field]; ok && expected != dim {
+		return &EmbeddingDimensionError{Collection: collection, Field: field, Expected: expected, Got: dim, Model: c.embeddingModel}
+	}
+	return nil
+}
+
+// checkEmbeddingSchema compara, na inicialização, o num_dim do campo embedding dos serviços com
+// a dimensão gerada pelo modelo configurado. Com EMBEDDING_DIM_STRICT a aplicação não sobe com
+// divergência; sem ele, o erro é registrado e as gravações com vetor são recusadas.
+func (c *Client) checkEmbeddingSchema(ctx context.Context, collection string) {
+	err := c.checkEmbeddingDim(ctx, collection, "embedding", embeddingDimensions)
+	if err == nil {
+		return
+	}
+	if c.cfg.EmbeddingDimStrict {
+		log.Fatalf("ERRO: %v", err)
+	}
+	log.Printf("ERRO: %v", err)
+}
@@ -0,0 +1,32 @@
+package typesense
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckEmbeddingDim(t *testing.T) {
+	c := &Client{embeddingModel: "gemini-embedding-001"}
+	c.dims.entries = map[string]schemaDims{
+		"prefrio_services_base": {dims: map[string]int{"embedding": 768}, loadedAt: time.Now()},
+	}
+
+	if err := c.checkEmbeddingDim(context.Background(), "prefrio_services_base", "embedding", 768); err != nil {
+		t.Errorf("dimensão do schema deveria ser aceita: %v", err)
+	}
+	if err := c.checkEmbeddingDim(context.Background(), "prefrio_services_base", "embedding", 0); err != nil {
+		t.Errorf("documento sem vetor deveria ser aceito: %v", err)
+	}
+
+	err := c.checkEmbeddingDim(context.Background(), "prefrio_services_base", "embedding", 3072)
+	var dimErr *EmbeddingDimensionError
+	if !errors.As(err, &dimErr) || dimErr.Expected != 768 || dimErr.Got != 3072 {
+		t.Fatalf("esperado EmbeddingDimensionError 768/3072, obtido %v", err)
+	}
+	if !strings.Contains(err.Error(), "GEMINI_EMBEDDING_MODEL") || !strings.Contains(err.Error(), "--fix") {
+		t.Errorf("erro deveria sugerir o reparo: %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package typesense
+
+import (
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+)
+
+// reuseEmbedding indica se o embedding gravado no serviço pode ser mantido: o documento tem o
+// hash do vetor, gerado pelo modelo atual a partir do mesmo search_content
+func reuseEmbedding(existing *models.PrefRioService, searchContent, model string) bool {
+	return existing != nil &&
+		existing.EmbeddingHash != "" &&
+		existing.EmbeddingModel == model &&
+		existing.SearchContent == searchContent
+}
+
+// storedEmbedding retorna o vetor gravado no documento se ele for exatamente o identificado por
+// hash (utils.EmbeddingHash) e tiver sido gerado pelo modelo atual a partir do mesmo
+// search_content; nil caso contrário
+func storedEmbedding(doc map[string]interface{}, searchContent, model, hash string) []float64 {
+	if doc == nil || hash == "" {
+		return nil
+	}
+	if content, _ := doc["search_content"].(string); content != searchContent {
+		return nil
+	}
+	if stored, _ := doc["embedding_model"].(string); stored != model {
+		return nil
+	}
+	embedding := parseStoredEmbedding(doc["embedding"])
+	if utils.EmbeddingHash(model, embedding) != hash {
+		return nil
+	}
+	return embedding
+}
@@ -0,0 +1,44 @@
+package typesense
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+)
+
+func TestReuseEmbedding(t *testing.T) {
+	existing := &models.PrefRioService{SearchContent: "iptu", EmbeddingModel: "m", EmbeddingHash: "h"}
+	if !reuseEmbedding(existing, "iptu", "m") {
+		t.Error("conteúdo e modelo inalterados deveriam manter o embedding")
+	}
+	if reuseEmbedding(existing, "iptu 2024", "m") || reuseEmbedding(existing, "iptu", "outro") {
+		t.Error("conteúdo ou modelo alterados deveriam gerar novo embedding")
+	}
+	if reuseEmbedding(&models.PrefRioService{SearchContent: "iptu", EmbeddingModel: "m"}, "iptu", "m") || reuseEmbedding(nil, "iptu", "m") {
+		t.Error("documento sem hash deveria gerar novo embedding")
+	}
+}
+
+func TestStoredEmbedding(t *testing.T) {
+	hash := utils.EmbeddingHash("m", []float64{0.1, 0.2})
+	doc := map[string]interface{}{
+		"search_content":  "iptu",
+		"embedding_model": "m",
+		"embedding":       []interface{}{0.1, 0.2},
+	}
+
+	if got := storedEmbedding(doc, "iptu", "m", hash); !reflect.DeepEqual(got, []float64{0.1, 0.2}) {
+		t.Errorf("vetor com o hash da versão deveria ser reaproveitado, obtido %v", got)
+	}
+	if storedEmbedding(doc, "iptu", "m", utils.EmbeddingHash("m", []float64{0.1, 0.3})) != nil {
+		t.Error("vetor com outro hash não deveria ser reaproveitado")
+	}
+	if storedEmbedding(doc, "itbi", "m", hash) != nil || storedEmbedding(doc, "iptu", "outro", hash) != nil {
+		t.Error("conteúdo ou modelo diferentes não deveriam reaproveitar o vetor")
+	}
+	if storedEmbedding(nil, "iptu", "m", hash) != nil || storedEmbedding(doc, "iptu", "m", "") != nil {
+		t.Error("sem documento ou sem hash nada é reaproveitado")
+	}
+}
@@ -0,0 +1,68 @@
+package typesense
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+// UpdateServiceEntities grava as entidades extraídas do serviço. Por serem dados derivados,
+// a atualização é parcial e não gera nova versão nem altera last_update.
+func (c *Client) UpdateServiceEntities(ctx context.Context, id string, entities *models.ServiceEntities) error {
+	entitiesMap, err := c.structToMap(entities)
+	if err != nil {
+		return fmt.Errorf("erro ao converter entidades: %v", err)
+	}
+
+	collectionName := "prefrio_services_base"
+	update := map[string]interface{}{"entities": entitiesMap}
+	if _, err := c.client.Collection(collectionName).Document(id).Update(ctx, update, &api.DocumentIndexParameters{}); err != nil {
+		return fmt.Errorf("erro ao salvar entidades do serviço %s: %v", id, err)
+	}
+	return nil
+}
+
+// EnrichServiceEntities extrai e grava as entidades de um serviço
+func (c *Client) EnrichServiceEntities(ctx context.Context, service *models.PrefRioService, extractor *services.EntityExtractor) (*models.ServiceEntities, error) {
+	entities := extractor.Extract(ctx, service)
+	if err := c.UpdateServiceEntities(ctx, service.ID, entities); err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+// BackfillServiceEntities extrai as entidades dos serviços sem entidades ou editados depois da
+// última extração (todos, se force) e retorna quantos foram enriquecidos
+func (c *Client) BackfillServiceEntities(ctx context.Context, extractor *services.EntityExtractor, force bool) (int, error) {
+	const perPage = 100
+	enriched := 0
+
+	for page := 1; ; page++ {
+		resp, err := c.ListPrefRioServices(ctx, page, perPage, map[string]interface{}{})
+		if err != nil {
+			return enriched, fmt.Errorf("erro ao listar serviços (página %d): %v", page, err)
+		}
+
+		for i := range resp.Services {
+			service := &resp.Services[i]
+			if !force && service.Entities != nil && service.Entities.ExtractedAt >= service.LastUpdate {
+				continue
+			}
+			if _, err := c.EnrichServiceEntities(ctx, service, extractor); err != nil {
+				log.Printf("[Entidades] %v", err)
+				continue
+			}
+			enriched++
+		}
+
+		if len(resp.Services) < perPage {
+			break
+		}
+	}
+
+	return enriched, nil
+}
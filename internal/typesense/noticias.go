@@ -0,0 +1,143 @@
+package typesense
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/config"
+	"github.com/prefeitura-rio/app-busca-search/internal/jobs"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+// NoticiasCollection collection das notícias e eventos da cidade
+const NoticiasCollection = config.NoticiasCollectionName
+
+// NoticiasSource origem das notícias (CMS da redação)
+type NoticiasSource interface {
+	ListModified(ctx context.Context, since time.Time) ([]models.Noticia, error)
+}
+
+// noticiasSchema schema da collection noticias
+func noticiasSchema(collectionName string) *api.CollectionSchema {
+	return &api.CollectionSchema{
+		Name: collectionName,
+		Fields: []api.Field{
+			{Name: "id", Type: "string", Optional: boolPtr(true)},
+			{Name: "source_id", Type: "string", Facet: boolPtr(false)},
+			{Name: "title", Type: "string", Facet: boolPtr(false)},
+			{Name: "summary", Type: "string", Facet: boolPtr(false), Optional: boolPtr(true)},
+			{Name: "body", Type: "string", Facet: boolPtr(false)},
+			{Name: "url", Type: "string", Facet: boolPtr(false), Optional: boolPtr(true), Index: boolPtr(false)},
+			{Name: "tags", Type: "string[]", Facet: boolPtr(true), Optional: boolPtr(true)},
+			{Name: "status", Type: "int32", Facet: boolPtr(true)},
+			{Name: "published_at", Type: "int64", Facet: boolPtr(false)},
+			{Name: "updated_at", Type: "int64", Facet: boolPtr(false)},
+			{Name: "tenant", Type: "string", Facet: boolPtr(true), Optional: boolPtr(true)},
+			{Name: "embedding", Type: "float[]", NumDim: intPtr(768), Optional: boolPtr(true)},
+		},
+		DefaultSortingField: stringPtr("published_at"),
+	}
+}
+
+// SyncNoticias importa as notícias alteradas no CMS desde a última sincronização (o maior
+// updated_at indexado). Notícias despublicadas ou na lixeira são removidas do índice.
+func (c *Client) SyncNoticias(ctx context.Context, source NoticiasSource) (*models.NoticiasSyncResult, error) {
+	if err := c.EnsureCollectionExists(NoticiasCollection); err != nil {
+		return nil, err
+	}
+
+	since, err := c.latestNoticiaUpdate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var sinceTime time.Time
+	if since > 0 {
+		sinceTime = time.Unix(since, 0)
+	}
+	noticias, err := source.ListModified(ctx, sinceTime)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.NoticiasSyncResult{Since: since, Fetched: len(noticias)}
+	var docs []interface{}
+	for _, noticia := range noticias {
+		if noticia.Status != 1 {
+			_, err := c.client.Collection(NoticiasCollection).Document(noticia.ID).Delete(ctx)
+			if err != nil && !services.IsNotFoundError(err) {
+				return result, fmt.Errorf("erro ao remover notícia %s: %v", noticia.ID, err)
+			}
+			if err == nil {
+				result.Removed++
+			}
+			continue
+		}
+
+		doc, err := c.structToMap(noticia)
+		if err != nil {
+			return result, err
+		}
+
+		// Sem embedding a notícia continua disponível na busca textual
+		embedding, err := c.GerarEmbedding(ctx, strings.Join([]string{noticia.Title, noticia.Summary, noticia.Body}, "\n"))
+		if err != nil {
+			log.Printf("[Noticias] Aviso: embedding não gerado para %s: %v", noticia.ID, err)
+		} else {
+			doc["embedding"] = embedding
+		}
+		docs = append(docs, doc)
+	}
+
+	if len(docs) > 0 {
+		action := api.Upsert
+		if _, err := c.client.Collection(NoticiasCollection).Documents().Import(ctx, docs, &api.ImportDocumentsParams{Action: &action}); err != nil {
+			return result, fmt.Errorf("erro ao importar notícias: %v", err)
+		}
+		result.Upserted = len(docs)
+	}
+
+	return result, nil
+}
+
+// latestNoticiaUpdate retorna o maior updated_at indexado (0 com a collection vazia)
+func (c *Client) latestNoticiaUpdate(ctx context.Context) (int64, error) {
+	result, err := c.client.Collection(NoticiasCollection).Documents().Search(ctx, &api.SearchCollectionParams{
+		Q:             stringPtr("*"),
+		SortBy:        stringPtr("updated_at:desc"),
+		PerPage:       intPtr(1),
+		IncludeFields: stringPtr("updated_at"),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("erro ao consultar última notícia sincronizada: %v", err)
+	}
+	if result.Hits == nil || len(*result.Hits) == 0 || (*result.Hits)[0].Document == nil {
+		return 0, nil
+	}
+
+	switch updatedAt := (*(*result.Hits)[0].Document)["updated_at"].(type) {
+	case float64:
+		return int64(updatedAt), nil
+	case int64:
+		return updatedAt, nil
+	}
+	return 0, nil
+}
+
+// StartNoticiasSyncRoutine agenda a sincronização periódica com o CMS da redação, executada
+// por uma única réplica (job noticias-sync)
+func (c *Client) StartNoticiasSyncRoutine(runner *jobs.Runner, interval time.Duration, source NoticiasSource) error {
+	return runner.Register("noticias-sync", fmt.Sprintf("@every %s", interval), 10*time.Minute, func(ctx context.Context) error {
+		result, err := c.SyncNoticias(ctx, source)
+		if err != nil {
+			return err
+		}
+		log.Printf("[Noticias] %d notícias lidas do CMS: %d indexadas, %d removidas", result.Fetched, result.Upserted, result.Removed)
+		return nil
+	})
+}
@@ -0,0 +1,96 @@
+package typesense
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/jobs"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+// RefreshPlaintextFields grava os campos plaintext (textos sem markdown) dos serviços indexados
+// antes deles existirem ou com textos alterados fora da API. Como os campos são derivados, a
+// atualização é parcial e não gera nova versão nem altera last_update.
+func (c *Client) RefreshPlaintextFields(ctx context.Context) (*models.PlaintextBackfillResult, error) {
+	collectionName := "prefrio_services_base"
+	result := &models.PlaintextBackfillResult{}
+
+	// Carrega todos os serviços antes de atualizar, para não deslocar a paginação
+	const perPage = 100
+	var candidates []models.PrefRioService
+	for page := 1; ; page++ {
+		resp, err := c.ListPrefRioServices(ctx, page, perPage, map[string]interface{}{})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar serviços (página %d): %v", page, err)
+		}
+		candidates = append(candidates, resp.Services...)
+		if len(resp.Services) < perPage {
+			break
+		}
+	}
+
+	for i := range candidates {
+		service := &candidates[i]
+		result.Scanned++
+
+		current := *service
+		service.SetPlaintextFields()
+		if plaintextEqual(&current, service) {
+			continue
+		}
+
+		update := map[string]interface{}{
+			"resumo_plaintext":                 service.ResumoPlaintext,
+			"resultado_solicitacao_plaintext":  service.ResultadoSolicitacaoPlaintext,
+			"descricao_completa_plaintext":     service.DescricaoCompletaPlaintext,
+			"documentos_necessarios_plaintext": nonNilStrings(service.DocumentosNecessariosPlaintext),
+			"instrucoes_solicitante_plaintext": service.InstrucoesSolicitantePlaintext,
+		}
+		if _, err := c.client.Collection(collectionName).Document(service.ID).Update(ctx, update, &api.DocumentIndexParameters{}); err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", service.ID, err))
+			continue
+		}
+		result.Updated++
+	}
+
+	return result, nil
+}
+
+// StartPlaintextBackfillRoutine agenda a regeneração diária dos campos plaintext (job
+// plaintext-backfill), executada por uma única réplica. Após um deploy que altere os campos,
+// o job pode ser disparado em POST /admin/jobs/plaintext-backfill/run.
+func (c *Client) StartPlaintextBackfillRoutine(runner *jobs.Runner) error {
+	return runner.Register("plaintext-backfill", "50 3 * * *", 30*time.Minute, func(ctx context.Context) error {
+		result, err := c.RefreshPlaintextFields(ctx)
+		if err != nil {
+			return err
+		}
+		if result.Updated > 0 || result.Failed > 0 {
+			log.Printf("[Plaintext] %d serviços atualizados, %d falhas", result.Updated, result.Failed)
+		}
+		if result.Failed > 0 {
+			return fmt.Errorf("%d falhas ao atualizar campos plaintext, a primeira: %s", result.Failed, result.Errors[0])
+		}
+		return nil
+	})
+}
+
+func plaintextEqual(a, b *models.PrefRioService) bool {
+	return a.ResumoPlaintext == b.ResumoPlaintext &&
+		a.ResultadoSolicitacaoPlaintext == b.ResultadoSolicitacaoPlaintext &&
+		a.DescricaoCompletaPlaintext == b.DescricaoCompletaPlaintext &&
+		slices.Equal(a.DocumentosNecessariosPlaintext, b.DocumentosNecessariosPlaintext) &&
+		a.InstrucoesSolicitantePlaintext == b.InstrucoesSolicitantePlaintext
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,28 @@
+package typesense
+
+import (
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestPlaintextEqual(t *testing.T) {
+	service := &models.PrefRioService{
+		Resumo:                "**Emissão** da [segunda via](https://carioca.rio)",
+		DocumentosNecessarios: []string{"- CPF", "*RG*"},
+	}
+	current := *service
+	service.SetPlaintextFields()
+	if plaintextEqual(&current, service) {
+		t.Fatal("serviço sem campos plaintext gravados deveria precisar de atualização")
+	}
+	if service.ResumoPlaintext != "Emissão da segunda via" {
+		t.Errorf("resumo_plaintext inesperado: %q", service.ResumoPlaintext)
+	}
+
+	stored := *service
+	service.SetPlaintextFields()
+	if !plaintextEqual(&stored, service) {
+		t.Error("campos já gravados não deveriam gerar atualização")
+	}
+}
@@ -0,0 +1,73 @@
+package typesense
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+// SyncCollectionFields adiciona à collection existente os campos do schema em Go que ela ainda
+// não tem, pelo alter de collection do Typesense. Apenas adições são aplicadas: tipos
+// divergentes são registrados em log e campos retirados do schema permanecem. Idempotente;
+// retorna os campos adicionados.
+func (c *Client) SyncCollectionFields(ctx context.Context, collectionName string) ([]string, error) {
+	collection, err := c.client.Collection(collectionName).Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar schema da collection %s: %v", collectionName, err)
+	}
+
+	additions, mismatches := fieldAdditions(collection.Fields, collectionSchema(collectionName).Fields)
+	for _, mismatch := range mismatches {
+		log.Printf("Aviso: campo %s da collection %s diverge do schema; alteração não aplicada", mismatch, collectionName)
+	}
+	if len(additions) == 0 {
+		return nil, nil
+	}
+
+	if _, err := c.client.Collection(collectionName).Update(ctx, &api.CollectionUpdateSchema{Fields: additions}); err != nil {
+		return nil, fmt.Errorf("erro ao adicionar campos à collection %s: %v", collectionName, err)
+	}
+
+	names := make([]string, len(additions))
+	for i, field := range additions {
+		names[i] = field.Name
+	}
+	log.Printf("Campos adicionados à collection %s: %s", collectionName, strings.Join(names, ", "))
+	return names, nil
+}
+
+// syncCollectionFields sincroniza os campos na inicialização; falhas não impedem a subida
+func (c *Client) syncCollectionFields(ctx context.Context, collectionName string) {
+	if _, err := c.SyncCollectionFields(ctx, collectionName); err != nil {
+		log.Printf("Aviso: %v", err)
+	}
+}
+
+// fieldAdditions compara os campos do schema com os da collection: retorna os ausentes, na
+// ordem do schema, e os de tipo divergente (campo: tipo na collection → tipo no schema)
+func fieldAdditions(live, desired []api.Field) ([]api.Field, []string) {
+	liveTypes := make(map[string]string, len(live))
+	for _, field := range live {
+		liveTypes[field.Name] = field.Type
+	}
+
+	var additions []api.Field
+	var mismatches []string
+	for _, field := range desired {
+		// id não é um campo do schema no Typesense
+		if field.Name == "id" {
+			continue
+		}
+		liveType, exists := liveTypes[field.Name]
+		switch {
+		case !exists:
+			additions = append(additions, field)
+		case liveType != field.Type && liveType != "auto":
+			mismatches = append(mismatches, fmt.Sprintf("%s (%s → %s)", field.Name, liveType, field.Type))
+		}
+	}
+	return additions, mismatches
+}
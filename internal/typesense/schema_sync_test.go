@@ -0,0 +1,42 @@
+package typesense
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+func TestFieldAdditions(t *testing.T) {
+	live := []api.Field{
+		{Name: "nome_servico", Type: "string"},
+		{Name: "status", Type: "int64"},
+		{Name: "legado", Type: "string"},
+		{Name: ".*", Type: "auto"},
+	}
+	desired := []api.Field{
+		{Name: "id", Type: "string"},
+		{Name: "nome_servico", Type: "string"},
+		{Name: "status", Type: "int32"},
+		{Name: "bairros", Type: "string[]", Optional: boolPtr(true)},
+		{Name: "entities.valor_min", Type: "float", Optional: boolPtr(true)},
+	}
+
+	additions, mismatches := fieldAdditions(live, desired)
+	var names []string
+	for _, field := range additions {
+		names = append(names, field.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"bairros", "entities.valor_min"}) {
+		t.Errorf("campos adicionados inesperados: %v", names)
+	}
+	if !reflect.DeepEqual(mismatches, []string{"status (int64 → int32)"}) {
+		t.Errorf("divergências inesperadas: %v", mismatches)
+	}
+
+	// Com a collection sincronizada não há o que adicionar
+	live = append(live, additions...)
+	if additions, _ := fieldAdditions(live, desired); len(additions) != 0 {
+		t.Errorf("esperado nenhum campo, obtido %+v", additions)
+	}
+}
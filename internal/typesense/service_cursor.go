@@ -0,0 +1,90 @@
+package typesense
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+// ErrInvalidCursor indica cursor de paginação malformado
+var ErrInvalidCursor = errors.New("cursor inválido")
+
+// serviceListCursor posição de continuação da listagem de serviços, ordenada por last_update
+// decrescente: o last_update do último serviço retornado e os IDs já retornados com esse valor.
+// Ao contrário do offset de página, não pula nem repete serviços quando há edições entre as
+// requisições nem fica mais lento em páginas profundas.
+type serviceListCursor struct {
+	LastUpdate int64    `json:"t"`
+	IDs        []string `json:"ids"`
+}
+
+// ListPrefRioServicesAfter continua a listagem de serviços a partir do next_cursor de uma
+// resposta anterior
+func (c *Client) ListPrefRioServicesAfter(ctx context.Context, cursor string, perPage int, filters map[string]interface{}) (*models.PrefRioServiceResponse, error) {
+	after, err := decodeServiceListCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.listPrefRioServices(ctx, 1, perPage, filters, after, "")
+	if err != nil {
+		return nil, err
+	}
+	response.HasPrev = true
+	return response, nil
+}
+
+// filter serviços posteriores ao cursor na ordenação
+func (cur *serviceListCursor) filter() string {
+	if len(cur.IDs) == 0 {
+		return fmt.Sprintf("last_update:<%d", cur.LastUpdate)
+	}
+	ids := make([]string, len(cur.IDs))
+	for i, id := range cur.IDs {
+		ids[i] = "`" + id + "`"
+	}
+	return fmt.Sprintf("(last_update:<%d || (last_update:=%d && id:!=[%s]))", cur.LastUpdate, cur.LastUpdate, strings.Join(ids, ","))
+}
+
+func (cur *serviceListCursor) encode() string {
+	data, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeServiceListCursor(raw string) (*serviceListCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cur serviceListCursor
+	if err := json.Unmarshal(data, &cur); err != nil || cur.LastUpdate < 0 {
+		return nil, ErrInvalidCursor
+	}
+	for _, id := range cur.IDs {
+		if id == "" || strings.ContainsAny(id, "`") {
+			return nil, ErrInvalidCursor
+		}
+	}
+	return &cur, nil
+}
+
+// nextServiceListCursor cursor após a página: o last_update do último serviço e os IDs com esse
+// mesmo valor, incluindo os do cursor anterior quando a sequência de empates continua
+func nextServiceListCursor(page []models.PrefRioService, after *serviceListCursor) *serviceListCursor {
+	last := page[len(page)-1].LastUpdate
+	next := &serviceListCursor{LastUpdate: last}
+	if after != nil && after.LastUpdate == last {
+		next.IDs = append(next.IDs, after.IDs...)
+	}
+	for _, service := range page {
+		if service.LastUpdate == last {
+			next.IDs = append(next.IDs, service.ID)
+		}
+	}
+	return next
+}
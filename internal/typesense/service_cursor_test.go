@@ -0,0 +1,59 @@
+package typesense
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestServiceListCursorFilter(t *testing.T) {
+	cur := &serviceListCursor{LastUpdate: 100}
+	if got := cur.filter(); got != "last_update:<100" {
+		t.Errorf("filtro sem empates: %q", got)
+	}
+
+	cur.IDs = []string{"a", "b"}
+	expected := "(last_update:<100 || (last_update:=100 && id:!=[`a`,`b`]))"
+	if got := cur.filter(); got != expected {
+		t.Errorf("esperado %q, obtido %q", expected, got)
+	}
+}
+
+func TestServiceListCursorEncoding(t *testing.T) {
+	cur := &serviceListCursor{LastUpdate: 1_800_000_000, IDs: []string{"svc-1"}}
+	decoded, err := decodeServiceListCursor(cur.encode())
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, cur) {
+		t.Errorf("esperado %+v, obtido %+v", cur, decoded)
+	}
+
+	for _, raw := range []string{"!!", "bm9wZQ", (&serviceListCursor{LastUpdate: 1, IDs: []string{"a`b"}}).encode()} {
+		if _, err := decodeServiceListCursor(raw); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("cursor %q: esperado ErrInvalidCursor, obtido %v", raw, err)
+		}
+	}
+}
+
+func TestNextServiceListCursor(t *testing.T) {
+	page := []models.PrefRioService{
+		{ID: "c", LastUpdate: 300},
+		{ID: "d", LastUpdate: 200},
+		{ID: "e", LastUpdate: 200},
+	}
+
+	next := nextServiceListCursor(page, nil)
+	if next.LastUpdate != 200 || !reflect.DeepEqual(next.IDs, []string{"d", "e"}) {
+		t.Errorf("cursor inesperado: %+v", next)
+	}
+
+	// Empates que continuam da página anterior acumulam os IDs já retornados
+	tied := []models.PrefRioService{{ID: "f", LastUpdate: 200}}
+	next = nextServiceListCursor(tied, next)
+	if !reflect.DeepEqual(next.IDs, []string{"d", "e", "f"}) {
+		t.Errorf("cursor inesperado: %+v", next)
+	}
+}
@@ -0,0 +1,70 @@
+package typesense
+
+import (
+	"context"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
+)
+
+// embeddingSourceFields campos versionados que compõem o search_content (generateSearchContent)
+var embeddingSourceFields = map[string]bool{
+	"nome_servico":           true,
+	"resumo":                 true,
+	"descricao_completa":     true,
+	"tema_geral":             true,
+	"orgao_gestor":           true,
+	"publico_especifico":     true,
+	"documentos_necessarios": true,
+}
+
+// PreviewPrefRioServiceWrite simula a gravação de service: aplica as mesmas transformações da
+// criação e da atualização (sanitização, gateway nas URLs, search_content) e calcula o diff em
+// relação a existing, sem gravar o documento nem a versão. existing é nil na criação.
+func (c *Client) PreviewPrefRioServiceWrite(ctx context.Context, operation string, existing, service *models.PrefRioService) *models.ServiceWritePreview {
+	now := time.Now().Unix()
+	service.LastUpdate = now
+	if existing == nil {
+		service.CreatedAt = now
+		if service.Tenant == "" {
+			service.Tenant = tenant.OwnerID(ctx)
+		}
+	} else {
+		service.ID = existing.ID
+		if service.Tenant == "" {
+			service.Tenant = existing.Tenant
+		}
+	}
+
+	c.prepareServiceDocument(ctx, service)
+
+	preview := &models.ServiceWritePreview{
+		DryRun:            true,
+		Operation:         operation,
+		Service:           service,
+		Changes:           c.versionService.PreviewChanges(existing, service),
+		Reembedding:       c.geminiClient != nil && !reuseEmbedding(existing, service.SearchContent, c.embeddingModel),
+		ReembeddingFields: []string{},
+	}
+	if preview.Reembedding {
+		preview.ReembeddingFields = reembeddingFields(preview.Changes)
+	}
+	if existing != nil && !preview.Reembedding {
+		service.EmbeddingModel = existing.EmbeddingModel
+		service.EmbeddingDim = existing.EmbeddingDim
+		service.EmbeddingHash = existing.EmbeddingHash
+	}
+	return preview
+}
+
+// reembeddingFields filtra as mudanças nos campos que compõem o search_content
+func reembeddingFields(changes []models.FieldChange) []string {
+	fields := []string{}
+	for _, change := range changes {
+		if embeddingSourceFields[change.FieldName] {
+			fields = append(fields, change.FieldName)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,26 @@
+package typesense
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestReembeddingFields(t *testing.T) {
+	changes := []models.FieldChange{
+		{FieldName: "nome_servico"},
+		{FieldName: "status"},
+		{FieldName: "search_content"},
+		{FieldName: "documentos_necessarios"},
+		{FieldName: "canais_digitais"},
+	}
+	got := reembeddingFields(changes)
+	want := []string{"nome_servico", "documentos_necessarios"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("esperado %v, obtido %v", want, got)
+	}
+	if got := reembeddingFields(nil); got == nil || len(got) != 0 {
+		t.Errorf("sem mudanças deveria retornar lista vazia, obtido %v", got)
+	}
+}
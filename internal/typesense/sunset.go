@@ -0,0 +1,84 @@
+package typesense
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/jobs"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+// Identificação do usuário registrada nas versões geradas pela despublicação automática
+const (
+	sunsetUserName = "Sistema (sunset automático)"
+	sunsetUserCPF  = "00000000000"
+)
+
+// UnpublishSunsetServices despublica os serviços descontinuados cujo sunset_at já passou e
+// retorna os IDs despublicados
+func (c *Client) UnpublishSunsetServices(ctx context.Context) ([]string, error) {
+	collectionName := "prefrio_services_base"
+
+	filterBy := fmt.Sprintf("status:=1 && deprecated:=true && sunset_at:<=%d", time.Now().Unix())
+	searchParams := &api.SearchCollectionParams{
+		Q:             stringPtr("*"),
+		FilterBy:      &filterBy,
+		Page:          intPtr(1),
+		PerPage:       intPtr(250),
+		ExcludeFields: stringPtr("embedding"),
+	}
+
+	result, err := c.client.Collection(collectionName).Documents().Search(ctx, searchParams)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar serviços com sunset vencido: %v", err)
+	}
+
+	var unpublished []string
+	if result.Hits == nil {
+		return unpublished, nil
+	}
+
+	for _, hit := range *result.Hits {
+		if hit.Document == nil {
+			continue
+		}
+
+		docBytes, err := json.Marshal(*hit.Document)
+		if err != nil {
+			continue
+		}
+		var service models.PrefRioService
+		if err := json.Unmarshal(docBytes, &service); err != nil {
+			continue
+		}
+
+		service.Status = 0
+		if _, err := c.UpdatePrefRioServiceWithVersion(ctx, service.ID, &service, sunsetUserName, sunsetUserCPF, "Despublicação automática (sunset)"); err != nil {
+			log.Printf("[Sunset] Erro ao despublicar serviço %s: %v", service.ID, err)
+			continue
+		}
+		unpublished = append(unpublished, service.ID)
+	}
+
+	return unpublished, nil
+}
+
+// StartSunsetRoutine verifica periodicamente, em uma única réplica, os serviços com sunset
+// vencido e os despublica (job sunset). onUnpublish é chamado para cada serviço despublicado
+// (ex.: para invalidar caches).
+func (c *Client) StartSunsetRoutine(runner *jobs.Runner, interval time.Duration, onUnpublish func(ctx context.Context, serviceID string)) error {
+	return runner.Register("sunset", fmt.Sprintf("@every %dm", int(interval/time.Minute)), 5*time.Minute, func(ctx context.Context) error {
+		ids, err := c.UnpublishSunsetServices(ctx)
+		for _, id := range ids {
+			log.Printf("[Sunset] Serviço %s despublicado automaticamente", id)
+			if onUnpublish != nil {
+				onUnpublish(ctx, id)
+			}
+		}
+		return err
+	})
+}
@@ -0,0 +1,65 @@
+package typesense
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/tenant"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+)
+
+// tenantPartitionedCollections collections filtradas por tenant (tenant.ScopeFilter). hub_search
+// e as collections legadas não são particionadas.
+var tenantPartitionedCollections = []string{"prefrio_services_base", "tombamentos_overlay"}
+
+// AssignDefaultTenant atribui o tenant padrão aos serviços e tombamentos gravados sem tenant
+// (antes do particionamento ou com ele desabilitado). O filtro por tenant não encontra documentos
+// sem o campo, e tenant.Allows os trata como do tenant padrão. Idempotente; retorna quantos
+// documentos foram atualizados.
+func (c *Client) AssignDefaultTenant(ctx context.Context) (int, error) {
+	updated := 0
+	for _, collectionName := range tenantPartitionedCollections {
+		var ids []string
+		err := c.exportCollection(ctx, collectionName, "id,"+tenant.Field, func(line []byte) error {
+			var doc struct {
+				ID     string `json:"id"`
+				Tenant string `json:"tenant"`
+			}
+			if err := json.Unmarshal(line, &doc); err != nil {
+				return fmt.Errorf("erro ao deserializar documento: %w", err)
+			}
+			if doc.Tenant == "" {
+				ids = append(ids, doc.ID)
+			}
+			return nil
+		})
+		if err != nil {
+			return updated, fmt.Errorf("erro ao exportar %s: %w", collectionName, err)
+		}
+
+		for start := 0; start < len(ids); start += rebuildImportBatch {
+			batch := ids[start:min(start+rebuildImportBatch, len(ids))]
+			docs := make([]interface{}, len(batch))
+			for i, id := range batch {
+				docs[i] = map[string]interface{}{"id": id, tenant.Field: tenant.DefaultID}
+			}
+			results, err := c.client.Collection(collectionName).Documents().Import(ctx, docs, &api.ImportDocumentsParams{
+				Action: pointer.Any(api.Update),
+			})
+			if err != nil {
+				return updated, fmt.Errorf("erro ao atualizar tenant em %s: %w", collectionName, err)
+			}
+			for i, result := range results {
+				if !result.Success {
+					log.Printf("[Tenant] %s/%s: erro ao atribuir tenant padrão: %s", collectionName, batch[i], result.Error)
+					continue
+				}
+				updated++
+			}
+		}
+	}
+	return updated, nil
+}
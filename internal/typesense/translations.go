@@ -0,0 +1,34 @@
+package typesense
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+// IndexServiceTranslation grava a tradução revisada no serviço (translations.<idioma>), tornando-a
+// pesquisável. Como as traduções não alteram o conteúdo original, a atualização é parcial e não
+// gera nova versão nem altera last_update.
+func (c *Client) IndexServiceTranslation(ctx context.Context, service *models.PrefRioService, lang string, fields *models.ServiceTranslationFields) error {
+	translations := models.ServiceTranslations{}
+	for existingLang, existing := range service.Translations {
+		translations[existingLang] = existing
+	}
+	translations[lang] = fields
+
+	translationsMap, err := c.structToMap(translations)
+	if err != nil {
+		return fmt.Errorf("erro ao converter traduções: %v", err)
+	}
+
+	collectionName := "prefrio_services_base"
+	update := map[string]interface{}{"translations": translationsMap}
+	if _, err := c.client.Collection(collectionName).Document(service.ID).Update(ctx, update, &api.DocumentIndexParameters{}); err != nil {
+		return fmt.Errorf("erro ao indexar tradução do serviço %s: %v", service.ID, err)
+	}
+
+	service.Translations = translations
+	return nil
+}
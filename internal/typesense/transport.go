@@ -0,0 +1,89 @@
+package typesense
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+)
+
+// UpdateServiceTransport grava o transporte próximo dos canais presenciais do serviço. Como as
+// entidades, é dado derivado: a atualização é parcial e não gera nova versão.
+func (c *Client) UpdateServiceTransport(ctx context.Context, id string, transport *models.ServiceTransport) error {
+	transportMap, err := c.structToMap(transport)
+	if err != nil {
+		return fmt.Errorf("erro ao converter transporte: %v", err)
+	}
+
+	collectionName := "prefrio_services_base"
+	update := map[string]interface{}{"transporte": transportMap}
+	if _, err := c.client.Collection(collectionName).Document(id).Update(ctx, update, &api.DocumentIndexParameters{}); err != nil {
+		return fmt.Errorf("erro ao salvar transporte do serviço %s: %v", id, err)
+	}
+	return nil
+}
+
+// EnrichServiceTransport geocodifica os canais presenciais do serviço e grava as estações
+// próximas; retorna os canais que não puderam ser localizados
+func (c *Client) EnrichServiceTransport(ctx context.Context, service *models.PrefRioService, enricher *services.TransportEnricher) (*models.ServiceTransport, []string, error) {
+	transport, unresolved, err := enricher.Enrich(ctx, service.CanaisPresenciais)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao geocodificar canais do serviço %s: %v", service.ID, err)
+	}
+	if err := c.UpdateServiceTransport(ctx, service.ID, transport); err != nil {
+		return nil, nil, err
+	}
+	return transport, unresolved, nil
+}
+
+// BackfillServiceTransport enriquece os serviços com canais presenciais que ainda não têm
+// transporte ou foram editados depois do último enriquecimento (todos, se force)
+func (c *Client) BackfillServiceTransport(ctx context.Context, enricher *services.TransportEnricher, force bool) (*models.TransportEnrichReport, error) {
+	const perPage = 100
+	start := time.Now()
+	report := &models.TransportEnrichReport{Unresolved: []string{}, Errors: []string{}}
+
+	for page := 1; ; page++ {
+		resp, err := c.ListPrefRioServices(ctx, page, perPage, map[string]interface{}{})
+		if err != nil {
+			return report, fmt.Errorf("erro ao listar serviços (página %d): %v", page, err)
+		}
+
+		for i := range resp.Services {
+			service := &resp.Services[i]
+			if len(service.CanaisPresenciais) == 0 {
+				continue
+			}
+			report.Checked++
+			if !force && service.Transporte != nil && service.Transporte.EnrichedAt >= service.LastUpdate {
+				continue
+			}
+
+			transport, unresolved, err := c.EnrichServiceTransport(ctx, service, enricher)
+			if err != nil {
+				log.Printf("[Transporte] %v", err)
+				report.Errors = append(report.Errors, err.Error())
+				if ctx.Err() != nil {
+					return report, ctx.Err()
+				}
+				continue
+			}
+			report.Enriched++
+			report.Locations += len(transport.Locations)
+			for _, canal := range unresolved {
+				report.Unresolved = append(report.Unresolved, fmt.Sprintf("%s: %s", service.ID, canal))
+			}
+		}
+
+		if len(resp.Services) < perPage {
+			break
+		}
+	}
+
+	report.DurationMs = time.Since(start).Milliseconds()
+	return report, nil
+}
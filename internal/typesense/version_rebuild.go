@@ -0,0 +1,446 @@
+package typesense
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/concurrency"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"github.com/prefeitura-rio/app-busca-search/internal/utils"
+	"github.com/typesense/typesense-go/v3/typesense/api"
+	"github.com/typesense/typesense-go/v3/typesense/api/pointer"
+	"google.golang.org/genai"
+)
+
+// rebuildImportBatch documentos gravados por requisição de import
+const rebuildImportBatch = 100
+
+const (
+	// rebuildEmbeddingRetries novas tentativas de gerar um embedding antes de registrar o erro
+	rebuildEmbeddingRetries = 5
+	// rebuildEmbeddingRetryDelay espera antes da primeira nova tentativa; dobra a cada tentativa,
+	// dando tempo ao Gemini de liberar a cota após respostas 429
+	rebuildEmbeddingRetryDelay = 2 * time.Second
+)
+
+// isRetryableEmbeddingError classifica as falhas do Gemini que merecem nova tentativa: cota
+// esgotada (429, RESOURCE_EXHAUSTED), erros do servidor (5xx) e timeouts. As demais (ex.:
+// requisição inválida, chave sem permissão) falham na primeira tentativa.
+func isRetryableEmbeddingError(err error) bool {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == http.StatusTooManyRequests || apiErr.Code >= 500 ||
+			strings.Contains(apiErr.Status, "RESOURCE_EXHAUSTED")
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return strings.Contains(err.Error(), "RESOURCE_EXHAUSTED")
+}
+
+// rebuildSnapshot última versão de um serviço e a data da primeira
+type rebuildSnapshot struct {
+	latest    models.ServiceVersion
+	createdAt int64
+}
+
+// RebuildFromVersions reconstrói a collection de serviços a partir da última versão de cada
+// serviço em service_versions (recuperação de desastre quando a collection principal está
+// corrompida). Serviços cuja última versão é uma remoção são ignorados; search_content, slug,
+// deep links e embedding são regenerados como na criação do serviço. Os documentos são gravados
+// em opts.Target e comparados com a collection atual: documentos sem versões, serviços ausentes
+// e conteúdo divergente da última versão entram no relatório.
+func (c *Client) RebuildFromVersions(ctx context.Context, opts models.VersionRebuildOptions) (*models.VersionRebuildReport, error) {
+	if opts.Target == "" && !opts.DryRun {
+		return nil, errors.New("collection de destino não informada")
+	}
+	if !opts.SkipEmbeddings && c.geminiClient == nil {
+		return nil, errors.New("cliente Gemini indisponível: configure GEMINI_API_KEY ou desabilite a geração de embeddings")
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	opts.EmbedBatch = min(opts.EmbedBatch, maxEmbedBatch)
+
+	start := time.Now()
+	report := &models.VersionRebuildReport{
+		StartedAt: start.Unix(),
+		Source:    services.PrefRioServicesCollection,
+		Target:    opts.Target,
+		DryRun:    opts.DryRun,
+		Diffs:     []models.VersionRebuildDiff{},
+		Summary:   make(map[models.VersionRebuildDiffType]int),
+	}
+
+	var versions []models.ServiceVersion
+	err := c.exportCollection(ctx, services.ServiceVersionsCollection, "", func(line []byte) error {
+		var version models.ServiceVersion
+		if err := json.Unmarshal(line, &version); err != nil {
+			return fmt.Errorf("erro ao deserializar versão: %w", err)
+		}
+		versions = append(versions, version)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao exportar %s: %w", services.ServiceVersionsCollection, err)
+	}
+	report.TotalVersions = len(versions)
+
+	snapshots := latestSnapshots(versions)
+	report.TotalServices = len(snapshots)
+	ids := make([]string, 0, len(snapshots))
+	for id, snapshot := range snapshots {
+		if snapshot.latest.ChangeType == "delete" {
+			report.SkippedDeleted++
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	// A collection atual é lida antes da reconstrução: embeddings com o hash registrado na versão
+	// são reaproveitados em vez de gerados de novo
+	live, liveErr := c.exportLiveServices(ctx)
+	rebuilt := c.rebuildServices(ctx, ids, snapshots, live, opts, report)
+	c.verifyRebuild(snapshots, ids, live, liveErr, report)
+
+	if !opts.DryRun && len(rebuilt) > 0 {
+		if err := c.EnsureCollectionExists(opts.Target); err != nil {
+			return nil, fmt.Errorf("erro ao criar collection de destino: %w", err)
+		}
+		report.Rebuilt = c.importRebuilt(ctx, opts.Target, rebuilt, report)
+
+		collection, err := c.client.Collection(opts.Target).Retrieve(ctx)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("erro ao contar documentos em %s: %v", opts.Target, err))
+		} else if collection.NumDocuments != nil {
+			report.TargetDocuments = int(*collection.NumDocuments)
+		}
+	}
+
+	for _, diff := range report.Diffs {
+		report.Summary[diff.Type]++
+	}
+	sort.Strings(report.Errors)
+	report.DurationMs = time.Since(start).Milliseconds()
+	return report, nil
+}
+
+// latestSnapshots agrupa as versões por serviço, mantendo a de maior número e a data de
+// criação da primeira. Versões pendentes (criação ainda não confirmada) são ignoradas: um
+// serviço que só tem a versão pendente não chegou a existir na collection.
+func latestSnapshots(versions []models.ServiceVersion) map[string]*rebuildSnapshot {
+	snapshots := make(map[string]*rebuildSnapshot)
+	for _, version := range versions {
+		if version.ServiceID == "" || version.Pending {
+			continue
+		}
+		snapshot, ok := snapshots[version.ServiceID]
+		if !ok {
+			snapshots[version.ServiceID] = &rebuildSnapshot{latest: version, createdAt: version.CreatedAt}
+			continue
+		}
+		if version.VersionNumber > snapshot.latest.VersionNumber {
+			snapshot.latest = version
+		}
+		if version.CreatedAt < snapshot.createdAt {
+			snapshot.createdAt = version.CreatedAt
+		}
+	}
+	return snapshots
+}
+
+// rebuildServices monta os documentos dos serviços e regenera em paralelo os embeddings que não
+// podem ser reaproveitados da collection atual. Conteúdos de um único trecho são embedados em
+// lotes de opts.EmbedBatch por chamada ao Gemini; conteúdos longos, divididos em trechos, um a um.
+func (c *Client) rebuildServices(ctx context.Context, ids []string, snapshots map[string]*rebuildSnapshot, live map[string]map[string]interface{}, opts models.VersionRebuildOptions, report *models.VersionRebuildReport) []*models.PrefRioService {
+	rebuilt := make([]*models.PrefRioService, len(ids))
+
+	// O limite vale para cada chamada ao Gemini, inclusive novas tentativas e trechos de
+	// conteúdos longos
+	limiter := concurrency.NewLimiter(opts.RateLimit)
+	var requests atomic.Int64
+	embedBatch := func(ctx context.Context, texts []string) ([][]float32, error) {
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		requests.Add(1)
+		return c.GerarEmbeddings(ctx, texts)
+	}
+	embed := func(ctx context.Context, text string) ([]float32, error) {
+		embeddings, err := embedBatch(ctx, []string{text})
+		if err != nil {
+			return nil, err
+		}
+		return embeddings[0], nil
+	}
+
+	var mu sync.Mutex
+	pool, _ := concurrency.New(ctx, concurrency.Options{
+		Concurrency: opts.Concurrency,
+		Retries:     rebuildEmbeddingRetries,
+		RetryDelay:  rebuildEmbeddingRetryDelay,
+		Retryable:   isRetryableEmbeddingError,
+	})
+	setEmbedding := func(service *models.PrefRioService, embedding []float32) {
+		mu.Lock()
+		defer mu.Unlock()
+		service.Embedding = make([]float64, len(embedding))
+		for i, v := range embedding {
+			service.Embedding[i] = float64(v)
+		}
+		service.EmbeddingModel = c.embeddingModel
+		service.EmbeddingDim = len(embedding)
+		service.EmbeddingHash = utils.EmbeddingHash(service.EmbeddingModel, service.Embedding)
+		report.EmbeddingsGenerated++
+	}
+
+	var batch []*models.PrefRioService
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		pending := batch
+		batch = nil
+		return pool.Go(func(ctx context.Context) error {
+			texts := make([]string, len(pending))
+			for i, service := range pending {
+				texts[i] = service.SearchContent
+			}
+			embeddings, err := embedBatch(ctx, texts)
+			if err != nil {
+				batchIDs := make([]string, len(pending))
+				for i, service := range pending {
+					batchIDs[i] = service.ID
+				}
+				return fmt.Errorf("%s: erro ao gerar embeddings: %w", strings.Join(batchIDs, ", "), err)
+			}
+			for i, service := range pending {
+				setEmbedding(service, embeddings[i])
+			}
+			return nil
+		})
+	}
+
+	for i, id := range ids {
+		snapshot := snapshots[id]
+		service := services.ServiceFromVersion(id, &snapshot.latest)
+		service.CreatedAt = snapshot.createdAt
+		service.Slug = utils.GenerateSlug(service.NomeServico, id)
+		service.DeepLinks = c.deepLinks.Build(service)
+		service.SearchContent = c.generateSearchContent(service)
+		service.NomeServicoFonetico = utils.PhoneticKey(service.NomeServico)
+		rebuilt[i] = service
+
+		if opts.DryRun {
+			continue
+		}
+		if embedding := storedEmbedding(live[id], service.SearchContent, c.embeddingModel, snapshot.latest.EmbeddingHash); embedding != nil {
+			service.Embedding = embedding
+			service.EmbeddingModel = c.embeddingModel
+			service.EmbeddingDim = len(embedding)
+			service.EmbeddingHash = snapshot.latest.EmbeddingHash
+			report.EmbeddingsReused++
+			continue
+		}
+		if opts.SkipEmbeddings {
+			continue
+		}
+
+		var err error
+		if opts.EmbedBatch > 1 && utf8.RuneCountInString(service.SearchContent) <= embeddingChunkSize {
+			if batch = append(batch, service); len(batch) >= opts.EmbedBatch {
+				err = flush()
+			}
+		} else {
+			err = pool.Go(func(ctx context.Context) error {
+				embedding, err := embedServiceContent(ctx, service.SearchContent, embed)
+				if err != nil {
+					return fmt.Errorf("%s: erro ao gerar embedding: %v", service.ID, err)
+				}
+				setEmbedding(service, embedding)
+				return nil
+			})
+		}
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("rebuild interrompido: %v", err))
+			break
+		}
+	}
+	if err := flush(); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("rebuild interrompido: %v", err))
+	}
+	pool.Wait()
+	report.EmbeddingRetries = pool.Progress().Retries
+	report.EmbeddingRequests = int(requests.Load())
+	for _, err := range pool.Errors() {
+		report.Errors = append(report.Errors, err.Error())
+	}
+	return rebuilt
+}
+
+// exportLiveServices lê os documentos da collection atual por ID. Se a collection não existe
+// mais, retorna um mapa vazio.
+func (c *Client) exportLiveServices(ctx context.Context) (map[string]map[string]interface{}, error) {
+	live := make(map[string]map[string]interface{})
+	err := c.exportCollection(ctx, services.PrefRioServicesCollection, "", func(line []byte) error {
+		var doc map[string]interface{}
+		if err := json.Unmarshal(line, &doc); err != nil {
+			return fmt.Errorf("erro ao deserializar documento: %w", err)
+		}
+		if id, _ := doc["id"].(string); id != "" {
+			live[id] = doc
+		}
+		return nil
+	})
+	if err != nil && !services.IsNotFoundError(err) {
+		return nil, err
+	}
+	return live, nil
+}
+
+// verifyRebuild compara os serviços reconstruídos com a collection atual. Se a collection
+// atual não existe mais, todos os serviços constam como ausentes.
+func (c *Client) verifyRebuild(snapshots map[string]*rebuildSnapshot, ids []string, live map[string]map[string]interface{}, liveErr error, report *models.VersionRebuildReport) {
+	if liveErr != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("verificação não realizada: erro ao exportar %s: %v", services.PrefRioServicesCollection, liveErr))
+		return
+	}
+
+	rebuilt := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		rebuilt[id] = true
+		latest := &snapshots[id].latest
+		doc, ok := live[id]
+		if !ok {
+			report.Diffs = append(report.Diffs, models.VersionRebuildDiff{Type: models.RebuildMissingInCollection, ServiceID: id, NomeServico: latest.NomeServico})
+			continue
+		}
+		if fields := snapshotDiff(c.versionService, doc, latest); len(fields) > 0 {
+			report.Diffs = append(report.Diffs, models.VersionRebuildDiff{Type: models.RebuildFieldMismatch, ServiceID: id, NomeServico: latest.NomeServico, Fields: fields})
+		}
+	}
+
+	liveIDs := make([]string, 0, len(live))
+	for id := range live {
+		liveIDs = append(liveIDs, id)
+	}
+	sort.Strings(liveIDs)
+	for _, id := range liveIDs {
+		if rebuilt[id] {
+			continue
+		}
+		nome, _ := live[id]["nome_servico"].(string)
+		report.Diffs = append(report.Diffs, models.VersionRebuildDiff{Type: models.RebuildMissingInVersions, ServiceID: id, NomeServico: nome})
+	}
+}
+
+// snapshotDiff retorna os campos de conteúdo em que o documento difere da versão. Listas
+// vazias e ausentes são equivalentes (os snapshots omitem campos vazios).
+func snapshotDiff(vs *services.VersionService, doc map[string]interface{}, latest *models.ServiceVersion) []string {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return []string{"documento ilegível"}
+	}
+	var current models.ServiceVersion
+	if err := json.Unmarshal(data, &current); err != nil {
+		return []string{"documento ilegível"}
+	}
+
+	var fields []string
+	for _, change := range vs.ComputeDiff(&current, latest) {
+		if isEmptyList(change.OldValue) && isEmptyList(change.NewValue) {
+			continue
+		}
+		fields = append(fields, change.FieldName)
+	}
+	return fields
+}
+
+func isEmptyList(value interface{}) bool {
+	v := reflect.ValueOf(value)
+	return v.Kind() == reflect.Slice && v.Len() == 0
+}
+
+// importRebuilt grava os documentos em lotes (upsert) e retorna quantos foram gravados
+func (c *Client) importRebuilt(ctx context.Context, target string, rebuilt []*models.PrefRioService, report *models.VersionRebuildReport) int {
+	written := 0
+	for start := 0; start < len(rebuilt); start += rebuildImportBatch {
+		batch := rebuilt[start:min(start+rebuildImportBatch, len(rebuilt))]
+		docs := make([]interface{}, 0, len(batch))
+		for _, service := range batch {
+			doc, err := c.structToMap(service)
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: erro ao converter documento: %v", service.ID, err))
+				continue
+			}
+			docs = append(docs, doc)
+		}
+		if len(docs) == 0 {
+			continue
+		}
+
+		results, err := c.client.Collection(target).Documents().Import(ctx, docs, &api.ImportDocumentsParams{
+			Action: pointer.Any(api.Upsert),
+		})
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("erro ao gravar lote de %d documentos: %v", len(docs), err))
+			continue
+		}
+		for i, result := range results {
+			if result.Success {
+				written++
+				continue
+			}
+			id := ""
+			if i < len(docs) {
+				id, _ = docs[i].(map[string]interface{})["id"].(string)
+			}
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: erro ao gravar documento: %s", id, result.Error))
+		}
+	}
+	return written
+}
+
+// exportCollection exporta os documentos da collection, entregando cada linha JSONL a fn.
+// includeFields limita os campos exportados (vazio exporta todos).
+func (c *Client) exportCollection(ctx context.Context, collection, includeFields string, fn func(line []byte) error) error {
+	params := &api.ExportDocumentsParams{}
+	if includeFields != "" {
+		params.IncludeFields = pointer.String(includeFields)
+	}
+	body, err := c.client.Collection(collection).Documents().Export(ctx, params)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 50*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		if err := fn(scanner.Bytes()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
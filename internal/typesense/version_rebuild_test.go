@@ -0,0 +1,88 @@
+package typesense
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+	"google.golang.org/genai"
+)
+
+func TestLatestSnapshots(t *testing.T) {
+	snapshots := latestSnapshots([]models.ServiceVersion{
+		{ServiceID: "a", VersionNumber: 2, CreatedAt: 200, NomeServico: "A v2"},
+		{ServiceID: "a", VersionNumber: 1, CreatedAt: 100, NomeServico: "A v1"},
+		{ServiceID: "a", VersionNumber: 3, CreatedAt: 300, NomeServico: "A v3"},
+		{ServiceID: "b", VersionNumber: 1, CreatedAt: 50, ChangeType: "create"},
+		{ServiceID: "b", VersionNumber: 2, CreatedAt: 60, ChangeType: "delete"},
+		{ServiceID: "c", VersionNumber: 1, CreatedAt: 70, ChangeType: "create", Pending: true},
+		{VersionNumber: 1},
+	})
+
+	if len(snapshots) != 2 {
+		t.Fatalf("esperados 2 serviços, obtidos %d", len(snapshots))
+	}
+	if a := snapshots["a"]; a.latest.NomeServico != "A v3" || a.createdAt != 100 {
+		t.Errorf("snapshot inesperado para a: %+v", a)
+	}
+	if b := snapshots["b"]; b.latest.ChangeType != "delete" || b.createdAt != 50 {
+		t.Errorf("snapshot inesperado para b: %+v", b)
+	}
+	if c, ok := snapshots["c"]; ok {
+		t.Errorf("serviço só com versão pendente não deveria ser reconstruído: %+v", c)
+	}
+}
+
+func TestSnapshotDiff(t *testing.T) {
+	vs := services.NewVersionService(nil)
+	latest := &models.ServiceVersion{
+		ServiceID:   "a",
+		NomeServico: "Segunda via do IPTU",
+		OrgaoGestor: []string{"SMFP"},
+		Resumo:      "Emissão da segunda via",
+		Status:      1,
+	}
+
+	doc := map[string]interface{}{
+		"id":                     "a",
+		"nome_servico":           "Segunda via do IPTU",
+		"orgao_gestor":           []interface{}{"SMFP"},
+		"resumo":                 "Emissão da segunda via",
+		"documentos_necessarios": []interface{}{},
+		"status":                 1,
+		"embedding":              []interface{}{0.1, 0.2},
+	}
+	if fields := snapshotDiff(vs, doc, latest); len(fields) != 0 {
+		t.Errorf("documento igual à versão não deveria divergir: %v", fields)
+	}
+
+	doc["resumo"] = "Resumo corrompido"
+	doc["status"] = 0
+	if fields := snapshotDiff(vs, doc, latest); !reflect.DeepEqual(fields, []string{"resumo", "status"}) {
+		t.Errorf("campos divergentes inesperados: %v", fields)
+	}
+}
+
+func TestIsRetryableEmbeddingError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{genai.APIError{Code: 429, Status: "RESOURCE_EXHAUSTED"}, true},
+		{fmt.Errorf("a, b: erro ao gerar embeddings: %w", genai.APIError{Code: 503}), true},
+		{genai.APIError{Code: 400, Status: "INVALID_ARGUMENT"}, false},
+		{genai.APIError{Code: 403, Status: "PERMISSION_DENIED"}, false},
+		{fmt.Errorf("erro ao gerar embedding: %w", context.DeadlineExceeded), true},
+		{context.Canceled, false},
+		{errors.New("cliente Gemini não inicializado"), false},
+	}
+	for _, tt := range tests {
+		if got := isRetryableEmbeddingError(tt.err); got != tt.want {
+			t.Errorf("isRetryableEmbeddingError(%v) = %v, esperado %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package typesense
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/jobs"
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+	"github.com/prefeitura-rio/app-busca-search/internal/services"
+)
+
+// versionReconcilePlan ações da reconciliação: versões pendentes a confirmar e a descartar (por
+// ID da versão) e serviços sem histórico que recebem a versão 1
+type versionReconcilePlan struct {
+	confirm  []string
+	discard  []string
+	backfill []string
+}
+
+// ReconcileVersions resolve as inconsistências entre os serviços e o histórico de versões:
+// versões pendentes abandonadas são confirmadas se o serviço foi criado e removidas se não foi;
+// serviços sem nenhuma versão (criados antes do versionamento) recebem a versão 1 a partir do
+// estado atual
+func (c *Client) ReconcileVersions(ctx context.Context) (*models.VersionReconcileResult, error) {
+	var versions []models.ServiceVersion
+	err := c.exportCollection(ctx, services.ServiceVersionsCollection, "id,service_id,created_at,pending", func(line []byte) error {
+		var version models.ServiceVersion
+		if err := json.Unmarshal(line, &version); err != nil {
+			return fmt.Errorf("erro ao deserializar versão: %w", err)
+		}
+		versions = append(versions, version)
+		return nil
+	})
+	if err != nil && !services.IsNotFoundError(err) {
+		return nil, fmt.Errorf("erro ao exportar %s: %w", services.ServiceVersionsCollection, err)
+	}
+
+	live := make(map[string]bool)
+	err = c.exportCollection(ctx, services.PrefRioServicesCollection, "id", func(line []byte) error {
+		var doc struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(line, &doc); err != nil {
+			return fmt.Errorf("erro ao deserializar documento: %w", err)
+		}
+		live[doc.ID] = true
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao exportar %s: %w", services.PrefRioServicesCollection, err)
+	}
+
+	plan := planVersionReconcile(versions, live, time.Now().Add(-services.VersionPendingGrace).Unix())
+	result := &models.VersionReconcileResult{}
+
+	for _, versionID := range plan.confirm {
+		if err := c.versionService.ConfirmVersion(ctx, versionID); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		result.Confirmed++
+	}
+	for _, versionID := range plan.discard {
+		if err := c.versionService.DiscardVersion(ctx, versionID); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		result.Discarded++
+	}
+	for _, serviceID := range plan.backfill {
+		service, err := c.GetPrefRioService(ctx, serviceID)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", serviceID, err))
+			continue
+		}
+		_, err = c.versionService.CaptureVersion(ctx, service, "create", service.Autor, "",
+			"Versão inicial (criada pela reconciliação para serviço sem histórico)", nil)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", serviceID, err))
+			continue
+		}
+		result.Backfilled++
+	}
+
+	return result, nil
+}
+
+// StartVersionReconcileRoutine agenda a reconciliação do histórico de versões, executada por
+// uma única réplica a cada hora (job versions-reconcile)
+func (c *Client) StartVersionReconcileRoutine(runner *jobs.Runner) error {
+	return runner.Register("versions-reconcile", "20 * * * *", 15*time.Minute, func(ctx context.Context) error {
+		result, err := c.ReconcileVersions(ctx)
+		if err != nil {
+			return err
+		}
+		if result.Confirmed+result.Discarded+result.Backfilled > 0 || len(result.Errors) > 0 {
+			log.Printf("[Versions] Reconciliação: %d confirmadas, %d descartadas, %d serviços sem histórico, %d erros",
+				result.Confirmed, result.Discarded, result.Backfilled, len(result.Errors))
+		}
+		if len(result.Errors) > 0 {
+			return fmt.Errorf("%d falhas na reconciliação, a primeira: %s", len(result.Errors), result.Errors[0])
+		}
+		return nil
+	})
+}
+
+// planVersionReconcile decide as ações da reconciliação. Versões pendentes criadas depois de
+// cutoff pertencem a criações em andamento e não são tocadas.
+func planVersionReconcile(versions []models.ServiceVersion, live map[string]bool, cutoff int64) versionReconcilePlan {
+	var plan versionReconcilePlan
+	versioned := make(map[string]bool)
+	for _, version := range versions {
+		switch {
+		case !version.Pending:
+			versioned[version.ServiceID] = true
+		case version.CreatedAt > cutoff:
+			versioned[version.ServiceID] = true
+		case live[version.ServiceID]:
+			versioned[version.ServiceID] = true
+			plan.confirm = append(plan.confirm, version.ID)
+		default:
+			plan.discard = append(plan.discard, version.ID)
+		}
+	}
+
+	for serviceID := range live {
+		if !versioned[serviceID] {
+			plan.backfill = append(plan.backfill, serviceID)
+		}
+	}
+	sort.Strings(plan.confirm)
+	sort.Strings(plan.discard)
+	sort.Strings(plan.backfill)
+	return plan
+}
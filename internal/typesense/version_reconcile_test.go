@@ -0,0 +1,30 @@
+package typesense
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prefeitura-rio/app-busca-search/internal/models"
+)
+
+func TestPlanVersionReconcile(t *testing.T) {
+	versions := []models.ServiceVersion{
+		{ID: "v-a1", ServiceID: "a", CreatedAt: 10},
+		{ID: "v-b1", ServiceID: "b", CreatedAt: 10, Pending: true},  // criado, confirmação falhou
+		{ID: "v-c1", ServiceID: "c", CreatedAt: 10, Pending: true},  // documento não gravado
+		{ID: "v-d1", ServiceID: "d", CreatedAt: 100, Pending: true}, // criação em andamento
+		{ID: "v-x1", ServiceID: "x", CreatedAt: 10},                 // serviço removido
+	}
+	live := map[string]bool{"a": true, "b": true, "d": true, "legado": true, "antigo": true}
+
+	plan := planVersionReconcile(versions, live, 50)
+
+	expected := versionReconcilePlan{
+		confirm:  []string{"v-b1"},
+		discard:  []string{"v-c1"},
+		backfill: []string{"antigo", "legado"},
+	}
+	if !reflect.DeepEqual(plan, expected) {
+		t.Errorf("esperado %+v, obtido %+v", expected, plan)
+	}
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+)
+
+// EmbeddingHash identifica o embedding pelo conteúdo: SHA-256 do nome do modelo e dos bytes de
+// cada componente (float64 little-endian). Vetores iguais do mesmo modelo têm sempre o mesmo
+// hash, permitindo reaproveitar o vetor em vez de gerá-lo de novo. Vazio sem embedding.
+func EmbeddingHash(model string, embedding []float64) string {
+	if len(embedding) == 0 {
+		return ""
+	}
+
+	h := sha256.New()
+	h.Write([]byte(model))
+	h.Write([]byte{0})
+	buf := make([]byte, 8)
+	for _, value := range embedding {
+		binary.LittleEndian.PutUint64(buf, math.Float64bits(value))
+		h.Write(buf)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,23 @@
+package utils
+
+import "testing"
+
+func TestEmbeddingHash(t *testing.T) {
+	embedding := []float64{0.1, -0.25, 3}
+	hash := EmbeddingHash("gemini-embedding-001", embedding)
+	if len(hash) != 64 {
+		t.Fatalf("hash inesperado: %q", hash)
+	}
+	if EmbeddingHash("gemini-embedding-001", []float64{0.1, -0.25, 3}) != hash {
+		t.Error("o mesmo vetor deve gerar o mesmo hash")
+	}
+	if EmbeddingHash("text-embedding-004", embedding) == hash {
+		t.Error("o modelo deve fazer parte do hash")
+	}
+	if EmbeddingHash("gemini-embedding-001", []float64{0.1, -0.25, 3.0000001}) == hash {
+		t.Error("vetores diferentes devem gerar hashes diferentes")
+	}
+	if EmbeddingHash("gemini-embedding-001", nil) != "" {
+		t.Error("sem embedding o hash deve ser vazio")
+	}
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/gomarkdown/markdown"
 	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/html"
+	"github.com/gomarkdown/markdown/parser"
 )
 
 // StripMarkdown removes all markdown formatting from text and returns plain text
@@ -28,6 +30,21 @@ func StripMarkdown(text string) string {
 	return result
 }
 
+// ParseMarkdown sanitizes the text (see SanitizeMarkdown) and parses it with the extensions
+// used to render service content
+func ParseMarkdown(text string) ast.Node {
+	return parser.NewWithExtensions(parser.CommonExtensions).Parse([]byte(SanitizeMarkdown(text)))
+}
+
+// RenderMarkdownHTML renders a parsed document to HTML. Raw HTML is skipped and links open
+// in a new tab with rel="noopener".
+func RenderMarkdownHTML(doc ast.Node) string {
+	renderer := html.NewRenderer(html.RendererOptions{
+		Flags: html.SkipHTML | html.HrefTargetBlank | html.NoopenerLinks,
+	})
+	return strings.TrimSpace(string(markdown.Render(doc, renderer)))
+}
+
 // StripMarkdownArray processes an array of markdown strings
 func StripMarkdownArray(texts []string) []string {
 	if texts == nil {
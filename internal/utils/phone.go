@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+)
+
+// DefaultAreaCode DDD assumido para telefones informados sem DDD
+const DefaultAreaCode = "21"
+
+// ErrInvalidPhone indica telefone que não pode ser normalizado
+var ErrInvalidPhone = errors.New("telefone inválido")
+
+// NormalizePhone normaliza telefones para E.164 (+5521999999999). Números brasileiros são
+// aceitos com ou sem código do país, DDD (assume DefaultAreaCode) e zero de prefixo; números
+// iniciados por + são considerados internacionais. Números de utilidade pública de 3 a 5
+// dígitos (ex.: 1746) não têm representação E.164 e são mantidos apenas com os dígitos.
+func NormalizePhone(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	international := strings.HasPrefix(raw, "+")
+
+	var b strings.Builder
+	for _, r := range raw {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case strings.ContainsRune(" -.()/+", r):
+		default:
+			return "", ErrInvalidPhone
+		}
+	}
+	digits := b.String()
+
+	if international {
+		if len(digits) < 8 || len(digits) > 15 {
+			return "", ErrInvalidPhone
+		}
+		return "+" + digits, nil
+	}
+
+	if len(digits) >= 3 && len(digits) <= 5 {
+		return digits, nil
+	}
+
+	digits = strings.TrimLeft(digits, "0")
+	switch {
+	case strings.HasPrefix(digits, "55") && (len(digits) == 12 || len(digits) == 13):
+		return "+" + digits, nil
+	case len(digits) == 10 || len(digits) == 11:
+		return "+55" + digits, nil
+	case len(digits) == 8 || len(digits) == 9:
+		return "+55" + DefaultAreaCode + digits, nil
+	}
+	return "", ErrInvalidPhone
+}
@@ -0,0 +1,54 @@
+package utils
+
+import "testing"
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"(21) 98888-7777", "+5521988887777"},
+		{"021 3333-4444", "+552133334444"},
+		{"+55 21 98888-7777", "+5521988887777"},
+		{"5521988887777", "+5521988887777"},
+		{"98888-7777", "+5521988887777"},
+		{"0800 123 4567", "+558001234567"},
+		{"1746", "1746"},
+		{"+1 415 555 2671", "+14155552671"},
+	}
+	for _, tt := range tests {
+		got, err := NormalizePhone(tt.input)
+		if err != nil || got != tt.expected {
+			t.Errorf("NormalizePhone(%q) = %q, %v; esperado %q", tt.input, got, err, tt.expected)
+		}
+	}
+
+	for _, invalid := range []string{"", "12", "abc", "1234567", "+123"} {
+		if _, err := NormalizePhone(invalid); err == nil {
+			t.Errorf("NormalizePhone(%q) deveria falhar", invalid)
+		}
+	}
+}
+
+func TestEnforceHTTPS(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"https://carioca.rio/x", "https://carioca.rio/x"},
+		{"http://carioca.rio/x?a=1", "https://carioca.rio/x?a=1"},
+		{"www.rio.rj.gov.br/iptu", "https://www.rio.rj.gov.br/iptu"},
+	}
+	for _, tt := range tests {
+		got, err := EnforceHTTPS(tt.input)
+		if err != nil || got != tt.expected {
+			t.Errorf("EnforceHTTPS(%q) = %q, %v; esperado %q", tt.input, got, err, tt.expected)
+		}
+	}
+
+	for _, invalid := range []string{"", "/servicos/iptu", "mailto:a@rio.rj.gov.br", "ftp://rio.rj.gov.br", "https://localhost"} {
+		if _, err := EnforceHTTPS(invalid); err == nil {
+			t.Errorf("EnforceHTTPS(%q) deveria falhar", invalid)
+		}
+	}
+}
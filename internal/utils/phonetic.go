@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// PhoneticKey gera a chave fonética (no estilo do Metaphone-BR) de cada palavra do texto,
+// separadas por espaço. Grafias que soam igual em português geram a mesma chave:
+// "alvara"/"auvara", "certidao"/"sertidao", "chuva"/"xuva", "quiosque"/"kiosque".
+// Vogais só são mantidas no início da palavra e s/z/ç se confundem.
+func PhoneticKey(text string) string {
+	text = strings.ReplaceAll(strings.ToLower(text), "ç", "s")
+	text = strings.ReplaceAll(NormalizarCategoria(text), "-", "")
+
+	words := strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	keys := make([]string, 0, len(words))
+	for _, word := range words {
+		if key := phoneticWordKey([]rune(strings.TrimLeft(word, "h"))); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return strings.Join(keys, " ")
+}
+
+func phoneticWordKey(w []rune) string {
+	at := func(i int) rune {
+		if i < 0 || i >= len(w) {
+			return 0
+		}
+		return w[i]
+	}
+	isVowel := func(i int) bool { return strings.ContainsRune("aeiouy", at(i)) }
+	isFront := func(i int) bool { return strings.ContainsRune("eiy", at(i)) }
+
+	var b strings.Builder
+	last := ""
+	for i := 0; i < len(w); i++ {
+		code := ""
+		switch c := w[i]; c {
+		case 'a', 'e', 'i', 'o', 'u', 'y':
+			if i == 0 {
+				code = strings.ToUpper(strings.ReplaceAll(string(c), "y", "i"))
+			}
+		case 'b', 'd', 'f', 'v', 'r', 'j':
+			code = strings.ToUpper(string(c))
+		case 'p':
+			code = "P"
+			if at(i+1) == 'h' {
+				code = "F"
+				i++
+			}
+		case 't':
+			code = "T"
+			if at(i+1) == 'h' {
+				i++
+			}
+		case 'c':
+			switch {
+			case at(i+1) == 'h':
+				code = "X"
+				i++
+			case isFront(i + 1):
+				code = "S"
+			default:
+				code = "K"
+			}
+		case 'g':
+			switch {
+			case isFront(i + 1):
+				code = "J"
+			case at(i+1) == 'u' && isFront(i+2):
+				code = "G"
+				i++
+			default:
+				code = "G"
+			}
+		case 'k':
+			code = "K"
+		case 'q':
+			code = "K"
+			if at(i+1) == 'u' {
+				i++
+			}
+		case 'l':
+			switch {
+			case at(i+1) == 'h':
+				code = "L"
+				i++
+			case isVowel(i + 1):
+				code = "L"
+			case i == 0:
+				code = "L"
+			}
+			// l no fim da sílaba soa como u ("alvara" = "auvara") e é descartado como vogal
+		case 'm':
+			code = "M"
+			if !isVowel(i + 1) {
+				code = "N" // nasal: "campo" = "canpo"
+			}
+		case 'n':
+			code = "N"
+			if at(i+1) == 'h' {
+				i++
+			}
+		case 's', 'z':
+			code = "S"
+			switch {
+			case c == 's' && at(i+1) == 'h':
+				code = "X"
+				i++
+			case c == 's' && at(i+1) == 'c' && isFront(i+2):
+				i++
+			}
+		case 'x':
+			code = "X"
+			if at(i+1) == 'c' && isFront(i+2) {
+				code = "S"
+				i++
+			}
+		case 'w':
+			code = "V"
+		default:
+			if unicode.IsDigit(c) {
+				code = string(c)
+			}
+		}
+
+		// Consoantes repetidas ("ss", "rr", "sc") não mudam a chave
+		if code != "" && code != last {
+			b.WriteString(code)
+		}
+		last = code
+	}
+	return b.String()
+}
@@ -0,0 +1,33 @@
+package utils
+
+import "testing"
+
+func TestPhoneticKeyMatchesMisspellings(t *testing.T) {
+	pairs := [][2]string{
+		{"Alvará", "auvara"},
+		{"Certidão", "sertidao"},
+		{"chuva", "xuva"},
+		{"quiosque", "kiosque"},
+		{"Habite-se", "abitese"},
+		{"acessar", "asessar"},
+		{"gestante", "jestante"},
+		{"licença", "lisensa"},
+		{"campo", "canpo"},
+		{"exceção", "esesão"},
+	}
+	for _, pair := range pairs {
+		a, b := PhoneticKey(pair[0]), PhoneticKey(pair[1])
+		if a != b || a == "" {
+			t.Errorf("PhoneticKey(%q) = %q, PhoneticKey(%q) = %q", pair[0], a, pair[1], b)
+		}
+	}
+}
+
+func TestPhoneticKeyDistinguishesWords(t *testing.T) {
+	if PhoneticKey("alvará de obra") == PhoneticKey("alvará de festa") {
+		t.Error("palavras diferentes deveriam gerar chaves diferentes")
+	}
+	if got := PhoneticKey("Segunda via do IPTU 2024"); got != "SGND V D IPT 2024" {
+		t.Errorf("chave inesperada: %q", got)
+	}
+}
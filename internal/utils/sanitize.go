@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	// dangerousBlocks elementos removidos junto com o conteúdo
+	dangerousBlocks = []*regexp.Regexp{
+		regexp.MustCompile(`(?is)<script\b.*?(</script\s*>|$)`),
+		regexp.MustCompile(`(?is)<style\b.*?(</style\s*>|$)`),
+		regexp.MustCompile(`(?is)<iframe\b.*?(</iframe\s*>|$)`),
+		regexp.MustCompile(`(?is)<object\b.*?(</object\s*>|$)`),
+		regexp.MustCompile(`(?is)<!--.*?(-->|$)`),
+	}
+
+	// htmlTag qualquer tag HTML; autolinks do markdown (<https://...>, <email@...>) não casam
+	htmlTag = regexp.MustCompile(`(?i)</?[a-z][a-z0-9-]*(\s[^>]*)?/?>`)
+
+	// unsafeLinkTarget destino de link/imagem markdown com esquema executável (aceita um nível de
+	// parênteses no destino, ex.: javascript:alert(1))
+	unsafeLinkTarget = regexp.MustCompile(`(?i)\]\(\s*<?\s*(javascript|vbscript|data)\s*:[^()]*(\([^()]*\)[^()]*)*\)`)
+)
+
+// SanitizeMarkdown remove HTML (scripts, estilos, iframes e demais tags) e links com esquemas
+// executáveis (javascript:, vbscript:, data:) de um texto markdown, preservando a formatação
+// markdown. Os textos são renderizados pelos portais e apps a partir do índice.
+func SanitizeMarkdown(text string) string {
+	if text == "" || !strings.ContainsAny(text, "<]") {
+		return text
+	}
+
+	for _, block := range dangerousBlocks {
+		text = block.ReplaceAllString(text, "")
+	}
+	text = htmlTag.ReplaceAllString(text, "")
+	text = unsafeLinkTarget.ReplaceAllString(text, "](#)")
+
+	return strings.TrimSpace(text)
+}
+
+// SanitizeMarkdownArray aplica SanitizeMarkdown em cada item
+func SanitizeMarkdownArray(texts []string) []string {
+	for i, text := range texts {
+		texts[i] = SanitizeMarkdown(text)
+	}
+	return texts
+}
+
+// IsSafeURL indica se a URL pode ser exibida como link: http(s), mailto, tel ou caminho relativo
+func IsSafeURL(raw string) bool {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return true
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "", "http", "https", "mailto", "tel":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,43 @@
+package utils
+
+import "testing"
+
+func TestSanitizeMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"markdown preservado", "**Atenção**: leve o [RG](https://rio.rj.gov.br/rg)", "**Atenção**: leve o [RG](https://rio.rj.gov.br/rg)"},
+		{"script removido", "Resumo<script>alert('x')</script> do serviço", "Resumo do serviço"},
+		{"script sem fechamento", "Resumo <SCRIPT src=x>alert(1)", "Resumo"},
+		{"tags removidas", `<p onclick="x()">Texto <b>forte</b></p>`, "Texto forte"},
+		{"iframe removido", `Veja <iframe src="https://evil"></iframe>aqui`, "Veja aqui"},
+		{"comentário removido", "Antes<!-- <img src=x> -->depois", "Antesdepois"},
+		{"link javascript", "[clique](javascript:alert(1)) agora", "[clique](#) agora"},
+		{"imagem data", "![x]( data:text/html;base64,PHNjcmlwdD4=)", "![x](#)"},
+		{"autolink preservado", "Acesse <https://1746.rio> ou <ouvidoria@rio.rj.gov.br>", "Acesse <https://1746.rio> ou <ouvidoria@rio.rj.gov.br>"},
+		{"comparação preservada", "Idade < 18 anos e renda > 2 salários", "Idade < 18 anos e renda > 2 salários"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeMarkdown(tt.input); got != tt.expected {
+				t.Errorf("SanitizeMarkdown(%q) = %q, esperado %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsSafeURL(t *testing.T) {
+	for _, raw := range []string{"", "https://www.rio.rj.gov.br", "/servicos/iptu", "mailto:a@rio.rj.gov.br", "tel:1746"} {
+		if !IsSafeURL(raw) {
+			t.Errorf("%q deveria ser aceita", raw)
+		}
+	}
+	for _, raw := range []string{"javascript:alert(1)", " JavaScript:alert(1)", "data:text/html,x", "vbscript:x"} {
+		if IsSafeURL(raw) {
+			t.Errorf("%q deveria ser recusada", raw)
+		}
+	}
+}
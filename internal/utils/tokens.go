@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// legalReferencePattern referências a atos normativos: "Lei 1234/2020", "Lei nº 1.234, de 2020",
+// "Decreto Municipal nº 45.678 de 1º de janeiro de 2019", "LC 270/24"
+var legalReferencePattern = regexp.MustCompile(`(?i)\b(lei complementar|lc|decreto-lei|decreto|lei|resolu[cç][aã]o|portaria|delibera[cç][aã]o)\s*(?:(?:municipal|estadual|federal)\s+)?(?:n[º°o]?\.?\s*)?(\d{1,3}(?:\.\d{3})+|\d+)(?:\s*/\s*(\d{4}|\d{2})\b|,?\s+de\s+(?:\d{1,2}[º°]?\s+de\s+[[:alpha:]çã]+\s+de\s+)?(\d{4})\b)?`)
+
+// separatedNumberPattern números com separadores, como protocolos ("01/123.456/2020",
+// "2020 - 123456")
+var separatedNumberPattern = regexp.MustCompile(`\b\d+(?:\s*[./-]\s*\d+)+\b`)
+
+// hyphenatedPattern termos com hífen ("habite-se", "pré-escola")
+var hyphenatedPattern = regexp.MustCompile(`[[:alpha:]À-ÿ]+(?:-[[:alpha:]À-ÿ]+)+`)
+
+// legalReferenceTypes prefixo canônico de cada tipo de ato
+var legalReferenceTypes = map[string]string{
+	"lei complementar": "lc",
+	"lc":               "lc",
+	"decreto-lei":      "dl",
+	"decreto":          "decreto",
+	"lei":              "lei",
+	"resolucao":        "resolucao",
+	"portaria":         "portaria",
+	"deliberacao":      "deliberacao",
+}
+
+// IndexTokens gera os tokens canônicos do texto para o search_content, de forma que buscas
+// encontrem o documento independentemente da formatação:
+//   - referências legais viram "lei1234" e, com ano, "lei12342020"
+//   - números com separadores viram só dígitos ("2020 - 123456" → "2020123456")
+//   - termos com hífen também são indexados separados ("habite-se" → "habite se")
+//
+// Tokens já presentes no texto não são repetidos.
+func IndexTokens(text string) string {
+	existing := make(map[string]bool)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		existing[word] = true
+	}
+
+	var tokens []string
+	add := func(token string) {
+		words := strings.Fields(token)
+		missing := false
+		for _, word := range words {
+			if !existing[word] {
+				missing = true
+				existing[word] = true
+			}
+		}
+		if missing {
+			tokens = append(tokens, token)
+		}
+	}
+
+	for _, match := range legalReferencePattern.FindAllStringSubmatch(text, -1) {
+		for _, token := range legalReferenceTokens(match) {
+			add(token)
+		}
+	}
+	for _, number := range separatedNumberPattern.FindAllString(text, -1) {
+		add(digitsOnly(number))
+	}
+	for _, term := range hyphenatedPattern.FindAllString(text, -1) {
+		add(strings.ToLower(strings.ReplaceAll(term, "-", " ")))
+	}
+
+	return strings.Join(tokens, " ")
+}
+
+// NormalizeQueryTokens aplica à query as mesmas regras da indexação: referências legais são
+// substituídas pelos tokens canônicos e números com separadores por seus dígitos
+func NormalizeQueryTokens(query string) string {
+	query = legalReferencePattern.ReplaceAllStringFunc(query, func(reference string) string {
+		return strings.Join(legalReferenceTokens(legalReferencePattern.FindStringSubmatch(reference)), " ")
+	})
+	return separatedNumberPattern.ReplaceAllStringFunc(query, digitsOnly)
+}
+
+// legalReferenceTokens tokens canônicos de uma referência: tipo+número e, se houver ano,
+// tipo+número+ano (anos com dois dígitos são expandidos)
+func legalReferenceTokens(match []string) []string {
+	kind := legalReferenceTypes[strings.Join(strings.Fields(NormalizarCategoria(match[1])), " ")]
+	number := strings.TrimLeft(digitsOnly(match[2]), "0")
+	if kind == "" || number == "" {
+		return nil
+	}
+
+	tokens := []string{kind + number}
+	year := match[3]
+	if year == "" {
+		year = match[4]
+	}
+	if len(year) == 2 {
+		y, _ := strconv.Atoi(year)
+		century := 2000
+		if y > time.Now().Year()%100 {
+			century = 1900
+		}
+		year = strconv.Itoa(century + y)
+	}
+	if year != "" {
+		tokens = append(tokens, kind+number+year)
+	}
+	return tokens
+}
+
+func digitsOnly(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
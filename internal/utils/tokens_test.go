@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLegalReferencesMatchAcrossFormats(t *testing.T) {
+	indexed := IndexTokens("Conforme a Lei nº 1.234, de 15 de março de 2020, e o Decreto Municipal 45.678/19.")
+	for _, query := range []string{"Lei 1234/2020", "lei n° 1234 de 2020", "LEI 1.234/20", "lei 1234", "decreto 45678/2019"} {
+		for _, token := range strings.Fields(NormalizeQueryTokens(query)) {
+			if !strings.Contains(" "+indexed+" ", " "+token+" ") {
+				t.Errorf("token %q da query %q não indexado em %q", token, query, indexed)
+			}
+		}
+	}
+}
+
+func TestIndexTokensNumbersAndHyphens(t *testing.T) {
+	got := IndexTokens("Protocolo 01/123.456/2020 ou 2020 - 987654. Habite-se e pré-escola.")
+	want := "011234562020 2020987654 habite se pré escola"
+	if got != want {
+		t.Errorf("IndexTokens = %q, want %q", got, want)
+	}
+
+	// Texto já expandido não gera tokens repetidos
+	if again := IndexTokens("Habite-se " + got); again != "" {
+		t.Errorf("tokens repetidos: %q", again)
+	}
+}
+
+func TestNormalizeQueryTokens(t *testing.T) {
+	tests := map[string]string{
+		"alvará lei complementar 270/2024": "alvará lc270 lc2702024",
+		"protocolo 2020 - 123456":          "protocolo 2020123456",
+		"segunda via iptu":                 "segunda via iptu",
+	}
+	for query, want := range tests {
+		if got := NormalizeQueryTokens(query); got != want {
+			t.Errorf("NormalizeQueryTokens(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
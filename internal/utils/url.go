@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
@@ -71,3 +72,33 @@ func WrapURLsInArray(urls []string, gatewayBaseURL string) []string {
 	}
 	return wrapped
 }
+
+// EnforceHTTPS normaliza a URL para https: http é promovido e endereços sem esquema
+// (ex.: www.rio.rj.gov.br/servico) recebem https://. Outros esquemas e caminhos relativos
+// são recusados.
+func EnforceHTTPS(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", errors.New("URL vazia")
+	}
+	if !strings.Contains(raw, "://") && !strings.HasPrefix(raw, "/") && !strings.Contains(raw, ":") {
+		raw = "https://" + raw
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("URL inválida: %w", err)
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "https":
+	case "http":
+		parsed.Scheme = "https"
+	default:
+		return "", fmt.Errorf("URL deve usar https: %s", raw)
+	}
+	if parsed.Host == "" || !strings.Contains(parsed.Host, ".") {
+		return "", fmt.Errorf("URL sem domínio válido: %s", raw)
+	}
+	parsed.Scheme = "https"
+	return parsed.String(), nil
+}
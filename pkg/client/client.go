@@ -0,0 +1,228 @@
+// Package client é o SDK Go tipado da API de busca da Prefeitura do Rio.
+//
+// Uso básico:
+//
+//	c := client.New("https://services.app.dados.rio/app-busca-search",
+//		client.WithToken(os.Getenv("BUSCA_TOKEN")))
+//	resp, err := c.Search(ctx, &client.SearchParams{Query: "iptu", Type: client.SearchTypeHybrid})
+//
+// Requisições com falha transitória (erros de rede, 429 e 5xx) são repetidas com
+// backoff exponencial (POST apenas em 429), e o contexto de tracing OpenTelemetry é propagado nos headers.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	defaultTimeout    = 30 * time.Second
+	defaultMaxRetries = 3
+	defaultBackoff    = 200 * time.Millisecond
+	maxBackoff        = 5 * time.Second
+	userAgent         = "app-busca-search-go-client/1.0"
+)
+
+// Client é o cliente HTTP da API de busca
+type Client struct {
+	baseURL     string
+	token       string
+	httpClient  *http.Client
+	maxRetries  int
+	baseBackoff time.Duration
+	propagator  propagation.TextMapPropagator
+}
+
+// Option configura o Client
+type Option func(*Client)
+
+// WithToken define o token JWT enviado no header Authorization (necessário para rotas admin)
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithHTTPClient substitui o http.Client padrão
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithRetries define o número máximo de novas tentativas e o backoff inicial
+func WithRetries(maxRetries int, baseBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.baseBackoff = baseBackoff
+	}
+}
+
+// WithPropagator define o propagador de tracing (padrão: otel.GetTextMapPropagator())
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *Client) { c.propagator = propagator }
+}
+
+// New cria um novo cliente para a API hospedada em baseURL
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		httpClient:  &http.Client{Timeout: defaultTimeout},
+		maxRetries:  defaultMaxRetries,
+		baseBackoff: defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError representa uma resposta de erro da API
+type APIError struct {
+	StatusCode int
+	Message    string `json:"error"`
+	Details    string `json:"details,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	if e.Details != "" {
+		return fmt.Sprintf("api-busca-search: status %d: %s (%s)", e.StatusCode, e.Message, e.Details)
+	}
+	return fmt.Sprintf("api-busca-search: status %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound indica se o erro é um 404 da API
+func IsNotFound(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// do executa a requisição com retries e decodifica a resposta JSON em out (se não nil)
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("erro ao serializar corpo da requisição: %w", err)
+		}
+	}
+
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			if err := c.wait(ctx, attempt); err != nil {
+				return err
+			}
+		}
+
+		retry, err := c.attempt(ctx, method, endpoint, payload, out)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			return err
+		}
+		// POST não é idempotente: só repete quando o servidor recusou explicitamente (429)
+		if method == http.MethodPost {
+			if apiErr, ok := err.(*APIError); !ok || apiErr.StatusCode != http.StatusTooManyRequests {
+				return err
+			}
+		}
+	}
+
+	return lastErr
+}
+
+// attempt executa uma única tentativa; retorna se o erro é passível de nova tentativa
+func (c *Client) attempt(ctx context.Context, method, endpoint string, payload []byte, out interface{}) (bool, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return false, fmt.Errorf("erro ao criar requisição: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	propagator := c.propagator
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		return true, fmt.Errorf("erro na requisição %s %s: %w", method, endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return true, fmt.Errorf("erro ao ler resposta: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		if json.Unmarshal(data, apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(data))
+		}
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retry, apiErr
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent || len(data) == 0 {
+		return false, nil
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return false, fmt.Errorf("erro ao decodificar resposta: %w", err)
+	}
+
+	return false, nil
+}
+
+// wait aguarda o backoff exponencial com jitter antes da próxima tentativa
+func (c *Client) wait(ctx context.Context, attempt int) error {
+	backoff := c.baseBackoff << (attempt - 1)
+	if backoff > maxBackoff || backoff <= 0 {
+		backoff = maxBackoff
+	}
+	backoff = backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
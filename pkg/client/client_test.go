@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryOnServerError(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(map[string]string{"error": "indisponível"})
+			return
+		}
+		if r.URL.Query().Get("q") != "iptu" || r.URL.Query().Get("type") != "hybrid" {
+			t.Errorf("query inesperada: %s", r.URL.RawQuery)
+		}
+		json.NewEncoder(w).Encode(SearchResponse{TotalCount: 1, Results: []*ServiceDocument{{ID: "1"}}})
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithRetries(3, time.Millisecond))
+	resp, err := c.Search(context.Background(), &SearchParams{Query: "iptu"})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if len(resp.Results) != 1 || calls != 3 {
+		t.Errorf("esperado 1 resultado após 3 chamadas, obtido %d resultados e %d chamadas", len(resp.Results), calls)
+	}
+}
+
+func TestNoRetryOnClientError(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Serviço não encontrado"})
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithRetries(3, time.Millisecond), WithToken("abc"))
+	_, err := c.AdminGetService(context.Background(), "x")
+	if !IsNotFound(err) {
+		t.Fatalf("esperado 404, obtido %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("esperada 1 chamada, obtidas %d", calls)
+	}
+}
+
+func TestAllServicesPagination(t *testing.T) {
+	const total = 5
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer abc" {
+			t.Errorf("header Authorization ausente")
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+
+		list := ServiceList{Found: total, Page: page}
+		for i := (page - 1) * perPage; i < page*perPage && i < total; i++ {
+			list.Services = append(list.Services, Service{ID: strconv.Itoa(i)})
+		}
+		json.NewEncoder(w).Encode(list)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, WithToken("abc"))
+	var ids []string
+	for service, err := range c.AllServices(context.Background(), &ListServicesParams{PerPage: 2}) {
+		if err != nil {
+			t.Fatalf("erro inesperado: %v", err)
+		}
+		ids = append(ids, service.ID)
+	}
+
+	if len(ids) != total {
+		t.Errorf("esperados %d serviços, obtidos %d (%v)", total, len(ids), ids)
+	}
+}
@@ -0,0 +1,236 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Search executa GET /api/v1/search
+func (c *Client) Search(ctx context.Context, params *SearchParams) (*SearchResponse, error) {
+	var resp SearchResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/search", params.values(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SearchAll itera sobre todos os resultados da busca, página a página
+func (c *Client) SearchAll(ctx context.Context, params *SearchParams) iter.Seq2[*ServiceDocument, error] {
+	return func(yield func(*ServiceDocument, error) bool) {
+		p := *params
+		if p.Page < 1 {
+			p.Page = 1
+		}
+		if p.PerPage < 1 {
+			p.PerPage = 100
+		}
+
+		for {
+			resp, err := c.Search(ctx, &p)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, doc := range resp.Results {
+				if !yield(doc, nil) {
+					return
+				}
+			}
+			if len(resp.Results) == 0 || p.Page*p.PerPage >= resp.TotalCount {
+				return
+			}
+			p.Page++
+		}
+	}
+}
+
+// GetService busca um serviço publicado pelo ID (GET /api/v1/search/{id})
+func (c *Client) GetService(ctx context.Context, id string) (*Service, error) {
+	var service Service
+	if err := c.do(ctx, http.MethodGet, "/api/v1/search/"+url.PathEscape(id), nil, nil, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// GetServiceBySlug busca um serviço pelo slug; slugs antigos são redirecionados para o atual
+func (c *Client) GetServiceBySlug(ctx context.Context, slug string) (*Service, error) {
+	var service Service
+	if err := c.do(ctx, http.MethodGet, "/api/v1/services/"+url.PathEscape(slug), nil, nil, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// CreateService cria um serviço (POST /api/v1/admin/services)
+func (c *Client) CreateService(ctx context.Context, req *ServiceRequest) (*Service, error) {
+	var service Service
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/services", nil, req, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// AdminGetService busca um serviço, inclusive rascunhos (GET /api/v1/admin/services/{id})
+func (c *Client) AdminGetService(ctx context.Context, id string) (*Service, error) {
+	var service Service
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/services/"+url.PathEscape(id), nil, nil, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// UpdateService atualiza um serviço (PUT /api/v1/admin/services/{id})
+func (c *Client) UpdateService(ctx context.Context, id string, req *ServiceRequest) (*Service, error) {
+	var service Service
+	if err := c.do(ctx, http.MethodPut, "/api/v1/admin/services/"+url.PathEscape(id), nil, req, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// DeleteService remove um serviço (DELETE /api/v1/admin/services/{id})
+func (c *Client) DeleteService(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/admin/services/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// PublishService publica um serviço (PATCH /api/v1/admin/services/{id}/publish)
+func (c *Client) PublishService(ctx context.Context, id string) (*Service, error) {
+	var service Service
+	if err := c.do(ctx, http.MethodPatch, "/api/v1/admin/services/"+url.PathEscape(id)+"/publish", nil, nil, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// UnpublishService despublica um serviço (PATCH /api/v1/admin/services/{id}/unpublish)
+func (c *Client) UnpublishService(ctx context.Context, id string) (*Service, error) {
+	var service Service
+	if err := c.do(ctx, http.MethodPatch, "/api/v1/admin/services/"+url.PathEscape(id)+"/unpublish", nil, nil, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// ListServices lista serviços com filtros (GET /api/v1/admin/services)
+func (c *Client) ListServices(ctx context.Context, params *ListServicesParams) (*ServiceList, error) {
+	var list ServiceList
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/services", params.values(), nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// AllServices itera sobre todos os serviços que atendem aos filtros, página a página
+func (c *Client) AllServices(ctx context.Context, params *ListServicesParams) iter.Seq2[*Service, error] {
+	return func(yield func(*Service, error) bool) {
+		p := ListServicesParams{}
+		if params != nil {
+			p = *params
+		}
+		if p.Page < 1 {
+			p.Page = 1
+		}
+		if p.PerPage < 1 {
+			p.PerPage = 100
+		}
+
+		for {
+			list, err := c.ListServices(ctx, &p)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for i := range list.Services {
+				if !yield(&list.Services[i], nil) {
+					return
+				}
+			}
+			if len(list.Services) < p.PerPage || p.Page*p.PerPage >= list.Found {
+				return
+			}
+			p.Page++
+		}
+	}
+}
+
+func (p *SearchParams) values() url.Values {
+	v := url.Values{}
+	if p == nil {
+		return v
+	}
+	v.Set("q", p.Query)
+	searchType := p.Type
+	if searchType == "" {
+		searchType = SearchTypeHybrid
+	}
+	v.Set("type", string(searchType))
+	setInt(v, "page", p.Page)
+	setInt(v, "per_page", p.PerPage)
+	if p.IncludeInactive {
+		v.Set("include_inactive", "true")
+	}
+	if p.Alpha > 0 {
+		v.Set("alpha", strconv.FormatFloat(p.Alpha, 'f', -1, 64))
+	}
+	if p.ExcludeAgentExclusive != nil {
+		v.Set("exclude_agent_exclusive", strconv.FormatBool(*p.ExcludeAgentExclusive))
+	}
+	if p.GenerateScores {
+		v.Set("generate_scores", "true")
+	}
+	if p.RecencyBoost {
+		v.Set("recency_boost", "true")
+	}
+	setFloat(v, "threshold_keyword", p.ThresholdKeyword)
+	setFloat(v, "threshold_semantic", p.ThresholdSemantic)
+	setFloat(v, "threshold_hybrid", p.ThresholdHybrid)
+	setFloat(v, "threshold_ai", p.ThresholdAI)
+	return v
+}
+
+func (p *ListServicesParams) values() url.Values {
+	v := url.Values{}
+	if p == nil {
+		return v
+	}
+	setInt(v, "page", p.Page)
+	setInt(v, "per_page", p.PerPage)
+	if p.Status != nil {
+		v.Set("status", strconv.Itoa(*p.Status))
+	}
+	if p.Author != "" {
+		v.Set("author", p.Author)
+	}
+	if p.TemaGeral != "" {
+		v.Set("tema_geral", p.TemaGeral)
+	}
+	if p.SubCategoria != "" {
+		v.Set("sub_categoria", p.SubCategoria)
+	}
+	if p.AwaitingApproval != nil {
+		v.Set("awaiting_approval", strconv.FormatBool(*p.AwaitingApproval))
+	}
+	if p.IsFree != nil {
+		v.Set("is_free", strconv.FormatBool(*p.IsFree))
+	}
+	if p.NomeServico != "" {
+		v.Set("nome_servico", p.NomeServico)
+	}
+	return v
+}
+
+func setInt(v url.Values, key string, value int) {
+	if value > 0 {
+		v.Set(key, strconv.Itoa(value))
+	}
+}
+
+func setFloat(v url.Values, key string, value *float64) {
+	if value != nil {
+		v.Set(key, strconv.FormatFloat(*value, 'f', -1, 64))
+	}
+}
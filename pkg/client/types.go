@@ -0,0 +1,216 @@
+package client
+
+// SearchType define os tipos de busca disponíveis
+type SearchType string
+
+const (
+	SearchTypeKeyword  SearchType = "keyword"
+	SearchTypeSemantic SearchType = "semantic"
+	SearchTypeHybrid   SearchType = "hybrid"
+	SearchTypeAI       SearchType = "ai"
+)
+
+// SearchParams representa os parâmetros de GET /api/v1/search
+type SearchParams struct {
+	Query                 string
+	Type                  SearchType
+	Page                  int
+	PerPage               int
+	IncludeInactive       bool
+	Alpha                 float64
+	ExcludeAgentExclusive *bool
+	GenerateScores        bool
+	RecencyBoost          bool
+	ThresholdKeyword      *float64
+	ThresholdSemantic     *float64
+	ThresholdHybrid       *float64
+	ThresholdAI           *float64
+}
+
+// ScoreInfo contém informações sobre os scores de relevância de um documento
+type ScoreInfo struct {
+	TextMatchNormalized *float64 `json:"text_match_normalized,omitempty"`
+	VectorSimilarity    *float64 `json:"vector_similarity,omitempty"`
+	HybridScore         *float64 `json:"hybrid_score,omitempty"`
+	RecencyFactor       *float64 `json:"recency_factor,omitempty"`
+	FinalScore          *float64 `json:"final_score,omitempty"`
+	ThresholdApplied    string   `json:"threshold_applied,omitempty"`
+	ThresholdValue      *float64 `json:"threshold_value,omitempty"`
+	PassedThreshold     bool     `json:"passed_threshold"`
+}
+
+// ServiceDocument representa um documento de serviço retornado pela busca
+type ServiceDocument struct {
+	ID          string                 `json:"id"`
+	Title       string                 `json:"title"`
+	Description string                 `json:"description"`
+	Category    string                 `json:"category"`
+	Subcategory *string                `json:"subcategory,omitempty"`
+	Slug        string                 `json:"slug,omitempty"`
+	Status      int32                  `json:"status"`
+	CreatedAt   int64                  `json:"created_at"`
+	UpdatedAt   int64                  `json:"updated_at"`
+	Metadata    map[string]interface{} `json:"metadata"`
+}
+
+// SearchResponse representa a resposta de uma busca
+type SearchResponse struct {
+	Results       []*ServiceDocument     `json:"results"`
+	TotalCount    int                    `json:"total_count"`
+	FilteredCount int                    `json:"filtered_count"`
+	Page          int                    `json:"page"`
+	PerPage       int                    `json:"per_page"`
+	SearchType    SearchType             `json:"search_type"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// AgentsConfig representa a configuração para agentes
+type AgentsConfig struct {
+	ToolHint           string `json:"tool_hint"`
+	ExclusiveForAgents bool   `json:"exclusive_for_agents"`
+}
+
+// Button representa um botão de ação para o serviço
+type Button struct {
+	Titulo     string `json:"titulo"`
+	Descricao  string `json:"descricao"`
+	IsEnabled  bool   `json:"is_enabled"`
+	Ordem      int    `json:"ordem"`
+	URLService string `json:"url_service"`
+}
+
+// Service representa um serviço da collection prefrio_services_base
+type Service struct {
+	ID                    string                 `json:"id,omitempty"`
+	NomeServico           string                 `json:"nome_servico"`
+	OrgaoGestor           []string               `json:"orgao_gestor"`
+	Resumo                string                 `json:"resumo"`
+	TempoAtendimento      string                 `json:"tempo_atendimento"`
+	CustoServico          string                 `json:"custo_servico"`
+	ResultadoSolicitacao  string                 `json:"resultado_solicitacao"`
+	DescricaoCompleta     string                 `json:"descricao_completa"`
+	Autor                 string                 `json:"autor"`
+	DocumentosNecessarios []string               `json:"documentos_necessarios"`
+	InstrucoesSolicitante string                 `json:"instrucoes_solicitante"`
+	CanaisDigitais        []string               `json:"canais_digitais"`
+	CanaisPresenciais     []string               `json:"canais_presenciais"`
+	ServicoNaoCobre       string                 `json:"servico_nao_cobre"`
+	LegislacaoRelacionada []string               `json:"legislacao_relacionada"`
+	TemaGeral             string                 `json:"tema_geral"`
+	SubCategoria          *string                `json:"sub_categoria,omitempty"`
+	PublicoEspecifico     []string               `json:"publico_especifico,omitempty"`
+	FixarDestaque         bool                   `json:"fixar_destaque"`
+	AwaitingApproval      bool                   `json:"awaiting_approval"`
+	PublishedAt           *int64                 `json:"published_at,omitempty"`
+	IsFree                *bool                  `json:"is_free,omitempty"`
+	Agents                *AgentsConfig          `json:"agents,omitempty"`
+	ExtraFields           map[string]interface{} `json:"extra_fields,omitempty"`
+	Status                int                    `json:"status"`
+	CreatedAt             int64                  `json:"created_at"`
+	LastUpdate            int64                  `json:"last_update"`
+	Buttons               []Button               `json:"buttons"`
+	Slug                  string                 `json:"slug"`
+	SlugHistory           []string               `json:"slug_history,omitempty"`
+}
+
+// ServiceRequest representa os dados de entrada para criar/atualizar um serviço
+type ServiceRequest struct {
+	NomeServico           string                 `json:"nome_servico"`
+	OrgaoGestor           []string               `json:"orgao_gestor"`
+	Resumo                string                 `json:"resumo"`
+	TempoAtendimento      string                 `json:"tempo_atendimento,omitempty"`
+	CustoServico          string                 `json:"custo_servico,omitempty"`
+	ResultadoSolicitacao  string                 `json:"resultado_solicitacao,omitempty"`
+	DescricaoCompleta     string                 `json:"descricao_completa,omitempty"`
+	DocumentosNecessarios []string               `json:"documentos_necessarios"`
+	InstrucoesSolicitante string                 `json:"instrucoes_solicitante"`
+	CanaisDigitais        []string               `json:"canais_digitais"`
+	CanaisPresenciais     []string               `json:"canais_presenciais"`
+	ServicoNaoCobre       string                 `json:"servico_nao_cobre"`
+	LegislacaoRelacionada []string               `json:"legislacao_relacionada"`
+	TemaGeral             string                 `json:"tema_geral"`
+	SubCategoria          *string                `json:"sub_categoria,omitempty"`
+	PublicoEspecifico     []string               `json:"publico_especifico"`
+	FixarDestaque         bool                   `json:"fixar_destaque"`
+	AwaitingApproval      bool                   `json:"awaiting_approval"`
+	PublishedAt           *int64                 `json:"published_at,omitempty"`
+	IsFree                *bool                  `json:"is_free,omitempty"`
+	Agents                *AgentsConfig          `json:"agents,omitempty"`
+	ExtraFields           map[string]interface{} `json:"extra_fields,omitempty"`
+	Status                int                    `json:"status"`
+	Buttons               []Button               `json:"buttons"`
+}
+
+// ServiceList representa a resposta de listagem de serviços
+type ServiceList struct {
+	Found    int       `json:"found"`
+	OutOf    int       `json:"out_of"`
+	Page     int       `json:"page"`
+	Services []Service `json:"services"`
+}
+
+// ListServicesParams representa os filtros de GET /api/v1/admin/services
+type ListServicesParams struct {
+	Page             int
+	PerPage          int
+	Status           *int
+	Author           string
+	TemaGeral        string
+	SubCategoria     string
+	AwaitingApproval *bool
+	IsFree           *bool
+	NomeServico      string
+}
+
+// FieldChange representa uma mudança em um campo específico
+type FieldChange struct {
+	FieldName string      `json:"field_name"`
+	OldValue  interface{} `json:"old_value,omitempty"`
+	NewValue  interface{} `json:"new_value,omitempty"`
+	ValueType string      `json:"value_type"`
+}
+
+// ServiceVersion representa uma versão de um serviço (snapshot sem embedding)
+type ServiceVersion struct {
+	ID                string   `json:"id,omitempty"`
+	ServiceID         string   `json:"service_id"`
+	VersionNumber     int64    `json:"version_number"`
+	CreatedAt         int64    `json:"created_at"`
+	CreatedBy         string   `json:"created_by"`
+	CreatedByCPF      string   `json:"created_by_cpf"`
+	ChangeType        string   `json:"change_type"`
+	ChangeReason      string   `json:"change_reason,omitempty"`
+	PreviousVersion   int64    `json:"previous_version,omitempty"`
+	IsRollback        bool     `json:"is_rollback"`
+	RollbackToVersion int64    `json:"rollback_to_version,omitempty"`
+	NomeServico       string   `json:"nome_servico"`
+	OrgaoGestor       []string `json:"orgao_gestor"`
+	Resumo            string   `json:"resumo"`
+	TemaGeral         string   `json:"tema_geral"`
+	Status            int      `json:"status"`
+	ChangedFieldsJSON string   `json:"changed_fields_json,omitempty"`
+}
+
+// VersionHistory representa uma lista paginada de versões
+type VersionHistory struct {
+	Found    int              `json:"found"`
+	OutOf    int              `json:"out_of"`
+	Page     int              `json:"page"`
+	Versions []ServiceVersion `json:"versions"`
+}
+
+// VersionDiff representa a diferença entre duas versões
+type VersionDiff struct {
+	FromVersion int64         `json:"from_version"`
+	ToVersion   int64         `json:"to_version"`
+	Changes     []FieldChange `json:"changes"`
+	ChangedBy   string        `json:"changed_by"`
+	ChangedAt   int64         `json:"changed_at"`
+	ChangeType  string        `json:"change_type"`
+}
+
+// RollbackRequest representa uma solicitação de rollback
+type RollbackRequest struct {
+	ToVersion    int64  `json:"to_version"`
+	ChangeReason string `json:"change_reason,omitempty"`
+}
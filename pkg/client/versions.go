@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ListVersions lista as versões de um serviço (GET /api/v1/admin/services/{id}/versions)
+func (c *Client) ListVersions(ctx context.Context, serviceID string, page, perPage int) (*VersionHistory, error) {
+	query := url.Values{}
+	setInt(query, "page", page)
+	setInt(query, "per_page", perPage)
+
+	var history VersionHistory
+	if err := c.do(ctx, http.MethodGet, versionsPath(serviceID), query, nil, &history); err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// AllVersions itera sobre todas as versões de um serviço, da mais recente para a mais antiga
+func (c *Client) AllVersions(ctx context.Context, serviceID string) iter.Seq2[*ServiceVersion, error] {
+	return func(yield func(*ServiceVersion, error) bool) {
+		const perPage = 100
+		for page := 1; ; page++ {
+			history, err := c.ListVersions(ctx, serviceID, page, perPage)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for i := range history.Versions {
+				if !yield(&history.Versions[i], nil) {
+					return
+				}
+			}
+			if len(history.Versions) < perPage || page*perPage >= history.Found {
+				return
+			}
+		}
+	}
+}
+
+// GetVersion busca uma versão específica (GET /api/v1/admin/services/{id}/versions/{version})
+func (c *Client) GetVersion(ctx context.Context, serviceID string, version int64) (*ServiceVersion, error) {
+	var v ServiceVersion
+	path := versionsPath(serviceID) + "/" + strconv.FormatInt(version, 10)
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// CompareVersions compara duas versões (GET /api/v1/admin/services/{id}/versions/compare)
+func (c *Client) CompareVersions(ctx context.Context, serviceID string, fromVersion, toVersion int64) (*VersionDiff, error) {
+	query := url.Values{}
+	query.Set("from_version", strconv.FormatInt(fromVersion, 10))
+	query.Set("to_version", strconv.FormatInt(toVersion, 10))
+
+	var diff VersionDiff
+	if err := c.do(ctx, http.MethodGet, versionsPath(serviceID)+"/compare", query, nil, &diff); err != nil {
+		return nil, err
+	}
+	return &diff, nil
+}
+
+// Rollback restaura um serviço para uma versão anterior (POST /api/v1/admin/services/{id}/rollback)
+func (c *Client) Rollback(ctx context.Context, serviceID string, req *RollbackRequest) (*Service, error) {
+	var service Service
+	path := "/api/v1/admin/services/" + url.PathEscape(serviceID) + "/rollback"
+	if err := c.do(ctx, http.MethodPost, path, nil, req, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+func versionsPath(serviceID string) string {
+	return "/api/v1/admin/services/" + url.PathEscape(serviceID) + "/versions"
+}